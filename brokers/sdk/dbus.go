@@ -0,0 +1,152 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/ubuntu/decorate"
+)
+
+// Config is the configuration of a broker, which authd reads to find it on the bus.
+type Config struct {
+	// Name is the name of the broker shown to the users.
+	Name string
+	// BrandIcon is the path of the icon of the broker.
+	BrandIcon string
+	// DbusName is the well-known name the broker owns on the system bus.
+	DbusName string
+	// DbusObject is the path of the object implementing DbusInterface.
+	DbusObject dbus.ObjectPath
+	// Usernames are the optional patterns of the usernames owned by the broker (for example "*@example.com").
+	Usernames []string
+}
+
+// WriteConfig writes the configuration of the broker to the file read by authd, usually in
+// /etc/authd/brokers.d/.
+func WriteConfig(path string, cfg Config) (err error) {
+	defer decorate.OnError(&err, "could not write broker configuration")
+
+	content := fmt.Sprintf(`[authd]
+name = %s
+brand_icon = %s
+dbus_name = %s
+dbus_object = %s
+`, cfg.Name, cfg.BrandIcon, cfg.DbusName, cfg.DbusObject)
+	if len(cfg.Usernames) > 0 {
+		content += fmt.Sprintf("usernames = %s\n", strings.Join(cfg.Usernames, ","))
+	}
+
+	return os.WriteFile(path, []byte(content), 0600)
+}
+
+// Export exports the service on the bus, at the object path of the configuration, and requests its name.
+func (s *Service[S]) Export(conn *dbus.Conn, cfg Config) (err error) {
+	defer decorate.OnError(&err, "could not export broker %q on the bus", cfg.Name)
+
+	obj := &dbusObject[S]{s: s}
+	if err := conn.Export(obj, cfg.DbusObject, DbusInterface); err != nil {
+		return err
+	}
+	s.emitProgress = func(sessionID string, p AuthProgress) error {
+		return conn.Emit(cfg.DbusObject, DbusInterface+"."+authProgressSignal, sessionID, p.message, int32(p.percentage), p.cancellable)
+	}
+	if err := conn.Export(introspect.NewIntrospectable(&introspect.Node{
+		Name: string(cfg.DbusObject),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			{
+				Name:    DbusInterface,
+				Methods: introspect.Methods(obj),
+				Signals: []introspect.Signal{{
+					Name: authProgressSignal,
+					Args: []introspect.Arg{
+						{Name: "sessionID", Type: "s"},
+						{Name: "message", Type: "s"},
+						{Name: "percentage", Type: "i"},
+						{Name: "cancellable", Type: "b"},
+					},
+				}},
+			},
+		},
+	}), cfg.DbusObject, introspect.IntrospectData.Name); err != nil {
+		return err
+	}
+
+	reply, err := conn.RequestName(cfg.DbusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return errors.New("D-Bus name already taken")
+	}
+	return nil
+}
+
+// dbusObject is the D-Bus object answering the calls of authd, whose methods are the ones of DbusInterface.
+type dbusObject[S any] struct {
+	s *Service[S]
+}
+
+// NewSession is the D-Bus method creating a new session.
+func (o *dbusObject[S]) NewSession(username, lang, mode string) (sessionID, encryptionKey string, dbusErr *dbus.Error) {
+	sessionID, encryptionKey, err := o.s.NewSession(context.Background(), username, lang, mode)
+	if err != nil {
+		return "", "", dbus.MakeFailedError(err)
+	}
+	return sessionID, encryptionKey, nil
+}
+
+// GetAuthenticationModes is the D-Bus method returning the authentication modes of a session.
+func (o *dbusObject[S]) GetAuthenticationModes(sessionID string, supportedUILayouts []map[string]string) (authenticationModes []map[string]string, dbusErr *dbus.Error) {
+	authenticationModes, err := o.s.GetAuthenticationModes(context.Background(), sessionID, supportedUILayouts)
+	if err != nil {
+		return nil, dbus.MakeFailedError(err)
+	}
+	return authenticationModes, nil
+}
+
+// SelectAuthenticationMode is the D-Bus method selecting the authentication mode of a session.
+func (o *dbusObject[S]) SelectAuthenticationMode(sessionID, authenticationModeName string) (uiLayoutInfo map[string]string, dbusErr *dbus.Error) {
+	uiLayoutInfo, err := o.s.SelectAuthenticationMode(context.Background(), sessionID, authenticationModeName)
+	if err != nil {
+		return nil, dbus.MakeFailedError(err)
+	}
+	return uiLayoutInfo, nil
+}
+
+// IsAuthenticated is the D-Bus method authenticating the user of a session.
+func (o *dbusObject[S]) IsAuthenticated(sessionID, authenticationData string) (access, data string, dbusErr *dbus.Error) {
+	access, data, err := o.s.IsAuthenticated(context.Background(), sessionID, authenticationData)
+	if err != nil {
+		return "", "", dbus.MakeFailedError(err)
+	}
+	return access, data, nil
+}
+
+// EndSession is the D-Bus method ending a session.
+func (o *dbusObject[S]) EndSession(sessionID string) (dbusErr *dbus.Error) {
+	if err := o.s.EndSession(context.Background(), sessionID); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// CancelIsAuthenticated is the D-Bus method cancelling the authentication in progress for a session.
+func (o *dbusObject[S]) CancelIsAuthenticated(sessionID string) (dbusErr *dbus.Error) {
+	o.s.CancelIsAuthenticated(context.Background(), sessionID)
+	return nil
+}
+
+// UserPreCheck is the D-Bus method returning the information of a user which never logged in.
+func (o *dbusObject[S]) UserPreCheck(username string) (userinfo string, dbusErr *dbus.Error) {
+	userinfo, err := o.s.UserPreCheck(context.Background(), username)
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	return userinfo, nil
+}
@@ -0,0 +1,166 @@
+package sdk
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+)
+
+// The layout types.
+const (
+	// LayoutForm is the layout of the forms the user fills.
+	LayoutForm = layouts.Form
+	// LayoutQrCode is the layout showing a QR code or a URL the user authenticates with on another device.
+	LayoutQrCode = layouts.QrCode
+	// LayoutNewPassword is the layout asking the user for a new password.
+	LayoutNewPassword = layouts.NewPassword
+	// LayoutSmartCard is the layout asking the user for their smart card.
+	LayoutSmartCard = layouts.SmartCard
+)
+
+// The entry types of the layouts.
+const (
+	// EntryChars is an entry of characters.
+	EntryChars = entries.Chars
+	// EntryCharsPassword is a hidden entry of characters.
+	EntryCharsPassword = entries.CharsPassword
+	// EntryDigits is an entry of digits.
+	EntryDigits = entries.Digits
+	// EntryDigitsPassword is a hidden entry of digits.
+	EntryDigitsPassword = entries.DigitsPassword
+)
+
+// UILayout is the layout of an authentication mode. The empty fields are not sent to authd.
+//
+// The label and the content, except the one of the QR codes, can use a subset of markdown, like the messages returned
+// by IsAuthenticated: **bold** text, lists whose items start with "-", "*", "+" or "1.", and [links](https://…) to web
+// or mail addresses, shown as footnotes in the terminals. HTML tags and other links are removed.
+type UILayout struct {
+	Type              string
+	Label             string
+	Entry             string
+	Button            string
+	Wait              string
+	Content           string
+	Code              string
+	RendersQrCode     string
+	CodeLength        string
+	CodeValidity      string
+	SmartCardAction   string
+	CachedCredentials string
+	MinStrength       string
+}
+
+// FormLayout returns the layout of a form with the given label and entry type.
+func FormLayout(label, entry string) UILayout {
+	return UILayout{Type: LayoutForm, Label: label, Entry: entry}
+}
+
+// QrCodeLayout returns the layout showing the content as a QR code, with the code the user must enter on the other
+// device, if any. The user is waiting for the broker to authenticate them.
+func QrCodeLayout(label, content, code string) UILayout {
+	return UILayout{Type: LayoutQrCode, Label: label, Content: content, Code: code, Wait: layouts.True}
+}
+
+// NewPasswordLayout returns the layout asking the user for a new password.
+func NewPasswordLayout(label string) UILayout {
+	return UILayout{Type: LayoutNewPassword, Label: label, Entry: EntryCharsPassword}
+}
+
+// WithButton returns the layout with a button with the given label, to restart the authentication mode.
+func (l UILayout) WithButton(label string) UILayout {
+	l.Button = label
+	return l
+}
+
+// WithWait returns the layout where the user waits for the broker to authenticate them.
+func (l UILayout) WithWait() UILayout {
+	l.Wait = layouts.True
+	return l
+}
+
+// WithCode returns the form layout showing a code which expires after validity seconds, and which is submitted once
+// it has length characters (0 to disable).
+func (l UILayout) WithCode(length, validity int) UILayout {
+	if length > 0 {
+		l.CodeLength = strconv.Itoa(length)
+	}
+	if validity > 0 {
+		l.CodeValidity = strconv.Itoa(validity)
+	}
+	return l
+}
+
+// WithMinStrength returns the new password layout refusing the passwords whose strength score, from 0 (very weak) to 4
+// (very strong), is lower than score.
+func (l UILayout) WithMinStrength(score int) UILayout {
+	l.MinStrength = strconv.Itoa(score)
+	return l
+}
+
+// fields returns the keys of the layout with the pointers to their fields.
+func (l *UILayout) fields() map[string]*string {
+	return map[string]*string{
+		layouts.Type:              &l.Type,
+		layouts.Label:             &l.Label,
+		layouts.Entry:             &l.Entry,
+		layouts.Button:            &l.Button,
+		layouts.Wait:              &l.Wait,
+		layouts.Content:           &l.Content,
+		layouts.Code:              &l.Code,
+		layouts.RendersQrCode:     &l.RendersQrCode,
+		layouts.CodeLength:        &l.CodeLength,
+		layouts.CodeValidity:      &l.CodeValidity,
+		layouts.SmartCardAction:   &l.SmartCardAction,
+		layouts.CachedCredentials: &l.CachedCredentials,
+		layouts.MinStrength:       &l.MinStrength,
+	}
+}
+
+// toMap returns the layout in the format sent to authd.
+func (l UILayout) toMap() map[string]string {
+	m := make(map[string]string)
+	for k, v := range l.fields() {
+		if *v != "" {
+			m[k] = *v
+		}
+	}
+	return m
+}
+
+// layoutFromMap returns the layout sent by authd, ignoring the unknown fields.
+func layoutFromMap(m map[string]string) UILayout {
+	var l UILayout
+	for k, v := range l.fields() {
+		*v = m[k]
+	}
+	return l
+}
+
+// SupportedLayouts are the layouts supported by the user interface. Their fields are either "required" or "optional",
+// optionally followed by the supported values (for example "optional:chars,chars_password").
+type SupportedLayouts []UILayout
+
+// Get returns the supported layout of the given type.
+func (s SupportedLayouts) Get(layoutType string) (UILayout, bool) {
+	i := slices.IndexFunc(s, func(l UILayout) bool { return l.Type == layoutType })
+	if i < 0 {
+		return UILayout{}, false
+	}
+	return s[i], true
+}
+
+// Supports returns whether the layout type is supported, with the given entry type if not empty.
+func (s SupportedLayouts) Supports(layoutType, entry string) bool {
+	l, ok := s.Get(layoutType)
+	if !ok {
+		return false
+	}
+	if entry == "" {
+		return true
+	}
+	_, supportedEntries := layouts.ParseItems(l.Entry)
+	return slices.Contains(supportedEntries, entry)
+}
@@ -0,0 +1,221 @@
+// Package sdk helps implementing authd brokers in Go.
+//
+// A broker implements the Broker interface with typed requests and replies, while the Service handles the wire format
+// expected by authd: the D-Bus methods, the sessions, the decryption of the secrets and the cancellation of the
+// authentication requests.
+package sdk
+
+import (
+	"context"
+
+	"github.com/ubuntu/authd/internal/brokers/auth"
+)
+
+// DbusInterface is the D-Bus interface the brokers implement.
+const DbusInterface = "com.ubuntu.authd.Broker"
+
+// authProgressSignal is the D-Bus signal of DbusInterface reporting the progress of an authentication.
+const authProgressSignal = "AuthProgress"
+
+// The session modes requested by authd.
+const (
+	// SessionModeLogin is the mode of the sessions authenticating a user to log in.
+	SessionModeLogin = auth.SessionModeLogin
+	// SessionModeChangePassword is the mode of the sessions changing the password of a user.
+	SessionModeChangePassword = auth.SessionModeChangePassword
+	// SessionModeStepUp is the mode of the sessions re-authenticating a user who already logged in, for a service
+	// requiring it. It's only requested to the brokers declaring step_up in their configuration file.
+	SessionModeStepUp = auth.SessionModeStepUp
+)
+
+// The error codes a broker can return with a denied or retry reply.
+const (
+	// ErrorCodeNetwork is the error code when the identity provider could not be reached.
+	ErrorCodeNetwork = auth.ErrorCodeNetwork
+	// ErrorCodeInvalidCredentials is the error code when the provided credentials are not valid.
+	ErrorCodeInvalidCredentials = auth.ErrorCodeInvalidCredentials
+	// ErrorCodeAccountLocked is the error code when the user account is locked or disabled.
+	ErrorCodeAccountLocked = auth.ErrorCodeAccountLocked
+	// ErrorCodeMFARequired is the error code when the user must set up or use multi-factor authentication.
+	ErrorCodeMFARequired = auth.ErrorCodeMFARequired
+	// ErrorCodeConsentDenied is the error code when the user (or an administrator) denied the required consent.
+	ErrorCodeConsentDenied = auth.ErrorCodeConsentDenied
+)
+
+// Broker is the interface implemented by the brokers, where S is the type of the state they keep for each session.
+//
+// The calls for a given session are serialized, so the session can be modified without any locking.
+type Broker[S any] interface {
+	// NewSession initializes the state of a new session. Returning an error refuses the session.
+	NewSession(ctx context.Context, s *Session[S]) error
+	// AuthenticationModes returns the authentication modes available for the session, using only the supported
+	// layouts.
+	AuthenticationModes(ctx context.Context, s *Session[S], supported SupportedLayouts) ([]AuthenticationMode, error)
+	// SelectAuthenticationMode returns the layout of the selected authentication mode.
+	SelectAuthenticationMode(ctx context.Context, s *Session[S], modeID string) (UILayout, error)
+	// IsAuthenticated authenticates the user of the session with the selected mode. The context is cancelled if authd
+	// cancels the request.
+	IsAuthenticated(ctx context.Context, s *Session[S], data AuthenticationData) (Reply, error)
+	// UserPreCheck returns the information of a user which never logged in, if the broker allows them to use SSH.
+	UserPreCheck(ctx context.Context, username string) (UserInfo, error)
+}
+
+// SessionEnder is the optional interface implemented by the brokers needing to release the resources of a session.
+type SessionEnder[S any] interface {
+	EndSession(ctx context.Context, s *Session[S]) error
+}
+
+// Session is an authentication session of a user.
+type Session[S any] struct {
+	// ID is the identifier of the session.
+	ID string
+	// Username is the name of the user to authenticate.
+	Username string
+	// Lang is the language of the user interface.
+	Lang string
+	// Mode is the session mode, one of SessionModeLogin, SessionModeChangePassword or SessionModeStepUp.
+	Mode string
+	// AuthenticationMode is the currently selected authentication mode, if any.
+	AuthenticationMode string
+
+	// State is the state kept by the broker for the session.
+	State S
+
+	// reportProgress emits the progress of the authentication of the session, if the service is exported.
+	reportProgress func(AuthProgress) error
+}
+
+// ReportProgress shows the progress of a long authentication to the user, while IsAuthenticated is waiting (for
+// example for an approval on their phone). Each report replaces the previous one, until the authentication completes.
+func (s *Session[S]) ReportProgress(p AuthProgress) error {
+	if s.reportProgress == nil {
+		return nil
+	}
+	return s.reportProgress(p)
+}
+
+// AuthProgress is the progress of a long authentication.
+type AuthProgress struct {
+	message     string
+	percentage  int
+	cancellable bool
+}
+
+// Progress is the progress of an authentication whose completion is unknown, with the message to show the user.
+func Progress(msg string) AuthProgress {
+	return AuthProgress{message: msg, percentage: -1}
+}
+
+// WithPercentage returns the progress with the completion of the authentication, from 0 to 100.
+func (p AuthProgress) WithPercentage(percentage int) AuthProgress {
+	p.percentage = max(0, min(percentage, 100))
+	return p
+}
+
+// Cancellable returns the progress telling the user they can cancel the authentication.
+func (p AuthProgress) Cancellable() AuthProgress {
+	p.cancellable = true
+	return p
+}
+
+// AuthenticationMode is an authentication mode offered to the user.
+type AuthenticationMode struct {
+	// ID is the identifier of the mode.
+	ID string
+	// Label is the name of the mode shown to the user.
+	Label string
+}
+
+// AuthenticationData is the data sent by the user to authenticate.
+type AuthenticationData struct {
+	// Secret is the decrypted secret the user entered, if any.
+	Secret string
+	// Wait is set if the user is waiting for the broker to authenticate them (for example through a QR code).
+	Wait bool
+	// Skip is set if the user skipped an optional step (for example a password change).
+	Skip bool
+}
+
+// UserInfo is the information of an authenticated user.
+type UserInfo struct {
+	Name   string  `json:"name"`
+	Gecos  string  `json:"gecos"`
+	Dir    string  `json:"dir"`
+	Shell  string  `json:"shell"`
+	Groups []Group `json:"groups"`
+
+	// StorageSecret is an optional secret to unlock the storage of the user.
+	StorageSecret string `json:"storage_secret,omitempty"`
+
+	// AvatarData is an optional picture of the user, shown by the display managers.
+	AvatarData []byte `json:"avatar_data,omitempty"`
+	// AvatarURL is the optional address authd downloads the picture of the user from, if AvatarData is not set.
+	AvatarURL string `json:"avatar_url,omitempty"`
+
+	// GecosFields are the optional structured subfields of the GECOS field of the user. The non-empty ones take
+	// precedence over Gecos and over the values the user changed locally.
+	GecosFields *GecosFields `json:"gecos_fields,omitempty"`
+
+	// ConsentMessage is an optional message, like the terms of use, that the user must accept on their first login.
+	// They are asked again to accept it when it changes.
+	ConsentMessage string `json:"consent_message,omitempty"`
+
+	// Roles are the optional roles of the user, like "dev-laptops", which the machines can require to let the user
+	// log in. They are cached by authd, so that they are also enforced when the broker can't be reached.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// GecosFields are the subfields of the GECOS field of a user, as set by chfn.
+type GecosFields struct {
+	FullName  string `json:"full_name,omitempty"`
+	Room      string `json:"room,omitempty"`
+	WorkPhone string `json:"work_phone,omitempty"`
+	HomePhone string `json:"home_phone,omitempty"`
+}
+
+// Group is a group of a user.
+type Group struct {
+	// Name is the name of the group.
+	Name string `json:"name"`
+	// UGID is the unique identifier of the group in the identity provider. It is empty for local groups.
+	UGID string `json:"ugid"`
+}
+
+// Reply is the reply to an authentication request.
+type Reply struct {
+	access    string
+	userInfo  UserInfo
+	message   string
+	errorCode string
+}
+
+// Granted is the reply granting access to the user.
+func Granted(u UserInfo) Reply {
+	return Reply{access: auth.Granted, userInfo: u}
+}
+
+// Denied is the reply denying access to the user, with the message to show them.
+func Denied(msg string) Reply {
+	return Reply{access: auth.Denied, message: msg}
+}
+
+// Retry is the reply letting the user try again, with the message to show them.
+func Retry(msg string) Reply {
+	return Reply{access: auth.Retry, message: msg}
+}
+
+// Next is the reply requesting another authentication step.
+func Next() Reply {
+	return Reply{access: auth.Next}
+}
+
+// Cancelled is the reply when the authentication was cancelled.
+func Cancelled() Reply {
+	return Reply{access: auth.Cancelled}
+}
+
+// WithErrorCode returns the denied or retry reply with an error code, so that authd can show a generic message.
+func (r Reply) WithErrorCode(code string) Reply {
+	r.errorCode = code
+	return r
+}
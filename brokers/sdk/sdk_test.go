@@ -0,0 +1,283 @@
+package sdk_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/brokers/sdk"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/testutils"
+	"github.com/ubuntu/authd/internal/users/types"
+	"github.com/ubuntu/authd/log"
+)
+
+// passwordBroker is a broker granting access to the users with the password "goodpass", in less than 100 lines.
+type passwordBroker struct{}
+
+type sessionState struct {
+	attempts int
+}
+
+func (passwordBroker) NewSession(_ context.Context, s *sdk.Session[sessionState]) error {
+	if s.Username == "unknown-user" {
+		return errors.New("unknown user")
+	}
+	return nil
+}
+
+func (passwordBroker) AuthenticationModes(_ context.Context, _ *sdk.Session[sessionState], supported sdk.SupportedLayouts) ([]sdk.AuthenticationMode, error) {
+	var modes []sdk.AuthenticationMode
+	if supported.Supports(sdk.LayoutForm, sdk.EntryCharsPassword) {
+		modes = append(modes, sdk.AuthenticationMode{ID: "password", Label: "Password"})
+	}
+	if supported.Supports(sdk.LayoutQrCode, "") {
+		modes = append(modes, sdk.AuthenticationMode{ID: "qrcode", Label: "Login with a QR code"})
+	}
+	return modes, nil
+}
+
+func (passwordBroker) SelectAuthenticationMode(_ context.Context, _ *sdk.Session[sessionState], modeID string) (sdk.UILayout, error) {
+	switch modeID {
+	case "password":
+		return sdk.FormLayout("Password", sdk.EntryCharsPassword), nil
+	case "qrcode":
+		return sdk.QrCodeLayout("Scan the QR code", "https://example.com", "1337").WithButton("Regenerate code"), nil
+	}
+	return sdk.UILayout{}, fmt.Errorf("unknown authentication mode %q", modeID)
+}
+
+func (passwordBroker) IsAuthenticated(ctx context.Context, s *sdk.Session[sessionState], data sdk.AuthenticationData) (sdk.Reply, error) {
+	if s.AuthenticationMode == "qrcode" {
+		// The QR code is never scanned, so we wait for the authentication to be cancelled.
+		if err := s.ReportProgress(sdk.Progress("Waiting for the QR code to be scanned").WithPercentage(50).Cancellable()); err != nil {
+			return sdk.Reply{}, err
+		}
+		<-ctx.Done()
+		return sdk.Cancelled(), nil
+	}
+
+	s.State.attempts++
+	if data.Secret != "goodpass" {
+		return sdk.Retry(fmt.Sprintf("Invalid password, attempt %d", s.State.attempts)).WithErrorCode(sdk.ErrorCodeInvalidCredentials), nil
+	}
+	return sdk.Granted(userInfo(s.Username)), nil
+}
+
+func (passwordBroker) UserPreCheck(_ context.Context, username string) (sdk.UserInfo, error) {
+	return userInfo(username), nil
+}
+
+func userInfo(username string) sdk.UserInfo {
+	return sdk.UserInfo{
+		Name:   username,
+		Gecos:  "gecos for " + username,
+		Dir:    "/home/" + username,
+		Shell:  "/bin/sh",
+		Groups: []sdk.Group{{Name: "group-" + username, UGID: "ugid-" + username}},
+	}
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username string
+		mode     string
+		secrets  []string
+		cancel   bool
+
+		wantAccess string
+		wantData   string
+		wantErr    bool
+	}{
+		"Granted_with_valid_password": {mode: "password", secrets: []string{"goodpass"}, wantAccess: auth.Granted},
+		"Granted_after_retrying":      {mode: "password", secrets: []string{"badpass", "goodpass"}, wantAccess: auth.Granted},
+		"Retry_with_invalid_password": {
+			mode: "password", secrets: []string{"badpass", "badpass"}, wantAccess: auth.Retry,
+			wantData: `{"error_code":"invalid-credentials","message":"Invalid password, attempt 2"}`,
+		},
+		"Cancelled_when_waiting_authentication_is_cancelled": {mode: "qrcode", cancel: true, wantAccess: auth.Cancelled, wantData: "{}"},
+
+		"Error_when_session_is_refused":  {username: "unknown-user", wantErr: true},
+		"Error_when_mode_does_not_exist": {mode: "does-not-exist", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.username == "" {
+				tc.username = "user-" + strings.ToLower(name)
+			}
+
+			m, b := startBroker(t, name)
+
+			sessionID, key, err := m.NewSession(context.Background(), b.ID, tc.username, "lang", sdk.SessionModeLogin)
+			if tc.wantErr && tc.mode == "" {
+				require.Error(t, err, "NewSession should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "NewSession should not return an error, but did")
+
+			modes, err := b.GetAuthenticationModes(context.Background(), sessionID, supportedLayouts())
+			require.NoError(t, err, "GetAuthenticationModes should not return an error, but did")
+			require.Len(t, modes, 2, "GetAuthenticationModes should return the modes of the supported layouts")
+
+			layout, err := b.SelectAuthenticationMode(context.Background(), sessionID, tc.mode)
+			if tc.wantErr {
+				require.Error(t, err, "SelectAuthenticationMode should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SelectAuthenticationMode should not return an error, but did")
+			require.NotEmpty(t, layout[layouts.Type], "SelectAuthenticationMode should return a layout")
+
+			var access, data string
+			if tc.cancel {
+				ctx, cancel := context.WithCancel(context.Background())
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					access, data, err = b.IsAuthenticated(ctx, sessionID, `{"wait":"true"}`)
+				}()
+				var progress brokers.AuthProgress
+				require.Eventually(t, func() bool {
+					var ok bool
+					progress, ok = b.AuthProgress(sessionID)
+					return ok
+				}, 5*time.Second, 10*time.Millisecond, "The progress reported by the broker should be received")
+				require.Equal(t, brokers.AuthProgress{Message: "Waiting for the QR code to be scanned", Percentage: 50, Cancellable: true},
+					progress, "AuthProgress should return the progress reported by the broker")
+				cancel()
+				<-done
+				_, ok := b.AuthProgress(sessionID)
+				require.False(t, ok, "AuthProgress should not return any progress once the authentication completed")
+			}
+			for _, secret := range tc.secrets {
+				access, data, err = b.IsAuthenticated(context.Background(), sessionID,
+					fmt.Sprintf(`{"challenge":%q}`, encryptSecret(t, key, secret)))
+			}
+			require.NoError(t, err, "IsAuthenticated should not return an error, but did")
+			require.Equal(t, tc.wantAccess, access, "IsAuthenticated should return the expected access")
+
+			if tc.wantAccess != auth.Granted {
+				require.JSONEq(t, tc.wantData, data, "IsAuthenticated should return the expected data")
+				return
+			}
+			var u types.UserInfo
+			require.NoError(t, json.Unmarshal([]byte(data), &u), "IsAuthenticated should return the user info")
+			require.Equal(t, tc.username, u.Name, "IsAuthenticated should return the authenticated user")
+			require.Equal(t, "/home/"+tc.username, u.Dir, "IsAuthenticated should return the home directory of the user")
+			require.Equal(t, []types.GroupInfo{{Name: "group-" + tc.username, UGID: "ugid-" + tc.username}}, u.Groups,
+				"IsAuthenticated should return the groups of the user")
+		})
+	}
+}
+
+func TestUserPreCheck(t *testing.T) {
+	t.Parallel()
+
+	_, b := startBroker(t, t.Name())
+
+	userinfo, err := b.UserPreCheck(context.Background(), "user-pre-check")
+	require.NoError(t, err, "UserPreCheck should not return an error, but did")
+
+	var u types.UserInfo
+	require.NoError(t, json.Unmarshal([]byte(userinfo), &u), "UserPreCheck should return the user info")
+	require.Equal(t, "user-pre-check", u.Name, "UserPreCheck should return the user")
+	require.Equal(t, "/bin/sh", u.Shell, "UserPreCheck should return the shell of the user")
+}
+
+// startBroker exports a passwordBroker on the bus and returns it as loaded by authd, with its manager.
+func startBroker(t *testing.T, name string) (*brokers.Manager, *brokers.Broker) {
+	t.Helper()
+
+	s, err := sdk.NewService[sessionState](passwordBroker{})
+	require.NoError(t, err, "Setup: NewService should not return an error, but did")
+
+	conn, err := testutils.GetSystemBusConnection(t)
+	require.NoError(t, err, "Setup: could not connect to the system bus")
+	t.Cleanup(func() { conn.Close() })
+
+	id := strings.NewReplacer("_", "", "/", "").Replace(name)
+	cfg := sdk.Config{
+		Name:       "SDK broker " + name,
+		BrandIcon:  "/usr/share/icons/sdk.png",
+		DbusName:   "com.ubuntu.authd.SdkBroker" + id,
+		DbusObject: "/com/ubuntu/authd/SdkBroker",
+	}
+	require.NoError(t, s.Export(conn, cfg), "Setup: Export should not return an error, but did")
+
+	dir := t.TempDir()
+	require.NoError(t, sdk.WriteConfig(filepath.Join(dir, "sdk.conf"), cfg), "Setup: WriteConfig should not return an error, but did")
+
+	m, err := brokers.NewManager(context.Background(), dir, nil)
+	require.NoError(t, err, "Setup: could not create the brokers manager")
+	for _, b := range m.AvailableBrokers() {
+		if b.Name == cfg.Name {
+			return m, b
+		}
+	}
+	require.Fail(t, "Setup: SDK broker was not loaded")
+	return nil, nil
+}
+
+func supportedLayouts() []map[string]string {
+	return []map[string]string{
+		{
+			layouts.Type:  layouts.Form,
+			layouts.Label: layouts.Required,
+			layouts.Entry: layouts.OptionalItems("chars", "chars_password"),
+			layouts.Wait:  layouts.OptionalWithBooleans,
+		},
+		{
+			layouts.Type:    layouts.QrCode,
+			layouts.Label:   layouts.Required,
+			layouts.Content: layouts.Required,
+			layouts.Code:    layouts.Optional,
+			layouts.Button:  layouts.Optional,
+			layouts.Wait:    layouts.RequiredWithBooleans,
+		},
+	}
+}
+
+// encryptSecret encrypts the secret with the key of the broker, like authd does.
+func encryptSecret(t *testing.T, key, secret string) string {
+	t.Helper()
+
+	pubASN1, err := base64.StdEncoding.DecodeString(key)
+	require.NoError(t, err, "Setup: encryption key should be base64 encoded")
+	pubKey, err := x509.ParsePKIXPublicKey(pubASN1)
+	require.NoError(t, err, "Setup: encryption key should be valid")
+	//nolint:forcetypeassert // The key is always an RSA key.
+	ciphertext, err := rsa.EncryptOAEP(sha512.New(), rand.Reader, pubKey.(*rsa.PublicKey), []byte(secret), nil)
+	require.NoError(t, err, "Setup: could not encrypt secret")
+
+	return base64.StdEncoding.EncodeToString(ciphertext)
+}
+
+func TestMain(m *testing.M) {
+	log.SetLevel(log.DebugLevel)
+
+	cleanup, err := testutils.StartSystemBusMock()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer cleanup()
+
+	m.Run()
+}
@@ -0,0 +1,282 @@
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/decorate"
+)
+
+// Service serves the requests of authd for a broker, handling the sessions and the wire format.
+type Service[S any] struct {
+	broker     Broker[S]
+	privateKey *rsa.PrivateKey
+	publicKey  string
+
+	sessions   map[string]*sessionEntry[S]
+	sessionsMu sync.Mutex
+
+	// emitProgress emits the progress of an authentication on the bus, once the service is exported.
+	emitProgress func(sessionID string, p AuthProgress) error
+}
+
+// sessionEntry is a session with the lock serializing its calls.
+type sessionEntry[S any] struct {
+	mu      sync.Mutex
+	session Session[S]
+
+	// cancel cancels the authentication in progress, if any. It's protected by the sessions lock, as it's used
+	// while an authentication holds the session lock.
+	cancel context.CancelFunc
+}
+
+// NewService returns a service for the broker, with a new key to encrypt the secrets sent by authd.
+func NewService[S any](b Broker[S]) (s *Service[S], err error) {
+	defer decorate.OnError(&err, "could not create broker service")
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	pubASN1, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service[S]{
+		broker:     b,
+		privateKey: privateKey,
+		publicKey:  base64.StdEncoding.EncodeToString(pubASN1),
+		sessions:   make(map[string]*sessionEntry[S]),
+	}, nil
+}
+
+// NewSession creates a new session for the user, returning its ID and the key to encrypt the secrets with.
+func (s *Service[S]) NewSession(ctx context.Context, username, lang, mode string) (sessionID, encryptionKey string, err error) {
+	defer decorate.OnError(&err, "could not create session for user %q", username)
+
+	e := &sessionEntry[S]{session: Session[S]{
+		ID:       uuid.New().String(),
+		Username: username,
+		Lang:     lang,
+		Mode:     mode,
+	}}
+	e.session.reportProgress = func(p AuthProgress) error {
+		if s.emitProgress == nil {
+			return nil
+		}
+		return s.emitProgress(e.session.ID, p)
+	}
+	if err := s.broker.NewSession(ctx, &e.session); err != nil {
+		return "", "", err
+	}
+
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	s.sessions[e.session.ID] = e
+
+	return e.session.ID, s.publicKey, nil
+}
+
+// GetAuthenticationModes returns the authentication modes of the session, using the layouts supported by the user
+// interface.
+func (s *Service[S]) GetAuthenticationModes(ctx context.Context, sessionID string, supportedUILayouts []map[string]string) (authenticationModes []map[string]string, err error) {
+	defer decorate.OnError(&err, "could not get authentication modes of session %q", sessionID)
+
+	supported := make(SupportedLayouts, 0, len(supportedUILayouts))
+	for _, l := range supportedUILayouts {
+		supported = append(supported, layoutFromMap(l))
+	}
+
+	var modes []AuthenticationMode
+	err = s.withSession(sessionID, func(session *Session[S]) (err error) {
+		modes, err = s.broker.AuthenticationModes(ctx, session, supported)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range modes {
+		authenticationModes = append(authenticationModes, map[string]string{
+			layouts.ID:    m.ID,
+			layouts.Label: m.Label,
+		})
+	}
+	return authenticationModes, nil
+}
+
+// SelectAuthenticationMode selects the authentication mode of the session, returning its layout.
+func (s *Service[S]) SelectAuthenticationMode(ctx context.Context, sessionID, authenticationModeName string) (uiLayoutInfo map[string]string, err error) {
+	defer decorate.OnError(&err, "could not select authentication mode %q of session %q", authenticationModeName, sessionID)
+
+	var layout UILayout
+	err = s.withSession(sessionID, func(session *Session[S]) (err error) {
+		if layout, err = s.broker.SelectAuthenticationMode(ctx, session, authenticationModeName); err != nil {
+			return err
+		}
+		session.AuthenticationMode = authenticationModeName
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return layout.toMap(), nil
+}
+
+// IsAuthenticated authenticates the user of the session with the data sent by authd, returning the access and its
+// associated data.
+func (s *Service[S]) IsAuthenticated(ctx context.Context, sessionID, authenticationData string) (access, data string, err error) {
+	defer decorate.OnError(&err, "could not authenticate user of session %q", sessionID)
+
+	var rawData map[string]string
+	if err := json.Unmarshal([]byte(authenticationData), &rawData); err != nil {
+		return "", "", fmt.Errorf("authentication data is not a valid JSON object: %v", err)
+	}
+	authData := AuthenticationData{
+		Wait: rawData["wait"] == layouts.True,
+		Skip: rawData["skip"] == layouts.True,
+	}
+	if challenge := rawData["challenge"]; challenge != "" {
+		if authData.Secret, err = s.decryptSecret(challenge); err != nil {
+			return "", "", err
+		}
+	}
+
+	var reply Reply
+	err = s.withSession(sessionID, func(session *Session[S]) (err error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		s.setCancel(sessionID, cancel)
+		defer s.setCancel(sessionID, nil)
+
+		reply, err = s.broker.IsAuthenticated(ctx, session, authData)
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	data, err = reply.data()
+	if err != nil {
+		return "", "", err
+	}
+	return reply.access, data, nil
+}
+
+// CancelIsAuthenticated cancels the authentication in progress for the session, if any.
+func (s *Service[S]) CancelIsAuthenticated(_ context.Context, sessionID string) {
+	s.setCancel(sessionID, nil)
+}
+
+// EndSession ends the session, cancelling any authentication in progress.
+func (s *Service[S]) EndSession(ctx context.Context, sessionID string) (err error) {
+	defer decorate.OnError(&err, "could not end session %q", sessionID)
+
+	s.CancelIsAuthenticated(ctx, sessionID)
+	err = s.withSession(sessionID, func(session *Session[S]) error {
+		if ender, ok := s.broker.(SessionEnder[S]); ok {
+			return ender.EndSession(ctx, session)
+		}
+		return nil
+	})
+
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	delete(s.sessions, sessionID)
+
+	return err
+}
+
+// UserPreCheck returns the information of a user which never logged in, as a JSON object.
+func (s *Service[S]) UserPreCheck(ctx context.Context, username string) (userinfo string, err error) {
+	defer decorate.OnError(&err, "could not check user %q", username)
+
+	u, err := s.broker.UserPreCheck(ctx, username)
+	if err != nil {
+		return "", err
+	}
+	d, err := json.Marshal(u)
+	if err != nil {
+		return "", err
+	}
+	return string(d), nil
+}
+
+// withSession calls f with the session, serializing the calls for a given session.
+func (s *Service[S]) withSession(sessionID string, f func(*Session[S]) error) error {
+	s.sessionsMu.Lock()
+	e, ok := s.sessions[sessionID]
+	s.sessionsMu.Unlock()
+	if !ok {
+		return errors.New("session does not exist")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return f(&e.session)
+}
+
+// setCancel cancels the authentication in progress for the session and replaces its cancel function.
+func (s *Service[S]) setCancel(sessionID string, cancel context.CancelFunc) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	e, ok := s.sessions[sessionID]
+	if !ok {
+		return
+	}
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.cancel = cancel
+}
+
+// decryptSecret decrypts the base64 encoded secret encrypted by authd with the public key of the service.
+func (s *Service[S]) decryptSecret(challenge string) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(challenge)
+	if err != nil {
+		return "", fmt.Errorf("secret is not base64 encoded: %v", err)
+	}
+	plaintext, err := rsa.DecryptOAEP(sha512.New(), nil, s.privateKey, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt secret: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+// data returns the data associated with the reply, in the format expected by authd.
+func (r Reply) data() (string, error) {
+	var v any
+	switch r.access {
+	case auth.Granted:
+		v = map[string]UserInfo{"userinfo": r.userInfo}
+	case auth.Denied, auth.Retry:
+		m := map[string]string{"message": r.message}
+		if r.errorCode != "" {
+			m[auth.ErrorCodeKey] = r.errorCode
+		}
+		v = m
+	case auth.Next, auth.Cancelled:
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid reply %q", r.access)
+	}
+
+	d, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal reply data: %v", err)
+	}
+	return string(d), nil
+}
@@ -0,0 +1,118 @@
+// Package authenticate implements the authctl command authenticating a user without any user interaction.
+package authenticate
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// NewCmd returns the authenticate command, connecting to the daemon through the given socket path.
+func NewCmd(socketPath *string) *cobra.Command {
+	var broker, authMode string
+
+	cmd := &cobra.Command{
+		Use:   "authenticate USERNAME",
+		Short: "Authenticate a user without any user interaction",
+		Long: `Authenticate a user, typically a service account, without any user interaction.
+
+The secret is read from the standard input, and is sent to the first authentication
+mode of the broker accepting it, unless one is requested.
+This is meant for system services and scheduled jobs which need a broker backed identity.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			secret, err := readSecret(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			brokerID, err := brokerID(cmd.Context(), c, broker)
+			if err != nil {
+				return err
+			}
+
+			resp, err := c.AuthenticateHeadless(cmd.Context(), &authd.AHRequest{
+				BrokerId:             brokerID,
+				Username:             args[0],
+				AuthenticationModeId: authMode,
+				Secret:               secret,
+			})
+			if err != nil {
+				return err
+			}
+			if resp.GetAccess() != auth.Granted {
+				msg := fmt.Sprintf("authentication %s", resp.GetAccess())
+				if resp.GetMsg() != "" {
+					msg = fmt.Sprintf("%s: %s", msg, resp.GetMsg())
+				}
+				return authderrors.New(authderrors.PermissionDenied, msg)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&broker, "broker", "b", "", "the name or ID of the broker to authenticate with")
+	cmd.Flags().StringVarP(&authMode, "mode", "m", "", "the ID of the authentication mode to use")
+	_ = cmd.MarkFlagRequired("broker")
+	_ = cmd.RegisterFlagCompletionFunc("broker", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		c, closeConn, err := client.NewPAM(*socketPath)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		defer closeConn()
+
+		resp, err := c.AvailableBrokers(cmd.Context(), &authd.Empty{})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var names []string
+		for _, b := range resp.GetBrokersInfos() {
+			names = append(names, b.GetName())
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// readSecret returns the first line of r, which must not be empty.
+func readSecret(r io.Reader) (string, error) {
+	secret, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("could not read secret: %v", err)
+	}
+	secret = strings.TrimRight(secret, "\r\n")
+	if secret == "" {
+		return "", authderrors.New(authderrors.InvalidArgument, "no secret provided on the standard input")
+	}
+	return secret, nil
+}
+
+// brokerID returns the ID of the broker matching the given name or ID.
+func brokerID(ctx context.Context, c authd.PAMClient, nameOrID string) (string, error) {
+	resp, err := c.AvailableBrokers(ctx, &authd.Empty{})
+	if err != nil {
+		return "", err
+	}
+	for _, b := range resp.GetBrokersInfos() {
+		if b.GetId() == nameOrID || strings.EqualFold(b.GetName(), nameOrID) {
+			return b.GetId(), nil
+		}
+	}
+	return "", authderrors.Errorf(authderrors.NotFound, "no broker named %q", nameOrID)
+}
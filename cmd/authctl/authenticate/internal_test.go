@@ -0,0 +1,40 @@
+package authenticate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSecret(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input string
+
+		want    string
+		wantErr bool
+	}{
+		"Read_secret_without_newline":            {input: "my secret", want: "my secret"},
+		"Read_secret_stripping_trailing_newline": {input: "my secret\n", want: "my secret"},
+		"Read_secret_stripping_trailing_CRLF":    {input: "my secret\r\n", want: "my secret"},
+		"Read_only_the_first_line":               {input: "my secret\nsomething else\n", want: "my secret"},
+
+		"Error_when_input_is_empty":      {input: "", wantErr: true},
+		"Error_when_first_line_is_empty": {input: "\nmy secret\n", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := readSecret(strings.NewReader(tc.input))
+			if tc.wantErr {
+				require.Error(t, err, "readSecret should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "readSecret should not return an error, but did")
+			require.Equal(t, tc.want, got, "readSecret returned an unexpected secret")
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Package broker implements the authctl commands helping to develop and debug the brokers.
+package broker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/brokers/conformance"
+	"github.com/ubuntu/authd/internal/consts"
+)
+
+// NewCmd returns the broker command, printing the results in the given output format.
+func NewCmd(output *printer.Format) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "broker COMMAND",
+		Short: "Develop and debug the brokers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newConformanceCmd(output))
+
+	return cmd
+}
+
+// newConformanceCmd returns the command checking the conformance of a broker to the protocol.
+func newConformanceCmd(output *printer.Format) *cobra.Command {
+	var name, username, brokersDir string
+
+	cmd := &cobra.Command{
+		Use:   "conformance",
+		Short: "Check that a broker conforms to the authd protocol",
+		Long: `Check that a broker conforms to the authd protocol.
+
+The broker is loaded from its configuration file and called directly on the system bus,
+like authd does, without going through the daemon. Its sessions are started for a test
+user and exercise the error paths: invalid secrets, cancelled authentications and
+unknown or ended sessions. No user is ever added to the authd database.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := brokers.NewManager(cmd.Context(), brokersDir, nil)
+			if err != nil {
+				return err
+			}
+
+			brokerID, err := brokerID(m, name)
+			if err != nil {
+				return err
+			}
+
+			results, err := conformance.Run(cmd.Context(), m, brokerID, conformance.WithUsername(username))
+			if err != nil {
+				return err
+			}
+			if err := printer.Print(cmd.OutOrStdout(), *output, resultList{Results: results}); err != nil {
+				return err
+			}
+
+			var failed int
+			for _, r := range results {
+				if r.Status == conformance.Failed {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("broker %q failed %d conformance checks", name, failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&name, "name", "n", "", "the name of the broker to check")
+	cmd.Flags().StringVarP(&username, "user", "u", conformance.DefaultUsername, "the user to start the sessions for")
+	cmd.Flags().StringVar(&brokersDir, "brokers-dir", consts.DefaultBrokersConfPath, "the directory of the brokers configuration files")
+	_ = cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+// brokerID returns the ID of the broker with the given name, which is not the local one.
+func brokerID(m *brokers.Manager, name string) (string, error) {
+	for _, b := range m.AvailableBrokers() {
+		if !strings.EqualFold(b.Name, name) {
+			continue
+		}
+		if b.ID == brokers.LocalBrokerName {
+			return "", authderrors.New(authderrors.InvalidArgument, "the local broker can't be checked")
+		}
+		return b.ID, nil
+	}
+	return "", authderrors.Errorf(authderrors.NotFound, "no broker named %q", name)
+}
+
+// resultList is the list of conformance check results printed by the conformance command.
+type resultList struct {
+	Results []conformance.Result `json:"results" yaml:"results"`
+}
+
+// Header returns the header of the results table.
+func (l resultList) Header() []string {
+	return []string{"CHECK", "STATUS", "DETAILS"}
+}
+
+// Rows returns the results table rows.
+func (l resultList) Rows() (rows [][]string) {
+	for _, r := range l.Results {
+		rows = append(rows, []string{r.Check, strings.ToUpper(string(r.Status)), r.Details})
+	}
+	return rows
+}
@@ -0,0 +1,62 @@
+package broker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/brokers/conformance"
+)
+
+func TestPrintResults(t *testing.T) {
+	t.Parallel()
+
+	results := []conformance.Result{
+		{Check: "new_session", Status: conformance.Passed, Details: "session started"},
+		{Check: "cancel_authentication", Status: conformance.Skipped, Details: "no wait mode"},
+		{Check: "invalid_secret", Status: conformance.Failed},
+	}
+
+	tests := map[string]struct {
+		format printer.Format
+
+		want string
+	}{
+		"Print_results_as_table": {
+			format: printer.Table,
+			want: strings.Join([]string{
+				"CHECK                  STATUS   DETAILS",
+				"new_session            PASSED   session started",
+				"cancel_authentication  SKIPPED  no wait mode",
+				"invalid_secret         FAILED   ",
+				"",
+			}, "\n"),
+		},
+		"Print_results_as_YAML": {
+			format: printer.YAML,
+			want: strings.Join([]string{
+				`results:`,
+				`  - check: new_session`,
+				`    status: passed`,
+				`    details: session started`,
+				`  - check: cancel_authentication`,
+				`    status: skipped`,
+				`    details: no wait mode`,
+				`  - check: invalid_secret`,
+				`    status: failed`,
+				``,
+			}, "\n"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var out strings.Builder
+			err := printer.Print(&out, tc.format, resultList{Results: results})
+			require.NoError(t, err, "Print should not return an error, but did")
+			require.Equal(t, tc.want, out.String(), "Print returned an unexpected output")
+		})
+	}
+}
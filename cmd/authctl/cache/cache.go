@@ -0,0 +1,90 @@
+// Package cache implements the authctl commands exporting and importing the users database of authd.
+package cache
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// NewCmd returns the cache command, connecting to the daemon through the given socket path.
+func NewCmd(socketPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache COMMAND",
+		Short: "Export and import the users database",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "dump",
+		Short: "Print the users, groups and broker of each user as JSON",
+		Long: `Print the whole content of the users database as JSON.
+
+The output can be attached to bug reports, or imported on another machine with the import command.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			resp, err := c.DumpDatabase(cmd.Context(), &authd.Empty{})
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(resp.GetContent()))
+			return err
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "import FILE",
+		Short: "Replace the users database with the content of a dump",
+		Long: `Replace the whole content of the users database with the JSON content printed by the dump command.
+
+Use - to read the content from the standard input.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content, err := readDump(cmd.InOrStdin(), args[0])
+			if err != nil {
+				return err
+			}
+
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			_, err = c.ImportDatabase(cmd.Context(), &authd.DatabaseDump{Content: content})
+			return err
+		},
+	})
+
+	return cmd
+}
+
+// readDump returns the content of the given file, or of stdin if the path is "-".
+func readDump(stdin io.Reader, path string) ([]byte, error) {
+	if path == "-" {
+		content, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the standard input: %v", err)
+		}
+		return content, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read dump: %v", err)
+	}
+	return content, nil
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDump(t *testing.T) {
+	t.Parallel()
+
+	dumpPath := filepath.Join(t.TempDir(), "users.json")
+	err := os.WriteFile(dumpPath, []byte(`{"users": []}`), 0600)
+	require.NoError(t, err, "Setup: could not write dump")
+
+	tests := map[string]struct {
+		path  string
+		stdin string
+
+		want    string
+		wantErr bool
+	}{
+		"Read_dump_from_file":           {path: dumpPath, stdin: "ignored", want: `{"users": []}`},
+		"Read_dump_from_standard_input": {path: "-", stdin: `{"groups": []}`, want: `{"groups": []}`},
+
+		"Error_when_file_does_not_exist": {path: filepath.Join(t.TempDir(), "does-not-exist"), wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := readDump(strings.NewReader(tc.stdin), tc.path)
+			if tc.wantErr {
+				require.Error(t, err, "readDump should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "readDump should not return an error, but did")
+			require.Equal(t, tc.want, string(got), "readDump returned an unexpected content")
+		})
+	}
+}
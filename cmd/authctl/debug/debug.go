@@ -0,0 +1,69 @@
+// Package debug implements the authctl commands enabling and disabling the debug socket of the daemon.
+package debug
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// NewCmd returns the debug command, connecting to the daemon through the given socket path.
+func NewCmd(socketPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug COMMAND",
+		Short: "Profile the daemon",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "enable",
+		Short: "Serve the profiles and the runtime statistics of the daemon on its debug socket",
+		Long: `Serve the profiles of net/http/pprof and the runtime statistics of expvar on the
+debug socket of the daemon, until it is disabled or the daemon stops.
+
+Only root can connect to the debug socket, for example with:
+  curl --unix-socket /run/authd-debug.sock -o cpu.pprof http://authd/debug/pprof/profile
+  curl --unix-socket /run/authd-debug.sock http://authd/debug/vars`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setDebugSocket(cmd, *socketPath, true)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "disable",
+		Short: "Stop serving on the debug socket of the daemon",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setDebugSocket(cmd, *socketPath, false)
+		},
+	})
+
+	return cmd
+}
+
+// setDebugSocket enables or disables the debug socket of the daemon and prints its state.
+func setDebugSocket(cmd *cobra.Command, socketPath string, enable bool) error {
+	c, closeConn, err := client.NewPAM(socketPath)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := c.SetDebugSocket(cmd.Context(), &authd.SDSRequest{Enable: enable})
+	if err != nil {
+		return err
+	}
+
+	if resp.GetEnabled() {
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "Debug socket enabled on %s\n", resp.GetSocketPath())
+		return err
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "Debug socket %s disabled\n", resp.GetSocketPath())
+	return err
+}
@@ -0,0 +1,108 @@
+// Package doctor implements the authctl command diagnosing the setup of authd.
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/consts"
+	"github.com/ubuntu/authd/internal/doctor"
+)
+
+// NewCmd returns the doctor command, connecting to the daemon through the given socket path and printing the results
+// in the given output format.
+func NewCmd(socketPath *string, output *printer.Format) *cobra.Command {
+	var bundle, brokersDir, dbDir, configFile string
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the setup of authd",
+		Long: `Diagnose the setup of authd.
+
+The checks cover the connection to the daemon, the reachability of the brokers
+on the system bus, the NSS and PAM configurations, the integrity of the database
+and the free disk space left to store it. Run it as root to check the database.
+
+With --bundle, a support bundle is written to the given file, as a gzipped
+tarball of the results and of the configuration files of authd, its brokers,
+NSS and PAM. The values of the secrets in the configuration files are redacted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			opts := []doctor.Option{
+				doctor.WithBrokersDir(brokersDir),
+				doctor.WithDatabaseDir(dbDir),
+				doctor.WithConfigFile(configFile),
+			}
+			results := doctor.Run(cmd.Context(), c, opts...)
+			if err := printer.Print(cmd.OutOrStdout(), *output, resultList{Results: results}); err != nil {
+				return err
+			}
+
+			if bundle != "" {
+				if err := writeBundle(bundle, results, opts...); err != nil {
+					return err
+				}
+			}
+
+			var failed int
+			for _, r := range results {
+				if r.Status == doctor.Failed {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d diagnostic checks failed", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&bundle, "bundle", "b", "", "write a support bundle to the given file")
+	cmd.Flags().StringVar(&brokersDir, "brokers-dir", consts.DefaultBrokersConfPath, "the directory of the brokers configuration files")
+	cmd.Flags().StringVar(&dbDir, "db-dir", consts.DefaultDatabaseDir, "the directory of the authd database")
+	cmd.Flags().StringVar(&configFile, "config", "/etc/authd/authd.yaml", "the authd configuration file to add to the support bundle")
+
+	return cmd
+}
+
+// writeBundle writes the support bundle to the given path, readable only by the current user.
+func writeBundle(path string, results []doctor.Result, opts ...doctor.Option) (err error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("could not create support bundle: %v", err)
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	return doctor.WriteBundle(f, results, opts...)
+}
+
+// resultList is the list of diagnostic check results printed by the doctor command.
+type resultList struct {
+	Results []doctor.Result `json:"results" yaml:"results"`
+}
+
+// Header returns the header of the results table.
+func (l resultList) Header() []string {
+	return []string{"CHECK", "STATUS", "DETAILS"}
+}
+
+// Rows returns the results table rows.
+func (l resultList) Rows() (rows [][]string) {
+	for _, r := range l.Results {
+		rows = append(rows, []string{r.Check, strings.ToUpper(string(r.Status)), r.Details})
+	}
+	return rows
+}
@@ -0,0 +1,71 @@
+package doctor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/doctor"
+)
+
+func TestPrintResults(t *testing.T) {
+	t.Parallel()
+
+	results := []doctor.Result{
+		{Check: "daemon", Status: doctor.Passed, Details: "the daemon answers with 2 available brokers"},
+		{Check: "disk_space", Status: doctor.Warning, Details: "only 10 MiB left in /var/lib/authd/"},
+		{Check: "pam", Status: doctor.Failed},
+	}
+
+	tests := map[string]struct {
+		format printer.Format
+
+		want string
+	}{
+		"Print_results_as_table": {
+			format: printer.Table,
+			want: strings.Join([]string{
+				"CHECK       STATUS   DETAILS",
+				"daemon      PASSED   the daemon answers with 2 available brokers",
+				"disk_space  WARNING  only 10 MiB left in /var/lib/authd/",
+				"pam         FAILED   ",
+				"",
+			}, "\n"),
+		},
+		"Print_results_as_JSON": {
+			format: printer.JSON,
+			want: strings.Join([]string{
+				`{`,
+				`  "results": [`,
+				`    {`,
+				`      "check": "daemon",`,
+				`      "status": "passed",`,
+				`      "details": "the daemon answers with 2 available brokers"`,
+				`    },`,
+				`    {`,
+				`      "check": "disk_space",`,
+				`      "status": "warning",`,
+				`      "details": "only 10 MiB left in /var/lib/authd/"`,
+				`    },`,
+				`    {`,
+				`      "check": "pam",`,
+				`      "status": "failed"`,
+				`    }`,
+				`  ]`,
+				`}`,
+				``,
+			}, "\n"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var out strings.Builder
+			err := printer.Print(&out, tc.format, resultList{Results: results})
+			require.NoError(t, err, "Print should not return an error, but did")
+			require.Equal(t, tc.want, out.String(), "Print returned an unexpected output")
+		})
+	}
+}
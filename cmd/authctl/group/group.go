@@ -0,0 +1,84 @@
+// Package group implements the authctl commands about the groups of the users.
+package group
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// NewCmd returns the group command, connecting to the daemon through the given socket path and printing the results
+// in the given output format.
+func NewCmd(socketPath *string, output *printer.Format) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "group COMMAND",
+		Short: "Manage the groups of the users",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reconcile",
+		Short: "Add the users back to the local groups they were removed from",
+		Long: `Add the users of authd back to the local groups they were given by their broker
+or by an override, but were removed from, for example by a manual edit of
+/etc/group. The users which were changed are printed.
+
+The users are never removed from the other local groups, as the ones added by
+the administrator can't be told apart from the ones added by mistake.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			resp, err := c.ReconcileLocalGroups(cmd.Context(), &authd.Empty{})
+			if err != nil {
+				return err
+			}
+			return printer.Print(cmd.OutOrStdout(), *output, newReconciledUsers(resp))
+		},
+	})
+
+	return cmd
+}
+
+// reconciledUsers are the users added back to their local groups by the reconcile command.
+type reconciledUsers struct {
+	Users []reconciledUser `json:"users" yaml:"users"`
+}
+
+// reconciledUser is a user added back to some of its local groups.
+type reconciledUser struct {
+	Name        string   `json:"name" yaml:"name"`
+	AddedGroups []string `json:"added_groups" yaml:"added_groups"`
+}
+
+func newReconciledUsers(resp *authd.RLGResponse) reconciledUsers {
+	r := reconciledUsers{Users: []reconciledUser{}}
+	for _, u := range resp.GetUsers() {
+		r.Users = append(r.Users, reconciledUser{Name: u.GetName(), AddedGroups: u.GetAddedGroups()})
+	}
+	return r
+}
+
+// Header returns the header of the reconciled users table.
+func (r reconciledUsers) Header() []string {
+	return []string{"USER", "ADDED GROUPS"}
+}
+
+// Rows returns a row for each reconciled user, with the local groups it was added back to.
+func (r reconciledUsers) Rows() [][]string {
+	var rows [][]string
+	for _, u := range r.Users {
+		rows = append(rows, []string{u.Name, strings.Join(u.AddedGroups, ",")})
+	}
+	return rows
+}
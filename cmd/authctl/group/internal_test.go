@@ -0,0 +1,68 @@
+package group
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+func TestPrintReconciledUsers(t *testing.T) {
+	t.Parallel()
+
+	user1 := &authd.RLGResponse_User{Name: "user1", AddedGroups: []string{"localgroup1", "localgroup2"}}
+	user2 := &authd.RLGResponse_User{Name: "user2", AddedGroups: []string{"localgroup1"}}
+
+	tests := map[string]struct {
+		resp   *authd.RLGResponse
+		format printer.Format
+
+		want string
+	}{
+		"Print_no_reconciled_user": {
+			resp: &authd.RLGResponse{},
+			want: "USER  ADDED GROUPS\n",
+		},
+		"Print_reconciled_users": {
+			resp: &authd.RLGResponse{Users: []*authd.RLGResponse_User{user1, user2}},
+			want: strings.Join([]string{
+				"USER   ADDED GROUPS",
+				"user1  localgroup1,localgroup2",
+				"user2  localgroup1",
+				"",
+			}, "\n"),
+		},
+		"Print_reconciled_users_as_JSON": {
+			resp:   &authd.RLGResponse{Users: []*authd.RLGResponse_User{user2}},
+			format: printer.JSON,
+			want:   "{\n  \"users\": [\n    {\n      \"name\": \"user2\",\n      \"added_groups\": [\n        \"localgroup1\"\n      ]\n    }\n  ]\n}\n",
+		},
+		"Print_reconciled_users_as_YAML": {
+			resp:   &authd.RLGResponse{Users: []*authd.RLGResponse_User{user2}},
+			format: printer.YAML,
+			want: strings.Join([]string{
+				`users:`,
+				`  - name: user2`,
+				`    added_groups:`,
+				`      - localgroup1`,
+				``,
+			}, "\n"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.format == "" {
+				tc.format = printer.Table
+			}
+
+			var out strings.Builder
+			err := printer.Print(&out, tc.format, newReconciledUsers(tc.resp))
+			require.NoError(t, err, "Print should not return an error, but did")
+			require.Equal(t, tc.want, out.String(), "Print returned an unexpected output")
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Package client provides the connection of the authctl commands to the daemon.
+package client
+
+import (
+	"fmt"
+
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/compat"
+	"github.com/ubuntu/authd/internal/services/errmessages"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// NewPAM returns a PAM service client connected to the daemon and the function to close the connection.
+func NewPAM(socketPath string) (client authd.PAMClient, closeConn func(), err error) {
+	conn, err := grpc.NewClient("unix://"+socketPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(errmessages.FormatErrorMessage, compat.UnaryClientInterceptor))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not connect to authd: %v", err)
+	}
+	return authd.NewPAMClient(conn), func() { _ = conn.Close() }, nil
+}
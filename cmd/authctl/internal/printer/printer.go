@@ -0,0 +1,96 @@
+// Package printer prints the results of the authctl commands in the format requested by the user.
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format supported by the printer.
+type Format string
+
+const (
+	// Table prints the values as a table aligned on columns, to be read by humans.
+	Table Format = "table"
+	// JSON prints the values as indented JSON.
+	JSON Format = "json"
+	// YAML prints the values as YAML.
+	YAML Format = "yaml"
+)
+
+// Formats are all the supported output formats.
+var Formats = []Format{Table, JSON, YAML}
+
+// String returns the format name.
+func (f *Format) String() string {
+	return string(*f)
+}
+
+// Set sets the format from its name, so that it can be used as a command line flag.
+func (f *Format) Set(s string) error {
+	if !slices.Contains(Formats, Format(s)) {
+		return fmt.Errorf("unsupported output format %q, must be one of: %s", s, formatNames())
+	}
+	*f = Format(s)
+	return nil
+}
+
+// Type returns the name of the flag value type.
+func (f *Format) Type() string {
+	return "format"
+}
+
+// formatNames returns the names of the supported formats, separated by commas.
+func formatNames() string {
+	var names []string
+	for _, f := range Formats {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, ", ")
+}
+
+// Tabular is implemented by the values that can be printed as a table.
+type Tabular interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// Print prints the value to w in the given format.
+func Print(w io.Writer, format Format, v Tabular) error {
+	switch format {
+	case Table, "":
+		return printTable(w, v)
+	case JSON:
+		d, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("can't marshal output to JSON: %v", err)
+		}
+		_, err = fmt.Fprintln(w, string(d))
+		return err
+	case YAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("can't marshal output to YAML: %v", err)
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// printTable prints the header and rows of the value aligned on columns.
+func printTable(w io.Writer, v Tabular) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(v.Header(), "\t"))
+	for _, row := range v.Rows() {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
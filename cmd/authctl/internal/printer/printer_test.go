@@ -0,0 +1,41 @@
+package printer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+)
+
+func TestFormatSet(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value string
+
+		want    printer.Format
+		wantErr bool
+	}{
+		"Set_table_format": {value: "table", want: printer.Table},
+		"Set_json_format":  {value: "json", want: printer.JSON},
+		"Set_yaml_format":  {value: "yaml", want: printer.YAML},
+
+		"Error_on_unsupported_format": {value: "xml", wantErr: true},
+		"Error_on_empty_format":       {value: "", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f := printer.Table
+			err := f.Set(tc.value)
+			if tc.wantErr {
+				require.Error(t, err, "Set should return an error, but did not")
+				require.Equal(t, printer.Table, f, "Format should not change on error")
+				return
+			}
+			require.NoError(t, err, "Set should not return an error, but did")
+			require.Equal(t, tc.want, f, "Set did not set the expected format")
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package main is the entry point of authctl, the command line tool to inspect and control the authd daemon.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/authenticate"
+	"github.com/ubuntu/authd/cmd/authctl/broker"
+	"github.com/ubuntu/authd/cmd/authctl/cache"
+	"github.com/ubuntu/authd/cmd/authctl/debug"
+	"github.com/ubuntu/authd/cmd/authctl/doctor"
+	"github.com/ubuntu/authd/cmd/authctl/group"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/cmd/authctl/pin"
+	"github.com/ubuntu/authd/cmd/authctl/session"
+	"github.com/ubuntu/authd/cmd/authctl/uid"
+	"github.com/ubuntu/authd/cmd/authctl/user"
+	"github.com/ubuntu/authd/cmd/authctl/version"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/consts"
+)
+
+// cmdName is the binary name for the control tool.
+const cmdName = "authctl"
+
+// Exit codes of the command, so that it can be used in scripts.
+const (
+	exitOK = iota
+	exitError
+	exitUsageError
+	exitPermissionDenied
+	exitNotFound
+	exitUnavailable
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs(args)
+
+	err := rootCmd.Execute()
+	if err == nil {
+		return exitOK
+	}
+
+	fmt.Fprintf(rootCmd.ErrOrStderr(), "Error: %v\n", err)
+	return exitCode(err, !rootCmd.SilenceUsage)
+}
+
+// newRootCmd returns the authctl command with all its subcommands.
+func newRootCmd() *cobra.Command {
+	var socketPath string
+	output := printer.Table
+
+	rootCmd := &cobra.Command{
+		Use:           fmt.Sprintf("%s COMMAND", cmdName),
+		Short:         "Control the authentication daemon",
+		Long:          "Inspect and control the authentication daemon and its ongoing sessions.",
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// Command parsing has been successful. Returns to not print usage anymore.
+			cmd.Root().SilenceUsage = true
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	// We provide our own completion command, restricted to the shells we support.
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+
+	rootCmd.PersistentFlags().StringVar(&socketPath, "socket", consts.DefaultSocketPath, "the authd socket to connect to")
+	rootCmd.PersistentFlags().VarP(&output, "output", "o", "output format (table, json or yaml)")
+	_ = rootCmd.RegisterFlagCompletionFunc("output", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		var formats []string
+		for _, f := range printer.Formats {
+			formats = append(formats, string(f))
+		}
+		return formats, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	rootCmd.AddCommand(newCompletionCmd())
+	rootCmd.AddCommand(session.NewCmd(&socketPath, &output))
+	rootCmd.AddCommand(authenticate.NewCmd(&socketPath))
+	rootCmd.AddCommand(cache.NewCmd(&socketPath))
+	rootCmd.AddCommand(pin.NewCmd(&socketPath, &output))
+	rootCmd.AddCommand(user.NewCmd(&socketPath, &output))
+	rootCmd.AddCommand(uid.NewCmd(&socketPath, &output))
+	rootCmd.AddCommand(group.NewCmd(&socketPath, &output))
+	rootCmd.AddCommand(broker.NewCmd(&output))
+	rootCmd.AddCommand(doctor.NewCmd(&socketPath, &output))
+	rootCmd.AddCommand(debug.NewCmd(&socketPath))
+	rootCmd.AddCommand(version.NewCmd(&socketPath, &output))
+
+	return rootCmd
+}
+
+// newCompletionCmd returns the command generating the shell completion scripts.
+func newCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:       "completion bash|zsh|fish",
+		Short:     "Generate the completion script for the specified shell",
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return cmd.Root().GenBashCompletionV2(out, true)
+			case "zsh":
+				return cmd.Root().GenZshCompletion(out)
+			case "fish":
+				return cmd.Root().GenFishCompletion(out, true)
+			}
+			return nil
+		},
+	}
+}
+
+// exitCode returns the exit code matching the error.
+func exitCode(err error, usageError bool) int {
+	if usageError {
+		return exitUsageError
+	}
+
+	switch authderrors.CodeOf(err) {
+	case authderrors.InvalidArgument:
+		return exitUsageError
+	case authderrors.PermissionDenied:
+		return exitPermissionDenied
+	case authderrors.NotFound:
+		return exitNotFound
+	case authderrors.Unavailable, authderrors.Timeout:
+		return exitUnavailable
+	}
+	return exitError
+}
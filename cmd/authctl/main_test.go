@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/authderrors"
+)
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		shell string
+
+		wantErr bool
+	}{
+		"Generate_bash_completion": {shell: "bash"},
+		"Generate_zsh_completion":  {shell: "zsh"},
+		"Generate_fish_completion": {shell: "fish"},
+
+		"Error_on_unsupported_shell": {shell: "powershell", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := newRootCmd()
+			var out strings.Builder
+			cmd.SetOut(&out)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs([]string{"completion", tc.shell})
+
+			err := cmd.Execute()
+			if tc.wantErr {
+				require.Error(t, err, "Completion should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Completion should not return an error, but did")
+			require.Contains(t, out.String(), cmdName, "Completion script should reference the command")
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err        error
+		usageError bool
+
+		want int
+	}{
+		"Usage_error":               {err: errors.New("some error"), usageError: true, want: exitUsageError},
+		"Generic_error":             {err: errors.New("some error"), want: exitError},
+		"Invalid_argument_error":    {err: authderrors.New(authderrors.InvalidArgument, "err"), want: exitUsageError},
+		"Permission_denied_error":   {err: authderrors.New(authderrors.PermissionDenied, "err"), want: exitPermissionDenied},
+		"Not_found_error":           {err: authderrors.New(authderrors.NotFound, "err"), want: exitNotFound},
+		"Unavailable_error":         {err: authderrors.New(authderrors.Unavailable, "err"), want: exitUnavailable},
+		"Timeout_error":             {err: authderrors.New(authderrors.Timeout, "err"), want: exitUnavailable},
+		"Unclassified_daemon_error": {err: authderrors.New(authderrors.Internal, "err"), want: exitError},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, exitCode(tc.err, tc.usageError), "exitCode returned an unexpected code")
+		})
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	t.Parallel()
+
+	noSocket := filepath.Join(t.TempDir(), "authd.sock")
+
+	tests := map[string]struct {
+		args []string
+
+		want int
+	}{
+		"Success_on_help": {args: []string{"--help"}, want: exitOK},
+
+		"Usage_error_on_unknown_command":         {args: []string{"unknown"}, want: exitUsageError},
+		"Usage_error_on_unknown_subcommand":      {args: []string{"session", "unknown"}, want: exitUsageError},
+		"Usage_error_on_missing_argument":        {args: []string{"session", "kill"}, want: exitUsageError},
+		"Usage_error_on_invalid_output":          {args: []string{"--output", "xml", "session", "list"}, want: exitUsageError},
+		"Unavailable_when_daemon_is_not_running": {args: []string{"--socket", noSocket, "session", "list"}, want: exitUnavailable},
+
+		"Usage_error_on_missing_dump_to_import": {args: []string{"cache", "import"}, want: exitUsageError},
+		"Error_on_unexisting_dump_to_import":    {args: []string{"--socket", noSocket, "cache", "import", noSocket}, want: exitError},
+
+		"Usage_error_on_missing_pin_user": {args: []string{"pin", "set"}, want: exitUsageError},
+
+		"Usage_error_on_missing_gecos_user": {args: []string{"user", "gecos"}, want: exitUsageError},
+		"Usage_error_on_no_gecos_field":     {args: []string{"--socket", noSocket, "user", "gecos", "user1"}, want: exitUsageError},
+		"Usage_error_on_missing_shell":      {args: []string{"user", "set-shell", "user1"}, want: exitUsageError},
+		"Usage_error_on_missing_reset_user": {args: []string{"user", "reset-broker"}, want: exitUsageError},
+		"Usage_error_on_no_override":        {args: []string{"--socket", noSocket, "user", "override", "set", "user1"}, want: exitUsageError},
+
+		"Success_on_local_version":                     {args: []string{"--socket", noSocket, "version"}, want: exitOK},
+		"Unavailable_on_remote_version_without_daemon": {args: []string{"--socket", noSocket, "version", "--remote"}, want: exitUnavailable},
+
+		"Usage_error_on_debug_argument":              {args: []string{"debug", "enable", "unexpected"}, want: exitUsageError},
+		"Unavailable_on_debug_enable_without_daemon": {args: []string{"--socket", noSocket, "debug", "enable"}, want: exitUnavailable},
+
+		"Usage_error_on_doctor_argument": {args: []string{"doctor", "unexpected"}, want: exitUsageError},
+		"Error_when_doctor_checks_fail": {
+			args: []string{"--socket", noSocket, "doctor", "--brokers-dir", t.TempDir(), "--db-dir", t.TempDir()},
+			want: exitError,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := newRootCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			got := exitOK
+			if err != nil {
+				got = exitCode(err, !cmd.SilenceUsage)
+			}
+			require.Equal(t, tc.want, got, "Command returned an unexpected exit code: %v", err)
+		})
+	}
+}
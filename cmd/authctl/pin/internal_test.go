@@ -0,0 +1,110 @@
+package pin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+func TestReadPIN(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input string
+
+		want    string
+		wantErr bool
+	}{
+		"Read_PIN_without_newline":            {input: "123456", want: "123456"},
+		"Read_PIN_stripping_trailing_newline": {input: "123456\n", want: "123456"},
+		"Read_PIN_stripping_trailing_CRLF":    {input: "123456\r\n", want: "123456"},
+		"Read_only_the_first_line":            {input: "123456\n654321\n", want: "123456"},
+
+		"Error_when_input_is_empty":      {input: "", wantErr: true},
+		"Error_when_first_line_is_empty": {input: "\n123456\n", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := readPIN(strings.NewReader(tc.input))
+			if tc.wantErr {
+				require.Error(t, err, "readPIN should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "readPIN should not return an error, but did")
+			require.Equal(t, tc.want, got, "readPIN returned an unexpected PIN")
+		})
+	}
+}
+
+func TestPrintStatus(t *testing.T) {
+	t.Parallel()
+
+	resp := &authd.GLPSResponse{Registered: true, Usable: true, FailedAttempts: 2}
+
+	tests := map[string]struct {
+		service string
+		resp    *authd.GLPSResponse
+		format  printer.Format
+
+		want string
+	}{
+		"Print_status_as_table": {
+			resp: resp,
+			want: "USER   REGISTERED  FAILED ATTEMPTS\nuser1  true        2\n",
+		},
+		"Print_status_for_service_as_table": {
+			service: "sudo",
+			resp:    resp,
+			want:    "USER   REGISTERED  FAILED ATTEMPTS  SERVICE  USABLE\nuser1  true        2                sudo     true\n",
+		},
+		"Print_unregistered_status_as_table": {
+			resp: &authd.GLPSResponse{},
+			want: "USER   REGISTERED  FAILED ATTEMPTS\nuser1  false       0\n",
+		},
+		"Print_status_as_JSON": {
+			service: "sudo",
+			resp:    resp,
+			format:  printer.JSON,
+			want: strings.Join([]string{
+				`{`,
+				`  "user": "user1",`,
+				`  "registered": true,`,
+				`  "failed_attempts": 2,`,
+				`  "service": "sudo",`,
+				`  "usable": true`,
+				`}`,
+				``,
+			}, "\n"),
+		},
+		"Print_status_as_YAML": {
+			resp:   resp,
+			format: printer.YAML,
+			want: strings.Join([]string{
+				`user: user1`,
+				`registered: true`,
+				`failed_attempts: 2`,
+				`usable: true`,
+				``,
+			}, "\n"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.format == "" {
+				tc.format = printer.Table
+			}
+
+			var out strings.Builder
+			err := printer.Print(&out, tc.format, newStatus("user1", tc.service, tc.resp))
+			require.NoError(t, err, "Print should not return an error, but did")
+			require.Equal(t, tc.want, out.String(), "Print returned an unexpected output")
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package pin implements the authctl commands handling the machine-local PINs of the users.
+package pin
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// NewCmd returns the pin command, connecting to the daemon through the given socket path and printing the results
+// in the given output format.
+func NewCmd(socketPath *string, output *printer.Format) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pin COMMAND",
+		Short: "Manage the local PINs of the users",
+		Long: `Manage the machine-local PINs of the users.
+
+Shortly after logging in with their broker, the users can register their
+local PIN, accepted by the PAM services allowed by the daemon configuration
+without reaching the identity provider. After too many failed attempts, the PIN is
+locked until the user logs in again with their broker.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set USERNAME",
+		Short: "Set your own local PIN, read from the standard input",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pin, err := readPIN(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			_, err = c.SetLocalPIN(cmd.Context(), &authd.SLPRequest{Username: args[0], Pin: pin})
+			return err
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove USERNAME",
+		Short: "Remove the local PIN of a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			_, err = c.RemoveLocalPIN(cmd.Context(), &authd.RLPRequest{Username: args[0]})
+			return err
+		},
+	})
+
+	var service string
+	statusCmd := &cobra.Command{
+		Use:   "status USERNAME",
+		Short: "Show whether a user registered a local PIN",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			resp, err := c.GetLocalPINStatus(cmd.Context(), &authd.GLPSRequest{Username: args[0], Service: service})
+			if err != nil {
+				return err
+			}
+			return printer.Print(cmd.OutOrStdout(), *output, newStatus(args[0], service, resp))
+		},
+	}
+	statusCmd.Flags().StringVarP(&service, "service", "s", "", "the PAM service to check whether the local PIN is usable for")
+	cmd.AddCommand(statusCmd)
+
+	return cmd
+}
+
+// readPIN returns the first line of r, which must not be empty.
+func readPIN(r io.Reader) (string, error) {
+	pin, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("could not read PIN: %v", err)
+	}
+	pin = strings.TrimRight(pin, "\r\n")
+	if pin == "" {
+		return "", authderrors.New(authderrors.InvalidArgument, "no PIN provided on the standard input")
+	}
+	return pin, nil
+}
+
+// status is the local PIN status printed by the status command.
+type status struct {
+	User           string `json:"user" yaml:"user"`
+	Registered     bool   `json:"registered" yaml:"registered"`
+	FailedAttempts uint32 `json:"failed_attempts" yaml:"failed_attempts"`
+	Service        string `json:"service,omitempty" yaml:"service,omitempty"`
+	Usable         bool   `json:"usable" yaml:"usable"`
+}
+
+func newStatus(username, service string, resp *authd.GLPSResponse) status {
+	return status{
+		User:           username,
+		Registered:     resp.GetRegistered(),
+		FailedAttempts: resp.GetFailedAttempts(),
+		Service:        service,
+		Usable:         resp.GetUsable(),
+	}
+}
+
+// Header returns the header of the status table.
+func (s status) Header() []string {
+	if s.Service == "" {
+		return []string{"USER", "REGISTERED", "FAILED ATTEMPTS"}
+	}
+	return []string{"USER", "REGISTERED", "FAILED ATTEMPTS", "SERVICE", "USABLE"}
+}
+
+// Rows returns the status table row.
+func (s status) Rows() [][]string {
+	row := []string{s.User, strconv.FormatBool(s.Registered), strconv.FormatUint(uint64(s.FailedAttempts), 10)}
+	if s.Service != "" {
+		row = append(row, s.Service, strconv.FormatBool(s.Usable))
+	}
+	return [][]string{row}
+}
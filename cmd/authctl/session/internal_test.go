@@ -0,0 +1,90 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+func TestPrintSessions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	sessions := []*authd.LSResponse_SessionInfo{
+		{SessionId: "id1", Username: "user1", BrokerId: "broker", Stage: "broker-selected", StartTime: 990},
+		{SessionId: "id2", Username: "longer-user", BrokerId: "broker", Stage: "authenticating", StartTime: 820},
+	}
+
+	tests := map[string]struct {
+		sessions []*authd.LSResponse_SessionInfo
+		format   printer.Format
+
+		want string
+	}{
+		"Print_only_header_without_sessions": {
+			want: "SESSION ID  USER  BROKER  AGE  STAGE\n",
+		},
+		"Print_sessions_with_their_age": {
+			sessions: sessions,
+			want: strings.Join([]string{
+				"SESSION ID  USER         BROKER  AGE   STAGE",
+				"id1         user1        broker  10s   broker-selected",
+				"id2         longer-user  broker  3m0s  authenticating",
+				"",
+			}, "\n"),
+		},
+		"Print_sessions_as_JSON": {
+			sessions: sessions[:1],
+			format:   printer.JSON,
+			want: strings.Join([]string{
+				`{`,
+				`  "sessions": [`,
+				`    {`,
+				`      "id": "id1",`,
+				`      "user": "user1",`,
+				`      "broker": "broker",`,
+				`      "stage": "broker-selected",`,
+				`      "start_time": "1970-01-01T00:16:30Z"`,
+				`    }`,
+				`  ]`,
+				`}`,
+				``,
+			}, "\n"),
+		},
+		"Print_no_sessions_as_JSON": {
+			format: printer.JSON,
+			want:   "{\n  \"sessions\": []\n}\n",
+		},
+		"Print_sessions_as_YAML": {
+			sessions: sessions[:1],
+			format:   printer.YAML,
+			want: strings.Join([]string{
+				`sessions:`,
+				`  - id: id1`,
+				`    user: user1`,
+				`    broker: broker`,
+				`    stage: broker-selected`,
+				`    start_time: 1970-01-01T00:16:30Z`,
+				``,
+			}, "\n"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.format == "" {
+				tc.format = printer.Table
+			}
+
+			var out strings.Builder
+			err := printer.Print(&out, tc.format, newSessionList(tc.sessions, now))
+			require.NoError(t, err, "Print should not return an error, but did")
+			require.Equal(t, tc.want, out.String(), "Print returned an unexpected output")
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Package session implements the authctl commands handling the authd ongoing sessions.
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// NewCmd returns the session command, connecting to the daemon through the given socket path and printing the
+// results in the given output format.
+func NewCmd(socketPath *string, output *printer.Format) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "session COMMAND",
+		Short: "Manage the ongoing authentication sessions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the ongoing authentication sessions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			resp, err := c.ListSessions(cmd.Context(), &authd.Empty{})
+			if err != nil {
+				return err
+			}
+			return printer.Print(cmd.OutOrStdout(), *output, newSessionList(resp.GetSessions(), time.Now()))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "kill SESSION_ID",
+		Short: "Abort an ongoing authentication session",
+		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeSessionIDs(cmd, *socketPath)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			_, err = c.AbortSession(cmd.Context(), &authd.ASRequest{SessionId: args[0]})
+			return err
+		},
+	})
+
+	return cmd
+}
+
+// completeSessionIDs returns the IDs of the ongoing sessions, for shell completion.
+func completeSessionIDs(cmd *cobra.Command, socketPath string) ([]string, cobra.ShellCompDirective) {
+	c, closeConn, err := client.NewPAM(socketPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer closeConn()
+
+	resp, err := c.ListSessions(cmd.Context(), &authd.Empty{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var ids []string
+	for _, s := range resp.GetSessions() {
+		ids = append(ids, fmt.Sprintf("%s\t%s (%s)", s.GetSessionId(), s.GetUsername(), s.GetStage()))
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// sessionList is the list of sessions printed by the list command.
+type sessionList struct {
+	Sessions []sessionEntry `json:"sessions" yaml:"sessions"`
+
+	// now is the time the sessions age is computed from.
+	now time.Time
+}
+
+// sessionEntry is a session printed by the list command.
+type sessionEntry struct {
+	ID        string    `json:"id" yaml:"id"`
+	User      string    `json:"user" yaml:"user"`
+	Broker    string    `json:"broker" yaml:"broker"`
+	Stage     string    `json:"stage" yaml:"stage"`
+	StartTime time.Time `json:"start_time" yaml:"start_time"`
+}
+
+func newSessionList(sessions []*authd.LSResponse_SessionInfo, now time.Time) sessionList {
+	l := sessionList{Sessions: []sessionEntry{}, now: now}
+	for _, s := range sessions {
+		l.Sessions = append(l.Sessions, sessionEntry{
+			ID:        s.GetSessionId(),
+			User:      s.GetUsername(),
+			Broker:    s.GetBrokerId(),
+			Stage:     s.GetStage(),
+			StartTime: time.Unix(s.GetStartTime(), 0).UTC(),
+		})
+	}
+	return l
+}
+
+// Header returns the header of the sessions table.
+func (l sessionList) Header() []string {
+	return []string{"SESSION ID", "USER", "BROKER", "AGE", "STAGE"}
+}
+
+// Rows returns the sessions table rows, with the sessions age.
+func (l sessionList) Rows() (rows [][]string) {
+	for _, s := range l.Sessions {
+		age := l.now.Sub(s.StartTime).Truncate(time.Second)
+		rows = append(rows, []string{s.ID, s.User, s.Broker, age.String(), s.Stage})
+	}
+	return rows
+}
@@ -0,0 +1,75 @@
+package uid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+func TestPrintIDOwners(t *testing.T) {
+	t.Parallel()
+
+	user := &authd.WIDResponse_Owner{Name: "user1", Source: "user"}
+	group := &authd.WIDResponse_Owner{Name: "user1", Source: "group"}
+	deletedUser := &authd.WIDResponse_Owner{Name: "olduser", Source: "deleted-user", DeletedAt: 1700000000}
+
+	tests := map[string]struct {
+		resp   *authd.WIDResponse
+		format printer.Format
+
+		want string
+	}{
+		"Print_user_of_ID": {
+			resp: &authd.WIDResponse{Owners: []*authd.WIDResponse_Owner{user}},
+			want: "ID    NAME   SOURCE  DELETED\n1111  user1  user    -\n",
+		},
+		"Print_deleted_user_of_ID": {
+			resp: &authd.WIDResponse{Owners: []*authd.WIDResponse_Owner{deletedUser}},
+			want: "ID    NAME     SOURCE        DELETED\n1111  olduser  deleted-user  2023-11-14 22:13:20\n",
+		},
+		"Print_all_owners_of_ID": {
+			resp: &authd.WIDResponse{Owners: []*authd.WIDResponse_Owner{user, deletedUser, group}},
+			want: strings.Join([]string{
+				"ID    NAME     SOURCE        DELETED",
+				"1111  user1    user          -",
+				"1111  olduser  deleted-user  2023-11-14 22:13:20",
+				"1111  user1    group         -",
+				"",
+			}, "\n"),
+		},
+		"Print_owners_of_ID_as_JSON": {
+			resp:   &authd.WIDResponse{Owners: []*authd.WIDResponse_Owner{user}},
+			format: printer.JSON,
+			want:   "{\n  \"id\": 1111,\n  \"owners\": [\n    {\n      \"name\": \"user1\",\n      \"source\": \"user\"\n    }\n  ]\n}\n",
+		},
+		"Print_owners_of_ID_as_YAML": {
+			resp:   &authd.WIDResponse{Owners: []*authd.WIDResponse_Owner{deletedUser}},
+			format: printer.YAML,
+			want: strings.Join([]string{
+				`id: 1111`,
+				`owners:`,
+				`  - name: olduser`,
+				`    source: deleted-user`,
+				`    deleted_at: 2023-11-14T22:13:20Z`,
+				``,
+			}, "\n"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.format == "" {
+				tc.format = printer.Table
+			}
+
+			var out strings.Builder
+			err := printer.Print(&out, tc.format, newIDOwners(1111, tc.resp))
+			require.NoError(t, err, "Print should not return an error, but did")
+			require.Equal(t, tc.want, out.String(), "Print returned an unexpected output")
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Package uid implements the authctl commands about the UIDs and GIDs of the users and groups.
+package uid
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// NewCmd returns the uid command, connecting to the daemon through the given socket path and printing the results in
+// the given output format.
+func NewCmd(socketPath *string, output *printer.Format) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "uid COMMAND",
+		Short: "Inspect the UIDs and GIDs of the users and groups",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "whois ID",
+		Short: "Print the users and groups an ID belongs to",
+		Long: `Print the users and groups a UID or GID belongs to, to debug the collisions
+of IDs.
+
+The owners are looked up in the users and groups of authd, the users deleted
+from authd, the temporary users and groups being registered, and the local
+passwd and group files. The UIDs of the deleted users are never given to new
+users, so that the files they still own can be attributed with this command.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 32)
+			if err != nil {
+				return authderrors.Errorf(authderrors.InvalidArgument, "invalid ID %q", args[0])
+			}
+
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			resp, err := c.WhoisID(cmd.Context(), &authd.WIDRequest{Id: uint32(id)})
+			if err != nil {
+				return err
+			}
+			return printer.Print(cmd.OutOrStdout(), *output, newIDOwners(uint32(id), resp))
+		},
+	})
+
+	return cmd
+}
+
+// idOwners are the owners of an ID printed by the whois command.
+type idOwners struct {
+	ID     uint32    `json:"id" yaml:"id"`
+	Owners []idOwner `json:"owners" yaml:"owners"`
+}
+
+// idOwner is a user or group an ID belongs to.
+type idOwner struct {
+	Name      string     `json:"name" yaml:"name"`
+	Source    string     `json:"source" yaml:"source"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" yaml:"deleted_at,omitempty"`
+}
+
+func newIDOwners(id uint32, resp *authd.WIDResponse) idOwners {
+	o := idOwners{ID: id, Owners: []idOwner{}}
+	for _, owner := range resp.GetOwners() {
+		idOwner := idOwner{Name: owner.GetName(), Source: owner.GetSource()}
+		if owner.GetDeletedAt() != 0 {
+			deletedAt := time.Unix(owner.GetDeletedAt(), 0).UTC()
+			idOwner.DeletedAt = &deletedAt
+		}
+		o.Owners = append(o.Owners, idOwner)
+	}
+	return o
+}
+
+// Header returns the header of the ID owners table.
+func (o idOwners) Header() []string {
+	return []string{"ID", "NAME", "SOURCE", "DELETED"}
+}
+
+// Rows returns a row for each owner of the ID, with the deletion date if the user was deleted.
+func (o idOwners) Rows() [][]string {
+	var rows [][]string
+	for _, owner := range o.Owners {
+		deleted := "-"
+		if owner.DeletedAt != nil {
+			deleted = owner.DeletedAt.Format(time.DateTime)
+		}
+		rows = append(rows, []string{fmt.Sprint(o.ID), owner.Name, owner.Source, deleted})
+	}
+	return rows
+}
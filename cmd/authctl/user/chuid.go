@@ -0,0 +1,125 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// chownProgressInterval is the number of files whose ownership is changed between two progress reports.
+const chownProgressInterval = 1000
+
+// newChuidCmd returns the chuid command, connecting to the daemon through the given socket path.
+func newChuidCmd(socketPath *string) *cobra.Command {
+	var from, to uint32
+	var chownPaths []string
+
+	cmd := &cobra.Command{
+		Use:   "chuid --from UID --to UID [--chown PATH]...",
+		Short: "Change the UID of a user",
+		Long: `Change the UID of a user who already logged in with their broker, for example
+when enabling deterministic IDs on an existing machine.
+
+The ownership of the files in the given paths which are owned by the previous
+UID is changed to the new one. The previous UID is never given to another user,
+so that the files not migrated can still be attributed with 'authctl uid whois'.
+
+The command can be run again with the same arguments to resume an interrupted
+migration: only the files still owned by the previous UID are changed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			resp, err := c.ChangeUID(cmd.Context(), &authd.CUIDRequest{OldUid: from, NewUid: to})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "UID of user %q changed from %d to %d\n", resp.GetUsername(), from, to)
+
+			for _, path := range chownPaths {
+				if err := cmd.Context().Err(); err != nil {
+					return err
+				}
+				n, err := chownTree(path, from, to, cmd.ErrOrStderr())
+				if err != nil {
+					return authderrors.Errorf(authderrors.Internal, "could not change the ownership of the files in %q after %d of them, run the command again to resume: %v", path, n, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Changed the ownership of %d files in %s\n", n, path)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Uint32Var(&from, "from", 0, "the current UID of the user")
+	cmd.Flags().Uint32Var(&to, "to", 0, "the new UID of the user")
+	cmd.Flags().StringArrayVar(&chownPaths, "chown", nil, "a path in which to change the ownership of the files owned by the current UID")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+// chownTree changes the owner of the files in the tree rooted at root owned by the UID from to the UID to, keeping
+// their group and mode, and returns the number of changed files. Progress is reported to the writer.
+//
+// The symbolic links are changed but not followed, and the other file systems are not crossed.
+func chownTree(root string, from, to uint32, progress io.Writer) (n int, err error) {
+	rootInfo, err := os.Lstat(root)
+	if err != nil {
+		return 0, err
+	}
+	rootDev := rootInfo.Sys().(*syscall.Stat_t).Dev
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		fi, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// The file was removed while walking the tree.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		st := fi.Sys().(*syscall.Stat_t)
+		if st.Dev != rootDev {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if st.Uid != from {
+			return nil
+		}
+
+		if err := os.Lchown(path, int(to), -1); err != nil {
+			return err
+		}
+		// Changing the owner clears the setuid and setgid bits, which are restored.
+		if fi.Mode()&(fs.ModeSetuid|fs.ModeSetgid) != 0 && fi.Mode()&fs.ModeSymlink == 0 {
+			if err := os.Chmod(path, fi.Mode()); err != nil {
+				return err
+			}
+		}
+
+		n++
+		if n%chownProgressInterval == 0 {
+			fmt.Fprintf(progress, "Changed the ownership of %d files in %s\n", n, root)
+		}
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,249 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// copyProgressInterval is the number of files copied between two progress reports.
+const copyProgressInterval = 1000
+
+// newSetHomeCmd returns the set-home command, connecting to the daemon through the given socket path.
+func newSetHomeCmd(socketPath *string) *cobra.Command {
+	var move bool
+
+	cmd := &cobra.Command{
+		Use:   "set-home USERNAME HOME",
+		Short: "Change the home directory of a user, like usermod --home",
+		Long: `Change the home directory of a user who already logged in with their broker.
+It takes precedence over the one provided by the broker on the next logins of
+the user.
+
+With --move, the content of the previous home directory is moved to the new
+one first, keeping the ownership, modes and modification times of the files.
+The home directory is only changed once all the files are copied, so the
+command can be run again with the same arguments to resume an interrupted
+move: the files already copied are skipped. The hard links are not kept.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			username, home := args[0], args[1]
+
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			resp, err := c.SetUserHome(cmd.Context(), &authd.SUHRequest{Username: username, Home: home, ValidateOnly: move})
+			if err != nil {
+				return err
+			}
+			if !move {
+				return nil
+			}
+
+			previous := resp.GetPreviousHome()
+			home = filepath.Clean(home)
+			if previous != home {
+				n, err := moveTree(previous, home, cmd.ErrOrStderr())
+				if err != nil {
+					return authderrors.Errorf(authderrors.Internal, "could not move the content of %q after %d files, run the command again to resume: %v", previous, n, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Moved %d files from %s to %s\n", n, previous, home)
+			}
+
+			_, err = c.SetUserHome(cmd.Context(), &authd.SUHRequest{Username: username, Home: home})
+			return err
+		},
+	}
+	cmd.Flags().BoolVarP(&move, "move", "m", false, "move the content of the previous home directory to the new one")
+
+	return cmd
+}
+
+// moveTree moves the tree rooted at src to dst and returns the number of moved files. Progress is reported to the
+// writer.
+//
+// The tree is renamed if dst doesn't exist and is on the same file system. Otherwise, it's copied, keeping the owners,
+// modes and modification times, then removed. The files already copied with the same size and modification time are
+// skipped, so that an interrupted move can be resumed.
+func moveTree(src, dst string, progress io.Writer) (n int, err error) {
+	if _, err := os.Lstat(src); errors.Is(err, fs.ErrNotExist) {
+		// There is nothing to move.
+		return 0, nil
+	}
+	if isWithin(dst, src) || isWithin(src, dst) {
+		return 0, fmt.Errorf("%q and %q overlap", dst, src)
+	}
+
+	if _, err := os.Lstat(dst); errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return 0, err
+		}
+		err := os.Rename(src, dst)
+		if err == nil {
+			return countFiles(dst)
+		}
+		if !errors.Is(err, syscall.EXDEV) {
+			return 0, err
+		}
+	}
+
+	n, err = copyTree(src, dst, progress)
+	if err != nil {
+		return n, err
+	}
+	return n, os.RemoveAll(src)
+}
+
+// isWithin returns whether path is root or inside it. It's considered so if they can't be compared.
+func isWithin(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return true
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// copyTree copies the tree rooted at src to dst, skipping the regular files already copied, and returns the number of
+// copied files. The special files, like sockets and devices, are not copied.
+func copyTree(src, dst string, progress io.Writer) (n int, err error) {
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case fi.IsDir():
+			err = copyDir(fi, target)
+		case fi.Mode()&fs.ModeSymlink != 0:
+			err = copySymlink(path, fi, target)
+		case fi.Mode().IsRegular():
+			err = copyFile(path, fi, target)
+		default:
+			fmt.Fprintf(progress, "Skipping special file %s\n", path)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		n++
+		if n%copyProgressInterval == 0 {
+			fmt.Fprintf(progress, "Copied %d files to %s\n", n, dst)
+		}
+		return nil
+	})
+	return n, err
+}
+
+// copyDir creates the directory target with the mode and owner of the directory described by fi.
+func copyDir(fi fs.FileInfo, target string) error {
+	if err := os.MkdirAll(target, 0700); err != nil {
+		return err
+	}
+	return setAttributes(target, fi)
+}
+
+// copySymlink creates the symbolic link target pointing to the same destination as the one at path, without following
+// it.
+func copySymlink(path string, fi fs.FileInfo, target string) error {
+	dest, err := os.Readlink(path)
+	if err != nil {
+		return err
+	}
+	if existing, err := os.Readlink(target); err == nil && existing == dest {
+		return nil
+	}
+	if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+	if err := os.Symlink(dest, target); err != nil {
+		return err
+	}
+	st := fi.Sys().(*syscall.Stat_t)
+	return os.Lchown(target, int(st.Uid), int(st.Gid))
+}
+
+// copyFile copies the regular file at path to target, unless it was already copied with the same size and
+// modification time. The file is written to a temporary file first, so that an interrupted copy is never skipped.
+func copyFile(path string, fi fs.FileInfo, target string) (err error) {
+	if existing, err := os.Lstat(target); err == nil && existing.Mode().IsRegular() &&
+		existing.Size() == fi.Size() && existing.ModTime().Equal(fi.ModTime()) {
+		return nil
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(out.Name())
+		}
+	}()
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := setAttributes(out.Name(), fi); err != nil {
+		return err
+	}
+	return os.Rename(out.Name(), target)
+}
+
+// setAttributes sets the owner, mode and modification time of the file described by fi to the file at path.
+func setAttributes(path string, fi fs.FileInfo) error {
+	st := fi.Sys().(*syscall.Stat_t)
+	if err := os.Lchown(path, int(st.Uid), int(st.Gid)); err != nil {
+		return err
+	}
+	// Changing the owner clears the setuid and setgid bits, so the mode is set afterwards.
+	if err := os.Chmod(path, fi.Mode()); err != nil {
+		return err
+	}
+	return os.Chtimes(path, fi.ModTime(), fi.ModTime())
+}
+
+// countFiles returns the number of files in the tree rooted at root.
+func countFiles(root string) (n int, err error) {
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,347 @@
+package user
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+func TestNewSUGRequest(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(s string) *string { return &s }
+
+	tests := map[string]struct {
+		args []string
+
+		wantFullName  *string
+		wantRoom      *string
+		wantWorkPhone *string
+		wantHomePhone *string
+		wantErr       bool
+	}{
+		"Only_set_given_fields":        {args: []string{"--full-name", "Jane Doe", "-r", "42"}, wantFullName: ptr("Jane Doe"), wantRoom: ptr("42")},
+		"Set_phones":                   {args: []string{"-w", "555-0100", "--home-phone=555-0199"}, wantWorkPhone: ptr("555-0100"), wantHomePhone: ptr("555-0199")},
+		"Clear_field_given_as_empty":   {args: []string{"--room", ""}, wantRoom: ptr("")},
+		"Error_when_no_field_is_given": {wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, _, err := NewCmd(new(string), new(printer.Format)).Find([]string{"gecos"})
+			require.NoError(t, err, "Setup: could not find gecos command")
+			require.NoError(t, cmd.ParseFlags(tc.args), "Setup: could not parse flags")
+
+			req, err := newSUGRequest(cmd, "user1")
+			if tc.wantErr {
+				require.Error(t, err, "newSUGRequest should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "newSUGRequest should not return an error, but did")
+			require.Equal(t, "user1", req.GetUsername(), "newSUGRequest returned an unexpected username")
+			require.Equal(t, tc.wantFullName, req.FullName, "newSUGRequest returned an unexpected full name")
+			require.Equal(t, tc.wantRoom, req.Room, "newSUGRequest returned an unexpected room")
+			require.Equal(t, tc.wantWorkPhone, req.WorkPhone, "newSUGRequest returned an unexpected work phone")
+			require.Equal(t, tc.wantHomePhone, req.HomePhone, "newSUGRequest returned an unexpected home phone")
+		})
+	}
+}
+
+func TestNewSUORequest(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(s string) *string { return &s }
+
+	tests := map[string]struct {
+		args []string
+
+		wantShell  *string
+		wantHome   *string
+		wantGecos  *string
+		wantGroups []string
+		wantErr    bool
+	}{
+		"Only_set_given_attributes": {args: []string{"--shell", "/bin/zsh", "--home=/srv/user1"}, wantShell: ptr("/bin/zsh"), wantHome: ptr("/srv/user1")},
+		"Set_empty_GECOS":           {args: []string{"--gecos", ""}, wantGecos: ptr("")},
+		"Add_local_groups":          {args: []string{"--add-group", "sudo,docker", "--add-group", "adm"}, wantGroups: []string{"sudo", "docker", "adm"}},
+
+		"Error_when_no_attribute_is_given": {wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, _, err := NewCmd(new(string), new(printer.Format)).Find([]string{"override", "set"})
+			require.NoError(t, err, "Setup: could not find override set command")
+			require.NoError(t, cmd.ParseFlags(tc.args), "Setup: could not parse flags")
+
+			req, err := newSUORequest(cmd, "user1")
+			if tc.wantErr {
+				require.Error(t, err, "newSUORequest should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "newSUORequest should not return an error, but did")
+			require.Equal(t, "user1", req.GetUsername(), "newSUORequest returned an unexpected username")
+			require.Equal(t, tc.wantShell, req.Shell, "newSUORequest returned an unexpected shell")
+			require.Equal(t, tc.wantHome, req.Home, "newSUORequest returned an unexpected home")
+			require.Equal(t, tc.wantGecos, req.Gecos, "newSUORequest returned an unexpected GECOS")
+			require.Equal(t, tc.wantGroups, req.AddLocalGroups, "newSUORequest returned unexpected local groups")
+		})
+	}
+}
+
+func TestPrintOverrides(t *testing.T) {
+	t.Parallel()
+
+	overrides := []*authd.UserOverride{
+		{Username: "user1", Shell: "/bin/zsh", LocalGroups: []string{"docker", "sudo"}},
+		{Username: "user2", Home: "/srv/user2", Gecos: "User Two"},
+	}
+
+	tests := map[string]struct {
+		overrides []*authd.UserOverride
+		format    printer.Format
+
+		want string
+	}{
+		"Print_overrides_as_table": {
+			overrides: overrides,
+			want: strings.Join([]string{
+				"USER   SHELL     HOME        GECOS     LOCAL GROUPS",
+				"user1  /bin/zsh                        docker,sudo",
+				"user2            /srv/user2  User Two  ",
+				"",
+			}, "\n"),
+		},
+		"Print_no_overrides_as_JSON": {
+			format: printer.JSON,
+			want:   "{\n  \"overrides\": []\n}\n",
+		},
+		"Print_overrides_as_YAML": {
+			overrides: overrides[:1],
+			format:    printer.YAML,
+			want: strings.Join([]string{
+				`overrides:`,
+				`  - user: user1`,
+				`    shell: /bin/zsh`,
+				`    local_groups:`,
+				`      - docker`,
+				`      - sudo`,
+				``,
+			}, "\n"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.format == "" {
+				tc.format = printer.Table
+			}
+
+			var out strings.Builder
+			err := printer.Print(&out, tc.format, newOverrideList(tc.overrides))
+			require.NoError(t, err, "Print should not return an error, but did")
+			require.Equal(t, tc.want, out.String(), "Print returned an unexpected output")
+		})
+	}
+}
+
+func TestPrintUserStatus(t *testing.T) {
+	t.Parallel()
+
+	failures := &authd.GFAResponse{
+		Sources: []*authd.GFAResponse_Source{
+			{Service: "gdm-authd", FailedAttempts: 2},
+			{Service: "sshd", FailedAttempts: 3},
+		},
+		LastFailure:  1700000000,
+		BackoffUntil: 1700000060,
+	}
+
+	tests := map[string]struct {
+		resp   *authd.GFAResponse
+		format printer.Format
+
+		want string
+	}{
+		"Print_failures_as_table": {
+			resp: failures,
+			want: strings.Join([]string{
+				"USER   SERVICE    FAILED ATTEMPTS  LAST FAILURE         BACKED OFF UNTIL",
+				"user1  gdm-authd  2                2023-11-14 22:13:20  2023-11-14 22:14:20",
+				"user1  sshd       3                2023-11-14 22:13:20  2023-11-14 22:14:20",
+				"",
+			}, "\n"),
+		},
+		"Print_no_failures_as_table": {
+			resp: &authd.GFAResponse{},
+			want: strings.Join([]string{
+				"USER   SERVICE  FAILED ATTEMPTS  LAST FAILURE  BACKED OFF UNTIL",
+				"user1  -        0                -             -",
+				"",
+			}, "\n"),
+		},
+		"Print_no_failures_as_JSON": {
+			resp:   &authd.GFAResponse{},
+			format: printer.JSON,
+			want:   "{\n  \"user\": \"user1\",\n  \"failures\": []\n}\n",
+		},
+		"Print_failures_as_YAML": {
+			resp:   &authd.GFAResponse{Sources: failures.Sources[1:], LastFailure: failures.LastFailure},
+			format: printer.YAML,
+			want: strings.Join([]string{
+				`user: user1`,
+				`failures:`,
+				`  - service: sshd`,
+				`    failed_attempts: 3`,
+				`last_failure: 2023-11-14T22:13:20Z`,
+				``,
+			}, "\n"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.format == "" {
+				tc.format = printer.Table
+			}
+
+			var out strings.Builder
+			err := printer.Print(&out, tc.format, newUserStatus("user1", tc.resp))
+			require.NoError(t, err, "Print should not return an error, but did")
+			require.Equal(t, tc.want, out.String(), "Print returned an unexpected output")
+		})
+	}
+}
+
+func TestChownTree(t *testing.T) {
+	t.Parallel()
+
+	uid := uint32(os.Getuid())
+
+	tests := map[string]struct {
+		from uint32
+
+		wantCount int
+	}{
+		"Change_files_owned_by_UID":         {from: uid, wantCount: 6},
+		"Keep_files_not_owned_by_UID_as_is": {from: uid + 1, wantCount: 0},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(root, "dir", "subdir"), 0700), "Setup: could not create directories")
+			require.NoError(t, os.WriteFile(filepath.Join(root, "dir", "file"), nil, 0600), "Setup: could not create file")
+			require.NoError(t, os.WriteFile(filepath.Join(root, "setuid"), nil, 0700), "Setup: could not create file")
+			require.NoError(t, os.Chmod(filepath.Join(root, "setuid"), 0700|os.ModeSetuid), "Setup: could not set setuid bit")
+			require.NoError(t, os.Symlink("dir/file", filepath.Join(root, "link")), "Setup: could not create symlink")
+
+			// The ownership is changed to the same UID, as changing it to another one requires to be root.
+			got, err := chownTree(root, tc.from, uid, io.Discard)
+			require.NoError(t, err, "chownTree should not return an error, but did")
+			// The root, the 2 directories, the 2 files and the symlink, which is not followed.
+			require.Equal(t, tc.wantCount, got, "chownTree changed an unexpected number of files")
+
+			fi, err := os.Stat(filepath.Join(root, "setuid"))
+			require.NoError(t, err, "Stat should not return an error, but did")
+			require.NotZero(t, fi.Mode()&os.ModeSetuid, "chownTree should keep the setuid bit")
+			require.Equal(t, uid, fi.Sys().(*syscall.Stat_t).Uid, "chownTree should set the new owner")
+		})
+	}
+}
+
+func TestMoveTree(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		noSrc        bool
+		dstExists    bool
+		alreadyMoved bool
+		// dstFromSrc is the destination relative to the source, if it overlaps with it.
+		dstFromSrc string
+
+		wantCount int
+		wantErr   bool
+	}{
+		"Rename_tree_when_destination_does_not_exist": {wantCount: 6},
+		"Copy_tree_into_existing_destination":         {dstExists: true, wantCount: 6},
+		"Skip_files_already_copied":                   {dstExists: true, alreadyMoved: true, wantCount: 6},
+		"Do_nothing_when_source_does_not_exist":       {noSrc: true},
+
+		"Error_when_destination_is_inside_source":      {dstFromSrc: "new-home", wantErr: true},
+		"Error_when_destination_is_the_source":         {dstFromSrc: ".", wantErr: true},
+		"Error_when_destination_is_parent_of_source":   {dstFromSrc: "..", wantErr: true},
+		"Error_when_destination_is_ancestor_of_source": {dstFromSrc: "../..", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			src := filepath.Join(t.TempDir(), "home", "user1")
+			dst := filepath.Join(t.TempDir(), "srv", "user1")
+			if tc.dstFromSrc != "" {
+				dst = filepath.Join(src, tc.dstFromSrc)
+			}
+			mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+			if !tc.noSrc {
+				require.NoError(t, os.MkdirAll(filepath.Join(src, "dir", "subdir"), 0700), "Setup: could not create directories")
+				require.NoError(t, os.WriteFile(filepath.Join(src, "dir", "file"), []byte("content"), 0600), "Setup: could not create file")
+				require.NoError(t, os.Chtimes(filepath.Join(src, "dir", "file"), mtime, mtime), "Setup: could not set modification time")
+				require.NoError(t, os.WriteFile(filepath.Join(src, "script"), []byte("#!/bin/sh"), 0750), "Setup: could not create file")
+				require.NoError(t, os.Symlink("dir/file", filepath.Join(src, "link")), "Setup: could not create symlink")
+			}
+			if tc.dstExists {
+				require.NoError(t, os.MkdirAll(filepath.Join(dst, "dir"), 0700), "Setup: could not create destination")
+			}
+			if tc.alreadyMoved {
+				// A file copied by a previous run is skipped: its content is not compared.
+				require.NoError(t, os.WriteFile(filepath.Join(dst, "dir", "file"), []byte("already"), 0600), "Setup: could not create file")
+				require.NoError(t, os.Chtimes(filepath.Join(dst, "dir", "file"), mtime, mtime), "Setup: could not set modification time")
+			}
+
+			got, err := moveTree(src, dst, io.Discard)
+			if tc.wantErr {
+				require.Error(t, err, "moveTree should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "moveTree should not return an error, but did")
+			// The root, the 2 directories, the 2 files and the symlink, which is not followed.
+			require.Equal(t, tc.wantCount, got, "moveTree moved an unexpected number of files")
+			if tc.noSrc {
+				return
+			}
+
+			require.NoDirExists(t, src, "moveTree should remove the source")
+			content, err := os.ReadFile(filepath.Join(dst, "dir", "file"))
+			require.NoError(t, err, "The file should be moved")
+			wantContent := "content"
+			if tc.alreadyMoved {
+				wantContent = "already"
+			}
+			require.Equal(t, wantContent, string(content), "The file should have the expected content")
+
+			fi, err := os.Stat(filepath.Join(dst, "dir", "file"))
+			require.NoError(t, err, "Stat should not return an error, but did")
+			require.True(t, mtime.Equal(fi.ModTime()), "moveTree should keep the modification time")
+			fi, err = os.Stat(filepath.Join(dst, "script"))
+			require.NoError(t, err, "Stat should not return an error, but did")
+			require.Equal(t, os.FileMode(0750), fi.Mode().Perm(), "moveTree should keep the mode")
+			link, err := os.Readlink(filepath.Join(dst, "link"))
+			require.NoError(t, err, "The symlink should be moved")
+			require.Equal(t, "dir/file", link, "The symlink should point to the same destination")
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package user
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// newOverrideCmd returns the override command, connecting to the daemon through the given socket path and printing
+// the results in the given output format.
+func newOverrideCmd(socketPath *string, output *printer.Format) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "override COMMAND",
+		Short: "Manage the attributes of the users set locally",
+		Long: `Manage the attributes of the users set locally by the administrator.
+
+The login shell, home directory and GECOS field set locally take precedence
+over the ones provided by the broker, which never replaces them. The users are
+also added to the local groups set locally on each login.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set USERNAME",
+		Short: "Set attributes of a user locally",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req, err := newSUORequest(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			_, err = c.SetUserOverride(cmd.Context(), req)
+			return err
+		},
+	}
+	setCmd.Flags().String("shell", "", "the login shell of the user, which must be listed in /etc/shells")
+	setCmd.Flags().String("home", "", "the home directory of the user")
+	setCmd.Flags().String("gecos", "", "the GECOS field of the user")
+	setCmd.Flags().StringSlice("add-group", nil, "a local group to always add the user to, on their next login")
+	cmd.AddCommand(setCmd)
+
+	req := &authd.UUORequest{}
+	unsetCmd := &cobra.Command{
+		Use:   "unset USERNAME",
+		Short: "Remove attributes of a user set locally, or all of them if none is given",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.Username = args[0]
+
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			_, err = c.UnsetUserOverride(cmd.Context(), req)
+			return err
+		},
+	}
+	unsetCmd.Flags().BoolVar(&req.Shell, "shell", false, "restore the login shell provided by the broker")
+	unsetCmd.Flags().BoolVar(&req.Home, "home", false, "restore the home directory provided by the broker")
+	unsetCmd.Flags().BoolVar(&req.Gecos, "gecos", false, "restore the GECOS field provided by the broker")
+	unsetCmd.Flags().StringSliceVar(&req.RemoveLocalGroups, "remove-group", nil, "a local group to not add the user to anymore")
+	cmd.AddCommand(unsetCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the attributes of the users set locally",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			resp, err := c.ListUserOverrides(cmd.Context(), &authd.Empty{})
+			if err != nil {
+				return err
+			}
+			return printer.Print(cmd.OutOrStdout(), *output, newOverrideList(resp.GetOverrides()))
+		},
+	})
+
+	return cmd
+}
+
+// newSUORequest returns the request setting the attributes of the user given in the flags of the command, so that
+// the other ones are kept unchanged.
+func newSUORequest(cmd *cobra.Command, username string) (*authd.SUORequest, error) {
+	flags := cmd.Flags()
+	req := &authd.SUORequest{Username: username}
+	for flag, field := range map[string]**string{
+		"shell": &req.Shell,
+		"home":  &req.Home,
+		"gecos": &req.Gecos,
+	} {
+		if !flags.Changed(flag) {
+			continue
+		}
+		v, err := flags.GetString(flag)
+		if err != nil {
+			return nil, err
+		}
+		*field = &v
+	}
+
+	if flags.Changed("add-group") {
+		groups, err := flags.GetStringSlice("add-group")
+		if err != nil {
+			return nil, err
+		}
+		req.AddLocalGroups = groups
+	}
+
+	if req.Shell == nil && req.Home == nil && req.Gecos == nil && len(req.AddLocalGroups) == 0 {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no attribute to set given")
+	}
+	return req, nil
+}
+
+// overrideList is the list of user overrides printed by the list command.
+type overrideList struct {
+	Overrides []overrideEntry `json:"overrides" yaml:"overrides"`
+}
+
+// overrideEntry is a user override printed by the list command.
+type overrideEntry struct {
+	User        string   `json:"user" yaml:"user"`
+	Shell       string   `json:"shell,omitempty" yaml:"shell,omitempty"`
+	Home        string   `json:"home,omitempty" yaml:"home,omitempty"`
+	Gecos       string   `json:"gecos,omitempty" yaml:"gecos,omitempty"`
+	LocalGroups []string `json:"local_groups,omitempty" yaml:"local_groups,omitempty"`
+}
+
+func newOverrideList(overrides []*authd.UserOverride) overrideList {
+	l := overrideList{Overrides: []overrideEntry{}}
+	for _, o := range overrides {
+		l.Overrides = append(l.Overrides, overrideEntry{
+			User:        o.GetUsername(),
+			Shell:       o.GetShell(),
+			Home:        o.GetHome(),
+			Gecos:       o.GetGecos(),
+			LocalGroups: o.GetLocalGroups(),
+		})
+	}
+	return l
+}
+
+// Header returns the header of the overrides table.
+func (l overrideList) Header() []string {
+	return []string{"USER", "SHELL", "HOME", "GECOS", "LOCAL GROUPS"}
+}
+
+// Rows returns the overrides table rows.
+func (l overrideList) Rows() (rows [][]string) {
+	for _, o := range l.Overrides {
+		rows = append(rows, []string{o.User, o.Shell, o.Home, o.Gecos, strings.Join(o.LocalGroups, ",")})
+	}
+	return rows
+}
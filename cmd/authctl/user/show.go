@@ -0,0 +1,95 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// newShowCmd returns the show command, connecting to the daemon through the given socket path and printing the
+// results in the given output format.
+func newShowCmd(socketPath *string, output *printer.Format) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show USERNAME",
+		Short: "Show the failed authentications of a user",
+		Long: `Show the failed authentications of a user since their last successful one,
+by PAM service, and until when their authentications are refused after too
+many failures.
+
+The failed authentications are only kept in memory by the daemon.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			resp, err := c.GetFailedAuthentications(cmd.Context(), &authd.GFARequest{Username: args[0]})
+			if err != nil {
+				return err
+			}
+			return printer.Print(cmd.OutOrStdout(), *output, newUserStatus(args[0], resp))
+		},
+	}
+}
+
+// userStatus is the status of a user printed by the show command.
+type userStatus struct {
+	User         string         `json:"user" yaml:"user"`
+	Failures     []failureEntry `json:"failures" yaml:"failures"`
+	LastFailure  *time.Time     `json:"last_failure,omitempty" yaml:"last_failure,omitempty"`
+	BackoffUntil *time.Time     `json:"backoff_until,omitempty" yaml:"backoff_until,omitempty"`
+}
+
+// failureEntry are the failed authentications of a user through a PAM service.
+type failureEntry struct {
+	Service        string `json:"service" yaml:"service"`
+	FailedAttempts uint32 `json:"failed_attempts" yaml:"failed_attempts"`
+}
+
+func newUserStatus(username string, resp *authd.GFAResponse) userStatus {
+	s := userStatus{User: username, Failures: []failureEntry{}}
+	for _, source := range resp.GetSources() {
+		s.Failures = append(s.Failures, failureEntry{Service: source.GetService(), FailedAttempts: source.GetFailedAttempts()})
+	}
+	if resp.GetLastFailure() != 0 {
+		lastFailure := time.Unix(resp.GetLastFailure(), 0).UTC()
+		s.LastFailure = &lastFailure
+	}
+	if resp.GetBackoffUntil() != 0 {
+		backoffUntil := time.Unix(resp.GetBackoffUntil(), 0).UTC()
+		s.BackoffUntil = &backoffUntil
+	}
+	return s
+}
+
+// Header returns the header of the user status table.
+func (s userStatus) Header() []string {
+	return []string{"USER", "SERVICE", "FAILED ATTEMPTS", "LAST FAILURE", "BACKED OFF UNTIL"}
+}
+
+// Rows returns a row for each PAM service the authentications of the user failed through, with the last failure and
+// the end of the backoff of the user.
+func (s userStatus) Rows() [][]string {
+	lastFailure, backoffUntil := "-", "-"
+	if s.LastFailure != nil {
+		lastFailure = s.LastFailure.Format(time.DateTime)
+	}
+	if s.BackoffUntil != nil {
+		backoffUntil = s.BackoffUntil.Format(time.DateTime)
+	}
+
+	if len(s.Failures) == 0 {
+		return [][]string{{s.User, "-", "0", lastFailure, backoffUntil}}
+	}
+	var rows [][]string
+	for _, f := range s.Failures {
+		rows = append(rows, []string{s.User, f.Service, fmt.Sprint(f.FailedAttempts), lastFailure, backoffUntil})
+	}
+	return rows
+}
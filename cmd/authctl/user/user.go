@@ -0,0 +1,149 @@
+// Package user implements the authctl commands handling the users of authd.
+package user
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// NewCmd returns the user command, connecting to the daemon through the given socket path and printing the results
+// in the given output format.
+func NewCmd(socketPath *string, output *printer.Format) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user COMMAND",
+		Short: "Manage the users of authd",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	gecosCmd := &cobra.Command{
+		Use:   "gecos USERNAME",
+		Short: "Change the GECOS fields of a user, like chfn",
+		Long: `Change the full name, room and phone numbers stored in the GECOS field of a
+user who already logged in with their broker.
+
+Only the given fields are changed. The values provided by the broker take
+precedence over them on the next login of the user.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req, err := newSUGRequest(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			_, err = c.SetUserGecos(cmd.Context(), req)
+			return err
+		},
+	}
+	gecosCmd.Flags().StringP("full-name", "f", "", "the full name of the user")
+	gecosCmd.Flags().StringP("room", "r", "", "the room number of the user")
+	gecosCmd.Flags().StringP("work-phone", "w", "", "the work phone number of the user")
+	gecosCmd.Flags().StringP("home-phone", "p", "", "the home phone number of the user")
+	cmd.AddCommand(gecosCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set-shell USERNAME SHELL",
+		Short: "Change the login shell of a user, like chsh",
+		Long: `Change the login shell of a user who already logged in with their broker.
+
+The shell must be listed in /etc/shells. It takes precedence over the one
+provided by the broker on the next logins of the user.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			_, err = c.SetUserShell(cmd.Context(), &authd.SUSRequest{Username: args[0], Shell: args[1]})
+			return err
+		},
+	})
+
+	cmd.AddCommand(newSetHomeCmd(socketPath))
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "adopt LOCALNAME BROKERUSER",
+		Short: "Migrate a local user to a user of a broker",
+		Long: `Migrate a user of /etc/passwd to the management of authd.
+
+On their first login, the user of the broker takes over the UID, primary group
+and home directory of the local user instead of getting new ones, so that they
+keep owning their files without changing their ownership.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			_, err = c.AdoptUser(cmd.Context(), &authd.AURequest{LocalName: args[0], BrokerUser: args[1]})
+			return err
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reset-broker USERNAME",
+		Short: "Make a user select their broker again on their next login",
+		Long: `Forget the broker a user last authenticated with, so that the broker selection
+is shown again on their next login. It's useful for users switching to another
+identity provider.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, closeConn, err := client.NewPAM(*socketPath)
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			_, err = c.ResetBrokerForUser(cmd.Context(), &authd.RBFURequest{Username: args[0]})
+			return err
+		},
+	})
+
+	cmd.AddCommand(newChuidCmd(socketPath))
+	cmd.AddCommand(newOverrideCmd(socketPath, output))
+	cmd.AddCommand(newShowCmd(socketPath, output))
+
+	return cmd
+}
+
+// newSUGRequest returns the request changing the GECOS fields of the user set in the flags of the command, so that
+// the fields not given on the command line are kept unchanged.
+func newSUGRequest(cmd *cobra.Command, username string) (*authd.SUGRequest, error) {
+	flags := cmd.Flags()
+	req := &authd.SUGRequest{Username: username}
+	for flag, field := range map[string]**string{
+		"full-name":  &req.FullName,
+		"room":       &req.Room,
+		"work-phone": &req.WorkPhone,
+		"home-phone": &req.HomePhone,
+	} {
+		if !flags.Changed(flag) {
+			continue
+		}
+		v, err := flags.GetString(flag)
+		if err != nil {
+			return nil, err
+		}
+		*field = &v
+	}
+
+	if req.FullName == nil && req.Room == nil && req.WorkPhone == nil && req.HomePhone == nil {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no GECOS field to change given")
+	}
+	return req, nil
+}
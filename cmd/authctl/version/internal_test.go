@@ -0,0 +1,121 @@
+package version
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/buildinfo"
+)
+
+func TestPrintVersions(t *testing.T) {
+	t.Parallel()
+
+	authctl := component{Name: "authctl", Info: buildinfo.Info{
+		Version: "1.0", GDMProtocolVersion: 1, BrokerProtocolVersion: 1, GoVersion: "go1.24", Revision: "abcdef",
+	}}
+	authd := component{Name: "authd", Info: buildinfo.Info{
+		Version: "0.9", GDMProtocolVersion: 1, BrokerProtocolVersion: 1, GoVersion: "go1.23", Revision: "012345", Modified: true,
+	}}
+
+	tests := map[string]struct {
+		components []component
+		format     printer.Format
+
+		want string
+	}{
+		"Print_local_version": {
+			components: []component{authctl},
+			want: strings.Join([]string{
+				"COMPONENT  VERSION  GDM PROTOCOL  BROKER PROTOCOL  GO      REVISION",
+				"authctl    1.0      1             1                go1.24  abcdef",
+				"",
+			}, "\n"),
+		},
+		"Print_local_and_remote_versions": {
+			components: []component{authctl, authd},
+			want: strings.Join([]string{
+				"COMPONENT  VERSION  GDM PROTOCOL  BROKER PROTOCOL  GO      REVISION",
+				"authctl    1.0      1             1                go1.24  abcdef",
+				"authd      0.9      1             1                go1.23  012345 (modified)",
+				"",
+			}, "\n"),
+		},
+		"Print_versions_as_JSON": {
+			components: []component{authd},
+			format:     printer.JSON,
+			want: strings.Join([]string{
+				`{`,
+				`  "components": [`,
+				`    {`,
+				`      "name": "authd",`,
+				`      "version": "0.9",`,
+				`      "gdm_protocol_version": 1,`,
+				`      "broker_protocol_version": 1,`,
+				`      "go_version": "go1.23",`,
+				`      "revision": "012345",`,
+				`      "modified": true`,
+				`    }`,
+				`  ]`,
+				`}`,
+				``,
+			}, "\n"),
+		},
+		"Print_versions_as_YAML": {
+			components: []component{authctl},
+			format:     printer.YAML,
+			want: strings.Join([]string{
+				`components:`,
+				`  - name: authctl`,
+				`    version: "1.0"`,
+				`    gdm_protocol_version: 1`,
+				`    broker_protocol_version: 1`,
+				`    go_version: go1.24`,
+				`    revision: abcdef`,
+				``,
+			}, "\n"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.format == "" {
+				tc.format = printer.Table
+			}
+
+			var out strings.Builder
+			err := printer.Print(&out, tc.format, versions{Components: tc.components})
+			require.NoError(t, err, "Print should not return an error, but did")
+			require.Equal(t, tc.want, out.String(), "Print returned an unexpected output")
+		})
+	}
+}
+
+func TestSkewWarnings(t *testing.T) {
+	t.Parallel()
+
+	local := buildinfo.Info{Version: "1.0", GDMProtocolVersion: 1, BrokerProtocolVersion: 1, GoVersion: "go1.24"}
+
+	tests := map[string]struct {
+		remote buildinfo.Info
+
+		wantWarnings int
+	}{
+		"No_warning_when_versions_match":              {remote: local},
+		"No_warning_when_only_the_build_differs":      {remote: buildinfo.Info{Version: "1.0", GDMProtocolVersion: 1, BrokerProtocolVersion: 1, GoVersion: "go1.23"}},
+		"Warn_when_versions_differ":                   {remote: buildinfo.Info{Version: "0.9", GDMProtocolVersion: 1, BrokerProtocolVersion: 1}, wantWarnings: 1},
+		"Warn_when_GDM_protocol_versions_differ":      {remote: buildinfo.Info{Version: "1.0", GDMProtocolVersion: 2, BrokerProtocolVersion: 1}, wantWarnings: 1},
+		"Warn_when_broker_protocol_versions_differ":   {remote: buildinfo.Info{Version: "1.0", GDMProtocolVersion: 1, BrokerProtocolVersion: 2}, wantWarnings: 1},
+		"Warn_about_all_the_differences_between_them": {remote: buildinfo.Info{Version: "0.9", GDMProtocolVersion: 2, BrokerProtocolVersion: 2}, wantWarnings: 3},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := skewWarnings(local, tc.remote)
+			require.Len(t, got, tc.wantWarnings, "skewWarnings returned an unexpected number of warnings: %v", got)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Package version implements the authctl command printing the versions of authctl and of the daemon.
+package version
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/ubuntu/authd/cmd/authctl/internal/client"
+	"github.com/ubuntu/authd/cmd/authctl/internal/printer"
+	"github.com/ubuntu/authd/internal/buildinfo"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// NewCmd returns the version command, connecting to the daemon through the given socket path and printing the results
+// in the given output format.
+func NewCmd(socketPath *string, output *printer.Format) *cobra.Command {
+	var remote bool
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the version of authctl and of the daemon",
+		Long: `Print the version of authctl, the versions of the protocols it speaks and how it
+was built. With --remote, the ones of the daemon are printed too, and a warning
+is shown if they don't match the ones of authctl.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := versions{Components: []component{{Name: "authctl", Info: buildinfo.Get()}}}
+
+			if remote {
+				c, closeConn, err := client.NewPAM(*socketPath)
+				if err != nil {
+					return err
+				}
+				defer closeConn()
+
+				resp, err := c.GetVersion(cmd.Context(), &authd.Empty{})
+				if err != nil {
+					return err
+				}
+				v.Components = append(v.Components, component{Name: "authd", Info: infoFromResponse(resp)})
+
+				for _, w := range skewWarnings(v.Components[0].Info, v.Components[1].Info) {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", w)
+				}
+			}
+
+			return printer.Print(cmd.OutOrStdout(), *output, v)
+		},
+	}
+	cmd.Flags().BoolVar(&remote, "remote", false, "also print the version of the daemon")
+
+	return cmd
+}
+
+// versions are the versions of authctl and, if requested, of the daemon.
+type versions struct {
+	Components []component `json:"components" yaml:"components"`
+}
+
+// component is the version of an executable of authd.
+type component struct {
+	Name           string `json:"name" yaml:"name"`
+	buildinfo.Info `json:",inline" yaml:",inline"`
+}
+
+func infoFromResponse(resp *authd.GVResponse) buildinfo.Info {
+	return buildinfo.Info{
+		Version:               resp.GetVersion(),
+		GDMProtocolVersion:    resp.GetGdmProtocolVersion(),
+		BrokerProtocolVersion: resp.GetBrokerProtocolVersion(),
+		GoVersion:             resp.GetGoVersion(),
+		Revision:              resp.GetRevision(),
+		Modified:              resp.GetModified(),
+	}
+}
+
+// skewWarnings returns the differences between the versions of authctl and of the daemon which may prevent them from
+// working together, usually because one was upgraded without restarting the daemon.
+func skewWarnings(local, remote buildinfo.Info) []string {
+	var warnings []string
+	if local.Version != remote.Version {
+		warnings = append(warnings, fmt.Sprintf("authctl version %s doesn't match the daemon version %s, the daemon may need to be restarted",
+			local.Version, remote.Version))
+	}
+	if local.GDMProtocolVersion != remote.GDMProtocolVersion {
+		warnings = append(warnings, fmt.Sprintf("authctl speaks the GDM protocol version %d, but the daemon speaks version %d",
+			local.GDMProtocolVersion, remote.GDMProtocolVersion))
+	}
+	if local.BrokerProtocolVersion != remote.BrokerProtocolVersion {
+		warnings = append(warnings, fmt.Sprintf("authctl speaks the broker protocol version %d, but the daemon speaks version %d",
+			local.BrokerProtocolVersion, remote.BrokerProtocolVersion))
+	}
+	return warnings
+}
+
+// Header returns the header of the versions table.
+func (v versions) Header() []string {
+	return []string{"COMPONENT", "VERSION", "GDM PROTOCOL", "BROKER PROTOCOL", "GO", "REVISION"}
+}
+
+// Rows returns a row for each component, with its version and build metadata.
+func (v versions) Rows() [][]string {
+	var rows [][]string
+	for _, c := range v.Components {
+		revision := c.Revision
+		if c.Modified {
+			revision += " (modified)"
+		}
+		rows = append(rows, []string{
+			c.Name,
+			c.Version,
+			strconv.FormatUint(uint64(c.GDMProtocolVersion), 10),
+			strconv.FormatUint(uint64(c.BrokerProtocolVersion), 10),
+			c.GoVersion,
+			revision,
+		})
+	}
+	return rows
+}
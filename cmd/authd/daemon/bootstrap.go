@@ -0,0 +1,223 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/internal/users/types"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigPath is the configuration file the seed configuration is written to, when the daemon didn't use any.
+const defaultConfigPath = "/etc/authd/authd.yaml"
+
+// seed is the content of a seed file, describing the state authd should be in before the first login.
+type seed struct {
+	// Brokers are the content of the configuration files of the brokers to register, indexed by file name.
+	Brokers map[string]string `yaml:"brokers"`
+	// Config is merged into the configuration file of the daemon, for instance to set the login policy.
+	Config map[string]any `yaml:"config"`
+	// Users are the users to pre-provision in the database.
+	Users []seedUser `yaml:"users"`
+}
+
+// seedUser is a user pre-provisioned from a seed file, as if it had logged in with its broker.
+type seedUser struct {
+	Name   string      `yaml:"name"`
+	Broker string      `yaml:"broker"`
+	Gecos  string      `yaml:"gecos"`
+	Dir    string      `yaml:"dir"`
+	Shell  string      `yaml:"shell"`
+	Groups []seedGroup `yaml:"groups"`
+}
+
+// seedGroup is a group of a pre-provisioned user. Groups without UGID are local groups.
+type seedGroup struct {
+	Name string `yaml:"name"`
+	UGID string `yaml:"ugid"`
+}
+
+func (a *App) installBootstrap() {
+	cmd := &cobra.Command{
+		Use:                                                                                       "bootstrap",
+		Short:/*i18n.G(*/ "Applies a seed file to prepare the system for broker logins and exits", /*)*/
+		Long: /*i18n.G(*/ `Applies a seed file registering brokers, setting the configuration of the daemon
+and pre-provisioning users, so that the system is ready for broker logins at first boot.
+This is meant to be run once, for instance from cloud-init, before the daemon is started.`, /*)*/
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			seedPath, err := cmd.Flags().GetString("seed")
+			if err != nil {
+				return err
+			}
+			return a.bootstrap(seedPath)
+		},
+	}
+	cmd.Flags().String("seed", "" /*i18n.G(*/, "seed file to apply") /*)*/
+	_ = cmd.MarkFlagRequired("seed")
+
+	a.rootCmd.AddCommand(cmd)
+}
+
+// bootstrap applies the seed file at seedPath. Applying the same seed again leaves the system unchanged.
+func (a *App) bootstrap(seedPath string) (err error) {
+	defer decorate.OnError(&err, "could not apply seed file %q", seedPath)
+
+	s, err := readSeed(seedPath)
+	if err != nil {
+		return err
+	}
+
+	if len(s.Config) > 0 {
+		if err := a.applySeedConfig(s.Config); err != nil {
+			return err
+		}
+	}
+
+	if err := installBrokersConf(a.config.Paths.BrokersConf, s.Brokers); err != nil {
+		return err
+	}
+
+	if len(s.Users) > 0 {
+		if err := provisionUsers(a.config, s.Users); err != nil {
+			return err
+		}
+	}
+
+	log.Infof(context.Background(), "Applied seed file %q", seedPath)
+	return nil
+}
+
+// readSeed reads and validates the seed file at path.
+func readSeed(path string) (s seed, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return seed{}, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return seed{}, fmt.Errorf("invalid seed file: %w", err)
+	}
+
+	for name := range s.Brokers {
+		if name != filepath.Base(name) || !strings.HasSuffix(name, ".conf") {
+			return seed{}, fmt.Errorf("invalid broker configuration file name %q: must be a file name ending with .conf", name)
+		}
+	}
+	for _, u := range s.Users {
+		if u.Name == "" || u.Broker == "" {
+			return seed{}, errors.New("invalid seed file: users need a name and a broker")
+		}
+	}
+
+	return s, nil
+}
+
+// applySeedConfig merges the seed configuration into the configuration file of the daemon, and into the current
+// configuration so that the users are provisioned with it.
+func (a *App) applySeedConfig(config map[string]any) (err error) {
+	path := a.viper.ConfigFileUsed()
+	if path == "" {
+		path = defaultConfigPath
+	}
+	defer decorate.OnError(&err, "could not write configuration to %q", path)
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := v.MergeConfigMap(config); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := v.WriteConfigAs(path); err != nil {
+		return err
+	}
+
+	if err := a.viper.MergeConfigMap(config); err != nil {
+		return err
+	}
+	return a.viper.Unmarshal(&a.config)
+}
+
+// installBrokersConf writes the configuration files of the brokers to the brokers configuration directory.
+func installBrokersConf(dir string, confs map[string]string) (err error) {
+	defer decorate.OnError(&err, "could not install brokers configuration in %q", dir)
+
+	if len(confs) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for name, content := range confs {
+		//nolint:gosec // The brokers configuration files are world-readable, like the ones installed by the brokers.
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return err
+		}
+		log.Infof(context.Background(), "Registered broker configuration %q", name)
+	}
+	return nil
+}
+
+// provisionUsers adds the users to the database, as if they had logged in with their broker.
+func provisionUsers(config daemonConfig, seedUsers []seedUser) (err error) {
+	defer decorate.OnError(&err, "could not provision users")
+
+	dbDir := config.Paths.Database
+	if err := ensureDirWithPerms(dbDir, 0700); err != nil {
+		return fmt.Errorf("error initializing database directory at %q: %v", dbDir, err)
+	}
+
+	m, err := users.NewManager(config.UsersConfig, dbDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Stop() }()
+
+	for _, su := range seedUsers {
+		u := types.UserInfo{
+			Name:  su.Name,
+			Gecos: su.Gecos,
+			Dir:   su.Dir,
+			Shell: su.Shell,
+		}
+		if u.Dir == "" {
+			u.Dir = filepath.Join("/home", su.Name)
+		}
+		if u.Shell == "" {
+			u.Shell = "/bin/bash"
+		}
+		for _, g := range su.Groups {
+			u.Groups = append(u.Groups, types.GroupInfo{Name: g.Name, UGID: g.UGID})
+		}
+
+		if err := m.UpdateUser(context.Background(), u, su.Broker); err != nil {
+			return err
+		}
+		if err := m.UpdateBrokerForUser(u.Name, brokers.IDFromName(su.Broker)); err != nil {
+			return err
+		}
+		log.Infof(context.Background(), "Provisioned user %q for broker %q", u.Name, su.Broker)
+	}
+	return nil
+}
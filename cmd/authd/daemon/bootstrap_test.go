@@ -0,0 +1,148 @@
+package daemon_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/cmd/authd/daemon"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/users/db"
+	"gopkg.in/yaml.v3"
+)
+
+const validSeed = `
+brokers:
+  examplebroker.conf: |
+    [authd]
+    name = ExampleBroker
+    dbus_name = com.ubuntu.authd.ExampleBroker
+    dbus_object = /com/ubuntu/authd/ExampleBroker
+config:
+  login_policy:
+    allowed_groups: [admins]
+  broker_trust:
+    ExampleBroker:
+      allowed_shells: [/bin/zsh]
+users:
+  - name: user1@example.com
+    broker: ExampleBroker
+    gecos: User 1
+    groups:
+      - name: admins
+        ugid: admins-ugid
+  - name: user2@example.com
+    broker: ExampleBroker
+    dir: /home/custom
+    shell: /bin/zsh
+`
+
+func TestBootstrap(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		seed       string
+		noSeed     bool
+		applyTwice bool
+
+		wantErr bool
+	}{
+		"Apply_seed_file":                 {seed: validSeed},
+		"Apply_seed_file_twice":           {seed: validSeed, applyTwice: true},
+		"Apply_empty_seed_file":           {seed: ""},
+		"Apply_seed_file_with_only_users": {seed: "users: [{name: user1@example.com, broker: ExampleBroker}]"},
+
+		"Error_if_seed_file_does_not_exist":       {noSeed: true, wantErr: true},
+		"Error_if_seed_file_is_invalid":           {seed: "not: [valid", wantErr: true},
+		"Error_if_seed_file_has_unknown_fields":   {seed: "unknown: true", wantErr: true},
+		"Error_if_broker_file_is_not_a_conf_file": {seed: "brokers: {broker.txt: content}", wantErr: true},
+		"Error_if_broker_file_is_not_a_file_name": {seed: "brokers: {../broker.conf: content}", wantErr: true},
+		"Error_if_user_has_no_broker":             {seed: "users: [{name: user1@example.com}]", wantErr: true},
+		"Error_if_user_has_no_name":               {seed: "users: [{broker: ExampleBroker}]", wantErr: true},
+		"Error_if_config_is_invalid":              {seed: "config: {login_policy: 1}", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			config := daemon.DaemonConfig{
+				Paths: daemon.SystemPaths{
+					BrokersConf: filepath.Join(t.TempDir(), "brokers.d"),
+					Database:    filepath.Join(t.TempDir(), "db"),
+				},
+			}
+			configPath := daemon.GenerateTestConfig(t, &config)
+
+			seedPath := filepath.Join(t.TempDir(), "seed.yaml")
+			if !tc.noSeed {
+				err := os.WriteFile(seedPath, []byte(tc.seed), 0600)
+				require.NoError(t, err, "Setup: could not write seed file")
+			}
+
+			runs := 1
+			if tc.applyTwice {
+				runs = 2
+			}
+			var err error
+			for range runs {
+				a := daemon.New()
+				a.SetArgs("bootstrap", "--config", configPath, "--seed", seedPath)
+				err = a.Run()
+				if err != nil {
+					break
+				}
+			}
+			if tc.wantErr {
+				require.Error(t, err, "Bootstrap should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Bootstrap should not return an error, but did")
+
+			if tc.seed != validSeed {
+				return
+			}
+
+			brokerConf, err := os.ReadFile(filepath.Join(config.Paths.BrokersConf, "examplebroker.conf"))
+			require.NoError(t, err, "The broker configuration file should be installed")
+			require.Contains(t, string(brokerConf), "name = ExampleBroker", "The broker configuration file should have the seed content")
+
+			data, err := os.ReadFile(configPath)
+			require.NoError(t, err, "The configuration file should be readable")
+			var gotConfig map[string]any
+			require.NoError(t, yaml.Unmarshal(data, &gotConfig), "The configuration file should be valid YAML")
+			require.Equal(t, map[string]any{"allowed_groups": []any{"admins"}}, gotConfig["login_policy"],
+				"The login policy should be merged into the configuration file")
+			require.Equal(t, config.Paths.Database, gotConfig["paths"].(map[string]any)["database"],
+				"The existing configuration should be kept")
+
+			m, err := db.New(config.Paths.Database)
+			require.NoError(t, err, "Setup: could not open the database")
+			t.Cleanup(func() { _ = m.Close() })
+
+			users, err := m.AllUsers()
+			require.NoError(t, err, "AllUsers should not return an error, but did")
+			require.Len(t, users, 2, "The users of the seed file should be provisioned once")
+
+			u1, err := m.UserByName("user1@example.com")
+			require.NoError(t, err, "user1 should be provisioned")
+			require.Equal(t, "User 1", u1.Gecos, "Gecos of user1 should be the one of the seed file")
+			require.Equal(t, "/home/user1@example.com", u1.Dir, "Home directory of user1 should default to /home")
+			require.Equal(t, "/bin/bash", u1.Shell, "Shell of user1 should default to bash")
+			require.Equal(t, brokers.IDFromName("ExampleBroker"), u1.BrokerID, "user1 should be assigned to its broker")
+
+			groups, err := m.UserGroups(u1.UID)
+			require.NoError(t, err, "UserGroups should not return an error, but did")
+			var groupNames []string
+			for _, g := range groups {
+				groupNames = append(groupNames, g.Name)
+			}
+			require.Contains(t, groupNames, "admins", "user1 should be a member of the seed group")
+
+			u2, err := m.UserByName("user2@example.com")
+			require.NoError(t, err, "user2 should be provisioned")
+			require.Equal(t, "/home/custom", u2.Dir, "Home directory of user2 should be the one of the seed file")
+			require.Equal(t, "/bin/zsh", u2.Shell, "Shell of user2 should be the one of the seed file")
+		})
+	}
+}
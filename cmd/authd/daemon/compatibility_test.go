@@ -0,0 +1,147 @@
+package daemon
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	// sqlite3 driver.
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/fileutils"
+	"github.com/ubuntu/authd/internal/testutils/golden"
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/db/bbolt"
+)
+
+// TestDatabaseMigrationAndSchemaCompatibility checks how the database migration behaves when the bbolt database
+// is still around, and that databases with a newer schema can be used:
+//   - All the data of the bbolt database is migrated to SQLite.
+//   - Recreating the bbolt database after the migration, as the releases using it would do, never touches the SQLite
+//     database, and the migration keeps the SQLite data, ignoring what may have been written in the bbolt one.
+//   - A database with additional columns or tables can still be read and updated.
+//
+// The releases using bbolt are simulated with the bbolt code of this tree and the newer schema is a hand-written
+// fixture, these are not databases written by actual releases.
+func TestDatabaseMigrationAndSchemaCompatibility(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		bboltFixture  string
+		sqliteFixture string
+
+		recreateBBoltDB         bool
+		recreatedBBoltDBFixture string
+
+		wantMigrated bool
+	}{
+		"Migrates_all_the_bbolt_data": {
+			bboltFixture: "multiple_users_and_groups.db.yaml",
+			wantMigrated: true,
+		},
+		"Keeps_the_SQLite_data_when_the_bbolt_database_is_recreated": {
+			bboltFixture:    "multiple_users_and_groups.db.yaml",
+			recreateBBoltDB: true,
+			wantMigrated:    true,
+		},
+		"Ignores_the_data_written_in_the_bbolt_database_after_the_migration": {
+			bboltFixture:            "multiple_users_and_groups.db.yaml",
+			recreateBBoltDB:         true,
+			recreatedBBoltDBFixture: "written_after_migration.db.yaml",
+			wantMigrated:            true,
+		},
+		"Reads_and_updates_a_database_with_additional_columns_and_tables": {sqliteFixture: "newer_schema.sql"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			sqlitePath := filepath.Join(dbDir, db.Filename())
+
+			if tc.bboltFixture != "" {
+				err := bbolt.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", tc.bboltFixture), dbDir)
+				require.NoError(t, err, "Setup: could not create bbolt database")
+			}
+			if tc.sqliteFixture != "" {
+				createSQLiteDBFromFixture(t, filepath.Join("testdata", tc.sqliteFixture), sqlitePath)
+			}
+
+			migrated, err := maybeMigrateBBoltToSQLite(dbDir)
+			require.NoError(t, err, "Migration should not fail")
+			require.Equal(t, tc.wantMigrated, migrated, "Migration status is not the expected one")
+
+			want := dumpDB(t, dbDir)
+
+			if tc.recreateBBoltDB {
+				sqliteContent, err := os.ReadFile(sqlitePath)
+				require.NoError(t, err, "Setup: could not read SQLite database")
+
+				// The releases using bbolt only know about its database, so they create a new one.
+				bboltDB, err := bbolt.New(dbDir)
+				require.NoError(t, err, "bbolt database should be recreated after the migration")
+				require.NoError(t, bboltDB.Close(), "bbolt database should be closed")
+
+				if tc.recreatedBBoltDBFixture != "" {
+					require.NoError(t, bbolt.RemoveDb(dbDir), "Setup: could not remove bbolt database")
+					err := bbolt.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", tc.recreatedBBoltDBFixture), dbDir)
+					require.NoError(t, err, "Setup: could not create the recreated bbolt database")
+				}
+
+				got, err := os.ReadFile(sqlitePath)
+				require.NoError(t, err, "Setup: could not read SQLite database")
+				require.Equal(t, sqliteContent, got, "Recreating the bbolt database should not modify the SQLite one")
+
+				migrated, err = maybeMigrateBBoltToSQLite(dbDir)
+				require.NoError(t, err, "Migration with a recreated bbolt database should not fail")
+				require.False(t, migrated, "Recreated bbolt database should not be migrated again")
+
+				exists, err := fileutils.FileExists(filepath.Join(dbDir, bbolt.DBFilename()))
+				require.NoError(t, err, "Setup: could not check for the bbolt database")
+				require.True(t, exists, "Migration should not remove the recreated bbolt database")
+			}
+
+			got := dumpDB(t, dbDir)
+			require.Equal(t, want, got, "Database content should be preserved")
+			golden.CheckOrUpdate(t, got)
+
+			// Ensure that the database can be updated too.
+			m, err := db.New(dbDir)
+			require.NoError(t, err, "Database should be opened by the current release")
+			t.Cleanup(func() { require.NoError(t, m.Close(), "Teardown: could not close database") })
+
+			err = m.UpdateUserEntry(context.Background(), db.NewUserRow("newuser", 6666, 66666, "", "/home/newuser", "/bin/bash"),
+				[]db.GroupRow{db.NewGroupRow("newgroup", 66666, "66666666")}, nil)
+			require.NoError(t, err, "Database should be updated by the current release")
+		})
+	}
+}
+
+func createSQLiteDBFromFixture(t *testing.T, fixture, dbPath string) {
+	t.Helper()
+
+	schema, err := os.ReadFile(fixture)
+	require.NoError(t, err, "Setup: could not read SQLite fixture")
+
+	sqlDB, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err, "Setup: could not open SQLite database")
+	defer sqlDB.Close()
+
+	_, err = sqlDB.Exec(string(schema))
+	require.NoError(t, err, "Setup: could not load SQLite fixture")
+	require.NoError(t, os.Chmod(dbPath, 0600), "Setup: could not change SQLite database permissions")
+}
+
+func dumpDB(t *testing.T, dbDir string) string {
+	t.Helper()
+
+	m, err := db.New(dbDir)
+	require.NoError(t, err, "Database should be opened by the current release")
+	defer m.Close()
+
+	content, err := db.Z_ForTests_DumpNormalizedYAML(m)
+	require.NoError(t, err, "Database content should be readable by the current release")
+	return content
+}
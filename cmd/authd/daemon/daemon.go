@@ -4,13 +4,20 @@ package daemon
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/consts"
 	"github.com/ubuntu/authd/internal/daemon"
+	"github.com/ubuntu/authd/internal/debugserver"
 	"github.com/ubuntu/authd/internal/services"
+	"github.com/ubuntu/authd/internal/services/pam"
+	"github.com/ubuntu/authd/internal/services/rpclimits"
+	"github.com/ubuntu/authd/internal/tracing"
 	"github.com/ubuntu/authd/internal/users"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
@@ -19,13 +26,20 @@ import (
 // cmdName is the binary name for the agent.
 const cmdName = "authd"
 
+// defaultShutdownTimeout is the default time the in-flight authentications are given to complete when quitting.
+const defaultShutdownTimeout = 10 * time.Second
+
+// stateDumpsDir is the directory of the state dumps, in the database directory.
+const stateDumpsDir = "state-dumps"
+
 // App encapsulate commands and options of the daemon, which can be controlled by env variables and config files.
 type App struct {
 	rootCmd cobra.Command
 	viper   *viper.Viper
 	config  daemonConfig
 
-	daemon *daemon.Daemon
+	daemon   *daemon.Daemon
+	services *services.Manager
 
 	ready chan struct{}
 }
@@ -35,14 +49,31 @@ type systemPaths struct {
 	BrokersConf string
 	Database    string
 	Socket      string
+	DebugSocket string
 }
 
 // daemonConfig defines configuration parameters of the daemon.
 type daemonConfig struct {
-	Brokers     []string
-	Verbosity   int
-	Paths       systemPaths
-	UsersConfig users.Config `mapstructure:",squash"`
+	Brokers                      []string
+	Verbosity                    int
+	Paths                        systemPaths
+	MaxConcurrentAuthentications int                            `mapstructure:"max_concurrent_authentications"`
+	RecentAuthentication         pam.RecentAuthenticationPolicy `mapstructure:"recent_authentication"`
+	LocalFallback                pam.LocalFallbackPolicy        `mapstructure:"local_fallback"`
+	Session                      pam.SessionPolicy              `mapstructure:"session"`
+	Login                        pam.LoginPolicy                `mapstructure:"login_policy"`
+	Consent                      pam.ConsentPolicy              `mapstructure:"consent"`
+	StepUp                       pam.StepUpPolicy               `mapstructure:"step_up"`
+	LocalPIN                     pam.LocalPINPolicy             `mapstructure:"local_pin"`
+	BruteForce                   pam.BruteForcePolicy           `mapstructure:"brute_force"`
+	DefaultBroker                string                         `mapstructure:"default_broker"`
+	IdleTimeout                  time.Duration                  `mapstructure:"idle_timeout"`
+	ShutdownTimeout              time.Duration                  `mapstructure:"shutdown_timeout"`
+	RPCLimits                    rpclimits.Config               `mapstructure:"rpc_limits"`
+	Tracing                      tracing.Config                 `mapstructure:"tracing"`
+	RecordBrokersTraffic         string                         `mapstructure:"record_brokers_traffic"`
+	DebugSocket                  bool                           `mapstructure:"debug_socket" yaml:"debug_socket"`
+	UsersConfig                  users.Config                   `mapstructure:",squash"`
 }
 
 // New registers commands and return a new App.
@@ -67,8 +98,10 @@ func New() *App {
 					BrokersConf: consts.DefaultBrokersConfPath,
 					Database:    consts.DefaultDatabaseDir,
 					Socket:      "",
+					DebugSocket: consts.DefaultDebugSocketPath,
 				},
-				UsersConfig: users.DefaultConfig,
+				UsersConfig:     users.DefaultConfig,
+				ShutdownTimeout: defaultShutdownTimeout,
 			}
 
 			// Install and unmarshall configuration
@@ -107,6 +140,7 @@ func New() *App {
 
 	// subcommands
 	a.installVersion()
+	a.installBootstrap()
 
 	return &a
 }
@@ -121,7 +155,47 @@ func (a *App) serve(config daemonConfig) error {
 		return fmt.Errorf("error initializing database directory at %q: %v", dbDir, err)
 	}
 
-	m, err := services.NewManager(ctx, dbDir, config.Paths.BrokersConf, config.Brokers, config.UsersConfig)
+	shutdownTracing, err := tracing.Setup(ctx, config.Tracing)
+	if err != nil {
+		close(a.ready)
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Warningf(ctx, "Could not flush the pending traces: %v", err)
+		}
+	}()
+
+	var brokerOpts []brokers.Option
+	if config.RecordBrokersTraffic != "" {
+		brokerOpts = append(brokerOpts, brokers.WithRecordingsDir(config.RecordBrokersTraffic))
+	}
+
+	// The debug socket can also be enabled and disabled at runtime with authctl.
+	debugServer := debugserver.New(config.Paths.DebugSocket)
+	if config.DebugSocket {
+		if err := debugServer.Start(); err != nil {
+			log.Warningf(ctx, "The debug socket is not available: %v", err)
+		}
+	}
+	defer func() {
+		if err := debugServer.Stop(); err != nil {
+			log.Warningf(ctx, "%v", err)
+		}
+	}()
+
+	m, err := services.NewManager(ctx, dbDir, config.Paths.BrokersConf, config.Brokers, brokerOpts, config.UsersConfig, config.RPCLimits,
+		pam.WithMaxConcurrentAuthentications(config.MaxConcurrentAuthentications),
+		pam.WithRecentAuthenticationPolicy(config.RecentAuthentication),
+		pam.WithLocalFallbackPolicy(config.LocalFallback),
+		pam.WithSessionPolicy(config.Session),
+		pam.WithLoginPolicy(config.Login),
+		pam.WithConsentPolicy(config.Consent),
+		pam.WithStepUpPolicy(config.StepUp),
+		pam.WithLocalPINPolicy(config.LocalPIN),
+		pam.WithBruteForcePolicy(config.BruteForce),
+		pam.WithDefaultBroker(config.DefaultBroker),
+		pam.WithDebugServer(debugServer))
 	if err != nil {
 		close(a.ready)
 		return err
@@ -129,11 +203,19 @@ func (a *App) serve(config daemonConfig) error {
 	// We are closing the database on exit.
 	defer func() { _ = m.Stop() }()
 
+	stopDBusBridge := m.ExportDBusBridge(ctx)
+	defer stopDBusBridge()
+
 	socketPath := config.Paths.Socket
 	var daemonopts []daemon.Option
 	if socketPath != "" {
 		daemonopts = append(daemonopts, daemon.WithSocketPath(socketPath))
 	}
+	daemonopts = append(daemonopts, daemon.WithHealthCheck(m.CheckHealth))
+	if config.IdleTimeout > 0 {
+		daemonopts = append(daemonopts, daemon.WithIdleTimeout(config.IdleTimeout))
+		daemonopts = append(daemonopts, daemon.WithBusyCheck(m.Busy))
+	}
 
 	daemon, err := daemon.New(ctx, m.RegisterGRPCServices, daemonopts...)
 	if err != nil {
@@ -142,6 +224,7 @@ func (a *App) serve(config daemonConfig) error {
 	}
 
 	a.daemon = daemon
+	a.services = &m
 	close(a.ready)
 
 	return daemon.Serve(ctx)
@@ -164,20 +247,61 @@ func (a App) UsageError() bool {
 	return !a.rootCmd.SilenceUsage
 }
 
-// Hup prints all goroutine stack traces and return false to signal you shouldn't quit.
-func (a App) Hup() (shouldQuit bool) {
+// Hup prints all goroutine stack traces, the counters of the gRPC requests and of the failed authentications, and
+// return false to signal you shouldn't quit.
+func (a *App) Hup() (shouldQuit bool) {
 	buf := make([]byte, 1<<16)
-	runtime.Stack(buf, true)
-	fmt.Printf("%s", buf)
+	n := runtime.Stack(buf, true)
+	fmt.Printf("%s", buf[:n])
+	select {
+	case <-a.ready:
+		if a.services != nil {
+			for _, m := range a.services.RPCMetrics() {
+				fmt.Println(m)
+			}
+			fmt.Println(a.services.BruteForceMetrics())
+		}
+	default:
+	}
 	return false
 }
 
+// DumpState writes a diagnostic snapshot of the state of the daemon, with the stack traces of its goroutines, its
+// sessions in progress, its brokers, the statistics of its cache and its recent errors, to a timestamped file in the
+// state dumps directory of the database directory.
+func (a *App) DumpState() {
+	select {
+	case <-a.ready:
+	default:
+		log.Warning(context.Background(), "Can't dump the state of the daemon, which is not ready")
+		return
+	}
+	if a.services == nil {
+		return
+	}
+
+	path, err := a.services.WriteStateDump(context.Background(), filepath.Join(a.config.Paths.Database, stateDumpsDir))
+	if err != nil {
+		log.Warningf(context.Background(), "%v", err)
+		return
+	}
+	log.Noticef(context.Background(), "State of the daemon dumped to %s", path)
+}
+
 // Quit gracefully shutdown the service.
+//
+// New sessions are refused, while in-flight authentications are given some time to complete before all the remaining
+// broker sessions are ended. Then the gRPC server is stopped, which makes serve close the database.
 func (a *App) Quit() {
 	a.WaitReady()
 	if a.daemon == nil {
 		return
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.ShutdownTimeout)
+	defer cancel()
+	a.services.Shutdown(ctx)
+
 	a.daemon.Quit(context.Background(), false)
 }
 
@@ -213,6 +213,26 @@ func TestAppCanSigHupWhenExecute(t *testing.T) {
 	_, err = io.Copy(&out, r)
 	require.NoError(t, err, "Couldn't copy stdout to buffer")
 	require.NotEmpty(t, out.String(), "Stacktrace is printed")
+	require.Contains(t, out.String(), "Authentication requests:", "Metrics of the requests are printed")
+}
+
+func TestAppDumpState(t *testing.T) {
+	a, wait := startDaemon(t, nil)
+	defer wait()
+	defer a.Quit()
+
+	a.DumpState()
+
+	dumps, err := filepath.Glob(filepath.Join(a.Config().Paths.Database, "state-dumps", "authd-state-*.json"))
+	require.NoError(t, err, "Setup: could not list the state dumps")
+	require.Len(t, dumps, 1, "The state of the daemon should be dumped to a file")
+}
+
+func TestAppDumpStateWithoutExecute(t *testing.T) {
+	a := daemon.NewForTests(t, nil)
+
+	// The state can't be dumped before the daemon is ready, which should not block.
+	a.DumpState()
 }
 
 func TestAppCanSigHupAfterExecute(t *testing.T) {
@@ -279,6 +299,21 @@ func TestConfigLoad(t *testing.T) {
 	require.Equal(t, 1, a.Config().Verbosity, "Verbosity is set from config")
 }
 
+func TestConfigEnablesDebugSocket(t *testing.T) {
+	debugSocketPath := filepath.Join(t.TempDir(), "mydebugsocket")
+	var config daemon.DaemonConfig
+	config.DebugSocket = true
+	config.Paths.DebugSocket = debugSocketPath
+
+	a, wait := startDaemon(t, &config)
+	_, err := os.Stat(debugSocketPath)
+	require.NoError(t, err, "Debug socket should exist")
+
+	a.Quit()
+	wait()
+	require.NoFileExists(t, debugSocketPath, "Debug socket should be removed on exit")
+}
+
 func TestAutoDetectConfig(t *testing.T) {
 	customizedSocketPath := filepath.Join(t.TempDir(), "mysocket")
 	var config daemon.DaemonConfig
@@ -324,6 +359,8 @@ func TestNoConfigSetDefaults(t *testing.T) {
 	require.Equal(t, consts.DefaultBrokersConfPath, a.Config().Paths.BrokersConf, "Default brokers configuration path")
 	require.Equal(t, consts.DefaultDatabaseDir, a.Config().Paths.Database, "Default database directory")
 	require.Equal(t, "", a.Config().Paths.Socket, "No socket address as default")
+	require.Equal(t, consts.DefaultDebugSocketPath, a.Config().Paths.DebugSocket, "Default debug socket path")
+	require.False(t, a.Config().DebugSocket, "Debug socket disabled by default")
 }
 
 func TestBadConfigReturnsError(t *testing.T) {
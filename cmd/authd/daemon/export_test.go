@@ -47,6 +47,9 @@ func GenerateTestConfig(t *testing.T, origConf *daemonConfig) string {
 	if conf.Paths.Socket == "" {
 		conf.Paths.Socket = filepath.Join(t.TempDir(), "authd.socket")
 	}
+	if conf.Paths.DebugSocket == "" {
+		conf.Paths.DebugSocket = filepath.Join(t.TempDir(), "authd-debug.socket")
+	}
 	d, err := yaml.Marshal(conf)
 	require.NoError(t, err, "Setup: could not marshal configuration for tests")
 
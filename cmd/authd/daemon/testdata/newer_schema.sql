@@ -0,0 +1,52 @@
+-- Database as written by a hypothetical newer release, which added a column
+-- and a table on top of the current schema.
+CREATE TABLE IF NOT EXISTS users (
+    name      TEXT NOT NULL,
+    uid       INT PRIMARY KEY,
+    gid       INT NOT NULL,
+    gecos     TEXT DEFAULT "",
+    dir       TEXT DEFAULT "",
+    shell     TEXT DEFAULT "/bin/bash",
+    broker_id TEXT DEFAULT "",
+    locked    INT DEFAULT 0
+);
+CREATE UNIQUE INDEX "idx_user_name" ON users ("name");
+
+CREATE TABLE IF NOT EXISTS GROUPS (
+    name TEXT NOT NULL,
+    gid  INT PRIMARY KEY,
+    ugid INT NOT NULL
+);
+CREATE UNIQUE INDEX "idx_group_name" ON GROUPS ("name");
+CREATE UNIQUE INDEX "idx_group_ugid" ON GROUPS ("ugid");
+
+CREATE TABLE IF NOT EXISTS users_to_groups (
+    uid INT NOT NULL,
+    gid INT NOT NULL,
+    PRIMARY KEY (uid, gid),
+    FOREIGN KEY (uid) REFERENCES users (uid) ON DELETE CASCADE,
+    FOREIGN KEY (gid) REFERENCES GROUPS (gid) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS users_to_local_groups (
+    uid        INT NOT NULL,
+    group_name TEXT NOT NULL,
+    PRIMARY KEY (uid, group_name),
+    FOREIGN KEY (uid) REFERENCES users (uid) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS schema_version (
+    version INT NOT NULL
+);
+
+INSERT INTO schema_version (version) VALUES (2);
+
+INSERT INTO users (name, uid, gid, gecos, dir, shell, broker_id, locked) VALUES
+    ('user1', 1111, 11111, 'User1', '/home/user1', '/bin/bash', 'broker-id', 1),
+    ('user2', 2222, 22222, 'User2', '/home/user2', '/bin/dash', 'broker-id', 0);
+INSERT INTO GROUPS (name, gid, ugid) VALUES
+    ('group1', 11111, '12345678'),
+    ('group2', 22222, '56781234');
+INSERT INTO users_to_groups (uid, gid) VALUES
+    (1111, 11111),
+    (2222, 22222);
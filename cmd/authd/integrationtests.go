@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ubuntu/authd/internal/faults"
 	"github.com/ubuntu/authd/internal/services/permissions"
 	"github.com/ubuntu/authd/internal/users/localentries"
 )
@@ -23,4 +24,10 @@ func init() {
 	}
 	localentries.Z_ForTests_SetGpasswdCmd(strings.Split(gpasswdArgs, " "))
 	localentries.Z_ForTests_SetGroupPath(grpFilePath)
+
+	if spec := os.Getenv(faults.SpecEnv); spec != "" {
+		if err := faults.SetFromSpec(spec); err != nil {
+			panic(err)
+		}
+	}
 }
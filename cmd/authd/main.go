@@ -26,6 +26,7 @@ type app interface {
 	Run() error
 	UsageError() bool
 	Hup() bool
+	DumpState()
 	Quit()
 }
 
@@ -46,7 +47,7 @@ func run(a app) int {
 
 func installSignalHandler(a app) func() {
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(c, syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGINT, syscall.SIGTERM)
 
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -62,6 +63,8 @@ func installSignalHandler(a app) func() {
 					a.Quit()
 					return
 				}
+			case syscall.SIGUSR1:
+				a.DumpState()
 			default:
 				// channel was closed: we exited
 				if !ok {
@@ -10,7 +10,8 @@ import (
 )
 
 type myApp struct {
-	done chan struct{}
+	done   chan struct{}
+	dumped chan struct{}
 
 	runError         bool
 	usageErrorReturn bool
@@ -33,6 +34,10 @@ func (a myApp) Hup() bool {
 	return a.hupReturn
 }
 
+func (a *myApp) DumpState() {
+	close(a.dumped)
+}
+
 func (a *myApp) Quit() {
 	close(a.done)
 }
@@ -59,11 +64,13 @@ func TestRun(t *testing.T) {
 		"Send_SIGTERM_exits":          {sendSig: syscall.SIGTERM},
 		"Send_SIGHUP_without_exiting": {sendSig: syscall.SIGHUP},
 		"Send_SIGHUP_with_exit":       {sendSig: syscall.SIGHUP, hupReturn: true},
+		"Send_SIGUSR1_dumps_state":    {sendSig: syscall.SIGUSR1},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			a := myApp{
 				done:             make(chan struct{}),
+				dumped:           make(chan struct{}),
 				runError:         tc.runError,
 				usageErrorReturn: tc.usageErrorReturn,
 				hupReturn:        tc.hupReturn,
@@ -104,6 +111,14 @@ func TestRun(t *testing.T) {
 				// if SIGHUP returns false: do nothing and still wait.
 				// Otherwise, it means that we wanted to stop
 				require.Equal(t, tc.hupReturn, exited, "Expect to exit only on SIGHUP returning True")
+			case syscall.SIGUSR1:
+				err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+				require.NoError(t, err, "Teardown: kill should return no error")
+				select {
+				case <-time.After(time.Second):
+					require.Fail(t, "Expect to dump the state on SIGUSR1")
+				case <-a.dumped:
+				}
 			}
 
 			if !exited {
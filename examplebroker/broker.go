@@ -341,6 +341,11 @@ func (b *Broker) NewSession(ctx context.Context, username, lang, mode string) (s
 		info.neededAuthSteps++
 		info.pwdChange = mustReset
 	}
+	if info.sessionMode == auth.SessionModeStepUp {
+		// The user already logged in, a single lighter authentication is enough.
+		info.neededAuthSteps = 1
+		info.pwdChange = noReset
+	}
 
 	pubASN1, err := x509.MarshalPKIXPublicKey(&b.privateKey.PublicKey)
 	if err != nil {
@@ -379,6 +384,10 @@ func (b *Broker) GetAuthenticationModes(ctx context.Context, sessionID string, s
 		}
 	}
 
+	if sessionInfo.sessionMode == auth.SessionModeStepUp {
+		allModes = getStepUpModes(allModes)
+	}
+
 	if sessionInfo.acceptedAuthModesIDs != nil {
 		maps.DeleteFunc(allModes, func(id string, mode authMode) bool {
 			return !slices.Contains(sessionInfo.acceptedAuthModesIDs, id)
@@ -426,6 +435,21 @@ func (b *Broker) GetAuthenticationModes(ctx context.Context, sessionID string, s
 	return authenticationModes, nil
 }
 
+// getStepUpModes returns the lighter authentication modes offered to the users who already logged in, if any of them
+// is supported. Otherwise, the users authenticate as for a login.
+func getStepUpModes(allModes map[string]authMode) map[string]authMode {
+	stepUpModes := make(map[string]authMode)
+	for _, m := range []authMode{pinCodeMode, totpMode, totpWithButtonMode} {
+		if mode, ok := allModes[m.id]; ok {
+			stepUpModes[m.id] = mode
+		}
+	}
+	if len(stepUpModes) == 0 {
+		return allModes
+	}
+	return stepUpModes
+}
+
 func getSupportedModes(sessionInfo sessionInfo, supportedUILayouts []map[string]string) map[string]authMode {
 	allModes := make(map[string]authMode)
 	for _, layout := range supportedUILayouts {
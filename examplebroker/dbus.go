@@ -67,6 +67,7 @@ name = ExampleBroker
 brand_icon = /usr/share/backgrounds/warty-final-ubuntu.png
 dbus_name = %s
 dbus_object = %s
+step_up = true
 `, busName, dbusObjectPath)),
 		0600); err != nil {
 		return nil, err
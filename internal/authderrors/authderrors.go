@@ -0,0 +1,146 @@
+// Package authderrors defines the classification of the errors shared by the daemon and the PAM module, so that
+// failures are classified once and handled consistently on both sides of the gRPC connection.
+package authderrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/status"
+)
+
+// Code classifies an error.
+type Code int
+
+const (
+	// Unknown is the code of errors which are not classified.
+	Unknown Code = iota
+	// InvalidArgument is the code of errors caused by an invalid request.
+	InvalidArgument
+	// NotFound is the code of errors caused by a requested entry which does not exist.
+	NotFound
+	// PermissionDenied is the code of errors caused by a caller which is not allowed to perform the request.
+	PermissionDenied
+	// Unavailable is the code of errors caused by the daemon or a broker which can't be reached.
+	Unavailable
+	// Timeout is the code of errors caused by an operation which did not complete in time.
+	Timeout
+	// ResourceExhausted is the code of errors caused by a request which can be retried later.
+	ResourceExhausted
+	// Canceled is the code of errors caused by an operation cancelled by the caller.
+	Canceled
+	// Internal is the code of errors caused by an unexpected failure.
+	Internal
+)
+
+// String returns the name of the code.
+func (c Code) String() string {
+	switch c {
+	case InvalidArgument:
+		return "InvalidArgument"
+	case NotFound:
+		return "NotFound"
+	case PermissionDenied:
+		return "PermissionDenied"
+	case Unavailable:
+		return "Unavailable"
+	case Timeout:
+		return "Timeout"
+	case ResourceExhausted:
+		return "ResourceExhausted"
+	case Canceled:
+		return "Canceled"
+	case Internal:
+		return "Internal"
+	default:
+		return "Unknown"
+	}
+}
+
+// Error is an error classified with a code.
+type Error struct {
+	code Code
+	err  error
+	// sentinel is the sentinel error this error matches, if any.
+	sentinel *Sentinel
+}
+
+// New returns a new error with the given code and message.
+func New(code Code, msg string) error {
+	return Error{code: code, err: errors.New(msg)}
+}
+
+// Errorf returns a new error with the given code, formatting the message as fmt.Errorf does.
+func Errorf(code Code, format string, args ...any) error {
+	return Error{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// Wrap classifies err with the given code. It returns nil if err is nil.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return Error{code: code, err: err}
+}
+
+// Error implements the error interface.
+func (e Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the classified error.
+func (e Error) Unwrap() error {
+	return e.err
+}
+
+// Code returns the code of the error.
+func (e Error) Code() Code {
+	return e.code
+}
+
+// Is reports whether the error matches the target sentinel error, either because it was attached to it or because the
+// sentinel matches all the errors with its code.
+func (e Error) Is(target error) bool {
+	s, ok := target.(*Sentinel)
+	if !ok {
+		return false
+	}
+	return s == e.sentinel || s.reason == e.code.String()
+}
+
+// Coder is the interface implemented by the errors which classify themselves.
+type Coder interface {
+	Code() Code
+}
+
+// CodeOf returns the code of the first classified error in the err tree.
+// Context and gRPC errors are classified too. Unknown is returned if err is nil or is not classified.
+func CodeOf(err error) Code {
+	if err == nil {
+		return Unknown
+	}
+
+	var c Coder
+	if errors.As(err, &c) {
+		return c.Code()
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	}
+
+	if st, ok := status.FromError(err); ok {
+		return FromGRPCCode(st.Code())
+	}
+
+	return Unknown
+}
+
+// Is returns whether err is classified with the given code.
+func Is(err error, code Code) bool {
+	return CodeOf(err) == code
+}
@@ -0,0 +1,157 @@
+package authderrors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type selfClassifiedError struct{}
+
+func (selfClassifiedError) Error() string          { return "self classified error" }
+func (selfClassifiedError) Code() authderrors.Code { return authderrors.NotFound }
+
+func TestCodeOf(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err error
+
+		want authderrors.Code
+	}{
+		"Code_of_classified_error":         {err: authderrors.New(authderrors.InvalidArgument, "some error"), want: authderrors.InvalidArgument},
+		"Code_of_wrapped_classified_error": {err: fmt.Errorf("context: %w", authderrors.New(authderrors.Internal, "some error")), want: authderrors.Internal},
+		"Code_of_self_classified_error":    {err: fmt.Errorf("context: %w", selfClassifiedError{}), want: authderrors.NotFound},
+		"Code_of_outermost_classification": {err: authderrors.Wrap(authderrors.PermissionDenied, authderrors.New(authderrors.NotFound, "some error")), want: authderrors.PermissionDenied},
+		"Code_of_context_cancellation":     {err: fmt.Errorf("context: %w", context.Canceled), want: authderrors.Canceled},
+		"Code_of_context_deadline":         {err: context.DeadlineExceeded, want: authderrors.Timeout},
+		"Code_of_gRPC_error":               {err: status.Error(codes.ResourceExhausted, "some error"), want: authderrors.ResourceExhausted},
+		"Code_of_unmapped_gRPC_error":      {err: status.Error(codes.AlreadyExists, "some error"), want: authderrors.Unknown},
+
+		"Unknown_for_unclassified_error": {err: errors.New("some error"), want: authderrors.Unknown},
+		"Unknown_for_nil_error":          {want: authderrors.Unknown},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, authderrors.CodeOf(tc.err), "CodeOf returned an unexpected code")
+			require.True(t, authderrors.Is(tc.err, tc.want), "Is should match the code returned by CodeOf")
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, authderrors.Wrap(authderrors.Internal, nil), "Wrap should return nil for a nil error")
+
+	orig := errors.New("some error")
+	err := authderrors.Wrap(authderrors.Internal, orig)
+	require.ErrorIs(t, err, orig, "Wrap should keep the original error in the chain")
+	require.Equal(t, orig.Error(), err.Error(), "Wrap should not change the error message")
+}
+
+func TestGRPCMapping(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		code authderrors.Code
+
+		wantGRPCCode codes.Code
+	}{
+		"Map_Unknown":           {code: authderrors.Unknown, wantGRPCCode: codes.Unknown},
+		"Map_InvalidArgument":   {code: authderrors.InvalidArgument, wantGRPCCode: codes.InvalidArgument},
+		"Map_NotFound":          {code: authderrors.NotFound, wantGRPCCode: codes.NotFound},
+		"Map_PermissionDenied":  {code: authderrors.PermissionDenied, wantGRPCCode: codes.PermissionDenied},
+		"Map_Unavailable":       {code: authderrors.Unavailable, wantGRPCCode: codes.Unavailable},
+		"Map_Timeout":           {code: authderrors.Timeout, wantGRPCCode: codes.DeadlineExceeded},
+		"Map_ResourceExhausted": {code: authderrors.ResourceExhausted, wantGRPCCode: codes.ResourceExhausted},
+		"Map_Canceled":          {code: authderrors.Canceled, wantGRPCCode: codes.Canceled},
+		"Map_Internal":          {code: authderrors.Internal, wantGRPCCode: codes.Internal},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := fmt.Errorf("context: %w", authderrors.New(tc.code, "some error"))
+
+			st, ok := status.FromError(err)
+			require.True(t, ok, "Classified error should be convertible to a gRPC status")
+			require.Equal(t, tc.wantGRPCCode, st.Code(), "gRPC status has an unexpected code")
+			require.Equal(t, "context: some error", st.Message(), "gRPC status should keep the whole error message")
+
+			require.Equal(t, tc.code, authderrors.FromGRPCCode(st.Code()), "FromGRPCCode should map back to the original code")
+			require.Equal(t, tc.code, authderrors.CodeOf(st.Err()), "CodeOf should classify the gRPC error with the original code")
+		})
+	}
+}
+
+func TestSentinels(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err error
+
+		wantSentinel *authderrors.Sentinel
+		wantCode     authderrors.Code
+	}{
+		"Match_sentinel_the_error_is_attached_to": {
+			err:          authderrors.ErrCorrupted.Errorf("bad data"),
+			wantSentinel: authderrors.ErrCorrupted,
+			wantCode:     authderrors.Internal,
+		},
+		"Match_sentinel_the_wrapped_error_is_attached_to": {
+			err:          fmt.Errorf("context: %w", authderrors.ErrBrokerUnavailable.Wrap(errors.New("no reply"))),
+			wantSentinel: authderrors.ErrBrokerUnavailable,
+			wantCode:     authderrors.Unavailable,
+		},
+		"Match_sentinel_returned_as_is": {
+			err:          fmt.Errorf("context: %w", authderrors.ErrDisabled),
+			wantSentinel: authderrors.ErrDisabled,
+			wantCode:     authderrors.PermissionDenied,
+		},
+		"Match_sentinel_of_all_the_errors_with_its_code": {
+			err:          authderrors.New(authderrors.NotFound, "no such user"),
+			wantSentinel: authderrors.ErrNotFound,
+			wantCode:     authderrors.NotFound,
+		},
+
+		"No_sentinel_for_error_with_another_code": {err: authderrors.New(authderrors.Unavailable, "no daemon"), wantCode: authderrors.Unavailable},
+		"No_sentinel_for_unclassified_error":      {err: errors.New("some error")},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.wantSentinel, authderrors.SentinelOf(tc.err), "SentinelOf returned an unexpected sentinel")
+			require.Equal(t, tc.wantCode, authderrors.CodeOf(tc.err), "The error should be classified with the code of its sentinel")
+			if tc.wantSentinel != nil {
+				require.ErrorIs(t, tc.err, tc.wantSentinel, "The error should match its sentinel")
+			}
+
+			st, ok := status.FromError(tc.err)
+			if !ok {
+				return
+			}
+			require.Equal(t, tc.wantSentinel, authderrors.SentinelFromStatus(st), "The sentinel should be preserved in the gRPC status")
+		})
+	}
+}
+
+func TestSentinelWrapKeepsMessage(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, authderrors.ErrCorrupted.Wrap(nil), "Wrap should return nil for a nil error")
+
+	orig := errors.New("some error")
+	err := authderrors.ErrCorrupted.Wrap(orig)
+	require.ErrorIs(t, err, orig, "Wrap should keep the original error in the chain")
+	require.Equal(t, orig.Error(), err.Error(), "Wrap should not change the error message")
+}
@@ -0,0 +1,108 @@
+package authderrors
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the error information attached to the gRPC statuses generated by authd.
+const errorDomain = "authd"
+
+// GRPCStatus returns the gRPC status matching the error, so that it is sent to the client with the right code and
+// the sentinel error it matches.
+func (e Error) GRPCStatus() *status.Status {
+	reason := e.code.String()
+	if s := SentinelOf(e); s != nil {
+		reason = s.reason
+	}
+
+	st := status.New(ToGRPCCode(e.code), e.Error())
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Domain: errorDomain, Reason: reason}); err == nil {
+		return withInfo
+	}
+	return st
+}
+
+// GRPCStatus returns the gRPC status of the sentinel error, when it's returned as is.
+func (s *Sentinel) GRPCStatus() *status.Status {
+	return s.Wrap(errors.New(s.msg)).(Error).GRPCStatus()
+}
+
+// SentinelFromStatus returns the sentinel error the error sent by authd with the gRPC status matched, or nil if it
+// matched none.
+func SentinelFromStatus(st *status.Status) *Sentinel {
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != errorDomain {
+			continue
+		}
+		for _, s := range sentinels {
+			if s.reason == info.GetReason() {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// IsFromAuthd returns whether the gRPC status has been generated by authd from a classified error, and not by
+// the gRPC stack itself (as it happens when the daemon can't be reached).
+func IsFromAuthd(st *status.Status) bool {
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetDomain() == errorDomain {
+			return true
+		}
+	}
+	return false
+}
+
+// ToGRPCCode returns the gRPC code matching the given code.
+func ToGRPCCode(code Code) codes.Code {
+	switch code {
+	case InvalidArgument:
+		return codes.InvalidArgument
+	case NotFound:
+		return codes.NotFound
+	case PermissionDenied:
+		return codes.PermissionDenied
+	case Unavailable:
+		return codes.Unavailable
+	case Timeout:
+		return codes.DeadlineExceeded
+	case ResourceExhausted:
+		return codes.ResourceExhausted
+	case Canceled:
+		return codes.Canceled
+	case Internal:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
+
+// FromGRPCCode returns the code matching the given gRPC code.
+func FromGRPCCode(code codes.Code) Code {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return InvalidArgument
+	case codes.NotFound:
+		return NotFound
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return PermissionDenied
+	case codes.Unavailable:
+		return Unavailable
+	case codes.DeadlineExceeded:
+		return Timeout
+	case codes.ResourceExhausted:
+		return ResourceExhausted
+	case codes.Canceled:
+		return Canceled
+	case codes.Internal, codes.DataLoss, codes.Unimplemented:
+		return Internal
+	default:
+		return Unknown
+	}
+}
@@ -0,0 +1,77 @@
+package authderrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The sentinel errors which the consumers of the API check with errors.Is, instead of matching the error messages.
+// They are preserved through the gRPC connection between the daemon and its clients.
+var (
+	// ErrNotFound matches the errors caused by a requested user, group or other entry which does not exist, which are
+	// all the errors classified as NotFound.
+	ErrNotFound = &Sentinel{code: NotFound, reason: NotFound.String(), msg: "not found"}
+	// ErrCorrupted matches the errors caused by stored data which is inconsistent or can't be read.
+	ErrCorrupted = &Sentinel{code: Internal, reason: "Corrupted", msg: "corrupted data"}
+	// ErrDisabled matches the errors caused by a request to a feature which is disabled in the configuration.
+	ErrDisabled = &Sentinel{code: PermissionDenied, reason: "Disabled", msg: "disabled"}
+	// ErrBrokerUnavailable matches the errors caused by a broker which can't be reached.
+	ErrBrokerUnavailable = &Sentinel{code: Unavailable, reason: "BrokerUnavailable", msg: "broker unavailable"}
+	// ErrMissingRole matches the errors caused by a user denied the access to the machine because they don't have any
+	// of the roles it requires.
+	ErrMissingRole = &Sentinel{code: PermissionDenied, reason: "MissingRole", msg: "missing role"}
+	// ErrClientTooOld matches the errors caused by a request which needs a feature of the protocol the client doesn't
+	// support.
+	ErrClientTooOld = &Sentinel{code: InvalidArgument, reason: "ClientTooOld", msg: "client too old"}
+)
+
+// sentinels are all the sentinel errors, to find them from the reason of the gRPC statuses.
+var sentinels = []*Sentinel{ErrNotFound, ErrCorrupted, ErrDisabled, ErrBrokerUnavailable, ErrMissingRole, ErrClientTooOld}
+
+// Sentinel is an error that other errors can be attached to, so that they match it with errors.Is. It also classifies
+// them with its code.
+type Sentinel struct {
+	code Code
+	// reason identifies the sentinel in the gRPC statuses.
+	reason string
+	msg    string
+}
+
+// Error implements the error interface.
+func (s *Sentinel) Error() string {
+	return s.msg
+}
+
+// Code returns the code of the errors attached to the sentinel.
+func (s *Sentinel) Code() Code {
+	return s.code
+}
+
+// Wrap attaches err to the sentinel, without changing its message. It returns nil if err is nil.
+func (s *Sentinel) Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	return Error{code: s.code, err: err, sentinel: s}
+}
+
+// Errorf returns a new error attached to the sentinel, formatting the message as fmt.Errorf does.
+func (s *Sentinel) Errorf(format string, args ...any) error {
+	return s.Wrap(fmt.Errorf(format, args...))
+}
+
+// SentinelOf returns the sentinel error matched by err, or nil if it matches none.
+func SentinelOf(err error) *Sentinel {
+	// The sentinels matching all the errors of a code are only returned if no other sentinel matches.
+	var byCode *Sentinel
+	for _, s := range sentinels {
+		if !errors.Is(err, s) {
+			continue
+		}
+		if s.reason != CodeOf(err).String() {
+			return s
+		}
+		byCode = s
+	}
+	return byCode
+}
@@ -17,6 +17,27 @@ const (
 // Replies is the list of all possible authentication replies.
 var Replies = []string{Granted, Denied, Cancelled, Retry, Next}
 
+const (
+	// ErrorCodeKey is the key of the optional error code in the data returned with a denied or retry reply.
+	ErrorCodeKey = "error_code"
+
+	// ErrorCodeNetwork is the error code when the identity provider could not be reached.
+	ErrorCodeNetwork = "network"
+	// ErrorCodeInvalidCredentials is the error code when the provided credentials are not valid.
+	ErrorCodeInvalidCredentials = "invalid-credentials"
+	// ErrorCodeAccountLocked is the error code when the user account is locked or disabled.
+	ErrorCodeAccountLocked = "account-locked"
+	// ErrorCodeMFARequired is the error code when the user must set up or use multi-factor authentication.
+	ErrorCodeMFARequired = "mfa-required"
+	// ErrorCodeConsentDenied is the error code when the user (or an administrator) denied the required consent.
+	ErrorCodeConsentDenied = "consent-denied"
+)
+
+// ErrorCodes is the list of all the error codes a broker can return.
+var ErrorCodes = []string{
+	ErrorCodeNetwork, ErrorCodeInvalidCredentials, ErrorCodeAccountLocked, ErrorCodeMFARequired, ErrorCodeConsentDenied,
+}
+
 const (
 	// SessionModeLogin is used when the session is for user login.
 	// TODO: We can change this to "login" once all broker installations are updated to use the new name.
@@ -24,4 +45,8 @@ const (
 	// SessionModeChangePassword is used when the session is for changing the user password.
 	// TODO: We can change this to "change-password" once all broker installations are updated to use the new name.
 	SessionModeChangePassword = "passwd"
+	// SessionModeStepUp is used when the session re-authenticates, for a service requiring it (for example sudo),
+	// a user who already logged in with the broker. Only the brokers declaring step_up in their configuration
+	// are requested such sessions, they can then offer lighter authentication modes than for a login.
+	SessionModeStepUp = "step-up"
 )
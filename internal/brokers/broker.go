@@ -6,13 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"net/url"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/faults"
 	"github.com/ubuntu/authd/internal/users/types"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
@@ -38,10 +42,17 @@ type Broker struct {
 	ID                    string
 	Name                  string
 	BrandIconPath         string
+	usernamePatterns      []string
+	stepUp                bool
 	layoutValidators      map[string]map[string]layoutValidator
 	layoutValidatorsMu    *sync.Mutex
 	ongoingUserRequests   map[string]string
 	ongoingUserRequestsMu *sync.Mutex
+	// authProgress is the last progress reported by the broker for the sessions authenticating their user.
+	authProgress   map[string]*AuthProgress
+	authProgressMu *sync.Mutex
+
+	retryPolicy retryPolicy
 
 	brokerer brokerer
 }
@@ -60,35 +71,78 @@ func newBroker(ctx context.Context, configFile string, bus *dbus.Conn) (b Broker
 	name := LocalBrokerName
 	id := LocalBrokerName
 	var brandIcon string
+	var usernamePatterns []string
+	var stepUp bool
 	var broker brokerer
 
 	if configFile != "" {
 		log.Debugf(ctx, "Loading broker from %q", configFile)
-		broker, name, brandIcon, err = newDbusBroker(ctx, bus, configFile)
+		broker, name, brandIcon, usernamePatterns, stepUp, err = newDbusBroker(ctx, bus, configFile)
 		if err != nil {
 			return Broker{}, err
 		}
-		h := fnv.New32a()
-		// This can’t error out in Hash32 implementation.
-		_, _ = h.Write([]byte(name))
-		id = fmt.Sprint(h.Sum32())
+		id = IDFromName(name)
 	}
 
 	return Broker{
 		ID:                    id,
 		Name:                  name,
 		BrandIconPath:         brandIcon,
+		usernamePatterns:      usernamePatterns,
+		stepUp:                stepUp,
 		brokerer:              broker,
 		layoutValidators:      make(map[string]map[string]layoutValidator),
 		layoutValidatorsMu:    &sync.Mutex{},
 		ongoingUserRequests:   make(map[string]string),
 		ongoingUserRequestsMu: &sync.Mutex{},
+		authProgress:          make(map[string]*AuthProgress),
+		authProgressMu:        &sync.Mutex{},
+		retryPolicy:           defaultRetryPolicy,
 	}, nil
 }
 
+// IDFromName returns the ID of the broker with the given name, as published in its configuration file.
+func IDFromName(name string) string {
+	h := fnv.New32a()
+	// This can’t error out in Hash32 implementation.
+	_, _ = h.Write([]byte(name))
+	return fmt.Sprint(h.Sum32())
+}
+
+// MayOwnUser returns whether the user may be handled by the broker, according to the patterns of the usernames it
+// publishes in its configuration file. Usernames are matched case-insensitively.
+func (b Broker) MayOwnUser(username string) bool {
+	return len(b.usernamePatterns) == 0 || b.ClaimsUser(username)
+}
+
+// ClaimsUser returns whether the user matches one of the patterns of the usernames the broker publishes in its
+// configuration file. Usernames are matched case-insensitively.
+func (b Broker) ClaimsUser(username string) bool {
+	username = strings.ToLower(username)
+	return slices.ContainsFunc(b.usernamePatterns, func(p string) bool {
+		// The patterns are validated when loading the broker.
+		matched, _ := path.Match(strings.ToLower(p), username)
+		return matched
+	})
+}
+
+// SupportsStepUp returns whether the broker declares in its configuration file that it handles step-up sessions.
+func (b Broker) SupportsStepUp() bool {
+	return b.stepUp
+}
+
 // newSession calls the broker corresponding method, expanding sessionID with the broker ID prefix.
+// Step-up sessions are turned into login ones for the brokers not supporting them.
 func (b Broker) newSession(ctx context.Context, username, lang, mode string) (sessionID, encryptionKey string, err error) {
-	sessionID, encryptionKey, err = b.brokerer.NewSession(ctx, username, lang, mode)
+	if mode == auth.SessionModeStepUp && !b.stepUp {
+		log.Debugf(ctx, "Broker %q does not support step-up sessions, starting a login session for %q", b.Name, username)
+		mode = auth.SessionModeLogin
+	}
+
+	err = b.retryPolicy.retry(ctx, "NewSession", func() (err error) {
+		sessionID, encryptionKey, err = b.brokerer.NewSession(ctx, username, lang, mode)
+		return err
+	})
 	if err != nil {
 		return "", "", err
 	}
@@ -96,6 +150,9 @@ func (b Broker) newSession(ctx context.Context, username, lang, mode string) (se
 	if sessionID == "" {
 		return "", "", errors.New("no session ID provided by broker")
 	}
+	if _, _, err := encryption.ParseKey(encryptionKey); err != nil {
+		return "", "", err
+	}
 
 	b.ongoingUserRequestsMu.Lock()
 	b.ongoingUserRequests[sessionID] = username
@@ -142,22 +199,38 @@ func (b Broker) SelectAuthenticationMode(ctx context.Context, sessionID, authent
 func (b Broker) IsAuthenticated(ctx context.Context, sessionID, authenticationData string) (access string, data string, err error) {
 	sessionID = b.parseSessionID(sessionID)
 
+	stopProgress := b.trackAuthProgress(sessionID)
+	defer stopProgress()
+
 	// monitor ctx in goroutine to call cancel
 	done := make(chan struct{})
 	go func() {
-		access, data, err = b.brokerer.IsAuthenticated(ctx, sessionID, authenticationData)
+		var attempts int
+		err = b.retryPolicy.retry(ctx, "IsAuthenticated", func() (err error) {
+			if attempts++; attempts > 1 {
+				// Let the user know why the authentication takes longer, until the broker reports its own progress.
+				b.setAuthProgress(sessionID, AuthProgress{Message: brokerRetryingMessage, Percentage: -1})
+			}
+			access, data, err = b.brokerer.IsAuthenticated(ctx, sessionID, authenticationData)
+			if err != nil {
+				return err
+			}
+			d, err := faults.Inject(ctx, faults.BrokerResponse, []byte(data))
+			data = string(d)
+			return err
+		})
 		close(done)
 	}()
 
 	select {
 	case <-done:
-		if err != nil {
-			return "", "", err
-		}
 	case <-ctx.Done():
 		b.cancelIsAuthenticated(ctx, sessionID)
 		<-done
 	}
+	if err != nil {
+		return "", "", err
+	}
 
 	// Validate access authentication.
 	if !slices.Contains(auth.Replies, access) {
@@ -194,8 +267,29 @@ func (b Broker) IsAuthenticated(ctx context.Context, sessionID, authenticationDa
 		if _, err := unmarshalAndGetKey(data, "message"); err != nil {
 			return "", "", err
 		}
+		if data, err = validateErrorCode(ctx, data); err != nil {
+			return "", "", err
+		}
+		if access == auth.Retry {
+			if err := validateEncryptionKey(data); err != nil {
+				return "", "", err
+			}
+		}
 
-	case auth.Cancelled, auth.Next:
+	case auth.Next:
+		// The broker may only rotate its encryption key before the next step.
+		_, remainingData, err := encryption.KeyFromData(data)
+		if err != nil {
+			return "", "", err
+		}
+		if remainingData != "{}" {
+			return "", "", fmt.Errorf("access mode %q should not return any data, got: %v", access, data)
+		}
+		if err := validateEncryptionKey(data); err != nil {
+			return "", "", err
+		}
+
+	case auth.Cancelled:
 		if data != "{}" {
 			return "", "", fmt.Errorf("access mode %q should not return any data, got: %v", access, data)
 		}
@@ -346,6 +440,21 @@ func validateUserInfo(uInfo types.UserInfo) (err error) {
 		return fmt.Errorf("value provided for shell is not an absolute path: %s", uInfo.Shell)
 	}
 
+	// Validate avatar URL
+	if uInfo.AvatarURL != "" {
+		u, parseErr := url.Parse(uInfo.AvatarURL)
+		if parseErr != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("value provided for avatar_url is not an HTTP or HTTPS URL: %s", uInfo.AvatarURL)
+		}
+	}
+
+	// Validate GECOS subfields
+	if uInfo.GecosFields != nil {
+		if err := uInfo.GecosFields.Validate(); err != nil {
+			return fmt.Errorf("value provided for gecos_fields is invalid: %v", err)
+		}
+	}
+
 	// Validate groups
 	for _, g := range uInfo.Groups {
 		if g.Name == "" {
@@ -356,6 +465,45 @@ func validateUserInfo(uInfo types.UserInfo) (err error) {
 	return nil
 }
 
+// validateErrorCode checks the error code optionally returned by the broker, dropping it if unknown.
+func validateErrorCode(ctx context.Context, data string) (string, error) {
+	var returnedData map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &returnedData); err != nil {
+		return "", fmt.Errorf("response returned by the broker is not a valid json: %v\nBroker returned: %v", err, data)
+	}
+
+	rawCode, ok := returnedData[auth.ErrorCodeKey]
+	if !ok {
+		return data, nil
+	}
+
+	var code string
+	if err := json.Unmarshal(rawCode, &code); err != nil {
+		return "", fmt.Errorf("%q returned by the broker is not a string: %v", auth.ErrorCodeKey, string(rawCode))
+	}
+	if slices.Contains(auth.ErrorCodes, code) {
+		return data, nil
+	}
+
+	log.Warningf(ctx, "Ignoring unknown error code %q returned by the broker", code)
+	delete(returnedData, auth.ErrorCodeKey)
+	d, err := json.Marshal(returnedData)
+	if err != nil {
+		return "", fmt.Errorf("can't marshal broker data: %v", err)
+	}
+	return string(d), nil
+}
+
+// validateEncryptionKey checks that the algorithm of the new encryption key sent by the broker, if any, is supported.
+func validateEncryptionKey(data string) error {
+	key, _, err := encryption.KeyFromData(data)
+	if err != nil || key == "" {
+		return err
+	}
+	_, _, err = encryption.ParseKey(key)
+	return err
+}
+
 // unmarshalAndGetKey tries to unmarshal the content in data and returns the value of the requested key.
 func unmarshalAndGetKey(data, key string) (json.RawMessage, error) {
 	var returnedData map[string]json.RawMessage
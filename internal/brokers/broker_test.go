@@ -3,6 +3,7 @@ package brokers_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/brokers/auth"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/faults"
 	"github.com/ubuntu/authd/internal/testutils"
 	"github.com/ubuntu/authd/internal/testutils/golden"
 )
@@ -49,6 +51,8 @@ func TestNewBroker(t *testing.T) {
 	}{
 		"No_config_means_local_broker":                        {configFile: "-"},
 		"Successfully_create_broker_with_correct_config_file": {configFile: "valid.conf"},
+		"Successfully_create_broker_with_usernames":           {configFile: "valid_with_usernames.conf"},
+		"Successfully_create_broker_with_step_up":             {configFile: "valid_with_step_up.conf"},
 
 		// General config errors
 		"Error_when_config_file_is_invalid":     {configFile: "invalid.conf", wantErr: true},
@@ -59,6 +63,8 @@ func TestNewBroker(t *testing.T) {
 		"Error_when_config_does_not_have_brand_icon_field":  {configFile: "no_brand_icon.conf", wantErr: true},
 		"Error_when_config_does_not_have_dbus_name_field":   {configFile: "no_dbus_name.conf", wantErr: true},
 		"Error_when_config_does_not_have_dbus_object_field": {configFile: "no_dbus_object.conf", wantErr: true},
+		"Error_when_config_has_invalid_usernames":           {configFile: "invalid_usernames.conf", wantErr: true},
+		"Error_when_config_has_invalid_step_up":             {configFile: "invalid_step_up.conf", wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -91,6 +97,73 @@ func TestNewBroker(t *testing.T) {
 	}
 }
 
+func TestMayOwnUser(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		configFile string
+		username   string
+
+		want        bool
+		wantClaimed bool
+	}{
+		"Broker_without_usernames_may_own_any_user":  {configFile: "valid.conf", username: "user@example.com", want: true},
+		"Broker_may_own_user_matching_its_usernames": {configFile: "valid_with_usernames.conf", username: "user@example.com", want: true, wantClaimed: true},
+		"Usernames_are_matched_case_insensitively":   {configFile: "valid_with_usernames.conf", username: "Admin-User@example.org", want: true, wantClaimed: true},
+		"Local_broker_may_own_any_user":              {username: "user", want: true},
+		"Broker_can_not_own_user_not_matching":       {configFile: "valid_with_usernames.conf", username: "user@example.org"},
+		"Broker_can_not_own_user_matching_partially": {configFile: "valid_with_usernames.conf", username: "user@example.com.evil"},
+		"Broker_can_not_own_user_without_any_domain": {configFile: "valid_with_usernames.conf", username: "root"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			conn, err := testutils.GetSystemBusConnection(t)
+			require.NoError(t, err, "Setup: could not connect to system bus")
+
+			if tc.configFile != "" {
+				tc.configFile = filepath.Join(brokerConfFixtures, "valid_brokers", tc.configFile)
+			}
+			b, err := brokers.NewBroker(context.Background(), tc.configFile, conn)
+			require.NoError(t, err, "Setup: could not create broker")
+
+			require.Equal(t, tc.want, b.MayOwnUser(tc.username), "MayOwnUser should return the expected value")
+			require.Equal(t, tc.wantClaimed, b.ClaimsUser(tc.username), "ClaimsUser should return the expected value")
+		})
+	}
+}
+
+func TestSupportsStepUp(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		configFile string
+
+		want bool
+	}{
+		"Broker_declaring_step_up_supports_it":          {configFile: "valid_with_step_up.conf", want: true},
+		"Broker_not_declaring_step_up_does_not_support": {configFile: "valid.conf"},
+		"Local_broker_does_not_support_step_up":         {},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			conn, err := testutils.GetSystemBusConnection(t)
+			require.NoError(t, err, "Setup: could not connect to system bus")
+
+			if tc.configFile != "" {
+				tc.configFile = filepath.Join(brokerConfFixtures, "valid_brokers", tc.configFile)
+			}
+			b, err := brokers.NewBroker(context.Background(), tc.configFile, conn)
+			require.NoError(t, err, "Setup: could not create broker")
+
+			require.Equal(t, tc.want, b.SupportsStepUp(), "SupportsStepUp should return the expected value")
+		})
+	}
+}
+
 func TestGetAuthenticationModes(t *testing.T) {
 	t.Parallel()
 
@@ -220,6 +293,12 @@ func TestIsAuthenticated(t *testing.T) {
 		"No_error_when_broker_returns_userinfo_with_empty_gecos":           {sessionID: "IA_info_empty_gecos"},
 		"No_error_when_broker_returns_userinfo_with_group_with_empty_UGID": {sessionID: "IA_info_empty_ugid"},
 		"No_error_when_broker_returns_userinfo_with_mismatching_username":  {sessionID: "IA_info_mismatching_user_name"},
+		"No_error_when_broker_returns_userinfo_with_avatar_URL":            {sessionID: "IA_info_avatar_url"},
+		"No_error_when_broker_returns_userinfo_with_GECOS_fields":          {sessionID: "IA_info_gecos_fields"},
+		"No_error_when_broker_denies_with_error_code":                      {sessionID: "IA_denied_with_error_code"},
+		"Unknown_error_code_is_dropped":                                    {sessionID: "IA_denied_with_unknown_error_code"},
+		"No_error_when_broker_rotates_encryption_key_on_auth.Retry":        {sessionID: "IA_retry_with_encryption_key"},
+		"No_error_when_broker_rotates_encryption_key_on_auth.Next":         {sessionID: "IA_next_with_encryption_key"},
 
 		// broker errors
 		"Error_when_authenticating":                                           {sessionID: "IA_error"},
@@ -231,10 +310,14 @@ func TestIsAuthenticated(t *testing.T) {
 		"Error_when_broker_returns_userinfo_with_empty_group_name":            {sessionID: "IA_info_empty_group_name"},
 		"Error_when_broker_returns_userinfo_with_invalid_homedir":             {sessionID: "IA_info_invalid_home"},
 		"Error_when_broker_returns_userinfo_with_invalid_shell":               {sessionID: "IA_info_invalid_shell"},
+		"Error_when_broker_returns_userinfo_with_invalid_avatar_URL":          {sessionID: "IA_info_invalid_avatar_url"},
+		"Error_when_broker_returns_userinfo_with_invalid_GECOS_fields":        {sessionID: "IA_info_invalid_gecos_fields"},
 		"Error_when_broker_returns_data_on_auth.Next":                         {sessionID: "IA_next_with_data"},
 		"Error_when_broker_returns_data_on_auth.Cancelled":                    {sessionID: "IA_cancelled_with_data"},
 		"Error_when_broker_returns_no_data_on_auth.Denied":                    {sessionID: "IA_denied_without_data"},
 		"Error_when_broker_returns_no_data_on_auth.Retry":                     {sessionID: "IA_retry_without_data"},
+		"Error_when_broker_returns_invalid_error_code":                        {sessionID: "IA_retry_with_invalid_error_code"},
+		"Error_when_broker_rotates_to_an_unsupported_encryption_algorithm":    {sessionID: "IA_retry_with_unsupported_encryption_algorithm"},
 		"Error_when_calling_IsAuthenticated_a_second_time_without_cancelling": {sessionID: "IA_second_call", secondCall: true, cancelFirstCall: true},
 	}
 	for name, tc := range tests {
@@ -315,6 +398,61 @@ func TestCancelIsAuthenticated(t *testing.T) {
 	}
 }
 
+//nolint:paralleltest // The faults are global to the process: the test and its subtests can't run in parallel.
+func TestIsAuthenticatedWithFaults(t *testing.T) {
+	b := newBrokerForTests(t, "", "")
+
+	tests := map[string]struct {
+		fault   faults.Fault
+		timeout time.Duration
+
+		wantErr      error
+		wantRecovery bool
+	}{
+		"Successfully_authenticate_after_a_transient_broker_failure": {
+			fault:        faults.Fault{Err: errors.New("transient failure"), Times: 1},
+			wantRecovery: true,
+		},
+
+		"Error_when_broker_fails_to_respond":      {fault: faults.Fault{Err: errors.New("broker failure")}},
+		"Error_when_broker_response_is_corrupted": {fault: faults.Fault{CorruptJSON: true}},
+		"Error_when_broker_response_is_delayed_past_the_deadline": {
+			fault:   faults.Fault{Delay: time.Hour},
+			timeout: 100 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			faults.Set(faults.BrokerResponse, tc.fault)
+			t.Cleanup(func() { faults.Remove(faults.BrokerResponse) })
+
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			sessionID := prefixID(t, "success")
+			b.AddOngoingUserRequest(sessionID, t.Name()+testutils.IDSeparator+"success")
+
+			_, _, err := b.IsAuthenticated(ctx, sessionID, "password")
+			require.Error(t, err, "IsAuthenticated should return an error when the fault is injected")
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr, "IsAuthenticated should return the expected error")
+			}
+			if !tc.wantRecovery {
+				return
+			}
+
+			access, _, err := b.IsAuthenticated(context.Background(), sessionID, "password")
+			require.NoError(t, err, "IsAuthenticated should not return an error once the fault is gone")
+			require.Equal(t, auth.Granted, access, "IsAuthenticated should grant access once the fault is gone")
+		})
+	}
+}
+
 func TestUserPreCheck(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,448 @@
+// Package conformance checks that a broker implements the protocol expected by authd, exercising it through the
+// same code paths as the daemon.
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/log"
+)
+
+// Status is the status of a conformance check.
+type Status string
+
+const (
+	// Passed is the status of a check the broker conforms to.
+	Passed Status = "passed"
+	// Failed is the status of a check revealing a protocol violation.
+	Failed Status = "failed"
+	// Skipped is the status of a check which does not apply to the broker, or which depends on a failed one.
+	Skipped Status = "skipped"
+)
+
+// Result is the result of a conformance check.
+type Result struct {
+	Check   string `json:"check" yaml:"check"`
+	Status  Status `json:"status" yaml:"status"`
+	Details string `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+// DefaultUsername is the user the sessions are created for, if none is requested.
+const DefaultUsername = "authd-conformance-test"
+
+type options struct {
+	username      string
+	cancelTimeout time.Duration
+}
+
+// Option is the function signature used to tweak the conformance checks.
+type Option func(*options)
+
+// WithUsername creates the sessions for the given user.
+func WithUsername(username string) Option {
+	return func(o *options) {
+		o.username = username
+	}
+}
+
+// WithCancelTimeout sets how long the broker has to return once an authentication is cancelled.
+func WithCancelTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.cancelTimeout = timeout
+	}
+}
+
+// runner runs the conformance checks of a broker, collecting their results.
+type runner struct {
+	m      *brokers.Manager
+	broker *brokers.Broker
+	opts   options
+
+	results []Result
+}
+
+// Run runs the conformance checks of the broker with the given ID, which must be loaded by the manager.
+func Run(ctx context.Context, m *brokers.Manager, brokerID string, args ...Option) ([]Result, error) {
+	opts := options{
+		username:      DefaultUsername,
+		cancelTimeout: 10 * time.Second,
+	}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	i := slices.IndexFunc(m.AvailableBrokers(), func(b *brokers.Broker) bool { return b.ID == brokerID })
+	if i < 0 {
+		return nil, fmt.Errorf("broker %q is not available", brokerID)
+	}
+	r := runner{m: m, broker: m.AvailableBrokers()[i], opts: opts}
+
+	sessionID, encryptionKey, ok := r.checkNewSession(ctx)
+	if !ok {
+		r.skip("authentication_modes", "unsupported_layouts", "select_authentication_mode", "invalid_secret",
+			"invalid_challenge", "cancel_authentication", "unknown_session", "end_session")
+		return r.results, nil
+	}
+
+	modes, ok := r.checkAuthenticationModes(ctx, sessionID)
+	r.checkUnsupportedLayouts(ctx)
+	if !ok {
+		_ = m.EndSession(ctx, sessionID)
+		r.skip("select_authentication_mode", "invalid_secret", "invalid_challenge", "cancel_authentication",
+			"unknown_session", "end_session")
+		return r.results, nil
+	}
+
+	modesLayouts := r.checkSelectAuthenticationMode(ctx, sessionID, modes)
+	formMode := modeWithLayout(modesLayouts, func(l map[string]string) bool {
+		return l[layouts.Type] == layouts.Form && l[layouts.Entry] != ""
+	})
+	waitMode := modeWithLayout(modesLayouts, func(l map[string]string) bool {
+		return l[layouts.Wait] == layouts.True
+	})
+
+	r.checkInvalidSecret(ctx, formMode)
+	r.checkInvalidChallenge(ctx, formMode)
+	r.checkCancelAuthentication(ctx, waitMode)
+	r.checkUnknownSession(ctx)
+	r.checkEndSession(ctx, sessionID, encryptionKey)
+
+	return r.results, nil
+}
+
+func (r *runner) add(check string, status Status, format string, a ...any) {
+	details := fmt.Sprintf(format, a...)
+	log.Debugf(context.Background(), "Conformance check %q %s: %s", check, status, details)
+	r.results = append(r.results, Result{Check: check, Status: status, Details: details})
+}
+
+func (r *runner) skip(checks ...string) {
+	for _, c := range checks {
+		r.add(c, Skipped, "a previous check failed")
+	}
+}
+
+// newSession starts a session for a check, returning the function ending it.
+func (r *runner) newSession(ctx context.Context) (sessionID, encryptionKey string, endSession func(), err error) {
+	sessionID, encryptionKey, err = r.m.NewSession(ctx, r.broker.ID, r.opts.username, "C", auth.SessionModeLogin)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("could not start session: %v", err)
+	}
+	return sessionID, encryptionKey, func() { _ = r.m.EndSession(context.WithoutCancel(ctx), sessionID) }, nil
+}
+
+// selectMode starts a session with the authentication mode selected.
+func (r *runner) selectMode(ctx context.Context, mode string) (sessionID, encryptionKey string, endSession func(), err error) {
+	sessionID, encryptionKey, endSession, err = r.newSession(ctx)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if _, err = r.broker.GetAuthenticationModes(ctx, sessionID, SupportedUILayouts()); err != nil {
+		endSession()
+		return "", "", nil, fmt.Errorf("could not get authentication modes: %v", err)
+	}
+	if _, err = r.broker.SelectAuthenticationMode(ctx, sessionID, mode); err != nil {
+		endSession()
+		return "", "", nil, fmt.Errorf("could not select authentication mode %q: %v", mode, err)
+	}
+	return sessionID, encryptionKey, endSession, nil
+}
+
+func (r *runner) checkNewSession(ctx context.Context) (sessionID, encryptionKey string, ok bool) {
+	const check = "new_session"
+
+	sessionID, encryptionKey, err := r.m.NewSession(ctx, r.broker.ID, r.opts.username, "C", auth.SessionModeLogin)
+	if err != nil {
+		r.add(check, Failed, "could not start session: %v", err)
+		return "", "", false
+	}
+	if _, err := encryptSecret(encryptionKey, "secret"); err != nil {
+		r.add(check, Failed, "%v", err)
+		_ = r.m.EndSession(ctx, sessionID)
+		return "", "", false
+	}
+
+	r.add(check, Passed, "session started with a valid encryption key")
+	return sessionID, encryptionKey, true
+}
+
+func (r *runner) checkAuthenticationModes(ctx context.Context, sessionID string) (modes []string, ok bool) {
+	const check = "authentication_modes"
+
+	authModes, err := r.broker.GetAuthenticationModes(ctx, sessionID, SupportedUILayouts())
+	if err != nil {
+		r.add(check, Failed, "%v", err)
+		return nil, false
+	}
+	if len(authModes) == 0 {
+		r.add(check, Failed, "no authentication mode offered with all the layouts supported")
+		return nil, false
+	}
+	for _, m := range authModes {
+		id := m[layouts.ID]
+		if slices.Contains(modes, id) {
+			r.add(check, Failed, "authentication mode %q is offered more than once", id)
+			return nil, false
+		}
+		modes = append(modes, id)
+	}
+
+	r.add(check, Passed, "%d authentication modes offered", len(modes))
+	return modes, true
+}
+
+func (r *runner) checkUnsupportedLayouts(ctx context.Context) {
+	const check = "unsupported_layouts"
+
+	sessionID, _, endSession, err := r.newSession(ctx)
+	if err != nil {
+		r.add(check, Failed, "%v", err)
+		return
+	}
+	defer endSession()
+
+	authModes, err := r.broker.GetAuthenticationModes(ctx, sessionID, nil)
+	if err != nil {
+		r.add(check, Failed, "%v", err)
+		return
+	}
+	if len(authModes) > 0 {
+		r.add(check, Failed, "%d authentication modes offered while no layout is supported", len(authModes))
+		return
+	}
+	r.add(check, Passed, "no authentication mode offered while no layout is supported")
+}
+
+func (r *runner) checkSelectAuthenticationMode(ctx context.Context, sessionID string, modes []string) map[string]map[string]string {
+	const check = "select_authentication_mode"
+
+	modesLayouts := make(map[string]map[string]string)
+	for _, mode := range modes {
+		layout, err := r.broker.SelectAuthenticationMode(ctx, sessionID, mode)
+		if err != nil {
+			r.add(check, Failed, "authentication mode %q: %v", mode, err)
+			return modesLayouts
+		}
+		modesLayouts[mode] = layout
+	}
+
+	r.add(check, Passed, "all the authentication modes have a supported layout")
+	return modesLayouts
+}
+
+func (r *runner) checkInvalidSecret(ctx context.Context, mode string) {
+	const check = "invalid_secret"
+
+	if mode == "" {
+		r.add(check, Skipped, "no authentication mode with a form entry")
+		return
+	}
+
+	sessionID, encryptionKey, endSession, err := r.selectMode(ctx, mode)
+	if err != nil {
+		r.add(check, Failed, "%v", err)
+		return
+	}
+	defer endSession()
+
+	secret, err := encryptSecret(encryptionKey, "authd-conformance-invalid-secret")
+	if err != nil {
+		r.add(check, Failed, "%v", err)
+		return
+	}
+	access, _, err := r.broker.IsAuthenticated(ctx, sessionID, fmt.Sprintf(`{"challenge":%q}`, secret))
+	if err != nil {
+		r.add(check, Failed, "authentication mode %q: %v", mode, err)
+		return
+	}
+	if access != auth.Retry && access != auth.Denied {
+		r.add(check, Failed, "authentication mode %q replied %q to an invalid secret, expected %q or %q",
+			mode, access, auth.Retry, auth.Denied)
+		return
+	}
+	r.add(check, Passed, "authentication mode %q replied %q to an invalid secret", mode, access)
+}
+
+func (r *runner) checkInvalidChallenge(ctx context.Context, mode string) {
+	const check = "invalid_challenge"
+
+	if mode == "" {
+		r.add(check, Skipped, "no authentication mode with a form entry")
+		return
+	}
+
+	sessionID, _, endSession, err := r.selectMode(ctx, mode)
+	if err != nil {
+		r.add(check, Failed, "%v", err)
+		return
+	}
+	defer endSession()
+
+	access, _, err := r.broker.IsAuthenticated(ctx, sessionID, `{"challenge":"not encrypted"}`)
+	if err == nil && access == auth.Granted {
+		r.add(check, Failed, "authentication mode %q granted access with a secret which is not encrypted", mode)
+		return
+	}
+	r.add(check, Passed, "authentication mode %q refused a secret which is not encrypted", mode)
+}
+
+func (r *runner) checkCancelAuthentication(ctx context.Context, mode string) {
+	const check = "cancel_authentication"
+
+	if mode == "" {
+		r.add(check, Skipped, "no authentication mode waiting for the broker")
+		return
+	}
+
+	sessionID, _, endSession, err := r.selectMode(ctx, mode)
+	if err != nil {
+		r.add(check, Failed, "%v", err)
+		return
+	}
+	defer endSession()
+
+	authCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type reply struct {
+		access string
+		err    error
+	}
+	done := make(chan reply, 1)
+	go func() {
+		access, _, err := r.broker.IsAuthenticated(authCtx, sessionID, `{"wait":"true"}`)
+		done <- reply{access, err}
+	}()
+
+	// Give some time to the broker to start waiting before cancelling.
+	select {
+	case rep := <-done:
+		r.add(check, Failed, "authentication mode %q replied %q (%v) without waiting", mode, rep.access, rep.err)
+		return
+	case <-time.After(500 * time.Millisecond):
+	}
+	cancel()
+
+	select {
+	case rep := <-done:
+		if rep.err == nil && rep.access != auth.Cancelled {
+			r.add(check, Failed, "authentication mode %q replied %q once cancelled, expected %q", mode, rep.access, auth.Cancelled)
+			return
+		}
+	case <-time.After(r.opts.cancelTimeout):
+		r.add(check, Failed, "authentication mode %q did not return %s after being cancelled", mode, r.opts.cancelTimeout)
+		return
+	}
+	r.add(check, Passed, "authentication mode %q returned once cancelled", mode)
+}
+
+func (r *runner) checkUnknownSession(ctx context.Context) {
+	const check = "unknown_session"
+
+	sessionID := fmt.Sprintf("%s-authd-conformance-unknown-session", r.broker.ID)
+	if access, _, err := r.broker.IsAuthenticated(ctx, sessionID, `{"challenge":"secret"}`); err == nil {
+		r.add(check, Failed, "authenticating an unknown session replied %q instead of failing", access)
+		return
+	}
+	r.add(check, Passed, "authenticating an unknown session failed")
+}
+
+func (r *runner) checkEndSession(ctx context.Context, sessionID, encryptionKey string) {
+	const check = "end_session"
+
+	if err := r.m.EndSession(ctx, sessionID); err != nil {
+		r.add(check, Failed, "%v", err)
+		return
+	}
+
+	// The session is not known anymore by the manager, so let's call the broker directly.
+	secret, err := encryptSecret(encryptionKey, "secret")
+	if err != nil {
+		r.add(check, Failed, "%v", err)
+		return
+	}
+	if access, _, err := r.broker.IsAuthenticated(ctx, sessionID, fmt.Sprintf(`{"challenge":%q}`, secret)); err == nil {
+		r.add(check, Failed, "authenticating an ended session replied %q instead of failing", access)
+		return
+	}
+	r.add(check, Passed, "session ended")
+}
+
+// modeWithLayout returns the first authentication mode, in ascii order, whose layout matches.
+func modeWithLayout(modesLayouts map[string]map[string]string, match func(map[string]string) bool) string {
+	var modes []string
+	for mode, l := range modesLayouts {
+		if match(l) {
+			modes = append(modes, mode)
+		}
+	}
+	if len(modes) == 0 {
+		return ""
+	}
+	slices.Sort(modes)
+	return modes[0]
+}
+
+// SupportedUILayouts returns all the layouts supported by the authd user interfaces, with all their fields.
+func SupportedUILayouts() []map[string]string {
+	allEntries := layouts.OptionalItems(entries.Chars, entries.CharsPassword, entries.Digits, entries.DigitsPassword)
+	return []map[string]string{
+		{
+			layouts.Type:              layouts.Form,
+			layouts.Label:             layouts.Required,
+			layouts.Entry:             allEntries,
+			layouts.Wait:              layouts.OptionalWithBooleans,
+			layouts.Button:            layouts.Optional,
+			layouts.Code:              layouts.Optional,
+			layouts.CodeLength:        layouts.Optional,
+			layouts.CodeValidity:      layouts.Optional,
+			layouts.CachedCredentials: layouts.OptionalWithBooleans,
+		},
+		{
+			layouts.Type:          layouts.QrCode,
+			layouts.Label:         layouts.Optional,
+			layouts.Content:       layouts.Required,
+			layouts.Code:          layouts.Optional,
+			layouts.Wait:          layouts.RequiredWithBooleans,
+			layouts.Button:        layouts.Optional,
+			layouts.RendersQrCode: layouts.OptionalWithBooleans,
+		},
+		{
+			layouts.Type:        layouts.NewPassword,
+			layouts.Label:       layouts.Required,
+			layouts.Entry:       allEntries,
+			layouts.Button:      layouts.Optional,
+			layouts.MinStrength: layouts.OptionalItems("0", "1", "2", "3", "4"),
+		},
+		{
+			layouts.Type:            layouts.SmartCard,
+			layouts.Label:           layouts.Optional,
+			layouts.Content:         layouts.Optional,
+			layouts.Entry:           allEntries,
+			layouts.Button:          layouts.Optional,
+			layouts.Wait:            layouts.OptionalWithBooleans,
+			layouts.SmartCardAction: layouts.RequiredItems(layouts.SmartCardInsert, layouts.SmartCardPIN, layouts.SmartCardTouch),
+		},
+	}
+}
+
+// encryptSecret encrypts the secret with the encryption key sent by the broker, like the PAM module does.
+func encryptSecret(encryptionKey, secret string) (string, error) {
+	algorithm, encodedKey, err := encryption.ParseKey(encryptionKey)
+	if err != nil {
+		return "", err
+	}
+	key, err := encryption.NewPublicKey(algorithm, encodedKey)
+	if err != nil {
+		return "", err
+	}
+	return key.Encrypt(secret)
+}
@@ -0,0 +1,224 @@
+package conformance_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/brokers/sdk"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/brokers/conformance"
+	"github.com/ubuntu/authd/internal/testutils"
+	"github.com/ubuntu/authd/log"
+)
+
+// testBroker is a broker granting access with the password "goodpass", which can be configured to violate the
+// protocol.
+type testBroker struct {
+	grantAnySecret bool
+	ignoreLayouts  bool
+	ignoreCancel   bool
+	duplicateModes bool
+	noWaitMode     bool
+}
+
+type sessionState struct{}
+
+func (b testBroker) NewSession(context.Context, *sdk.Session[sessionState]) error {
+	return nil
+}
+
+func (b testBroker) AuthenticationModes(_ context.Context, _ *sdk.Session[sessionState], supported sdk.SupportedLayouts) ([]sdk.AuthenticationMode, error) {
+	var modes []sdk.AuthenticationMode
+	if b.ignoreLayouts || supported.Supports(sdk.LayoutForm, sdk.EntryCharsPassword) {
+		modes = append(modes, sdk.AuthenticationMode{ID: "password", Label: "Password"})
+		if b.duplicateModes {
+			modes = append(modes, sdk.AuthenticationMode{ID: "password", Label: "Password again"})
+		}
+	}
+	if !b.noWaitMode && (b.ignoreLayouts || supported.Supports(sdk.LayoutQrCode, "")) {
+		modes = append(modes, sdk.AuthenticationMode{ID: "qrcode", Label: "Login with a QR code"})
+	}
+	return modes, nil
+}
+
+func (b testBroker) SelectAuthenticationMode(_ context.Context, _ *sdk.Session[sessionState], modeID string) (sdk.UILayout, error) {
+	switch modeID {
+	case "password":
+		return sdk.FormLayout("Password", sdk.EntryCharsPassword), nil
+	case "qrcode":
+		return sdk.QrCodeLayout("Scan the QR code", "https://example.com", "1337"), nil
+	}
+	return sdk.UILayout{}, fmt.Errorf("unknown authentication mode %q", modeID)
+}
+
+func (b testBroker) IsAuthenticated(ctx context.Context, s *sdk.Session[sessionState], data sdk.AuthenticationData) (sdk.Reply, error) {
+	if s.AuthenticationMode == "qrcode" {
+		if b.ignoreCancel {
+			time.Sleep(2 * time.Second)
+			return sdk.Denied("QR code not scanned"), nil
+		}
+		<-ctx.Done()
+		return sdk.Cancelled(), nil
+	}
+
+	if !b.grantAnySecret && data.Secret != "goodpass" {
+		return sdk.Retry("Invalid password"), nil
+	}
+	return sdk.Granted(sdk.UserInfo{
+		Name:   s.Username,
+		Dir:    "/home/" + s.Username,
+		Shell:  "/bin/sh",
+		Groups: []sdk.Group{{Name: "group-" + s.Username, UGID: "ugid-" + s.Username}},
+	}), nil
+}
+
+func (b testBroker) UserPreCheck(context.Context, string) (sdk.UserInfo, error) {
+	return sdk.UserInfo{}, nil
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	allPassed := map[string]conformance.Status{
+		"new_session":                conformance.Passed,
+		"authentication_modes":       conformance.Passed,
+		"unsupported_layouts":        conformance.Passed,
+		"select_authentication_mode": conformance.Passed,
+		"invalid_secret":             conformance.Passed,
+		"invalid_challenge":          conformance.Passed,
+		"cancel_authentication":      conformance.Passed,
+		"unknown_session":            conformance.Passed,
+		"end_session":                conformance.Passed,
+	}
+	with := func(statuses map[string]conformance.Status) map[string]conformance.Status {
+		r := make(map[string]conformance.Status)
+		for k, v := range allPassed {
+			r[k] = v
+		}
+		for k, v := range statuses {
+			r[k] = v
+		}
+		return r
+	}
+
+	tests := map[string]struct {
+		broker   testBroker
+		brokerID string
+
+		want    map[string]conformance.Status
+		wantErr bool
+	}{
+		"Conforming_broker_passes_all_checks": {want: allPassed},
+		"Checks_requiring_a_wait_mode_are_skipped_without_one": {
+			broker: testBroker{noWaitMode: true},
+			want:   with(map[string]conformance.Status{"cancel_authentication": conformance.Skipped}),
+		},
+
+		"Broker_granting_any_secret_fails": {
+			broker: testBroker{grantAnySecret: true},
+			want:   with(map[string]conformance.Status{"invalid_secret": conformance.Failed}),
+		},
+		"Broker_ignoring_the_supported_layouts_fails": {
+			broker: testBroker{ignoreLayouts: true},
+			want:   with(map[string]conformance.Status{"unsupported_layouts": conformance.Failed}),
+		},
+		"Broker_ignoring_cancellation_fails": {
+			broker: testBroker{ignoreCancel: true},
+			want:   with(map[string]conformance.Status{"cancel_authentication": conformance.Failed}),
+		},
+		"Broker_offering_duplicate_modes_fails_and_skips_dependent_checks": {
+			broker: testBroker{duplicateModes: true},
+			want: map[string]conformance.Status{
+				"new_session":                conformance.Passed,
+				"authentication_modes":       conformance.Failed,
+				"unsupported_layouts":        conformance.Passed,
+				"select_authentication_mode": conformance.Skipped,
+				"invalid_secret":             conformance.Skipped,
+				"invalid_challenge":          conformance.Skipped,
+				"cancel_authentication":      conformance.Skipped,
+				"unknown_session":            conformance.Skipped,
+				"end_session":                conformance.Skipped,
+			},
+		},
+
+		"Error_when_broker_is_not_available": {brokerID: "does-not-exist", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m, brokerID := startBroker(t, name, tc.broker)
+			if tc.brokerID != "" {
+				brokerID = tc.brokerID
+			}
+
+			results, err := conformance.Run(context.Background(), m, brokerID,
+				conformance.WithUsername("user-"+strings.ToLower(name)),
+				conformance.WithCancelTimeout(500*time.Millisecond))
+			if tc.wantErr {
+				require.Error(t, err, "Run should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Run should not return an error, but did")
+
+			got := make(map[string]conformance.Status)
+			for _, r := range results {
+				require.NotContains(t, got, r.Check, "Run should return each check once")
+				got[r.Check] = r.Status
+			}
+			require.Equal(t, tc.want, got, "Run should return the expected statuses, got %v", results)
+		})
+	}
+}
+
+// startBroker exports the test broker on the bus and returns the manager loading it, with its ID.
+func startBroker(t *testing.T, name string, b testBroker) (*brokers.Manager, string) {
+	t.Helper()
+
+	s, err := sdk.NewService[sessionState](b)
+	require.NoError(t, err, "Setup: NewService should not return an error, but did")
+
+	conn, err := testutils.GetSystemBusConnection(t)
+	require.NoError(t, err, "Setup: could not connect to the system bus")
+	t.Cleanup(func() { conn.Close() })
+
+	cfg := sdk.Config{
+		Name:       "Conformance broker " + name,
+		BrandIcon:  "/usr/share/icons/conformance.png",
+		DbusName:   "com.ubuntu.authd.ConformanceBroker" + strings.ReplaceAll(name, "_", ""),
+		DbusObject: "/com/ubuntu/authd/ConformanceBroker",
+	}
+	require.NoError(t, s.Export(conn, cfg), "Setup: Export should not return an error, but did")
+
+	dir := t.TempDir()
+	require.NoError(t, sdk.WriteConfig(filepath.Join(dir, "conformance.conf"), cfg), "Setup: WriteConfig should not return an error, but did")
+
+	m, err := brokers.NewManager(context.Background(), dir, nil)
+	require.NoError(t, err, "Setup: could not create the brokers manager")
+	for _, b := range m.AvailableBrokers() {
+		if b.Name == cfg.Name {
+			return m, b.ID
+		}
+	}
+	require.Fail(t, "Setup: conformance broker was not loaded")
+	return nil, ""
+}
+
+func TestMain(m *testing.M) {
+	log.SetLevel(log.DebugLevel)
+
+	cleanup, err := testutils.StartSystemBusMock()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer cleanup()
+
+	m.Run()
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 
 	"github.com/godbus/dbus/v5"
+	"github.com/ubuntu/authd/internal/authderrors"
 	"github.com/ubuntu/authd/internal/services/errmessages"
+	"github.com/ubuntu/authd/internal/tracing"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/ini.v1"
 )
 
@@ -22,40 +26,56 @@ type dbusBroker struct {
 }
 
 // newDbusBroker returns a dbus broker and broker attributes from its configuration file.
-func newDbusBroker(ctx context.Context, bus *dbus.Conn, configFile string) (b dbusBroker, name, brandIcon string, err error) {
+func newDbusBroker(ctx context.Context, bus *dbus.Conn, configFile string) (b dbusBroker, name, brandIcon string, usernamePatterns []string, stepUp bool, err error) {
 	defer decorate.OnError(&err, "D-Bus broker from configuration file: %q", configFile)
 
 	log.Debugf(ctx, "D-Bus broker configuration at %q", configFile)
 
 	cfg, err := ini.Load(configFile)
 	if err != nil {
-		return b, "", "", fmt.Errorf("could not read ini configuration for broker %v", err)
+		return b, "", "", nil, false, fmt.Errorf("could not read ini configuration for broker %v", err)
 	}
 
 	nameVal, err := cfg.Section("authd").GetKey("name")
 	if err != nil {
-		return b, "", "", fmt.Errorf("missing field for broker: %v", err)
+		return b, "", "", nil, false, fmt.Errorf("missing field for broker: %v", err)
 	}
 
 	brandIconVal, err := cfg.Section("authd").GetKey("brand_icon")
 	if err != nil {
-		return b, "", "", fmt.Errorf("missing field for broker: %v", err)
+		return b, "", "", nil, false, fmt.Errorf("missing field for broker: %v", err)
 	}
 
 	dbusName, err := cfg.Section("authd").GetKey("dbus_name")
 	if err != nil {
-		return b, "", "", fmt.Errorf("missing field for broker: %v", err)
+		return b, "", "", nil, false, fmt.Errorf("missing field for broker: %v", err)
 	}
 
 	objectName, err := cfg.Section("authd").GetKey("dbus_object")
 	if err != nil {
-		return b, "", "", fmt.Errorf("missing field for broker: %v", err)
+		return b, "", "", nil, false, fmt.Errorf("missing field for broker: %v", err)
+	}
+
+	// The usernames owned by the broker are optional: without them, the broker may own any user.
+	usernamePatterns = cfg.Section("authd").Key("usernames").Strings(",")
+	for _, p := range usernamePatterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return b, "", "", nil, false, fmt.Errorf("invalid username pattern %q for broker: %v", p, err)
+		}
+	}
+
+	// Step-up sessions are only requested to the brokers supporting them.
+	if cfg.Section("authd").HasKey("step_up") {
+		stepUp, err = cfg.Section("authd").Key("step_up").Bool()
+		if err != nil {
+			return b, "", "", nil, false, fmt.Errorf("invalid step_up value for broker: %v", err)
+		}
 	}
 
 	return dbusBroker{
 		name:       nameVal.String(),
 		dbusObject: bus.Object(dbusName.String(), dbus.ObjectPath(objectName.String())),
-	}, nameVal.String(), brandIconVal.String(), nil
+	}, nameVal.String(), brandIconVal.String(), usernamePatterns, stepUp, nil
 }
 
 // NewSession calls the corresponding method on the broker bus and returns the session ID and encryption key.
@@ -98,9 +118,9 @@ func (b dbusBroker) SelectAuthenticationMode(ctx context.Context, sessionID, aut
 }
 
 // IsAuthenticated calls the corresponding method on the broker bus and returns the user information and access.
-func (b dbusBroker) IsAuthenticated(_ context.Context, sessionID, authenticationData string) (access, data string, err error) {
+func (b dbusBroker) IsAuthenticated(ctx context.Context, sessionID, authenticationData string) (access, data string, err error) {
 	// We don’t want to cancel the context when the parent call is cancelled.
-	call, err := b.call(context.Background(), "IsAuthenticated", sessionID, authenticationData)
+	call, err := b.call(context.WithoutCancel(ctx), "IsAuthenticated", sessionID, authenticationData)
 	if err != nil {
 		return "", "", err
 	}
@@ -122,7 +142,7 @@ func (b dbusBroker) EndSession(ctx context.Context, sessionID string) (err error
 // CancelIsAuthenticated calls the corresponding method on the broker bus.
 func (b dbusBroker) CancelIsAuthenticated(ctx context.Context, sessionID string) {
 	// We don’t want to cancel the context when the parent call is cancelled.
-	if _, err := b.call(context.Background(), "CancelIsAuthenticated", sessionID); err != nil {
+	if _, err := b.call(context.WithoutCancel(ctx), "CancelIsAuthenticated", sessionID); err != nil {
 		log.Errorf(ctx, "could not cancel IsAuthenticated call for session %q: %v", sessionID, err)
 	}
 }
@@ -142,17 +162,28 @@ func (b dbusBroker) UserPreCheck(ctx context.Context, username string) (userinfo
 
 // call is an abstraction over dbus calls to ensure we wrap the returned error to an ErrorToDisplay.
 // All wrapped errors will be logged, but not returned to the UI.
-func (b dbusBroker) call(ctx context.Context, method string, args ...interface{}) (*dbus.Call, error) {
+func (b dbusBroker) call(ctx context.Context, method string, args ...interface{}) (_ *dbus.Call, err error) {
+	ctx, span := tracing.Start(ctx, "broker."+method, attribute.String("broker", b.name))
+	defer tracing.End(span, &err)
+
 	dbusMethod := DbusInterface + "." + method
 	call := b.dbusObject.CallWithContext(ctx, dbusMethod, 0, args...)
 	if err := call.Err; err != nil {
 		var dbusError dbus.Error
 		// If the broker is not available ib dbus, the original "method was not provided by any .service files" isn't
 		// user-friendly, so we replace it with a better message.
+		transient, undelivered := isTransientDBusError(err), isUndeliveredDBusError(err)
 		if errors.As(err, &dbusError) && dbusError.Name == "org.freedesktop.DBus.Error.ServiceUnknown" {
 			err = fmt.Errorf("couldn't connect to broker %q. Is it running?", b.name)
 		}
-		return nil, errmessages.NewToDisplayError(err)
+		err = errmessages.NewToDisplayError(err)
+		if undelivered {
+			err = undeliveredError{err: err}
+		}
+		if transient {
+			return nil, authderrors.ErrBrokerUnavailable.Wrap(err)
+		}
+		return nil, err
 	}
 
 	return call, nil
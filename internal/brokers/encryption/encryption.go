@@ -0,0 +1,154 @@
+// Package encryption handles the encryption of the secrets sent to the brokers.
+//
+// Brokers send their encryption key when a session starts, and may send a new one with a retry or next reply to
+// rotate it. The key is a base64 encoded PKIX public key, optionally prefixed by the algorithm to use and a colon
+// (for example "rsa-oaep-sha256:MIIBIjANBg..."). Keys without prefix use the DefaultAlgorithm.
+package encryption
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+)
+
+const (
+	// RSAOAEPSHA512 is RSA-OAEP with SHA-512, the algorithm used by the brokers not specifying any.
+	RSAOAEPSHA512 = "rsa-oaep-sha512"
+	// RSAOAEPSHA256 is RSA-OAEP with SHA-256.
+	RSAOAEPSHA256 = "rsa-oaep-sha256"
+
+	// DefaultAlgorithm is the algorithm of the keys sent without algorithm identifier.
+	DefaultAlgorithm = RSAOAEPSHA512
+)
+
+// Algorithms is the list of all the supported algorithms.
+var Algorithms = []string{RSAOAEPSHA512, RSAOAEPSHA256}
+
+// KeyDataKey is the key of the new encryption key in the data returned by the broker with a retry or next reply.
+const KeyDataKey = "encryption_key"
+
+// ParseKey splits the encryption key sent by a broker into its algorithm and its base64 encoded public key.
+// It returns an error if the algorithm is not supported, the public key itself is only checked by NewPublicKey.
+func ParseKey(key string) (algorithm, encodedKey string, err error) {
+	algorithm, encodedKey, found := strings.Cut(key, ":")
+	if !found {
+		return DefaultAlgorithm, key, nil
+	}
+	if err := checkAlgorithm(algorithm); err != nil {
+		return "", "", err
+	}
+	return algorithm, encodedKey, nil
+}
+
+// KeyFromData returns the new encryption key included in the data of a broker reply, if any, and the data without it.
+func KeyFromData(data string) (key, remainingData string, err error) {
+	var d map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &d); err != nil {
+		return "", "", fmt.Errorf("response returned by the broker is not a valid json: %v", err)
+	}
+
+	rawKey, ok := d[KeyDataKey]
+	if !ok {
+		return "", data, nil
+	}
+	if err := json.Unmarshal(rawKey, &key); err != nil || key == "" {
+		return "", "", fmt.Errorf("%q returned by the broker is not a non empty string: %s", KeyDataKey, rawKey)
+	}
+
+	delete(d, KeyDataKey)
+	r, err := json.Marshal(d)
+	if err != nil {
+		return "", "", err
+	}
+	return key, string(r), nil
+}
+
+// PublicKey is the public key of a broker, used to encrypt the secrets sent to it.
+type PublicKey struct {
+	algorithm string
+	key       *rsa.PublicKey
+}
+
+// NewPublicKey returns the public key for the algorithm from its base64 encoded PKIX form.
+func NewPublicKey(algorithm, encodedKey string) (*PublicKey, error) {
+	if err := checkAlgorithm(algorithm); err != nil {
+		return nil, err
+	}
+
+	pubASN1, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key sent by broker is not a valid base64 encoded string: %v", err)
+	}
+	pubKey, err := x509.ParsePKIXPublicKey(pubASN1)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key sent by broker is not valid: %v", err)
+	}
+	rsaPublicKey, ok := pubKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("expected encryption key sent by broker to be RSA public key, got %T", pubKey)
+	}
+
+	return &PublicKey{algorithm: algorithm, key: rsaPublicKey}, nil
+}
+
+// Algorithm returns the algorithm the key encrypts with.
+func (k PublicKey) Algorithm() string {
+	return k.algorithm
+}
+
+// Encrypt encrypts the secret, returning it base64 encoded.
+func (k PublicKey) Encrypt(secret string) (string, error) {
+	return k.EncryptBytes([]byte(secret))
+}
+
+// EncryptBytes encrypts the secret, returning it base64 encoded. The secret is not retained, so that the caller can
+// wipe it once encrypted.
+func (k PublicKey) EncryptBytes(secret []byte) (string, error) {
+	ciphertext, err := rsa.EncryptOAEP(oaepHash(k.algorithm), rand.Reader, k.key, secret, nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts the base64 encoded secret encrypted with the public key of the private key, using the algorithm.
+// This is the operation done by the brokers.
+func Decrypt(algorithm string, privateKey *rsa.PrivateKey, secret string) (string, error) {
+	if err := checkAlgorithm(algorithm); err != nil {
+		return "", err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return "", fmt.Errorf("secret is not base64 encoded: %w", err)
+	}
+	plaintext, err := rsa.DecryptOAEP(oaepHash(algorithm), nil, privateKey, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// checkAlgorithm returns an error if the algorithm is not supported.
+func checkAlgorithm(algorithm string) error {
+	if !slices.Contains(Algorithms, algorithm) {
+		return fmt.Errorf("unsupported encryption algorithm %q, expected one of %s", algorithm, strings.Join(Algorithms, ", "))
+	}
+	return nil
+}
+
+// oaepHash returns the hash used by the OAEP algorithm.
+func oaepHash(algorithm string) hash.Hash {
+	if algorithm == RSAOAEPSHA256 {
+		return sha256.New()
+	}
+	return sha512.New()
+}
@@ -0,0 +1,139 @@
+package encryption_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
+)
+
+func TestParseKey(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		key string
+
+		wantAlgorithm string
+		wantKey       string
+		wantErr       bool
+	}{
+		"Key_without_algorithm_uses_the_default_one": {key: "c29tZWtleQ==", wantAlgorithm: encryption.DefaultAlgorithm, wantKey: "c29tZWtleQ=="},
+		"Key_with_algorithm":                         {key: "rsa-oaep-sha256:c29tZWtleQ==", wantAlgorithm: encryption.RSAOAEPSHA256, wantKey: "c29tZWtleQ=="},
+
+		"Error_on_unsupported_algorithm": {key: "rsa-pkcs1:c29tZWtleQ==", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			algorithm, key, err := encryption.ParseKey(tc.key)
+			if tc.wantErr {
+				require.Error(t, err, "ParseKey should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "ParseKey should not return an error, but did")
+			require.Equal(t, tc.wantAlgorithm, algorithm, "ParseKey returned an unexpected algorithm")
+			require.Equal(t, tc.wantKey, key, "ParseKey returned an unexpected key")
+		})
+	}
+}
+
+func TestKeyFromData(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data string
+
+		wantKey  string
+		wantData string
+		wantErr  bool
+	}{
+		"Data_without_key_is_unchanged": {data: `{"message":"retry"}`, wantData: `{"message":"retry"}`},
+		"Key_is_removed_from_data":      {data: `{"message":"retry","encryption_key":"rsa-oaep-sha256:a2V5"}`, wantKey: "rsa-oaep-sha256:a2V5", wantData: `{"message":"retry"}`},
+		"Only_key_in_data":              {data: `{"encryption_key":"a2V5"}`, wantKey: "a2V5", wantData: `{}`},
+
+		"Error_on_invalid_JSON":   {data: `not json`, wantErr: true},
+		"Error_on_empty_key":      {data: `{"encryption_key":""}`, wantErr: true},
+		"Error_on_non_string_key": {data: `{"encryption_key":42}`, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			key, data, err := encryption.KeyFromData(tc.data)
+			if tc.wantErr {
+				require.Error(t, err, "KeyFromData should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "KeyFromData should not return an error, but did")
+			require.Equal(t, tc.wantKey, key, "KeyFromData returned an unexpected key")
+			require.JSONEq(t, tc.wantData, data, "KeyFromData returned unexpected data")
+		})
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Setup: could not generate key")
+	pubASN1, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err, "Setup: could not marshal public key")
+	encodedKey := base64.StdEncoding.EncodeToString(pubASN1)
+
+	tests := map[string]struct {
+		algorithm        string
+		decryptAlgorithm string
+		key              string
+
+		wantNewErr     bool
+		wantDecryptErr bool
+	}{
+		"Encrypt_with_RSA_OAEP_SHA512": {algorithm: encryption.RSAOAEPSHA512},
+		"Encrypt_with_RSA_OAEP_SHA256": {algorithm: encryption.RSAOAEPSHA256},
+
+		"Error_on_unsupported_algorithm":          {algorithm: "rsa-pkcs1", wantNewErr: true},
+		"Error_on_key_not_base64_encoded":         {algorithm: encryption.RSAOAEPSHA512, key: "not base64", wantNewErr: true},
+		"Error_on_invalid_key":                    {algorithm: encryption.RSAOAEPSHA512, key: "a2V5", wantNewErr: true},
+		"Error_decrypting_with_another_algorithm": {algorithm: encryption.RSAOAEPSHA256, decryptAlgorithm: encryption.RSAOAEPSHA512, wantDecryptErr: true},
+		"Error_decrypting_with_unsupported_algorithm": {
+			algorithm: encryption.RSAOAEPSHA256, decryptAlgorithm: "rsa-pkcs1", wantDecryptErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.key == "" {
+				tc.key = encodedKey
+			}
+			if tc.decryptAlgorithm == "" {
+				tc.decryptAlgorithm = tc.algorithm
+			}
+
+			k, err := encryption.NewPublicKey(tc.algorithm, tc.key)
+			if tc.wantNewErr {
+				require.Error(t, err, "NewPublicKey should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "NewPublicKey should not return an error, but did")
+			require.Equal(t, tc.algorithm, k.Algorithm(), "Algorithm should return the algorithm of the key")
+
+			encrypted, err := k.Encrypt("my secret")
+			require.NoError(t, err, "Encrypt should not return an error, but did")
+			require.NotEqual(t, "my secret", encrypted, "Encrypt should encrypt the secret")
+
+			decrypted, err := encryption.Decrypt(tc.decryptAlgorithm, privateKey, encrypted)
+			if tc.wantDecryptErr {
+				require.Error(t, err, "Decrypt should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Decrypt should not return an error, but did")
+			require.Equal(t, "my secret", decrypted, "Decrypt should return the original secret")
+		})
+	}
+}
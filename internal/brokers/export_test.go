@@ -13,6 +13,16 @@ func NewBroker(ctx context.Context, configFile string, bus *dbus.Conn) (Broker,
 	return newBroker(ctx, configFile, bus)
 }
 
+// NewSession exports the private newSession method for testing purposes.
+func (b Broker) NewSession(ctx context.Context, username, lang, mode string) (sessionID, encryptionKey string, err error) {
+	return b.newSession(ctx, username, lang, mode)
+}
+
+// EndSession exports the private endSession method for testing purposes.
+func (b Broker) EndSession(ctx context.Context, sessionID string) error {
+	return b.endSession(ctx, sessionID)
+}
+
 // SetBrokerForSession sets the broker for a given session.
 //
 // This is to be used only in tests.
@@ -1,10 +1,18 @@
 package brokers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/godbus/dbus/v5"
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
 	"github.com/ubuntu/authd/internal/testutils/golden"
 )
 
@@ -93,3 +101,138 @@ func TestUnmarshalUserInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	transientErr := authderrors.ErrBrokerUnavailable.Wrap(undeliveredError{err: errors.New("transient error")})
+	deliveredErr := authderrors.ErrBrokerUnavailable.Wrap(errors.New("no reply"))
+	otherErr := errors.New("some error")
+
+	tests := map[string]struct {
+		errs   []error
+		budget time.Duration
+
+		wantCalls int
+		wantErr   error
+	}{
+		"Success_without_retrying":                {errs: []error{nil}, budget: time.Second, wantCalls: 1},
+		"Success_after_retrying_transient_errors": {errs: []error{transientErr, transientErr, nil}, budget: time.Second, wantCalls: 3},
+		"No_retry_when_budget_is_0":               {errs: []error{transientErr, nil}, wantCalls: 1, wantErr: transientErr},
+		"No_retry_on_error_that_is_not_transient": {errs: []error{otherErr, nil}, budget: time.Second, wantCalls: 1, wantErr: otherErr},
+		"No_retry_on_call_that_may_have_been_delivered": {
+			errs: []error{deliveredErr, nil}, budget: time.Second, wantCalls: 1, wantErr: deliveredErr,
+		},
+		"Error_when_failing_after_retrying":    {errs: []error{transientErr, otherErr}, budget: time.Second, wantCalls: 2, wantErr: otherErr},
+		"Error_when_retry_budget_is_exhausted": {errs: []error{transientErr}, budget: 50 * time.Millisecond, wantErr: transientErr},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := retryPolicy{initialBackoff: 10 * time.Millisecond, maxBackoff: 40 * time.Millisecond, budget: tc.budget}
+
+			var calls int
+			err := p.retry(context.Background(), t.Name(), func() error {
+				calls++
+				return tc.errs[min(calls, len(tc.errs))-1]
+			})
+			require.ErrorIs(t, err, tc.wantErr, "Retry should return the expected error")
+			if tc.wantCalls > 0 {
+				require.Equal(t, tc.wantCalls, calls, "Function should be called the expected number of times")
+			} else {
+				require.Greater(t, calls, 1, "Function should be retried until the budget is exhausted")
+			}
+		})
+	}
+}
+
+func TestIsTransientDBusError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err error
+
+		want            bool
+		wantUndelivered bool
+	}{
+		"Service_unknown_is_transient_and_undelivered": {
+			err:  dbus.Error{Name: "org.freedesktop.DBus.Error.ServiceUnknown"},
+			want: true, wantUndelivered: true,
+		},
+		"Name_without_owner_is_transient_and_undelivered": {
+			err:  fmt.Errorf("wrapped: %w", dbus.Error{Name: "org.freedesktop.DBus.Error.NameHasNoOwner"}),
+			want: true, wantUndelivered: true,
+		},
+		"No_reply_is_transient_but_may_be_delivered": {
+			err:  fmt.Errorf("wrapped: %w", dbus.Error{Name: "org.freedesktop.DBus.Error.NoReply"}),
+			want: true,
+		},
+		"Timeout_is_transient_but_may_be_delivered":           {err: dbus.Error{Name: "org.freedesktop.DBus.Error.Timeout"}, want: true},
+		"Closed_connection_is_transient_but_may_be_delivered": {err: dbus.ErrClosed, want: true},
+
+		"Broker_error_is_not_transient":  {err: dbus.Error{Name: "com.ubuntu.authd.Error"}},
+		"Generic_error_is_not_transient": {err: errors.New("some error")},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, isTransientDBusError(tc.err), "Transient classification does not match")
+			require.Equal(t, tc.wantUndelivered, isUndeliveredDBusError(tc.err), "Undelivered classification does not match")
+		})
+	}
+}
+
+func FuzzValidateUILayout(f *testing.F) {
+	f.Add(`[{"type":"form","label":"required","entry":"optional:chars,chars_password","wait":"optional:true,false"}]`,
+		`{"type":"form","label":"Password","entry":"chars_password"}`)
+	f.Add(`[{"type":"qrcode","content":"required","code":"optional","button":"optional"}]`,
+		`{"type":"qrcode","content":"https://example.com","button":"Regenerate"}`)
+	f.Add(`[{"type":"newpassword","label":"required","entry":"optional:chars_password"}]`,
+		`{"type":"newpassword","entry":"digits"}`)
+	f.Add(`[{"label":"required"}]`, `{"type":"form","unknown":"value"}`)
+
+	f.Fuzz(func(t *testing.T, supportedUILayoutsJSON, layoutJSON string) {
+		var supportedUILayouts []map[string]string
+		if err := json.Unmarshal([]byte(supportedUILayoutsJSON), &supportedUILayouts); err != nil {
+			t.Skip("Supported UI layouts are not a list of string maps")
+		}
+		var layout map[string]string
+		if err := json.Unmarshal([]byte(layoutJSON), &layout); err != nil {
+			t.Skip("UI layout is not a string map")
+		}
+
+		const sessionID = "session"
+		b := Broker{
+			layoutValidators:   map[string]map[string]layoutValidator{},
+			layoutValidatorsMu: &sync.Mutex{},
+		}
+		validators := generateValidators(context.Background(), sessionID, supportedUILayouts)
+		b.layoutValidators[sessionID] = validators
+
+		r, err := b.validateUILayout(sessionID, layout)
+		if err != nil {
+			require.Nil(t, r, "validateUILayout should not return a layout on error")
+			return
+		}
+
+		// Any accepted layout must match the validator of its type.
+		validator, ok := validators[r[layouts.Type]]
+		require.True(t, ok, "Accepted layout type %q should be supported", r[layouts.Type])
+		for key, value := range r {
+			if key == layouts.Type {
+				continue
+			}
+			require.Contains(t, validator, key, "Accepted layout field %q should be supported", key)
+			if value != "" && validator[key].supportedValues != nil {
+				require.Contains(t, validator[key].supportedValues, value, "Accepted layout field %q should have a supported value", key)
+			}
+		}
+		for key, fv := range validator {
+			if fv.required {
+				require.NotEmpty(t, r[key], "Accepted layout should have the required field %q", key)
+			}
+		}
+	})
+}
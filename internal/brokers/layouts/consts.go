@@ -8,6 +8,10 @@ const (
 	QrCode = "qrcode"
 	// NewPassword the layout used by new password UI layouts.
 	NewPassword = "newpassword"
+	// SmartCard is the layout used by smart card (PKCS#11, PIV, CAC) authentication UI layouts.
+	SmartCard = "smartcard"
+	// Consent is the layout used to show a message the user must accept to log in, like the terms of use.
+	Consent = "consent"
 )
 
 const (
@@ -43,6 +47,27 @@ const (
 	Code = "code"
 	// RendersQrCode is the key for the layout renders qrcode.
 	RendersQrCode = "renders_qrcode"
+	// CodeLength is the key for the layout code length, after which the code is submitted automatically.
+	CodeLength = "code_length"
+	// CodeValidity is the key for the layout code validity, in seconds.
+	CodeValidity = "code_validity"
+	// MinStrength is the key for the layout minimum strength score, from 0 (very weak) to 4 (very strong), of the new
+	// password.
+	MinStrength = "min_strength"
+	// SmartCardAction is the key for the layout smart card action.
+	SmartCardAction = "smartcard_action"
+	// CachedCredentials is the key for the layout flag telling that the broker authenticates the user with cached
+	// credentials (for example a refresh token), without any user interaction.
+	CachedCredentials = "cached_credentials"
+)
+
+const (
+	// SmartCardInsert is the smart card action asking the user to insert their card.
+	SmartCardInsert = "insert"
+	// SmartCardPIN is the smart card action asking the user for the PIN of their card.
+	SmartCardPIN = "pin"
+	// SmartCardTouch is the smart card action asking the user to confirm their presence by touching their card.
+	SmartCardTouch = "touch"
 )
 
 var (
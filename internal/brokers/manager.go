@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/ubuntu/authd/log"
@@ -31,10 +32,39 @@ type Manager struct {
 	cleanup func()
 }
 
+type options struct {
+	retryPolicy   retryPolicy
+	recordingsDir string
+}
+
+// Option is the function signature used to tweak the manager creation.
+type Option func(*options)
+
+// WithTransientErrorsRetryBudget sets for how long the calls to the brokers failing with transient errors are
+// retried. A value of 0 disables retries.
+func WithTransientErrorsRetryBudget(budget time.Duration) Option {
+	return func(o *options) {
+		o.retryPolicy.budget = budget
+	}
+}
+
+// WithRecordingsDir records the traffic with each broker to a file in dir, which can be replayed in tests with
+// NewReplayBroker. The secrets sent to the brokers are redacted, but the user information they return is recorded.
+func WithRecordingsDir(dir string) Option {
+	return func(o *options) {
+		o.recordingsDir = dir
+	}
+}
+
 // NewManager creates a new broker manager object.
-func NewManager(ctx context.Context, brokersConfPath string, configuredBrokers []string) (m *Manager, err error) {
+func NewManager(ctx context.Context, brokersConfPath string, configuredBrokers []string, args ...Option) (m *Manager, err error) {
 	defer decorate.OnError(&err /*i18n.G(*/, "can't create brokers detection object") //)
 
+	opts := options{retryPolicy: defaultRetryPolicy}
+	for _, f := range args {
+		f(&opts)
+	}
+
 	log.Debug(ctx, "Building broker detection")
 
 	brokersConfPathWithExample, cleanup, err := useExampleBrokers()
@@ -90,6 +120,7 @@ func NewManager(ctx context.Context, brokersConfPath string, configuredBrokers [
 	brokers[b.ID] = &b
 
 	// Load brokers configuration
+	var watched []watchedBroker
 	for _, cfgFileName := range configuredBrokers {
 		configFile := filepath.Join(brokersConfPath, cfgFileName)
 		b, err := newBroker(ctx, configFile, bus)
@@ -97,10 +128,22 @@ func NewManager(ctx context.Context, brokersConfPath string, configuredBrokers [
 			log.Warningf(ctx, "Skipping broker %q is not correctly configured: %v", cfgFileName, err)
 			continue
 		}
+		if d, ok := b.brokerer.(dbusBroker); ok {
+			watched = append(watched, watchedBroker{broker: &b, dbusName: d.dbusObject.Destination(), path: d.dbusObject.Path()})
+		}
+		b.retryPolicy = opts.retryPolicy
+		if opts.recordingsDir != "" {
+			log.Noticef(ctx, "Recording the traffic with broker %q to %q", b.Name, opts.recordingsDir)
+			b.brokerer = newRecorder(b.brokerer, opts.recordingsDir, b.ID, b.Name)
+		}
 		brokersOrder = append(brokersOrder, b.ID)
 		brokers[b.ID] = &b
 	}
 
+	if err := watchAuthProgress(ctx, bus, watched); err != nil {
+		log.Warningf(ctx, "The progress of the authentications won't be shown: %v", err)
+	}
+
 	return &Manager{
 		brokers:      brokers,
 		brokersOrder: brokersOrder,
@@ -120,6 +163,24 @@ func (m *Manager) AvailableBrokers() (r []*Broker) {
 	return r
 }
 
+// MayOwnUser returns whether any broker may handle the user, according to the usernames they publish.
+// Brokers which don't publish their usernames may handle any user.
+func (m *Manager) MayOwnUser(username string) bool {
+	hasBrokers := false
+	for _, b := range m.brokers {
+		// The local broker is not a real broker, so we skip it.
+		if b.ID == LocalBrokerName {
+			continue
+		}
+		hasBrokers = true
+		if b.MayOwnUser(username) {
+			return true
+		}
+	}
+	// Without any broker, the users in the database are still served.
+	return !hasBrokers
+}
+
 // SetDefaultBrokerForUser memorizes which broker was used for which user.
 func (m *Manager) SetDefaultBrokerForUser(brokerID, username string) error {
 	broker, err := m.brokerFromID(brokerID)
@@ -133,6 +194,13 @@ func (m *Manager) SetDefaultBrokerForUser(brokerID, username string) error {
 	return nil
 }
 
+// UnsetDefaultBrokerForUser forgets which broker was used for the user.
+func (m *Manager) UnsetDefaultBrokerForUser(username string) {
+	m.usersToBrokerMu.Lock()
+	defer m.usersToBrokerMu.Unlock()
+	delete(m.usersToBroker, username)
+}
+
 // BrokerForUser returns any previously selected broker for a given user, if any.
 func (m *Manager) BrokerForUser(username string) (broker *Broker) {
 	m.usersToBrokerMu.RLock()
@@ -159,44 +227,66 @@ func (m *Manager) BrokerFromSessionID(id string) (broker *Broker, err error) {
 }
 
 // NewSession create a new session for the broker and store the sesssionID on the manager.
-func (m *Manager) NewSession(brokerID, username, lang, mode string) (sessionID string, encryptionKey string, err error) {
+// If the context is cancelled once the broker created the session, the session is ended right away so that it's not
+// left behind.
+func (m *Manager) NewSession(ctx context.Context, brokerID, username, lang, mode string) (sessionID string, encryptionKey string, err error) {
 	broker, err := m.brokerFromID(brokerID)
 	if err != nil {
 		return "", "", fmt.Errorf("invalid broker: %v", err)
 	}
 
-	sessionID, encryptionKey, err = broker.newSession(context.Background(), username, lang, mode)
+	sessionID, encryptionKey, err = broker.newSession(ctx, username, lang, mode)
 	if err != nil {
 		return "", "", err
 	}
 
+	if err := ctx.Err(); err != nil {
+		log.Infof(ctx, "%s: Request cancelled while starting session for %q, ending it", sessionID, username)
+		if endErr := broker.endSession(context.WithoutCancel(ctx), sessionID); endErr != nil {
+			log.Warningf(ctx, "%s: Could not end session: %v", sessionID, endErr)
+		}
+		return "", "", err
+	}
+
 	m.transactionsToBrokerMu.Lock()
 	defer m.transactionsToBrokerMu.Unlock()
-	log.Debug(context.Background(), fmt.Sprintf("%s: New session for %q", sessionID, username))
+	log.Debug(ctx, fmt.Sprintf("%s: New session for %q", sessionID, username))
 	m.transactionsToBroker[sessionID] = broker
 	return sessionID, encryptionKey, nil
 }
 
 // EndSession signals the end of the session to the broker associated with the sessionID and then removes the
 // session -> broker mapping.
-func (m *Manager) EndSession(sessionID string) error {
+// The session is ended even if the context gets cancelled, as the client may go away right after requesting it.
+func (m *Manager) EndSession(ctx context.Context, sessionID string) error {
 	b, err := m.BrokerFromSessionID(sessionID)
 	if err != nil {
 		return err
 	}
 
-	if err = b.endSession(context.Background(), sessionID); err != nil {
+	if err = b.endSession(context.WithoutCancel(ctx), sessionID); err != nil {
 		return err
 	}
 
 	m.transactionsToBrokerMu.Lock()
-	log.Debug(context.Background(), fmt.Sprintf("%s: End session %q",
+	log.Debug(ctx, fmt.Sprintf("%s: End session %q",
 		sessionID, m.transactionsToBroker[sessionID].Name))
 	delete(m.transactionsToBroker, sessionID)
 	m.transactionsToBrokerMu.Unlock()
 	return nil
 }
 
+// AbortSession cancels any pending authentication of the session and then ends it.
+func (m *Manager) AbortSession(ctx context.Context, sessionID string) error {
+	b, err := m.BrokerFromSessionID(sessionID)
+	if err != nil {
+		return err
+	}
+
+	b.cancelIsAuthenticated(ctx, b.parseSessionID(sessionID))
+	return m.EndSession(ctx, sessionID)
+}
+
 // BrokerExists returns true if the brokerID is known by the manager. It can
 // happen that a broker which was stored in the database is not available anymore
 // because the user removed the configuration file.
@@ -119,6 +119,41 @@ func TestBrokerForUser(t *testing.T) {
 	// Broker for user should return nil if no broker is assigned
 	got = m.BrokerForUser("no_broker")
 	require.Nil(t, got, "BrokerForUser should return nil if no broker is assigned, but did not")
+
+	// Broker for user should return nil once the assigned broker is unset
+	m.UnsetDefaultBrokerForUser("user")
+	got = m.BrokerForUser("user")
+	require.Nil(t, got, "BrokerForUser should return nil once the broker is unset, but did not")
+}
+
+func TestManagerMayOwnUser(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		brokerConfigDir   string
+		configuredBrokers []string
+		username          string
+
+		want bool
+	}{
+		"Any_user_may_be_owned_without_any_broker":                   {brokerConfigDir: "no_brokers", username: "user@example.com", want: true},
+		"Any_user_may_be_owned_if_a_broker_has_no_usernames":         {brokerConfigDir: "valid_brokers", username: "user", want: true},
+		"User_matching_the_usernames_of_a_broker_may_be_owned":       {configuredBrokers: []string{"valid_with_usernames.conf"}, username: "user@example.com", want: true},
+		"User_not_matching_the_usernames_of_any_broker_is_not_owned": {configuredBrokers: []string{"valid_with_usernames.conf"}, username: "user"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.brokerConfigDir == "" {
+				tc.brokerConfigDir = "valid_brokers"
+			}
+			m, err := brokers.NewManager(context.Background(), filepath.Join(brokerConfFixtures, tc.brokerConfigDir), tc.configuredBrokers)
+			require.NoError(t, err, "Setup: could not create manager")
+
+			require.Equal(t, tc.want, m.MayOwnUser(tc.username), "MayOwnUser should return the expected value")
+		})
+	}
 }
 
 func TestBrokerFromSessionID(t *testing.T) {
@@ -178,6 +213,7 @@ func TestNewSession(t *testing.T) {
 
 		configuredBrokers []string
 		unavailableBroker bool
+		cancelledContext  bool
 
 		wantErr bool
 	}{
@@ -185,10 +221,12 @@ func TestNewSession(t *testing.T) {
 		"Successfully_start_a_new_passwd_session":                  {username: "success", sessionMode: auth.SessionModeChangePassword},
 		"Successfully_start_a_new_session_with_the_correct_broker": {username: "success", configuredBrokers: []string{t.Name() + "_Broker1.conf", t.Name() + "_Broker2.conf"}},
 
-		"Error_when_broker_does_not_exist":           {brokerID: "does_not_exist", wantErr: true},
-		"Error_when_broker_does_not_provide_an_ID":   {username: "NS_no_id", wantErr: true},
-		"Error_when_starting_a_new_session":          {username: "NS_error", wantErr: true},
-		"Error_when_broker_is_not_available_on_dbus": {unavailableBroker: true, wantErr: true},
+		"Error_when_broker_does_not_exist":               {brokerID: "does_not_exist", wantErr: true},
+		"Error_when_broker_does_not_provide_an_ID":       {username: "NS_no_id", wantErr: true},
+		"Error_when_starting_a_new_session":              {username: "NS_error", wantErr: true},
+		"Error_when_encryption_algorithm_is_unsupported": {username: "NS_unsupported_encryption_algorithm", wantErr: true},
+		"Error_when_broker_is_not_available_on_dbus":     {unavailableBroker: true, wantErr: true},
+		"Error_when_context_is_cancelled":                {username: "success", cancelledContext: true, wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -237,7 +275,13 @@ func TestNewSession(t *testing.T) {
 				tc.sessionMode = "auth"
 			}
 
-			gotID, gotEKey, err := m.NewSession(tc.brokerID, tc.username, "some_lang", tc.sessionMode)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelledContext {
+				cancel()
+			}
+
+			gotID, gotEKey, err := m.NewSession(ctx, tc.brokerID, tc.username, "some_lang", tc.sessionMode)
 			if tc.wantErr {
 				require.Error(t, err, "NewSession should return an error, but did not")
 				return
@@ -295,7 +339,7 @@ func TestEndSession(t *testing.T) {
 				m.SetBrokerForSession(&wantBroker, tc.sessionID)
 			}
 
-			err = m.EndSession(tc.sessionID)
+			err = m.EndSession(context.Background(), tc.sessionID)
 			if tc.wantErr {
 				require.Error(t, err, "EndSession should return an error, but did not")
 				return
@@ -333,13 +377,13 @@ func TestStartAndEndSession(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		id, key, err := m.NewSession(b1.ID, "user1", "some_lang", "auth")
+		id, key, err := m.NewSession(context.Background(), b1.ID, "user1", "some_lang", "auth")
 		firstID, firstKey, firstErr = &id, &key, &err
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		id, key, err := m.NewSession(b2.ID, "user2", "some_lang", "auth")
+		id, key, err := m.NewSession(context.Background(), b2.ID, "user2", "some_lang", "auth")
 		secondID, secondKey, secondErr = &id, &key, &err
 	}()
 	wg.Wait()
@@ -367,12 +411,12 @@ func TestStartAndEndSession(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		*firstErr = m.EndSession(*firstID)
+		*firstErr = m.EndSession(context.Background(), *firstID)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		*secondErr = m.EndSession(*secondID)
+		*secondErr = m.EndSession(context.Background(), *secondID)
 	}()
 	wg.Wait()
 
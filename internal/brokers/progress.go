@@ -0,0 +1,148 @@
+package brokers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/ubuntu/authd/log"
+)
+
+// authProgressSignal is the D-Bus signal the brokers emit to report the progress of a long authentication.
+const authProgressSignal = "AuthProgress"
+
+// AuthProgress is the progress of a long authentication, as reported by the broker while it authenticates the user
+// (for example when waiting for an approval on their phone).
+type AuthProgress struct {
+	// Message is the message to show to the user.
+	Message string
+	// Percentage is the completion of the authentication, from 0 to 100, or negative if it's unknown.
+	Percentage int32
+	// Cancellable is set if the user can cancel the authentication.
+	Cancellable bool
+}
+
+// AuthProgress returns the last progress reported by the broker for the authentication in progress of the session, if
+// any.
+func (b Broker) AuthProgress(sessionID string) (AuthProgress, bool) {
+	if b.authProgressMu == nil {
+		return AuthProgress{}, false
+	}
+
+	b.authProgressMu.Lock()
+	defer b.authProgressMu.Unlock()
+	p := b.authProgress[b.parseSessionID(sessionID)]
+	if p == nil || p.Message == "" {
+		return AuthProgress{}, false
+	}
+	return *p, true
+}
+
+// trackAuthProgress accepts the progress reported by the broker for the session, until the returned function is called
+// once the authentication completes. The session ID is the one of the broker.
+func (b Broker) trackAuthProgress(sessionID string) (stop func()) {
+	if b.authProgressMu == nil {
+		return func() {}
+	}
+
+	b.authProgressMu.Lock()
+	defer b.authProgressMu.Unlock()
+	b.authProgress[sessionID] = &AuthProgress{}
+
+	return func() {
+		b.authProgressMu.Lock()
+		defer b.authProgressMu.Unlock()
+		delete(b.authProgress, sessionID)
+	}
+}
+
+// setAuthProgress records the progress reported by the broker for the session, if it's authenticating its user. The
+// session ID is the one of the broker.
+func (b Broker) setAuthProgress(sessionID string, p AuthProgress) bool {
+	if b.authProgressMu == nil {
+		return false
+	}
+
+	b.authProgressMu.Lock()
+	defer b.authProgressMu.Unlock()
+	current, ok := b.authProgress[sessionID]
+	if !ok {
+		return false
+	}
+	*current = p
+	return true
+}
+
+// progressWatcher dispatches the progress signals of the D-Bus brokers to the brokers which emitted them.
+type progressWatcher struct {
+	bus     *dbus.Conn
+	brokers []watchedBroker
+}
+
+// watchedBroker is a D-Bus broker whose progress signals are watched.
+type watchedBroker struct {
+	broker   *Broker
+	dbusName string
+	path     dbus.ObjectPath
+}
+
+// watchAuthProgress subscribes to the progress signals of the D-Bus brokers and records them until the bus connection
+// is closed. Only the signals emitted by the owner of the D-Bus name of a broker are accepted.
+func watchAuthProgress(ctx context.Context, bus *dbus.Conn, brokers []watchedBroker) error {
+	if len(brokers) == 0 {
+		return nil
+	}
+
+	for _, b := range brokers {
+		if err := bus.AddMatchSignal(
+			dbus.WithMatchSender(b.dbusName),
+			dbus.WithMatchObjectPath(b.path),
+			dbus.WithMatchInterface(DbusInterface),
+			dbus.WithMatchMember(authProgressSignal),
+		); err != nil {
+			return fmt.Errorf("could not watch progress signals of broker %q: %v", b.broker.Name, err)
+		}
+	}
+
+	// The progress is recorded for as long as the daemon runs.
+	ctx = context.WithoutCancel(ctx)
+	w := progressWatcher{bus: bus, brokers: brokers}
+	signals := make(chan *dbus.Signal, 16)
+	bus.Signal(signals)
+	go func() {
+		for s := range signals {
+			w.dispatch(ctx, s)
+		}
+	}()
+
+	return nil
+}
+
+// dispatch records the progress of the signal for the broker which emitted it.
+func (w progressWatcher) dispatch(ctx context.Context, s *dbus.Signal) {
+	if s.Name != DbusInterface+"."+authProgressSignal {
+		return
+	}
+
+	var sessionID string
+	var p AuthProgress
+	if err := dbus.Store(s.Body, &sessionID, &p.Message, &p.Percentage, &p.Cancellable); err != nil {
+		log.Warningf(ctx, "Ignoring invalid progress signal from %q: %v", s.Sender, err)
+		return
+	}
+
+	for _, b := range w.brokers {
+		if b.path != s.Path {
+			continue
+		}
+		var owner string
+		if err := w.bus.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.GetNameOwner", 0, b.dbusName).Store(&owner); err != nil || owner != s.Sender {
+			continue
+		}
+		if b.broker.setAuthProgress(sessionID, p) {
+			log.Debugf(ctx, "%s-%s: Authentication progress: %q (%d%%)", b.broker.ID, sessionID, p.Message, p.Percentage)
+		}
+		return
+	}
+	log.Warningf(ctx, "Ignoring progress signal of %q, which is not a broker", s.Sender)
+}
@@ -0,0 +1,299 @@
+package brokers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ubuntu/authd/internal/testsdetection"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue replaces the secrets of the authentication data in the recordings.
+const redactedValue = "REDACTED"
+
+// recordedSecretKeys are the keys of the authentication data holding secrets, which are never recorded.
+var recordedSecretKeys = []string{"secret", "challenge"}
+
+// Recording is the traffic between authd and a broker, which can be replayed in tests.
+type Recording struct {
+	// Broker is the name of the recorded broker.
+	Broker string `yaml:"broker"`
+	// Interactions are the calls to the broker, in the order they were made.
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+// Interaction is a call to a broker and its results. Only the fields relevant to the method are set.
+type Interaction struct {
+	Method string `yaml:"method"`
+
+	// Arguments of the call.
+	Username               string `yaml:"username,omitempty"`
+	Lang                   string `yaml:"lang,omitempty"`
+	Mode                   string `yaml:"mode,omitempty"`
+	SessionID              string `yaml:"session_id,omitempty"`
+	AuthenticationModeName string `yaml:"authentication_mode_name,omitempty"`
+	AuthenticationData     string `yaml:"authentication_data,omitempty"`
+
+	// Results of the call.
+	EncryptionKey       string              `yaml:"encryption_key,omitempty"`
+	AuthenticationModes []map[string]string `yaml:"authentication_modes,omitempty"`
+	UILayoutInfo        map[string]string   `yaml:"ui_layout_info,omitempty"`
+	Access              string              `yaml:"access,omitempty"`
+	Data                string              `yaml:"data,omitempty"`
+	UserInfo            string              `yaml:"userinfo,omitempty"`
+	Error               string              `yaml:"error,omitempty"`
+}
+
+// recorder wraps a broker to save all its traffic to a recording file.
+type recorder struct {
+	brokerer
+
+	path      string
+	recording Recording
+	mu        sync.Mutex
+}
+
+// newRecorder returns a brokerer recording the traffic of broker to a file in dir, named after the broker ID.
+func newRecorder(broker brokerer, dir, id, name string) *recorder {
+	return &recorder{
+		brokerer:  broker,
+		path:      filepath.Join(dir, id+".yaml"),
+		recording: Recording{Broker: name},
+	}
+}
+
+// record appends the interaction to the recording, and saves it so that it's complete even if authd is stopped.
+func (r *recorder) record(ctx context.Context, i Interaction, err error) {
+	if err != nil {
+		i.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recording.Interactions = append(r.recording.Interactions, i)
+	if err := r.save(); err != nil {
+		log.Warningf(ctx, "Could not save the recording of broker %q: %v", r.recording.Broker, err)
+	}
+}
+
+func (r *recorder) save() (err error) {
+	defer decorate.OnError(&err, "could not write recording to %q", r.path)
+
+	data, err := yaml.Marshal(r.recording)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return err
+	}
+
+	// The temporary file is only readable by root, as the recordings contain the user information returned by the
+	// broker.
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	defer tmp.Close()
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), r.path)
+}
+
+func (r *recorder) NewSession(ctx context.Context, username, lang, mode string) (sessionID, encryptionKey string, err error) {
+	sessionID, encryptionKey, err = r.brokerer.NewSession(ctx, username, lang, mode)
+	r.record(ctx, Interaction{
+		Method: "NewSession", Username: username, Lang: lang, Mode: mode,
+		SessionID: sessionID, EncryptionKey: encryptionKey,
+	}, err)
+	return sessionID, encryptionKey, err
+}
+
+func (r *recorder) GetAuthenticationModes(ctx context.Context, sessionID string, supportedUILayouts []map[string]string) (authenticationModes []map[string]string, err error) {
+	authenticationModes, err = r.brokerer.GetAuthenticationModes(ctx, sessionID, supportedUILayouts)
+	r.record(ctx, Interaction{
+		Method: "GetAuthenticationModes", SessionID: sessionID,
+		AuthenticationModes: authenticationModes,
+	}, err)
+	return authenticationModes, err
+}
+
+func (r *recorder) SelectAuthenticationMode(ctx context.Context, sessionID, authenticationModeName string) (uiLayoutInfo map[string]string, err error) {
+	uiLayoutInfo, err = r.brokerer.SelectAuthenticationMode(ctx, sessionID, authenticationModeName)
+	r.record(ctx, Interaction{
+		Method: "SelectAuthenticationMode", SessionID: sessionID, AuthenticationModeName: authenticationModeName,
+		UILayoutInfo: uiLayoutInfo,
+	}, err)
+	return uiLayoutInfo, err
+}
+
+func (r *recorder) IsAuthenticated(ctx context.Context, sessionID, authenticationData string) (access, data string, err error) {
+	access, data, err = r.brokerer.IsAuthenticated(ctx, sessionID, authenticationData)
+	r.record(ctx, Interaction{
+		Method: "IsAuthenticated", SessionID: sessionID, AuthenticationData: redactAuthenticationData(authenticationData),
+		Access: access, Data: data,
+	}, err)
+	return access, data, err
+}
+
+func (r *recorder) EndSession(ctx context.Context, sessionID string) (err error) {
+	err = r.brokerer.EndSession(ctx, sessionID)
+	r.record(ctx, Interaction{Method: "EndSession", SessionID: sessionID}, err)
+	return err
+}
+
+func (r *recorder) UserPreCheck(ctx context.Context, username string) (userinfo string, err error) {
+	userinfo, err = r.brokerer.UserPreCheck(ctx, username)
+	r.record(ctx, Interaction{Method: "UserPreCheck", Username: username, UserInfo: userinfo}, err)
+	return userinfo, err
+}
+
+// redactAuthenticationData replaces the secrets of the authentication data, which are encrypted for the broker and
+// don't belong in a recording anyway.
+func redactAuthenticationData(data string) string {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return redactedValue
+	}
+	for _, k := range recordedSecretKeys {
+		if _, ok := fields[k]; ok {
+			fields[k] = redactedValue
+		}
+	}
+	redacted, err := json.Marshal(fields)
+	if err != nil {
+		return redactedValue
+	}
+	return string(redacted)
+}
+
+// replayer is a broker answering with the results of a recording, for the calls made in the same order.
+type replayer struct {
+	recording Recording
+	next      int
+	mu        sync.Mutex
+}
+
+// NewReplayBroker returns a broker replaying the recording at path. The calls must be made in the order of the
+// recording, with the same arguments, apart from the secrets and the UI layouts supported by the client.
+//
+// This is to be used only in tests.
+func NewReplayBroker(path string) (b Broker, err error) {
+	testsdetection.MustBeTesting()
+	defer decorate.OnError(&err, "could not load recording %q", path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Broker{}, err
+	}
+	var recording Recording
+	if err := yaml.Unmarshal(data, &recording); err != nil {
+		return Broker{}, err
+	}
+	if recording.Broker == "" {
+		return Broker{}, errors.New("recording has no broker name")
+	}
+
+	b, err = newBroker(context.Background(), "", nil)
+	if err != nil {
+		return Broker{}, err
+	}
+	b.Name = recording.Broker
+	b.ID = IDFromName(recording.Broker)
+	b.brokerer = &replayer{recording: recording}
+	return b, nil
+}
+
+// replay returns the next interaction of the recording, if it matches the call.
+func (r *replayer) replay(call Interaction) (Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next >= len(r.recording.Interactions) {
+		return Interaction{}, fmt.Errorf("unexpected call to %s: the recording is over", call.Method)
+	}
+	want := r.recording.Interactions[r.next]
+	r.next++
+
+	if call.arguments() != want.arguments() {
+		return Interaction{}, fmt.Errorf("unexpected call #%d: got %+v, recording expects %+v", r.next, call.arguments(), want.arguments())
+	}
+
+	if want.Error != "" {
+		return want, errors.New(want.Error)
+	}
+	return want, nil
+}
+
+// callArguments are the arguments of a call to a broker, which must match the recording when replaying it.
+type callArguments struct {
+	Method, Username, Lang, Mode, SessionID, AuthenticationModeName, AuthenticationData string
+}
+
+func (i Interaction) arguments() callArguments {
+	args := callArguments{
+		Method:                 i.Method,
+		Username:               i.Username,
+		Lang:                   i.Lang,
+		Mode:                   i.Mode,
+		SessionID:              i.SessionID,
+		AuthenticationModeName: i.AuthenticationModeName,
+		AuthenticationData:     i.AuthenticationData,
+	}
+	if i.Method == "NewSession" {
+		// The session ID is a result of the call.
+		args.SessionID = ""
+	}
+	return args
+}
+
+func (r *replayer) NewSession(ctx context.Context, username, lang, mode string) (sessionID, encryptionKey string, err error) {
+	i, err := r.replay(Interaction{Method: "NewSession", Username: username, Lang: lang, Mode: mode})
+	return i.SessionID, i.EncryptionKey, err
+}
+
+func (r *replayer) GetAuthenticationModes(ctx context.Context, sessionID string, supportedUILayouts []map[string]string) (authenticationModes []map[string]string, err error) {
+	i, err := r.replay(Interaction{Method: "GetAuthenticationModes", SessionID: sessionID})
+	return i.AuthenticationModes, err
+}
+
+func (r *replayer) SelectAuthenticationMode(ctx context.Context, sessionID, authenticationModeName string) (uiLayoutInfo map[string]string, err error) {
+	i, err := r.replay(Interaction{Method: "SelectAuthenticationMode", SessionID: sessionID, AuthenticationModeName: authenticationModeName})
+	return i.UILayoutInfo, err
+}
+
+func (r *replayer) IsAuthenticated(ctx context.Context, sessionID, authenticationData string) (access, data string, err error) {
+	i, err := r.replay(Interaction{Method: "IsAuthenticated", SessionID: sessionID, AuthenticationData: redactAuthenticationData(authenticationData)})
+	return i.Access, i.Data, err
+}
+
+func (r *replayer) EndSession(ctx context.Context, sessionID string) (err error) {
+	_, err = r.replay(Interaction{Method: "EndSession", SessionID: sessionID})
+	return err
+}
+
+// CancelIsAuthenticated is a no-op: the recorded calls to IsAuthenticated already return.
+func (r *replayer) CancelIsAuthenticated(ctx context.Context, sessionID string) {}
+
+func (r *replayer) UserPreCheck(ctx context.Context, username string) (userinfo string, err error) {
+	i, err := r.replay(Interaction{Method: "UserPreCheck", Username: username})
+	return i.UserInfo, err
+}
@@ -0,0 +1,204 @@
+package brokers_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/testutils"
+	"github.com/ubuntu/authd/internal/testutils/golden"
+	"gopkg.in/yaml.v3"
+)
+
+// replayLayouts are the UI layouts supported by the clients replaying the recordings, like the ones of GDM.
+var replayLayouts = []map[string]string{
+	{
+		layouts.Type:   layouts.Form,
+		layouts.Label:  layouts.Required,
+		layouts.Entry:  layouts.OptionalItems(entries.Chars, entries.CharsPassword),
+		layouts.Wait:   layouts.OptionalItems(layouts.True, layouts.False),
+		layouts.Button: layouts.Optional,
+	},
+	{
+		layouts.Type:    layouts.QrCode,
+		layouts.Content: layouts.Required,
+		layouts.Code:    layouts.Optional,
+		layouts.Wait:    layouts.RequiredItems(layouts.True, layouts.False),
+		layouts.Label:   layouts.Optional,
+		layouts.Button:  layouts.Optional,
+	},
+	{
+		layouts.Type:   layouts.NewPassword,
+		layouts.Label:  layouts.Required,
+		layouts.Entry:  layouts.OptionalItems(entries.Chars, entries.CharsPassword),
+		layouts.Button: layouts.Optional,
+	},
+}
+
+func TestReplayRecordings(t *testing.T) {
+	t.Parallel()
+
+	recordings, err := filepath.Glob(filepath.Join("testdata", "recordings", "*.yaml"))
+	require.NoError(t, err, "Setup: could not list the recordings")
+	require.NotEmpty(t, recordings, "Setup: there should be recordings to replay")
+
+	for _, path := range recordings {
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".yaml"), func(t *testing.T) {
+			t.Parallel()
+
+			b, err := brokers.NewReplayBroker(path)
+			require.NoError(t, err, "NewReplayBroker should not return an error, but did")
+
+			got := callBroker(t, b, readInteractions(t, path))
+			golden.CheckOrUpdate(t, got)
+		})
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	brokersConfPath := t.TempDir()
+	recordingsDir := filepath.Join(t.TempDir(), "recordings")
+	b := newBrokerForTests(t, brokersConfPath, t.Name()+"_Broker.conf")
+
+	m, err := brokers.NewManager(context.Background(), brokersConfPath, []string{b.Name + ".conf"},
+		brokers.WithRecordingsDir(recordingsDir))
+	require.NoError(t, err, "Setup: could not create manager")
+	var recorded *brokers.Broker
+	for _, broker := range m.AvailableBrokers() {
+		if broker.Name == b.Name {
+			recorded = broker
+		}
+	}
+	require.NotNil(t, recorded, "Setup: the broker should be available")
+
+	// The mock broker answers depending on the username.
+	calls := []brokers.Interaction{
+		{Method: "NewSession", Username: t.Name() + testutils.IDSeparator + "HA_success", Lang: "C.UTF-8", Mode: auth.SessionModeLogin},
+		{Method: "GetAuthenticationModes"},
+		{Method: "SelectAuthenticationMode", AuthenticationModeName: "mode1"},
+		{Method: "IsAuthenticated", AuthenticationData: `{"secret":"some secret"}`},
+		{Method: "EndSession"},
+	}
+	want := callBroker(t, *recorded, calls)
+
+	recording := filepath.Join(recordingsDir, recorded.ID+".yaml")
+	data, err := os.ReadFile(recording)
+	require.NoError(t, err, "The traffic with the broker should be recorded")
+	require.NotContains(t, string(data), "some secret", "The secrets should not be recorded")
+
+	replayed, err := brokers.NewReplayBroker(recording)
+	require.NoError(t, err, "NewReplayBroker should not return an error, but did")
+	require.Equal(t, recorded.Name, replayed.Name, "The replayed broker should have the name of the recorded one")
+	require.Equal(t, recorded.ID, replayed.ID, "The replayed broker should have the ID of the recorded one")
+
+	got := callBroker(t, replayed, calls)
+	require.Equal(t, want, got, "Replaying the recording should give the same results as the recorded broker")
+}
+
+func TestReplayErrors(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join("testdata", "recordings", "google_password_retry.yaml")
+
+	tests := map[string]struct {
+		recording string
+		call      brokers.Interaction
+
+		wantLoadErr bool
+	}{
+		"Error_when_the_username_differs_from_the_recording": {call: brokers.Interaction{Method: "NewSession", Username: "other@example.com", Lang: "en_US.UTF-8", Mode: auth.SessionModeLogin}},
+		"Error_when_the_method_differs_from_the_recording":   {call: brokers.Interaction{Method: "UserPreCheck", Username: "user@example.com"}},
+		"Error_when_the_recording_is_over":                   {recording: "broker: Google\ninteractions: []", call: brokers.Interaction{Method: "UserPreCheck", Username: "user@example.com"}},
+
+		"Error_when_the_recording_does_not_exist": {recording: "-", wantLoadErr: true},
+		"Error_when_the_recording_is_invalid":     {recording: "not: [valid", wantLoadErr: true},
+		"Error_when_the_recording_has_no_broker":  {recording: "interactions: []", wantLoadErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := path
+			if tc.recording == "-" {
+				p = filepath.Join(t.TempDir(), "does-not-exist.yaml")
+			} else if tc.recording != "" {
+				p = filepath.Join(t.TempDir(), "recording.yaml")
+				require.NoError(t, os.WriteFile(p, []byte(tc.recording), 0600), "Setup: could not write recording")
+			}
+
+			b, err := brokers.NewReplayBroker(p)
+			if tc.wantLoadErr {
+				require.Error(t, err, "NewReplayBroker should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "NewReplayBroker should not return an error, but did")
+
+			var callErr error
+			switch tc.call.Method {
+			case "NewSession":
+				_, _, callErr = b.NewSession(context.Background(), tc.call.Username, tc.call.Lang, tc.call.Mode)
+			case "UserPreCheck":
+				_, callErr = b.UserPreCheck(context.Background(), tc.call.Username)
+			}
+			require.Error(t, callErr, "The call should return an error as it does not match the recording")
+		})
+	}
+}
+
+// readInteractions returns the interactions of the recording at path.
+func readInteractions(t *testing.T, path string) []brokers.Interaction {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "Setup: could not read recording")
+	var r brokers.Recording
+	require.NoError(t, yaml.Unmarshal(data, &r), "Setup: could not parse recording")
+	return r.Interactions
+}
+
+// callBroker makes the calls to the broker, with the arguments of the interactions, and returns what authd gets back
+// from the broker after validating its answers.
+func callBroker(t *testing.T, b brokers.Broker, calls []brokers.Interaction) string {
+	t.Helper()
+
+	ctx := context.Background()
+	var out strings.Builder
+	var sessionID string
+	for _, c := range calls {
+		var res any
+		var err error
+		switch c.Method {
+		case "NewSession":
+			var key string
+			sessionID, key, err = b.NewSession(ctx, c.Username, c.Lang, c.Mode)
+			// The session ID of the mock broker depends on the test name, which differs between the recordings.
+			res = fmt.Sprintf("session started: %t, encryption key: %q", sessionID != "", key)
+		case "GetAuthenticationModes":
+			res, err = b.GetAuthenticationModes(ctx, sessionID, replayLayouts)
+		case "SelectAuthenticationMode":
+			res, err = b.SelectAuthenticationMode(ctx, sessionID, c.AuthenticationModeName)
+		case "IsAuthenticated":
+			var access, data string
+			access, data, err = b.IsAuthenticated(ctx, sessionID, c.AuthenticationData)
+			res = map[string]string{"access": access, "data": data}
+		case "EndSession":
+			err = b.EndSession(ctx, sessionID)
+		case "UserPreCheck":
+			res, err = b.UserPreCheck(ctx, c.Username)
+		default:
+			require.Fail(t, "Setup: unknown method", "Method %q is not supported", c.Method)
+		}
+		fmt.Fprintf(&out, "%s:\n\tresult: %v\n\terr: %v\n", c.Method, res, err)
+	}
+	return out.String()
+}
@@ -0,0 +1,114 @@
+package brokers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/ubuntu/authd/internal/clock"
+	"github.com/ubuntu/authd/log"
+)
+
+// transientDBusErrors are the D-Bus errors that may go away by retrying the call, like when the broker is
+// restarting.
+var transientDBusErrors = []string{
+	"org.freedesktop.DBus.Error.ServiceUnknown",
+	"org.freedesktop.DBus.Error.NameHasNoOwner",
+	"org.freedesktop.DBus.Error.NoReply",
+	"org.freedesktop.DBus.Error.NoServer",
+	"org.freedesktop.DBus.Error.Disconnected",
+	"org.freedesktop.DBus.Error.LimitsExceeded",
+	"org.freedesktop.DBus.Error.Timeout",
+}
+
+// undeliveredDBusErrors are the transient D-Bus errors proving that the call never reached the broker. The other ones
+// may happen after the broker received the call, so retrying it could process it twice.
+var undeliveredDBusErrors = []string{
+	"org.freedesktop.DBus.Error.ServiceUnknown",
+	"org.freedesktop.DBus.Error.NameHasNoOwner",
+}
+
+// isTransientDBusError returns whether the error returned by a D-Bus call may go away by retrying it.
+func isTransientDBusError(err error) bool {
+	if errors.Is(err, dbus.ErrClosed) {
+		return true
+	}
+	var dbusError dbus.Error
+	return errors.As(err, &dbusError) && slices.Contains(transientDBusErrors, dbusError.Name)
+}
+
+// isUndeliveredDBusError returns whether the error returned by a D-Bus call proves that it never reached the broker.
+func isUndeliveredDBusError(err error) bool {
+	var dbusError dbus.Error
+	return errors.As(err, &dbusError) && slices.Contains(undeliveredDBusErrors, dbusError.Name)
+}
+
+// undeliveredError is an error of a call that never reached the broker, so that it's safe to retry it.
+type undeliveredError struct {
+	err error
+}
+
+func (e undeliveredError) Error() string {
+	return e.err.Error()
+}
+
+func (e undeliveredError) Unwrap() error {
+	return e.err
+}
+
+// retryPolicy defines how the calls to a broker failing with a transient error are retried.
+type retryPolicy struct {
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	// budget is the overall time we keep retrying, 0 disables retries.
+	budget time.Duration
+	// clock is used to wait between the attempts, the default clock is used if nil.
+	clock clock.Clock
+}
+
+// brokerRetryingMessage is the progress shown to the user while a call to the broker is retried.
+const brokerRetryingMessage = "The authentication service is not available, retrying..."
+
+var defaultRetryPolicy = retryPolicy{
+	initialBackoff: 100 * time.Millisecond,
+	maxBackoff:     2 * time.Second,
+	budget:         10 * time.Second,
+}
+
+// retry calls f until it succeeds, it fails with an error of a call which may have reached the broker, or the retry
+// budget is exhausted. The time between each attempt grows exponentially.
+// This is the only place where the calls to the brokers are retried, the clients don't retry them on their side.
+func (p retryPolicy) retry(ctx context.Context, op string, f func() error) error {
+	c := p.clock
+	if c == nil {
+		c = clock.Default()
+	}
+
+	deadline := c.Now().Add(p.budget)
+	backoff := p.initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if !errors.As(err, &undeliveredError{}) {
+			return err
+		}
+
+		wait := min(backoff, deadline.Sub(c.Now()))
+		if wait <= 0 {
+			if attempt > 1 {
+				log.Warningf(ctx, "%s: giving up after %d attempts: %v", op, attempt, err)
+			}
+			return err
+		}
+
+		log.Noticef(ctx, "%s: transient failure on attempt %d, retrying in %s: %v", op, attempt, wait, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-c.After(wait):
+		}
+		backoff = min(2*backoff, p.maxBackoff)
+	}
+}
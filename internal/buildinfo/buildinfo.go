@@ -0,0 +1,46 @@
+// Package buildinfo describes the version of authd, the versions of the protocols it speaks and how it was built, so
+// that the skew between the daemon and its clients can be detected.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/ubuntu/authd/internal/consts"
+)
+
+// Info is the version and build metadata of an authd executable.
+type Info struct {
+	Version               string `json:"version" yaml:"version"`
+	GDMProtocolVersion    uint32 `json:"gdm_protocol_version" yaml:"gdm_protocol_version"`
+	BrokerProtocolVersion uint32 `json:"broker_protocol_version" yaml:"broker_protocol_version"`
+	GoVersion             string `json:"go_version" yaml:"go_version"`
+	// Revision is the commit the executable was built from, if built from a version control checkout, and Modified
+	// tells if the checkout had local changes.
+	Revision string `json:"revision,omitempty" yaml:"revision,omitempty"`
+	Modified bool   `json:"modified,omitempty" yaml:"modified,omitempty"`
+}
+
+// Get returns the version and build metadata of the running executable.
+func Get() Info {
+	info := Info{
+		Version:               consts.Version,
+		GDMProtocolVersion:    consts.GDMProtocolVersion,
+		BrokerProtocolVersion: consts.BrokerProtocolVersion,
+		GoVersion:             runtime.Version(),
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
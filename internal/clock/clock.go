@@ -0,0 +1,92 @@
+// Package clock abstracts the passing of time, so that the timeouts and polling intervals can be tuned at runtime and
+// controlled in tests.
+package clock
+
+import (
+	"context"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ubuntu/authd/log"
+)
+
+// ScaleEnv is the environment variable multiplying all the durations waited with the default clock, for example to
+// give more time to slow systems.
+const ScaleEnv = "AUTHD_TIME_SCALE"
+
+// Clock tells the time and waits for durations to elapse.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+	// AfterFunc waits for the duration to elapse and then calls f in its own goroutine.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a timer created by AfterFunc.
+type Timer interface {
+	// Stop prevents the timer from firing. It returns false if the timer already fired or was stopped.
+	Stop() bool
+	// Reset changes the timer to fire after the duration. It returns true if the timer was active.
+	Reset(d time.Duration) bool
+}
+
+var (
+	defaultClock     Clock
+	defaultClockOnce sync.Once
+)
+
+// Default returns the system clock, scaled by the value of ScaleEnv if set.
+func Default() Clock {
+	defaultClockOnce.Do(func() {
+		scale := 1.0
+		if v := os.Getenv(ScaleEnv); v != "" {
+			s, err := strconv.ParseFloat(v, 64)
+			if err != nil || s <= 0 {
+				log.Warningf(context.Background(), "Ignoring invalid %s value %q: it must be a positive number", ScaleEnv, v)
+			} else {
+				scale = s
+			}
+		}
+		defaultClock = Real(scale)
+	})
+	return defaultClock
+}
+
+// Real returns the system clock, where all waited durations are multiplied by scale.
+func Real(scale float64) Clock {
+	return realClock{scale: scale}
+}
+
+type realClock struct {
+	scale float64
+}
+
+func (c realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (c realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(c.scaled(d))
+}
+
+func (c realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{Timer: time.AfterFunc(c.scaled(d), f), clock: c}
+}
+
+func (c realClock) scaled(d time.Duration) time.Duration {
+	return time.Duration(math.Round(float64(d) * c.scale))
+}
+
+type realTimer struct {
+	*time.Timer
+	clock realClock
+}
+
+func (t realTimer) Reset(d time.Duration) bool {
+	return t.Timer.Reset(t.clock.scaled(d))
+}
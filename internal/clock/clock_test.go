@@ -0,0 +1,99 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/clock"
+)
+
+func TestRealScalesDurations(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		scale float64
+
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		"Waits_the_duration_when_not_scaled": {scale: 1, wantMin: 50 * time.Millisecond, wantMax: 150 * time.Millisecond},
+		"Waits_less_when_scaled_down":        {scale: 0.1, wantMax: 40 * time.Millisecond},
+		"Waits_more_when_scaled_up":          {scale: 3, wantMin: 150 * time.Millisecond},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := clock.Real(tc.scale)
+
+			start := time.Now()
+			<-c.After(50 * time.Millisecond)
+			elapsed := time.Since(start)
+
+			require.GreaterOrEqual(t, elapsed, tc.wantMin, "After should wait for the scaled duration")
+			if tc.wantMax > 0 {
+				require.Less(t, elapsed, tc.wantMax, "After should not wait longer than the scaled duration")
+			}
+
+			fired := make(chan struct{})
+			start = time.Now()
+			c.AfterFunc(50*time.Millisecond, func() { close(fired) })
+			<-fired
+			require.GreaterOrEqual(t, time.Since(start), tc.wantMin, "AfterFunc should wait for the scaled duration")
+		})
+	}
+}
+
+func TestFake(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	c := clock.NewFake(start)
+	require.Equal(t, start, c.Now(), "Now should return the initial time")
+
+	after := c.After(time.Minute)
+	fired := make(chan struct{})
+	c.AfterFunc(2*time.Minute, func() { close(fired) })
+	stopped := c.AfterFunc(time.Minute, func() { require.Fail(t, "Stopped timer should not fire") })
+	require.Equal(t, 3, c.Waiters(), "All timers should be waiting")
+	require.True(t, stopped.Stop(), "Stop should return true for an active timer")
+	require.False(t, stopped.Stop(), "Stop should return false for a stopped timer")
+
+	c.Advance(30 * time.Second)
+	select {
+	case <-after:
+		require.Fail(t, "After should not fire before the duration elapsed")
+	default:
+	}
+
+	c.Advance(30 * time.Second)
+	require.Equal(t, start.Add(time.Minute), <-after, "After should send the time once the duration elapsed")
+	require.Equal(t, 1, c.Waiters(), "Only the timer not fired yet should be waiting")
+
+	c.Advance(time.Minute)
+	<-fired
+	require.Equal(t, start.Add(2*time.Minute), c.Now(), "Now should return the advanced time")
+	require.Zero(t, c.Waiters(), "No timer should be waiting")
+}
+
+func TestFakeTimerReset(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewFake(time.Now())
+	fired := make(chan struct{}, 1)
+	timer := c.AfterFunc(time.Minute, func() { fired <- struct{}{} })
+
+	c.Advance(30 * time.Second)
+	require.True(t, timer.Reset(time.Minute), "Reset should return true for an active timer")
+
+	c.Advance(45 * time.Second)
+	require.Empty(t, fired, "Reset timer should not fire before the new duration elapsed")
+
+	c.Advance(15 * time.Second)
+	<-fired
+
+	require.False(t, timer.Reset(time.Minute), "Reset should return false for a fired timer")
+	c.Advance(time.Minute)
+	<-fired
+}
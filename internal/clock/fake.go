@@ -0,0 +1,117 @@
+package clock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ubuntu/authd/internal/testsdetection"
+)
+
+// Fake is a clock whose time only changes when advanced, so that tests don't have to wait.
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFake returns a fake clock starting at the given time. It can only be used in tests.
+func NewFake(now time.Time) *Fake {
+	testsdetection.MustBeTesting()
+
+	return &Fake{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *Fake) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After sends the time on the returned channel once the clock is advanced by the duration.
+func (c *Fake) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	c.addTimer(d, func(now time.Time) { ch <- now })
+	return ch
+}
+
+// AfterFunc calls f in its own goroutine once the clock is advanced by the duration.
+func (c *Fake) AfterFunc(d time.Duration, f func()) Timer {
+	return c.addTimer(d, func(time.Time) { go f() })
+}
+
+// Advance moves the time of the clock forward, firing the timers which expire.
+func (c *Fake) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+
+	var expired []*fakeTimer
+	active := c.timers[:0]
+	for _, t := range c.timers {
+		if t.deadline.After(now) {
+			active = append(active, t)
+			continue
+		}
+		expired = append(expired, t)
+	}
+	c.timers = active
+	c.mu.Unlock()
+
+	for _, t := range expired {
+		t.fire(now)
+	}
+}
+
+// Waiters returns the number of timers which have not fired yet, so that tests can wait for the code under test to
+// wait on the clock before advancing it.
+func (c *Fake) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+func (c *Fake) addTimer(d time.Duration, fire func(time.Time)) *fakeTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, deadline: c.now.Add(d), fire: fire}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// remove removes the timer from the clock and returns whether it was active.
+func (c *Fake) remove(t *fakeTimer) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, other := range c.timers {
+		if other == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock    *Fake
+	deadline time.Time
+	fire     func(time.Time)
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	active := t.clock.remove(t)
+
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.deadline = t.clock.now.Add(d)
+	t.clock.timers = append(t.clock.timers, t)
+	return active
+}
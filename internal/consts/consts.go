@@ -15,6 +15,12 @@ const (
 	// DefaultSocketPath is the default socket path.
 	DefaultSocketPath = "/run/authd.sock"
 
+	// DefaultNSSSnapshotPath is the default path of the snapshot of the NSS entries, which is read by the NSS module.
+	DefaultNSSSnapshotPath = "/run/authd-nss.snapshot"
+
+	// DefaultDebugSocketPath is the default path of the socket serving the profiles and the runtime statistics.
+	DefaultDebugSocketPath = "/run/authd-debug.sock"
+
 	// DefaultBrokersConfPath is the default configuration directory for the brokers.
 	DefaultBrokersConfPath = "/etc/authd/brokers.d/"
 
@@ -26,4 +32,10 @@ const (
 
 	// ServiceName is the authd service name for health check purposes.
 	ServiceName = "com.ubuntu.authd"
+
+	// GDMProtocolVersion is the version of the JSON protocol spoken between the PAM module and the GDM greeter.
+	GDMProtocolVersion = 1
+
+	// BrokerProtocolVersion is the version of the D-Bus interface implemented by the brokers.
+	BrokerProtocolVersion = 1
 )
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"time"
 
 	"github.com/coreos/go-systemd/v22/activation"
 	"github.com/coreos/go-systemd/v22/daemon"
+	"github.com/ubuntu/authd/internal/clock"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
 	"google.golang.org/grpc"
@@ -16,18 +18,28 @@ import (
 
 // Daemon is a grpc daemon with systemd support.
 type Daemon struct {
-	grpcServer *grpc.Server
-	lis        net.Listener
-
-	systemdSdNotifier systemdSdNotifier
+	grpcServer  *grpc.Server
+	lis         net.Listener
+	idleTimeout time.Duration
+	clock       clock.Clock
+	healthCheck func(context.Context) error
+	busy        func() bool
+
+	systemdSdNotifier      systemdSdNotifier
+	systemdWatchdogEnabled func(unsetEnvironment bool) (time.Duration, error)
 }
 
 type options struct {
-	socketPath string
+	socketPath  string
+	idleTimeout time.Duration
+	clock       clock.Clock
+	healthCheck func(context.Context) error
+	busy        func() bool
 
 	// private member that we export for tests.
 	systemdActivationListener func() ([]net.Listener, error)
 	systemdSdNotifier         func(unsetEnvironment bool, state string) (bool, error)
+	systemdWatchdogEnabled    func(unsetEnvironment bool) (time.Duration, error)
 }
 
 type systemdSdNotifier func(unsetEnvironment bool, state string) (bool, error)
@@ -42,6 +54,30 @@ func WithSocketPath(p string) func(o *options) {
 	}
 }
 
+// WithIdleTimeout makes the daemon quit once no client has been connected for the given duration.
+// This is only used with systemd socket activation, as systemd starts the daemon again on the next connection.
+func WithIdleTimeout(timeout time.Duration) func(o *options) {
+	return func(o *options) {
+		o.idleTimeout = timeout
+	}
+}
+
+// WithHealthCheck makes the daemon only send the systemd watchdog heartbeats while the health check succeeds. A health
+// check which hangs stops the heartbeats too, so that systemd restarts the daemon.
+func WithHealthCheck(f func(context.Context) error) func(o *options) {
+	return func(o *options) {
+		o.healthCheck = f
+	}
+}
+
+// WithBusyCheck makes the daemon not quit on idle timeout while busy returns true, for example because it would lose
+// some state which is only kept in memory.
+func WithBusyCheck(busy func() bool) func(o *options) {
+	return func(o *options) {
+		o.busy = busy
+	}
+}
+
 // GRPCServiceRegisterer is a function that the daemon will call everytime we want to build a new GRPC object.
 type GRPCServiceRegisterer func(context.Context) *grpc.Server
 
@@ -55,9 +91,11 @@ func New(ctx context.Context, registerGRPCService GRPCServiceRegisterer, args ..
 	// Set default options.
 	opts := options{
 		socketPath: "",
+		clock:      clock.Default(),
 
 		systemdActivationListener: activation.Listeners,
 		systemdSdNotifier:         daemon.SdNotify,
+		systemdWatchdogEnabled:    daemon.SdWatchdogEnabled,
 	}
 	// Apply given args.
 	for _, f := range args {
@@ -81,6 +119,11 @@ func New(ctx context.Context, registerGRPCService GRPCServiceRegisterer, args ..
 		if err = os.Chmod(opts.socketPath, 0666); err != nil {
 			return nil, fmt.Errorf("could not change socket permission: %v", err)
 		}
+
+		if opts.idleTimeout > 0 {
+			log.Warning(ctx, "Ignoring idle timeout: it's only supported with socket activation")
+			opts.idleTimeout = 0
+		}
 	} else {
 		log.Debug(ctx, "Use socket activation")
 
@@ -102,10 +145,15 @@ func New(ctx context.Context, registerGRPCService GRPCServiceRegisterer, args ..
 	}
 
 	return &Daemon{
-		grpcServer: registerGRPCService(ctx),
-		lis:        lis,
-
-		systemdSdNotifier: opts.systemdSdNotifier,
+		grpcServer:  registerGRPCService(ctx),
+		lis:         lis,
+		idleTimeout: opts.idleTimeout,
+		clock:       opts.clock,
+		healthCheck: opts.healthCheck,
+		busy:        opts.busy,
+
+		systemdSdNotifier:      opts.systemdSdNotifier,
+		systemdWatchdogEnabled: opts.systemdWatchdogEnabled,
 	}, nil
 }
 
@@ -116,14 +164,31 @@ func (d *Daemon) Serve(ctx context.Context) (err error) {
 	log.Debugf(ctx, "Starting to serve requests on %s", d.lis.Addr())
 
 	// Signal to systemd that we are ready.
-	if sent, err := d.systemdSdNotifier(false, "READY=1"); err != nil {
+	if sent, err := d.systemdSdNotifier(false, daemon.SdNotifyReady); err != nil {
 		return fmt.Errorf( /*i18n.G(*/ "couldn't send ready notification to systemd: %v" /*)*/, err)
 	} else if sent {
 		log.Debug(context.Background(), "Ready state sent to systemd")
 	}
 
+	if interval, err := d.systemdWatchdogEnabled(false); err != nil {
+		log.Warningf(ctx, "Not sending heartbeats to the systemd watchdog: %v", err)
+	} else if interval > 0 {
+		watchdogCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go d.watchdog(watchdogCtx, interval)
+	}
+
+	lis := d.lis
+	if d.idleTimeout > 0 {
+		log.Infof(ctx, "Quitting after %s without any client connected", d.idleTimeout)
+		lis = newIdleListener(d.lis, d.idleTimeout, d.clock, d.busy, func() {
+			log.Infof(ctx, "No client connected for %s, quitting", d.idleTimeout)
+			d.Quit(ctx, false)
+		})
+	}
+
 	log.Infof(ctx, "Serving gRPC requests on %v", d.lis.Addr())
-	if err := d.grpcServer.Serve(d.lis); err != nil {
+	if err := d.grpcServer.Serve(lis); err != nil {
 		return fmt.Errorf("gRPC error: %v", err)
 	}
 	return nil
@@ -133,6 +198,10 @@ func (d *Daemon) Serve(ctx context.Context) (err error) {
 // It can drops any existing connexion is force is true.
 func (d Daemon) Quit(ctx context.Context, force bool) {
 	log.Info(ctx, "Stopping daemon requested.")
+	if _, err := d.systemdSdNotifier(false, daemon.SdNotifyStopping); err != nil {
+		log.Warningf(ctx, "Couldn't send stopping notification to systemd: %v", err)
+	}
+
 	if force {
 		d.grpcServer.Stop()
 		return
@@ -142,3 +211,31 @@ func (d Daemon) Quit(ctx context.Context, force bool) {
 	d.grpcServer.GracefulStop()
 	log.Debug(ctx, "All connections have now ended.")
 }
+
+// watchdog sends a heartbeat to systemd twice per watchdog interval until ctx is done, as long as the health check
+// succeeds. Systemd restarts the daemon when it misses the heartbeats, for instance because the database hangs.
+func (d *Daemon) watchdog(ctx context.Context, interval time.Duration) {
+	log.Debugf(ctx, "Sending heartbeats to the systemd watchdog every %s", interval/2)
+
+	// The interval is set by systemd, so it's not scaled like the other durations waited by the daemon.
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if d.healthCheck != nil {
+			if err := d.healthCheck(ctx); err != nil {
+				log.Warningf(ctx, "Skipping the systemd watchdog heartbeat: %v", err)
+				continue
+			}
+		}
+		if _, err := d.systemdSdNotifier(false, daemon.SdNotifyWatchdog); err != nil {
+			log.Warningf(ctx, "Couldn't send heartbeat to the systemd watchdog: %v", err)
+		}
+	}
+}
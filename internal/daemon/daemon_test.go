@@ -7,6 +7,8 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -292,6 +294,191 @@ func TestQuit(t *testing.T) {
 	}
 }
 
+func TestIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	const idleTimeout = 200 * time.Millisecond
+
+	testCases := map[string]struct {
+		manualSocket     bool
+		clientConnection bool
+		busy             bool
+
+		wantQuit bool
+	}{
+		"Quit_when_no_client_connects":          {wantQuit: true},
+		"Quit_once_the_last_client_disconnects": {clientConnection: true, wantQuit: true},
+
+		"Do_not_quit_when_socket_is_not_activated": {manualSocket: true},
+		"Do_not_quit_while_busy":                   {busy: true},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			registerGRPC := func(context.Context) *grpc.Server {
+				grpcServer := grpc.NewServer(grpc.UnaryInterceptor(errmessages.RedactErrorInterceptor))
+				hc := health.NewServer()
+				hc.SetServingStatus(consts.ServiceName, healthpb.HealthCheckResponse_SERVING)
+				healthgrpc.RegisterHealthServer(grpcServer, hc)
+				return grpcServer
+			}
+			systemdNotifier := func(unsetEnvironment bool, state string) (bool, error) {
+				return true, nil
+			}
+
+			socketPath := filepath.Join(t.TempDir(), "authd.socket")
+			args := []daemon.Option{
+				daemon.WithSystemdSdNotifier(systemdNotifier),
+				daemon.WithIdleTimeout(idleTimeout),
+				daemon.WithBusyCheck(func() bool { return tc.busy }),
+			}
+			if tc.manualSocket {
+				args = append(args, daemon.WithSocketPath(socketPath))
+			} else {
+				l, err := net.Listen("unix", socketPath)
+				require.NoError(t, err, "Setup: couldn't create unix socket")
+				t.Cleanup(func() { l.Close() })
+				args = append(args, daemon.WithSystemdActivationListener(func() ([]net.Listener, error) {
+					return []net.Listener{l}, nil
+				}))
+			}
+
+			d, err := daemon.New(context.Background(), registerGRPC, args...)
+			require.NoError(t, err, "Setup: New() should not return an error")
+
+			var conn *grpc.ClientConn
+			if tc.clientConnection {
+				conn, err = grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+				require.NoError(t, err, "Setup: could not create client")
+			}
+
+			serveDone := make(chan error)
+			go func() { serveDone <- d.Serve(context.Background()) }()
+
+			if conn != nil {
+				err = grpcutils.WaitForConnection(context.Background(), conn, 5*time.Second)
+				require.NoError(t, err, "Setup: client could not connect")
+
+				select {
+				case <-serveDone:
+					require.Fail(t, "Serve() should not return while a client is connected")
+				case <-time.After(3 * idleTimeout):
+				}
+				require.NoError(t, conn.Close(), "Setup: could not disconnect client")
+			}
+
+			if !tc.wantQuit {
+				select {
+				case <-serveDone:
+					require.Fail(t, "Serve() should not return on idle timeout")
+				case <-time.After(3 * idleTimeout):
+				}
+				d.Quit(context.Background(), false)
+			}
+
+			select {
+			case err := <-serveDone:
+				require.NoError(t, err, "Serve() should not return an error")
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "Serve() should have returned")
+			}
+		})
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	t.Parallel()
+
+	const watchdogInterval = 100 * time.Millisecond
+
+	testCases := map[string]struct {
+		watchdogDisabled bool
+		watchdogErr      bool
+		healthCheckErr   bool
+		healthCheckHangs bool
+		noHealthCheck    bool
+
+		wantHeartbeats bool
+	}{
+		"Send_heartbeats_while_healthy":            {wantHeartbeats: true},
+		"Send_heartbeats_without_any_health_check": {noHealthCheck: true, wantHeartbeats: true},
+
+		"No_heartbeats_if_watchdog_is_disabled":              {watchdogDisabled: true},
+		"No_heartbeats_if_watchdog_state_can_not_be_queried": {watchdogErr: true},
+		"No_heartbeats_while_health_check_fails":             {healthCheckErr: true},
+		"No_heartbeats_while_health_check_hangs":             {healthCheckHangs: true},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			registerGRPC := func(context.Context) *grpc.Server {
+				return grpc.NewServer(grpc.UnaryInterceptor(errmessages.RedactErrorInterceptor))
+			}
+
+			var statesMu sync.Mutex
+			var states []string
+			systemdNotifier := func(unsetEnvironment bool, state string) (bool, error) {
+				statesMu.Lock()
+				defer statesMu.Unlock()
+				states = append(states, state)
+				return true, nil
+			}
+			watchdogEnabled := func(unsetEnvironment bool) (time.Duration, error) {
+				if tc.watchdogErr {
+					return 0, errors.New("invalid watchdog configuration")
+				}
+				if tc.watchdogDisabled {
+					return 0, nil
+				}
+				return watchdogInterval, nil
+			}
+
+			hang := make(chan struct{})
+			t.Cleanup(func() { close(hang) })
+			args := []daemon.Option{
+				daemon.WithSystemdSdNotifier(systemdNotifier),
+				daemon.WithSystemdWatchdogEnabled(watchdogEnabled),
+				daemon.WithSocketPath(filepath.Join(t.TempDir(), "authd.socket")),
+			}
+			if !tc.noHealthCheck {
+				args = append(args, daemon.WithHealthCheck(func(context.Context) error {
+					if tc.healthCheckHangs {
+						<-hang
+					}
+					if tc.healthCheckErr {
+						return errors.New("health check failure")
+					}
+					return nil
+				}))
+			}
+
+			d, err := daemon.New(context.Background(), registerGRPC, args...)
+			require.NoError(t, err, "Setup: New() should not return an error")
+
+			serveDone := make(chan error)
+			go func() { serveDone <- d.Serve(context.Background()) }()
+
+			time.Sleep(3 * watchdogInterval)
+			d.Quit(context.Background(), false)
+			require.NoError(t, <-serveDone, "Serve() should not return an error")
+
+			statesMu.Lock()
+			defer statesMu.Unlock()
+			require.Equal(t, "READY=1", states[0], "Ready state should be sent first")
+			require.Contains(t, states, "STOPPING=1", "Stopping state should be sent when quitting")
+
+			heartbeats := slices.DeleteFunc(slices.Clone(states), func(s string) bool { return s != "WATCHDOG=1" })
+			if !tc.wantHeartbeats {
+				require.Empty(t, heartbeats, "No heartbeat should be sent to the watchdog")
+				return
+			}
+			require.GreaterOrEqual(t, len(heartbeats), 2, "Heartbeats should be sent twice per watchdog interval")
+		})
+	}
+}
+
 func createClientConnection(t *testing.T, socketPath string) (success bool, disconnect func()) {
 	t.Helper()
 
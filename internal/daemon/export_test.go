@@ -1,6 +1,9 @@
 package daemon
 
-import "net"
+import (
+	"net"
+	"time"
+)
 
 func WithSystemdActivationListener(f func() ([]net.Listener, error)) func(o *options) {
 	return func(o *options) {
@@ -14,6 +17,12 @@ func WithSystemdSdNotifier(f func(unsetEnvironment bool, state string) (bool, er
 	}
 }
 
+func WithSystemdWatchdogEnabled(f func(unsetEnvironment bool) (time.Duration, error)) func(o *options) {
+	return func(o *options) {
+		o.systemdWatchdogEnabled = f
+	}
+}
+
 func (d Daemon) SelectedSocketAddr() string {
 	return d.lis.Addr().String()
 }
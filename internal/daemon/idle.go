@@ -0,0 +1,101 @@
+package daemon
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ubuntu/authd/internal/clock"
+)
+
+// idleListener is a listener calling onIdle once no connection has been open for the given timeout, unless busy
+// returns true. It then waits for another timeout.
+type idleListener struct {
+	net.Listener
+
+	timeout time.Duration
+	busy    func() bool
+	onIdle  func()
+
+	mu     sync.Mutex
+	active int
+	timer  clock.Timer
+}
+
+func newIdleListener(lis net.Listener, timeout time.Duration, c clock.Clock, busy func() bool, onIdle func()) *idleListener {
+	l := &idleListener{
+		Listener: lis,
+		timeout:  timeout,
+		busy:     busy,
+		onIdle:   onIdle,
+	}
+	l.timer = c.AfterFunc(timeout, l.idle)
+	return l
+}
+
+// Accept waits for the next connection, tracking it until it gets closed.
+func (l *idleListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active++
+	l.timer.Stop()
+
+	return &trackedConn{Conn: c, onClose: l.connectionClosed}, nil
+}
+
+// Close closes the listener and stops the idle timer.
+func (l *idleListener) Close() error {
+	l.timer.Stop()
+	return l.Listener.Close()
+}
+
+// connectionClosed restarts the idle timer once the last connection is closed.
+func (l *idleListener) connectionClosed() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	if l.active == 0 {
+		l.timer.Reset(l.timeout)
+	}
+}
+
+func (l *idleListener) idle() {
+	l.mu.Lock()
+	active := l.active
+	l.mu.Unlock()
+
+	// A connection may have been accepted while the timer was firing.
+	if active > 0 {
+		return
+	}
+	if l.busy != nil && l.busy() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.active == 0 {
+			l.timer.Reset(l.timeout)
+		}
+		return
+	}
+	l.onIdle()
+}
+
+// trackedConn is a connection calling onClose once closed.
+type trackedConn struct {
+	net.Conn
+
+	onClose   func()
+	closeOnce sync.Once
+}
+
+// Close closes the connection, notifying it only the first time.
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(c.onClose)
+	return err
+}
@@ -0,0 +1,185 @@
+// Package debugserver serves the profiles of net/http/pprof and the runtime statistics of expvar on a unix socket, so
+// that the performance problems of the daemon can be investigated on production machines without rebuilding it.
+package debugserver
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+	"golang.org/x/sys/unix"
+)
+
+type options struct {
+	rootUID uint32
+}
+
+// Option represents an optional function to override the Server default values.
+type Option func(*options)
+
+// Server is the debug server, which only accepts the connections of root on its socket.
+type Server struct {
+	path    string
+	rootUID uint32
+
+	httpServer *http.Server
+	served     chan struct{}
+	mu         sync.Mutex
+}
+
+// New returns a debug server listening on the socket at path once started.
+func New(path string, args ...Option) *Server {
+	opts := options{rootUID: 0}
+	for _, arg := range args {
+		arg(&opts)
+	}
+
+	return &Server{path: path, rootUID: opts.rootUID}
+}
+
+// Path returns the path of the socket of the server.
+func (s *Server) Path() string {
+	return s.path
+}
+
+// Running returns whether the server is serving on its socket.
+func (s *Server) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.httpServer != nil
+}
+
+// Start starts serving on the socket, replacing any stale one. It does nothing if the server is already running.
+func (s *Server) Start() (err error) {
+	defer decorate.OnError(&err, "can't start debug server on %q", s.path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpServer != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	l, err := net.Listen("unix", s.path)
+	if err != nil {
+		return err
+	}
+	// The connections of other users are refused anyway, but don't let them connect in the first place.
+	if err := os.Chmod(s.path, 0600); err != nil {
+		_ = l.Close()
+		return err
+	}
+
+	s.httpServer = &http.Server{Handler: newMux(), ReadHeaderTimeout: 10 * time.Second}
+	s.served = make(chan struct{})
+	go func(srv *http.Server, served chan struct{}) {
+		defer close(served)
+		if err := srv.Serve(rootOnlyListener{Listener: l, rootUID: s.rootUID}); !errors.Is(err, http.ErrServerClosed) {
+			log.Warningf(context.Background(), "Debug server stopped: %v", err)
+		}
+	}(s.httpServer, s.served)
+
+	log.Noticef(context.Background(), "Serving profiling and runtime statistics on %s", s.path)
+	return nil
+}
+
+// Stop stops serving and removes the socket. It does nothing if the server isn't running.
+func (s *Server) Stop() (err error) {
+	defer decorate.OnError(&err, "can't stop debug server")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpServer == nil {
+		return nil
+	}
+
+	err = s.httpServer.Close()
+	<-s.served
+	s.httpServer = nil
+	if rmErr := os.Remove(s.path); rmErr != nil && !errors.Is(rmErr, fs.ErrNotExist) {
+		err = errors.Join(err, rmErr)
+	}
+
+	log.Noticef(context.Background(), "Stopped serving profiling and runtime statistics on %s", s.path)
+	return err
+}
+
+// newMux returns the handler of the profiles and the runtime statistics.
+func newMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// rootOnlyListener closes the connections of the peers which are not root.
+type rootOnlyListener struct {
+	net.Listener
+	rootUID uint32
+}
+
+// Accept returns the next connection of root.
+func (l rootOnlyListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		uid, err := peerUID(conn)
+		if err == nil && uid == l.rootUID {
+			return conn, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("peer UID %d is not root", uid)
+		}
+		log.Warningf(context.Background(), "Refused connection to the debug server: %v", err)
+		_ = conn.Close()
+	}
+}
+
+// peerUID returns the UID of the peer of the unix socket connection.
+func peerUID(conn net.Conn) (uid uint32, err error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, errors.New("not a unix socket connection")
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var cred *unix.Ucred
+	var credErr error
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+	return cred.Uid, nil
+}
@@ -0,0 +1,80 @@
+package debugserver_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/debugserver"
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		otherRoot bool
+
+		wantRefused bool
+	}{
+		"Serve_runtime_statistics_to_root": {},
+
+		"Refuse_connections_of_other_users": {otherRoot: true, wantRefused: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rootUID := uint32(os.Getuid())
+			if tc.otherRoot {
+				rootUID++
+			}
+			path := filepath.Join(t.TempDir(), "debug", "authd-debug.sock")
+			s := debugserver.New(path, debugserver.WithRootUID(rootUID))
+			require.False(t, s.Running(), "The server should not run before being started")
+
+			require.NoError(t, s.Start(), "Start should not return an error, but did")
+			t.Cleanup(func() { _ = s.Stop() })
+			require.NoError(t, s.Start(), "Start should do nothing when the server is already running")
+			require.True(t, s.Running(), "The server should run once started")
+
+			fi, err := os.Stat(path)
+			require.NoError(t, err, "The socket should be created")
+			require.Equal(t, os.FileMode(0600), fi.Mode().Perm(), "The socket should only be accessible to its owner")
+
+			body, err := get(path, "/debug/vars")
+			if tc.wantRefused {
+				require.Error(t, err, "The connection should be refused")
+			} else {
+				require.NoError(t, err, "The runtime statistics should be served")
+				require.Contains(t, body, "memstats", "The runtime statistics should be served")
+			}
+
+			require.NoError(t, s.Stop(), "Stop should not return an error, but did")
+			require.False(t, s.Running(), "The server should not run once stopped")
+			require.NoFileExists(t, path, "The socket should be removed once stopped")
+			require.NoError(t, s.Stop(), "Stop should do nothing when the server is not running")
+		})
+	}
+}
+
+// get returns the body of the response to a GET request of the path on the unix socket.
+func get(socket, path string) (string, error) {
+	client := http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := client.Get("http://authd" + path)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
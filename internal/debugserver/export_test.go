@@ -0,0 +1,8 @@
+package debugserver
+
+// WithRootUID makes the server accept the connections of the given user as root for tests.
+func WithRootUID(uid uint32) Option {
+	return func(o *options) {
+		o.rootUID = uid
+	}
+}
@@ -0,0 +1,100 @@
+package doctor
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/ubuntu/authd/internal/consts"
+)
+
+// redactedValue replaces the values of the secrets in the files of the support bundle.
+const redactedValue = "REDACTED"
+
+// secretLineRegexp matches the INI and YAML lines setting a value whose key looks like a secret, capturing the key
+// and its separator.
+var secretLineRegexp = regexp.MustCompile(`(?im)^(\s*[#;]?\s*[\w.-]*(?:secret|password|token|key|pin)[\w.-]*\s*[=:][ \t]*)\S.*$`)
+
+// WriteBundle writes to w a gzipped tarball with the results of the diagnostic checks and the configuration files
+// of authd, its brokers, NSS and PAM. The values of the secrets in the configuration files are redacted.
+func WriteBundle(w io.Writer, results []Result, args ...Option) (err error) {
+	opts := newOptions(args)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	defer func() {
+		err = errors.Join(err, tw.Close(), gz.Close())
+	}()
+
+	content, err := json.MarshalIndent(struct {
+		Version string   `json:"version"`
+		Results []Result `json:"results"`
+	}{Version: consts.Version, Results: results}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal diagnostic results: %v", err)
+	}
+	if err := addToBundle(tw, "results.json", content); err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		"authd.yaml":    opts.configFile,
+		"nsswitch.conf": opts.nsswitchFile,
+	}
+	for _, name := range []string{"common-auth", "common-account", "common-password", "common-session", "gdm-authd"} {
+		files[filepath.Join("pam.d", name)] = filepath.Join(opts.pamDir, name)
+	}
+	brokers, err := filepath.Glob(filepath.Join(opts.brokersDir, "*.conf"))
+	if err != nil {
+		return fmt.Errorf("could not list the brokers configuration files: %v", err)
+	}
+	for _, b := range brokers {
+		files[filepath.Join("brokers.d", filepath.Base(b))] = b
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		content, err := os.ReadFile(files[name])
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not read %s: %v", files[name], err)
+		}
+		if err := addToBundle(tw, name, redact(content)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addToBundle adds a file with the given name and content to the tarball.
+func addToBundle(tw *tar.Writer, name string, content []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("could not add %s to the support bundle: %v", name, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("could not add %s to the support bundle: %v", name, err)
+	}
+	return nil
+}
+
+// redact returns the content with the values of the secrets replaced.
+func redact(content []byte) []byte {
+	return secretLineRegexp.ReplaceAll(content, []byte("${1}"+redactedValue))
+}
@@ -0,0 +1,345 @@
+// Package doctor diagnoses the setup of authd on the system, and collects the information needed to report issues.
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/ubuntu/authd/internal/consts"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/log"
+	"gopkg.in/ini.v1"
+)
+
+// Status is the status of a diagnostic check.
+type Status string
+
+const (
+	// Passed is the status of a check which found no issue.
+	Passed Status = "passed"
+	// Warning is the status of a check which found an issue that doesn't prevent authd from working.
+	Warning Status = "warning"
+	// Failed is the status of a check which found an issue preventing authd from working.
+	Failed Status = "failed"
+	// Skipped is the status of a check which depends on a failed one.
+	Skipped Status = "skipped"
+)
+
+// Result is the result of a diagnostic check.
+type Result struct {
+	Check   string `json:"check" yaml:"check"`
+	Status  Status `json:"status" yaml:"status"`
+	Details string `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+const (
+	// defaultMinFreeSpace is the free space of the database directory under which a warning is reported.
+	defaultMinFreeSpace = 100 * 1024 * 1024
+	// callTimeout is how long the daemon and the brokers have to answer.
+	callTimeout = 5 * time.Second
+)
+
+type options struct {
+	brokersDir        string
+	dbDir             string
+	configFile        string
+	nsswitchFile      string
+	pamDir            string
+	nssModulePatterns []string
+	minFreeSpace      uint64
+}
+
+// Option is the function signature used to tweak the diagnostic checks.
+type Option func(*options)
+
+// WithBrokersDir checks the brokers configured in the given directory.
+func WithBrokersDir(dir string) Option {
+	return func(o *options) {
+		o.brokersDir = dir
+	}
+}
+
+// WithDatabaseDir checks the database stored in the given directory.
+func WithDatabaseDir(dir string) Option {
+	return func(o *options) {
+		o.dbDir = dir
+	}
+}
+
+// WithConfigFile collects the given daemon configuration file in the support bundle.
+func WithConfigFile(path string) Option {
+	return func(o *options) {
+		o.configFile = path
+	}
+}
+
+// WithNSSwitchFile checks the given NSS configuration file instead of /etc/nsswitch.conf.
+// This option is only useful in tests.
+func WithNSSwitchFile(path string) Option {
+	return func(o *options) {
+		o.nsswitchFile = path
+	}
+}
+
+// WithPAMDir checks the PAM configuration files of the given directory instead of /etc/pam.d.
+// This option is only useful in tests.
+func WithPAMDir(dir string) Option {
+	return func(o *options) {
+		o.pamDir = dir
+	}
+}
+
+// WithNSSModulePatterns looks for the NSS module in the paths matching the given patterns.
+// This option is only useful in tests.
+func WithNSSModulePatterns(patterns ...string) Option {
+	return func(o *options) {
+		o.nssModulePatterns = patterns
+	}
+}
+
+// WithMinFreeSpace reports a warning if the database directory has less free space than the given number of bytes.
+// This option is only useful in tests.
+func WithMinFreeSpace(bytes uint64) Option {
+	return func(o *options) {
+		o.minFreeSpace = bytes
+	}
+}
+
+func newOptions(args []Option) options {
+	opts := options{
+		brokersDir:   consts.DefaultBrokersConfPath,
+		dbDir:        consts.DefaultDatabaseDir,
+		configFile:   "/etc/authd/authd.yaml",
+		nsswitchFile: "/etc/nsswitch.conf",
+		pamDir:       "/etc/pam.d",
+		nssModulePatterns: []string{
+			"/usr/lib/*/libnss_authd.so.2",
+			"/lib/*/libnss_authd.so.2",
+			"/usr/lib/libnss_authd.so.2",
+		},
+		minFreeSpace: defaultMinFreeSpace,
+	}
+	for _, f := range args {
+		f(&opts)
+	}
+	return opts
+}
+
+// runner runs the diagnostic checks, collecting their results.
+type runner struct {
+	opts options
+
+	results []Result
+}
+
+func (r *runner) add(check string, status Status, format string, a ...any) {
+	details := fmt.Sprintf(format, a...)
+	log.Debugf(context.Background(), "Diagnostic check %q %s: %s", check, status, details)
+	r.results = append(r.results, Result{Check: check, Status: status, Details: details})
+}
+
+// Run runs all the diagnostic checks, reaching the daemon with the given client.
+func Run(ctx context.Context, client authd.PAMClient, args ...Option) []Result {
+	r := runner{opts: newOptions(args)}
+
+	availableBrokers, daemonOK := r.checkDaemon(ctx, client)
+	r.checkBrokers(ctx, availableBrokers, daemonOK)
+	r.checkNSSModule()
+	r.checkNSSwitch()
+	r.checkPAM()
+	r.checkDatabase()
+	r.checkDiskSpace()
+
+	return r.results
+}
+
+// checkDaemon checks that the daemon answers on its socket, and returns the names of the brokers it loaded.
+func (r *runner) checkDaemon(ctx context.Context, client authd.PAMClient) (brokers []string, ok bool) {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	resp, err := client.AvailableBrokers(ctx, &authd.Empty{})
+	if err != nil {
+		r.add("daemon", Failed, "could not reach the daemon: %v", err)
+		return nil, false
+	}
+	for _, b := range resp.GetBrokersInfos() {
+		brokers = append(brokers, b.GetName())
+	}
+	r.add("daemon", Passed, "the daemon answers with %d available brokers", len(brokers))
+	return brokers, true
+}
+
+// checkBrokers checks that each configured broker is reachable on the system bus and loaded by the daemon.
+func (r *runner) checkBrokers(ctx context.Context, availableBrokers []string, daemonOK bool) {
+	files, err := filepath.Glob(filepath.Join(r.opts.brokersDir, "*.conf"))
+	if err != nil {
+		r.add("brokers", Failed, "could not list the brokers configuration files: %v", err)
+		return
+	}
+	if len(files) == 0 {
+		r.add("brokers", Warning, "no broker configured in %s, only local users can log in", r.opts.brokersDir)
+		return
+	}
+
+	// Don't call dbus.SystemBus which caches globally the system bus connection.
+	bus, err := dbus.ConnectSystemBus()
+	if err != nil {
+		r.add("brokers", Failed, "could not connect to the system bus: %v", err)
+		return
+	}
+	defer bus.Close()
+
+	for _, f := range files {
+		check := "broker " + filepath.Base(f)
+
+		cfg, err := ini.Load(f)
+		if err != nil {
+			r.add(check, Failed, "invalid configuration file: %v", err)
+			continue
+		}
+		section := cfg.Section("authd")
+		name := section.Key("name").String()
+		dbusName := section.Key("dbus_name").String()
+		dbusObject := section.Key("dbus_object").String()
+		if name == "" || dbusName == "" || dbusObject == "" {
+			r.add(check, Failed, "the configuration file must set the name, dbus_name and dbus_object fields")
+			continue
+		}
+
+		if err := pingBroker(ctx, bus, dbusName, dbusObject); err != nil {
+			r.add(check, Failed, "broker %q is not reachable on the system bus: %v", name, err)
+			continue
+		}
+		if !daemonOK {
+			r.add(check, Skipped, "broker %q is reachable, but the daemon is not", name)
+			continue
+		}
+		if !slices.Contains(availableBrokers, name) {
+			r.add(check, Failed, "broker %q is reachable, but was not loaded by the daemon: restart the daemon or check its logs", name)
+			continue
+		}
+		r.add(check, Passed, "broker %q is reachable and loaded by the daemon", name)
+	}
+}
+
+// pingBroker checks that the D-Bus object of the broker answers.
+func pingBroker(ctx context.Context, bus *dbus.Conn, dbusName, dbusObject string) error {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	return bus.Object(dbusName, dbus.ObjectPath(dbusObject)).CallWithContext(ctx, "org.freedesktop.DBus.Peer.Ping", 0).Err
+}
+
+// checkNSSModule checks that the NSS module of authd is installed.
+func (r *runner) checkNSSModule() {
+	for _, p := range r.opts.nssModulePatterns {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			r.add("nss_module", Failed, "invalid NSS module pattern %q: %v", p, err)
+			return
+		}
+		if len(matches) > 0 {
+			r.add("nss_module", Passed, "the NSS module is installed at %s", strings.Join(matches, ", "))
+			return
+		}
+	}
+	r.add("nss_module", Failed, "the NSS module libnss_authd.so.2 is not installed")
+}
+
+// checkNSSwitch checks that the NSS configuration looks up the users, groups and shadow entries with authd.
+func (r *runner) checkNSSwitch() {
+	content, err := os.ReadFile(r.opts.nsswitchFile)
+	if err != nil {
+		r.add("nsswitch", Failed, "could not read the NSS configuration: %v", err)
+		return
+	}
+
+	configured := make(map[string]bool)
+	for _, line := range nonCommentLines(content) {
+		database, sources, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		configured[strings.TrimSpace(database)] = slices.Contains(strings.Fields(sources), "authd")
+	}
+
+	var missing []string
+	for _, database := range []string{"passwd", "group", "shadow"} {
+		if !configured[database] {
+			missing = append(missing, database)
+		}
+	}
+	if len(missing) > 0 {
+		r.add("nsswitch", Failed, "authd is not a source of the %s databases in %s", strings.Join(missing, ", "), r.opts.nsswitchFile)
+		return
+	}
+	r.add("nsswitch", Passed, "authd is a source of the passwd, group and shadow databases")
+}
+
+// checkPAM checks that the common PAM authentication stack uses one of the PAM modules of authd.
+func (r *runner) checkPAM() {
+	path := filepath.Join(r.opts.pamDir, "common-auth")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		r.add("pam", Failed, "could not read the PAM configuration: %v", err)
+		return
+	}
+
+	for _, line := range nonCommentLines(content) {
+		fields := strings.Fields(line)
+		if slices.Contains(fields, "pam_authd_exec.so") || slices.Contains(fields, "pam_authd.so") {
+			r.add("pam", Passed, "the PAM module is in the authentication stack")
+			return
+		}
+	}
+	r.add("pam", Failed, "the PAM module is not in the authentication stack of %s: enable it with pam-auth-update", path)
+}
+
+// checkDatabase checks that the database is not corrupted.
+func (r *runner) checkDatabase() {
+	if err := db.CheckIntegrity(r.opts.dbDir); err != nil {
+		r.add("database", Failed, "%v", err)
+		return
+	}
+	r.add("database", Passed, "the database is consistent")
+}
+
+// checkDiskSpace checks that there is enough free space left to store the database.
+func (r *runner) checkDiskSpace() {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(r.opts.dbDir, &stat); err != nil {
+		r.add("disk_space", Failed, "could not get the free space of %s: %v", r.opts.dbDir, err)
+		return
+	}
+
+	free := stat.Bavail * uint64(stat.Bsize)
+	if free < r.opts.minFreeSpace {
+		r.add("disk_space", Warning, "only %d MiB left in %s", free/(1024*1024), r.opts.dbDir)
+		return
+	}
+	r.add("disk_space", Passed, "%d MiB left in %s", free/(1024*1024), r.opts.dbDir)
+}
+
+// nonCommentLines returns the lines of the content which are neither empty nor comments.
+func nonCommentLines(content []byte) (lines []string) {
+	s := bufio.NewScanner(bytes.NewReader(content))
+	for s.Scan() {
+		line, _, _ := strings.Cut(s.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
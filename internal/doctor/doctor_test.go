@@ -0,0 +1,305 @@
+package doctor_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/doctor"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/testutils"
+	"github.com/ubuntu/authd/internal/testutils/golden"
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/log"
+	"google.golang.org/grpc"
+)
+
+// brokerConfig is the configuration file of the broker exported on the system bus for the tests.
+var brokerConfig []byte
+
+const (
+	validNSSwitch = "passwd: files systemd authd\ngroup: files systemd authd\nshadow: files authd\n"
+	validPAMAuth  = "auth [success=end default=die] pam_authd_exec.so /usr/libexec/authd-pam\n"
+)
+
+// pamClient is a PAM client answering the available brokers, or an error.
+type pamClient struct {
+	authd.PAMClient
+
+	brokers []string
+	err     error
+}
+
+func (c pamClient) AvailableBrokers(context.Context, *authd.Empty, ...grpc.CallOption) (*authd.ABResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	resp := &authd.ABResponse{}
+	for _, name := range c.brokers {
+		resp.BrokersInfos = append(resp.BrokersInfos, &authd.ABResponse_BrokerInfo{Name: name})
+	}
+	return resp, nil
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	allPassed := map[string]doctor.Status{
+		"daemon":                 doctor.Passed,
+		"broker BrokerMock.conf": doctor.Passed,
+		"nss_module":             doctor.Passed,
+		"nsswitch":               doctor.Passed,
+		"pam":                    doctor.Passed,
+		"database":               doctor.Passed,
+		"disk_space":             doctor.Passed,
+	}
+	with := func(statuses map[string]doctor.Status) map[string]doctor.Status {
+		r := make(map[string]doctor.Status)
+		for k, v := range allPassed {
+			r[k] = v
+		}
+		for k, v := range statuses {
+			r[k] = v
+		}
+		return r
+	}
+
+	tests := map[string]struct {
+		daemonErr         bool
+		brokerNotLoaded   bool
+		unreachableBroker bool
+		noBrokers         bool
+		nsswitch          string
+		pamAuth           string
+		noNSSModule       bool
+		noDatabase        bool
+		minFreeSpace      uint64
+
+		want map[string]doctor.Status
+	}{
+		"Valid_setup_passes_all_checks": {want: allPassed},
+
+		"Daemon_not_running_fails_and_skips_loaded_brokers_check": {
+			daemonErr: true,
+			want:      with(map[string]doctor.Status{"daemon": doctor.Failed, "broker BrokerMock.conf": doctor.Skipped}),
+		},
+		"Broker_not_loaded_by_daemon_fails": {
+			brokerNotLoaded: true,
+			want:            with(map[string]doctor.Status{"broker BrokerMock.conf": doctor.Failed}),
+		},
+		"Unreachable_broker_fails": {
+			unreachableBroker: true,
+			want:              with(map[string]doctor.Status{"broker Unreachable.conf": doctor.Failed}),
+		},
+		"No_configured_broker_warns": {
+			noBrokers: true,
+			want: map[string]doctor.Status{
+				"daemon":     doctor.Passed,
+				"brokers":    doctor.Warning,
+				"nss_module": doctor.Passed,
+				"nsswitch":   doctor.Passed,
+				"pam":        doctor.Passed,
+				"database":   doctor.Passed,
+				"disk_space": doctor.Passed,
+			},
+		},
+		"Missing_NSS_module_fails": {
+			noNSSModule: true,
+			want:        with(map[string]doctor.Status{"nss_module": doctor.Failed}),
+		},
+		"NSS_configuration_without_authd_fails": {
+			nsswitch: "passwd: files authd\ngroup: files\n# shadow: files authd\n",
+			want:     with(map[string]doctor.Status{"nsswitch": doctor.Failed}),
+		},
+		"PAM_stack_without_authd_fails": {
+			pamAuth: "auth required pam_unix.so\n# auth sufficient pam_authd_exec.so\n",
+			want:    with(map[string]doctor.Status{"pam": doctor.Failed}),
+		},
+		"Missing_database_fails": {
+			noDatabase: true,
+			want:       with(map[string]doctor.Status{"database": doctor.Failed}),
+		},
+		"Low_disk_space_warns": {
+			minFreeSpace: math.MaxUint64,
+			want:         with(map[string]doctor.Status{"disk_space": doctor.Warning}),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			brokersDir := t.TempDir()
+			if !tc.noBrokers {
+				writeFile(t, filepath.Join(brokersDir, "BrokerMock.conf"), string(brokerConfig))
+			}
+			if tc.unreachableBroker {
+				writeFile(t, filepath.Join(brokersDir, "Unreachable.conf"), strings.Join([]string{
+					"[authd]",
+					"name = Unreachable",
+					"brand_icon = icon.png",
+					"dbus_name = com.ubuntu.authd.Unreachable",
+					"dbus_object = /com/ubuntu/authd/Unreachable",
+				}, "\n"))
+			}
+
+			client := pamClient{brokers: []string{"local", "BrokerMock", "Unreachable"}}
+			if tc.brokerNotLoaded {
+				client.brokers = []string{"local"}
+			}
+			if tc.daemonErr {
+				client.err = errors.New("connection refused")
+			}
+
+			if tc.nsswitch == "" {
+				tc.nsswitch = validNSSwitch
+			}
+			if tc.pamAuth == "" {
+				tc.pamAuth = validPAMAuth
+			}
+
+			dir := t.TempDir()
+			nsswitchFile := filepath.Join(dir, "nsswitch.conf")
+			writeFile(t, nsswitchFile, tc.nsswitch)
+			pamDir := filepath.Join(dir, "pam.d")
+			writeFile(t, filepath.Join(pamDir, "common-auth"), tc.pamAuth)
+			nssModule := filepath.Join(dir, "lib", "libnss_authd.so.2")
+			if !tc.noNSSModule {
+				writeFile(t, nssModule, "")
+			}
+
+			dbDir := t.TempDir()
+			if !tc.noDatabase {
+				m, err := db.New(dbDir)
+				require.NoError(t, err, "Setup: could not create database")
+				require.NoError(t, m.Close(), "Setup: could not close database")
+			}
+
+			opts := []doctor.Option{
+				doctor.WithBrokersDir(brokersDir),
+				doctor.WithDatabaseDir(dbDir),
+				doctor.WithNSSwitchFile(nsswitchFile),
+				doctor.WithPAMDir(pamDir),
+				doctor.WithNSSModulePatterns(filepath.Join(dir, "*", "libnss_authd.so.2")),
+			}
+			if tc.minFreeSpace != 0 {
+				opts = append(opts, doctor.WithMinFreeSpace(tc.minFreeSpace))
+			}
+
+			results := doctor.Run(context.Background(), client, opts...)
+
+			got := make(map[string]doctor.Status)
+			for _, r := range results {
+				require.NotContains(t, got, r.Check, "Run should return each check once")
+				got[r.Check] = r.Status
+			}
+			require.Equal(t, tc.want, got, "Run should return the expected statuses, got %v", results)
+		})
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	brokersDir := filepath.Join(dir, "brokers.d")
+	writeFile(t, filepath.Join(brokersDir, "broker.conf"), strings.Join([]string{
+		"[authd]",
+		"name = Broker",
+		"dbus_name = com.ubuntu.authd.Broker",
+		"",
+		"[oidc]",
+		"issuer = https://issuer.example.com",
+		"client_id = client",
+		"client_secret = my-secret",
+		"; client_secret = my-old-secret",
+		"",
+	}, "\n"))
+	writeFile(t, filepath.Join(brokersDir, "ignored.txt"), "not a broker configuration\n")
+	configFile := filepath.Join(dir, "authd.yaml")
+	writeFile(t, configFile, "local_pin:\n  services: [sudo]\nstorage_hooks:\n  broker: [/usr/bin/hook, --token, abc]\napi_token: xyz\n")
+	nsswitchFile := filepath.Join(dir, "nsswitch.conf")
+	writeFile(t, nsswitchFile, validNSSwitch)
+	pamDir := filepath.Join(dir, "pam.d")
+	writeFile(t, filepath.Join(pamDir, "common-auth"), validPAMAuth)
+	writeFile(t, filepath.Join(pamDir, "sshd"), "@include common-auth\n")
+
+	results := []doctor.Result{
+		{Check: "daemon", Status: doctor.Passed, Details: "the daemon answers with 2 available brokers"},
+		{Check: "pam", Status: doctor.Failed, Details: "the PAM module is not in the authentication stack"},
+	}
+
+	var bundle strings.Builder
+	err := doctor.WriteBundle(&bundle, results,
+		doctor.WithBrokersDir(brokersDir),
+		doctor.WithConfigFile(configFile),
+		doctor.WithNSSwitchFile(nsswitchFile),
+		doctor.WithPAMDir(pamDir))
+	require.NoError(t, err, "WriteBundle should not return an error, but did")
+
+	gz, err := gzip.NewReader(strings.NewReader(bundle.String()))
+	require.NoError(t, err, "Bundle should be gzipped")
+	tr := tar.NewReader(gz)
+	var got strings.Builder
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err, "Bundle should be a valid tarball")
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err, "Bundle files should be readable")
+		fmt.Fprintf(&got, "==> %s <==\n%s\n", h.Name, content)
+	}
+
+	golden.CheckOrUpdate(t, got.String())
+}
+
+// writeFile writes the content to the file, creating its parent directory.
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700), "Setup: could not create directory")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600), "Setup: could not write file")
+}
+
+func TestMain(m *testing.M) {
+	log.SetLevel(log.DebugLevel)
+
+	cleanup, err := testutils.StartSystemBusMock()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer cleanup()
+
+	dir, err := os.MkdirTemp("", "authd-doctor-tests-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+
+	cfgPath, brokerCleanup, err := testutils.StartBusBrokerMock(dir, "BrokerMock")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer brokerCleanup()
+
+	brokerConfig, err = os.ReadFile(cfgPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	m.Run()
+}
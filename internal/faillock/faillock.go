@@ -0,0 +1,215 @@
+// Package faillock reads and writes the tally files of pam_faillock, so that the tools inspecting the failed
+// authentications recorded by pam_faillock, like faillock(8), also see the ones of the users of authd.
+package faillock
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ubuntu/decorate"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// DefaultConfigPath is the configuration file of pam_faillock.
+	DefaultConfigPath = "/etc/security/faillock.conf"
+	// DefaultDir is the directory of the tally files, if the configuration doesn't set one.
+	DefaultDir = "/var/run/faillock"
+)
+
+// The status flags of the records, as defined by pam_faillock.
+const (
+	// StatusValid is set on the records of failures which are still counted.
+	StatusValid uint16 = 0x1
+	// StatusRHost is set on the records whose source is a remote host.
+	StatusRHost uint16 = 0x2
+	// StatusTTY is set on the records whose source is a TTY. The source is a PAM service if neither StatusRHost nor
+	// StatusTTY are set.
+	StatusTTY uint16 = 0x4
+)
+
+const (
+	// sourceSize is the size of the source field of the records, which is not necessarily NUL terminated.
+	sourceSize = 52
+	// recordSize is the size of a record in the tally files.
+	recordSize = sourceSize + 2 + 2 + 8
+)
+
+// Record is a failed authentication recorded in a tally file.
+type Record struct {
+	// Source is the remote host, the TTY or the PAM service the authentication failed through.
+	Source string
+	Status uint16
+	Time   time.Time
+}
+
+// Valid returns whether the failure is still counted by pam_faillock.
+func (r Record) Valid() bool {
+	return r.Status&StatusValid != 0
+}
+
+// Dir returns the directory of the tally files set in the pam_faillock configuration file at path, or the default
+// one if it doesn't set any.
+func Dir(path string) (dir string, err error) {
+	defer decorate.OnError(&err, "can't read pam_faillock configuration %q", path)
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultDir, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	dir = DefaultDir
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, _ := strings.Cut(line, "=")
+		if strings.TrimSpace(key) == "dir" && strings.TrimSpace(value) != "" {
+			dir = strings.TrimSpace(value)
+		}
+	}
+	return dir, scanner.Err()
+}
+
+// Usernames returns the users which have a tally file in dir, which are empty if it doesn't exist.
+func Usernames(dir string) (usernames []string, err error) {
+	defer decorate.OnError(&err, "can't list tally files of %q", dir)
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		usernames = append(usernames, e.Name())
+	}
+	return usernames, nil
+}
+
+// Read returns the records of the tally file of the user in dir, which are empty if it doesn't exist.
+func Read(dir, username string) (records []Record, err error) {
+	defer decorate.OnError(&err, "can't read tally file of user %q", username)
+
+	f, err := openTally(dir, username, os.O_RDONLY, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := lock(f, unix.F_RDLCK); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	// A truncated record at the end of the file is ignored, like pam_faillock does.
+	for len(data) >= recordSize {
+		records = append(records, decode(data[:recordSize]))
+		data = data[recordSize:]
+	}
+	return records, nil
+}
+
+// Append adds the record at the end of the tally file of the user in dir, creating it if needed.
+func Append(dir, username string, r Record) (err error) {
+	defer decorate.OnError(&err, "can't write to tally file of user %q", username)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	// The tally files created by pam_faillock are only writable by root, and readable by the administrators.
+	f, err := openTally(dir, username, os.O_RDWR|os.O_CREATE, 0660)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := lock(f, unix.F_WRLCK); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+	if _, err := f.Write(encode(r)); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Reset removes all the records of the tally file of the user in dir, like faillock --reset does.
+func Reset(dir, username string) (err error) {
+	defer decorate.OnError(&err, "can't reset tally file of user %q", username)
+
+	f, err := openTally(dir, username, os.O_RDWR, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := lock(f, unix.F_WRLCK); err != nil {
+		return err
+	}
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// openTally opens the tally file of the user in dir, refusing the user names which would escape it.
+func openTally(dir, username string, flag int, perm fs.FileMode) (*os.File, error) {
+	if username == "" || username == "." || username == ".." || strings.ContainsRune(username, '/') {
+		return nil, fmt.Errorf("invalid user name %q", username)
+	}
+	return os.OpenFile(filepath.Join(dir, username), flag, perm)
+}
+
+// lock takes a lock of the given type on the whole file, which is released when it's closed. pam_faillock uses the
+// same POSIX record locks.
+func lock(f *os.File, lockType int16) error {
+	return unix.FcntlFlock(f.Fd(), unix.F_SETLKW, &unix.Flock_t{Type: lockType, Whence: io.SeekStart})
+}
+
+// encode returns the record in the binary format of pam_faillock, which uses the native byte order.
+func encode(r Record) []byte {
+	b := make([]byte, recordSize)
+	copy(b[:sourceSize], r.Source)
+	// The next two bytes are reserved.
+	binary.NativeEndian.PutUint16(b[sourceSize+2:], r.Status)
+	binary.NativeEndian.PutUint64(b[sourceSize+4:], uint64(r.Time.Unix()))
+	return b
+}
+
+// decode returns the record from its binary format.
+func decode(b []byte) Record {
+	source, _, _ := bytes.Cut(b[:sourceSize], []byte{0})
+	return Record{
+		Source: string(source),
+		Status: binary.NativeEndian.Uint16(b[sourceSize+2:]),
+		Time:   time.Unix(int64(binary.NativeEndian.Uint64(b[sourceSize+4:])), 0),
+	}
+}
@@ -0,0 +1,113 @@
+package faillock_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/faillock"
+)
+
+func TestDir(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config string
+		noConf bool
+
+		want    string
+		wantErr bool
+	}{
+		"Default_directory_without_configuration":    {noConf: true, want: faillock.DefaultDir},
+		"Default_directory_when_not_set":             {config: "deny = 3\n# dir = /var/lib/faillock\n", want: faillock.DefaultDir},
+		"Directory_of_configuration":                 {config: "deny = 3\ndir = /var/lib/faillock # comment\n", want: "/var/lib/faillock"},
+		"Default_directory_when_set_to_empty_string": {config: "dir =\n", want: faillock.DefaultDir},
+
+		"Error_when_configuration_is_a_directory": {config: "-", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "faillock.conf")
+			switch {
+			case tc.config == "-":
+				require.NoError(t, os.Mkdir(path, 0700), "Setup: could not create directory")
+			case !tc.noConf:
+				require.NoError(t, os.WriteFile(path, []byte(tc.config), 0600), "Setup: could not write configuration")
+			}
+
+			got, err := faillock.Dir(path)
+			if tc.wantErr {
+				require.Error(t, err, "Dir should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Dir should not return an error, but did")
+			require.Equal(t, tc.want, got, "Dir should return the expected directory")
+		})
+	}
+}
+
+func TestAppendReadReset(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "faillock")
+	now := time.Unix(1700000000, 0)
+	records := []faillock.Record{
+		{Source: "sshd", Status: faillock.StatusValid, Time: now},
+		{Source: "a-source-longer-than-the-52-bytes-of-the-source-field-of-a-record", Status: faillock.StatusValid, Time: now.Add(time.Second)},
+	}
+
+	usernames, err := faillock.Usernames(dir)
+	require.NoError(t, err, "Usernames should not return an error for a missing directory")
+	require.Empty(t, usernames, "Usernames should not return any user for a missing directory")
+
+	got, err := faillock.Read(dir, "user1")
+	require.NoError(t, err, "Read should not return an error for a missing tally file")
+	require.Empty(t, got, "Read should not return any record for a missing tally file")
+
+	for _, r := range records {
+		require.NoError(t, faillock.Append(dir, "user1", r), "Append should not return an error, but did")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "user1"))
+	require.NoError(t, err, "The tally file should be named after the user")
+	require.Len(t, data, 2*64, "The tally file should contain the records in the format of pam_faillock")
+	require.Equal(t, uint64(now.Unix()), binary.NativeEndian.Uint64(data[56:64]), "The time should be at the end of the record")
+
+	got, err = faillock.Read(dir, "user1")
+	require.NoError(t, err, "Read should not return an error, but did")
+	records[1].Source = records[1].Source[:52]
+	require.Equal(t, records, got, "Read should return the appended records, with the sources truncated")
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "not-a-tally-file"), 0700), "Setup: could not create directory")
+	usernames, err = faillock.Usernames(dir)
+	require.NoError(t, err, "Usernames should not return an error, but did")
+	require.Equal(t, []string{"user1"}, usernames, "Usernames should only return the users with a tally file")
+
+	got, err = faillock.Read(dir, "user2")
+	require.NoError(t, err, "Read should not return an error for another user")
+	require.Empty(t, got, "The records of other users should not be returned")
+
+	require.NoError(t, faillock.Reset(dir, "user1"), "Reset should not return an error, but did")
+	got, err = faillock.Read(dir, "user1")
+	require.NoError(t, err, "Read should not return an error after a reset")
+	require.Empty(t, got, "Read should not return any record after a reset")
+
+	require.NoError(t, faillock.Reset(dir, "user2"), "Reset should not return an error for a missing tally file")
+}
+
+func TestInvalidUsernames(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, username := range []string{"", ".", "..", "../user1", "user1/../user2"} {
+		_, err := faillock.Read(dir, username)
+		require.Error(t, err, "Read should return an error for user name %q", username)
+		require.Error(t, faillock.Append(dir, username, faillock.Record{}), "Append should return an error for user name %q", username)
+		require.Error(t, faillock.Reset(dir, username), "Reset should return an error for user name %q", username)
+	}
+}
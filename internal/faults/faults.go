@@ -0,0 +1,192 @@
+// Package faults injects faults at some points of authd and of the PAM module, so that the tests can cover their
+// recovery paths deterministically.
+//
+// The faults can only be set in tests and integration tests builds. Elsewhere, no fault is ever set and the injection
+// points let everything through.
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ubuntu/authd/internal/testsdetection"
+)
+
+// Point is a place of the code where faults can be injected.
+type Point string
+
+const (
+	// CacheWrite is the update of the user and group entries in the database.
+	CacheWrite Point = "cache-write"
+	// BrokerResponse is the response of a broker to an authentication request.
+	BrokerResponse Point = "broker-response"
+	// GDMEvent is the emission of an event from the PAM module to GDM.
+	GDMEvent Point = "gdm-event"
+)
+
+// SpecEnv is the environment variable the integration tests builds of the daemon read their faults spec from.
+const SpecEnv = "AUTHD_INTEGRATIONTESTS_FAULTS"
+
+// points are all the injection points.
+var points = []Point{CacheWrite, BrokerResponse, GDMEvent}
+
+// ErrDropped is returned by Inject when the message handled by the injection point must be discarded.
+var ErrDropped = errors.New("message dropped by fault injection")
+
+// Fault describes what happens when an injection point is hit.
+type Fault struct {
+	// Delay is how long the injection point waits before proceeding.
+	Delay time.Duration
+	// Err is the error returned by the injection point.
+	Err error
+	// Drop makes the injection point discard the message it handles.
+	Drop bool
+	// CorruptJSON makes the injection point return malformed JSON instead of the message it handles.
+	CorruptJSON bool
+	// Times is the number of times the fault is injected before being removed, 0 keeps it until it's removed.
+	Times int
+}
+
+var (
+	// enabled avoids taking the lock on the hot paths when no fault is set, which is always the case in production.
+	enabled atomic.Bool
+	mu      sync.Mutex
+	faults  = make(map[Point]*Fault)
+)
+
+// Set sets the fault injected at the point until it's removed, replacing any previous one.
+// The faults are global to the process, so the tests setting them can't run in parallel with the ones hitting the
+// same point.
+func Set(p Point, f Fault) {
+	testsdetection.MustBeTesting()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	faults[p] = &f
+	enabled.Store(true)
+}
+
+// Remove removes the fault injected at the point, if any.
+func Remove(p Point) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(faults, p)
+	enabled.Store(len(faults) > 0)
+}
+
+// Inject applies the fault set at the point, if any, to the data the point handles. It returns the data to proceed
+// with, or an error matching ErrDropped if the data must be discarded.
+func Inject(ctx context.Context, p Point, data []byte) ([]byte, error) {
+	if !enabled.Load() {
+		return data, nil
+	}
+
+	f, ok := take(p)
+	if !ok {
+		return data, nil
+	}
+
+	if f.Delay > 0 {
+		select {
+		case <-time.After(f.Delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if f.Drop {
+		return nil, fmt.Errorf("%w at %s", ErrDropped, p)
+	}
+	if f.CorruptJSON {
+		// Truncating the message in the middle of it and appending a stray brace gives invalid JSON, whatever the
+		// message was.
+		return append(data[:len(data)/2:len(data)/2], '{'), nil
+	}
+	return data, nil
+}
+
+// take returns the fault set at the point, consuming one of its times.
+func take(p Point) (Fault, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	f, ok := faults[p]
+	if !ok {
+		return Fault{}, false
+	}
+	if f.Times > 0 {
+		f.Times--
+		if f.Times == 0 {
+			delete(faults, p)
+			enabled.Store(len(faults) > 0)
+		}
+	}
+	return *f, true
+}
+
+// SetFromSpec sets the faults described by spec, for the processes started by the integration tests.
+//
+// The spec is a semicolon-separated list of point=action[,action...] where the actions are: delay:<duration>,
+// error[:<message>], drop, corrupt-json and times:<count>. For instance:
+//
+//	cache-write=error:disk full;broker-response=delay:2s,times:1
+func SetFromSpec(spec string) error {
+	testsdetection.MustBeTesting()
+
+	for _, entry := range strings.Split(spec, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		point, actions, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid fault %q: expected point=actions", entry)
+		}
+		p := Point(strings.TrimSpace(point))
+		if !slices.Contains(points, p) {
+			return fmt.Errorf("invalid fault %q: unknown point %q", entry, p)
+		}
+
+		var f Fault
+		for _, action := range strings.Split(actions, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(action), ":")
+			switch name {
+			case "delay":
+				d, err := time.ParseDuration(arg)
+				if err != nil {
+					return fmt.Errorf("invalid delay of fault %q: %w", entry, err)
+				}
+				f.Delay = d
+			case "error":
+				if arg == "" {
+					arg = "injected fault"
+				}
+				f.Err = errors.New(arg)
+			case "drop":
+				f.Drop = true
+			case "corrupt-json":
+				f.CorruptJSON = true
+			case "times":
+				n, err := strconv.Atoi(arg)
+				if err != nil || n < 0 {
+					return fmt.Errorf("invalid times of fault %q: expected a positive number, got %q", entry, arg)
+				}
+				f.Times = n
+			default:
+				return fmt.Errorf("invalid fault %q: unknown action %q", entry, name)
+			}
+		}
+		Set(p, f)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package faults_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/faults"
+)
+
+//nolint:paralleltest // The faults are global to the process.
+func TestInject(t *testing.T) {
+	const data = `{"message": "hello"}`
+
+	tests := map[string]struct {
+		fault   *faults.Fault
+		timeout time.Duration
+		calls   int
+
+		wantData        string
+		wantCorruptJSON bool
+		wantErr         error
+		wantErrCalls    int
+	}{
+		"Data_goes_through_without_fault":           {wantData: data},
+		"Data_goes_through_after_delay":             {fault: &faults.Fault{Delay: 10 * time.Millisecond}, wantData: data},
+		"Data_goes_through_once_fault_is_consumed":  {fault: &faults.Fault{Err: errors.New("failure"), Times: 2}, calls: 3, wantData: data, wantErrCalls: 2},
+		"Data_is_corrupted":                         {fault: &faults.Fault{CorruptJSON: true}, wantCorruptJSON: true},
+		"Error_when_fault_returns_an_error":         {fault: &faults.Fault{Err: errors.New("failure")}, calls: 2, wantErrCalls: 2},
+		"Error_when_data_is_dropped":                {fault: &faults.Fault{Drop: true}, wantErr: faults.ErrDropped, wantErrCalls: 1},
+		"Error_when_delay_is_past_context_deadline": {fault: &faults.Fault{Delay: time.Hour}, timeout: 10 * time.Millisecond, wantErr: context.DeadlineExceeded, wantErrCalls: 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.fault != nil {
+				faults.Set(faults.BrokerResponse, *tc.fault)
+			}
+			t.Cleanup(func() { faults.Remove(faults.BrokerResponse) })
+			if tc.calls == 0 {
+				tc.calls = 1
+			}
+
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			for i := range tc.calls {
+				got, err := faults.Inject(ctx, faults.BrokerResponse, []byte(data))
+				if i < tc.wantErrCalls {
+					require.Error(t, err, "Inject should return an error, but did not")
+					if tc.wantErr != nil {
+						require.ErrorIs(t, err, tc.wantErr, "Inject should return the expected error")
+					}
+					continue
+				}
+				require.NoError(t, err, "Inject should not return an error, but did")
+
+				if tc.wantCorruptJSON {
+					require.False(t, json.Valid(got), "Inject should return invalid JSON, got %q", got)
+					continue
+				}
+				require.Equal(t, tc.wantData, string(got), "Inject should return the data unchanged")
+			}
+
+			// Other points are not affected.
+			got, err := faults.Inject(ctx, faults.CacheWrite, []byte(data))
+			require.NoError(t, err, "Inject should not return an error at a point without fault")
+			require.Equal(t, data, string(got), "Inject should return the data unchanged at a point without fault")
+		})
+	}
+}
+
+//nolint:paralleltest // The faults are global to the process.
+func TestSetFromSpec(t *testing.T) {
+	tests := map[string]struct {
+		spec string
+
+		wantErr bool
+	}{
+		"Empty_spec":                 {spec: ""},
+		"Single_fault":               {spec: "cache-write=error:disk full"},
+		"Multiple_faults":            {spec: "cache-write=error;broker-response=delay:1ms,corrupt-json,times:1;gdm-event=drop"},
+		"Trailing_separator_ignored": {spec: "gdm-event=drop;"},
+
+		"Error_on_missing_actions": {spec: "cache-write", wantErr: true},
+		"Error_on_unknown_point":   {spec: "unknown=error", wantErr: true},
+		"Error_on_unknown_action":  {spec: "cache-write=explode", wantErr: true},
+		"Error_on_invalid_delay":   {spec: "cache-write=delay:soon", wantErr: true},
+		"Error_on_invalid_times":   {spec: "cache-write=error,times:-1", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Cleanup(func() {
+				faults.Remove(faults.CacheWrite)
+				faults.Remove(faults.BrokerResponse)
+				faults.Remove(faults.GDMEvent)
+			})
+
+			err := faults.SetFromSpec(tc.spec)
+			if tc.wantErr {
+				require.Error(t, err, "SetFromSpec should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SetFromSpec should not return an error, but did")
+		})
+	}
+}
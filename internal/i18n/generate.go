@@ -0,0 +1,5 @@
+//go:build generate
+
+//go:generate ../../tools/update-po.sh
+
+package i18n
@@ -0,0 +1,185 @@
+// Package i18n is responsible for the internationalization of the user facing strings.
+package i18n
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ubuntu/authd/log"
+)
+
+const (
+	// TextDomain is the gettext domain used by authd.
+	TextDomain = "authd"
+
+	// defaultLocaleDir is the system directory containing the compiled translations.
+	defaultLocaleDir = "/usr/share/locale"
+)
+
+var (
+	defaultCatalog   *Catalog
+	defaultCatalogMu sync.RWMutex
+)
+
+// Catalog contains the translations of a domain for a locale.
+// A nil Catalog returns the strings untranslated.
+type Catalog struct {
+	c *catalog
+}
+
+type options struct {
+	localeDir string
+	locale    string
+}
+
+// Option is the function signature used to tweak the translations loading.
+type Option func(*options)
+
+// WithLocaleDir overrides the directory from which the translations are loaded.
+func WithLocaleDir(dir string) Option {
+	return func(o *options) {
+		o.localeDir = dir
+	}
+}
+
+// WithLocale forces the locale to use instead of the one from the process environment.
+func WithLocale(locale string) Option {
+	return func(o *options) {
+		o.locale = locale
+	}
+}
+
+// InitI18nDomain loads the translations of the given domain for the current locale, used by G and NG.
+// If no translation is available, the strings will be returned untranslated.
+//
+// The translations are shared by the whole process, so the code serving users with different locales, like the PAM
+// module whose transactions each have their own locale, must use the Catalog returned by LoadCatalog instead.
+func InitI18nDomain(domain string, args ...Option) {
+	c := LoadCatalog(domain, args...)
+
+	defaultCatalogMu.Lock()
+	defer defaultCatalogMu.Unlock()
+	defaultCatalog = c
+}
+
+// LoadCatalog loads the translations of the given domain for the current locale.
+// If no translation is available, the returned Catalog returns the strings untranslated.
+func LoadCatalog(domain string, args ...Option) *Catalog {
+	opts := options{
+		localeDir: defaultLocaleDir,
+		locale:    LocaleFromEnv(os.Getenv),
+	}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	c, err := loadCatalog(opts.localeDir, domain, opts.locale)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warningf(context.Background(), "Could not load translations for %q: %v", opts.locale, err)
+	}
+	if c == nil {
+		return nil
+	}
+	return &Catalog{c: c}
+}
+
+// G returns the translation of msgid in the locale of InitI18nDomain, or msgid itself if none is available.
+func G(msgid string) string {
+	defaultCatalogMu.RLock()
+	defer defaultCatalogMu.RUnlock()
+
+	return defaultCatalog.G(msgid)
+}
+
+// NG returns the translation of msgid or msgidPlural matching the n quantity in the locale of InitI18nDomain.
+func NG(msgid, msgidPlural string, n int) string {
+	defaultCatalogMu.RLock()
+	defer defaultCatalogMu.RUnlock()
+
+	return defaultCatalog.NG(msgid, msgidPlural, n)
+}
+
+// G returns the translation of msgid in the locale of the catalog, or msgid itself if none is available.
+func (c *Catalog) G(msgid string) string {
+	if c == nil {
+		return msgid
+	}
+	return c.c.get(msgid)
+}
+
+// NG returns the translation of msgid or msgidPlural matching the n quantity in the locale of the catalog.
+func (c *Catalog) NG(msgid, msgidPlural string, n int) string {
+	if c == nil {
+		if n == 1 {
+			return msgid
+		}
+		return msgidPlural
+	}
+	return c.c.nget(msgid, msgidPlural, n)
+}
+
+// LocaleFromEnv returns the locale used for messages, following the POSIX precedence
+// of the environment variables as returned by getenv.
+func LocaleFromEnv(getenv func(string) string) string {
+	for _, e := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if l := getenv(e); l != "" {
+			return l
+		}
+	}
+	return "C"
+}
+
+// loadCatalog loads the first translation file matching the given locale.
+func loadCatalog(localeDir, domain, locale string) (*catalog, error) {
+	candidates := localeCandidates(locale)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var err error
+	for _, l := range candidates {
+		var c *catalog
+		c, err = parseMOFile(filepath.Join(localeDir, l, "LC_MESSAGES", domain+".mo"))
+		if err == nil {
+			return c, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// localeCandidates returns the list of directory names to look for a locale in
+// order of preference, as in "ll_CC.codeset@modifier" → "ll_CC@modifier", "ll_CC", "ll".
+func localeCandidates(locale string) []string {
+	if locale == "" || locale == "C" || locale == "POSIX" || strings.HasPrefix(locale, "C.") {
+		return nil
+	}
+
+	var candidates []string
+	add := func(c string) {
+		for _, e := range candidates {
+			if e == c {
+				return
+			}
+		}
+		candidates = append(candidates, c)
+	}
+
+	add(locale)
+	base, modifier, hasModifier := strings.Cut(locale, "@")
+	base, _, _ = strings.Cut(base, ".")
+	if hasModifier {
+		add(base + "@" + modifier)
+	}
+	add(base)
+	lang, _, _ := strings.Cut(base, "_")
+	add(lang)
+
+	return candidates
+}
@@ -0,0 +1,301 @@
+package i18n_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/i18n"
+)
+
+const testDomain = "authd-tests"
+
+func TestG(t *testing.T) {
+	tests := map[string]struct {
+		locale string
+		msgid  string
+
+		noTranslations bool
+
+		want string
+	}{
+		"Translates_message_for_exact_locale":         {locale: "fr_FR", msgid: "Access denied", want: "Accès refusé"},
+		"Translates_message_stripping_codeset":        {locale: "fr_FR.UTF-8", msgid: "Access denied", want: "Accès refusé"},
+		"Translates_message_falling_back_to_language": {locale: "fr_CA.UTF-8", msgid: "Access denied", want: "Accès refusé"},
+
+		"Returns_msgid_for_untranslated_message": {locale: "fr_FR", msgid: "Not translated", want: "Not translated"},
+		"Returns_msgid_for_C_locale":             {locale: "C", msgid: "Access denied", want: "Access denied"},
+		"Returns_msgid_for_unknown_locale":       {locale: "it_IT.UTF-8", msgid: "Access denied", want: "Access denied"},
+		"Returns_msgid_without_translations":     {locale: "fr_FR", noTranslations: true, msgid: "Access denied", want: "Access denied"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			localeDir := t.TempDir()
+			if !tc.noTranslations {
+				writeMOFile(t, filepath.Join(localeDir, "fr", "LC_MESSAGES", testDomain+".mo"),
+					"Plural-Forms: nplurals=2; plural=(n > 1);\n",
+					map[string][]string{"Access denied": {"Accès refusé"}})
+			}
+
+			i18n.InitI18nDomain(testDomain, i18n.WithLocaleDir(localeDir), i18n.WithLocale(tc.locale))
+			t.Cleanup(func() { i18n.InitI18nDomain(testDomain, i18n.WithLocaleDir(t.TempDir())) })
+
+			require.Equal(t, tc.want, i18n.G(tc.msgid), "G should return the expected message")
+		})
+	}
+}
+
+func TestNG(t *testing.T) {
+	tests := map[string]struct {
+		header string
+		n      int
+
+		noTranslations bool
+
+		want string
+	}{
+		"Translates_singular_form":                   {n: 1, want: "1 tentative restante"},
+		"Translates_plural_form":                     {n: 3, want: "3 tentatives restantes"},
+		"Translates_singular_form_using_plural_rule": {n: 0, want: "0 tentative restante"},
+		"Translates_using_complex_plural_rule": {
+			header: "Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n",
+			n:      5,
+			want:   "5 tentatives (many)",
+		},
+
+		"Returns_untranslated_singular_without_translations": {noTranslations: true, n: 1, want: "1 attempt left"},
+		"Returns_untranslated_plural_without_translations":   {noTranslations: true, n: 2, want: "2 attempts left"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			localeDir := t.TempDir()
+			if tc.header == "" {
+				tc.header = "Plural-Forms: nplurals=2; plural=(n > 1);\n"
+			}
+			if !tc.noTranslations {
+				writeMOFile(t, filepath.Join(localeDir, "fr", "LC_MESSAGES", testDomain+".mo"), tc.header,
+					map[string][]string{
+						"%d attempt left\x00%d attempts left": {
+							"%d tentative restante", "%d tentatives restantes", "%d tentatives (many)",
+						},
+					})
+			}
+
+			i18n.InitI18nDomain(testDomain, i18n.WithLocaleDir(localeDir), i18n.WithLocale("fr_FR.UTF-8"))
+			t.Cleanup(func() { i18n.InitI18nDomain(testDomain, i18n.WithLocaleDir(t.TempDir())) })
+
+			got := i18n.NG("%d attempt left", "%d attempts left", tc.n)
+			require.Equal(t, tc.want, fmt.Sprintf(got, tc.n), "NG should return the expected message")
+		})
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	t.Parallel()
+
+	localeDir := t.TempDir()
+	writeMOFile(t, filepath.Join(localeDir, "fr", "LC_MESSAGES", testDomain+".mo"),
+		"Plural-Forms: nplurals=2; plural=(n > 1);\n",
+		map[string][]string{"Access denied": {"Accès refusé"}})
+	writeMOFile(t, filepath.Join(localeDir, "de", "LC_MESSAGES", testDomain+".mo"),
+		"Plural-Forms: nplurals=2; plural=(n != 1);\n",
+		map[string][]string{"Access denied": {"Zugriff verweigert"}})
+
+	tests := map[string]struct {
+		locale string
+
+		want string
+	}{
+		"Translates_message_in_locale_of_catalog":    {locale: "fr_FR.UTF-8", want: "Accès refusé"},
+		"Translates_message_in_another_locale":       {locale: "de_DE.UTF-8", want: "Zugriff verweigert"},
+		"Returns_msgid_for_locale_without_catalog":   {locale: "it_IT.UTF-8", want: "Access denied"},
+		"Returns_msgid_for_C_locale_without_catalog": {locale: "C", want: "Access denied"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// The catalogs are used at the same time by the parallel subtests, without affecting each other.
+			c := i18n.LoadCatalog(testDomain, i18n.WithLocaleDir(localeDir), i18n.WithLocale(tc.locale))
+			for range 100 {
+				require.Equal(t, tc.want, c.G("Access denied"), "G should return the message in the locale of the catalog")
+			}
+		})
+	}
+}
+
+func TestInitI18nDomainWithInvalidFile(t *testing.T) {
+	tests := map[string]struct {
+		content []byte
+	}{
+		"Ignores_file_not_in_MO_format":           {content: []byte("not a MO file, but long enough to be parsed")},
+		"Ignores_file_with_too_many_strings":      {content: moHeader(0xffffffff, 28, 28)},
+		"Ignores_file_with_strings_out_of_bounds": {content: append(moHeader(1, 28, 36), 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0)},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			localeDir := t.TempDir()
+			p := filepath.Join(localeDir, "fr", "LC_MESSAGES", testDomain+".mo")
+			require.NoError(t, os.MkdirAll(filepath.Dir(p), 0700), "Setup: could not create locale directory")
+			require.NoError(t, os.WriteFile(p, tc.content, 0600), "Setup: could not write invalid MO file")
+
+			i18n.InitI18nDomain(testDomain, i18n.WithLocaleDir(localeDir), i18n.WithLocale("fr_FR"))
+			t.Cleanup(func() { i18n.InitI18nDomain(testDomain, i18n.WithLocaleDir(t.TempDir())) })
+
+			require.Equal(t, "Access denied", i18n.G("Access denied"), "G should return the msgid on invalid translations")
+		})
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env map[string]string
+
+		want string
+	}{
+		"LC_ALL_has_precedence":              {env: map[string]string{"LC_ALL": "de_DE", "LC_MESSAGES": "fr_FR", "LANG": "it_IT"}, want: "de_DE"},
+		"LC_MESSAGES_has_precedence_on_LANG": {env: map[string]string{"LC_MESSAGES": "fr_FR", "LANG": "it_IT"}, want: "fr_FR"},
+		"LANG_is_used_as_fallback":           {env: map[string]string{"LANG": "it_IT.UTF-8"}, want: "it_IT.UTF-8"},
+		"C_is_returned_if_nothing_is_set":    {want: "C"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := i18n.LocaleFromEnv(func(k string) string { return tc.env[k] })
+			require.Equal(t, tc.want, got, "LocaleFromEnv should return the expected locale")
+		})
+	}
+}
+
+// translatableStringRe matches the translatable strings of the Go sources, excluding the commented out ones.
+var translatableStringRe = regexp.MustCompile(`(?:^|[^*])(?:i18n|[cC]atalog)\.N?G\(("(?:[^"\\]|\\.)*")`)
+
+// msgidRe matches the messages of the translations template.
+var msgidRe = regexp.MustCompile(`(?m)^msgid (".*")$`)
+
+func TestPOTemplateIsUpToDate(t *testing.T) {
+	t.Parallel()
+
+	rootDir := filepath.Join("..", "..")
+	potFiles, err := os.ReadFile(filepath.Join(rootDir, "po", "POTFILES.in"))
+	require.NoError(t, err, "Setup: could not read POTFILES.in")
+	template, err := os.ReadFile(filepath.Join(rootDir, "po", "authd.pot"))
+	require.NoError(t, err, "Setup: could not read the translations template")
+
+	var msgids []string
+	// Join the long strings first, as they are wrapped over multiple lines.
+	for _, m := range msgidRe.FindAllStringSubmatch(strings.ReplaceAll(string(template), "\"\n\"", ""), -1) {
+		msgids = append(msgids, m[1])
+	}
+
+	var listed []string
+	for _, l := range strings.Split(string(potFiles), "\n") {
+		if l != "" && !strings.HasPrefix(l, "#") {
+			listed = append(listed, l)
+		}
+	}
+
+	var translatable []string
+	err = filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != rootDir &&
+			(d.Name() == "vendor" || d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		matches := translatableStringRe.FindAllSubmatch(content, -1)
+		if len(matches) == 0 {
+			return nil
+		}
+
+		rel, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return err
+		}
+		translatable = append(translatable, rel)
+		for _, m := range matches {
+			require.Contains(t, msgids, string(m[1]),
+				"%s: translatable string is not in po/authd.pot, run go generate -tags generate ./internal/i18n", rel)
+		}
+		return nil
+	})
+	require.NoError(t, err, "Setup: could not walk the sources")
+
+	slices.Sort(listed)
+	require.Equal(t, translatable, listed, "po/POTFILES.in should list all the files with translatable strings")
+}
+
+// moHeader returns the header of a little endian MO file with n strings, whose tables are at the given offsets.
+func moHeader(n, origTable, transTable uint32) []byte {
+	var header []byte
+	for _, v := range []uint32{0x950412de, 0, n, origTable, transTable, 0, 0} {
+		header = binary.LittleEndian.AppendUint32(header, v)
+	}
+	return header
+}
+
+// writeMOFile writes a little endian MO file at path with the given header and messages.
+func writeMOFile(t *testing.T, path, header string, messages map[string][]string) {
+	t.Helper()
+
+	all := map[string]string{"": header}
+	for k, v := range messages {
+		all[k] = strings.Join(v, "\x00")
+	}
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	const headerSize = 28
+	n := uint32(len(keys))
+	origTable := uint32(headerSize)
+	transTable := origTable + n*8
+	offset := transTable + n*8
+
+	var strs bytes.Buffer
+	var origEntries, transEntries []uint32
+	for _, k := range keys {
+		origEntries = append(origEntries, uint32(len(k)), offset+uint32(strs.Len()))
+		strs.WriteString(k)
+		strs.WriteByte(0)
+	}
+	for _, k := range keys {
+		transEntries = append(transEntries, uint32(len(all[k])), offset+uint32(strs.Len()))
+		strs.WriteString(all[k])
+		strs.WriteByte(0)
+	}
+
+	var buf bytes.Buffer
+	for _, v := range []uint32{0x950412de, 0, n, origTable, transTable, 0, 0} {
+		require.NoError(t, binary.Write(&buf, binary.LittleEndian, v), "Setup: could not write MO header")
+	}
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, origEntries), "Setup: could not write MO table")
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, transEntries), "Setup: could not write MO table")
+	buf.Write(strs.Bytes())
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700), "Setup: could not create locale directory")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600), "Setup: could not write MO file")
+}
@@ -0,0 +1,334 @@
+package i18n
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	moMagicLittleEndian = 0x950412de
+	moMagicBigEndian    = 0xde120495
+)
+
+// catalog contains the translations loaded from a gettext MO file.
+type catalog struct {
+	messages map[string][]string
+	plural   pluralExpr
+	nplurals int
+}
+
+func (c *catalog) get(msgid string) string {
+	t, ok := c.messages[msgid]
+	if !ok || len(t) == 0 || t[0] == "" {
+		return msgid
+	}
+	return t[0]
+}
+
+func (c *catalog) nget(msgid, msgidPlural string, n int) string {
+	idx := c.plural(n)
+	if idx < 0 || idx >= c.nplurals {
+		idx = 0
+	}
+	t, ok := c.messages[msgid]
+	if !ok || idx >= len(t) || t[idx] == "" {
+		if n == 1 {
+			return msgid
+		}
+		return msgidPlural
+	}
+	return t[idx]
+}
+
+// parseMOFile parses the gettext MO file at path.
+func parseMOFile(path string) (*catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := parseMO(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MO file %q: %w", path, err)
+	}
+	return c, nil
+}
+
+func parseMO(data []byte) (*catalog, error) {
+	if len(data) < 28 {
+		return nil, errors.New("file too short")
+	}
+
+	var order binary.ByteOrder
+	switch binary.LittleEndian.Uint32(data) {
+	case moMagicLittleEndian:
+		order = binary.LittleEndian
+	case moMagicBigEndian:
+		order = binary.BigEndian
+	default:
+		return nil, errors.New("invalid magic number")
+	}
+
+	n := order.Uint32(data[8:])
+	origTable := order.Uint32(data[12:])
+	transTable := order.Uint32(data[16:])
+
+	// Each string has an entry of 8 bytes in both tables, so the file can't be smaller than them.
+	if uint64(n)*16 > uint64(len(data)) {
+		return nil, errors.New("too many strings for the file size")
+	}
+
+	readString := func(table, i uint32) (string, error) {
+		entry := uint64(table) + uint64(i)*8
+		if entry+8 > uint64(len(data)) {
+			return "", errors.New("string table out of bounds")
+		}
+		length := uint64(order.Uint32(data[entry:]))
+		offset := uint64(order.Uint32(data[entry+4:]))
+		if offset+length > uint64(len(data)) {
+			return "", errors.New("string out of bounds")
+		}
+		return string(data[offset : offset+length]), nil
+	}
+
+	c := &catalog{
+		messages: make(map[string][]string, n),
+		plural:   germanicPlural,
+		nplurals: 2,
+	}
+	for i := uint32(0); i < n; i++ {
+		orig, err := readString(origTable, i)
+		if err != nil {
+			return nil, err
+		}
+		trans, err := readString(transTable, i)
+		if err != nil {
+			return nil, err
+		}
+
+		msgid, _, _ := strings.Cut(orig, "\x00")
+		if msgid == "" {
+			c.parseHeader(trans)
+			continue
+		}
+		c.messages[msgid] = strings.Split(trans, "\x00")
+	}
+
+	return c, nil
+}
+
+// parseHeader reads the plural forms definition from the MO file header.
+func (c *catalog) parseHeader(header string) {
+	for _, line := range strings.Split(header, "\n") {
+		k, v, found := strings.Cut(line, ":")
+		if !found || strings.TrimSpace(k) != "Plural-Forms" {
+			continue
+		}
+		for _, field := range strings.Split(v, ";") {
+			k, v, found := strings.Cut(strings.TrimSpace(field), "=")
+			if !found {
+				continue
+			}
+			switch strings.TrimSpace(k) {
+			case "nplurals":
+				if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && n > 0 {
+					c.nplurals = n
+				}
+			case "plural":
+				if expr, err := parsePluralExpr(v); err == nil {
+					c.plural = expr
+				}
+			}
+		}
+	}
+}
+
+// pluralExpr is a compiled C-like plural forms expression.
+type pluralExpr func(n int) int
+
+func germanicPlural(n int) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+// parsePluralExpr compiles the plural expression as defined by gettext, such as:
+// "n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2".
+func parsePluralExpr(s string) (pluralExpr, error) {
+	p := &pluralParser{s: strings.ReplaceAll(s, " ", "")}
+	e, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("unexpected %q in plural expression", p.s[p.pos:])
+	}
+	return e, nil
+}
+
+type pluralParser struct {
+	s   string
+	pos int
+}
+
+func (p *pluralParser) consume(tok string) bool {
+	if strings.HasPrefix(p.s[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+func (p *pluralParser) ternary() (pluralExpr, error) {
+	cond, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume("?") {
+		return cond, nil
+	}
+	a, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume(":") {
+		return nil, errors.New("missing ':' in plural expression")
+	}
+	b, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	return func(n int) int {
+		if cond(n) != 0 {
+			return a(n)
+		}
+		return b(n)
+	}, nil
+}
+
+// pluralOperators are the binary operators by increasing precedence.
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *pluralParser) binary(level int) (pluralExpr, error) {
+	if level == len(pluralOperators) {
+		return p.unary()
+	}
+	lhs, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op string
+		for _, o := range pluralOperators[level] {
+			if p.consume(o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return lhs, nil
+		}
+		rhs, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		lhs = binaryOp(op, lhs, rhs)
+	}
+}
+
+func binaryOp(op string, a, b pluralExpr) pluralExpr {
+	toInt := func(v bool) int {
+		if v {
+			return 1
+		}
+		return 0
+	}
+	return func(n int) int {
+		x, y := a(n), b(n)
+		switch op {
+		case "||":
+			return toInt(x != 0 || y != 0)
+		case "&&":
+			return toInt(x != 0 && y != 0)
+		case "==":
+			return toInt(x == y)
+		case "!=":
+			return toInt(x != y)
+		case "<=":
+			return toInt(x <= y)
+		case ">=":
+			return toInt(x >= y)
+		case "<":
+			return toInt(x < y)
+		case ">":
+			return toInt(x > y)
+		case "+":
+			return x + y
+		case "-":
+			return x - y
+		case "*":
+			return x * y
+		case "/", "%":
+			if y == 0 {
+				return 0
+			}
+			if op == "/" {
+				return x / y
+			}
+			return x % y
+		}
+		return 0
+	}
+}
+
+func (p *pluralParser) unary() (pluralExpr, error) {
+	switch {
+	case p.consume("!"):
+		e, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int) int {
+			if e(n) == 0 {
+				return 1
+			}
+			return 0
+		}, nil
+	case p.consume("("):
+		e, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, errors.New("missing ')' in plural expression")
+		}
+		return e, nil
+	case p.consume("n"):
+		return func(n int) int { return n }, nil
+	}
+
+	end := p.pos
+	for end < len(p.s) && p.s[end] >= '0' && p.s[end] <= '9' {
+		end++
+	}
+	if end == p.pos {
+		return nil, fmt.Errorf("unexpected %q in plural expression", p.s[p.pos:])
+	}
+	v, err := strconv.Atoi(p.s[p.pos:end])
+	if err != nil {
+		return nil, err
+	}
+	p.pos = end
+	return func(int) int { return v }, nil
+}
@@ -0,0 +1,238 @@
+// Package passwordstrength estimates the strength of the secrets chosen by the users, like zxcvbn does: the secret is
+// split in the patterns attackers try first (common passwords, sequences, keyboard rows and repeated characters) and
+// the number of guesses needed to find it is turned into a score.
+package passwordstrength
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ubuntu/authd/internal/i18n"
+)
+
+// Score is the estimated strength of a secret, from VeryWeak to VeryStrong.
+type Score int
+
+const (
+	// VeryWeak is the score of the secrets guessed in less than a thousand attempts.
+	VeryWeak Score = iota
+	// Weak is the score of the secrets guessed in less than a million attempts.
+	Weak
+	// Fair is the score of the secrets guessed in less than a hundred million attempts.
+	Fair
+	// Strong is the score of the secrets guessed in less than ten billion attempts.
+	Strong
+	// VeryStrong is the score of the other secrets.
+	VeryStrong
+)
+
+// scoreThresholds are the base 10 logarithms of the number of guesses from which each score above VeryWeak is reached.
+var scoreThresholds = []float64{3, 6, 8, 10}
+
+// String returns the name of the score, untranslated.
+func (s Score) String() string {
+	return s.Label(nil)
+}
+
+// Label returns the name of the score shown to the users, translated with the catalog.
+func (s Score) Label(catalog *i18n.Catalog) string {
+	switch s {
+	case VeryWeak:
+		return catalog.G("Very weak")
+	case Weak:
+		return catalog.G("Weak")
+	case Fair:
+		return catalog.G("Fair")
+	case Strong:
+		return catalog.G("Strong")
+	case VeryStrong:
+		return catalog.G("Very strong")
+	}
+	return fmt.Sprintf("Score(%d)", int(s))
+}
+
+// ParseScore parses the score of a layout, from 0 (VeryWeak) to 4 (VeryStrong).
+func ParseScore(s string) (Score, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < int(VeryWeak) || n > int(VeryStrong) {
+		return VeryWeak, fmt.Errorf("invalid password strength score %q, expected a number from %d to %d", s, VeryWeak, VeryStrong)
+	}
+	return Score(n), nil
+}
+
+const (
+	// minPatternLength is the minimum length of the sequences, keyboard rows and repeated characters.
+	minPatternLength = 3
+	// commonPasswordGuesses is the number of guesses needed to find any of the common passwords.
+	commonPasswordGuesses = 100
+)
+
+// commonPasswords are passwords, or parts of them, which are tried first by the attackers.
+var commonPasswords = []string{
+	"password", "passw0rd", "p@ssw0rd", "qwerty", "azerty", "letmein", "welcome", "admin", "administrator", "login",
+	"dragon", "monkey", "football", "baseball", "iloveyou", "sunshine", "master", "princess", "secret", "shadow",
+	"superman", "batman", "trustno1", "changeme", "default", "ubuntu", "linux", "root", "user", "hello",
+}
+
+// keyboardRows are the rows of the common keyboard layouts, in which the attackers try the adjacent keys.
+var keyboardRows = []string{
+	"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./",
+	"azertyuiop", "qsdfghjklm", "wxcvbn", "qwertzuiop", "yxcvbnm",
+}
+
+// Estimate returns the estimated strength of the secret.
+func Estimate(secret []byte) Score {
+	log10Guesses := estimateLog10Guesses([]rune(string(secret)))
+	score := VeryWeak
+	for _, threshold := range scoreThresholds {
+		if log10Guesses < threshold {
+			break
+		}
+		score++
+	}
+	return score
+}
+
+// estimateLog10Guesses returns the base 10 logarithm of the number of guesses needed to find the secret. It's split
+// greedily in the longest patterns found, the other characters being brute-forced in the character set of the secret.
+func estimateLog10Guesses(secret []rune) float64 {
+	if len(secret) == 0 {
+		return 0
+	}
+
+	// The users don't pick the other characters randomly, so only half of the entropy of the character set is counted.
+	bruteForce := math.Log10(float64(charsetSize(secret))) / 2
+	var log10Guesses float64
+	var patterns int
+	for i := 0; i < len(secret); {
+		n, guesses := longestPattern(secret[i:])
+		if n == 0 {
+			log10Guesses += bruteForce
+			i++
+			continue
+		}
+		log10Guesses += math.Log10(guesses)
+		patterns++
+		i += n
+	}
+
+	// The attackers also need to guess how the patterns are combined.
+	if patterns > 1 {
+		log10Guesses += math.Log10(float64(patterns))
+	}
+	return log10Guesses
+}
+
+// longestPattern returns the length of the longest pattern the secret starts with, and the number of guesses needed
+// to find it. The length is 0 if it doesn't start with any pattern.
+func longestPattern(secret []rune) (length int, guesses float64) {
+	lower := []rune(strings.ToLower(string(secret)))
+
+	for _, p := range commonPasswords {
+		if n := len([]rune(p)); n > length && strings.HasPrefix(string(lower), p) {
+			length, guesses = n, commonPasswordGuesses
+		}
+	}
+
+	if n := repeatedLength(lower); n >= minPatternLength && n > length {
+		length, guesses = n, float64(charsetSize(secret[:1])*n)
+	}
+
+	if n := sequenceLength(lower); n >= minPatternLength && n > length {
+		// The attackers try the sequences from any character, in both directions.
+		length, guesses = n, float64(2*charsetSize(secret[:1])*n)
+	}
+
+	if n := keyboardRowLength(lower); n >= minPatternLength && n > length {
+		length, guesses = n, float64(2*len(keyboardRows)*n)
+	}
+
+	return length, guesses
+}
+
+// repeatedLength returns the number of times the first character is repeated at the start of the secret.
+func repeatedLength(secret []rune) int {
+	n := 1
+	for n < len(secret) && secret[n] == secret[0] {
+		n++
+	}
+	return n
+}
+
+// sequenceLength returns the length of the sequence of consecutive characters, like "abcd" or "4321", the secret
+// starts with.
+func sequenceLength(secret []rune) int {
+	if len(secret) < 2 {
+		return len(secret)
+	}
+	step := secret[1] - secret[0]
+	if step != 1 && step != -1 {
+		return 1
+	}
+	n := 2
+	for n < len(secret) && secret[n]-secret[n-1] == step {
+		n++
+	}
+	return n
+}
+
+// keyboardRowLength returns the length of the run of adjacent keys of a keyboard row, in any direction, the secret
+// starts with.
+func keyboardRowLength(secret []rune) int {
+	var longest int
+	for _, row := range keyboardRows {
+		for _, r := range [][]rune{[]rune(row), []rune(reverse(row))} {
+			for start := range r {
+				n := 0
+				for n < len(secret) && start+n < len(r) && secret[n] == r[start+n] {
+					n++
+				}
+				longest = max(longest, n)
+			}
+		}
+	}
+	return longest
+}
+
+// reverse returns the string with its characters in the reverse order.
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// charsetSize returns the number of characters the attackers must try for each character of the secret, depending on
+// the classes of characters it contains.
+func charsetSize(secret []rune) int {
+	var lower, upper, digits, symbols, others bool
+	for _, c := range secret {
+		switch {
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= '0' && c <= '9':
+			digits = true
+		case c < unicode.MaxASCII && unicode.IsPrint(c):
+			symbols = true
+		default:
+			others = true
+		}
+	}
+
+	var size int
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{lower, 26}, {upper, 26}, {digits, 10}, {symbols, 33}, {others, 100}} {
+		if class.present {
+			size += class.size
+		}
+	}
+	return size
+}
@@ -0,0 +1,71 @@
+package passwordstrength_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/passwordstrength"
+)
+
+func TestEstimate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		secret string
+
+		want passwordstrength.Score
+	}{
+		"Very_weak_when_empty":                    {secret: "", want: passwordstrength.VeryWeak},
+		"Very_weak_when_common_password":          {secret: "password", want: passwordstrength.VeryWeak},
+		"Very_weak_when_common_password_any_case": {secret: "PassWord", want: passwordstrength.VeryWeak},
+		"Very_weak_when_repeated_characters":      {secret: "aaaaaaaa", want: passwordstrength.VeryWeak},
+		"Very_weak_when_sequence":                 {secret: "12345678", want: passwordstrength.VeryWeak},
+		"Very_weak_when_reversed_sequence":        {secret: "zyxwvuts", want: passwordstrength.VeryWeak},
+		"Weak_when_keyboard_row_and_sequence":     {secret: "qwerty123", want: passwordstrength.Weak},
+		"Weak_when_short_lowercase_word":          {secret: "goodpass", want: passwordstrength.Weak},
+		"Fair_when_mixing_character_classes":      {secret: "f7Gh2kLm", want: passwordstrength.Fair},
+		"Strong_when_long_with_symbols":           {secret: "xK9#mQ2$vL", want: passwordstrength.Strong},
+		"Very_strong_when_passphrase":             {secret: "correcthorsebatterystaple", want: passwordstrength.VeryStrong},
+		"Strong_with_non_ASCII_characters":        {secret: "ÉtéçàÙ€ñü", want: passwordstrength.Strong},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := passwordstrength.Estimate([]byte(tc.secret))
+			require.Equal(t, tc.want, got, "Estimate returned an unexpected score")
+		})
+	}
+}
+
+func TestParseScore(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		score string
+
+		want    passwordstrength.Score
+		wantErr bool
+	}{
+		"Parse_lowest_score":  {score: "0", want: passwordstrength.VeryWeak},
+		"Parse_highest_score": {score: "4", want: passwordstrength.VeryStrong},
+
+		"Error_when_not_a_number":  {score: "strong", wantErr: true},
+		"Error_when_negative":      {score: "-1", wantErr: true},
+		"Error_when_above_highest": {score: "5", wantErr: true},
+		"Error_when_empty":         {score: "", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := passwordstrength.ParseScore(tc.score)
+			if tc.wantErr {
+				require.Error(t, err, "ParseScore should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "ParseScore should not return an error, but did")
+			require.Equal(t, tc.want, got, "ParseScore returned an unexpected score")
+		})
+	}
+}
@@ -19,14 +19,45 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PAM_AvailableBrokers_FullMethodName         = "/authd.PAM/AvailableBrokers"
-	PAM_GetPreviousBroker_FullMethodName        = "/authd.PAM/GetPreviousBroker"
-	PAM_SelectBroker_FullMethodName             = "/authd.PAM/SelectBroker"
-	PAM_GetAuthenticationModes_FullMethodName   = "/authd.PAM/GetAuthenticationModes"
-	PAM_SelectAuthenticationMode_FullMethodName = "/authd.PAM/SelectAuthenticationMode"
-	PAM_IsAuthenticated_FullMethodName          = "/authd.PAM/IsAuthenticated"
-	PAM_EndSession_FullMethodName               = "/authd.PAM/EndSession"
-	PAM_SetDefaultBrokerForUser_FullMethodName  = "/authd.PAM/SetDefaultBrokerForUser"
+	PAM_AvailableBrokers_FullMethodName          = "/authd.PAM/AvailableBrokers"
+	PAM_GetPreviousBroker_FullMethodName         = "/authd.PAM/GetPreviousBroker"
+	PAM_SelectBroker_FullMethodName              = "/authd.PAM/SelectBroker"
+	PAM_GetAuthenticationModes_FullMethodName    = "/authd.PAM/GetAuthenticationModes"
+	PAM_SelectAuthenticationMode_FullMethodName  = "/authd.PAM/SelectAuthenticationMode"
+	PAM_IsAuthenticated_FullMethodName           = "/authd.PAM/IsAuthenticated"
+	PAM_GetAuthenticationProgress_FullMethodName = "/authd.PAM/GetAuthenticationProgress"
+	PAM_EndSession_FullMethodName                = "/authd.PAM/EndSession"
+	PAM_SetDefaultBrokerForUser_FullMethodName   = "/authd.PAM/SetDefaultBrokerForUser"
+	PAM_ResetBrokerForUser_FullMethodName        = "/authd.PAM/ResetBrokerForUser"
+	PAM_IsRecentlyAuthenticated_FullMethodName   = "/authd.PAM/IsRecentlyAuthenticated"
+	PAM_GetSessionActions_FullMethodName         = "/authd.PAM/GetSessionActions"
+	PAM_CheckLoginPolicy_FullMethodName          = "/authd.PAM/CheckLoginPolicy"
+	PAM_GetLocalPINStatus_FullMethodName         = "/authd.PAM/GetLocalPINStatus"
+	PAM_AuthenticateWithLocalPIN_FullMethodName  = "/authd.PAM/AuthenticateWithLocalPIN"
+	PAM_SetLocalPIN_FullMethodName               = "/authd.PAM/SetLocalPIN"
+	PAM_RemoveLocalPIN_FullMethodName            = "/authd.PAM/RemoveLocalPIN"
+	PAM_SetUserGecos_FullMethodName              = "/authd.PAM/SetUserGecos"
+	PAM_SetUserShell_FullMethodName              = "/authd.PAM/SetUserShell"
+	PAM_SetUserHome_FullMethodName               = "/authd.PAM/SetUserHome"
+	PAM_SetUserOverride_FullMethodName           = "/authd.PAM/SetUserOverride"
+	PAM_UnsetUserOverride_FullMethodName         = "/authd.PAM/UnsetUserOverride"
+	PAM_ListUserOverrides_FullMethodName         = "/authd.PAM/ListUserOverrides"
+	PAM_WhoisUID_FullMethodName                  = "/authd.PAM/WhoisUID"
+	PAM_WhoisID_FullMethodName                   = "/authd.PAM/WhoisID"
+	PAM_AdoptUser_FullMethodName                 = "/authd.PAM/AdoptUser"
+	PAM_ChangeUID_FullMethodName                 = "/authd.PAM/ChangeUID"
+	PAM_ReconcileLocalGroups_FullMethodName      = "/authd.PAM/ReconcileLocalGroups"
+	PAM_GetFailedAuthentications_FullMethodName  = "/authd.PAM/GetFailedAuthentications"
+	PAM_GetUserAccountInfo_FullMethodName        = "/authd.PAM/GetUserAccountInfo"
+	PAM_SetUserDisplayName_FullMethodName        = "/authd.PAM/SetUserDisplayName"
+	PAM_SetUserAvatar_FullMethodName             = "/authd.PAM/SetUserAvatar"
+	PAM_ListSessions_FullMethodName              = "/authd.PAM/ListSessions"
+	PAM_AbortSession_FullMethodName              = "/authd.PAM/AbortSession"
+	PAM_AuthenticateHeadless_FullMethodName      = "/authd.PAM/AuthenticateHeadless"
+	PAM_DumpDatabase_FullMethodName              = "/authd.PAM/DumpDatabase"
+	PAM_ImportDatabase_FullMethodName            = "/authd.PAM/ImportDatabase"
+	PAM_GetVersion_FullMethodName                = "/authd.PAM/GetVersion"
+	PAM_SetDebugSocket_FullMethodName            = "/authd.PAM/SetDebugSocket"
 )
 
 // PAMClient is the client API for PAM service.
@@ -39,8 +70,39 @@ type PAMClient interface {
 	GetAuthenticationModes(ctx context.Context, in *GAMRequest, opts ...grpc.CallOption) (*GAMResponse, error)
 	SelectAuthenticationMode(ctx context.Context, in *SAMRequest, opts ...grpc.CallOption) (*SAMResponse, error)
 	IsAuthenticated(ctx context.Context, in *IARequest, opts ...grpc.CallOption) (*IAResponse, error)
+	GetAuthenticationProgress(ctx context.Context, in *GAPRequest, opts ...grpc.CallOption) (*GAPResponse, error)
 	EndSession(ctx context.Context, in *ESRequest, opts ...grpc.CallOption) (*Empty, error)
 	SetDefaultBrokerForUser(ctx context.Context, in *SDBFURequest, opts ...grpc.CallOption) (*Empty, error)
+	ResetBrokerForUser(ctx context.Context, in *RBFURequest, opts ...grpc.CallOption) (*Empty, error)
+	IsRecentlyAuthenticated(ctx context.Context, in *IRARequest, opts ...grpc.CallOption) (*IRAResponse, error)
+	GetSessionActions(ctx context.Context, in *GSARequest, opts ...grpc.CallOption) (*GSAResponse, error)
+	CheckLoginPolicy(ctx context.Context, in *CLPRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetLocalPINStatus(ctx context.Context, in *GLPSRequest, opts ...grpc.CallOption) (*GLPSResponse, error)
+	AuthenticateWithLocalPIN(ctx context.Context, in *ALPRequest, opts ...grpc.CallOption) (*ALPResponse, error)
+	SetLocalPIN(ctx context.Context, in *SLPRequest, opts ...grpc.CallOption) (*Empty, error)
+	RemoveLocalPIN(ctx context.Context, in *RLPRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetUserGecos(ctx context.Context, in *SUGRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetUserShell(ctx context.Context, in *SUSRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetUserHome(ctx context.Context, in *SUHRequest, opts ...grpc.CallOption) (*SUHResponse, error)
+	SetUserOverride(ctx context.Context, in *SUORequest, opts ...grpc.CallOption) (*Empty, error)
+	UnsetUserOverride(ctx context.Context, in *UUORequest, opts ...grpc.CallOption) (*Empty, error)
+	ListUserOverrides(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LUOResponse, error)
+	WhoisUID(ctx context.Context, in *WUIDRequest, opts ...grpc.CallOption) (*WUIDResponse, error)
+	WhoisID(ctx context.Context, in *WIDRequest, opts ...grpc.CallOption) (*WIDResponse, error)
+	AdoptUser(ctx context.Context, in *AURequest, opts ...grpc.CallOption) (*Empty, error)
+	ChangeUID(ctx context.Context, in *CUIDRequest, opts ...grpc.CallOption) (*CUIDResponse, error)
+	ReconcileLocalGroups(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RLGResponse, error)
+	GetFailedAuthentications(ctx context.Context, in *GFARequest, opts ...grpc.CallOption) (*GFAResponse, error)
+	GetUserAccountInfo(ctx context.Context, in *GUAIRequest, opts ...grpc.CallOption) (*GUAIResponse, error)
+	SetUserDisplayName(ctx context.Context, in *SUDNRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetUserAvatar(ctx context.Context, in *SUARequest, opts ...grpc.CallOption) (*Empty, error)
+	ListSessions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LSResponse, error)
+	AbortSession(ctx context.Context, in *ASRequest, opts ...grpc.CallOption) (*Empty, error)
+	AuthenticateHeadless(ctx context.Context, in *AHRequest, opts ...grpc.CallOption) (*IAResponse, error)
+	DumpDatabase(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DatabaseDump, error)
+	ImportDatabase(ctx context.Context, in *DatabaseDump, opts ...grpc.CallOption) (*Empty, error)
+	GetVersion(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GVResponse, error)
+	SetDebugSocket(ctx context.Context, in *SDSRequest, opts ...grpc.CallOption) (*SDSResponse, error)
 }
 
 type pAMClient struct {
@@ -111,6 +173,16 @@ func (c *pAMClient) IsAuthenticated(ctx context.Context, in *IARequest, opts ...
 	return out, nil
 }
 
+func (c *pAMClient) GetAuthenticationProgress(ctx context.Context, in *GAPRequest, opts ...grpc.CallOption) (*GAPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GAPResponse)
+	err := c.cc.Invoke(ctx, PAM_GetAuthenticationProgress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *pAMClient) EndSession(ctx context.Context, in *ESRequest, opts ...grpc.CallOption) (*Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Empty)
@@ -118,226 +190,1208 @@ func (c *pAMClient) EndSession(ctx context.Context, in *ESRequest, opts ...grpc.
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *pAMClient) SetDefaultBrokerForUser(ctx context.Context, in *SDBFURequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_SetDefaultBrokerForUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) ResetBrokerForUser(ctx context.Context, in *RBFURequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_ResetBrokerForUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) IsRecentlyAuthenticated(ctx context.Context, in *IRARequest, opts ...grpc.CallOption) (*IRAResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IRAResponse)
+	err := c.cc.Invoke(ctx, PAM_IsRecentlyAuthenticated_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) GetSessionActions(ctx context.Context, in *GSARequest, opts ...grpc.CallOption) (*GSAResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GSAResponse)
+	err := c.cc.Invoke(ctx, PAM_GetSessionActions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) CheckLoginPolicy(ctx context.Context, in *CLPRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_CheckLoginPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) GetLocalPINStatus(ctx context.Context, in *GLPSRequest, opts ...grpc.CallOption) (*GLPSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GLPSResponse)
+	err := c.cc.Invoke(ctx, PAM_GetLocalPINStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) AuthenticateWithLocalPIN(ctx context.Context, in *ALPRequest, opts ...grpc.CallOption) (*ALPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ALPResponse)
+	err := c.cc.Invoke(ctx, PAM_AuthenticateWithLocalPIN_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) SetLocalPIN(ctx context.Context, in *SLPRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_SetLocalPIN_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) RemoveLocalPIN(ctx context.Context, in *RLPRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_RemoveLocalPIN_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) SetUserGecos(ctx context.Context, in *SUGRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_SetUserGecos_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) SetUserShell(ctx context.Context, in *SUSRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_SetUserShell_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) SetUserHome(ctx context.Context, in *SUHRequest, opts ...grpc.CallOption) (*SUHResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SUHResponse)
+	err := c.cc.Invoke(ctx, PAM_SetUserHome_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) SetUserOverride(ctx context.Context, in *SUORequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_SetUserOverride_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) UnsetUserOverride(ctx context.Context, in *UUORequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_UnsetUserOverride_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) ListUserOverrides(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LUOResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LUOResponse)
+	err := c.cc.Invoke(ctx, PAM_ListUserOverrides_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) WhoisUID(ctx context.Context, in *WUIDRequest, opts ...grpc.CallOption) (*WUIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WUIDResponse)
+	err := c.cc.Invoke(ctx, PAM_WhoisUID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) WhoisID(ctx context.Context, in *WIDRequest, opts ...grpc.CallOption) (*WIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WIDResponse)
+	err := c.cc.Invoke(ctx, PAM_WhoisID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) AdoptUser(ctx context.Context, in *AURequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_AdoptUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) ChangeUID(ctx context.Context, in *CUIDRequest, opts ...grpc.CallOption) (*CUIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CUIDResponse)
+	err := c.cc.Invoke(ctx, PAM_ChangeUID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) ReconcileLocalGroups(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RLGResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RLGResponse)
+	err := c.cc.Invoke(ctx, PAM_ReconcileLocalGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) GetFailedAuthentications(ctx context.Context, in *GFARequest, opts ...grpc.CallOption) (*GFAResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GFAResponse)
+	err := c.cc.Invoke(ctx, PAM_GetFailedAuthentications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) GetUserAccountInfo(ctx context.Context, in *GUAIRequest, opts ...grpc.CallOption) (*GUAIResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GUAIResponse)
+	err := c.cc.Invoke(ctx, PAM_GetUserAccountInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) SetUserDisplayName(ctx context.Context, in *SUDNRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_SetUserDisplayName_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) SetUserAvatar(ctx context.Context, in *SUARequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_SetUserAvatar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) ListSessions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LSResponse)
+	err := c.cc.Invoke(ctx, PAM_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) AbortSession(ctx context.Context, in *ASRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_AbortSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) AuthenticateHeadless(ctx context.Context, in *AHRequest, opts ...grpc.CallOption) (*IAResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IAResponse)
+	err := c.cc.Invoke(ctx, PAM_AuthenticateHeadless_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) DumpDatabase(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DatabaseDump, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DatabaseDump)
+	err := c.cc.Invoke(ctx, PAM_DumpDatabase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) ImportDatabase(ctx context.Context, in *DatabaseDump, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, PAM_ImportDatabase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) GetVersion(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GVResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GVResponse)
+	err := c.cc.Invoke(ctx, PAM_GetVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pAMClient) SetDebugSocket(ctx context.Context, in *SDSRequest, opts ...grpc.CallOption) (*SDSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SDSResponse)
+	err := c.cc.Invoke(ctx, PAM_SetDebugSocket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PAMServer is the server API for PAM service.
+// All implementations must embed UnimplementedPAMServer
+// for forward compatibility.
+type PAMServer interface {
+	AvailableBrokers(context.Context, *Empty) (*ABResponse, error)
+	GetPreviousBroker(context.Context, *GPBRequest) (*GPBResponse, error)
+	SelectBroker(context.Context, *SBRequest) (*SBResponse, error)
+	GetAuthenticationModes(context.Context, *GAMRequest) (*GAMResponse, error)
+	SelectAuthenticationMode(context.Context, *SAMRequest) (*SAMResponse, error)
+	IsAuthenticated(context.Context, *IARequest) (*IAResponse, error)
+	GetAuthenticationProgress(context.Context, *GAPRequest) (*GAPResponse, error)
+	EndSession(context.Context, *ESRequest) (*Empty, error)
+	SetDefaultBrokerForUser(context.Context, *SDBFURequest) (*Empty, error)
+	ResetBrokerForUser(context.Context, *RBFURequest) (*Empty, error)
+	IsRecentlyAuthenticated(context.Context, *IRARequest) (*IRAResponse, error)
+	GetSessionActions(context.Context, *GSARequest) (*GSAResponse, error)
+	CheckLoginPolicy(context.Context, *CLPRequest) (*Empty, error)
+	GetLocalPINStatus(context.Context, *GLPSRequest) (*GLPSResponse, error)
+	AuthenticateWithLocalPIN(context.Context, *ALPRequest) (*ALPResponse, error)
+	SetLocalPIN(context.Context, *SLPRequest) (*Empty, error)
+	RemoveLocalPIN(context.Context, *RLPRequest) (*Empty, error)
+	SetUserGecos(context.Context, *SUGRequest) (*Empty, error)
+	SetUserShell(context.Context, *SUSRequest) (*Empty, error)
+	SetUserHome(context.Context, *SUHRequest) (*SUHResponse, error)
+	SetUserOverride(context.Context, *SUORequest) (*Empty, error)
+	UnsetUserOverride(context.Context, *UUORequest) (*Empty, error)
+	ListUserOverrides(context.Context, *Empty) (*LUOResponse, error)
+	WhoisUID(context.Context, *WUIDRequest) (*WUIDResponse, error)
+	WhoisID(context.Context, *WIDRequest) (*WIDResponse, error)
+	AdoptUser(context.Context, *AURequest) (*Empty, error)
+	ChangeUID(context.Context, *CUIDRequest) (*CUIDResponse, error)
+	ReconcileLocalGroups(context.Context, *Empty) (*RLGResponse, error)
+	GetFailedAuthentications(context.Context, *GFARequest) (*GFAResponse, error)
+	GetUserAccountInfo(context.Context, *GUAIRequest) (*GUAIResponse, error)
+	SetUserDisplayName(context.Context, *SUDNRequest) (*Empty, error)
+	SetUserAvatar(context.Context, *SUARequest) (*Empty, error)
+	ListSessions(context.Context, *Empty) (*LSResponse, error)
+	AbortSession(context.Context, *ASRequest) (*Empty, error)
+	AuthenticateHeadless(context.Context, *AHRequest) (*IAResponse, error)
+	DumpDatabase(context.Context, *Empty) (*DatabaseDump, error)
+	ImportDatabase(context.Context, *DatabaseDump) (*Empty, error)
+	GetVersion(context.Context, *Empty) (*GVResponse, error)
+	SetDebugSocket(context.Context, *SDSRequest) (*SDSResponse, error)
+	mustEmbedUnimplementedPAMServer()
+}
+
+// UnimplementedPAMServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPAMServer struct{}
+
+func (UnimplementedPAMServer) AvailableBrokers(context.Context, *Empty) (*ABResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AvailableBrokers not implemented")
+}
+func (UnimplementedPAMServer) GetPreviousBroker(context.Context, *GPBRequest) (*GPBResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPreviousBroker not implemented")
+}
+func (UnimplementedPAMServer) SelectBroker(context.Context, *SBRequest) (*SBResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectBroker not implemented")
+}
+func (UnimplementedPAMServer) GetAuthenticationModes(context.Context, *GAMRequest) (*GAMResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthenticationModes not implemented")
+}
+func (UnimplementedPAMServer) SelectAuthenticationMode(context.Context, *SAMRequest) (*SAMResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectAuthenticationMode not implemented")
+}
+func (UnimplementedPAMServer) IsAuthenticated(context.Context, *IARequest) (*IAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsAuthenticated not implemented")
+}
+func (UnimplementedPAMServer) GetAuthenticationProgress(context.Context, *GAPRequest) (*GAPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthenticationProgress not implemented")
+}
+func (UnimplementedPAMServer) EndSession(context.Context, *ESRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EndSession not implemented")
+}
+func (UnimplementedPAMServer) SetDefaultBrokerForUser(context.Context, *SDBFURequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDefaultBrokerForUser not implemented")
+}
+func (UnimplementedPAMServer) ResetBrokerForUser(context.Context, *RBFURequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetBrokerForUser not implemented")
+}
+func (UnimplementedPAMServer) IsRecentlyAuthenticated(context.Context, *IRARequest) (*IRAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsRecentlyAuthenticated not implemented")
+}
+func (UnimplementedPAMServer) GetSessionActions(context.Context, *GSARequest) (*GSAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSessionActions not implemented")
+}
+func (UnimplementedPAMServer) CheckLoginPolicy(context.Context, *CLPRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckLoginPolicy not implemented")
+}
+func (UnimplementedPAMServer) GetLocalPINStatus(context.Context, *GLPSRequest) (*GLPSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLocalPINStatus not implemented")
+}
+func (UnimplementedPAMServer) AuthenticateWithLocalPIN(context.Context, *ALPRequest) (*ALPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthenticateWithLocalPIN not implemented")
+}
+func (UnimplementedPAMServer) SetLocalPIN(context.Context, *SLPRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLocalPIN not implemented")
+}
+func (UnimplementedPAMServer) RemoveLocalPIN(context.Context, *RLPRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveLocalPIN not implemented")
+}
+func (UnimplementedPAMServer) SetUserGecos(context.Context, *SUGRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserGecos not implemented")
+}
+func (UnimplementedPAMServer) SetUserShell(context.Context, *SUSRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserShell not implemented")
+}
+func (UnimplementedPAMServer) SetUserHome(context.Context, *SUHRequest) (*SUHResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserHome not implemented")
+}
+func (UnimplementedPAMServer) SetUserOverride(context.Context, *SUORequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserOverride not implemented")
+}
+func (UnimplementedPAMServer) UnsetUserOverride(context.Context, *UUORequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnsetUserOverride not implemented")
+}
+func (UnimplementedPAMServer) ListUserOverrides(context.Context, *Empty) (*LUOResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserOverrides not implemented")
+}
+func (UnimplementedPAMServer) WhoisUID(context.Context, *WUIDRequest) (*WUIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WhoisUID not implemented")
+}
+func (UnimplementedPAMServer) WhoisID(context.Context, *WIDRequest) (*WIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WhoisID not implemented")
+}
+func (UnimplementedPAMServer) AdoptUser(context.Context, *AURequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdoptUser not implemented")
+}
+func (UnimplementedPAMServer) ChangeUID(context.Context, *CUIDRequest) (*CUIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeUID not implemented")
+}
+func (UnimplementedPAMServer) ReconcileLocalGroups(context.Context, *Empty) (*RLGResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReconcileLocalGroups not implemented")
+}
+func (UnimplementedPAMServer) GetFailedAuthentications(context.Context, *GFARequest) (*GFAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFailedAuthentications not implemented")
+}
+func (UnimplementedPAMServer) GetUserAccountInfo(context.Context, *GUAIRequest) (*GUAIResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserAccountInfo not implemented")
+}
+func (UnimplementedPAMServer) SetUserDisplayName(context.Context, *SUDNRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserDisplayName not implemented")
+}
+func (UnimplementedPAMServer) SetUserAvatar(context.Context, *SUARequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserAvatar not implemented")
+}
+func (UnimplementedPAMServer) ListSessions(context.Context, *Empty) (*LSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedPAMServer) AbortSession(context.Context, *ASRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortSession not implemented")
+}
+func (UnimplementedPAMServer) AuthenticateHeadless(context.Context, *AHRequest) (*IAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthenticateHeadless not implemented")
+}
+func (UnimplementedPAMServer) DumpDatabase(context.Context, *Empty) (*DatabaseDump, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DumpDatabase not implemented")
+}
+func (UnimplementedPAMServer) ImportDatabase(context.Context, *DatabaseDump) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportDatabase not implemented")
+}
+func (UnimplementedPAMServer) GetVersion(context.Context, *Empty) (*GVResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (UnimplementedPAMServer) SetDebugSocket(context.Context, *SDSRequest) (*SDSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDebugSocket not implemented")
+}
+func (UnimplementedPAMServer) mustEmbedUnimplementedPAMServer() {}
+func (UnimplementedPAMServer) testEmbeddedByValue()             {}
+
+// UnsafePAMServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PAMServer will
+// result in compilation errors.
+type UnsafePAMServer interface {
+	mustEmbedUnimplementedPAMServer()
+}
+
+func RegisterPAMServer(s grpc.ServiceRegistrar, srv PAMServer) {
+	// If the following call pancis, it indicates UnimplementedPAMServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PAM_ServiceDesc, srv)
+}
+
+func _PAM_AvailableBrokers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).AvailableBrokers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_AvailableBrokers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).AvailableBrokers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_GetPreviousBroker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GPBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).GetPreviousBroker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_GetPreviousBroker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).GetPreviousBroker(ctx, req.(*GPBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_SelectBroker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).SelectBroker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_SelectBroker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).SelectBroker(ctx, req.(*SBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_GetAuthenticationModes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GAMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).GetAuthenticationModes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_GetAuthenticationModes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).GetAuthenticationModes(ctx, req.(*GAMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_SelectAuthenticationMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SAMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).SelectAuthenticationMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_SelectAuthenticationMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).SelectAuthenticationMode(ctx, req.(*SAMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_IsAuthenticated_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).IsAuthenticated(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_IsAuthenticated_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).IsAuthenticated(ctx, req.(*IARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_GetAuthenticationProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GAPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).GetAuthenticationProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_GetAuthenticationProgress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).GetAuthenticationProgress(ctx, req.(*GAPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_EndSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ESRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).EndSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_EndSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).EndSession(ctx, req.(*ESRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_SetDefaultBrokerForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SDBFURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).SetDefaultBrokerForUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_SetDefaultBrokerForUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).SetDefaultBrokerForUser(ctx, req.(*SDBFURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_ResetBrokerForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RBFURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).ResetBrokerForUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_ResetBrokerForUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).ResetBrokerForUser(ctx, req.(*RBFURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_IsRecentlyAuthenticated_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IRARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).IsRecentlyAuthenticated(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_IsRecentlyAuthenticated_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).IsRecentlyAuthenticated(ctx, req.(*IRARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_GetSessionActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GSARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).GetSessionActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_GetSessionActions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).GetSessionActions(ctx, req.(*GSARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_CheckLoginPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CLPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).CheckLoginPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_CheckLoginPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).CheckLoginPolicy(ctx, req.(*CLPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_GetLocalPINStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GLPSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).GetLocalPINStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_GetLocalPINStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).GetLocalPINStatus(ctx, req.(*GLPSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_AuthenticateWithLocalPIN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ALPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).AuthenticateWithLocalPIN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_AuthenticateWithLocalPIN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).AuthenticateWithLocalPIN(ctx, req.(*ALPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_SetLocalPIN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SLPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).SetLocalPIN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_SetLocalPIN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).SetLocalPIN(ctx, req.(*SLPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_RemoveLocalPIN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RLPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).RemoveLocalPIN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_RemoveLocalPIN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).RemoveLocalPIN(ctx, req.(*RLPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_SetUserGecos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SUGRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).SetUserGecos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_SetUserGecos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).SetUserGecos(ctx, req.(*SUGRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_SetUserShell_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SUSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).SetUserShell(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_SetUserShell_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).SetUserShell(ctx, req.(*SUSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_SetUserHome_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SUHRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).SetUserHome(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_SetUserHome_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).SetUserHome(ctx, req.(*SUHRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_SetUserOverride_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SUORequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).SetUserOverride(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_SetUserOverride_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).SetUserOverride(ctx, req.(*SUORequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_UnsetUserOverride_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UUORequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).UnsetUserOverride(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_UnsetUserOverride_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).UnsetUserOverride(ctx, req.(*UUORequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_ListUserOverrides_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).ListUserOverrides(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_ListUserOverrides_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).ListUserOverrides(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_WhoisUID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WUIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).WhoisUID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_WhoisUID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).WhoisUID(ctx, req.(*WUIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_WhoisID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).WhoisID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_WhoisID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).WhoisID(ctx, req.(*WIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PAM_AdoptUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).AdoptUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_AdoptUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).AdoptUser(ctx, req.(*AURequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *pAMClient) SetDefaultBrokerForUser(ctx context.Context, in *SDBFURequest, opts ...grpc.CallOption) (*Empty, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Empty)
-	err := c.cc.Invoke(ctx, PAM_SetDefaultBrokerForUser_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _PAM_ChangeUID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CUIDRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(PAMServer).ChangeUID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_ChangeUID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).ChangeUID(ctx, req.(*CUIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// PAMServer is the server API for PAM service.
-// All implementations must embed UnimplementedPAMServer
-// for forward compatibility.
-type PAMServer interface {
-	AvailableBrokers(context.Context, *Empty) (*ABResponse, error)
-	GetPreviousBroker(context.Context, *GPBRequest) (*GPBResponse, error)
-	SelectBroker(context.Context, *SBRequest) (*SBResponse, error)
-	GetAuthenticationModes(context.Context, *GAMRequest) (*GAMResponse, error)
-	SelectAuthenticationMode(context.Context, *SAMRequest) (*SAMResponse, error)
-	IsAuthenticated(context.Context, *IARequest) (*IAResponse, error)
-	EndSession(context.Context, *ESRequest) (*Empty, error)
-	SetDefaultBrokerForUser(context.Context, *SDBFURequest) (*Empty, error)
-	mustEmbedUnimplementedPAMServer()
+func _PAM_ReconcileLocalGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).ReconcileLocalGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_ReconcileLocalGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).ReconcileLocalGroups(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedPAMServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedPAMServer struct{}
-
-func (UnimplementedPAMServer) AvailableBrokers(context.Context, *Empty) (*ABResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AvailableBrokers not implemented")
-}
-func (UnimplementedPAMServer) GetPreviousBroker(context.Context, *GPBRequest) (*GPBResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetPreviousBroker not implemented")
-}
-func (UnimplementedPAMServer) SelectBroker(context.Context, *SBRequest) (*SBResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SelectBroker not implemented")
-}
-func (UnimplementedPAMServer) GetAuthenticationModes(context.Context, *GAMRequest) (*GAMResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetAuthenticationModes not implemented")
-}
-func (UnimplementedPAMServer) SelectAuthenticationMode(context.Context, *SAMRequest) (*SAMResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SelectAuthenticationMode not implemented")
-}
-func (UnimplementedPAMServer) IsAuthenticated(context.Context, *IARequest) (*IAResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method IsAuthenticated not implemented")
-}
-func (UnimplementedPAMServer) EndSession(context.Context, *ESRequest) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method EndSession not implemented")
-}
-func (UnimplementedPAMServer) SetDefaultBrokerForUser(context.Context, *SDBFURequest) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SetDefaultBrokerForUser not implemented")
+func _PAM_GetFailedAuthentications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GFARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).GetFailedAuthentications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_GetFailedAuthentications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).GetFailedAuthentications(ctx, req.(*GFARequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedPAMServer) mustEmbedUnimplementedPAMServer() {}
-func (UnimplementedPAMServer) testEmbeddedByValue()             {}
 
-// UnsafePAMServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to PAMServer will
-// result in compilation errors.
-type UnsafePAMServer interface {
-	mustEmbedUnimplementedPAMServer()
+func _PAM_GetUserAccountInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GUAIRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PAMServer).GetUserAccountInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_GetUserAccountInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).GetUserAccountInfo(ctx, req.(*GUAIRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterPAMServer(s grpc.ServiceRegistrar, srv PAMServer) {
-	// If the following call pancis, it indicates UnimplementedPAMServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _PAM_SetUserDisplayName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SUDNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&PAM_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(PAMServer).SetUserDisplayName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PAM_SetUserDisplayName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PAMServer).SetUserDisplayName(ctx, req.(*SUDNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _PAM_AvailableBrokers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _PAM_SetUserAvatar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SUARequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PAMServer).AvailableBrokers(ctx, in)
+		return srv.(PAMServer).SetUserAvatar(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: PAM_AvailableBrokers_FullMethodName,
+		FullMethod: PAM_SetUserAvatar_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PAMServer).AvailableBrokers(ctx, req.(*Empty))
+		return srv.(PAMServer).SetUserAvatar(ctx, req.(*SUARequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PAM_GetPreviousBroker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GPBRequest)
+func _PAM_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PAMServer).GetPreviousBroker(ctx, in)
+		return srv.(PAMServer).ListSessions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: PAM_GetPreviousBroker_FullMethodName,
+		FullMethod: PAM_ListSessions_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PAMServer).GetPreviousBroker(ctx, req.(*GPBRequest))
+		return srv.(PAMServer).ListSessions(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PAM_SelectBroker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SBRequest)
+func _PAM_AbortSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ASRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PAMServer).SelectBroker(ctx, in)
+		return srv.(PAMServer).AbortSession(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: PAM_SelectBroker_FullMethodName,
+		FullMethod: PAM_AbortSession_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PAMServer).SelectBroker(ctx, req.(*SBRequest))
+		return srv.(PAMServer).AbortSession(ctx, req.(*ASRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PAM_GetAuthenticationModes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GAMRequest)
+func _PAM_AuthenticateHeadless_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AHRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PAMServer).GetAuthenticationModes(ctx, in)
+		return srv.(PAMServer).AuthenticateHeadless(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: PAM_GetAuthenticationModes_FullMethodName,
+		FullMethod: PAM_AuthenticateHeadless_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PAMServer).GetAuthenticationModes(ctx, req.(*GAMRequest))
+		return srv.(PAMServer).AuthenticateHeadless(ctx, req.(*AHRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PAM_SelectAuthenticationMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SAMRequest)
+func _PAM_DumpDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PAMServer).SelectAuthenticationMode(ctx, in)
+		return srv.(PAMServer).DumpDatabase(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: PAM_SelectAuthenticationMode_FullMethodName,
+		FullMethod: PAM_DumpDatabase_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PAMServer).SelectAuthenticationMode(ctx, req.(*SAMRequest))
+		return srv.(PAMServer).DumpDatabase(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PAM_IsAuthenticated_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(IARequest)
+func _PAM_ImportDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DatabaseDump)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PAMServer).IsAuthenticated(ctx, in)
+		return srv.(PAMServer).ImportDatabase(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: PAM_IsAuthenticated_FullMethodName,
+		FullMethod: PAM_ImportDatabase_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PAMServer).IsAuthenticated(ctx, req.(*IARequest))
+		return srv.(PAMServer).ImportDatabase(ctx, req.(*DatabaseDump))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PAM_EndSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ESRequest)
+func _PAM_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PAMServer).EndSession(ctx, in)
+		return srv.(PAMServer).GetVersion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: PAM_EndSession_FullMethodName,
+		FullMethod: PAM_GetVersion_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PAMServer).EndSession(ctx, req.(*ESRequest))
+		return srv.(PAMServer).GetVersion(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PAM_SetDefaultBrokerForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SDBFURequest)
+func _PAM_SetDebugSocket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SDSRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PAMServer).SetDefaultBrokerForUser(ctx, in)
+		return srv.(PAMServer).SetDebugSocket(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: PAM_SetDefaultBrokerForUser_FullMethodName,
+		FullMethod: PAM_SetDebugSocket_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PAMServer).SetDefaultBrokerForUser(ctx, req.(*SDBFURequest))
+		return srv.(PAMServer).SetDebugSocket(ctx, req.(*SDSRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -373,6 +1427,10 @@ var PAM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "IsAuthenticated",
 			Handler:    _PAM_IsAuthenticated_Handler,
 		},
+		{
+			MethodName: "GetAuthenticationProgress",
+			Handler:    _PAM_GetAuthenticationProgress_Handler,
+		},
 		{
 			MethodName: "EndSession",
 			Handler:    _PAM_EndSession_Handler,
@@ -381,6 +1439,126 @@ var PAM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetDefaultBrokerForUser",
 			Handler:    _PAM_SetDefaultBrokerForUser_Handler,
 		},
+		{
+			MethodName: "ResetBrokerForUser",
+			Handler:    _PAM_ResetBrokerForUser_Handler,
+		},
+		{
+			MethodName: "IsRecentlyAuthenticated",
+			Handler:    _PAM_IsRecentlyAuthenticated_Handler,
+		},
+		{
+			MethodName: "GetSessionActions",
+			Handler:    _PAM_GetSessionActions_Handler,
+		},
+		{
+			MethodName: "CheckLoginPolicy",
+			Handler:    _PAM_CheckLoginPolicy_Handler,
+		},
+		{
+			MethodName: "GetLocalPINStatus",
+			Handler:    _PAM_GetLocalPINStatus_Handler,
+		},
+		{
+			MethodName: "AuthenticateWithLocalPIN",
+			Handler:    _PAM_AuthenticateWithLocalPIN_Handler,
+		},
+		{
+			MethodName: "SetLocalPIN",
+			Handler:    _PAM_SetLocalPIN_Handler,
+		},
+		{
+			MethodName: "RemoveLocalPIN",
+			Handler:    _PAM_RemoveLocalPIN_Handler,
+		},
+		{
+			MethodName: "SetUserGecos",
+			Handler:    _PAM_SetUserGecos_Handler,
+		},
+		{
+			MethodName: "SetUserShell",
+			Handler:    _PAM_SetUserShell_Handler,
+		},
+		{
+			MethodName: "SetUserHome",
+			Handler:    _PAM_SetUserHome_Handler,
+		},
+		{
+			MethodName: "SetUserOverride",
+			Handler:    _PAM_SetUserOverride_Handler,
+		},
+		{
+			MethodName: "UnsetUserOverride",
+			Handler:    _PAM_UnsetUserOverride_Handler,
+		},
+		{
+			MethodName: "ListUserOverrides",
+			Handler:    _PAM_ListUserOverrides_Handler,
+		},
+		{
+			MethodName: "WhoisUID",
+			Handler:    _PAM_WhoisUID_Handler,
+		},
+		{
+			MethodName: "WhoisID",
+			Handler:    _PAM_WhoisID_Handler,
+		},
+		{
+			MethodName: "AdoptUser",
+			Handler:    _PAM_AdoptUser_Handler,
+		},
+		{
+			MethodName: "ChangeUID",
+			Handler:    _PAM_ChangeUID_Handler,
+		},
+		{
+			MethodName: "ReconcileLocalGroups",
+			Handler:    _PAM_ReconcileLocalGroups_Handler,
+		},
+		{
+			MethodName: "GetFailedAuthentications",
+			Handler:    _PAM_GetFailedAuthentications_Handler,
+		},
+		{
+			MethodName: "GetUserAccountInfo",
+			Handler:    _PAM_GetUserAccountInfo_Handler,
+		},
+		{
+			MethodName: "SetUserDisplayName",
+			Handler:    _PAM_SetUserDisplayName_Handler,
+		},
+		{
+			MethodName: "SetUserAvatar",
+			Handler:    _PAM_SetUserAvatar_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _PAM_ListSessions_Handler,
+		},
+		{
+			MethodName: "AbortSession",
+			Handler:    _PAM_AbortSession_Handler,
+		},
+		{
+			MethodName: "AuthenticateHeadless",
+			Handler:    _PAM_AuthenticateHeadless_Handler,
+		},
+		{
+			MethodName: "DumpDatabase",
+			Handler:    _PAM_DumpDatabase_Handler,
+		},
+		{
+			MethodName: "ImportDatabase",
+			Handler:    _PAM_ImportDatabase_Handler,
+		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _PAM_GetVersion_Handler,
+		},
+		{
+			MethodName: "SetDebugSocket",
+			Handler:    _PAM_SetDebugSocket_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "authd.proto",
@@ -395,6 +1573,7 @@ const (
 	NSS_GetGroupEntries_FullMethodName  = "/authd.NSS/GetGroupEntries"
 	NSS_GetShadowByName_FullMethodName  = "/authd.NSS/GetShadowByName"
 	NSS_GetShadowEntries_FullMethodName = "/authd.NSS/GetShadowEntries"
+	NSS_GetAvatarByName_FullMethodName  = "/authd.NSS/GetAvatarByName"
 )
 
 // NSSClient is the client API for NSS service.
@@ -409,6 +1588,7 @@ type NSSClient interface {
 	GetGroupEntries(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GroupEntries, error)
 	GetShadowByName(ctx context.Context, in *GetShadowByNameRequest, opts ...grpc.CallOption) (*ShadowEntry, error)
 	GetShadowEntries(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ShadowEntries, error)
+	GetAvatarByName(ctx context.Context, in *GetAvatarByNameRequest, opts ...grpc.CallOption) (*Avatar, error)
 }
 
 type nSSClient struct {
@@ -499,6 +1679,16 @@ func (c *nSSClient) GetShadowEntries(ctx context.Context, in *Empty, opts ...grp
 	return out, nil
 }
 
+func (c *nSSClient) GetAvatarByName(ctx context.Context, in *GetAvatarByNameRequest, opts ...grpc.CallOption) (*Avatar, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Avatar)
+	err := c.cc.Invoke(ctx, NSS_GetAvatarByName_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NSSServer is the server API for NSS service.
 // All implementations must embed UnimplementedNSSServer
 // for forward compatibility.
@@ -511,6 +1701,7 @@ type NSSServer interface {
 	GetGroupEntries(context.Context, *Empty) (*GroupEntries, error)
 	GetShadowByName(context.Context, *GetShadowByNameRequest) (*ShadowEntry, error)
 	GetShadowEntries(context.Context, *Empty) (*ShadowEntries, error)
+	GetAvatarByName(context.Context, *GetAvatarByNameRequest) (*Avatar, error)
 	mustEmbedUnimplementedNSSServer()
 }
 
@@ -545,6 +1736,9 @@ func (UnimplementedNSSServer) GetShadowByName(context.Context, *GetShadowByNameR
 func (UnimplementedNSSServer) GetShadowEntries(context.Context, *Empty) (*ShadowEntries, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetShadowEntries not implemented")
 }
+func (UnimplementedNSSServer) GetAvatarByName(context.Context, *GetAvatarByNameRequest) (*Avatar, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAvatarByName not implemented")
+}
 func (UnimplementedNSSServer) mustEmbedUnimplementedNSSServer() {}
 func (UnimplementedNSSServer) testEmbeddedByValue()             {}
 
@@ -710,6 +1904,24 @@ func _NSS_GetShadowEntries_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NSS_GetAvatarByName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAvatarByNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NSSServer).GetAvatarByName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NSS_GetAvatarByName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NSSServer).GetAvatarByName(ctx, req.(*GetAvatarByNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NSS_ServiceDesc is the grpc.ServiceDesc for NSS service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -749,6 +1961,10 @@ var NSS_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetShadowEntries",
 			Handler:    _NSS_GetShadowEntries_Handler,
 		},
+		{
+			MethodName: "GetAvatarByName",
+			Handler:    _NSS_GetAvatarByName_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "authd.proto",
@@ -0,0 +1,211 @@
+// Package pwquality checks the quality of the new passwords with built-in rules, reading the same configuration as
+// libpwquality. It's used when libpwquality is not available or can't read its configuration.
+//
+// Only a subset of the libpwquality settings is supported: minlen, minclass, maxrepeat, maxsequence, difok and
+// enforcing. The other settings are ignored.
+package pwquality
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ubuntu/authd/internal/i18n"
+)
+
+// DefaultConfigPath is the configuration file of libpwquality. The files of its .d directory are read after it.
+const DefaultConfigPath = "/etc/security/pwquality.conf"
+
+// supportedSettings are the keys of the libpwquality configuration applied by the built-in rules.
+var supportedSettings = []string{"minlen", "minclass", "maxrepeat", "maxsequence", "difok", "enforcing"}
+
+// Settings are the rules the new passwords must follow.
+type Settings struct {
+	// MinLen is the minimum number of characters of the new password.
+	MinLen int
+	// MinClass is the minimum number of character classes (digits, upper and lower case letters, others) of the new
+	// password.
+	MinClass int
+	// MaxRepeat is the maximum number of consecutive identical characters, 0 disables the check.
+	MaxRepeat int
+	// MaxSequence is the maximum length of monotonic character sequences, like 12345 or fedcb, 0 disables the check.
+	MaxSequence int
+	// DifOK is the minimum number of characters of the new password which must not be in the old one.
+	DifOK int
+	// Enforcing rejects the passwords failing the checks. Otherwise, all the passwords are accepted.
+	Enforcing bool
+}
+
+// DefaultSettings are the default settings of libpwquality.
+var DefaultSettings = Settings{
+	MinLen:    8,
+	DifOK:     1,
+	Enforcing: true,
+}
+
+// LoadSettings returns the settings of the libpwquality configuration file at path and of its .d directory, applied
+// over the default ones. A missing configuration gives the default settings.
+func LoadSettings(path string) (s Settings, err error) {
+	s = DefaultSettings
+
+	paths := []string{path}
+	confs, err := filepath.Glob(path + ".d/*.conf")
+	if err != nil {
+		return s, err
+	}
+	slices.Sort(confs)
+	paths = append(paths, confs...)
+
+	for _, p := range paths {
+		if err := s.read(p); errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return DefaultSettings, fmt.Errorf("can't read password quality configuration %q: %w", p, err)
+		}
+	}
+	return s, nil
+}
+
+// read applies the settings of the configuration file at path.
+func (s *Settings) read(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		if !slices.Contains(supportedSettings, key) {
+			// The settings we don't support are ignored, like libpwquality does with the unknown ones.
+			continue
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid value %q for %s", n, value, key)
+		}
+
+		switch key {
+		case "minlen":
+			s.MinLen = v
+		case "minclass":
+			s.MinClass = v
+		case "maxrepeat":
+			s.MaxRepeat = v
+		case "maxsequence":
+			s.MaxSequence = v
+		case "difok":
+			s.DifOK = v
+		case "enforcing":
+			s.Enforcing = v != 0
+		}
+	}
+	return scanner.Err()
+}
+
+// Check returns an error explaining why the new password doesn't follow the rules, translated with the catalog. The
+// passwords are never copied to strings, so that the callers can wipe them.
+func (s Settings) Check(catalog *i18n.Catalog, oldPassword, newPassword []byte) error {
+	if !s.Enforcing {
+		return nil
+	}
+
+	newRunes := bytes.Runes(newPassword)
+	defer clear(newRunes)
+
+	if len(oldPassword) > 0 {
+		if bytes.Equal(oldPassword, newPassword) {
+			return errors.New(catalog.G("The password is the same as the old one"))
+		}
+		if bytes.EqualFold(oldPassword, newPassword) {
+			return errors.New(catalog.G("The password differs with case changes only"))
+		}
+		if s.DifOK > 0 && newCharacters(oldPassword, newRunes) < s.DifOK {
+			return errors.New(catalog.G("The password is too similar to the old one"))
+		}
+	}
+
+	if len(newRunes) < s.MinLen {
+		return fmt.Errorf(catalog.G("The password is shorter than %d characters"), s.MinLen)
+	}
+	if characterClasses(newRunes) < s.MinClass {
+		return fmt.Errorf(catalog.G("The password contains less than %d character classes"), s.MinClass)
+	}
+	if s.MaxRepeat > 0 && longestRun(newRunes, 0) > s.MaxRepeat {
+		return fmt.Errorf(catalog.G("The password contains more than %d same characters consecutively"), s.MaxRepeat)
+	}
+	if s.MaxSequence > 0 && max(longestRun(newRunes, 1), longestRun(newRunes, -1)) > s.MaxSequence {
+		return fmt.Errorf(catalog.G("The password contains monotonic sequence longer than %d characters"), s.MaxSequence)
+	}
+	return nil
+}
+
+// newCharacters returns the number of characters of the new password which are not in the old one.
+func newCharacters(oldPassword []byte, newRunes []rune) int {
+	oldRunes := bytes.Runes(oldPassword)
+	defer clear(oldRunes)
+
+	var n int
+	for _, r := range newRunes {
+		if !slices.Contains(oldRunes, r) {
+			n++
+		}
+	}
+	return n
+}
+
+// characterClasses returns the number of character classes of the password: digits, upper case letters, lower case
+// letters and others.
+func characterClasses(password []rune) int {
+	var digit, upper, lower, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		default:
+			other = true
+		}
+	}
+	var n int
+	for _, c := range []bool{digit, upper, lower, other} {
+		if c {
+			n++
+		}
+	}
+	return n
+}
+
+// longestRun returns the length of the longest run of characters each differing from the previous one by step.
+func longestRun(password []rune, step rune) int {
+	var longest, current int
+	for i, r := range password {
+		if i > 0 && r-password[i-1] == step {
+			current++
+		} else {
+			current = 1
+		}
+		longest = max(longest, current)
+	}
+	return longest
+}
@@ -0,0 +1,120 @@
+package pwquality_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/pwquality"
+)
+
+func TestLoadSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config  string
+		noConf  bool
+		dropIns map[string]string
+
+		want    pwquality.Settings
+		wantErr bool
+	}{
+		"Default_settings_without_configuration": {noConf: true, want: pwquality.DefaultSettings},
+		"Default_settings_with_commented_configuration": {
+			config: "# minlen = 12\n\n#minclass = 3\n",
+			want:   pwquality.DefaultSettings,
+		},
+		"Settings_of_configuration": {
+			config: "minlen = 12\nminclass=3 # comment\nmaxrepeat = 2\nmaxsequence = 3\ndifok = 5\nenforcing = 0\n",
+			want:   pwquality.Settings{MinLen: 12, MinClass: 3, MaxRepeat: 2, MaxSequence: 3, DifOK: 5},
+		},
+		"Unsupported_settings_are_ignored": {
+			config: "dictcheck = 1\nbadwords = foo bar\nenforce_for_root\nminlen = 10\n",
+			want:   pwquality.Settings{MinLen: 10, DifOK: 1, Enforcing: true},
+		},
+		"Drop-ins_override_configuration_in_order": {
+			config:  "minlen = 10\nminclass = 2\n",
+			dropIns: map[string]string{"20-second.conf": "minlen = 14\n", "10-first.conf": "minlen = 12\nmaxrepeat = 3\n", "ignored.txt": "minlen = 1\n"},
+			want:    pwquality.Settings{MinLen: 14, MinClass: 2, MaxRepeat: 3, DifOK: 1, Enforcing: true},
+		},
+		"Drop-ins_without_configuration": {
+			noConf:  true,
+			dropIns: map[string]string{"10-first.conf": "minclass = 4\n"},
+			want:    pwquality.Settings{MinLen: 8, MinClass: 4, DifOK: 1, Enforcing: true},
+		},
+
+		"Error_on_invalid_value": {config: "minlen = eight\n", want: pwquality.DefaultSettings, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "pwquality.conf")
+			if !tc.noConf {
+				require.NoError(t, os.WriteFile(path, []byte(tc.config), 0600), "Setup: could not write configuration")
+			}
+			if tc.dropIns != nil {
+				require.NoError(t, os.Mkdir(path+".d", 0700), "Setup: could not create drop-ins directory")
+				for name, content := range tc.dropIns {
+					require.NoError(t, os.WriteFile(filepath.Join(path+".d", name), []byte(content), 0600),
+						"Setup: could not write drop-in")
+				}
+			}
+
+			got, err := pwquality.LoadSettings(path)
+			if tc.wantErr {
+				require.Error(t, err, "LoadSettings should return an error, but did not")
+			} else {
+				require.NoError(t, err, "LoadSettings should not return an error, but did")
+			}
+			require.Equal(t, tc.want, got, "LoadSettings should return the expected settings")
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		settings    *pwquality.Settings
+		oldPassword string
+		newPassword string
+
+		wantErr bool
+	}{
+		"Accept_password_following_default_rules":         {newPassword: "correct horse"},
+		"Accept_password_different_from_old_one":          {oldPassword: "old password", newPassword: "new secret"},
+		"Accept_password_with_enough_character_classes":   {settings: &pwquality.Settings{MinLen: 8, MinClass: 3, Enforcing: true}, newPassword: "Passw0rdz"},
+		"Accept_password_with_allowed_repeats":            {settings: &pwquality.Settings{MaxRepeat: 2, Enforcing: true}, newPassword: "aabbccdd"},
+		"Accept_password_with_allowed_sequences":          {settings: &pwquality.Settings{MaxSequence: 3, Enforcing: true}, newPassword: "abc-321-xyz"},
+		"Accept_password_counting_characters_not_bytes":   {settings: &pwquality.Settings{MinLen: 4, Enforcing: true}, newPassword: "äöüß"},
+		"Accept_any_password_when_settings_not_enforcing": {settings: &pwquality.Settings{MinLen: 8}, newPassword: "short"},
+
+		"Error_when_password_is_too_short":                  {newPassword: "short", wantErr: true},
+		"Error_when_password_is_the_old_one":                {oldPassword: "same password", newPassword: "same password", wantErr: true},
+		"Error_when_password_only_changes_case":             {oldPassword: "Some Password", newPassword: "sOME pASSWORD", wantErr: true},
+		"Error_when_password_is_too_similar_to_the_old_one": {oldPassword: "password1", newPassword: "password11", wantErr: true},
+		"Error_when_password_has_too_few_character_classes": {settings: &pwquality.Settings{MinLen: 8, MinClass: 3, Enforcing: true}, newPassword: "password1", wantErr: true},
+		"Error_when_password_has_too_many_repeats":          {settings: &pwquality.Settings{MaxRepeat: 2, Enforcing: true}, newPassword: "passsword", wantErr: true},
+		"Error_when_password_has_increasing_sequence":       {settings: &pwquality.Settings{MaxSequence: 3, Enforcing: true}, newPassword: "pass1234", wantErr: true},
+		"Error_when_password_has_decreasing_sequence":       {settings: &pwquality.Settings{MaxSequence: 3, Enforcing: true}, newPassword: "pass-dcba", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := pwquality.DefaultSettings
+			if tc.settings != nil {
+				settings = *tc.settings
+			}
+
+			err := settings.Check(nil, []byte(tc.oldPassword), []byte(tc.newPassword))
+			if tc.wantErr {
+				require.Error(t, err, "Check should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Check should not return an error, but did")
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Package compat negotiates the features of the protocol between the daemon and its clients, so that the NSS modules
+// and PAM clients released before a change of the protocol keep working with a newer daemon.
+//
+// The clients report the version of the protocol they speak in the MetadataKey metadata of their requests. The
+// clients which don't report any were released before the negotiation, and speak the LegacyVersion.
+package compat
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey is the key of the gRPC metadata the clients report their protocol version in.
+const MetadataKey = "authd-client-version"
+
+const (
+	// LegacyVersion is the protocol version of the clients which don't report any.
+	LegacyVersion = 1
+	// CurrentVersion is the protocol version of the clients released with this daemon.
+	CurrentVersion = 2
+)
+
+// Feature is a part of the protocol which only the clients speaking a recent enough version support.
+type Feature struct {
+	name       string
+	minVersion int
+}
+
+var (
+	// EncryptionAlgorithms is the support of the encryption algorithms of the brokers other than the default one.
+	EncryptionAlgorithms = Feature{name: "encryption algorithms", minVersion: 2}
+	// EncryptionKeyRotation is the support of the encryption keys changed by the brokers during the sessions.
+	EncryptionKeyRotation = Feature{name: "encryption key rotation", minVersion: 2}
+)
+
+// String returns the name of the feature.
+func (f Feature) String() string {
+	return f.name
+}
+
+// ClientVersion returns the protocol version reported by the client of the request.
+func ClientVersion(ctx context.Context) int {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return LegacyVersion
+	}
+	values := md.Get(MetadataKey)
+	if len(values) == 0 {
+		return LegacyVersion
+	}
+	v, err := strconv.Atoi(values[0])
+	if err != nil || v < LegacyVersion {
+		return LegacyVersion
+	}
+	return v
+}
+
+// Supports returns whether the client of the request supports the feature.
+func Supports(ctx context.Context, f Feature) bool {
+	return ClientVersion(ctx) >= f.minVersion
+}
+
+// Require returns an error matching authderrors.ErrClientTooOld if the client of the request doesn't support the
+// feature, with the reason why the request needs it.
+func Require(ctx context.Context, f Feature, reason string) error {
+	if Supports(ctx, f) {
+		return nil
+	}
+	return authderrors.ErrClientTooOld.Wrap(fmt.Errorf("%s, but this client doesn't support %s (protocol version %d, %d needed): update it",
+		reason, f, ClientVersion(ctx), f.minVersion))
+}
+
+// AsCurrentClient returns a context for the requests the daemon makes to its own services on behalf of a client,
+// which support all the features of the protocol.
+func AsCurrentClient(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Set(MetadataKey, strconv.Itoa(CurrentVersion))
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// UnaryClientInterceptor reports the protocol version of the client in the metadata of each request.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, strconv.Itoa(CurrentVersion))
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,68 @@
+package compat_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/services/compat"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestClientVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		noMetadata bool
+		versions   []string
+
+		wantVersion        int
+		wantSupportsRecent bool
+	}{
+		"Legacy_version_without_metadata":      {noMetadata: true, wantVersion: compat.LegacyVersion},
+		"Legacy_version_without_version":       {wantVersion: compat.LegacyVersion},
+		"Legacy_version_with_invalid_version":  {versions: []string{"invalid"}, wantVersion: compat.LegacyVersion},
+		"Legacy_version_with_negative_version": {versions: []string{"-1"}, wantVersion: compat.LegacyVersion},
+		"Reported_version":                     {versions: []string{"2"}, wantVersion: 2, wantSupportsRecent: true},
+		"Newer_version":                        {versions: []string{"42"}, wantVersion: 42, wantSupportsRecent: true},
+		"First_reported_version":               {versions: []string{"1", "2"}, wantVersion: 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if !tc.noMetadata {
+				md := metadata.MD{}
+				md.Append(compat.MetadataKey, tc.versions...)
+				ctx = metadata.NewIncomingContext(ctx, md)
+			}
+
+			require.Equal(t, tc.wantVersion, compat.ClientVersion(ctx), "ClientVersion should return the expected version")
+			require.Equal(t, tc.wantSupportsRecent, compat.Supports(ctx, compat.EncryptionKeyRotation),
+				"Supports should return whether the client supports the feature")
+
+			err := compat.Require(ctx, compat.EncryptionKeyRotation, "the broker changed its encryption key")
+			if tc.wantSupportsRecent {
+				require.NoError(t, err, "Require should not return an error, but did")
+				return
+			}
+			require.True(t, errors.Is(err, authderrors.ErrClientTooOld), "Require should return ErrClientTooOld, got: %v", err)
+		})
+	}
+}
+
+func TestAsCurrentClient(t *testing.T) {
+	t.Parallel()
+
+	md := metadata.Pairs(compat.MetadataKey, "1", "other", "value")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	got := compat.AsCurrentClient(ctx)
+	require.Equal(t, compat.CurrentVersion, compat.ClientVersion(got), "The context should report the current version")
+	gotMD, _ := metadata.FromIncomingContext(got)
+	require.Equal(t, []string{"value"}, gotMD.Get("other"), "The other metadata should be kept")
+	require.Equal(t, compat.LegacyVersion, compat.ClientVersion(ctx), "The original context should not be modified")
+}
@@ -0,0 +1,217 @@
+// Package dbusbridge exports on the system bus a D-Bus interface mirroring the core functionality of the gRPC services,
+// so that the desktop components can integrate with authd without linking gRPC.
+package dbusbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/consts"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/internal/users/types"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// ObjectPath is the path of the object implementing the interface.
+	ObjectPath = "/com/ubuntu/authd"
+	// Interface is the name of the interface.
+	Interface = "com.ubuntu.authd.Manager1"
+
+	// ErrNotFound is the name of the D-Bus error returned when the requested user or broker doesn't exist.
+	ErrNotFound = "com.ubuntu.authd.Error.NotFound"
+	// ErrFailed is the name of the D-Bus error returned when the request failed.
+	ErrFailed = "com.ubuntu.authd.Error.Failed"
+)
+
+// User is a user stored in the database, as returned by the methods of the interface.
+type User struct {
+	Name  string
+	UID   uint32
+	GID   uint32
+	Gecos string
+	Dir   string
+	Shell string
+}
+
+// Broker is a broker loaded by the daemon, as returned by the methods of the interface.
+type Broker struct {
+	ID        string
+	Name      string
+	BrandIcon string
+}
+
+// Bridge answers the D-Bus method calls with the user and broker managers of the daemon, and emits signals when the
+// users change.
+type Bridge struct {
+	conn          *dbus.Conn
+	userManager   *users.Manager
+	brokerManager *brokers.Manager
+}
+
+type options struct {
+	busName string
+}
+
+// Option is the function signature used to tweak the bridge creation.
+type Option func(*options)
+
+// WithBusName requests the given name on the system bus instead of the one of authd.
+// This option is only useful in tests.
+func WithBusName(name string) Option {
+	return func(o *options) {
+		o.busName = name
+	}
+}
+
+// Export connects to the system bus, exports the interface and requests the name of authd.
+func Export(userManager *users.Manager, brokerManager *brokers.Manager, args ...Option) (b *Bridge, err error) {
+	defer decorate.OnError(&err, "can't export D-Bus interface")
+
+	opts := options{busName: consts.ServiceName}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	// Don't call dbus.SystemBus which caches globally the system bus connection.
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = conn.Close()
+		}
+	}()
+
+	b = &Bridge{conn: conn, userManager: userManager, brokerManager: brokerManager}
+	if err := conn.Export(b, ObjectPath, Interface); err != nil {
+		return nil, err
+	}
+	node := &introspect.Node{
+		Name: ObjectPath,
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			{
+				Name:    Interface,
+				Methods: introspect.Methods(b),
+				Signals: []introspect.Signal{
+					{Name: "UserAdded", Args: []introspect.Arg{{Name: "name", Type: "s"}}},
+					{Name: "UserChanged", Args: []introspect.Arg{{Name: "name", Type: "s"}}},
+				},
+			},
+		},
+	}
+	if err := conn.Export(introspect.NewIntrospectable(node), ObjectPath, introspect.IntrospectData.Name); err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.RequestName(opts.busName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return nil, err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return nil, fmt.Errorf("name %q is already owned on the system bus", opts.busName)
+	}
+
+	userManager.OnUserUpdated(b.emitUserUpdated)
+
+	log.Infof(context.Background(), "Exported D-Bus interface %s as %s", Interface, opts.busName)
+	return b, nil
+}
+
+// Stop releases the name of authd and closes the connection to the system bus.
+func (b *Bridge) Stop() {
+	if err := b.conn.Close(); err != nil {
+		log.Warningf(context.Background(), "Could not close the system bus connection of the D-Bus interface: %v", err)
+	}
+}
+
+// ListUsers returns all the users stored in the database.
+func (b *Bridge) ListUsers() ([]User, *dbus.Error) {
+	entries, err := b.userManager.AllUsers()
+	if err != nil {
+		return nil, dbusError(err)
+	}
+
+	r := make([]User, 0, len(entries))
+	for _, u := range entries {
+		r = append(r, userFromEntry(u))
+	}
+	return r, nil
+}
+
+// FindUserByName returns the user with the given name.
+func (b *Bridge) FindUserByName(name string) (User, *dbus.Error) {
+	u, err := b.userManager.UserByName(name)
+	if err != nil {
+		return User{}, dbusError(err)
+	}
+	return userFromEntry(u), nil
+}
+
+// FindUserByID returns the user with the given UID.
+func (b *Bridge) FindUserByID(uid uint32) (User, *dbus.Error) {
+	u, err := b.userManager.UserByID(uid)
+	if err != nil {
+		return User{}, dbusError(err)
+	}
+	return userFromEntry(u), nil
+}
+
+// ListBrokers returns the brokers loaded by the daemon, in preference order.
+func (b *Bridge) ListBrokers() ([]Broker, *dbus.Error) {
+	var r []Broker
+	for _, broker := range b.brokerManager.AvailableBrokers() {
+		r = append(r, Broker{ID: broker.ID, Name: broker.Name, BrandIcon: broker.BrandIconPath})
+	}
+	return r, nil
+}
+
+// GetUserBroker returns the broker selected by the user, which is used for their next authentications.
+func (b *Bridge) GetUserBroker(username string) (Broker, *dbus.Error) {
+	brokerID := ""
+	if broker := b.brokerManager.BrokerForUser(username); broker != nil {
+		brokerID = broker.ID
+	} else {
+		var err error
+		if brokerID, err = b.userManager.BrokerForUser(username); err != nil {
+			return Broker{}, dbusError(err)
+		}
+	}
+
+	for _, broker := range b.brokerManager.AvailableBrokers() {
+		if broker.ID == brokerID {
+			return Broker{ID: broker.ID, Name: broker.Name, BrandIcon: broker.BrandIconPath}, nil
+		}
+	}
+	return Broker{}, dbus.NewError(ErrNotFound, []any{fmt.Sprintf("user %q has no broker selected", username)})
+}
+
+// emitUserUpdated emits the signal notifying that the user was added or changed.
+func (b *Bridge) emitUserUpdated(name string, isNew bool) {
+	signal := Interface + ".UserChanged"
+	if isNew {
+		signal = Interface + ".UserAdded"
+	}
+	if err := b.conn.Emit(ObjectPath, signal, name); err != nil {
+		log.Warningf(context.Background(), "Could not emit D-Bus signal %s for user %q: %v", signal, name, err)
+	}
+}
+
+func userFromEntry(u types.UserEntry) User {
+	return User{Name: u.Name, UID: u.UID, GID: u.GID, Gecos: u.Gecos, Dir: u.Dir, Shell: u.Shell}
+}
+
+// dbusError returns the D-Bus error matching the error of the managers.
+func dbusError(err error) *dbus.Error {
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return dbus.NewError(ErrNotFound, []any{err.Error()})
+	}
+	return dbus.NewError(ErrFailed, []any{err.Error()})
+}
@@ -0,0 +1,260 @@
+package dbusbridge_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/services/dbusbridge"
+	"github.com/ubuntu/authd/internal/testutils"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/gecos"
+	"github.com/ubuntu/authd/internal/users/idgenerator"
+	"github.com/ubuntu/authd/internal/users/types"
+	"github.com/ubuntu/authd/log"
+)
+
+var brokerManager *brokers.Manager
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	m := newUserManager(t)
+	busName := busNameForTest(t)
+
+	b, err := dbusbridge.Export(m, brokerManager, dbusbridge.WithBusName(busName))
+	require.NoError(t, err, "Export should not return an error, but did")
+	defer b.Stop()
+
+	_, err = dbusbridge.Export(m, brokerManager, dbusbridge.WithBusName(busName))
+	require.Error(t, err, "Export should return an error when the name is already owned, but did not")
+}
+
+func TestListUsers(t *testing.T) {
+	t.Parallel()
+
+	obj, _ := newBridge(t)
+
+	var got []dbusbridge.User
+	err := obj.Call(dbusbridge.Interface+".ListUsers", 0).Store(&got)
+	require.NoError(t, err, "ListUsers should not return an error, but did")
+	require.Equal(t, []dbusbridge.User{
+		{Name: "user1", UID: 1111, GID: 11111, Gecos: "User1", Dir: "/home/user1", Shell: "/bin/bash"},
+		{Name: "user2", UID: 2222, GID: 22222, Gecos: "User2", Dir: "/home/user2", Shell: "/bin/dash"},
+		{Name: "userwithoutbroker", UID: 3333, GID: 33333, Gecos: "userwithoutbroker", Dir: "/home/userwithoutbroker", Shell: "/bin/sh"},
+	}, got, "ListUsers returned unexpected users")
+}
+
+func TestFindUser(t *testing.T) {
+	t.Parallel()
+
+	user1 := dbusbridge.User{Name: "user1", UID: 1111, GID: 11111, Gecos: "User1", Dir: "/home/user1", Shell: "/bin/bash"}
+
+	tests := map[string]struct {
+		method string
+		arg    any
+
+		want        dbusbridge.User
+		wantErrName string
+	}{
+		"Find_user_by_name": {method: "FindUserByName", arg: "user1", want: user1},
+		"Find_user_by_ID":   {method: "FindUserByID", arg: uint32(1111), want: user1},
+
+		"Error_when_no_user_has_the_name": {method: "FindUserByName", arg: "doesnotexist", wantErrName: dbusbridge.ErrNotFound},
+		"Error_when_no_user_has_the_ID":   {method: "FindUserByID", arg: uint32(4242), wantErrName: dbusbridge.ErrNotFound},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			obj, _ := newBridge(t)
+
+			var got dbusbridge.User
+			err := obj.Call(dbusbridge.Interface+"."+tc.method, 0, tc.arg).Store(&got)
+			if tc.wantErrName != "" {
+				requireDBusError(t, err, tc.wantErrName)
+				return
+			}
+			require.NoError(t, err, "%s should not return an error, but did", tc.method)
+			require.Equal(t, tc.want, got, "%s returned an unexpected user", tc.method)
+		})
+	}
+}
+
+func TestListBrokers(t *testing.T) {
+	t.Parallel()
+
+	obj, _ := newBridge(t)
+
+	var got []dbusbridge.Broker
+	err := obj.Call(dbusbridge.Interface+".ListBrokers", 0).Store(&got)
+	require.NoError(t, err, "ListBrokers should not return an error, but did")
+
+	var names []string
+	for _, b := range got {
+		names = append(names, b.Name)
+	}
+	require.Equal(t, []string{brokers.LocalBrokerName, "BrokerMock"}, names, "ListBrokers returned unexpected brokers")
+}
+
+func TestGetUserBroker(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username string
+
+		want        string
+		wantErrName string
+	}{
+		"Get_broker_of_user": {username: "user1", want: brokers.LocalBrokerName},
+
+		"Error_when_user_does_not_exist":        {username: "doesnotexist", wantErrName: dbusbridge.ErrNotFound},
+		"Error_when_user_has_no_broker":         {username: "userwithoutbroker", wantErrName: dbusbridge.ErrNotFound},
+		"Error_when_broker_of_user_is_not_used": {username: "user2", wantErrName: dbusbridge.ErrNotFound},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			obj, _ := newBridge(t)
+
+			var got dbusbridge.Broker
+			err := obj.Call(dbusbridge.Interface+".GetUserBroker", 0, tc.username).Store(&got)
+			if tc.wantErrName != "" {
+				requireDBusError(t, err, tc.wantErrName)
+				return
+			}
+			require.NoError(t, err, "GetUserBroker should not return an error, but did")
+			require.Equal(t, tc.want, got.Name, "GetUserBroker returned an unexpected broker")
+		})
+	}
+}
+
+func TestUserSignals(t *testing.T) {
+	t.Parallel()
+
+	obj, m := newBridge(t)
+
+	conn, err := testutils.GetSystemBusConnection(t)
+	require.NoError(t, err, "Setup: could not connect to the system bus")
+	t.Cleanup(func() { conn.Close() })
+	err = conn.AddMatchSignal(dbus.WithMatchSender(obj.Destination()), dbus.WithMatchInterface(dbusbridge.Interface))
+	require.NoError(t, err, "Setup: could not subscribe to the signals")
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+
+	fullName := "Jane Doe"
+	err = m.UpdateGecos("user1", gecos.Update{FullName: &fullName})
+	require.NoError(t, err, "Setup: UpdateGecos should not return an error, but did")
+	requireSignal(t, signals, dbusbridge.Interface+".UserChanged", "user1")
+
+	err = m.UpdateUser(context.Background(), types.UserInfo{Name: "newuser", Dir: "/home/newuser", Shell: "/bin/bash"}, "")
+	require.NoError(t, err, "Setup: UpdateUser should not return an error, but did")
+	requireSignal(t, signals, dbusbridge.Interface+".UserAdded", "newuser")
+}
+
+// newBridge exports the bridge with a name unique to the test, and returns the object to call its methods with the
+// user manager it uses.
+func newBridge(t *testing.T) (dbus.BusObject, *users.Manager) {
+	t.Helper()
+
+	m := newUserManager(t)
+	busName := busNameForTest(t)
+
+	b, err := dbusbridge.Export(m, brokerManager, dbusbridge.WithBusName(busName))
+	require.NoError(t, err, "Setup: Export should not return an error, but did")
+	t.Cleanup(b.Stop)
+
+	conn, err := testutils.GetSystemBusConnection(t)
+	require.NoError(t, err, "Setup: could not connect to the system bus")
+	t.Cleanup(func() { conn.Close() })
+
+	return conn.Object(busName, dbusbridge.ObjectPath), m
+}
+
+// newUserManager returns a user manager with the users of the test database.
+func newUserManager(t *testing.T) *users.Manager {
+	t.Helper()
+
+	dbDir := t.TempDir()
+	err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "users.db.yaml"), dbDir)
+	require.NoError(t, err, "Setup: could not create database from testdata")
+
+	m, err := users.NewManager(users.DefaultConfig, dbDir, users.WithIDGenerator(&idgenerator.IDGeneratorMock{
+		UIDsToGenerate: []uint32{4444},
+		GIDsToGenerate: []uint32{44444},
+	}))
+	require.NoError(t, err, "Setup: could not create user manager")
+	t.Cleanup(func() { _ = m.Stop() })
+
+	return m
+}
+
+// busNameForTest returns a bus name unique to the test.
+func busNameForTest(t *testing.T) string {
+	t.Helper()
+
+	return "com.ubuntu.authd.Test." + strings.ReplaceAll(t.Name(), "/", ".")
+}
+
+func requireDBusError(t *testing.T, err error, name string) {
+	t.Helper()
+
+	var dbusErr dbus.Error
+	require.True(t, errors.As(err, &dbusErr), "Call should return a D-Bus error, got %v", err)
+	require.Equal(t, name, dbusErr.Name, "Call returned an unexpected D-Bus error: %v", err)
+}
+
+func requireSignal(t *testing.T, signals <-chan *dbus.Signal, name, username string) {
+	t.Helper()
+
+	select {
+	case s := <-signals:
+		require.Equal(t, name, s.Name, "Unexpected signal emitted")
+		require.Equal(t, []any{username}, s.Body, "Signal emitted for an unexpected user")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Signal was not emitted", "expected signal %s", name)
+	}
+}
+
+func TestMain(m *testing.M) {
+	log.SetLevel(log.DebugLevel)
+
+	cleanup, err := testutils.StartSystemBusMock()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer cleanup()
+
+	brokersDir, err := os.MkdirTemp("", "authd-dbusbridge-tests-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(brokersDir)
+
+	_, brokerCleanup, err := testutils.StartBusBrokerMock(brokersDir, "BrokerMock")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer brokerCleanup()
+
+	brokerManager, err = brokers.NewManager(context.Background(), brokersDir, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	m.Run()
+}
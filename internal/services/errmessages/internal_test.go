@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/authderrors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -18,16 +19,43 @@ func TestRedactErrorInterceptor(t *testing.T) {
 	tests := map[string]struct {
 		inputError error
 
-		wantMessage string
+		wantMessage  string
+		wantCode     authderrors.Code
+		wantSentinel *authderrors.Sentinel
 	}{
 		"Trim_input_down_to_ErrToDisplay": {
 			inputError:  fmt.Errorf("Error to be redacted: %w", ToDisplayError{errors.New("Error to be shown")}),
 			wantMessage: "Error to be shown",
 		},
+		"Trim_input_down_to_ErrToDisplay_keeping_its_classification": {
+			inputError: authderrors.Wrap(authderrors.Unavailable,
+				fmt.Errorf("Error to be redacted: %w", ToDisplayError{errors.New("Error to be shown")})),
+			wantMessage: "Error to be shown",
+			wantCode:    authderrors.Unavailable,
+		},
+		"Trim_input_down_to_ErrToDisplay_keeping_its_sentinel": {
+			inputError: authderrors.ErrBrokerUnavailable.Wrap(
+				fmt.Errorf("Error to be redacted: %w", ToDisplayError{errors.New("Error to be shown")})),
+			wantMessage:  "Error to be shown",
+			wantCode:     authderrors.Unavailable,
+			wantSentinel: authderrors.ErrBrokerUnavailable,
+		},
 		"Return_original_error": {
 			inputError:  errors.New("Not a redacted error"),
 			wantMessage: "Not a redacted error",
 		},
+		"Return_original_error_classified_by_itself": {
+			inputError:   fmt.Errorf("Not a redacted error: %w", selfClassifiedError{}),
+			wantMessage:  "Not a redacted error: self classified error",
+			wantCode:     authderrors.NotFound,
+			wantSentinel: authderrors.ErrNotFound,
+		},
+		"Return_original_error_keeping_its_sentinel": {
+			inputError:   fmt.Errorf("Not a redacted error: %w", authderrors.ErrCorrupted.Errorf("bad data")),
+			wantMessage:  "Not a redacted error: bad data",
+			wantCode:     authderrors.Internal,
+			wantSentinel: authderrors.ErrCorrupted,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -36,6 +64,11 @@ func TestRedactErrorInterceptor(t *testing.T) {
 			_, err := RedactErrorInterceptor(context.TODO(), testRequest{tc.inputError}, nil, testHandler)
 			require.Error(t, err, "RedactErrorInterceptor should return an error")
 			require.Equal(t, tc.wantMessage, err.Error(), "RedactErrorInterceptor returned unexpected error message")
+			require.Equal(t, tc.wantCode, authderrors.CodeOf(err), "RedactErrorInterceptor returned an error with an unexpected code")
+			require.Equal(t, tc.wantSentinel, authderrors.SentinelOf(err), "RedactErrorInterceptor returned an error matching an unexpected sentinel")
+			if tc.wantCode != authderrors.Unknown {
+				require.Equal(t, authderrors.ToGRPCCode(tc.wantCode), status.Code(err), "RedactErrorInterceptor returned an error with an unexpected gRPC code")
+			}
 		})
 	}
 }
@@ -46,7 +79,9 @@ func TestFormatErrorMessage(t *testing.T) {
 	tests := map[string]struct {
 		inputError error
 
-		wantMessage string
+		wantMessage  string
+		wantCode     authderrors.Code
+		wantSentinel *authderrors.Sentinel
 	}{
 		"Non-gRPC_error_is_left_untouched": {
 			inputError:  errors.New("Non-gRPC error"),
@@ -59,20 +94,51 @@ func TestFormatErrorMessage(t *testing.T) {
 		"Code_Canceled_is_left_untouched": {
 			inputError:  status.Error(codes.Canceled, "Canceled error"),
 			wantMessage: "rpc error: code = Canceled desc = Canceled error",
+			wantCode:    authderrors.Canceled,
+		},
+		"Code_ResourceExhausted_is_left_untouched": {
+			inputError:  status.Error(codes.ResourceExhausted, "ResourceExhausted error"),
+			wantMessage: "rpc error: code = ResourceExhausted desc = ResourceExhausted error",
+			wantCode:    authderrors.ResourceExhausted,
 		},
 
 		"Parse_code_Unavailable": {
 			inputError:  status.Error(codes.Unavailable, "Unavailable error"),
 			wantMessage: "couldn't connect to authd daemon: Unavailable error",
+			wantCode:    authderrors.Unavailable,
+		},
+		"Code_Unavailable_from_authd_is_left_untouched": {
+			inputError:  status.Convert(authderrors.New(authderrors.Unavailable, "Unavailable broker")).Err(),
+			wantMessage: "Unavailable broker",
+			wantCode:    authderrors.Unavailable,
 		},
 		"Parse_code_DeadlineExceeded": {
 			inputError:  status.Error(codes.DeadlineExceeded, "DeadlineExceeded error"),
 			wantMessage: "service took too long to respond. Disconnecting client",
+			wantCode:    authderrors.Timeout,
 		},
 		"Parse_code_Unknown": {
 			inputError:  status.Error(codes.Unknown, "Unknown error"),
 			wantMessage: "Unknown error",
 		},
+		"Parse_code_NotFound": {
+			inputError:   status.Error(codes.NotFound, "NotFound error"),
+			wantMessage:  "error NotFound from server: NotFound error",
+			wantCode:     authderrors.NotFound,
+			wantSentinel: authderrors.ErrNotFound,
+		},
+		"Keep_sentinel_sent_by_authd": {
+			inputError:   status.Convert(authderrors.ErrBrokerUnavailable.Errorf("Unavailable broker")).Err(),
+			wantMessage:  "Unavailable broker",
+			wantCode:     authderrors.Unavailable,
+			wantSentinel: authderrors.ErrBrokerUnavailable,
+		},
+		"Keep_sentinel_with_another_code_sent_by_authd": {
+			inputError:   status.Convert(authderrors.ErrDisabled.Errorf("Disabled feature")).Err(),
+			wantMessage:  "error PermissionDenied from server: Disabled feature",
+			wantCode:     authderrors.PermissionDenied,
+			wantSentinel: authderrors.ErrDisabled,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -81,10 +147,17 @@ func TestFormatErrorMessage(t *testing.T) {
 			err := FormatErrorMessage(context.TODO(), "", testRequest{tc.inputError}, nil, nil, testInvoker)
 			require.Error(t, err, "FormatErrorMessage should return an error")
 			require.Equal(t, tc.wantMessage, err.Error(), "FormatErrorMessage returned unexpected error message")
+			require.Equal(t, tc.wantCode, authderrors.CodeOf(err), "FormatErrorMessage returned an error with an unexpected code")
+			require.Equal(t, tc.wantSentinel, authderrors.SentinelOf(err), "FormatErrorMessage returned an error matching an unexpected sentinel")
 		})
 	}
 }
 
+type selfClassifiedError struct{}
+
+func (selfClassifiedError) Error() string          { return "self classified error" }
+func (selfClassifiedError) Code() authderrors.Code { return authderrors.NotFound }
+
 type testRequest struct {
 	err error
 }
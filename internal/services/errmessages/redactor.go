@@ -4,8 +4,8 @@ package errmessages
 import (
 	"context"
 	"errors"
-	"fmt"
 
+	"github.com/ubuntu/authd/internal/authderrors"
 	"github.com/ubuntu/authd/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -15,22 +15,33 @@ import (
 // RedactErrorInterceptor redacts some of the attached errors before sending it to the client.
 //
 // It unwraps the error up to the first ErrToDisplay and sends it to the client. If none is found, it sends the original error.
+// In both cases, the error is sent with the gRPC status code of its classification and the sentinel error it matches,
+// so that the client can act on it.
 func RedactErrorInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	m, err := handler(ctx, req)
-	if err != nil {
-		log.Warning(context.TODO(), err.Error())
-		var redactedError ToDisplayError
-		if !errors.As(err, &redactedError) {
-			return m, err
-		}
-		return m, redactedError
+	if err == nil {
+		return m, nil
+	}
+
+	log.Warning(context.TODO(), err.Error())
+	sent := err
+	var redactedError ToDisplayError
+	if errors.As(err, &redactedError) {
+		sent = redactedError
+	}
+
+	if s := authderrors.SentinelOf(err); s != nil {
+		return m, s.Wrap(sent)
 	}
-	return m, nil
+	if code := authderrors.CodeOf(err); code != authderrors.Unknown {
+		return m, authderrors.Wrap(code, sent)
+	}
+	return m, sent
 }
 
 // FormatErrorMessage formats the error message received by the client to avoid printing useless information.
 //
-// It converts the gRPC error to a more human-readable error with a better message.
+// It converts the gRPC error to a more human-readable error with a better message, keeping its classification.
 func FormatErrorMessage(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 	err := invoker(ctx, method, req, reply, cc, opts...)
 	if err == nil {
@@ -42,21 +53,34 @@ func FormatErrorMessage(ctx context.Context, method string, req, reply any, cc *
 	}
 
 	switch st.Code() {
-	// no daemon
 	case codes.Unavailable:
-		err = fmt.Errorf("couldn't connect to authd daemon: %v", st.Message())
+		// a service the daemon relies on (like a broker) is temporarily unavailable
+		if authderrors.IsFromAuthd(st) {
+			err = authderrors.New(authderrors.Unavailable, st.Message())
+			break
+		}
+		// no daemon
+		err = authderrors.Errorf(authderrors.Unavailable, "couldn't connect to authd daemon: %v", st.Message())
 	// timeout
 	case codes.DeadlineExceeded:
-		err = errors.New("service took too long to respond. Disconnecting client")
+		err = authderrors.New(authderrors.Timeout, "service took too long to respond. Disconnecting client")
 	// regular error without annotation
 	case codes.Unknown:
-		err = errors.New(st.Message())
+		err = authderrors.New(authderrors.Unknown, st.Message())
 	// likely means that IsAuthenticated got cancelled, so we need to keep the error intact
 	case codes.Canceled:
 		break
+	// the request can be retried later, so we need to keep the error intact
+	case codes.ResourceExhausted:
+		break
 	// grpc error, just format it
 	default:
-		err = fmt.Errorf("error %s from server: %v", st.Code(), st.Message())
+		err = authderrors.Errorf(authderrors.FromGRPCCode(st.Code()), "error %s from server: %v", st.Code(), st.Message())
+	}
+
+	// Keep the sentinel error the daemon attached to the error, so that the callers don't need to match the message.
+	if s := authderrors.SentinelFromStatus(st); s != nil && !errors.Is(err, s) {
+		err = s.Wrap(err)
 	}
 	return err
 }
@@ -7,10 +7,13 @@ import (
 	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/consts"
 	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/dbusbridge"
 	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/internal/services/nss"
 	"github.com/ubuntu/authd/internal/services/pam"
 	"github.com/ubuntu/authd/internal/services/permissions"
+	"github.com/ubuntu/authd/internal/services/rpclimits"
+	"github.com/ubuntu/authd/internal/tracing"
 	"github.com/ubuntu/authd/internal/users"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
@@ -26,15 +29,21 @@ type Manager struct {
 	brokerManager *brokers.Manager
 	pamService    pam.Service
 	nssService    nss.Service
+	rpcLimiter    *rpclimits.Limiter
 }
 
 // NewManager returns a new manager after creating all necessary items for our business logic.
-func NewManager(ctx context.Context, dbDir, brokersConfPath string, configuredBrokers []string, usersConfig users.Config) (m Manager, err error) {
+func NewManager(ctx context.Context, dbDir, brokersConfPath string, configuredBrokers []string, brokerOpts []brokers.Option, usersConfig users.Config, rpcLimits rpclimits.Config, pamOpts ...pam.Option) (m Manager, err error) {
 	defer decorate.OnError(&err /*i18n.G(*/, "can't create authd object") //)
 
 	log.Debug(ctx, "Building authd object")
 
-	brokerManager, err := brokers.NewManager(ctx, brokersConfPath, configuredBrokers)
+	rpcLimiter, err := rpclimits.New(rpcLimits)
+	if err != nil {
+		return m, err
+	}
+
+	brokerManager, err := brokers.NewManager(ctx, brokersConfPath, configuredBrokers, brokerOpts...)
 	if err != nil {
 		return m, err
 	}
@@ -47,13 +56,14 @@ func NewManager(ctx context.Context, dbDir, brokersConfPath string, configuredBr
 	permissionManager := permissions.New()
 
 	nssService := nss.NewService(ctx, userManager, brokerManager, &permissionManager)
-	pamService := pam.NewService(ctx, userManager, brokerManager, &permissionManager)
+	pamService := pam.NewService(ctx, userManager, brokerManager, &permissionManager, pamOpts...)
 
 	return Manager{
 		userManager:   userManager,
 		brokerManager: brokerManager,
 		nssService:    nssService,
 		pamService:    pamService,
+		rpcLimiter:    rpcLimiter,
 	}, nil
 }
 
@@ -61,7 +71,8 @@ func NewManager(ctx context.Context, dbDir, brokersConfPath string, configuredBr
 func (m Manager) RegisterGRPCServices(ctx context.Context) *grpc.Server {
 	log.Debug(ctx, "Registering gRPC services")
 
-	opts := []grpc.ServerOption{permissions.WithUnixPeerCreds(), grpc.ChainUnaryInterceptor(m.globalPermissions, errmessages.RedactErrorInterceptor)}
+	opts := []grpc.ServerOption{permissions.WithUnixPeerCreds(), grpc.ChainUnaryInterceptor(
+		tracing.UnaryServerInterceptor, m.rpcLimiter.UnaryInterceptor, m.globalPermissions, errmessages.RedactErrorInterceptor)}
 	grpcServer := grpc.NewServer(opts...)
 
 	healthCheck := health.NewServer()
@@ -78,6 +89,43 @@ func (m Manager) RegisterGRPCServices(ctx context.Context) *grpc.Server {
 	return grpcServer
 }
 
+// RPCMetrics returns the counters of the gRPC requests handled by the daemon since it started.
+func (m Manager) RPCMetrics() []rpclimits.Metrics {
+	return m.rpcLimiter.Metrics()
+}
+
+// BruteForceMetrics returns the counters of the failed authentications since the daemon started.
+func (m Manager) BruteForceMetrics() pam.BruteForceMetrics {
+	return m.pamService.BruteForceMetrics()
+}
+
+// Busy returns whether the daemon must not quit when idle, as it would lose some state.
+func (m Manager) Busy() bool {
+	return m.pamService.Busy()
+}
+
+// ExportDBusBridge exports on the system bus the D-Bus interface mirroring the gRPC services, and returns the function
+// to stop it. Failing to export it doesn't prevent the daemon from serving the gRPC requests.
+func (m Manager) ExportDBusBridge(ctx context.Context, args ...dbusbridge.Option) (stop func()) {
+	b, err := dbusbridge.Export(m.userManager, m.brokerManager, args...)
+	if err != nil {
+		log.Warningf(ctx, "The D-Bus interface is not available: %v", err)
+		return func() {}
+	}
+	return b.Stop
+}
+
+// CheckHealth returns an error if the daemon can't serve the requests, and blocks while the database is locked.
+func (m Manager) CheckHealth(ctx context.Context) error {
+	return m.userManager.CheckHealth()
+}
+
+// Shutdown stops accepting new sessions, waits for the in-flight authentications to complete until the context is
+// done and ends all the remaining broker sessions.
+func (m Manager) Shutdown(ctx context.Context) {
+	m.pamService.Shutdown(ctx)
+}
+
 // stop stops the underlying database.
 func (m *Manager) stop() error {
 	log.Debug(context.TODO(), "Closing gRPC manager and database")
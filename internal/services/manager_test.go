@@ -3,6 +3,7 @@ package services_test
 import (
 	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/internal/services"
 	"github.com/ubuntu/authd/internal/services/errmessages"
+	"github.com/ubuntu/authd/internal/services/rpclimits"
 	"github.com/ubuntu/authd/internal/testutils"
 	"github.com/ubuntu/authd/internal/testutils/golden"
 	"github.com/ubuntu/authd/internal/users"
@@ -43,7 +45,7 @@ func TestNewManager(t *testing.T) {
 				t.Setenv("DBUS_SYSTEM_BUS_ADDRESS", tc.systemBusSocket)
 			}
 
-			m, err := services.NewManager(context.Background(), tc.dbDir, t.TempDir(), nil, users.DefaultConfig)
+			m, err := services.NewManager(context.Background(), tc.dbDir, t.TempDir(), nil, nil, users.DefaultConfig, rpclimits.Config{})
 			if tc.wantErr {
 				require.Error(t, err, "NewManager should have returned an error, but did not")
 				return
@@ -58,7 +60,7 @@ func TestNewManager(t *testing.T) {
 func TestRegisterGRPCServices(t *testing.T) {
 	t.Parallel()
 
-	m, err := services.NewManager(context.Background(), t.TempDir(), t.TempDir(), nil, users.DefaultConfig)
+	m, err := services.NewManager(context.Background(), t.TempDir(), t.TempDir(), nil, nil, users.DefaultConfig, rpclimits.Config{})
 	require.NoError(t, err, "Setup: could not create manager for the test")
 	defer require.NoError(t, m.Stop(), "Teardown: Stop should not have returned an error, but did")
 
@@ -75,7 +77,7 @@ func TestRegisterGRPCServices(t *testing.T) {
 func TestAccessAuthorization(t *testing.T) {
 	t.Parallel()
 
-	m, err := services.NewManager(context.Background(), t.TempDir(), t.TempDir(), nil, users.DefaultConfig)
+	m, err := services.NewManager(context.Background(), t.TempDir(), t.TempDir(), nil, nil, users.DefaultConfig, rpclimits.Config{})
 	require.NoError(t, err, "Setup: could not create manager for the test")
 	defer require.NoError(t, m.Stop(), "Teardown: Stop should not have returned an error, but did")
 
@@ -105,6 +107,10 @@ func TestAccessAuthorization(t *testing.T) {
 	_, err = pamClient.AvailableBrokers(context.Background(), &authd.Empty{})
 	require.Error(t, err, "PAM calls are not allowed to any random user")
 
+	// The daemon version is available to any user.
+	_, err = pamClient.GetVersion(context.Background(), &authd.Empty{})
+	require.NoError(t, err, "GetVersion should be allowed to any user")
+
 	// Global authorization for NSS is always granted for non root user.
 	nssClient := authd.NewNSSClient(conn)
 	_, err = nssClient.GetPasswdByName(context.Background(), &authd.GetPasswdByNameRequest{Name: ""})
@@ -114,6 +120,31 @@ func TestAccessAuthorization(t *testing.T) {
 	require.NoError(t, err, "Teardown: could not close the client connection")
 }
 
+func TestWriteStateDump(t *testing.T) {
+	t.Parallel()
+
+	m, err := services.NewManager(context.Background(), t.TempDir(), t.TempDir(), nil, nil, users.DefaultConfig, rpclimits.Config{})
+	require.NoError(t, err, "Setup: could not create manager for the test")
+	t.Cleanup(func() { require.NoError(t, m.Stop(), "Teardown: Stop should not have returned an error, but did") })
+
+	dir := filepath.Join(t.TempDir(), "dumps")
+	path, err := m.WriteStateDump(context.Background(), dir)
+	require.NoError(t, err, "WriteStateDump should not have returned an error, but did")
+	require.Equal(t, dir, filepath.Dir(path), "The state dump should be written in the given directory")
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err, "The state dump should be written")
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm(), "The state dump should only be readable by its owner")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "Setup: could not read the state dump")
+	var got services.StateDump
+	require.NoError(t, json.Unmarshal(data, &got), "The state dump should be valid JSON")
+	require.NotEmpty(t, got.Brokers, "The state dump should list the brokers")
+	require.Empty(t, got.Cache.Error, "The state dump should have the statistics of the cache")
+	require.Contains(t, got.Goroutines, "goroutine", "The state dump should have the stack traces of the goroutines")
+}
+
 func TestMain(m *testing.M) {
 	// Start system bus mock.
 	cleanup, err := testutils.StartSystemBusMock()
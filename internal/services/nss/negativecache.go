@@ -0,0 +1,83 @@
+package nss
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// negativeCacheTTL is how long a user which was not found is remembered as not existing.
+const negativeCacheTTL = 5 * time.Second
+
+// negativeCache remembers for a short time the users which were not found, to avoid querying the database again when
+// they are looked up repeatedly (for example by sudo, cron or systemd).
+type negativeCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	entries map[string]time.Time
+	// generation is incremented on each invalidation, so that a lookup which started before a user was created
+	// doesn't add that user to the cache.
+	generation uint64
+	mu         sync.Mutex
+}
+
+func newNegativeCache(ttl time.Duration, now func() time.Time) *negativeCache {
+	return &negativeCache{
+		ttl:     ttl,
+		now:     now,
+		entries: make(map[string]time.Time),
+	}
+}
+
+// Keys of the negative cache, there are distinct ones for the shadow entries as temporary users only have a passwd
+// entry.
+func passwdNameKey(name string) string { return "passwd-name:" + name }
+func passwdUIDKey(uid uint32) string   { return fmt.Sprintf("passwd-uid:%d", uid) }
+func shadowNameKey(name string) string { return "shadow-name:" + name }
+
+// currentGeneration returns the generation to pass to add for a lookup starting now.
+func (c *negativeCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// add remembers that the key was not found by a lookup which started at the given generation.
+func (c *negativeCache) add(key string, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	now := c.now()
+	// Purge the expired entries, so that the cache doesn't grow unbounded.
+	for k, expiration := range c.entries {
+		if !now.Before(expiration) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = now.Add(c.ttl)
+}
+
+// contains returns true if the key was recently not found.
+func (c *negativeCache) contains(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiration, ok := c.entries[key]
+	return ok && c.now().Before(expiration)
+}
+
+// invalidateUser forgets that the user was not found, once it's created.
+func (c *negativeCache) invalidateUser(name string, uid uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	delete(c.entries, passwdNameKey(name))
+	delete(c.entries, passwdUIDKey(uid))
+	delete(c.entries, shadowNameKey(name))
+}
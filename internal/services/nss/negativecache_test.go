@@ -0,0 +1,47 @@
+package nss
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegativeCache(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		elapsed         time.Duration
+		invalidate      bool
+		invalidateFirst bool
+
+		want bool
+	}{
+		"Contains_recently_added_key": {want: true},
+
+		"Does_not_contain_expired_key":                   {elapsed: negativeCacheTTL},
+		"Does_not_contain_key_of_created_user":           {invalidate: true},
+		"Does_not_add_key_of_user_created_during_lookup": {invalidateFirst: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Now()
+			c := newNegativeCache(negativeCacheTTL, func() time.Time { return now })
+
+			generation := c.currentGeneration()
+			if tc.invalidateFirst {
+				c.invalidateUser("user1", 1111)
+			}
+			c.add(passwdNameKey("user1"), generation)
+			if tc.invalidate {
+				c.invalidateUser("user1", 1111)
+			}
+			now = now.Add(tc.elapsed)
+
+			require.Equal(t, tc.want, c.contains(passwdNameKey("user1")), "Negative cache should contain the key only if not expired nor invalidated")
+			require.False(t, c.contains(passwdUIDKey(1111)), "Negative cache should not contain keys which were not added")
+		})
+	}
+}
@@ -7,15 +7,15 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
+	"github.com/ubuntu/authd/internal/authderrors"
 	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/internal/services/permissions"
 	"github.com/ubuntu/authd/internal/users"
 	"github.com/ubuntu/authd/internal/users/types"
 	"github.com/ubuntu/authd/log"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 // Service is the implementation of the NSS module service.
@@ -24,6 +24,8 @@ type Service struct {
 	brokerManager     *brokers.Manager
 	permissionManager *permissions.Manager
 
+	negativeCache *negativeCache
+
 	authd.UnimplementedNSSServer
 }
 
@@ -31,23 +33,42 @@ type Service struct {
 func NewService(ctx context.Context, userManager *users.Manager, brokerManager *brokers.Manager, permissionManager *permissions.Manager) Service {
 	log.Debug(ctx, "Building new gRPC NSS service")
 
+	negativeCache := newNegativeCache(negativeCacheTTL, time.Now)
+	userManager.OnNewUser(negativeCache.invalidateUser)
+
 	return Service{
 		userManager:       userManager,
 		brokerManager:     brokerManager,
 		permissionManager: permissionManager,
+		negativeCache:     negativeCache,
 	}
 }
 
 // GetPasswdByName returns the passwd entry for the given username.
 func (s Service) GetPasswdByName(ctx context.Context, req *authd.GetPasswdByNameRequest) (*authd.PasswdEntry, error) {
 	if req.GetName() == "" {
-		return nil, status.Error(codes.InvalidArgument, "no user name provided")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name provided")
+	}
+
+	// Avoid querying the database and the brokers for users which clearly can't be handled by any broker.
+	if !s.brokerManager.MayOwnUser(req.GetName()) {
+		return nil, authderrors.New(authderrors.NotFound, "")
+	}
+
+	// The pre-check asks the brokers, so it's not affected by the users which were not found in the database.
+	key := passwdNameKey(req.GetName())
+	if !req.GetShouldPreCheck() && s.negativeCache.contains(key) {
+		return nil, authderrors.New(authderrors.NotFound, "")
 	}
 
+	generation := s.negativeCache.currentGeneration()
 	u, err := s.userManager.UserByName(req.GetName())
 	if err == nil {
 		return nssPasswdFromUsersPasswd(u), nil
 	}
+	if errors.Is(err, users.NoDataFoundError{}) {
+		s.negativeCache.add(key, generation)
+	}
 
 	if !errors.Is(err, users.NoDataFoundError{}) || !req.GetShouldPreCheck() {
 		return nil, noDataFoundErrorToGRPCError(err)
@@ -56,7 +77,7 @@ func (s Service) GetPasswdByName(ctx context.Context, req *authd.GetPasswdByName
 	// If the user is not found in the database, we check if it exists in at least one broker.
 	pwent, err := s.userPreCheck(ctx, req.GetName())
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, authderrors.Wrap(authderrors.NotFound, err)
 	}
 
 	return pwent, nil
@@ -64,7 +85,16 @@ func (s Service) GetPasswdByName(ctx context.Context, req *authd.GetPasswdByName
 
 // GetPasswdByUID returns the passwd entry for the given UID.
 func (s Service) GetPasswdByUID(ctx context.Context, req *authd.GetByIDRequest) (*authd.PasswdEntry, error) {
+	key := passwdUIDKey(req.GetId())
+	if s.negativeCache.contains(key) {
+		return nil, authderrors.New(authderrors.NotFound, "")
+	}
+
+	generation := s.negativeCache.currentGeneration()
 	u, err := s.userManager.UserByID(req.GetId())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		s.negativeCache.add(key, generation)
+	}
 	if err != nil {
 		return nil, noDataFoundErrorToGRPCError(err)
 	}
@@ -90,7 +120,7 @@ func (s Service) GetPasswdEntries(ctx context.Context, req *authd.Empty) (*authd
 // GetGroupByName returns the group entry for the given group name.
 func (s Service) GetGroupByName(ctx context.Context, req *authd.GetGroupByNameRequest) (*authd.GroupEntry, error) {
 	if req.GetName() == "" {
-		return nil, status.Error(codes.InvalidArgument, "no group name provided")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no group name provided")
 	}
 	g, err := s.userManager.GroupByName(req.GetName())
 	if err != nil {
@@ -132,9 +162,22 @@ func (s Service) GetShadowByName(ctx context.Context, req *authd.GetShadowByName
 	}
 
 	if req.GetName() == "" {
-		return nil, status.Error(codes.InvalidArgument, "no shadow name provided")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no shadow name provided")
+	}
+	if !s.brokerManager.MayOwnUser(req.GetName()) {
+		return nil, authderrors.New(authderrors.NotFound, "")
+	}
+
+	key := shadowNameKey(req.GetName())
+	if s.negativeCache.contains(key) {
+		return nil, authderrors.New(authderrors.NotFound, "")
 	}
+
+	generation := s.negativeCache.currentGeneration()
 	u, err := s.userManager.ShadowByName(req.GetName())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		s.negativeCache.add(key, generation)
+	}
 	if err != nil {
 		return nil, noDataFoundErrorToGRPCError(err)
 	}
@@ -161,6 +204,20 @@ func (s Service) GetShadowEntries(ctx context.Context, req *authd.Empty) (*authd
 	return &r, nil
 }
 
+// GetAvatarByName returns the picture of the user provided by their broker.
+func (s Service) GetAvatarByName(ctx context.Context, req *authd.GetAvatarByNameRequest) (*authd.Avatar, error) {
+	if req.GetName() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name provided")
+	}
+
+	content, contentType, err := s.userManager.Avatar(req.GetName())
+	if err != nil {
+		return nil, noDataFoundErrorToGRPCError(err)
+	}
+
+	return &authd.Avatar{Content: content, ContentType: contentType}, nil
+}
+
 // userPreCheck checks if the user exists in at least one broker.
 func (s Service) userPreCheck(ctx context.Context, username string) (pwent *authd.PasswdEntry, err error) {
 	// Check if the user exists in at least one broker.
@@ -237,11 +294,11 @@ func nssShadowFromUsersShadow(u types.ShadowEntry) *authd.ShadowEntry {
 // noDataFoundErrorToGRPCError converts a data not found to proper GRPC status code.
 // This code is picked up by the NSS module to return corresponding NSS status.
 func noDataFoundErrorToGRPCError(err error) error {
-	if !errors.Is(err, users.NoDataFoundError{}) {
+	if !authderrors.Is(err, authderrors.NotFound) {
 		return err
 	}
 
-	return status.Error(codes.NotFound, "")
+	return authderrors.New(authderrors.NotFound, "")
 }
 
 // convertToNumberOfDays returns an int32 from an int. This should be only use for safe conversions where
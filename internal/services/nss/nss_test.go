@@ -47,13 +47,15 @@ func TestGetPasswdByName(t *testing.T) {
 	tests := map[string]struct {
 		username string
 
-		sourceDB       string
-		shouldPreCheck bool
+		sourceDB        string
+		shouldPreCheck  bool
+		brokerUsernames string
 
 		wantErr          bool
 		wantErrNotExists bool
 	}{
 		"Return_existing_user": {username: "user1"},
+		"Return_existing_user_matching_the_usernames_of_the_broker": {username: "user1", brokerUsernames: "other-*, USER*"},
 
 		"Precheck_user_if_not_in_db": {username: "user-pre-check", shouldPreCheck: true},
 		"Prechecked_user_with_upper_cases_in_username_has_same_id_as_lower_case": {username: "User-Pre-Check", shouldPreCheck: true},
@@ -63,13 +65,19 @@ func TestGetPasswdByName(t *testing.T) {
 
 		"Error_if_user_not_in_db_and_precheck_is_disabled": {username: "user-pre-check", wantErr: true, wantErrNotExists: true},
 		"Error_if_user_not_in_db_and_precheck_fails":       {username: "does-not-exist", sourceDB: "empty.db.yaml", shouldPreCheck: true, wantErr: true, wantErrNotExists: true},
+		"Error_with_typed_GRPC_notfound_code_on_user_not_matching_the_usernames_of_the_broker": {
+			username: "user1", brokerUsernames: "*@example.com", wantErr: true, wantErrNotExists: true,
+		},
+		"Error_if_user_not_matching_the_usernames_of_the_broker_is_not_prechecked": {
+			username: "user-pre-check", brokerUsernames: "*@example.com", shouldPreCheck: true, wantErr: true, wantErrNotExists: true,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			// We don't care about gpasswd output here as it's already covered in the db unit tests.
 			_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "empty.group"))
 
-			client := newNSSClient(t, tc.sourceDB, false)
+			client := newNSSClientWithBrokerUsernames(t, tc.sourceDB, false, tc.brokerUsernames)
 
 			got, err := client.GetPasswdByName(context.Background(), &authd.GetPasswdByNameRequest{Name: tc.username, ShouldPreCheck: tc.shouldPreCheck})
 			requireExpectedResult(t, "GetPasswdByName", got, err, tc.wantErr, tc.wantErrNotExists)
@@ -77,6 +85,25 @@ func TestGetPasswdByName(t *testing.T) {
 	}
 }
 
+func TestGetPasswdByUIDAfterUserIsCreated(t *testing.T) {
+	// We don't care about gpasswd output here as it's already covered in the db unit tests.
+	_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "empty.group"))
+
+	client := newNSSClient(t, "", false)
+
+	// This is the UID generated for the pre-checked user.
+	const uid = 1234
+	_, err := client.GetPasswdByUID(context.Background(), &authd.GetByIDRequest{Id: uid})
+	requireExpectedResult[authd.PasswdEntry](t, "GetPasswdByUID", nil, err, true, true)
+
+	u, err := client.GetPasswdByName(context.Background(), &authd.GetPasswdByNameRequest{Name: "user-pre-check", ShouldPreCheck: true})
+	require.NoError(t, err, "GetPasswdByName should pre-check the user")
+	require.Equal(t, uint32(uid), u.GetUid(), "The pre-checked user should have the generated UID")
+
+	_, err = client.GetPasswdByUID(context.Background(), &authd.GetByIDRequest{Id: uid})
+	require.NoError(t, err, "GetPasswdByUID should return the user once it is created, even if it was not found before")
+}
+
 func TestGetPasswdByUID(t *testing.T) {
 	tests := map[string]struct {
 		uid uint32
@@ -208,6 +235,7 @@ func TestGetShadowByName(t *testing.T) {
 
 		sourceDB           string
 		currentUserNotRoot bool
+		brokerUsernames    string
 
 		wantErr          bool
 		wantErrNotExists bool
@@ -217,13 +245,16 @@ func TestGetShadowByName(t *testing.T) {
 		"Error_when_not_root": {currentUserNotRoot: true, username: "user1", wantErr: true},
 		"Error_with_typed_GRPC_notfound_code_on_unexisting_user": {username: "does-not-exists", wantErr: true, wantErrNotExists: true},
 		"Error_on_missing_name":                                  {wantErr: true},
+		"Error_with_typed_GRPC_notfound_code_on_user_not_matching_the_usernames_of_the_broker": {
+			username: "user1", brokerUsernames: "*@example.com", wantErr: true, wantErrNotExists: true,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			// We don't care about gpasswd output here as it's already covered in the db unit tests.
 			_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "empty.group"))
 
-			client := newNSSClient(t, tc.sourceDB, tc.currentUserNotRoot)
+			client := newNSSClientWithBrokerUsernames(t, tc.sourceDB, tc.currentUserNotRoot, tc.brokerUsernames)
 
 			got, err := client.GetShadowByName(context.Background(), &authd.GetShadowByNameRequest{Name: tc.username})
 			requireExpectedResult(t, "GetShadowByName", got, err, tc.wantErr, tc.wantErrNotExists)
@@ -256,6 +287,47 @@ func TestGetShadowEntries(t *testing.T) {
 	}
 }
 
+func TestGetAvatarByName(t *testing.T) {
+	tests := map[string]struct {
+		username string
+
+		sourceDB           string
+		currentUserNotRoot bool
+
+		wantErr          bool
+		wantErrNotExists bool
+	}{
+		"Return_avatar_of_existing_user":         {username: "user1"},
+		"Return_avatar_even_if_user_is_not_root": {username: "user1", currentUserNotRoot: true},
+
+		"Error_with_typed_GRPC_notfound_code_on_user_without_avatar": {username: "user2", wantErr: true, wantErrNotExists: true},
+		"Error_with_typed_GRPC_notfound_code_on_unexisting_user":     {username: "does-not-exists", wantErr: true, wantErrNotExists: true},
+		"Error_on_missing_name": {wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about gpasswd output here as it's already covered in the db unit tests.
+			_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "empty.group"))
+
+			client := newNSSClient(t, tc.sourceDB, tc.currentUserNotRoot)
+
+			got, err := client.GetAvatarByName(context.Background(), &authd.GetAvatarByNameRequest{Name: tc.username})
+			if tc.wantErr {
+				require.Error(t, err, "GetAvatarByName should return an error but did not")
+				s, ok := status.FromError(err)
+				require.True(t, ok, "The error is always a gRPC error")
+				if tc.wantErrNotExists {
+					require.Equal(t, codes.NotFound, s.Code(), "GetAvatarByName should return NotFound error")
+				}
+				return
+			}
+			require.NoError(t, err, "GetAvatarByName should not return an error, but did")
+			require.Equal(t, "GIF89a user1 picture", string(got.GetContent()), "GetAvatarByName returned an unexpected picture")
+			require.Equal(t, "image/gif", got.GetContentType(), "GetAvatarByName returned an unexpected content type")
+		})
+	}
+}
+
 func TestMockgpasswd(t *testing.T) {
 	localgroupstestutils.Mockgpasswd(t)
 }
@@ -264,6 +336,13 @@ func TestMockgpasswd(t *testing.T) {
 func newNSSClient(t *testing.T, sourceDB string, currentUserNotRoot bool) (client authd.NSSClient) {
 	t.Helper()
 
+	return newNSSClientWithBrokerUsernames(t, sourceDB, currentUserNotRoot, "")
+}
+
+// newNSSClientWithBrokerUsernames returns a new NSS client, whose broker publishes the given usernames patterns.
+func newNSSClientWithBrokerUsernames(t *testing.T, sourceDB string, currentUserNotRoot bool, brokerUsernames string) (client authd.NSSClient) {
+	t.Helper()
+
 	// socket path is limited in length.
 	tmpDir, err := os.MkdirTemp("", "authd-socket-dir")
 	require.NoError(t, err, "Setup: could not setup temporary socket dir path")
@@ -279,7 +358,7 @@ func newNSSClient(t *testing.T, sourceDB string, currentUserNotRoot bool) (clien
 	}
 	pm := permissions.New(opts...)
 
-	service := nss.NewService(context.Background(), newUserManagerForTests(t, sourceDB), newBrokersManagerForTests(t), &pm)
+	service := nss.NewService(context.Background(), newUserManagerForTests(t, sourceDB), newBrokersManagerForTests(t, brokerUsernames), &pm)
 
 	grpcServer := grpc.NewServer(permissions.WithUnixPeerCreds(), grpc.ChainUnaryInterceptor(enableCheckGlobalAccess(service), errmessages.RedactErrorInterceptor))
 	authd.RegisterNSSServer(grpcServer, service)
@@ -337,13 +416,22 @@ func newUserManagerForTests(t *testing.T, sourceDB string) *users.Manager {
 }
 
 // newBrokersManagerForTests returns a new broker manager with a broker mock for tests, it's cleaned when the test ends.
-func newBrokersManagerForTests(t *testing.T) *brokers.Manager {
+// If usernames is not empty, the broker publishes these patterns of the usernames it owns.
+func newBrokersManagerForTests(t *testing.T, usernames string) *brokers.Manager {
 	t.Helper()
 
 	cfg, cleanup, err := testutils.StartBusBrokerMock(t.TempDir(), "BrokerMock")
 	require.NoError(t, err, "Setup: could not start bus broker mock")
 	t.Cleanup(cleanup)
 
+	if usernames != "" {
+		f, err := os.OpenFile(cfg, os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err, "Setup: could not open broker configuration")
+		_, err = fmt.Fprintf(f, "usernames = %s\n", usernames)
+		require.NoError(t, err, "Setup: could not add usernames to broker configuration")
+		require.NoError(t, f.Close(), "Setup: could not close broker configuration")
+	}
+
 	m, err := brokers.NewManager(context.Background(), filepath.Dir(cfg), nil)
 	require.NoError(t, err, "Setup: could not create broker manager")
 	t.Cleanup(m.Stop)
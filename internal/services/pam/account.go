@@ -0,0 +1,142 @@
+package pam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/permissions"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/internal/users/gecos"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// GetUserAccountInfo returns the information the account settings of the desktop show about a user who already logged
+// in with their broker.
+func (s Service) GetUserAccountInfo(ctx context.Context, req *authd.GUAIRequest) (resp *authd.GUAIResponse, err error) {
+	defer decorate.OnError(&err, "can't get account information of user")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+	if err := s.checkAccountAccess(ctx, req.GetUsername()); err != nil {
+		return nil, err
+	}
+
+	// Only the users stored in the database logged in with authd, unlike the temporary ones UserByName also returns.
+	brokerID, err := s.userManager.BrokerForUser(req.GetUsername())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q never logged in with authd", req.GetUsername())
+	}
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.userManager.UserByName(req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, err = s.userManager.Avatar(u.Name)
+	if err != nil && !errors.Is(err, users.NoDataFoundError{}) {
+		return nil, err
+	}
+	hasAvatar := err == nil
+
+	resp = &authd.GUAIResponse{
+		ManagedBy:   "Managed by authd",
+		BrokerId:    brokerID,
+		DisplayName: gecos.Parse(u.Gecos).FullName,
+		HasAvatar:   hasAvatar,
+		Disabled:    true,
+	}
+	for _, b := range s.brokerManager.AvailableBrokers() {
+		if b.ID != brokerID {
+			continue
+		}
+		resp.BrokerName = b.Name
+		resp.ManagedBy = fmt.Sprintf("Managed by %s", b.Name)
+		resp.PasswordChangePossible = b.ID != brokers.LocalBrokerName
+		resp.Disabled = false
+	}
+
+	return resp, nil
+}
+
+// SetUserDisplayName changes the full name of a user who already logged in with their broker. The one provided by the
+// broker takes precedence over it on the next login.
+func (s Service) SetUserDisplayName(ctx context.Context, req *authd.SUDNRequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't set display name of user")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+	if err := s.checkAccountAccess(ctx, req.GetUsername()); err != nil {
+		return nil, err
+	}
+
+	displayName := req.GetDisplayName()
+	update := gecos.Update{FullName: &displayName}
+	if err := update.Validate(); err != nil {
+		return nil, authderrors.Wrap(authderrors.InvalidArgument, err)
+	}
+
+	err = s.userManager.UpdateGecos(req.GetUsername(), update)
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q never logged in with authd", req.GetUsername())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "Display name of user %q set to %q", req.GetUsername(), displayName)
+	return &authd.Empty{}, nil
+}
+
+// SetUserAvatar changes the picture of a user who already logged in with their broker. The one provided by the broker
+// takes precedence over it on the next login.
+func (s Service) SetUserAvatar(ctx context.Context, req *authd.SUARequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't set avatar of user")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+	if err := s.checkAccountAccess(ctx, req.GetUsername()); err != nil {
+		return nil, err
+	}
+
+	err = s.userManager.SetAvatar(req.GetUsername(), req.GetContent())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q never logged in with authd", req.GetUsername())
+	}
+	if errors.Is(err, users.ErrInvalidAvatar) {
+		return nil, authderrors.Wrap(authderrors.InvalidArgument, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "Avatar of user %q changed", req.GetUsername())
+	return &authd.Empty{}, nil
+}
+
+// checkAccountAccess returns an error unless the request comes from root or from the user themselves.
+func (s Service) checkAccountAccess(ctx context.Context, username string) error {
+	if err := s.permissionManager.IsRequestFromRoot(ctx); err == nil {
+		return nil
+	}
+
+	uid, err := permissions.PeerUID(ctx)
+	if err != nil {
+		return authderrors.Wrap(authderrors.PermissionDenied, err)
+	}
+	// The same error is returned for the users who never logged in, so that other users can't probe them.
+	u, err := s.userManager.UserByName(username)
+	if err != nil || u.UID != uid {
+		return authderrors.Errorf(authderrors.PermissionDenied, "only user %q and root can manage their account", username)
+	}
+	return nil
+}
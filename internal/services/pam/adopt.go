@@ -0,0 +1,36 @@
+package pam
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// AdoptUser migrates a local user to the management of authd, so that the user of the broker logging in keeps their
+// UID and home directory instead of getting a new identity.
+func (s Service) AdoptUser(ctx context.Context, req *authd.AURequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't adopt local user")
+
+	if req.GetLocalName() == "" || req.GetBrokerUser() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "the local user and the user of the broker are required")
+	}
+
+	err = s.userManager.AdoptUser(req.GetLocalName(), req.GetBrokerUser())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q not found in /etc/passwd", req.GetLocalName())
+	}
+	if errors.Is(err, users.ErrInvalidAdoption) {
+		return nil, authderrors.Wrap(authderrors.InvalidArgument, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "Local user %q adopted by %q", req.GetLocalName(), req.GetBrokerUser())
+	return &authd.Empty{}, nil
+}
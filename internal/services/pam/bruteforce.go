@@ -0,0 +1,390 @@
+package pam
+
+import (
+	"context"
+	"maps"
+	"math/bits"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/clock"
+	"github.com/ubuntu/authd/internal/faillock"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// defaultBruteForceDelay is the first backoff delay, if the policy doesn't set one.
+	defaultBruteForceDelay = 30 * time.Second
+	// defaultBruteForceMaxDelay is the longest backoff delay, if the policy doesn't set one.
+	defaultBruteForceMaxDelay = 15 * time.Minute
+	// defaultBruteForceResetAfter is how long the failed authentications are remembered, if the policy doesn't set it.
+	defaultBruteForceResetAfter = time.Hour
+	// unknownSource is the source of the failed authentications of the clients which don't give their PAM service.
+	unknownSource = "unknown"
+)
+
+// BruteForcePolicy defines the backoff applied to the authentications of a user after too many failures, whatever the
+// PAM services they went through, so that an attacker can't try more passwords by switching between gdm, sshd and sudo.
+type BruteForcePolicy struct {
+	// MaxAttempts is the number of failed authentications, through all the PAM services, after which the
+	// authentications of the user are refused for a while. The backoff is disabled if it's 0.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// Delay is how long the authentications are refused after MaxAttempts failures. It doubles with each further
+	// failure.
+	Delay time.Duration `mapstructure:"delay"`
+	// MaxDelay is the longest the authentications are refused after a failure.
+	MaxDelay time.Duration `mapstructure:"max_delay"`
+	// ResetAfter is how long after their last failure the failed authentications of a user are forgotten.
+	ResetAfter time.Duration `mapstructure:"reset_after"`
+	// Faillock also records the failures in the tally files of pam_faillock, for the tools inspecting them, and
+	// forgets the failures of the users whose tally file was reset, for example with faillock --reset.
+	Faillock bool `mapstructure:"faillock"`
+}
+
+// withDefaults returns the policy with the default values for its unset delays.
+func (p BruteForcePolicy) withDefaults() BruteForcePolicy {
+	if p.Delay <= 0 {
+		p.Delay = defaultBruteForceDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultBruteForceMaxDelay
+	}
+	if p.ResetAfter <= 0 {
+		p.ResetAfter = defaultBruteForceResetAfter
+	}
+	return p
+}
+
+// enabled returns whether the authentications are refused after too many failures.
+func (p BruteForcePolicy) enabled() bool {
+	return p.MaxAttempts > 0
+}
+
+// BruteForceMetrics are the counters of the failed authentications since the daemon started.
+type BruteForceMetrics struct {
+	// Failures is the number of authentications denied by the brokers.
+	Failures uint64
+	// Refused is the number of authentications refused because their user was backed off.
+	Refused uint64
+	// TrackedUsers is the number of users with failed authentications.
+	TrackedUsers int
+	// BackedOffUsers is the number of users whose authentications are currently refused.
+	BackedOffUsers int
+}
+
+// failedAuthentications are the failed authentications of a user since their last successful one.
+type failedAuthentications struct {
+	bySource    map[string]int
+	lastFailure time.Time
+}
+
+// total returns the number of failed authentications through all the sources.
+func (f failedAuthentications) total() (n int) {
+	for _, c := range f.bySource {
+		n += c
+	}
+	return n
+}
+
+// bruteForceProtection tracks the failed authentications of the users, by source, and applies the backoff of the
+// policy to all of them.
+type bruteForceProtection struct {
+	policy BruteForcePolicy
+	clock  clock.Clock
+	// tallyDir is the directory of the pam_faillock tally files, which are not used if it's empty.
+	tallyDir string
+
+	users map[string]*failedAuthentications
+	// inFlight are the authentications of each user which passed the check, but whose outcome is not known yet.
+	inFlight map[string]int
+	failures uint64
+	refused  uint64
+	mu       sync.Mutex
+}
+
+func newBruteForceProtection(ctx context.Context, policy BruteForcePolicy, c clock.Clock, faillockConfigPath string) *bruteForceProtection {
+	b := &bruteForceProtection{
+		policy:   policy.withDefaults(),
+		clock:    c,
+		users:    make(map[string]*failedAuthentications),
+		inFlight: make(map[string]int),
+	}
+	if !policy.Faillock {
+		return b
+	}
+
+	dir, err := faillock.Dir(faillockConfigPath)
+	if err != nil {
+		log.Warningf(ctx, "The failed authentications are not recorded for pam_faillock: %v", err)
+		return b
+	}
+	log.Debugf(ctx, "Recording the failed authentications in the pam_faillock tally files of %q", dir)
+	b.tallyDir = dir
+	b.restore(ctx)
+	return b
+}
+
+// restore rebuilds the failed authentications of the users from the valid records of their tally files, so that
+// restarting the daemon, for example after it quit when idle, doesn't reset their backoff.
+func (b *bruteForceProtection) restore(ctx context.Context) {
+	usernames, err := faillock.Usernames(b.tallyDir)
+	if err != nil {
+		log.Warningf(ctx, "Could not restore failed authentications from pam_faillock: %v", err)
+		return
+	}
+
+	for _, username := range usernames {
+		records, err := faillock.Read(b.tallyDir, username)
+		if err != nil {
+			log.Warningf(ctx, "Could not restore failed authentications from pam_faillock: %v", err)
+			continue
+		}
+
+		f := &failedAuthentications{bySource: make(map[string]int)}
+		for _, r := range records {
+			if !r.Valid() {
+				continue
+			}
+			f.bySource[r.Source]++
+			if r.Time.After(f.lastFailure) {
+				f.lastFailure = r.Time
+			}
+		}
+		if len(f.bySource) == 0 || b.clock.Now().Sub(f.lastFailure) > b.policy.ResetAfter {
+			continue
+		}
+		log.Debugf(ctx, "Restored %d failed authentications of user %q from pam_faillock", f.total(), username)
+		b.users[username] = f
+	}
+}
+
+// busy returns whether the failed authentications of some users are only tracked in memory, so that they would be
+// forgotten if the daemon quit.
+func (b *bruteForceProtection) busy() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tallyDir != "" {
+		return false
+	}
+	b.forgetExpired()
+	return len(b.users) > 0
+}
+
+// check returns a ResourceExhausted error if the authentications of the user are refused after too many failures.
+// Otherwise, the attempt is reserved until the returned function is called, once its failure or success is recorded,
+// so that concurrent authentications can't exceed the maximum number of attempts.
+func (b *bruteForceProtection) check(ctx context.Context, username, service string) (release func(), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.policy.enabled() {
+		return func() {}, nil
+	}
+
+	b.honorTallyReset(ctx, username)
+	until := b.backoffUntil(username)
+	now := b.clock.Now()
+	if until.After(now) {
+		b.refused++
+		wait := until.Sub(now).Round(time.Second)
+		log.Noticef(ctx, "Audit: refused authentication of user %q to service %q: too many failed attempts, backed off for %s",
+			username, sourceOf(service), wait)
+		return nil, authderrors.Errorf(authderrors.ResourceExhausted, "too many failed authentication attempts, try again in %s", wait)
+	}
+
+	// Once the maximum is reached, only one attempt at a time can be made, as its failure backs the user off again.
+	f, _ := b.get(username)
+	if pending := b.inFlight[username]; pending > 0 && f.total()+pending >= b.policy.MaxAttempts {
+		b.refused++
+		log.Noticef(ctx, "Audit: refused authentication of user %q to service %q: too many authentication attempts in progress",
+			username, sourceOf(service))
+		return nil, authderrors.New(authderrors.ResourceExhausted, "too many authentication attempts in progress, try again later")
+	}
+
+	b.inFlight[username]++
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.inFlight[username]--
+		if b.inFlight[username] <= 0 {
+			delete(b.inFlight, username)
+		}
+	}, nil
+}
+
+// failed records a failed authentication of the user through the service.
+func (b *bruteForceProtection) failed(ctx context.Context, username, service string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.forgetExpired()
+
+	f, ok := b.users[username]
+	if !ok {
+		f = &failedAuthentications{bySource: make(map[string]int)}
+		b.users[username] = f
+	}
+	f.bySource[sourceOf(service)]++
+	f.lastFailure = b.clock.Now()
+	b.failures++
+
+	if b.tallyDir != "" {
+		r := faillock.Record{Source: sourceOf(service), Status: faillock.StatusValid, Time: f.lastFailure}
+		if err := faillock.Append(b.tallyDir, username, r); err != nil {
+			log.Warningf(ctx, "Could not record failed authentication for pam_faillock: %v", err)
+		}
+	}
+
+	log.Debugf(ctx, "Failed authentication of user %q to service %q, %d failures in total", username, sourceOf(service), f.total())
+}
+
+// succeeded forgets the failed authentications of the user, and resets their tally file like pam_faillock does.
+func (b *bruteForceProtection) succeeded(ctx context.Context, username string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.users, username)
+
+	if b.tallyDir == "" {
+		return
+	}
+	if err := faillock.Reset(b.tallyDir, username); err != nil {
+		log.Warningf(ctx, "Could not reset failed authentications for pam_faillock: %v", err)
+	}
+}
+
+// honorTallyReset forgets the failed authentications of the user if their tally file has no valid record anymore,
+// because the administrator reset it. It must be called with the lock held.
+func (b *bruteForceProtection) honorTallyReset(ctx context.Context, username string) {
+	if b.tallyDir == "" {
+		return
+	}
+	if _, ok := b.users[username]; !ok {
+		return
+	}
+
+	records, err := faillock.Read(b.tallyDir, username)
+	if err != nil {
+		log.Warningf(ctx, "Could not read failed authentications of pam_faillock: %v", err)
+		return
+	}
+	if slices.ContainsFunc(records, faillock.Record.Valid) {
+		return
+	}
+	log.Noticef(ctx, "Audit: forgetting the failed authentications of user %q, as their pam_faillock tally was reset", username)
+	delete(b.users, username)
+}
+
+// forgetExpired forgets the failed authentications of the users who didn't fail since the reset delay of the policy.
+// It must be called with the lock held.
+func (b *bruteForceProtection) forgetExpired() {
+	now := b.clock.Now()
+	maps.DeleteFunc(b.users, func(_ string, f *failedAuthentications) bool {
+		return now.Sub(f.lastFailure) > b.policy.ResetAfter
+	})
+}
+
+// get returns the failed authentications of the user, which are empty if they expired.
+// It must be called with the lock held.
+func (b *bruteForceProtection) get(username string) (failedAuthentications, bool) {
+	f, ok := b.users[username]
+	if !ok || b.clock.Now().Sub(f.lastFailure) > b.policy.ResetAfter {
+		return failedAuthentications{}, false
+	}
+	return *f, true
+}
+
+// backoffUntil returns the time until which the authentications of the user are refused, which is zero if they are
+// not. It must be called with the lock held.
+func (b *bruteForceProtection) backoffUntil(username string) time.Time {
+	f, ok := b.get(username)
+	if !ok || !b.policy.enabled() {
+		return time.Time{}
+	}
+	extra := f.total() - b.policy.MaxAttempts
+	if extra < 0 {
+		return time.Time{}
+	}
+
+	delay := b.policy.MaxDelay
+	// The delay saturates before the doubling could overflow it.
+	if extra < bits.Len64(uint64(b.policy.MaxDelay/b.policy.Delay)) {
+		delay = min(b.policy.Delay<<extra, b.policy.MaxDelay)
+	}
+	return f.lastFailure.Add(delay)
+}
+
+// metrics returns the counters of the failed authentications.
+func (b *bruteForceProtection) metrics() BruteForceMetrics {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.forgetExpired()
+	m := BruteForceMetrics{
+		Failures:     b.failures,
+		Refused:      b.refused,
+		TrackedUsers: len(b.users),
+	}
+	now := b.clock.Now()
+	for username := range b.users {
+		if b.backoffUntil(username).After(now) {
+			m.BackedOffUsers++
+		}
+	}
+	return m
+}
+
+// sourceOf returns the source of the authentications made through the PAM service.
+func sourceOf(service string) string {
+	if service == "" {
+		return unknownSource
+	}
+	return service
+}
+
+// Busy returns whether the daemon must not quit when idle, as it would forget the failed authentications of some
+// users and reset their backoff.
+func (s Service) Busy() bool {
+	return s.bruteForce.busy()
+}
+
+// BruteForceMetrics returns the counters of the failed authentications since the daemon started.
+func (s Service) BruteForceMetrics() BruteForceMetrics {
+	return s.bruteForce.metrics()
+}
+
+// GetFailedAuthentications returns the failed authentications of the user since their last successful one, by PAM
+// service, and until when their authentications are refused.
+func (s Service) GetFailedAuthentications(ctx context.Context, req *authd.GFARequest) (resp *authd.GFAResponse, err error) {
+	defer decorate.OnError(&err, "can't get failed authentications")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+
+	s.bruteForce.mu.Lock()
+	defer s.bruteForce.mu.Unlock()
+
+	s.bruteForce.honorTallyReset(ctx, req.GetUsername())
+	resp = &authd.GFAResponse{}
+	f, ok := s.bruteForce.get(req.GetUsername())
+	if !ok {
+		return resp, nil
+	}
+
+	for _, service := range slices.Sorted(maps.Keys(f.bySource)) {
+		resp.Sources = append(resp.Sources, &authd.GFAResponse_Source{
+			Service:        service,
+			FailedAttempts: uint32(f.bySource[service]),
+		})
+	}
+	resp.LastFailure = f.lastFailure.Unix()
+	if until := s.bruteForce.backoffUntil(req.GetUsername()); until.After(s.bruteForce.clock.Now()) {
+		resp.BackoffUntil = until.Unix()
+	}
+	return resp, nil
+}
@@ -0,0 +1,31 @@
+package pam
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// ChangeUID changes the UID of a user of authd. The ownership of their files is migrated by the client.
+func (s Service) ChangeUID(ctx context.Context, req *authd.CUIDRequest) (resp *authd.CUIDResponse, err error) {
+	defer decorate.OnError(&err, "can't change UID of user")
+
+	name, err := s.userManager.ChangeUID(req.GetOldUid(), req.GetNewUid())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "UID %d doesn't belong to a user of authd", req.GetOldUid())
+	}
+	if errors.Is(err, users.ErrInvalidUIDChange) {
+		return nil, authderrors.Wrap(authderrors.InvalidArgument, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Noticef(ctx, "UID of user %q changed from %d to %d", name, req.GetOldUid(), req.GetNewUid())
+	return &authd.CUIDResponse{Username: name}, nil
+}
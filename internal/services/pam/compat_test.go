@@ -0,0 +1,79 @@
+package pam_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/compat"
+	"github.com/ubuntu/authd/internal/testutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TestClientCompatibility runs stubs of the clients speaking the older versions of the protocol against the current
+// PAM service.
+func TestClientCompatibility(t *testing.T) {
+	t.Parallel()
+
+	const legacy = ""
+
+	tests := map[string]struct {
+		clientVersion string
+		username      string
+		authenticate  bool
+
+		wantClientTooOld bool
+	}{
+		"Legacy_client_starts_a_session_with_the_default_encryption_algorithm": {clientVersion: legacy, username: "success"},
+		"Legacy_client_authenticates_without_encryption_key_rotation":          {clientVersion: legacy, username: "IA_next", authenticate: true},
+		"Current_client_starts_a_session_with_another_encryption_algorithm":    {clientVersion: "2", username: "NS_encryption_algorithm"},
+		"Current_client_authenticates_with_encryption_key_rotation":            {clientVersion: "2", username: "IA_next_with_encryption_key", authenticate: true},
+		"Newer_client_authenticates_with_encryption_key_rotation":              {clientVersion: "3", username: "IA_retry_with_encryption_key", authenticate: true},
+
+		"Error_when_legacy_client_starts_a_session_with_another_encryption_algorithm": {clientVersion: legacy, username: "NS_encryption_algorithm", wantClientTooOld: true},
+		"Error_when_legacy_client_authenticates_with_encryption_key_rotation":         {clientVersion: legacy, username: "IA_next_with_encryption_key", authenticate: true, wantClientTooOld: true},
+		"Error_when_client_reports_an_invalid_version":                                {clientVersion: "invalid", username: "NS_encryption_algorithm", wantClientTooOld: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := newPermissionManager(t, false)
+			socketPath, _ := startPamService(t, nil, globalBrokerManager, &pm)
+			var interceptors []grpc.UnaryClientInterceptor
+			if tc.clientVersion != legacy {
+				interceptors = append(interceptors, reportClientVersion(tc.clientVersion))
+			}
+			client := newPamClientForSocket(t, socketPath, interceptors...)
+
+			sbResp, err := client.SelectBroker(context.Background(), &authd.SBRequest{
+				BrokerId: mockBrokerGeneratedID,
+				Username: t.Name() + testutils.IDSeparator + tc.username,
+				Mode:     authd.SessionMode_LOGIN,
+			})
+			if err == nil && tc.authenticate {
+				_, err = client.IsAuthenticated(context.Background(), &authd.IARequest{
+					SessionId:          sbResp.GetSessionId(),
+					AuthenticationData: &authd.IARequest_AuthenticationData{},
+				})
+			}
+			if tc.wantClientTooOld {
+				require.Equal(t, authderrors.ErrClientTooOld, authderrors.SentinelFromStatus(status.Convert(err)),
+					"The request should fail because the client is too old, got: %v", err)
+				return
+			}
+			require.NoError(t, err, "The request should not return an error, but did")
+		})
+	}
+}
+
+// reportClientVersion returns an interceptor reporting the given protocol version, like the clients do.
+func reportClientVersion(version string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(metadata.AppendToOutgoingContext(ctx, compat.MetadataKey, version), method, req, reply, cc, opts...)
+	}
+}
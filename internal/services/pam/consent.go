@@ -0,0 +1,136 @@
+package pam
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users/types"
+	"github.com/ubuntu/authd/log"
+)
+
+const (
+	// consentAuthModeID is the ID of the authentication mode showing the consent message, once the broker granted the
+	// access.
+	consentAuthModeID = "consent"
+	// defaultConsentTitle is the title of the consent message if none is set by the policy.
+	defaultConsentTitle = "Terms of use"
+)
+
+// ConsentPolicy defines a message, like the terms of use of the machine or the IT policy, that the users handled by
+// authd must accept on their first login. They are asked again to accept it when it changes, and a user declining it
+// is denied the access.
+type ConsentPolicy struct {
+	// Title is the title shown with the message.
+	Title string `mapstructure:"title"`
+	// Message is the message the users must accept. It takes precedence over the one provided by the brokers.
+	Message string `mapstructure:"message"`
+}
+
+// pendingConsent is the consent message a user must accept once the broker granted the access, with what's needed to
+// grant it afterwards.
+type pendingConsent struct {
+	title      string
+	message    string
+	userInfo   types.UserInfo
+	brokerName string
+}
+
+// consentFor returns the consent message the user granted by the broker must accept, if any.
+func (s Service) consentFor(ctx context.Context, sessionID string, uInfo types.UserInfo, brokerName string) (*pendingConsent, error) {
+	// The users only accept the message when logging in, not when changing their password or re-authenticating.
+	if info, ok := s.sessions.get(sessionID); !ok || info.mode != auth.SessionModeLogin {
+		return nil, nil
+	}
+
+	message := s.consentPolicy.Message
+	if message == "" {
+		message = uInfo.ConsentMessage
+	}
+	if message == "" {
+		return nil, nil
+	}
+
+	accepted, err := s.userManager.ConsentAccepted(uInfo.Name, message)
+	if err != nil {
+		return nil, err
+	}
+	if accepted {
+		return nil, nil
+	}
+
+	log.Debugf(ctx, "%s: User %q must accept the consent message", sessionID, uInfo.Name)
+	title := s.consentPolicy.Title
+	if title == "" {
+		title = defaultConsentTitle
+	}
+	return &pendingConsent{title: title, message: message, userInfo: uInfo, brokerName: brokerName}, nil
+}
+
+// consentAuthenticationModes returns the authentication mode showing the consent message, if the client supports it.
+func consentAuthenticationModes(supportedLayouts []*authd.UILayout) (*authd.GAMResponse, error) {
+	if !slices.ContainsFunc(supportedLayouts, func(l *authd.UILayout) bool { return l.GetType() == layouts.Consent }) {
+		return nil, authderrors.New(authderrors.PermissionDenied, "the consent message required to log in can't be shown")
+	}
+
+	return &authd.GAMResponse{
+		AuthenticationModes: []*authd.GAMResponse_AuthenticationMode{
+			{Id: consentAuthModeID, Label: "Accept the terms of use"},
+		},
+		LastUsedAuthenticationModeId: consentAuthModeID,
+	}, nil
+}
+
+// consentUILayout returns the layout showing the consent message.
+func consentUILayout(c pendingConsent, authenticationModeID string) (*authd.SAMResponse, error) {
+	if authenticationModeID != consentAuthModeID {
+		return nil, authderrors.Errorf(authderrors.InvalidArgument, "the consent message must be accepted first, not %q", authenticationModeID)
+	}
+
+	return &authd.SAMResponse{
+		UiLayoutInfo: mapToUILayout(map[string]string{
+			layouts.Type:    layouts.Consent,
+			layouts.Label:   c.title,
+			layouts.Content: c.message,
+		}),
+	}, nil
+}
+
+// answerConsent grants the access to the user accepting the consent message, recording when they accepted it, and
+// denies it to the user declining it.
+func (s Service) answerConsent(ctx context.Context, sessionID string, c pendingConsent, answer string) (*authd.IAResponse, error) {
+	username := c.userInfo.Name
+
+	switch answer {
+	case layouts.True:
+		resp, err := s.grantAccess(ctx, sessionID, c.userInfo, c.brokerName)
+		if err != nil {
+			return nil, err
+		}
+		s.sessions.setConsent(sessionID, nil)
+		if err := s.userManager.RecordConsent(username, c.message); err != nil {
+			return nil, err
+		}
+		log.Noticef(ctx, "Audit: user %q accepted the consent message", username)
+		return resp, nil
+
+	case layouts.False:
+		s.sessions.setConsent(sessionID, nil)
+		log.Noticef(ctx, "Audit: user %q declined the consent message", username)
+		data, err := json.Marshal(map[string]string{
+			"message":         "Access denied: the terms of use were declined",
+			auth.ErrorCodeKey: auth.ErrorCodeConsentDenied,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &authd.IAResponse{Access: auth.Denied, Msg: string(data)}, nil
+
+	default:
+		return nil, authderrors.New(authderrors.InvalidArgument, "the consent message must be accepted or declined")
+	}
+}
@@ -0,0 +1,51 @@
+package pam
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// DumpDatabase returns the whole content of the users database, JSON encoded. This is meant for debugging and for
+// migrating the users to another machine.
+func (s Service) DumpDatabase(ctx context.Context, _ *authd.Empty) (resp *authd.DatabaseDump, err error) {
+	defer decorate.OnError(&err, "could not dump the database")
+
+	d, err := s.userManager.DumpDatabase()
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	log.Noticef(ctx, "Audit: dumped the database with %d users and %d groups", len(d.Users), len(d.Groups))
+	return &authd.DatabaseDump{Content: content}, nil
+}
+
+// ImportDatabase replaces the whole content of the users database with the given JSON encoded one, as returned by
+// DumpDatabase.
+func (s Service) ImportDatabase(ctx context.Context, req *authd.DatabaseDump) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "could not import the database")
+
+	var d users.DatabaseDump
+	dec := json.NewDecoder(bytes.NewReader(req.GetContent()))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&d); err != nil {
+		return nil, authderrors.Errorf(authderrors.InvalidArgument, "invalid database content: %v", err)
+	}
+
+	if err := s.userManager.ImportDatabase(d); err != nil {
+		return nil, err
+	}
+
+	log.Noticef(ctx, "Audit: imported a database with %d users and %d groups", len(d.Users), len(d.Groups))
+	return &authd.Empty{}, nil
+}
@@ -0,0 +1,28 @@
+package pam
+
+import (
+	"context"
+
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// SetDebugSocket starts or stops serving the profiles and the runtime statistics of the daemon on the debug socket,
+// which only accepts the connections of root.
+func (s Service) SetDebugSocket(ctx context.Context, req *authd.SDSRequest) (resp *authd.SDSResponse, err error) {
+	defer decorate.OnError(&err, "can't set debug socket")
+
+	if req.GetEnable() {
+		log.Infof(ctx, "Enabling the debug socket %s", s.debugServer.Path())
+		err = s.debugServer.Start()
+	} else {
+		log.Infof(ctx, "Disabling the debug socket %s", s.debugServer.Path())
+		err = s.debugServer.Stop()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &authd.SDSResponse{SocketPath: s.debugServer.Path(), Enabled: s.debugServer.Running()}, nil
+}
@@ -0,0 +1,53 @@
+package pam
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+)
+
+// defaultBrokerFor returns the ID of the broker to select for a user which never used any broker, or an empty string
+// if the user should choose one.
+//
+// The default broker is the one requested by the PAM module if any, or the one configured for the machine otherwise.
+// It's not selected if the user doesn't match the usernames it publishes, or if they match the usernames of another
+// broker.
+func (s Service) defaultBrokerFor(ctx context.Context, req *authd.GPBRequest) string {
+	name := s.defaultBroker
+	if req.DefaultBroker != nil {
+		name = req.GetDefaultBroker()
+	}
+	if name == "" {
+		return ""
+	}
+
+	var defaultBroker *brokers.Broker
+	for _, b := range s.brokerManager.AvailableBrokers() {
+		if b.ID == name || strings.EqualFold(b.Name, name) {
+			defaultBroker = b
+			break
+		}
+	}
+	if defaultBroker == nil {
+		log.Warningf(ctx, "Default broker %q is not available", name)
+		return ""
+	}
+
+	username := req.GetUsername()
+	if !defaultBroker.MayOwnUser(username) {
+		log.Debugf(ctx, "User %q doesn't match the usernames of the default broker %q", username, defaultBroker.Name)
+		return ""
+	}
+	for _, b := range s.brokerManager.AvailableBrokers() {
+		if b.ID != defaultBroker.ID && b.ClaimsUser(username) {
+			log.Debugf(ctx, "User %q matches the usernames of broker %q, not selecting the default one", username, b.Name)
+			return ""
+		}
+	}
+
+	log.Debugf(ctx, "Selecting default broker %q for user %q", defaultBroker.Name, username)
+	return defaultBroker.ID
+}
@@ -0,0 +1,28 @@
+package pam
+
+import (
+	"time"
+
+	"github.com/ubuntu/authd/internal/clock"
+)
+
+// WithFaillockConfig overrides the configuration file of pam_faillock setting the directory of the tally files.
+func WithFaillockConfig(path string) Option {
+	return func(o *options) {
+		o.faillockConfigPath = path
+	}
+}
+
+// WithAuthenticationSlotWaitTimeout overrides the time an authentication request waits for an available slot.
+func WithAuthenticationSlotWaitTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.authenticationSlotWaitTimeout = timeout
+	}
+}
+
+// WithClock overrides the clock used to get the current time and wait for timeouts.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
@@ -0,0 +1,44 @@
+package pam
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/internal/users/gecos"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// SetUserGecos changes the subfields of the GECOS field of a user who already logged in with their broker. The
+// values provided by the broker take precedence over them on the next login.
+func (s Service) SetUserGecos(ctx context.Context, req *authd.SUGRequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't set GECOS of user")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+
+	update := gecos.Update{
+		FullName:  req.FullName,
+		Room:      req.Room,
+		WorkPhone: req.WorkPhone,
+		HomePhone: req.HomePhone,
+	}
+	if err := update.Validate(); err != nil {
+		return nil, authderrors.Wrap(authderrors.InvalidArgument, err)
+	}
+
+	err = s.userManager.UpdateGecos(req.GetUsername(), update)
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q never logged in with authd", req.GetUsername())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "GECOS of user %q changed", req.GetUsername())
+	return &authd.Empty{}, nil
+}
@@ -0,0 +1,136 @@
+package pam
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/compat"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// AuthenticateHeadless authenticates a user without any user interaction, for example a service account used by a
+// system service. The secret is sent to the first form of the broker which accepts it, and the user is added to the
+// database on success, like with any other authentication. The access is only granted if the login policy allows the
+// user to log in on this machine.
+func (s Service) AuthenticateHeadless(ctx context.Context, req *authd.AHRequest) (resp *authd.IAResponse, err error) {
+	defer decorate.OnError(&err, "can't authenticate %q without user interaction", req.GetUsername())
+
+	if req.GetSecret() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no secret provided")
+	}
+	// The daemon encrypts the secret itself, so it supports all the features of the protocol whatever the client.
+	ctx = compat.AsCurrentClient(ctx)
+
+	sbResp, err := s.SelectBroker(ctx, &authd.SBRequest{
+		BrokerId: req.GetBrokerId(),
+		Username: req.GetUsername(),
+		Mode:     authd.SessionMode_LOGIN,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sessionID := sbResp.GetSessionId()
+	defer func() {
+		if _, err := s.EndSession(context.WithoutCancel(ctx), &authd.ESRequest{SessionId: sessionID}); err != nil {
+			log.Warningf(ctx, "%s: Could not end session: %v", sessionID, err)
+		}
+	}()
+
+	gamResp, err := s.GetAuthenticationModes(ctx, &authd.GAMRequest{
+		SessionId:          sessionID,
+		SupportedUiLayouts: headlessUILayouts(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	authModeID, err := headlessAuthMode(gamResp.GetAuthenticationModes(), req.GetAuthenticationModeId())
+	if err != nil {
+		return nil, err
+	}
+
+	samResp, err := s.SelectAuthenticationMode(ctx, &authd.SAMRequest{
+		SessionId:            sessionID,
+		AuthenticationModeId: authModeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if layout := samResp.GetUiLayoutInfo(); layout.GetType() != layouts.Form || layout.GetEntry() == "" {
+		return nil, authderrors.Errorf(authderrors.InvalidArgument, "authentication mode %q requires user interaction", authModeID)
+	}
+
+	key, err := encryption.NewPublicKey(sbResp.GetEncryptionAlgorithm(), sbResp.GetEncryptionKey())
+	if err != nil {
+		return nil, err
+	}
+	secret, err := key.Encrypt(req.GetSecret())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err = s.IsAuthenticated(ctx, &authd.IARequest{
+		SessionId: sessionID,
+		AuthenticationData: &authd.IARequest_AuthenticationData{
+			Item: &authd.IARequest_AuthenticationData_Challenge{Challenge: secret},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetAccess() == auth.Next {
+		return nil, errors.New("the broker requires additional authentication steps, which need user interaction")
+	}
+	if resp.GetAccess() == auth.Granted {
+		// The headless authentications don't go through the account stage of the PAM module, which enforces the login
+		// policy and the roles required by the tags of the machine.
+		if _, err := s.CheckLoginPolicy(ctx, &authd.CLPRequest{Username: req.GetUsername()}); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Infof(ctx, "%s: Headless authentication of %q with mode %q: %s", sessionID, req.GetUsername(), authModeID, resp.GetAccess())
+	return resp, nil
+}
+
+// headlessUILayouts returns the UI layouts supported without user interaction: forms to send a secret to.
+func headlessUILayouts() []*authd.UILayout {
+	required, optional := layouts.Required, layouts.Optional
+	supportedEntries := layouts.OptionalItems(
+		entries.Chars,
+		entries.CharsPassword,
+		entries.Digits,
+		entries.DigitsPassword,
+	)
+
+	return []*authd.UILayout{
+		{
+			Type:   layouts.Form,
+			Label:  &required,
+			Entry:  &supportedEntries,
+			Button: &optional,
+		},
+	}
+}
+
+// headlessAuthMode returns the requested authentication mode if the broker offers it, or the first offered one if
+// none was requested.
+func headlessAuthMode(authModes []*authd.GAMResponse_AuthenticationMode, requested string) (string, error) {
+	if len(authModes) == 0 {
+		return "", authderrors.New(authderrors.NotFound, "the broker offers no authentication mode usable without user interaction")
+	}
+	if requested == "" {
+		return authModes[0].GetId(), nil
+	}
+	if !slices.ContainsFunc(authModes, func(a *authd.GAMResponse_AuthenticationMode) bool { return a.GetId() == requested }) {
+		return "", authderrors.Errorf(authderrors.NotFound, "authentication mode %q is not available without user interaction", requested)
+	}
+	return requested, nil
+}
@@ -0,0 +1,51 @@
+package pam
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// SetUserHome sets the home directory of a user who already logged in with their broker. It takes precedence over the
+// one provided by the broker. The content of the previous home directory is not moved by the daemon: the request can
+// be validated first, so that the client moves it before the home directory changes.
+func (s Service) SetUserHome(ctx context.Context, req *authd.SUHRequest) (resp *authd.SUHResponse, err error) {
+	defer decorate.OnError(&err, "can't set home directory of user")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+	if req.GetHome() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no home directory given")
+	}
+	home, err := users.SanitizePath(req.GetHome())
+	if err != nil {
+		return nil, authderrors.Errorf(authderrors.InvalidArgument, "home directory %q %v", req.GetHome(), err)
+	}
+
+	u, err := s.userManager.UserByName(req.GetUsername())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q never logged in with authd", req.GetUsername())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &authd.SUHResponse{PreviousHome: u.Dir, Uid: u.UID, Gid: u.GID}
+	if req.GetValidateOnly() {
+		return resp, nil
+	}
+
+	// The override updates the entries returned by NSS and notifies the change, like the other attributes set locally.
+	if err := s.updateUserOverride(req.GetUsername(), func(o *users.UserOverride) { o.Dir = home }); err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "Home directory of user %q changed from %q to %q", req.GetUsername(), u.Dir, home)
+	return resp, nil
+}
@@ -0,0 +1,67 @@
+package pam
+
+import (
+	"context"
+	"os/user"
+	"slices"
+
+	"github.com/ubuntu/authd/log"
+)
+
+// LocalFallbackPolicy defines which users are not handled by authd, so that the PAM module ignores them and lets the
+// next modules of the stack (for example pam_unix) authenticate them. This allows to enable authd for only some users.
+type LocalFallbackPolicy struct {
+	// Users is the list of user names falling back to the local authentication.
+	Users []string `mapstructure:"users"`
+	// Groups is the list of group names whose members fall back to the local authentication.
+	Groups []string `mapstructure:"groups"`
+}
+
+// fallbackToLocal returns whether the user is covered by the local fallback policy.
+func (s Service) fallbackToLocal(ctx context.Context, username string) bool {
+	p := s.localFallbackPolicy
+	if slices.Contains(p.Users, username) {
+		log.Debugf(ctx, "User %q falls back to local authentication", username)
+		return true
+	}
+
+	if g, ok := s.memberOfAny(ctx, username, p.Groups); ok {
+		log.Debugf(ctx, "User %q falls back to local authentication as member of group %q", username, g)
+		return true
+	}
+	return false
+}
+
+// memberOfAny returns the first of the groups the user is a member of, if any.
+func (s Service) memberOfAny(ctx context.Context, username string, groups []string) (string, bool) {
+	for _, g := range groups {
+		// Check the groups managed by authd first, as their members may not be resolvable through NSS yet.
+		if group, err := s.userManager.GroupByName(g); err == nil && slices.Contains(group.Users, username) {
+			return g, true
+		}
+	}
+	if len(groups) == 0 {
+		return "", false
+	}
+
+	u, err := user.Lookup(username)
+	if err != nil {
+		return "", false
+	}
+	gids, err := u.GroupIds()
+	if err != nil {
+		log.Warningf(ctx, "Could not get the groups of user %q: %v", username, err)
+		return "", false
+	}
+	for _, gid := range gids {
+		g, err := user.LookupGroupId(gid)
+		if err != nil {
+			continue
+		}
+		if slices.Contains(groups, g.Name) {
+			return g.Name, true
+		}
+	}
+
+	return "", false
+}
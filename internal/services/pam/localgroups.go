@@ -0,0 +1,27 @@
+package pam
+
+import (
+	"context"
+
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// ReconcileLocalGroups adds the users of authd back to the local groups they were removed from by manual edits of the
+// group file.
+func (s Service) ReconcileLocalGroups(ctx context.Context, _ *authd.Empty) (resp *authd.RLGResponse, err error) {
+	defer decorate.OnError(&err, "can't reconcile local groups")
+
+	reconciled, err := s.userManager.ReconcileLocalGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &authd.RLGResponse{}
+	for _, u := range reconciled {
+		log.Noticef(ctx, "User %q added back to local groups %v", u.Name, u.AddedGroups)
+		resp.Users = append(resp.Users, &authd.RLGResponse_User{Name: u.Name, AddedGroups: u.AddedGroups})
+	}
+	return resp, nil
+}
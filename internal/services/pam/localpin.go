@@ -0,0 +1,232 @@
+package pam
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/clock"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/permissions"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/internal/users/localpin"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// defaultLocalPINMinLength is the minimum number of digits of a local PIN, if the policy doesn't set one.
+	defaultLocalPINMinLength = 6
+	// defaultLocalPINMaxAttempts is the number of failed attempts locking a local PIN, if the policy doesn't set one.
+	defaultLocalPINMaxAttempts = 5
+	// defaultLocalPINSetWithin is how long after logging in with their broker the users can set their local PIN, if the
+	// policy doesn't set it.
+	defaultLocalPINSetWithin = 15 * time.Minute
+)
+
+// LocalPINPolicy defines which PAM services accept the machine-local PINs the users registered after logging in with
+// their broker, for example to unlock the screen or to use sudo without reaching the identity provider.
+type LocalPINPolicy struct {
+	// Services is the list of PAM services accepting the local PINs. The local PINs are disabled if it's empty.
+	Services []string `mapstructure:"services"`
+	// MinLength is the minimum number of digits of a local PIN.
+	MinLength int `mapstructure:"min_length"`
+	// MaxAttempts is the number of failed attempts after which a local PIN is locked, until the user authenticates
+	// again with their broker.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// SetWithin is how long after logging in with their broker the users can set their local PIN.
+	SetWithin time.Duration `mapstructure:"set_within"`
+}
+
+// withDefaults returns the policy with the default values for its unset limits.
+func (p LocalPINPolicy) withDefaults() LocalPINPolicy {
+	if p.MinLength <= 0 {
+		p.MinLength = defaultLocalPINMinLength
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultLocalPINMaxAttempts
+	}
+	if p.SetWithin <= 0 {
+		p.SetWithin = defaultLocalPINSetWithin
+	}
+	return p
+}
+
+// enabled returns whether the local PINs are accepted by any service.
+func (p LocalPINPolicy) enabled() bool {
+	return len(p.Services) > 0
+}
+
+// brokerLogins tracks when the users last logged in with their broker, as they can only set their local PIN shortly
+// after.
+type brokerLogins struct {
+	clock clock.Clock
+
+	last map[string]time.Time
+	mu   sync.Mutex
+}
+
+func newBrokerLogins(c clock.Clock) *brokerLogins {
+	return &brokerLogins{clock: c, last: make(map[string]time.Time)}
+}
+
+// record records that the user just logged in with their broker.
+func (b *brokerLogins) record(username string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last[username] = b.clock.Now()
+}
+
+// within returns whether the user logged in with their broker during the last given duration.
+func (b *brokerLogins) within(username string, d time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	last, ok := b.last[username]
+	if !ok {
+		return false
+	}
+	if b.clock.Now().Sub(last) > d {
+		delete(b.last, username)
+		return false
+	}
+	return true
+}
+
+// GetLocalPINStatus returns whether the user registered a local PIN, and whether it can be used for the given service.
+func (s Service) GetLocalPINStatus(ctx context.Context, req *authd.GLPSRequest) (resp *authd.GLPSResponse, err error) {
+	defer decorate.OnError(&err, "can't get local PIN status")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+
+	attempts, err := s.userManager.LocalPINFailedAttempts(req.GetUsername())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return &authd.GLPSResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &authd.GLPSResponse{
+		Registered:     true,
+		Usable:         slices.Contains(s.localPINPolicy.Services, req.GetService()) && attempts < s.localPINPolicy.MaxAttempts,
+		FailedAttempts: uint32(attempts),
+	}, nil
+}
+
+// AuthenticateWithLocalPIN authenticates the user to the given service with their local PIN, if the policy allows it.
+func (s Service) AuthenticateWithLocalPIN(ctx context.Context, req *authd.ALPRequest) (resp *authd.ALPResponse, err error) {
+	defer decorate.OnError(&err, "can't authenticate with local PIN")
+
+	username := req.GetUsername()
+	if username == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+	if req.GetService() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no PAM service given")
+	}
+	if !slices.Contains(s.localPINPolicy.Services, req.GetService()) {
+		return nil, authderrors.Errorf(authderrors.PermissionDenied, "local PINs are not allowed for service %q", req.GetService())
+	}
+	if err := s.shutdown.check(); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.userManager.CheckLocalPIN(username, req.GetPin(), s.localPINPolicy.MaxAttempts)
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q has no local PIN", username)
+	}
+	if errors.Is(err, users.ErrLocalPINLocked) {
+		log.Noticef(ctx, "Refused local PIN of user %q for service %q: locked after too many failed attempts", username, req.GetService())
+		return &authd.ALPResponse{Msg: "Too many failed attempts, authenticate with your identity provider to unlock your PIN"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		log.Noticef(ctx, "Refused invalid local PIN of user %q for service %q", username, req.GetService())
+		return &authd.ALPResponse{Msg: "Invalid PIN"}, nil
+	}
+
+	log.Infof(ctx, "User %q authenticated to service %q with their local PIN", username, req.GetService())
+	return &authd.ALPResponse{Granted: true}, nil
+}
+
+// SetLocalPIN registers the local PIN of the user performing the request, who must have recently logged in with their
+// broker. Not even root can set the PIN of another user, as it would let them authenticate as that user.
+func (s Service) SetLocalPIN(ctx context.Context, req *authd.SLPRequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't set local PIN")
+
+	if !s.localPINPolicy.enabled() {
+		return nil, authderrors.ErrDisabled.Errorf("local PINs are not enabled")
+	}
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+
+	u, err := s.userManager.UserByName(req.GetUsername())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q never logged in with authd", req.GetUsername())
+	}
+	if err != nil {
+		return nil, err
+	}
+	uid, err := permissions.PeerUID(ctx)
+	if err != nil {
+		return nil, authderrors.Wrap(authderrors.PermissionDenied, err)
+	}
+	if uid != u.UID {
+		return nil, authderrors.Errorf(authderrors.PermissionDenied, "only user %q can set their local PIN", req.GetUsername())
+	}
+	if !s.brokerLogins.within(req.GetUsername(), s.localPINPolicy.SetWithin) {
+		return nil, authderrors.Errorf(authderrors.PermissionDenied,
+			"user %q must log in with their broker again before setting their local PIN", req.GetUsername())
+	}
+
+	if err := localpin.Validate(req.GetPin(), s.localPINPolicy.MinLength); err != nil {
+		return nil, authderrors.Wrap(authderrors.InvalidArgument, err)
+	}
+
+	err = s.userManager.SetLocalPIN(req.GetUsername(), req.GetPin())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q never logged in with authd", req.GetUsername())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "Local PIN of user %q set", req.GetUsername())
+	return &authd.Empty{}, nil
+}
+
+// RemoveLocalPIN removes the local PIN of a user.
+func (s Service) RemoveLocalPIN(ctx context.Context, req *authd.RLPRequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't remove local PIN")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+
+	err = s.userManager.RemoveLocalPIN(req.GetUsername())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q has no local PIN", req.GetUsername())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "Local PIN of user %q removed", req.GetUsername())
+	return &authd.Empty{}, nil
+}
+
+// resetLocalPINFailedAttempts unlocks the local PIN of the user, after they logged in with their broker.
+func (s Service) resetLocalPINFailedAttempts(ctx context.Context, username string) {
+	if err := s.userManager.ResetLocalPINFailedAttempts(username); err != nil {
+		log.Warningf(ctx, "Could not reset the local PIN failed attempts of user %q: %v", username, err)
+	}
+}
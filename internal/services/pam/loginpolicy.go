@@ -0,0 +1,101 @@
+package pam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// LoginPolicy defines which of the users handled by authd may log in on this machine, so that shared workstations
+// can be restricted to some of the users of the brokers. It's enforced by the PAM module in the account stage, and
+// doesn't apply to the local users.
+type LoginPolicy struct {
+	// AllowedUsers is the list of user names allowed to log in. If it and AllowedGroups are empty, all users are
+	// allowed to log in.
+	AllowedUsers []string `mapstructure:"allowed_users"`
+	// AllowedGroups is the list of group names whose members are allowed to log in.
+	AllowedGroups []string `mapstructure:"allowed_groups"`
+	// DenyUsers is the list of user names not allowed to log in, even if they are allowed by the other lists.
+	DenyUsers []string `mapstructure:"deny_users"`
+	// MachineTags are the tags of this machine, like "dev-laptops". If set, only the users whose broker gave them a
+	// role named after one of the tags may log in, in addition to being allowed by the lists above. The roles of the
+	// last login are used, so that the policy is also enforced when the broker can't be reached.
+	MachineTags []string `mapstructure:"machine_tags"`
+}
+
+// CheckLoginPolicy returns a PermissionDenied error if the login policy doesn't allow the user handled by authd to
+// log in on this machine.
+func (s Service) CheckLoginPolicy(ctx context.Context, req *authd.CLPRequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't check login policy")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+
+	u, err := s.userManager.UserByName(req.GetUsername())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q is not handled by authd", req.GetUsername())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if reason, allowed := s.hasMachineRole(ctx, u.Name); !allowed {
+		log.Noticef(ctx, "Audit: refused login of user %q: %s", u.Name, reason)
+		return nil, authderrors.ErrMissingRole.Errorf("user %q doesn't have any of the roles required to log in on this machine", u.Name)
+	}
+	if reason, allowed := s.loginAllowed(ctx, u.Name); !allowed {
+		log.Noticef(ctx, "Audit: refused login of user %q: %s", u.Name, reason)
+		return nil, authderrors.Errorf(authderrors.PermissionDenied, "user %q is not allowed to log in on this machine", u.Name)
+	}
+
+	return &authd.Empty{}, nil
+}
+
+// hasMachineRole returns whether the user has a role matching one of the tags of the machine, if it has any, or the
+// reason why not.
+func (s Service) hasMachineRole(ctx context.Context, username string) (reason string, allowed bool) {
+	tags := s.loginPolicy.MachineTags
+	if len(tags) == 0 {
+		return "", true
+	}
+
+	roles, err := s.userManager.RolesForUser(username)
+	if err != nil {
+		return fmt.Sprintf("could not get the roles of the user: %v", err), false
+	}
+	for _, role := range roles {
+		if slices.Contains(tags, role) {
+			log.Debugf(ctx, "User %q is allowed to log in with role %q", username, role)
+			return "", true
+		}
+	}
+	return fmt.Sprintf("the user has none of the roles %v required by the tags of the machine", tags), false
+}
+
+// loginAllowed returns whether the login policy allows the user to log in, or the reason why not.
+func (s Service) loginAllowed(ctx context.Context, username string) (reason string, allowed bool) {
+	p := s.loginPolicy
+	if slices.Contains(p.DenyUsers, username) {
+		return "the user is denied by the login policy", false
+	}
+	if len(p.AllowedUsers) == 0 && len(p.AllowedGroups) == 0 {
+		return "", true
+	}
+
+	if slices.Contains(p.AllowedUsers, username) {
+		return "", true
+	}
+	if g, ok := s.memberOfAny(ctx, username, p.AllowedGroups); ok {
+		log.Debugf(ctx, "User %q is allowed to log in as member of group %q", username, g)
+		return "", true
+	}
+	return "the user is neither in the allowed users nor a member of the allowed groups", false
+}
@@ -0,0 +1,112 @@
+package pam
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// SetUserOverride sets attributes of a user locally, taking precedence over the ones provided by their broker.
+func (s Service) SetUserOverride(ctx context.Context, req *authd.SUORequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't set override of user")
+
+	if req.Shell == nil && req.Home == nil && req.Gecos == nil && len(req.GetAddLocalGroups()) == 0 {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no attribute to override given")
+	}
+
+	err = s.updateUserOverride(req.GetUsername(), func(o *users.UserOverride) {
+		if req.Shell != nil {
+			o.Shell = req.GetShell()
+		}
+		if req.Home != nil {
+			o.Dir = req.GetHome()
+		}
+		if req.Gecos != nil {
+			o.Gecos = req.GetGecos()
+		}
+		o.LocalGroups = append(o.LocalGroups, req.GetAddLocalGroups()...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "Override of user %q set", req.GetUsername())
+	return &authd.Empty{}, nil
+}
+
+// UnsetUserOverride removes attributes of a user set locally, restoring the ones provided by their broker.
+func (s Service) UnsetUserOverride(ctx context.Context, req *authd.UUORequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't unset override of user")
+
+	err = s.updateUserOverride(req.GetUsername(), func(o *users.UserOverride) {
+		if !req.GetShell() && !req.GetHome() && !req.GetGecos() && len(req.GetRemoveLocalGroups()) == 0 {
+			*o = users.UserOverride{}
+			return
+		}
+		if req.GetShell() {
+			o.Shell = ""
+		}
+		if req.GetHome() {
+			o.Dir = ""
+		}
+		if req.GetGecos() {
+			o.Gecos = ""
+		}
+		o.LocalGroups = slices.DeleteFunc(o.LocalGroups, func(g string) bool {
+			return slices.Contains(req.GetRemoveLocalGroups(), g)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "Override of user %q unset", req.GetUsername())
+	return &authd.Empty{}, nil
+}
+
+// ListUserOverrides returns the attributes of the users set locally, sorted by user name.
+func (s Service) ListUserOverrides(ctx context.Context, _ *authd.Empty) (resp *authd.LUOResponse, err error) {
+	defer decorate.OnError(&err, "can't list user overrides")
+
+	overrides, err := s.userManager.UserOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &authd.LUOResponse{}
+	for name, o := range overrides {
+		resp.Overrides = append(resp.Overrides, &authd.UserOverride{
+			Username:    name,
+			Shell:       o.Shell,
+			Home:        o.Dir,
+			Gecos:       o.Gecos,
+			LocalGroups: o.LocalGroups,
+		})
+	}
+	sort.Slice(resp.Overrides, func(i, j int) bool { return resp.Overrides[i].Username < resp.Overrides[j].Username })
+
+	return resp, nil
+}
+
+// updateUserOverride updates the override of the user, returning errors with the codes of the daemon.
+func (s Service) updateUserOverride(username string, update func(o *users.UserOverride)) error {
+	if username == "" {
+		return authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+
+	err := s.userManager.UpdateUserOverride(username, update)
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return authderrors.Errorf(authderrors.NotFound, "user %q never logged in with authd", username)
+	}
+	if errors.Is(err, users.ErrInvalidOverride) {
+		return authderrors.Wrap(authderrors.InvalidArgument, err)
+	}
+	return err
+}
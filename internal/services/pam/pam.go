@@ -7,40 +7,211 @@ import (
 	"errors"
 	"fmt"
 	"os/user"
+	"slices"
+	"time"
 
+	"github.com/ubuntu/authd/internal/authderrors"
 	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/clock"
+	"github.com/ubuntu/authd/internal/consts"
+	"github.com/ubuntu/authd/internal/debugserver"
+	"github.com/ubuntu/authd/internal/faillock"
 	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/compat"
 	"github.com/ubuntu/authd/internal/services/permissions"
+	"github.com/ubuntu/authd/internal/tracing"
 	"github.com/ubuntu/authd/internal/users"
 	"github.com/ubuntu/authd/internal/users/types"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
 var _ authd.PAMServer = Service{}
 
+// defaultAuthenticationSlotWaitTimeout is the maximum time an authentication request waits for an available slot
+// before the client is asked to try again.
+const defaultAuthenticationSlotWaitTimeout = 5 * time.Second
+
 // Service is the implementation of the PAM module service.
 type Service struct {
 	userManager       *users.Manager
 	brokerManager     *brokers.Manager
 	permissionManager *permissions.Manager
 
+	// authenticationSlots limits the number of concurrent authentications, it's nil if there's no limit.
+	authenticationSlots           chan struct{}
+	authenticationSlotWaitTimeout time.Duration
+	clock                         clock.Clock
+
+	sessions              *sessions
+	recentAuthentications *recentAuthentications
+	localFallbackPolicy   LocalFallbackPolicy
+	sessionPolicy         SessionPolicy
+	loginPolicy           LoginPolicy
+	consentPolicy         ConsentPolicy
+	stepUpPolicy          StepUpPolicy
+	localPINPolicy        LocalPINPolicy
+	brokerLogins          *brokerLogins
+	bruteForce            *bruteForceProtection
+	defaultBroker         string
+	debugServer           *debugserver.Server
+	shutdown              *shutdown
+
 	authd.UnimplementedPAMServer
 }
 
+type options struct {
+	maxConcurrentAuthentications  int
+	authenticationSlotWaitTimeout time.Duration
+	recentAuthenticationPolicy    RecentAuthenticationPolicy
+	localFallbackPolicy           LocalFallbackPolicy
+	sessionPolicy                 SessionPolicy
+	loginPolicy                   LoginPolicy
+	consentPolicy                 ConsentPolicy
+	stepUpPolicy                  StepUpPolicy
+	localPINPolicy                LocalPINPolicy
+	bruteForcePolicy              BruteForcePolicy
+	faillockConfigPath            string
+	defaultBroker                 string
+	debugServer                   *debugserver.Server
+	clock                         clock.Clock
+}
+
+// Option is the function signature used to tweak the service creation.
+type Option func(*options)
+
+// WithMaxConcurrentAuthentications limits the number of authentications that can be handled by the brokers at the
+// same time. A value of 0 or less means no limit.
+func WithMaxConcurrentAuthentications(n int) Option {
+	return func(o *options) {
+		o.maxConcurrentAuthentications = n
+	}
+}
+
+// WithRecentAuthenticationPolicy allows the services of the policy to authenticate a user without prompting
+// them again, if they recently authenticated with a strong authentication mode.
+func WithRecentAuthenticationPolicy(policy RecentAuthenticationPolicy) Option {
+	return func(o *options) {
+		o.recentAuthenticationPolicy = policy
+	}
+}
+
+// WithLocalFallbackPolicy makes the users of the policy fall back to the local authentication, as if they were
+// not handled by authd.
+func WithLocalFallbackPolicy(policy LocalFallbackPolicy) Option {
+	return func(o *options) {
+		o.localFallbackPolicy = policy
+	}
+}
+
+// WithSessionPolicy defines the actions performed by the PAM module when opening the sessions of the users.
+func WithSessionPolicy(policy SessionPolicy) Option {
+	return func(o *options) {
+		o.sessionPolicy = policy
+	}
+}
+
+// WithLoginPolicy restricts which of the users handled by authd may log in on this machine.
+func WithLoginPolicy(policy LoginPolicy) Option {
+	return func(o *options) {
+		o.loginPolicy = policy
+	}
+}
+
+// WithConsentPolicy makes the users accept the message of the policy on their first login.
+func WithConsentPolicy(policy ConsentPolicy) Option {
+	return func(o *options) {
+		o.consentPolicy = policy
+	}
+}
+
+// WithStepUpPolicy makes the services of the policy re-authenticate the users who already logged in with step-up
+// sessions.
+func WithStepUpPolicy(policy StepUpPolicy) Option {
+	return func(o *options) {
+		o.stepUpPolicy = policy
+	}
+}
+
+// WithLocalPINPolicy allows the users to register a machine-local PIN, accepted by the services of the policy.
+func WithLocalPINPolicy(policy LocalPINPolicy) Option {
+	return func(o *options) {
+		o.localPINPolicy = policy
+	}
+}
+
+// WithBruteForcePolicy refuses the authentications of the users for a while after too many failures, whatever the PAM
+// services they went through.
+func WithBruteForcePolicy(policy BruteForcePolicy) Option {
+	return func(o *options) {
+		o.bruteForcePolicy = policy
+	}
+}
+
+// WithDefaultBroker selects the broker with the given name or ID for the users which never used any broker, instead
+// of letting them choose one.
+func WithDefaultBroker(broker string) Option {
+	return func(o *options) {
+		o.defaultBroker = broker
+	}
+}
+
+// WithDebugServer selects the debug server enabled and disabled by SetDebugSocket, instead of the one listening on the
+// default debug socket.
+func WithDebugServer(server *debugserver.Server) Option {
+	return func(o *options) {
+		o.debugServer = server
+	}
+}
+
 // NewService returns a new PAM GRPC service.
-func NewService(ctx context.Context, userManager *users.Manager, brokerManager *brokers.Manager, permissionManager *permissions.Manager) Service {
+func NewService(ctx context.Context, userManager *users.Manager, brokerManager *brokers.Manager, permissionManager *permissions.Manager, args ...Option) Service {
 	log.Debug(ctx, "Building new gRPC PAM service")
 
+	opts := options{
+		authenticationSlotWaitTimeout: defaultAuthenticationSlotWaitTimeout,
+		clock:                         clock.Default(),
+		faillockConfigPath:            faillock.DefaultConfigPath,
+	}
+	for _, f := range args {
+		f(&opts)
+	}
+	if opts.debugServer == nil {
+		opts.debugServer = debugserver.New(consts.DefaultDebugSocketPath)
+	}
+
+	var authenticationSlots chan struct{}
+	if opts.maxConcurrentAuthentications > 0 {
+		log.Debugf(ctx, "Limiting concurrent authentications to %d", opts.maxConcurrentAuthentications)
+		authenticationSlots = make(chan struct{}, opts.maxConcurrentAuthentications)
+	}
+
 	return Service{
-		userManager:       userManager,
-		brokerManager:     brokerManager,
-		permissionManager: permissionManager,
+		userManager:         userManager,
+		brokerManager:       brokerManager,
+		permissionManager:   permissionManager,
+		authenticationSlots: authenticationSlots,
+
+		authenticationSlotWaitTimeout: opts.authenticationSlotWaitTimeout,
+		clock:                         opts.clock,
+		sessions:                      newSessions(),
+		recentAuthentications:         newRecentAuthentications(opts.recentAuthenticationPolicy, opts.clock),
+		localFallbackPolicy:           opts.localFallbackPolicy,
+		sessionPolicy:                 opts.sessionPolicy,
+		loginPolicy:                   opts.loginPolicy,
+		consentPolicy:                 opts.consentPolicy,
+		stepUpPolicy:                  opts.stepUpPolicy,
+		localPINPolicy:                opts.localPINPolicy.withDefaults(),
+		brokerLogins:                  newBrokerLogins(opts.clock),
+		bruteForce:                    newBruteForceProtection(ctx, opts.bruteForcePolicy, opts.clock, opts.faillockConfigPath),
+		defaultBroker:                 opts.defaultBroker,
+		debugServer:                   opts.debugServer,
+		shutdown:                      &shutdown{},
 	}
 }
 
@@ -61,7 +232,23 @@ func (s Service) AvailableBrokers(ctx context.Context, _ *authd.Empty) (*authd.A
 
 // GetPreviousBroker returns the previous broker set for a given user, if any.
 // If the user is not in our cache/database, it will try to check if it’s on the system, and return then "local".
+// The users of the local fallback policy always get "local", so that the PAM module ignores them.
 func (s Service) GetPreviousBroker(ctx context.Context, req *authd.GPBRequest) (*authd.GPBResponse, error) {
+	if s.fallbackToLocal(ctx, req.GetUsername()) {
+		return &authd.GPBResponse{PreviousBroker: brokers.LocalBrokerName}, nil
+	}
+
+	// Let the user select their broker again once their selection expired.
+	expired, err := s.userManager.BrokerSelectionExpired(req.GetUsername())
+	if err != nil {
+		log.Warningf(ctx, "Could not check if the broker selection of user %q expired: %v", req.GetUsername(), err)
+	}
+	if expired {
+		log.Infof(ctx, "Broker selection of user %q expired, letting the user select a new one", req.GetUsername())
+		s.brokerManager.UnsetDefaultBrokerForUser(req.GetUsername())
+		return &authd.GPBResponse{}, nil
+	}
+
 	// Use in memory cache first
 	if b := s.brokerManager.BrokerForUser(req.GetUsername()); b != nil {
 		return &authd.GPBResponse{PreviousBroker: b.ID}, nil
@@ -82,7 +269,7 @@ func (s Service) GetPreviousBroker(ctx context.Context, req *authd.GPBRequest) (
 		// User not accessible through NSS, first time login or no valid user. Anyway, no broker selected.
 		if _, err := user.Lookup(req.GetUsername()); err != nil {
 			log.Debugf(ctx, "User %q is unknown", req.GetUsername())
-			return &authd.GPBResponse{}, nil
+			return &authd.GPBResponse{PreviousBroker: s.defaultBrokerFor(ctx, req)}, nil
 		}
 
 		// We could resolve the user through NSS, which means then that another non authd service
@@ -96,7 +283,7 @@ func (s Service) GetPreviousBroker(ctx context.Context, req *authd.GPBRequest) (
 	// No error but the brokerID is empty (broker in database but default broker not stored yet due no successful login)
 	if brokerID == "" {
 		log.Infof(ctx, "No assigned broker for user %q from database", req.GetUsername())
-		return &authd.GPBResponse{}, nil
+		return &authd.GPBResponse{PreviousBroker: s.defaultBrokerFor(ctx, req)}, nil
 	}
 
 	if !s.brokerManager.BrokerExists(brokerID) {
@@ -129,14 +316,17 @@ func (s Service) SelectBroker(ctx context.Context, req *authd.SBRequest) (resp *
 	lang := req.GetLang()
 
 	if username == "" {
-		return nil, status.Error(codes.InvalidArgument, "no user name provided")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name provided")
 	}
 	if brokerID == "" {
-		return nil, status.Error(codes.InvalidArgument, "no broker selected")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no broker selected")
 	}
 	if lang == "" {
 		lang = "C"
 	}
+	if err := s.shutdown.check(); err != nil {
+		return nil, err
+	}
 
 	var mode string
 	switch req.GetMode() {
@@ -145,18 +335,38 @@ func (s Service) SelectBroker(ctx context.Context, req *authd.SBRequest) (resp *
 	case authd.SessionMode_CHANGE_PASSWORD:
 		mode = auth.SessionModeChangePassword
 	default:
-		return nil, status.Error(codes.InvalidArgument, "invalid session mode")
+		return nil, authderrors.New(authderrors.InvalidArgument, "invalid session mode")
+	}
+	if mode == auth.SessionModeLogin && s.stepUpRequested(ctx, username, brokerID, req.GetService()) {
+		log.Infof(ctx, "Starting a step-up session of user %q for service %q", username, req.GetService())
+		mode = auth.SessionModeStepUp
 	}
 
 	// Create a session and Memorize selected broker for it.
-	sessionID, encryptionKey, err := s.brokerManager.NewSession(brokerID, username, lang, mode)
+	sessionID, encryptionKey, err := s.brokerManager.NewSession(ctx, brokerID, username, lang, mode)
 	if err != nil {
 		return nil, err
 	}
+	// The algorithm was validated when the session was created.
+	algorithm, encryptionKey, err := encryption.ParseKey(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	// Older clients always encrypt the secrets with the default algorithm, which the broker would fail to decrypt.
+	if algorithm != encryption.DefaultAlgorithm {
+		if err := compat.Require(ctx, compat.EncryptionAlgorithms, fmt.Sprintf("the broker requires the %s encryption algorithm", algorithm)); err != nil {
+			if err := s.brokerManager.EndSession(ctx, sessionID); err != nil {
+				log.Warningf(ctx, "Could not end session %q: %v", sessionID, err)
+			}
+			return nil, err
+		}
+	}
+	s.sessions.add(sessionID, username, brokerID, req.GetService(), req.GetTty(), mode)
 
 	return &authd.SBResponse{
-		SessionId:     sessionID,
-		EncryptionKey: encryptionKey,
+		SessionId:           sessionID,
+		EncryptionKey:       encryptionKey,
+		EncryptionAlgorithm: algorithm,
 	}, err
 }
 
@@ -166,7 +376,7 @@ func (s Service) GetAuthenticationModes(ctx context.Context, req *authd.GAMReque
 
 	sessionID := req.GetSessionId()
 	if sessionID == "" {
-		return nil, status.Error(codes.InvalidArgument, "no session ID provided")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no session ID provided")
 	}
 
 	broker, err := s.brokerManager.BrokerFromSessionID(sessionID)
@@ -174,6 +384,10 @@ func (s Service) GetAuthenticationModes(ctx context.Context, req *authd.GAMReque
 		return nil, err
 	}
 
+	if info, ok := s.sessions.get(sessionID); ok && info.consent != nil {
+		return consentAuthenticationModes(req.GetSupportedUiLayouts())
+	}
+
 	var supportedLayouts []map[string]string
 	for _, l := range req.GetSupportedUiLayouts() {
 		layout, err := uiLayoutToMap(l)
@@ -197,10 +411,36 @@ func (s Service) GetAuthenticationModes(ctx context.Context, req *authd.GAMReque
 	}
 
 	return &authd.GAMResponse{
-		AuthenticationModes: authModes,
+		AuthenticationModes:          authModes,
+		LastUsedAuthenticationModeId: s.lastUsedAuthMode(ctx, sessionID, authModes),
 	}, nil
 }
 
+// lastUsedAuthMode returns the authentication mode the user of the session last successfully authenticated with, if
+// it's part of the available authentication modes.
+func (s Service) lastUsedAuthMode(ctx context.Context, sessionID string, authModes []*authd.GAMResponse_AuthenticationMode) string {
+	info, ok := s.sessions.get(sessionID)
+	if !ok {
+		return ""
+	}
+
+	authMode, err := s.userManager.LastAuthModeForUser(info.username, info.brokerID)
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return ""
+	}
+	if err != nil {
+		log.Warningf(ctx, "Could not get last used authentication mode for user %q: %v", info.username, err)
+		return ""
+	}
+
+	if !slices.ContainsFunc(authModes, func(a *authd.GAMResponse_AuthenticationMode) bool { return a.GetId() == authMode }) {
+		log.Debugf(ctx, "%s: Last used authentication mode %q is not available anymore", sessionID, authMode)
+		return ""
+	}
+
+	return authMode
+}
+
 // SelectAuthenticationMode set given authentication mode as selected for this sessionID to the broker.
 func (s Service) SelectAuthenticationMode(ctx context.Context, req *authd.SAMRequest) (resp *authd.SAMResponse, err error) {
 	defer decorate.OnError(&err, "can't select authentication mode")
@@ -209,10 +449,10 @@ func (s Service) SelectAuthenticationMode(ctx context.Context, req *authd.SAMReq
 	authenticationModeID := req.GetAuthenticationModeId()
 
 	if sessionID == "" {
-		return nil, status.Error(codes.InvalidArgument, "no session ID provided")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no session ID provided")
 	}
 	if authenticationModeID == "" {
-		return nil, status.Error(codes.InvalidArgument, "no authentication mode provided")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no authentication mode provided")
 	}
 
 	broker, err := s.brokerManager.BrokerFromSessionID(sessionID)
@@ -220,10 +460,15 @@ func (s Service) SelectAuthenticationMode(ctx context.Context, req *authd.SAMReq
 		return nil, err
 	}
 
+	if info, ok := s.sessions.get(sessionID); ok && info.consent != nil {
+		return consentUILayout(*info.consent, authenticationModeID)
+	}
+
 	uiLayoutInfo, err := broker.SelectAuthenticationMode(ctx, sessionID, authenticationModeID)
 	if err != nil {
 		return nil, err
 	}
+	s.sessions.setAuthMode(sessionID, authenticationModeID)
 
 	return &authd.SAMResponse{
 		UiLayoutInfo: mapToUILayout(uiLayoutInfo),
@@ -236,7 +481,7 @@ func (s Service) IsAuthenticated(ctx context.Context, req *authd.IARequest) (res
 
 	sessionID := req.GetSessionId()
 	if sessionID == "" {
-		return nil, status.Error(codes.InvalidArgument, "no session ID provided")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no session ID provided")
 	}
 
 	broker, err := s.brokerManager.BrokerFromSessionID(sessionID)
@@ -244,23 +489,48 @@ func (s Service) IsAuthenticated(ctx context.Context, req *authd.IARequest) (res
 		return nil, err
 	}
 
+	info, ok := s.sessions.get(sessionID)
+	if ok && info.consent != nil {
+		return s.answerConsent(ctx, sessionID, *info.consent, req.GetAuthenticationData().GetConsent())
+	}
+	if ok {
+		release, err := s.bruteForce.check(ctx, info.username, info.service)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
 	authenticationDataJSON, err := protojson.Marshal(req.GetAuthenticationData())
 	if err != nil {
 		return nil, err
 	}
 
+	authenticationDone, err := s.shutdown.startAuthentication()
+	if err != nil {
+		return nil, err
+	}
+	defer authenticationDone()
+
+	release, err := s.waitForAuthenticationSlot(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	s.sessions.setStage(sessionID, stageAuthenticating)
 	access, data, err := broker.IsAuthenticated(ctx, sessionID, string(authenticationDataJSON))
+	s.sessions.setStage(sessionID, stageAuthModeSelected)
+	release()
 	if err != nil {
 		return nil, err
 	}
 
 	log.Debugf(ctx, "%s: Authentication result: %s", sessionID, access)
 
+	if ok && (access == auth.Denied || access == auth.Retry) {
+		s.bruteForce.failed(ctx, info.username, info.service)
+	}
 	if access != auth.Granted {
-		return &authd.IAResponse{
-			Access: access,
-			Msg:    data,
-		}, nil
+		return notGrantedResponse(ctx, sessionID, access, data)
 	}
 
 	var uInfo types.UserInfo
@@ -268,29 +538,123 @@ func (s Service) IsAuthenticated(ctx context.Context, req *authd.IARequest) (res
 		return nil, fmt.Errorf("user data from broker invalid: %v", err)
 	}
 
+	consent, err := s.consentFor(ctx, sessionID, uInfo, broker.Name)
+	if err != nil {
+		return nil, err
+	}
+	if consent != nil {
+		// The access is only granted once the user accepted the message, in the next step of the authentication.
+		s.sessions.setConsent(sessionID, consent)
+		return &authd.IAResponse{Access: auth.Next}, nil
+	}
+
+	return s.grantAccess(ctx, sessionID, uInfo, broker.Name)
+}
+
+// grantAccess updates the user granted by the broker in the database, and memorizes how they authenticated.
+func (s Service) grantAccess(ctx context.Context, sessionID string, uInfo types.UserInfo, brokerName string) (*authd.IAResponse, error) {
 	// Update database and local groups on granted auth.
-	if err := s.userManager.UpdateUser(uInfo); err != nil {
+	if err := s.updateUser(ctx, uInfo, brokerName); err != nil {
 		return nil, err
 	}
 
+	info, ok := s.sessions.get(sessionID)
+	if ok && info.authMode != "" {
+		if err := s.userManager.UpdateLastAuthModeForUser(uInfo.Name, info.brokerID, info.authMode); err != nil {
+			log.Warningf(ctx, "Could not remember authentication mode %q for user %q: %v", info.authMode, uInfo.Name, err)
+		}
+		s.recentAuthentications.granted(ctx, sessionID, uInfo.Name, info.tty, info.authMode)
+	}
+	if ok {
+		s.bruteForce.succeeded(ctx, info.username)
+	}
+	if ok && info.mode == auth.SessionModeLogin {
+		// A full login with the broker unlocks the local PIN after too many failed attempts, and allows to set it.
+		s.resetLocalPINFailedAttempts(ctx, uInfo.Name)
+		s.brokerLogins.record(uInfo.Name)
+	}
+
 	return &authd.IAResponse{
-		Access: access,
+		Access: auth.Granted,
 		Msg:    "",
 	}, nil
 }
 
+// updateUser updates the user granted by the broker in the database, recording the time it takes.
+func (s Service) updateUser(ctx context.Context, uInfo types.UserInfo, brokerName string) (err error) {
+	_, span := tracing.Start(ctx, "users.UpdateUser", attribute.String("broker", brokerName))
+	defer tracing.End(span, &err)
+
+	return s.userManager.UpdateUser(ctx, uInfo, brokerName)
+}
+
+// notGrantedResponse returns the response to an authentication which was not granted, with the new encryption key
+// of the broker if it rotated it.
+func notGrantedResponse(ctx context.Context, sessionID, access, data string) (*authd.IAResponse, error) {
+	resp := &authd.IAResponse{
+		Access: access,
+		Msg:    data,
+	}
+	if access != auth.Retry && access != auth.Next {
+		return resp, nil
+	}
+
+	key, data, err := encryption.KeyFromData(data)
+	if err != nil || key == "" {
+		return resp, err
+	}
+	if resp.EncryptionAlgorithm, resp.EncryptionKey, err = encryption.ParseKey(key); err != nil {
+		return nil, err
+	}
+	// Older clients would keep encrypting the next secrets with the previous key, which the broker can't decrypt.
+	if err := compat.Require(ctx, compat.EncryptionKeyRotation, "the broker changed its encryption key"); err != nil {
+		return nil, err
+	}
+	resp.Msg = data
+	log.Debugf(ctx, "%s: Broker rotated its encryption key, now using %s", sessionID, resp.EncryptionAlgorithm)
+
+	return resp, nil
+}
+
+// waitForAuthenticationSlot waits for a free authentication slot and returns the function to release it.
+// If no slot gets available in time, a ResourceExhausted error is returned so that the client can try again.
+func (s Service) waitForAuthenticationSlot(ctx context.Context, sessionID string) (release func(), err error) {
+	if s.authenticationSlots == nil {
+		return func() {}, nil
+	}
+
+	release = func() { <-s.authenticationSlots }
+	select {
+	case s.authenticationSlots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	log.Debugf(ctx, "%s: Waiting for an available authentication slot", sessionID)
+	_, span := tracing.Start(ctx, "pam.WaitForAuthenticationSlot")
+	defer tracing.End(span, &err)
+	select {
+	case s.authenticationSlots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.clock.After(s.authenticationSlotWaitTimeout):
+		return nil, authderrors.New(authderrors.ResourceExhausted, "too many concurrent authentications")
+	}
+}
+
 // SetDefaultBrokerForUser sets the default broker for the given user.
 func (s Service) SetDefaultBrokerForUser(ctx context.Context, req *authd.SDBFURequest) (empty *authd.Empty, err error) {
 	defer decorate.OnError(&err, "can't set default broker %q for user %q", req.GetBrokerId(), req.GetUsername())
 
 	if req.GetUsername() == "" {
-		return nil, status.Error(codes.InvalidArgument, "no user name given")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
 	}
 
 	// Don't allow setting the default broker to the local broker, because the decision to use the local broker should
 	// be made each time the user tries to log in, based on whether the user is provided by any other NSS service.
 	if req.GetBrokerId() == brokers.LocalBrokerName {
-		return nil, status.Error(codes.InvalidArgument, "can't set local broker as default")
+		return nil, authderrors.New(authderrors.InvalidArgument, "can't set local broker as default")
 	}
 
 	if err = s.brokerManager.SetDefaultBrokerForUser(req.GetBrokerId(), req.GetUsername()); err != nil {
@@ -304,16 +668,92 @@ func (s Service) SetDefaultBrokerForUser(ctx context.Context, req *authd.SDBFURe
 	return &authd.Empty{}, nil
 }
 
+// ResetBrokerForUser forgets the broker the user last authenticated with, so that they select one again on their next
+// login.
+func (s Service) ResetBrokerForUser(ctx context.Context, req *authd.RBFURequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't reset broker for user %q", req.GetUsername())
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+
+	err = s.userManager.ResetBrokerForUser(req.GetUsername())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q never logged in with authd", req.GetUsername())
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.brokerManager.UnsetDefaultBrokerForUser(req.GetUsername())
+
+	log.Infof(ctx, "Broker of user %q reset", req.GetUsername())
+	return &authd.Empty{}, nil
+}
+
 // EndSession asks the broker associated with the sessionID to end the session.
 func (s Service) EndSession(ctx context.Context, req *authd.ESRequest) (empty *authd.Empty, err error) {
 	defer decorate.OnError(&err, "could not abort session")
 
 	sessionID := req.GetSessionId()
 	if sessionID == "" {
-		return nil, status.Error(codes.InvalidArgument, "no session id given")
+		return nil, authderrors.New(authderrors.InvalidArgument, "no session id given")
+	}
+
+	s.sessions.remove(sessionID)
+	return &authd.Empty{}, s.brokerManager.EndSession(ctx, sessionID)
+}
+
+// IsRecentlyAuthenticated returns whether the user can be authenticated to the given PAM service without being
+// prompted, because they recently authenticated with a strong authentication mode on the same terminal.
+func (s Service) IsRecentlyAuthenticated(ctx context.Context, req *authd.IRARequest) (resp *authd.IRAResponse, err error) {
+	defer decorate.OnError(&err, "can't check recent authentication")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+	if req.GetService() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no PAM service given")
 	}
 
-	return &authd.Empty{}, s.brokerManager.EndSession(sessionID)
+	return &authd.IRAResponse{
+		Granted: s.recentAuthentications.check(ctx, req.GetUsername(), req.GetTty(), req.GetService()),
+	}, nil
+}
+
+// ListSessions returns the sessions currently in progress.
+func (s Service) ListSessions(ctx context.Context, _ *authd.Empty) (*authd.LSResponse, error) {
+	var r authd.LSResponse
+	for _, info := range s.sessions.list() {
+		r.Sessions = append(r.Sessions, &authd.LSResponse_SessionInfo{
+			SessionId: info.id,
+			Username:  info.username,
+			BrokerId:  info.brokerID,
+			Stage:     info.stage,
+			StartTime: info.startTime.Unix(),
+		})
+	}
+	return &r, nil
+}
+
+// AbortSession forcibly ends a session in progress, cancelling any pending authentication request.
+func (s Service) AbortSession(ctx context.Context, req *authd.ASRequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "could not abort session %q", req.GetSessionId())
+
+	sessionID := req.GetSessionId()
+	if sessionID == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no session id given")
+	}
+	if _, ok := s.sessions.get(sessionID); !ok {
+		return nil, authderrors.New(authderrors.NotFound, "no such session")
+	}
+
+	log.Infof(ctx, "%s: Aborting session", sessionID)
+	if err := s.brokerManager.AbortSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	s.sessions.remove(sessionID)
+
+	return &authd.Empty{}, nil
 }
 
 func uiLayoutToMap(layout *authd.UILayout) (mapLayout map[string]string, err error) {
@@ -339,6 +779,21 @@ func uiLayoutToMap(layout *authd.UILayout) (mapLayout map[string]string, err err
 	if c := layout.GetCode(); c != "" {
 		r[layouts.Code] = c
 	}
+	if l := layout.GetCodeLength(); l != "" {
+		r[layouts.CodeLength] = l
+	}
+	if v := layout.GetCodeValidity(); v != "" {
+		r[layouts.CodeValidity] = v
+	}
+	if a := layout.GetSmartcardAction(); a != "" {
+		r[layouts.SmartCardAction] = a
+	}
+	if m := layout.GetMinStrength(); m != "" {
+		r[layouts.MinStrength] = m
+	}
+	if c := layout.GetCachedCredentials(); c != "" {
+		r[layouts.CachedCredentials] = c
+	}
 
 	if layout.GetType() != layouts.QrCode {
 		return r, nil
@@ -366,7 +821,7 @@ func mapToUILayout(layout map[string]string) (r *authd.UILayout) {
 	// We don't return whether the qrcode rendering is enabled back to the
 	// client on purpose, since it's something it mandates.
 
-	return &authd.UILayout{
+	r = &authd.UILayout{
 		Type:    typ,
 		Label:   &label,
 		Entry:   &entry,
@@ -375,4 +830,23 @@ func mapToUILayout(layout map[string]string) (r *authd.UILayout) {
 		Content: &content,
 		Code:    &code,
 	}
+
+	// The code, smart card, cached credentials and strength fields are only set when provided, so that the other
+	// layouts are unchanged.
+	if l, ok := layout[layouts.CodeLength]; ok {
+		r.CodeLength = &l
+	}
+	if v, ok := layout[layouts.CodeValidity]; ok {
+		r.CodeValidity = &v
+	}
+	if a, ok := layout[layouts.SmartCardAction]; ok {
+		r.SmartcardAction = &a
+	}
+	if c, ok := layout[layouts.CachedCredentials]; ok {
+		r.CachedCredentials = &c
+	}
+	if m, ok := layout[layouts.MinStrength]; ok {
+		r.MinStrength = &m
+	}
+	return r
 }
@@ -10,15 +10,23 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/authderrors"
 	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/brokers/auth"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/clock"
+	"github.com/ubuntu/authd/internal/consts"
+	"github.com/ubuntu/authd/internal/debugserver"
+	"github.com/ubuntu/authd/internal/faillock"
 	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/compat"
 	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/internal/services/pam"
 	"github.com/ubuntu/authd/internal/services/permissions"
@@ -31,12 +39,22 @@ import (
 	userstestutils "github.com/ubuntu/authd/internal/users/testutils"
 	"github.com/ubuntu/authd/log"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
 	globalBrokerManager   *brokers.Manager
 	mockBrokerGeneratedID string
+
+	// usersConfig trusts the shells of the users of the mock broker, which are not listed in /etc/shells.
+	usersConfig = func() users.Config {
+		c := users.DefaultConfig
+		c.BrokerTrust = map[string]users.BrokerTrustConfig{"BrokerMock": {AnyShell: true}}
+		return c
+	}()
 )
 
 // Used for TestGetAuthenticationModes and TestSelectAuthenticationMode.
@@ -61,6 +79,28 @@ var (
 		Type:  "optional-entry",
 		Entry: &optionalEntries,
 	}
+	codeEntry = &authd.UILayout{
+		Type:         layouts.Form,
+		Label:        &optional,
+		Entry:        &optionalEntries,
+		Button:       &optional,
+		CodeLength:   &optional,
+		CodeValidity: &optional,
+	}
+	cachedCredentials = &authd.UILayout{
+		Type:              layouts.Form,
+		Label:             &optional,
+		Entry:             &optionalEntries,
+		CachedCredentials: &layouts.OptionalWithBooleans,
+	}
+	smartCardActions = layouts.RequiredItems(layouts.SmartCardInsert, layouts.SmartCardPIN, layouts.SmartCardTouch)
+	smartCard        = &authd.UILayout{
+		Type:            layouts.SmartCard,
+		Label:           &optional,
+		Entry:           &optionalEntries,
+		Wait:            &layouts.OptionalWithBooleans,
+		SmartcardAction: &smartCardActions,
+	}
 	emptyType = &authd.UILayout{
 		Type:  "",
 		Entry: &requiredEntries,
@@ -70,7 +110,7 @@ var (
 func TestNewService(t *testing.T) {
 	t.Parallel()
 
-	m, err := users.NewManager(users.DefaultConfig, t.TempDir())
+	m, err := users.NewManager(usersConfig, t.TempDir())
 	require.NoError(t, err, "Setup: could not create user manager")
 
 	pm := permissions.New()
@@ -124,12 +164,18 @@ func TestGetPreviousBroker(t *testing.T) {
 	u, err := user.Current()
 	require.NoError(t, err, "Setup: could not fetch current user")
 	currentUsername := u.Username
+	g, err := user.LookupGroupId(u.Gid)
+	require.NoError(t, err, "Setup: could not fetch current user group")
+	currentGroupname := g.Name
 
 	tests := map[string]struct {
 		user string
 
 		currentUserNotRoot bool
 		onlyLocalBroker    bool
+		localFallback      pam.LocalFallbackPolicy
+		defaultBroker      string
+		requestedDefault   *string
 
 		wantBroker string
 		wantErr    bool
@@ -138,9 +184,33 @@ func TestGetPreviousBroker(t *testing.T) {
 		"For_local_user,_get_local_broker":                         {user: currentUsername, wantBroker: brokers.LocalBrokerName},
 		"For_unmanaged_user_and_only_one_broker,_get_local_broker": {user: "nonexistent", onlyLocalBroker: true, wantBroker: brokers.LocalBrokerName},
 
-		"Returns_empty_when_user_does_not_exist":         {user: "nonexistent", wantBroker: ""},
-		"Returns_empty_when_user_does_not_have_a_broker": {user: "userwithoutbroker", wantBroker: ""},
-		"Returns_empty_when_broker_is_not_available":     {user: "userwithinactivebroker", wantBroker: ""},
+		"For_user_of_the_local_fallback_policy,_get_local_broker": {
+			user: "userwithbroker", localFallback: pam.LocalFallbackPolicy{Users: []string{"userwithbroker"}}, wantBroker: brokers.LocalBrokerName,
+		},
+		"For_unknown_user_of_the_local_fallback_policy,_get_local_broker": {
+			user: "nonexistent", localFallback: pam.LocalFallbackPolicy{Users: []string{"nonexistent"}}, wantBroker: brokers.LocalBrokerName,
+		},
+		"For_member_of_an_authd_group_of_the_local_fallback_policy,_get_local_broker": {
+			user: "userwithbroker", localFallback: pam.LocalFallbackPolicy{Groups: []string{"group1"}}, wantBroker: brokers.LocalBrokerName,
+		},
+		"For_member_of_a_local_group_of_the_local_fallback_policy,_get_local_broker": {
+			user: currentUsername, localFallback: pam.LocalFallbackPolicy{Groups: []string{currentGroupname}}, wantBroker: brokers.LocalBrokerName,
+		},
+		"Users_not_in_the_local_fallback_policy_get_their_previous_broker": {
+			user: "userwithbroker", localFallback: pam.LocalFallbackPolicy{Users: []string{"otheruser"}, Groups: []string{"group2"}}, wantBroker: mockBrokerGeneratedID,
+		},
+
+		"For_unknown_user,_get_default_broker":                       {user: "nonexistent", defaultBroker: "BrokerMock", wantBroker: mockBrokerGeneratedID},
+		"For_user_without_broker,_get_default_broker":                {user: "userwithoutbroker", defaultBroker: "BrokerMock", wantBroker: mockBrokerGeneratedID},
+		"For_unknown_user,_get_default_broker_by_ID":                 {user: "nonexistent", defaultBroker: mockBrokerGeneratedID, wantBroker: mockBrokerGeneratedID},
+		"For_unknown_user,_get_default_broker_requested_by_PAM":      {user: "nonexistent", requestedDefault: ptrValue("BrokerMock"), wantBroker: mockBrokerGeneratedID},
+		"Users_with_a_broker_get_their_previous_broker_over_default": {user: "userwithbroker", defaultBroker: brokers.LocalBrokerName, wantBroker: mockBrokerGeneratedID},
+
+		"Returns_empty_when_user_does_not_exist":               {user: "nonexistent", wantBroker: ""},
+		"Returns_empty_when_default_broker_is_disabled_by_PAM": {user: "nonexistent", defaultBroker: "BrokerMock", requestedDefault: ptrValue(""), wantBroker: ""},
+		"Returns_empty_when_default_broker_is_not_available":   {user: "nonexistent", defaultBroker: "not-available", wantBroker: ""},
+		"Returns_empty_when_user_does_not_have_a_broker":       {user: "userwithoutbroker", wantBroker: ""},
+		"Returns_empty_when_broker_is_not_available":           {user: "userwithinactivebroker", wantBroker: ""},
 
 		"Error_when_not_root": {user: "userwithbroker", currentUserNotRoot: true, wantErr: true},
 	}
@@ -160,7 +230,7 @@ func TestGetPreviousBroker(t *testing.T) {
 			err = db.Z_ForTests_CreateDBFromYAMLReader(bytes.NewBuffer(d), dbDir)
 			require.NoError(t, err, "Setup: could not create database from testdata")
 
-			m, err := users.NewManager(users.DefaultConfig, dbDir)
+			m, err := users.NewManager(usersConfig, dbDir)
 			require.NoError(t, err, "Setup: could not create user manager")
 			t.Cleanup(func() { _ = m.Stop() })
 			pm := newPermissionManager(t, tc.currentUserNotRoot)
@@ -170,10 +240,10 @@ func TestGetPreviousBroker(t *testing.T) {
 				brokerManager, err = brokers.NewManager(context.Background(), "", nil)
 				require.NoError(t, err, "Setup: could not create broker manager with only local broker")
 			}
-			client := newPamClient(t, m, brokerManager, &pm)
+			client := newPamClient(t, m, brokerManager, &pm, pam.WithLocalFallbackPolicy(tc.localFallback), pam.WithDefaultBroker(tc.defaultBroker))
 
 			// Get existing entry
-			gotResp, err := client.GetPreviousBroker(context.Background(), &authd.GPBRequest{Username: tc.user})
+			gotResp, err := client.GetPreviousBroker(context.Background(), &authd.GPBRequest{Username: tc.user, DefaultBroker: tc.requestedDefault})
 
 			if tc.wantErr {
 				require.Error(t, err, "GetPreviousBroker should return an error, but did not")
@@ -200,6 +270,7 @@ func TestSelectBroker(t *testing.T) {
 	}{
 		"Successfully_select_a_broker_and_creates_auth_session":   {username: "success", sessionMode: auth.SessionModeLogin},
 		"Successfully_select_a_broker_and_creates_passwd_session": {username: "success", sessionMode: auth.SessionModeChangePassword},
+		"Successfully_select_a_broker_with_encryption_algorithm":  {username: "NS_encryption_algorithm"},
 
 		"Error_when_not_root":                             {username: "success", currentUserNotRoot: true, wantErr: true},
 		"Error_when_username_is_empty":                    {wantErr: true},
@@ -209,6 +280,7 @@ func TestSelectBroker(t *testing.T) {
 		"Error_when_broker_does_not_exist":                {username: "no broker", brokerID: "does not exist", wantErr: true},
 		"Error_when_broker_does_not_provide_a_session_ID": {username: "NS_no_id", wantErr: true},
 		"Error_when_starting_the_session":                 {username: "NS_error", wantErr: true},
+		"Error_when_encryption_algorithm_is_unsupported":  {username: "NS_unsupported_encryption_algorithm", wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -250,14 +322,77 @@ func TestSelectBroker(t *testing.T) {
 			}
 			require.NoError(t, err, "SelectBroker should not return an error, but did")
 
-			got := fmt.Sprintf("ID: %s\nEncryption Key: %s\n",
+			got := fmt.Sprintf("ID: %s\nEncryption Key: %s\nEncryption Algorithm: %s\n",
 				strings.ReplaceAll(sbResp.GetSessionId(), tc.brokerID, "BROKER_ID"),
-				sbResp.GetEncryptionKey())
+				sbResp.GetEncryptionKey(), sbResp.GetEncryptionAlgorithm())
 			golden.CheckOrUpdate(t, got)
 		})
 	}
 }
 
+func TestSelectBrokerStepUp(t *testing.T) {
+	t.Parallel()
+
+	sudoPolicy := pam.StepUpPolicy{Services: []string{"sudo"}}
+
+	tests := map[string]struct {
+		username    string
+		service     string
+		sessionMode authd.SessionMode
+		policy      pam.StepUpPolicy
+
+		wantStepUp bool
+	}{
+		"Step_up_session_for_user_logged_in_with_the_broker": {username: "userwithbroker", service: "sudo", policy: sudoPolicy, wantStepUp: true},
+
+		"Login_session_for_service_not_in_policy":              {username: "userwithbroker", service: "login", policy: sudoPolicy},
+		"Login_session_when_no_service_is_provided":            {username: "userwithbroker", policy: sudoPolicy},
+		"Login_session_when_policy_is_empty":                   {username: "userwithbroker", service: "sudo"},
+		"Login_session_for_user_who_never_logged_in":           {username: "nonexistent", service: "sudo", policy: sudoPolicy},
+		"Login_session_for_user_logged_in_with_another_broker": {username: "userwithotherbroker", service: "sudo", policy: sudoPolicy},
+		"Change_password_session_is_not_turned_into_a_step_up": {username: "userwithbroker", service: "sudo", sessionMode: authd.SessionMode_CHANGE_PASSWORD, policy: sudoPolicy},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+
+			// We have to replace MOCKBROKERID with our generated broker id.
+			d, err := os.ReadFile(filepath.Join(testutils.TestFamilyPath(t), "step-up.db"))
+			require.NoError(t, err, "Setup: could not read fixture database file")
+			d = bytes.ReplaceAll(d, []byte("MOCKBROKERID"), []byte(mockBrokerGeneratedID))
+			err = db.Z_ForTests_CreateDBFromYAMLReader(bytes.NewBuffer(d), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, false)
+			client := newPamClient(t, m, globalBrokerManager, &pm, pam.WithStepUpPolicy(tc.policy))
+
+			if tc.sessionMode == authd.SessionMode_UNDEFINED {
+				tc.sessionMode = authd.SessionMode_LOGIN
+			}
+
+			sbResp, err := client.SelectBroker(context.Background(), &authd.SBRequest{
+				BrokerId: mockBrokerGeneratedID,
+				Username: tc.username,
+				Mode:     tc.sessionMode,
+				Service:  tc.service,
+			})
+			require.NoError(t, err, "SelectBroker should not return an error, but did")
+
+			// The broker mock returns a dedicated key for the step-up sessions.
+			wantKey := testutils.GenerateEncryptionKey("BrokerMock")
+			if tc.wantStepUp {
+				wantKey = testutils.GenerateEncryptionKey("BrokerMock-step-up")
+			}
+			require.Equal(t, wantKey, sbResp.GetEncryptionKey(), "SelectBroker should start a session with the expected mode")
+		})
+	}
+}
+
 func TestGetAuthenticationModes(t *testing.T) {
 	t.Parallel()
 
@@ -338,6 +473,9 @@ func TestSelectAuthenticationMode(t *testing.T) {
 	}{
 		"Successfully_select_mode_with_required_value":         {username: "SAM_success_required_entry", supportedUILayouts: []*authd.UILayout{requiredEntry}},
 		"Successfully_select_mode_with_missing_optional_value": {username: "SAM_missing_optional_entry", supportedUILayouts: []*authd.UILayout{optionalEntry}},
+		"Successfully_select_code_entry_mode":                  {username: "SAM_code_entry", supportedUILayouts: []*authd.UILayout{codeEntry}},
+		"Successfully_select_mode_with_cached_credentials":     {username: "SAM_cached_credentials", supportedUILayouts: []*authd.UILayout{cachedCredentials}},
+		"Successfully_select_smart_card_mode":                  {username: "SAM_smart_card", supportedUILayouts: []*authd.UILayout{smartCard}},
 
 		// service errors
 		"Error_when_not_root":                {username: "SAM_success_required_entry", currentUserNotRoot: true, wantErr: true},
@@ -354,6 +492,7 @@ func TestSelectAuthenticationMode(t *testing.T) {
 		"Error_when_returns_layout_with_no_type":           {username: "SAM_no_layout_type", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
 		"Error_when_returns_layout_without_required_value": {username: "SAM_missing_required_entry", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
 		"Error_when_returns_layout_with_unknown_field":     {username: "SAM_unknown_field", supportedUILayouts: []*authd.UILayout{requiredEntry}, wantErr: true},
+		"Error_when_returns_unsupported_smart_card_action": {username: "SAM_invalid_smart_card_action", supportedUILayouts: []*authd.UILayout{smartCard}, wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -429,6 +568,8 @@ func TestIsAuthenticated(t *testing.T) {
 		"Denies_authentication_when_broker_times_out":         {username: "IA_timeout"},
 		"Update_existing_DB_on_success":                       {username: "success", existingDB: "cache-with-user.db"},
 		"Update_local_groups":                                 {username: "success_with_local_groups", localGroupsFile: "valid.group"},
+		"Returns_rotated_encryption_key_on_retry":             {username: "IA_retry_with_encryption_key"},
+		"Returns_rotated_encryption_key_on_next":              {username: "IA_next_with_encryption_key"},
 
 		// service errors
 		"Error_when_not_root":           {username: "success", currentUserNotRoot: true},
@@ -436,12 +577,13 @@ func TestIsAuthenticated(t *testing.T) {
 		"Error_when_there_is_no_broker": {sessionID: "invalid-session"},
 
 		// broker errors
-		"Error_when_authenticating":                         {username: "IA_error"},
-		"Error_on_empty_data_even_if_granted":               {username: "IA_empty_data"},
-		"Error_when_broker_returns_invalid_access":          {username: "IA_invalid_access"},
-		"Error_when_broker_returns_invalid_data":            {username: "IA_invalid_data"},
-		"Error_when_broker_returns_invalid_userinfo":        {username: "IA_invalid_userinfo"},
-		"Error_when_calling_second_time_without_cancelling": {username: "IA_second_call", secondCall: true},
+		"Error_when_authenticating":                              {username: "IA_error"},
+		"Error_on_empty_data_even_if_granted":                    {username: "IA_empty_data"},
+		"Error_when_broker_returns_invalid_access":               {username: "IA_invalid_access"},
+		"Error_when_broker_returns_invalid_data":                 {username: "IA_invalid_data"},
+		"Error_when_broker_returns_invalid_userinfo":             {username: "IA_invalid_userinfo"},
+		"Error_when_calling_second_time_without_cancelling":      {username: "IA_second_call", secondCall: true},
+		"Error_when_rotated_encryption_algorithm_is_unsupported": {username: "IA_retry_with_unsupported_encryption_algorithm"},
 
 		// local group error
 		"Error_on_updating_local_groups_with_unexisting_file": {username: "success_with_local_groups", localGroupsFile: "does_not_exists.group"},
@@ -470,7 +612,7 @@ func TestIsAuthenticated(t *testing.T) {
 				}),
 			}
 
-			m, err := users.NewManager(users.DefaultConfig, dbDir, managerOpts...)
+			m, err := users.NewManager(usersConfig, dbDir, managerOpts...)
 			require.NoError(t, err, "Setup: could not create user manager")
 			t.Cleanup(func() { _ = m.Stop() })
 			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
@@ -507,6 +649,12 @@ func TestIsAuthenticated(t *testing.T) {
 					iaResp.GetMsg(),
 					err,
 				)
+				if iaResp.GetEncryptionKey() != "" {
+					firstCall += fmt.Sprintf("\tencryption key: %s\n\tencryption algorithm: %s\n",
+						iaResp.GetEncryptionKey(),
+						iaResp.GetEncryptionAlgorithm(),
+					)
+				}
 			}()
 			// Give some time for the first call to block
 			time.Sleep(time.Second)
@@ -544,234 +692,2236 @@ func TestIsAuthenticated(t *testing.T) {
 	}
 }
 
-func TestIDGeneration(t *testing.T) {
+func TestIsAuthenticatedWithConcurrencyLimit(t *testing.T) {
 	t.Parallel()
-	usernamePrefix := t.Name()
 
 	tests := map[string]struct {
-		username string
+		maxConcurrentAuthentications int
+		slotWaitTimeout              time.Duration
+
+		wantErr bool
 	}{
-		"Generate_ID": {username: "success"},
+		"Authenticate_concurrently_without_limit":        {},
+		"Authenticate_once_a_slot_is_available":          {maxConcurrentAuthentications: 1, slotWaitTimeout: 5 * time.Second},
+		"Authenticate_concurrently_below_the_limit":      {maxConcurrentAuthentications: 2, slotWaitTimeout: 100 * time.Millisecond},
+		"Error_when_no_slot_is_available_before_timeout": {maxConcurrentAuthentications: 1, slotWaitTimeout: 100 * time.Millisecond, wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			managerOpts := []users.Option{
-				users.WithIDGenerator(&idgenerator.IDGeneratorMock{
-					UIDsToGenerate: []uint32{1111},
-					GIDsToGenerate: []uint32{1111, 2222},
-				}),
+			opts := []pam.Option{pam.WithMaxConcurrentAuthentications(tc.maxConcurrentAuthentications)}
+			if tc.slotWaitTimeout != 0 {
+				opts = append(opts, pam.WithAuthenticationSlotWaitTimeout(tc.slotWaitTimeout))
 			}
-
-			m, err := users.NewManager(users.DefaultConfig, t.TempDir(), managerOpts...)
-			require.NoError(t, err, "Setup: could not create user manager")
-			t.Cleanup(func() { _ = m.Stop() })
 			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
-			client := newPamClient(t, m, globalBrokerManager, &pm)
+			client := newPamClient(t, nil, globalBrokerManager, &pm, opts...)
 
-			sbResp, err := client.SelectBroker(context.Background(), &authd.SBRequest{
-				BrokerId: mockBrokerGeneratedID,
-				Username: usernamePrefix + testutils.IDSeparator + tc.username,
-				Mode:     authd.SessionMode_LOGIN,
-			})
-			require.NoError(t, err, "Setup: failed to create session for tests")
+			// The first authentication keeps its slot busy for a second.
+			busySessionID := startSession(t, client, "IA_timeout")
+			sessionID := startSession(t, client, "success")
 
-			resp, err := client.IsAuthenticated(context.Background(), &authd.IARequest{SessionId: sbResp.GetSessionId()})
-			require.NoError(t, err, "Setup: could not authenticate user")
-			require.Equal(t, "granted", resp.GetAccess(), "Setup: authentication should be granted")
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_, err := client.IsAuthenticated(context.Background(), &authd.IARequest{
+					SessionId:          busySessionID,
+					AuthenticationData: &authd.IARequest_AuthenticationData{},
+				})
+				require.NoError(t, err, "Setup: first authentication should not fail")
+			}()
+			// Give some time for the first call to take its slot.
+			time.Sleep(100 * time.Millisecond)
 
-			gotDB, err := db.Z_ForTests_DumpNormalizedYAML(userstestutils.GetManagerDB(m))
-			require.NoError(t, err, "Setup: failed to dump database for comparing")
-			golden.CheckOrUpdate(t, gotDB, golden.WithPath("cache.db"))
+			iaResp, err := client.IsAuthenticated(context.Background(), &authd.IARequest{
+				SessionId:          sessionID,
+				AuthenticationData: &authd.IARequest_AuthenticationData{},
+			})
+			<-done
+
+			if tc.wantErr {
+				require.Equal(t, codes.ResourceExhausted, status.Code(err), "IsAuthenticated should fail with ResourceExhausted")
+				return
+			}
+			require.NoError(t, err, "IsAuthenticated should not return an error")
+			require.Equal(t, auth.Granted, iaResp.GetAccess(), "IsAuthenticated should grant access")
 		})
 	}
 }
 
-func TestSetDefaultBrokerForUser(t *testing.T) {
+func TestGetAuthenticationProgress(t *testing.T) {
 	t.Parallel()
 
 	tests := map[string]struct {
 		username           string
-		brokerID           string
+		authenticate       bool
+		sessionID          string
 		currentUserNotRoot bool
 
-		wantErr bool
+		wantMsg         string
+		wantPercentage  *uint32
+		wantCancellable bool
+		wantErr         bool
 	}{
-		"Set_default_broker_for_existing_user_with_no_broker":   {username: "usersetbroker"},
-		"Update_default_broker_for_existing_user_with_a_broker": {username: "userupdatebroker"},
+		"Get_progress_reported_by_the_broker": {
+			username: "IA_progress", authenticate: true,
+			wantMsg: "Waiting for approval on your phone", wantPercentage: ptrValue(uint32(50)), wantCancellable: true,
+		},
+		"No_progress_without_authentication_in_progress": {username: "IA_progress"},
 
-		"Error_when_setting_default_broker_to_local_broker": {username: "userlocalbroker", brokerID: brokers.LocalBrokerName, wantErr: true},
-		"Error_when_not_root":                               {username: "usersetbroker", currentUserNotRoot: true, wantErr: true},
-		"Error_when_username_is_empty":                      {wantErr: true},
-		"Error_when_user_does_not_exist_":                   {username: "doesnotexist", wantErr: true},
-		"Error_when_broker_does_not_exist":                  {username: "userwithbroker", brokerID: "does not exist", wantErr: true},
+		"Error_when_not_root":             {username: "IA_progress", currentUserNotRoot: true, wantErr: true},
+		"Error_when_sessionID_is_empty":   {sessionID: "-", wantErr: true},
+		"Error_when_sessionID_is_invalid": {sessionID: "invalid-session", wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			dbDir := t.TempDir()
-			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "set-default-broker.db"), dbDir)
-			require.NoError(t, err, "Setup: could not create database from testdata")
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, nil, globalBrokerManager, &pm)
 
-			m, err := users.NewManager(users.DefaultConfig, dbDir)
-			require.NoError(t, err, "Setup: could not create user manager")
-			t.Cleanup(func() { _ = m.Stop() })
-			pm := newPermissionManager(t, tc.currentUserNotRoot)
-			client := newPamClient(t, m, globalBrokerManager, &pm)
+			switch tc.sessionID {
+			case "":
+				tc.sessionID = startSession(t, client, tc.username)
+			case "-":
+				tc.sessionID = ""
+			}
 
-			if tc.brokerID == "" {
-				tc.brokerID = mockBrokerGeneratedID
+			if tc.authenticate {
+				ctx, cancel := context.WithCancel(context.Background())
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					_, _ = client.IsAuthenticated(ctx, &authd.IARequest{
+						SessionId:          tc.sessionID,
+						AuthenticationData: &authd.IARequest_AuthenticationData{Item: &authd.IARequest_AuthenticationData_Wait{Wait: "true"}},
+					})
+				}()
+				t.Cleanup(func() {
+					cancel()
+					<-done
+				})
+				require.Eventually(t, func() bool {
+					resp, err := client.GetAuthenticationProgress(context.Background(), &authd.GAPRequest{SessionId: tc.sessionID})
+					return err == nil && resp.GetMsg() != ""
+				}, 5*time.Second, 10*time.Millisecond, "Setup: the progress reported by the broker should be received")
 			}
 
-			sdbfuReq := &authd.SDBFURequest{
-				BrokerId: tc.brokerID,
-				Username: tc.username,
+			if tc.currentUserNotRoot {
+				pm = newPermissionManager(t, true)
+				client = newPamClient(t, nil, globalBrokerManager, &pm)
 			}
-			_, err = client.SetDefaultBrokerForUser(context.Background(), sdbfuReq)
+
+			resp, err := client.GetAuthenticationProgress(context.Background(), &authd.GAPRequest{SessionId: tc.sessionID})
 			if tc.wantErr {
-				require.Error(t, err, "SetDefaultBrokerForUser should return an error, but did not")
+				require.Error(t, err, "GetAuthenticationProgress should return an error, but did not")
 				return
 			}
-			require.NoError(t, err, "SetDefaultBrokerForUser should not return an error, but did")
-
-			gpbResp, err := client.GetPreviousBroker(context.Background(), &authd.GPBRequest{Username: tc.username})
-			require.NoError(t, err, "GetPreviousBroker should not return an error")
-			require.Equal(t, tc.brokerID, gpbResp.GetPreviousBroker(), "SetDefaultBrokerForUser should set the default broker as expected")
-
-			// Check that database has been updated too.
-			gotDB, err := db.Z_ForTests_DumpNormalizedYAML(userstestutils.GetManagerDB(m))
-			require.NoError(t, err, "Setup: failed to dump database for comparing")
-			golden.CheckOrUpdate(t, gotDB, golden.WithPath("cache.db"))
+			require.NoError(t, err, "GetAuthenticationProgress should not return an error, but did")
+			require.Equal(t, tc.wantMsg, resp.GetMsg(), "GetAuthenticationProgress should return the expected message")
+			require.Equal(t, tc.wantPercentage, resp.Percentage, "GetAuthenticationProgress should return the expected percentage")
+			require.Equal(t, tc.wantCancellable, resp.GetCancellable(), "GetAuthenticationProgress should return whether the authentication can be cancelled")
 		})
 	}
 }
 
-func TestEndSession(t *testing.T) {
+func TestBruteForceProtection(t *testing.T) {
 	t.Parallel()
 
-	tests := map[string]struct {
-		sessionID string
+	policy := pam.BruteForcePolicy{MaxAttempts: 3, Delay: time.Minute, ResetAfter: 10 * time.Minute}
+	services := []string{"gdm-authd", "sshd", "sudo"}
 
-		username           string
-		currentUserNotRoot bool
+	tests := map[string]struct {
+		policy   pam.BruteForcePolicy
+		username string
+		failures []string
+		interval time.Duration
+		elapsed  time.Duration
 
-		wantErr bool
+		wantRefused  bool
+		wantFailures map[string]uint32
+		wantErr      bool
 	}{
-		"Successfully_end_session": {username: "success"},
-
-		"Error_when_not_root":             {username: "success", currentUserNotRoot: true, wantErr: true},
-		"Error_when_sessionID_is_empty":   {sessionID: "-", wantErr: true},
-		"Error_when_sessionID_is_invalid": {sessionID: "invalid-session", wantErr: true},
-		"Error_when_ending_session":       {username: "ES_error", wantErr: true},
+		"Refuse_after_max_attempts_through_different_services": {policy: policy, failures: services, wantRefused: true, wantFailures: map[string]uint32{"gdm-authd": 1, "sshd": 1, "sudo": 1}},
+		"Refuse_after_max_attempts_through_the_same_service":   {policy: policy, failures: []string{"sshd", "sshd", "sshd"}, wantRefused: true, wantFailures: map[string]uint32{"sshd": 3}},
+		"Refuse_after_max_attempts_with_retries":               {policy: policy, username: "IA_retry", failures: services, wantRefused: true, wantFailures: map[string]uint32{"gdm-authd": 1, "sshd": 1, "sudo": 1}},
+		"Refuse_with_doubled_delay_after_further_failures": {
+			policy: pam.BruteForcePolicy{MaxAttempts: 2, Delay: time.Minute}, failures: services, interval: time.Minute, elapsed: 90 * time.Second,
+			wantRefused: true, wantFailures: map[string]uint32{"gdm-authd": 1, "sshd": 1, "sudo": 1},
+		},
+		"Refuse_with_max_delay_once_doubling_would_overflow": {
+			policy: pam.BruteForcePolicy{MaxAttempts: 1, ResetAfter: time.Hour}, failures: slices.Repeat([]string{"sshd"}, 31), interval: 16 * time.Minute,
+			wantRefused: true, wantFailures: map[string]uint32{"sshd": 31},
+		},
+		"Count_failures_of_clients_without_service": {policy: policy, failures: []string{""}, wantFailures: map[string]uint32{"unknown": 1}},
+
+		"Allow_below_max_attempts":          {policy: policy, failures: services[:2], wantFailures: map[string]uint32{"gdm-authd": 1, "sshd": 1}},
+		"Allow_once_backoff_is_over":        {policy: policy, failures: services, elapsed: 2 * time.Minute, wantFailures: map[string]uint32{"gdm-authd": 1, "sshd": 1, "sudo": 1}},
+		"Allow_when_failures_are_forgotten": {policy: policy, failures: services, elapsed: 11 * time.Minute},
+		"Allow_without_failures":            {policy: policy},
+		"Allow_when_policy_is_disabled":     {failures: services, wantFailures: map[string]uint32{"gdm-authd": 1, "sshd": 1, "sudo": 1}},
+		"Error_when_username_is_empty":      {policy: policy, username: "-", wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
+			c := clock.NewFake(time.Now())
 			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
-			client := newPamClient(t, nil, globalBrokerManager, &pm)
+			client, service := newPamClientAndService(t, nil, globalBrokerManager, &pm, pam.WithBruteForcePolicy(tc.policy), pam.WithClock(c))
 
-			switch tc.sessionID {
-			case "invalid-session":
+			switch tc.username {
+			case "":
+				tc.username = t.Name() + testutils.IDSeparator + "IA_denied"
 			case "-":
-				tc.sessionID = ""
+				tc.username = ""
 			default:
-				id := startSession(t, client, tc.username)
-				if tc.sessionID == "" {
-					tc.sessionID = id
-				}
+				tc.username = t.Name() + testutils.IDSeparator + tc.username
 			}
 
-			// Now, set tests permissions for this use case
-			permissions.Z_ForTests_SetCurrentUserAsRoot(&pm, !tc.currentUserNotRoot)
+			authenticate := func(service string) (*authd.IAResponse, error) {
+				t.Helper()
+
+				sbResp, err := client.SelectBroker(context.Background(), &authd.SBRequest{
+					BrokerId: mockBrokerGeneratedID,
+					Username: tc.username,
+					Service:  service,
+					Mode:     authd.SessionMode_LOGIN,
+				})
+				require.NoError(t, err, "Setup: failed to create session for tests")
+				return client.IsAuthenticated(context.Background(), &authd.IARequest{
+					SessionId:          sbResp.GetSessionId(),
+					AuthenticationData: &authd.IARequest_AuthenticationData{},
+				})
+			}
 
-			esReq := &authd.ESRequest{
-				SessionId: tc.sessionID,
+			for i, service := range tc.failures {
+				if i > 0 {
+					c.Advance(tc.interval)
+				}
+				iaResp, err := authenticate(service)
+				require.NoError(t, err, "Setup: failed authentication should not return an error")
+				require.NotEqual(t, auth.Granted, iaResp.GetAccess(), "Setup: authentication should fail")
 			}
-			_, err := client.EndSession(context.Background(), esReq)
+			c.Advance(tc.elapsed)
+
+			gfaResp, err := client.GetFailedAuthentications(context.Background(), &authd.GFARequest{Username: tc.username})
 			if tc.wantErr {
-				require.Error(t, err, "EndSession should return an error, but did not")
+				require.Error(t, err, "GetFailedAuthentications should return an error, but did not")
 				return
 			}
-			require.NoError(t, err, "EndSession should not return an error, but did")
+			require.NoError(t, err, "GetFailedAuthentications should not return an error, but did")
+
+			gotFailures := make(map[string]uint32)
+			for _, source := range gfaResp.GetSources() {
+				gotFailures[source.GetService()] = source.GetFailedAttempts()
+			}
+			if tc.wantFailures == nil {
+				tc.wantFailures = map[string]uint32{}
+			}
+			require.Equal(t, tc.wantFailures, gotFailures, "GetFailedAuthentications returned unexpected failures")
+			require.Equal(t, len(tc.wantFailures) > 0, gfaResp.GetLastFailure() != 0, "GetFailedAuthentications returned an unexpected last failure")
+			require.Equal(t, tc.wantRefused, gfaResp.GetBackoffUntil() != 0, "GetFailedAuthentications returned an unexpected backoff")
+			require.Equal(t, len(tc.wantFailures) > 0, service.Busy(), "Busy should only be true while failures are tracked in memory")
+
+			// Another service can't be used to bypass the backoff.
+			iaResp, err := authenticate("login")
+			if tc.wantRefused {
+				require.Equal(t, codes.ResourceExhausted, status.Code(err), "IsAuthenticated should fail with ResourceExhausted")
+				return
+			}
+			require.NoError(t, err, "IsAuthenticated should not return an error, but did")
+			require.NotEqual(t, auth.Granted, iaResp.GetAccess(), "IsAuthenticated should not grant access")
 		})
 	}
 }
 
-func TestMockgpasswd(t *testing.T) {
-	localgroupstestutils.Mockgpasswd(t)
-}
+func TestBruteForceProtectionWithConcurrentAttempts(t *testing.T) {
+	t.Parallel()
 
-// initBrokers starts dbus mock brokers on the system bus. It returns its config path.
-func initBrokers() (brokerConfigPath string, cleanup func(), err error) {
-	tmpDir, err := os.MkdirTemp("", "authd-internal-pam-tests-")
-	if err != nil {
-		return "", nil, err
-	}
+	tests := map[string]struct {
+		policy pam.BruteForcePolicy
 
-	brokersConfPath := filepath.Join(tmpDir, "etc", "authd", "broker.d")
-	if err = os.MkdirAll(brokersConfPath, 0750); err != nil {
-		_ = os.RemoveAll(tmpDir)
-		return "", nil, err
-	}
-	_, brokerCleanup, err := testutils.StartBusBrokerMock(brokersConfPath, "BrokerMock")
-	if err != nil {
-		_ = os.RemoveAll(tmpDir)
-		return "", nil, err
+		wantRefused bool
+	}{
+		"Refuse_concurrent_attempt_beyond_max_attempts": {policy: pam.BruteForcePolicy{MaxAttempts: 1}, wantRefused: true},
+
+		"Allow_concurrent_attempt_below_max_attempts":      {policy: pam.BruteForcePolicy{MaxAttempts: 3}},
+		"Allow_concurrent_attempt_when_policy_is_disabled": {},
 	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
 
-	return brokersConfPath, func() {
-		brokerCleanup()
-		_ = os.RemoveAll(tmpDir)
-	}, nil
-}
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, nil, globalBrokerManager, &pm, pam.WithBruteForcePolicy(tc.policy))
+
+			// The authentications of this user only complete once cancelled.
+			username := t.Name() + testutils.IDSeparator + "IA_wait"
+			startSession := func() string {
+				t.Helper()
+
+				sbResp, err := client.SelectBroker(context.Background(), &authd.SBRequest{
+					BrokerId: mockBrokerGeneratedID,
+					Username: username,
+					Service:  "sshd",
+					Mode:     authd.SessionMode_LOGIN,
+				})
+				require.NoError(t, err, "Setup: failed to create session for tests")
+				return sbResp.GetSessionId()
+			}
+			firstSessionID := startSession()
+			secondSessionID := startSession()
 
-// newPAMClient returns a new GRPC PAM client for tests connected to brokerManager with the given database and
-// permissionmanager.
-// If the one passed is nil, this function will create the database and close it upon test teardown.
-func newPamClient(t *testing.T, m *users.Manager, brokerManager *brokers.Manager, pm *permissions.Manager) (client authd.PAMClient) {
-	t.Helper()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			firstDone := make(chan error, 1)
+			go func() {
+				_, err := client.IsAuthenticated(ctx, &authd.IARequest{
+					SessionId:          firstSessionID,
+					AuthenticationData: &authd.IARequest_AuthenticationData{},
+				})
+				firstDone <- err
+			}()
+			require.Eventually(t, func() bool {
+				resp, err := client.ListSessions(context.Background(), &authd.Empty{})
+				if err != nil {
+					return false
+				}
+				return slices.ContainsFunc(resp.GetSessions(), func(s *authd.LSResponse_SessionInfo) bool {
+					return s.GetSessionId() == firstSessionID && s.GetStage() == "authenticating"
+				})
+			}, 5*time.Second, 10*time.Millisecond, "Setup: first authentication should be in progress")
+
+			// The second authentication waits for the broker too if it's allowed, so only give it a little time.
+			secondCtx, secondCancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer secondCancel()
+			_, err := client.IsAuthenticated(secondCtx, &authd.IARequest{
+				SessionId:          secondSessionID,
+				AuthenticationData: &authd.IARequest_AuthenticationData{},
+			})
 
-	// socket path is limited in length.
-	tmpDir, err := os.MkdirTemp("", "authd-socket-dir")
-	require.NoError(t, err, "Setup: could not setup temporary socket dir path")
-	t.Cleanup(func() { _ = os.RemoveAll(tmpDir) })
-	socketPath := filepath.Join(tmpDir, "authd.sock")
+			cancel()
+			<-firstDone
 
-	lis, err := net.Listen("unix", socketPath)
-	require.NoError(t, err, "Setup: could not create unix socket")
+			if tc.wantRefused {
+				require.Equal(t, codes.ResourceExhausted, status.Code(err), "IsAuthenticated should fail with ResourceExhausted")
+				return
+			}
+			require.NotEqual(t, codes.ResourceExhausted, status.Code(err), "IsAuthenticated should not be refused")
+		})
+	}
+}
 
-	if m == nil {
-		m, err = users.NewManager(users.DefaultConfig, t.TempDir())
-		require.NoError(t, err, "Setup: could not create user manager")
-		t.Cleanup(func() { _ = m.Stop() })
+func TestBruteForceProtectionWithFaillock(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		resetTally bool
+		restart    bool
+
+		wantFailures map[string]uint32
+		wantRefused  bool
+	}{
+		"Record_failures_in_tally_file":                       {wantFailures: map[string]uint32{"gdm-authd": 1, "sshd": 1}, wantRefused: true},
+		"Keep_failures_after_restarting_the_service":          {restart: true, wantFailures: map[string]uint32{"gdm-authd": 1, "sshd": 1}, wantRefused: true},
+		"Forget_failures_when_tally_was_reset":                {resetTally: true, wantFailures: map[string]uint32{}},
+		"Forget_failures_when_tally_was_reset_before_restart": {resetTally: true, restart: true, wantFailures: map[string]uint32{}},
 	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
 
-	service := pam.NewService(context.Background(), m, brokerManager, pm)
+			tallyDir := filepath.Join(t.TempDir(), "faillock")
+			faillockConfig := filepath.Join(t.TempDir(), "faillock.conf")
+			err := os.WriteFile(faillockConfig, []byte("deny = 3\ndir = "+tallyDir+"\n"), 0600)
+			require.NoError(t, err, "Setup: could not write pam_faillock configuration")
 
-	grpcServer := grpc.NewServer(permissions.WithUnixPeerCreds(), grpc.ChainUnaryInterceptor(enableCheckGlobalAccess(service), errmessages.RedactErrorInterceptor))
-	authd.RegisterPAMServer(grpcServer, service)
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		_ = grpcServer.Serve(lis)
-	}()
-	t.Cleanup(func() {
-		grpcServer.Stop()
-		<-done
-	})
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			opts := []pam.Option{
+				pam.WithBruteForcePolicy(pam.BruteForcePolicy{MaxAttempts: 2, Faillock: true}),
+				pam.WithFaillockConfig(faillockConfig),
+			}
+			client, service := newPamClientAndService(t, nil, globalBrokerManager, &pm, opts...)
+
+			// The tally files are named after the users, which can't contain slashes.
+			username := strings.ReplaceAll(t.Name(), "/", "_") + testutils.IDSeparator + "IA_denied"
+			for _, service := range []string{"gdm-authd", "sshd"} {
+				sbResp, err := client.SelectBroker(context.Background(), &authd.SBRequest{
+					BrokerId: mockBrokerGeneratedID,
+					Username: username,
+					Service:  service,
+					Mode:     authd.SessionMode_LOGIN,
+				})
+				require.NoError(t, err, "Setup: failed to create session for tests")
+				_, err = client.IsAuthenticated(context.Background(), &authd.IARequest{
+					SessionId:          sbResp.GetSessionId(),
+					AuthenticationData: &authd.IARequest_AuthenticationData{},
+				})
+				require.NoError(t, err, "Setup: failed authentication should not return an error")
+			}
 
-	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(errmessages.FormatErrorMessage))
-	require.NoError(t, err, "Setup: Could not connect to gRPC server")
+			records, err := faillock.Read(tallyDir, username)
+			require.NoError(t, err, "Setup: could not read tally file")
+			require.Len(t, records, 2, "The failures should be recorded in the tally file")
+			for i, service := range []string{"gdm-authd", "sshd"} {
+				require.Equal(t, service, records[i].Source, "The PAM service should be the source of the record")
+				require.True(t, records[i].Valid(), "The record should be valid")
+			}
 
-	t.Cleanup(func() { _ = conn.Close() }) // We don't care about the error on cleanup
+			require.False(t, service.Busy(), "Busy should be false when the failures are recorded in the tally files")
 
-	return authd.NewPAMClient(conn)
+			if tc.resetTally {
+				require.NoError(t, faillock.Reset(tallyDir, username), "Setup: could not reset tally file")
+			}
+			if tc.restart {
+				// The service starts again, for example after the daemon quit when idle.
+				client = newPamClient(t, nil, globalBrokerManager, &pm, opts...)
+			}
+
+			gfaResp, err := client.GetFailedAuthentications(context.Background(), &authd.GFARequest{Username: username})
+			require.NoError(t, err, "GetFailedAuthentications should not return an error, but did")
+			gotFailures := make(map[string]uint32)
+			for _, source := range gfaResp.GetSources() {
+				gotFailures[source.GetService()] = source.GetFailedAttempts()
+			}
+			require.Equal(t, tc.wantFailures, gotFailures, "GetFailedAuthentications returned unexpected failures")
+			require.Equal(t, tc.wantRefused, gfaResp.GetBackoffUntil() != 0, "GetFailedAuthentications returned an unexpected backoff")
+		})
+	}
 }
 
-// newPermissionManager factors out permission manager creation for tests.
+func TestIsRecentlyAuthenticated(t *testing.T) {
+	t.Parallel()
+
+	strongPolicy := pam.RecentAuthenticationPolicy{
+		Services:  []string{"sudo"},
+		AuthModes: []string{"mode1"},
+		MaxAge:    5 * time.Minute,
+	}
+
+	tests := map[string]struct {
+		policy           pam.RecentAuthenticationPolicy
+		noAuthentication bool
+		authTTY          string
+		elapsed          time.Duration
+		username         string
+		service          string
+		tty              string
+
+		wantGranted bool
+		wantErr     bool
+	}{
+		"Granted_after_a_recent_strong_authentication": {policy: strongPolicy, elapsed: time.Minute, wantGranted: true},
+
+		"Denied_when_policy_is_disabled":                  {},
+		"Denied_when_policy_has_no_max_age":               {policy: pam.RecentAuthenticationPolicy{Services: strongPolicy.Services, AuthModes: strongPolicy.AuthModes}},
+		"Denied_when_service_is_not_in_the_policy":        {policy: strongPolicy, service: "login"},
+		"Denied_when_auth_mode_is_not_strong":             {policy: pam.RecentAuthenticationPolicy{Services: []string{"sudo"}, AuthModes: []string{"mode2"}, MaxAge: time.Hour}},
+		"Denied_when_user_did_not_authenticate":           {policy: strongPolicy, noAuthentication: true},
+		"Denied_when_authentication_is_too_old":           {policy: strongPolicy, elapsed: 6 * time.Minute},
+		"Denied_when_another_user_recently_authenticated": {policy: strongPolicy, username: "another-user"},
+		"Denied_on_another_terminal":                      {policy: strongPolicy, tty: "/dev/pts/2"},
+		"Denied_without_terminal":                         {policy: strongPolicy, tty: "-"},
+		"Denied_when_authenticated_without_terminal":      {policy: strongPolicy, authTTY: "ssh", tty: "ssh"},
+
+		"Error_when_username_is_empty": {policy: strongPolicy, username: "-", wantErr: true},
+		"Error_when_service_is_empty":  {policy: strongPolicy, service: "-", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := clock.NewFake(time.Now())
+
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, nil, globalBrokerManager, &pm,
+				pam.WithRecentAuthenticationPolicy(tc.policy), pam.WithClock(c))
+
+			if tc.authTTY == "" {
+				tc.authTTY = "/dev/pts/1"
+			}
+			switch tc.tty {
+			case "":
+				tc.tty = "/dev/pts/1"
+			case "-":
+				tc.tty = ""
+			}
+
+			const authenticatedUser = "SAM_success_required_entry"
+			if !tc.noAuthentication {
+				sbResp, err := client.SelectBroker(context.Background(), &authd.SBRequest{
+					BrokerId: mockBrokerGeneratedID,
+					Username: t.Name() + testutils.IDSeparator + authenticatedUser,
+					Mode:     authd.SessionMode_LOGIN,
+					Tty:      tc.authTTY,
+				})
+				require.NoError(t, err, "Setup: failed to create session for tests")
+				iaResp := isAuthenticatedWithMode(t, client, sbResp.GetSessionId(), "mode1")
+				require.Equal(t, auth.Granted, iaResp.GetAccess(), "Setup: user should have been authenticated")
+			}
+			c.Advance(tc.elapsed)
+
+			switch tc.username {
+			case "":
+				tc.username = t.Name() + testutils.IDSeparator + authenticatedUser
+			case "-":
+				tc.username = ""
+			}
+			switch tc.service {
+			case "":
+				tc.service = "sudo"
+			case "-":
+				tc.service = ""
+			}
+
+			resp, err := client.IsRecentlyAuthenticated(context.Background(), &authd.IRARequest{
+				Username: tc.username,
+				Service:  tc.service,
+				Tty:      tc.tty,
+			})
+			if tc.wantErr {
+				require.Error(t, err, "IsRecentlyAuthenticated should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "IsRecentlyAuthenticated should not return an error, but did")
+			require.Equal(t, tc.wantGranted, resp.GetGranted(), "IsRecentlyAuthenticated returned an unexpected result")
+		})
+	}
+}
+
+func TestGetSessionActions(t *testing.T) {
+	t.Parallel()
+
+	lingerPolicy := pam.SessionPolicy{LingerGroups: []string{"lingergroup"}}
+
+	tests := map[string]struct {
+		user               string
+		policy             pam.SessionPolicy
+		currentUserNotRoot bool
+
+		wantUID              uint32
+		wantGID              uint32
+		wantEnsureRuntimeDir bool
+		wantEnableLinger     bool
+		wantErr              bool
+	}{
+		"No_actions_when_policy_is_empty":            {user: "userinlingergroup", wantUID: 1111, wantGID: 11111},
+		"Ensure_runtime_dir_when_enabled_by_policy":  {user: "userinlingergroup", policy: pam.SessionPolicy{RuntimeDir: true}, wantUID: 1111, wantGID: 11111, wantEnsureRuntimeDir: true},
+		"Enable_linger_for_member_of_a_linger_group": {user: "userinlingergroup", policy: lingerPolicy, wantUID: 1111, wantGID: 11111, wantEnableLinger: true},
+		"Ignore_unknown_linger_groups": {
+			user: "userinlingergroup", policy: pam.SessionPolicy{LingerGroups: []string{"unknown", "lingergroup"}}, wantUID: 1111, wantGID: 11111, wantEnableLinger: true,
+		},
+
+		"Do_not_enable_linger_for_users_not_in_a_linger_group": {user: "userinothergroup", policy: lingerPolicy, wantUID: 2222, wantGID: 22222},
+
+		"Error_when_username_is_empty":   {policy: lingerPolicy, wantErr: true},
+		"Error_when_user_is_not_handled": {user: "nonexistent", policy: lingerPolicy, wantErr: true},
+		"Error_when_not_root":            {user: "userinlingergroup", currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "session-actions.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm, pam.WithSessionPolicy(tc.policy))
+
+			resp, err := client.GetSessionActions(context.Background(), &authd.GSARequest{Username: tc.user})
+			if tc.wantErr {
+				require.Error(t, err, "GetSessionActions should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "GetSessionActions should not return an error, but did")
+
+			require.Equal(t, tc.wantUID, resp.GetUid(), "GetSessionActions returned an unexpected UID")
+			require.Equal(t, tc.wantGID, resp.GetGid(), "GetSessionActions returned an unexpected GID")
+			require.Equal(t, tc.wantEnsureRuntimeDir, resp.GetEnsureRuntimeDir(), "GetSessionActions returned an unexpected runtime dir action")
+			require.Equal(t, tc.wantEnableLinger, resp.GetEnableLinger(), "GetSessionActions returned an unexpected linger action")
+		})
+	}
+}
+
+func TestCheckLoginPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		user               string
+		policy             pam.LoginPolicy
+		currentUserNotRoot bool
+
+		wantErr         bool
+		wantErrCode     codes.Code
+		wantMissingRole bool
+	}{
+		"Allow_all_users_when_policy_is_empty":          {user: "userinallowedgroup"},
+		"Allow_user_in_allowed_users":                   {user: "userinothergroup", policy: pam.LoginPolicy{AllowedUsers: []string{"userinothergroup"}}},
+		"Allow_member_of_an_allowed_group":              {user: "userinallowedgroup", policy: pam.LoginPolicy{AllowedGroups: []string{"unknown", "allowedgroup"}}},
+		"Allow_user_not_denied_when_no_user_is_allowed": {user: "userinothergroup", policy: pam.LoginPolicy{DenyUsers: []string{"userinallowedgroup"}}},
+		"Allow_user_with_a_role_matching_a_machine_tag": {user: "userinallowedgroup", policy: pam.LoginPolicy{MachineTags: []string{"servers", "dev-laptops"}}},
+		"Allow_user_with_a_role_and_in_allowed_group": {
+			user: "userinallowedgroup", policy: pam.LoginPolicy{AllowedGroups: []string{"allowedgroup"}, MachineTags: []string{"dev-laptops"}},
+		},
+
+		"Error_when_user_is_denied": {
+			user: "userinallowedgroup", policy: pam.LoginPolicy{DenyUsers: []string{"userinallowedgroup"}}, wantErr: true, wantErrCode: codes.PermissionDenied,
+		},
+		"Error_when_user_is_denied_even_if_allowed": {
+			user:    "userinallowedgroup",
+			policy:  pam.LoginPolicy{AllowedGroups: []string{"allowedgroup"}, DenyUsers: []string{"userinallowedgroup"}},
+			wantErr: true, wantErrCode: codes.PermissionDenied,
+		},
+		"Error_when_user_is_neither_allowed_nor_member_of_an_allowed_group": {
+			user:    "userinothergroup",
+			policy:  pam.LoginPolicy{AllowedUsers: []string{"userinallowedgroup"}, AllowedGroups: []string{"allowedgroup"}},
+			wantErr: true, wantErrCode: codes.PermissionDenied,
+		},
+		"Error_when_user_has_no_role_matching_a_machine_tag": {
+			user: "userinallowedgroup", policy: pam.LoginPolicy{MachineTags: []string{"servers"}}, wantErr: true, wantErrCode: codes.PermissionDenied, wantMissingRole: true,
+		},
+		"Error_when_user_has_no_role": {
+			user: "userinothergroup", policy: pam.LoginPolicy{MachineTags: []string{"dev-laptops"}}, wantErr: true, wantErrCode: codes.PermissionDenied, wantMissingRole: true,
+		},
+		"Error_when_user_has_a_role_but_is_not_allowed": {
+			user:    "userinallowedgroup",
+			policy:  pam.LoginPolicy{AllowedUsers: []string{"userinothergroup"}, MachineTags: []string{"dev-laptops"}},
+			wantErr: true, wantErrCode: codes.PermissionDenied,
+		},
+		"Error_when_username_is_empty":   {wantErr: true, wantErrCode: codes.InvalidArgument},
+		"Error_when_user_is_not_handled": {user: "nonexistent", wantErr: true, wantErrCode: codes.NotFound},
+		"Error_when_not_root":            {user: "userinallowedgroup", currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "login-policy.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm, pam.WithLoginPolicy(tc.policy))
+
+			_, err = client.CheckLoginPolicy(context.Background(), &authd.CLPRequest{Username: tc.user})
+			if tc.wantErr {
+				require.Error(t, err, "CheckLoginPolicy should return an error, but did not")
+				if tc.wantErrCode != codes.OK {
+					require.Equal(t, tc.wantErrCode, status.Code(err), "CheckLoginPolicy returned an unexpected error code")
+				}
+				require.Equal(t, tc.wantMissingRole, authderrors.SentinelFromStatus(status.Convert(err)) == authderrors.ErrMissingRole,
+					"CheckLoginPolicy should only deny the users without a role of the machine as such")
+				return
+			}
+			require.NoError(t, err, "CheckLoginPolicy should not return an error, but did")
+		})
+	}
+}
+
+func TestConsent(t *testing.T) {
+	t.Parallel()
+
+	policy := pam.ConsentPolicy{Title: "IT policy", Message: "Use this machine responsibly."}
+	required := layouts.Required
+	consentLayout := &authd.UILayout{Type: layouts.Consent, Label: &optional, Content: &required}
+
+	tests := map[string]struct {
+		policy               pam.ConsentPolicy
+		answer               string
+		noConsentLayout      bool
+		alreadyAuthenticated bool
+
+		wantAccess    string
+		wantNoConsent bool
+		wantErr       bool
+	}{
+		"Granted_without_consent_when_there_is_no_message": {wantAccess: auth.Granted, wantNoConsent: true},
+		"Granted_when_consent_is_accepted":                 {policy: policy, answer: layouts.True, wantAccess: auth.Granted},
+		"Granted_without_consent_when_already_accepted": {
+			policy: policy, alreadyAuthenticated: true, wantAccess: auth.Granted, wantNoConsent: true,
+		},
+		"Denied_when_consent_is_declined": {policy: policy, answer: layouts.False, wantAccess: auth.Denied},
+
+		"Error_when_client_does_not_support_the_consent_layout": {policy: policy, noConsentLayout: true, wantErr: true},
+		"Error_when_answer_is_invalid":                          {policy: policy, answer: "maybe", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := newPermissionManager(t, false)
+			client := newPamClient(t, nil, globalBrokerManager, &pm, pam.WithConsentPolicy(tc.policy))
+
+			const username = "SAM_success_required_entry"
+			if tc.alreadyAuthenticated {
+				sessionID := startSession(t, client, username)
+				iaResp := isAuthenticatedWithMode(t, client, sessionID, "mode1")
+				require.Equal(t, auth.Next, iaResp.GetAccess(), "Setup: consent should have been required")
+				answerConsent(t, client, sessionID, consentLayout, layouts.True)
+			}
+
+			sessionID := startSession(t, client, username)
+			iaResp := isAuthenticatedWithMode(t, client, sessionID, "mode1")
+			if tc.wantNoConsent {
+				require.Equal(t, tc.wantAccess, iaResp.GetAccess(), "IsAuthenticated returned an unexpected access")
+				return
+			}
+			require.Equal(t, auth.Next, iaResp.GetAccess(), "IsAuthenticated should require the consent of the user")
+
+			supportedLayouts := []*authd.UILayout{requiredEntry, consentLayout}
+			if tc.noConsentLayout {
+				supportedLayouts = []*authd.UILayout{requiredEntry}
+			}
+			gamResp, err := client.GetAuthenticationModes(context.Background(), &authd.GAMRequest{
+				SessionId:          sessionID,
+				SupportedUiLayouts: supportedLayouts,
+			})
+			if tc.wantErr && tc.noConsentLayout {
+				require.Error(t, err, "GetAuthenticationModes should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "GetAuthenticationModes should not return an error, but did")
+			require.Len(t, gamResp.GetAuthenticationModes(), 1, "GetAuthenticationModes should only return the consent mode")
+
+			samResp, err := client.SelectAuthenticationMode(context.Background(), &authd.SAMRequest{
+				SessionId:            sessionID,
+				AuthenticationModeId: gamResp.GetAuthenticationModes()[0].GetId(),
+			})
+			require.NoError(t, err, "SelectAuthenticationMode should not return an error, but did")
+			require.Equal(t, layouts.Consent, samResp.GetUiLayoutInfo().GetType(), "SelectAuthenticationMode returned an unexpected layout")
+			require.Equal(t, tc.policy.Title, samResp.GetUiLayoutInfo().GetLabel(), "SelectAuthenticationMode returned an unexpected title")
+			require.Equal(t, tc.policy.Message, samResp.GetUiLayoutInfo().GetContent(), "SelectAuthenticationMode returned an unexpected message")
+
+			iaResp, err = client.IsAuthenticated(context.Background(), &authd.IARequest{
+				SessionId: sessionID,
+				AuthenticationData: &authd.IARequest_AuthenticationData{
+					Item: &authd.IARequest_AuthenticationData_Consent{Consent: tc.answer},
+				},
+			})
+			if tc.wantErr {
+				require.Error(t, err, "IsAuthenticated should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "IsAuthenticated should not return an error, but did")
+			require.Equal(t, tc.wantAccess, iaResp.GetAccess(), "IsAuthenticated returned an unexpected access")
+			if tc.wantAccess == auth.Denied {
+				require.Contains(t, iaResp.GetMsg(), auth.ErrorCodeConsentDenied, "IsAuthenticated should explain the consent was declined")
+			}
+		})
+	}
+}
+
+func TestSetLocalPIN(t *testing.T) {
+	t.Parallel()
+
+	policy := pam.LocalPINPolicy{Services: []string{"sudo"}}
+
+	tests := map[string]struct {
+		username    string
+		pin         string
+		policy      pam.LocalPINPolicy
+		noLogin     bool
+		elapsed     time.Duration
+		anotherUser bool
+
+		wantErr bool
+	}{
+		"Set_local_PIN_of_user": {pin: "123456", policy: policy},
+		"Set_local_PIN_with_policy_min_length": {
+			pin: "1234", policy: pam.LocalPINPolicy{Services: []string{"sudo"}, MinLength: 4},
+		},
+		"Set_local_PIN_within_policy_delay_after_login": {
+			pin: "123456", policy: pam.LocalPINPolicy{Services: []string{"sudo"}, SetWithin: time.Hour}, elapsed: 30 * time.Minute,
+		},
+
+		"Error_when_policy_is_disabled":              {pin: "123456", wantErr: true},
+		"Error_when_username_is_empty":               {username: "-", pin: "123456", policy: policy, wantErr: true},
+		"Error_when_PIN_is_too_short":                {pin: "12345", policy: policy, wantErr: true},
+		"Error_when_PIN_is_not_numeric":              {pin: "abcdef", policy: policy, wantErr: true},
+		"Error_when_user_never_logged_in":            {username: "nonexistent", pin: "123456", policy: policy, wantErr: true},
+		"Error_when_user_did_not_log_in_with_broker": {username: "user1", pin: "123456", policy: policy, noLogin: true, wantErr: true},
+		"Error_when_broker_login_is_too_old":         {pin: "123456", policy: policy, elapsed: 16 * time.Minute, wantErr: true},
+		"Error_when_requested_by_another_user":       {pin: "123456", policy: policy, anotherUser: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "local-pin.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			c := clock.NewFake(time.Now())
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client, service := newPamClientAndService(t, m, globalBrokerManager, &pm, pam.WithLocalPINPolicy(tc.policy), pam.WithClock(c))
+
+			switch tc.username {
+			case "":
+				authenticateWithMode(t, client, "SAM_success_required_entry", "mode1")
+				tc.username = t.Name() + testutils.IDSeparator + "SAM_success_required_entry"
+			case "-":
+				tc.username = ""
+			}
+			c.Advance(tc.elapsed)
+
+			// The PIN is set by the user themselves.
+			var uid uint32
+			if u, err := m.UserByName(tc.username); err == nil {
+				uid = u.UID
+			}
+			if tc.anotherUser {
+				uid++
+			}
+			ctx := permissions.Z_ForTests_ContextWithPeerUID(context.Background(), uid)
+
+			_, err = service.SetLocalPIN(ctx, &authd.SLPRequest{Username: tc.username, Pin: tc.pin})
+			if tc.wantErr {
+				require.Error(t, err, "SetLocalPIN should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SetLocalPIN should not return an error, but did")
+
+			resp, err := client.GetLocalPINStatus(context.Background(), &authd.GLPSRequest{Username: tc.username, Service: "sudo"})
+			require.NoError(t, err, "GetLocalPINStatus should not return an error, but did")
+			require.True(t, resp.GetRegistered(), "Local PIN should be registered")
+			require.True(t, resp.GetUsable(), "Local PIN should be usable for the service of the policy")
+
+			_, err = client.RemoveLocalPIN(context.Background(), &authd.RLPRequest{Username: tc.username})
+			require.NoError(t, err, "RemoveLocalPIN should not return an error, but did")
+			resp, err = client.GetLocalPINStatus(context.Background(), &authd.GLPSRequest{Username: tc.username})
+			require.NoError(t, err, "GetLocalPINStatus should not return an error, but did")
+			require.False(t, resp.GetRegistered(), "Local PIN should not be registered anymore")
+
+			_, err = client.RemoveLocalPIN(context.Background(), &authd.RLPRequest{Username: tc.username})
+			require.Error(t, err, "RemoveLocalPIN should return an error when there is no local PIN")
+		})
+	}
+}
+
+func TestSetUserGecos(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(s string) *string { return &s }
+
+	tests := map[string]struct {
+		req                *authd.SUGRequest
+		currentUserNotRoot bool
+
+		wantGecos string
+		wantErr   bool
+	}{
+		"Set_full_name_of_user": {req: &authd.SUGRequest{Username: "user1", FullName: ptr("Jane Doe")}, wantGecos: "Jane Doe,Room 1"},
+		"Set_phones_of_user": {
+			req:       &authd.SUGRequest{Username: "user1", WorkPhone: ptr("555-0100"), HomePhone: ptr("555-0199")},
+			wantGecos: "User1,Room 1,555-0100,555-0199",
+		},
+		"Clear_room_of_user": {req: &authd.SUGRequest{Username: "user1", Room: ptr("")}, wantGecos: "User1"},
+
+		"Error_when_username_is_empty":    {req: &authd.SUGRequest{FullName: ptr("Jane Doe")}, wantErr: true},
+		"Error_when_no_field_is_given":    {req: &authd.SUGRequest{Username: "user1"}, wantErr: true},
+		"Error_when_field_is_invalid":     {req: &authd.SUGRequest{Username: "user1", Room: ptr("Room:1")}, wantErr: true},
+		"Error_when_user_never_logged_in": {req: &authd.SUGRequest{Username: "nonexistent", FullName: ptr("Jane Doe")}, wantErr: true},
+		"Error_when_not_root": {
+			req: &authd.SUGRequest{Username: "user1", FullName: ptr("Jane Doe")}, currentUserNotRoot: true, wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "set-user-gecos.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			_, err = client.SetUserGecos(context.Background(), tc.req)
+			if tc.wantErr {
+				require.Error(t, err, "SetUserGecos should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SetUserGecos should not return an error, but did")
+
+			u, err := m.UserByName(tc.req.GetUsername())
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			require.Equal(t, tc.wantGecos, u.Gecos, "SetUserGecos should update the GECOS of the user")
+		})
+	}
+}
+
+func TestSetUserShell(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username           string
+		shell              string
+		currentUserNotRoot bool
+
+		wantErr bool
+	}{
+		"Set_login_shell_of_user": {username: "user1", shell: "/usr/bin/zsh"},
+
+		"Error_when_username_is_empty":    {shell: "/usr/bin/zsh", wantErr: true},
+		"Error_when_shell_is_empty":       {username: "user1", wantErr: true},
+		"Error_when_shell_is_not_listed":  {username: "user1", shell: "/usr/bin/fish", wantErr: true},
+		"Error_when_user_never_logged_in": {username: "nonexistent", shell: "/usr/bin/zsh", wantErr: true},
+		"Error_when_not_root":             {username: "user1", shell: "/usr/bin/zsh", currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "set-user-shell.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir, users.WithShellsFile(filepath.Join(testutils.TestFamilyPath(t), "shells")))
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			_, err = client.SetUserShell(context.Background(), &authd.SUSRequest{Username: tc.username, Shell: tc.shell})
+			if tc.wantErr {
+				require.Error(t, err, "SetUserShell should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SetUserShell should not return an error, but did")
+
+			u, err := m.UserByName(tc.username)
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			require.Equal(t, tc.shell, u.Shell, "SetUserShell should update the login shell of the user")
+		})
+	}
+}
+
+func TestSetUserHome(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username           string
+		home               string
+		validateOnly       bool
+		currentUserNotRoot bool
+
+		wantHome string
+		wantErr  bool
+	}{
+		"Set_home_directory_of_user":               {username: "user1", home: "/srv/user1", wantHome: "/srv/user1"},
+		"Set_cleaned_home_directory_of_user":       {username: "user1", home: "/srv//user1/", wantHome: "/srv/user1"},
+		"Keep_home_directory_when_only_validating": {username: "user1", home: "/srv/user1", validateOnly: true, wantHome: "/home/user1"},
+
+		"Error_when_username_is_empty":      {home: "/srv/user1", wantErr: true},
+		"Error_when_home_is_empty":          {username: "user1", wantErr: true},
+		"Error_when_home_is_not_absolute":   {username: "user1", home: "srv/user1", wantErr: true},
+		"Error_when_home_contains_colon":    {username: "user1", home: "/srv/user1:/bin/sh", wantErr: true},
+		"Error_when_home_contains_newline":  {username: "user1", home: "/srv/user1\nroot", wantErr: true},
+		"Error_when_user_never_logged_in":   {username: "nonexistent", home: "/srv/user1", wantErr: true},
+		"Error_when_validating_as_not_root": {username: "user1", home: "/srv/user1", validateOnly: true, currentUserNotRoot: true, wantErr: true},
+		"Error_when_not_root":               {username: "user1", home: "/srv/user1", currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "set-user-home.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			resp, err := client.SetUserHome(context.Background(), &authd.SUHRequest{
+				Username:     tc.username,
+				Home:         tc.home,
+				ValidateOnly: tc.validateOnly,
+			})
+			if tc.wantErr {
+				require.Error(t, err, "SetUserHome should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SetUserHome should not return an error, but did")
+			require.Equal(t, "/home/user1", resp.GetPreviousHome(), "SetUserHome should return the previous home directory")
+			require.Equal(t, uint32(1111), resp.GetUid(), "SetUserHome should return the UID of the user")
+			require.Equal(t, uint32(11111), resp.GetGid(), "SetUserHome should return the GID of the user")
+
+			u, err := m.UserByName(tc.username)
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			require.Equal(t, tc.wantHome, u.Dir, "SetUserHome should update the home directory of the user")
+		})
+	}
+}
+
+func TestUserOverrides(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(s string) *string { return &s }
+	user2 := &authd.UserOverride{Username: "user2", Shell: "/usr/bin/zsh", Home: "/srv/user2", LocalGroups: []string{"docker", "sudo"}}
+
+	tests := map[string]struct {
+		set                *authd.SUORequest
+		unset              *authd.UUORequest
+		currentUserNotRoot bool
+
+		want    []*authd.UserOverride
+		wantErr bool
+	}{
+		"List_overrides": {want: []*authd.UserOverride{user2}},
+		"Set_override_of_user": {
+			set: &authd.SUORequest{Username: "user1", Shell: ptr("/usr/bin/zsh"), Gecos: ptr("Jane Doe"), AddLocalGroups: []string{"sudo"}},
+			want: []*authd.UserOverride{
+				{Username: "user1", Shell: "/usr/bin/zsh", Gecos: "Jane Doe", LocalGroups: []string{"sudo"}},
+				user2,
+			},
+		},
+		"Set_override_keeping_other_attributes": {
+			set: &authd.SUORequest{Username: "user2", Home: ptr("/home/user2"), AddLocalGroups: []string{"adm"}},
+			want: []*authd.UserOverride{
+				{Username: "user2", Shell: "/usr/bin/zsh", Home: "/home/user2", LocalGroups: []string{"adm", "docker", "sudo"}},
+			},
+		},
+		"Unset_some_attributes_of_override": {
+			unset: &authd.UUORequest{Username: "user2", Shell: true, RemoveLocalGroups: []string{"sudo"}},
+			want:  []*authd.UserOverride{{Username: "user2", Home: "/srv/user2", LocalGroups: []string{"docker"}}},
+		},
+		"Unset_whole_override": {unset: &authd.UUORequest{Username: "user2"}},
+
+		"Error_when_setting_without_username":   {set: &authd.SUORequest{Shell: ptr("/usr/bin/zsh")}, wantErr: true},
+		"Error_when_setting_no_attribute":       {set: &authd.SUORequest{Username: "user1"}, wantErr: true},
+		"Error_when_setting_invalid_shell":      {set: &authd.SUORequest{Username: "user1", Shell: ptr("/usr/bin/fish")}, wantErr: true},
+		"Error_when_setting_relative_home":      {set: &authd.SUORequest{Username: "user1", Home: ptr("home")}, wantErr: true},
+		"Error_when_setting_for_unknown_user":   {set: &authd.SUORequest{Username: "nonexistent", Shell: ptr("/usr/bin/zsh")}, wantErr: true},
+		"Error_when_unsetting_without_username": {unset: &authd.UUORequest{}, wantErr: true},
+		"Error_when_unsetting_for_unknown_user": {unset: &authd.UUORequest{Username: "nonexistent"}, wantErr: true},
+		"Error_when_not_root": {
+			set: &authd.SUORequest{Username: "user1", Shell: ptr("/usr/bin/zsh")}, currentUserNotRoot: true, wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "user-overrides.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir, users.WithShellsFile(filepath.Join(testutils.TestFamilyPath(t), "shells")))
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			if tc.set != nil {
+				_, err = client.SetUserOverride(context.Background(), tc.set)
+			}
+			if tc.unset != nil {
+				_, err = client.UnsetUserOverride(context.Background(), tc.unset)
+			}
+			if tc.wantErr {
+				require.Error(t, err, "Changing the override should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Changing the override should not return an error, but did")
+
+			resp, err := client.ListUserOverrides(context.Background(), &authd.Empty{})
+			require.NoError(t, err, "ListUserOverrides should not return an error, but did")
+			require.Len(t, resp.GetOverrides(), len(tc.want), "ListUserOverrides returned an unexpected number of overrides")
+			for i, want := range tc.want {
+				require.True(t, proto.Equal(want, resp.GetOverrides()[i]), "ListUserOverrides returned an unexpected override: %v", resp.GetOverrides()[i])
+			}
+		})
+	}
+}
+
+func TestGetUserAccountInfo(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username           string
+		currentUserNotRoot bool
+		// peerUID is the UID of the non-root user performing the request, if set.
+		peerUID uint32
+
+		want    *authd.GUAIResponse
+		wantErr bool
+	}{
+		"Get_account_information_of_user_with_broker": {
+			username: "userwithbroker",
+			want: &authd.GUAIResponse{
+				ManagedBy:              "Managed by BrokerMock",
+				BrokerId:               "MOCKBROKERID",
+				BrokerName:             "BrokerMock",
+				DisplayName:            "Jane Doe",
+				HasAvatar:              true,
+				PasswordChangePossible: true,
+			},
+		},
+		"Get_account_information_of_user_of_local_broker": {
+			username: "userwithlocalbroker",
+			want: &authd.GUAIResponse{
+				ManagedBy:   "Managed by local",
+				BrokerId:    brokers.LocalBrokerName,
+				BrokerName:  brokers.LocalBrokerName,
+				DisplayName: "userwithlocalbroker",
+			},
+		},
+		"User_whose_broker_is_not_loaded_is_disabled": {
+			username: "userwithremovedbroker",
+			want:     &authd.GUAIResponse{ManagedBy: "Managed by authd", BrokerId: "removed-broker-id", Disabled: true},
+		},
+		"Get_account_information_of_own_account_as_non_root_user": {
+			username: "userwithlocalbroker",
+			peerUID:  2222,
+			want: &authd.GUAIResponse{
+				ManagedBy:   "Managed by local",
+				BrokerId:    brokers.LocalBrokerName,
+				BrokerName:  brokers.LocalBrokerName,
+				DisplayName: "userwithlocalbroker",
+			},
+		},
+
+		"Error_when_username_is_empty":                                                  {wantErr: true},
+		"Error_when_user_never_logged_in":                                               {username: "nonexistent", wantErr: true},
+		"Error_when_not_root":                                                           {username: "userwithbroker", currentUserNotRoot: true, wantErr: true},
+		"Error_when_non_root_user_gets_account_information_of_another_user":             {username: "userwithbroker", peerUID: 2222, wantErr: true},
+		"Error_when_non_root_user_gets_account_information_of_user_who_never_logged_in": {username: "nonexistent", peerUID: 2222, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			d, err := os.ReadFile(filepath.Join(testutils.TestFamilyPath(t), "get-user-account-info.db"))
+			require.NoError(t, err, "Setup: could not read fixture database file")
+			d = bytes.ReplaceAll(d, []byte("MOCKBROKERID"), []byte(mockBrokerGeneratedID))
+			err = db.Z_ForTests_CreateDBFromYAMLReader(bytes.NewBuffer(d), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot || tc.peerUID != 0)
+			client, service := newPamClientAndService(t, m, globalBrokerManager, &pm)
+
+			var got *authd.GUAIResponse
+			if tc.peerUID != 0 {
+				ctx := permissions.Z_ForTests_ContextWithPeerUID(context.Background(), tc.peerUID)
+				got, err = service.GetUserAccountInfo(ctx, &authd.GUAIRequest{Username: tc.username})
+			} else {
+				got, err = client.GetUserAccountInfo(context.Background(), &authd.GUAIRequest{Username: tc.username})
+			}
+			if tc.wantErr {
+				require.Error(t, err, "GetUserAccountInfo should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "GetUserAccountInfo should not return an error, but did")
+
+			if tc.want.BrokerId == "MOCKBROKERID" {
+				tc.want.BrokerId = mockBrokerGeneratedID
+			}
+			require.True(t, proto.Equal(tc.want, got), "GetUserAccountInfo returned unexpected information: %v", got)
+		})
+	}
+}
+
+func TestWhoisUID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		uid                uint32
+		currentUserNotRoot bool
+
+		want    *authd.WUIDResponse
+		wantErr bool
+	}{
+		"Get_user_of_UID":         {uid: 1111, want: &authd.WUIDResponse{Username: "user1"}},
+		"Get_deleted_user_of_UID": {uid: 2222, want: &authd.WUIDResponse{Username: "olduser", DeletedAt: 1700000000}},
+
+		"Error_when_UID_was_never_used": {uid: 3333, wantErr: true},
+		"Error_when_not_root":           {uid: 1111, currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "whois-uid.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			got, err := client.WhoisUID(context.Background(), &authd.WUIDRequest{Uid: tc.uid})
+			if tc.wantErr {
+				require.Error(t, err, "WhoisUID should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "WhoisUID should not return an error, but did")
+			require.True(t, proto.Equal(tc.want, got), "WhoisUID returned unexpected information: %v", got)
+		})
+	}
+}
+
+func TestWhoisID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id                 uint32
+		currentUserNotRoot bool
+
+		want    *authd.WIDResponse
+		wantErr bool
+	}{
+		"Get_owner_of_UID": {id: 1111, want: &authd.WIDResponse{Owners: []*authd.WIDResponse_Owner{
+			{Name: "user1", Source: users.IDSourceUser},
+		}}},
+		"Get_owner_of_GID": {id: 11111, want: &authd.WIDResponse{Owners: []*authd.WIDResponse_Owner{
+			{Name: "user1", Source: users.IDSourceGroup},
+		}}},
+		"Get_deleted_owner_of_UID": {id: 2222, want: &authd.WIDResponse{Owners: []*authd.WIDResponse_Owner{
+			{Name: "olduser", Source: users.IDSourceDeletedUser, DeletedAt: 1700000000},
+		}}},
+
+		"Error_when_ID_was_never_used": {id: 3333, wantErr: true},
+		"Error_when_not_root":          {id: 1111, currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "whois-id.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			// Don't look up the owners in the passwd and group files of the system.
+			m, err := users.NewManager(usersConfig, dbDir, users.WithPasswdFile(os.DevNull), users.WithGroupFile(os.DevNull))
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			got, err := client.WhoisID(context.Background(), &authd.WIDRequest{Id: tc.id})
+			if tc.wantErr {
+				require.Error(t, err, "WhoisID should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "WhoisID should not return an error, but did")
+			require.True(t, proto.Equal(tc.want, got), "WhoisID returned unexpected owners: %v", got)
+		})
+	}
+}
+
+func TestReconcileLocalGroups(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		currentUserNotRoot bool
+
+		wantErr bool
+	}{
+		"Nothing_to_reconcile_when_users_have_no_local_groups": {},
+
+		"Error_when_not_root": {currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "TestWhoisID", "whois-id.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			got, err := client.ReconcileLocalGroups(context.Background(), &authd.Empty{})
+			if tc.wantErr {
+				require.Error(t, err, "ReconcileLocalGroups should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "ReconcileLocalGroups should not return an error, but did")
+			require.Empty(t, got.GetUsers(), "ReconcileLocalGroups should not add any user to local groups")
+		})
+	}
+}
+
+func TestGetVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		currentUserNotRoot bool
+	}{
+		"Get_the_version_of_the_daemon":               {},
+		"Get_the_version_of_the_daemon_when_not_root": {currentUserNotRoot: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := users.NewManager(usersConfig, t.TempDir())
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			got, err := client.GetVersion(context.Background(), &authd.Empty{})
+			require.NoError(t, err, "GetVersion should not return an error, but did")
+			require.Equal(t, consts.Version, got.GetVersion(), "GetVersion should return the version of the daemon")
+			require.Equal(t, uint32(consts.GDMProtocolVersion), got.GetGdmProtocolVersion(), "GetVersion should return the GDM protocol version")
+			require.Equal(t, uint32(consts.BrokerProtocolVersion), got.GetBrokerProtocolVersion(), "GetVersion should return the broker protocol version")
+			require.Equal(t, runtime.Version(), got.GetGoVersion(), "GetVersion should return the Go version the daemon was built with")
+		})
+	}
+}
+
+func TestSetDebugSocket(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		enable             bool
+		alreadyEnabled     bool
+		currentUserNotRoot bool
+
+		wantErr bool
+	}{
+		"Enable_the_debug_socket":                   {enable: true},
+		"Enable_the_debug_socket_already_enabled":   {enable: true, alreadyEnabled: true},
+		"Disable_the_debug_socket":                  {alreadyEnabled: true},
+		"Disable_the_debug_socket_already_disabled": {},
+
+		"Error_when_not_root": {enable: true, currentUserNotRoot: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// socket path is limited in length.
+			tmpDir, err := os.MkdirTemp("", "authd-debug-dir")
+			require.NoError(t, err, "Setup: could not setup temporary socket dir path")
+			t.Cleanup(func() { _ = os.RemoveAll(tmpDir) })
+			socketPath := filepath.Join(tmpDir, "authd-debug.sock")
+
+			debugServer := debugserver.New(socketPath)
+			t.Cleanup(func() { _ = debugServer.Stop() })
+			if tc.alreadyEnabled {
+				require.NoError(t, debugServer.Start(), "Setup: could not start debug server")
+			}
+
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, nil, globalBrokerManager, &pm, pam.WithDebugServer(debugServer))
+
+			got, err := client.SetDebugSocket(context.Background(), &authd.SDSRequest{Enable: tc.enable})
+			if tc.wantErr {
+				require.Error(t, err, "SetDebugSocket should return an error, but did not")
+				require.Equal(t, tc.alreadyEnabled, debugServer.Running(), "SetDebugSocket should not change the debug server on error")
+				return
+			}
+			require.NoError(t, err, "SetDebugSocket should not return an error, but did")
+			require.Equal(t, socketPath, got.GetSocketPath(), "SetDebugSocket should return the path of the debug socket")
+			require.Equal(t, tc.enable, got.GetEnabled(), "SetDebugSocket should return whether the debug socket is enabled")
+			require.Equal(t, tc.enable, debugServer.Running(), "SetDebugSocket should start or stop the debug server")
+			if tc.enable {
+				require.FileExists(t, socketPath, "The debug socket should exist once enabled")
+			} else {
+				require.NoFileExists(t, socketPath, "The debug socket should not exist once disabled")
+			}
+		})
+	}
+}
+
+func TestSetUserDisplayName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username           string
+		displayName        string
+		currentUserNotRoot bool
+		// peerUID is the UID of the non-root user performing the request, if set.
+		peerUID uint32
+
+		wantGecos string
+		wantErr   bool
+	}{
+		"Set_display_name_of_user":                         {username: "user1", displayName: "Jane Doe", wantGecos: "Jane Doe,Room 1"},
+		"Clear_display_name_of_user":                       {username: "user1", wantGecos: ",Room 1"},
+		"Set_display_name_of_own_account_as_non_root_user": {username: "user1", displayName: "Jane Doe", peerUID: 1111, wantGecos: "Jane Doe,Room 1"},
+
+		"Error_when_username_is_empty":                               {displayName: "Jane Doe", wantErr: true},
+		"Error_when_display_name_is_invalid":                         {username: "user1", displayName: "Jane:Doe", wantErr: true},
+		"Error_when_user_never_logged_in":                            {username: "nonexistent", displayName: "Jane Doe", wantErr: true},
+		"Error_when_not_root":                                        {username: "user1", displayName: "Jane Doe", currentUserNotRoot: true, wantErr: true},
+		"Error_when_non_root_user_sets_display_name_of_another_user": {username: "user1", displayName: "Jane Doe", peerUID: 2222, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "set-user-display-name.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot || tc.peerUID != 0)
+			client, service := newPamClientAndService(t, m, globalBrokerManager, &pm)
+
+			req := &authd.SUDNRequest{Username: tc.username, DisplayName: tc.displayName}
+			if tc.peerUID != 0 {
+				_, err = service.SetUserDisplayName(permissions.Z_ForTests_ContextWithPeerUID(context.Background(), tc.peerUID), req)
+			} else {
+				_, err = client.SetUserDisplayName(context.Background(), req)
+			}
+			if tc.wantErr {
+				require.Error(t, err, "SetUserDisplayName should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SetUserDisplayName should not return an error, but did")
+
+			u, err := m.UserByName(tc.username)
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			require.Equal(t, tc.wantGecos, u.Gecos, "SetUserDisplayName should update the GECOS of the user")
+		})
+	}
+}
+
+func TestSetUserAvatar(t *testing.T) {
+	t.Parallel()
+
+	picture := []byte("GIF89a user1 picture")
+
+	tests := map[string]struct {
+		username           string
+		content            []byte
+		currentUserNotRoot bool
+		// peerUID is the UID of the non-root user performing the request, if set.
+		peerUID uint32
+
+		wantErr bool
+	}{
+		"Set_avatar_of_user":                         {username: "user1", content: picture},
+		"Set_avatar_of_own_account_as_non_root_user": {username: "user1", content: picture, peerUID: 1111},
+
+		"Error_when_username_is_empty":                         {content: picture, wantErr: true},
+		"Error_when_content_is_not_a_picture":                  {username: "user1", content: []byte("not a picture"), wantErr: true},
+		"Error_when_user_never_logged_in":                      {username: "nonexistent", content: picture, wantErr: true},
+		"Error_when_not_root":                                  {username: "user1", content: picture, currentUserNotRoot: true, wantErr: true},
+		"Error_when_non_root_user_sets_avatar_of_another_user": {username: "user1", content: picture, peerUID: 2222, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "set-user-avatar.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			accountsDir := filepath.Join(t.TempDir(), "AccountsService")
+			require.NoError(t, os.Mkdir(accountsDir, 0700), "Setup: could not create AccountsService directory")
+			m, err := users.NewManager(usersConfig, dbDir, users.WithAccountsServiceDir(accountsDir))
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot || tc.peerUID != 0)
+			client, service := newPamClientAndService(t, m, globalBrokerManager, &pm)
+
+			req := &authd.SUARequest{Username: tc.username, Content: tc.content}
+			if tc.peerUID != 0 {
+				_, err = service.SetUserAvatar(permissions.Z_ForTests_ContextWithPeerUID(context.Background(), tc.peerUID), req)
+			} else {
+				_, err = client.SetUserAvatar(context.Background(), req)
+			}
+			if tc.wantErr {
+				require.Error(t, err, "SetUserAvatar should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SetUserAvatar should not return an error, but did")
+
+			content, _, err := m.Avatar(tc.username)
+			require.NoError(t, err, "Avatar should not return an error, but did")
+			require.Equal(t, tc.content, content, "SetUserAvatar should store the picture of the user")
+			icon, err := os.ReadFile(filepath.Join(accountsDir, "icons", tc.username))
+			require.NoError(t, err, "SetUserAvatar should install the AccountsService icon of the user")
+			require.Equal(t, tc.content, icon, "AccountsService icon should be the picture of the user")
+		})
+	}
+}
+
+func TestAuthenticateWithLocalPIN(t *testing.T) {
+	t.Parallel()
+
+	policy := pam.LocalPINPolicy{Services: []string{"sudo"}, MaxAttempts: 3}
+
+	tests := map[string]struct {
+		username       string
+		service        string
+		pin            string
+		failedAttempts int
+		noPIN          bool
+
+		wantGranted        bool
+		wantFailedAttempts uint32
+		wantUsable         bool
+		wantErr            bool
+	}{
+		"Granted_with_valid_PIN":                           {pin: "123456", wantGranted: true, wantUsable: true},
+		"Granted_with_valid_PIN_resets_failed_attempts":    {pin: "123456", failedAttempts: 2, wantGranted: true, wantUsable: true},
+		"Denied_with_invalid_PIN":                          {pin: "654321", wantFailedAttempts: 1, wantUsable: true},
+		"Denied_and_locked_after_too_many_attempts":        {pin: "654321", failedAttempts: 2, wantFailedAttempts: 3},
+		"Denied_with_valid_PIN_when_locked":                {pin: "123456", failedAttempts: 3, wantFailedAttempts: 3},
+		"Denied_with_invalid_PIN_does_not_count_if_locked": {pin: "654321", failedAttempts: 3, wantFailedAttempts: 3},
+
+		"Error_when_username_is_empty":            {username: "-", pin: "123456", wantErr: true},
+		"Error_when_service_is_empty":             {service: "-", pin: "123456", wantErr: true},
+		"Error_when_service_is_not_in_the_policy": {service: "login", pin: "123456", wantErr: true},
+		"Error_when_user_has_no_local_PIN":        {pin: "123456", noPIN: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "local-pin.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, false)
+			client := newPamClient(t, m, globalBrokerManager, &pm, pam.WithLocalPINPolicy(policy))
+
+			if !tc.noPIN {
+				err = m.SetLocalPIN("user1", "123456")
+				require.NoError(t, err, "Setup: could not set local PIN")
+			}
+			for range tc.failedAttempts {
+				_, err := client.AuthenticateWithLocalPIN(context.Background(), &authd.ALPRequest{Username: "user1", Service: "sudo", Pin: "000000"})
+				require.NoError(t, err, "Setup: could not fail to authenticate with local PIN")
+			}
+
+			switch tc.username {
+			case "":
+				tc.username = "user1"
+			case "-":
+				tc.username = ""
+			}
+			switch tc.service {
+			case "":
+				tc.service = "sudo"
+			case "-":
+				tc.service = ""
+			}
+
+			resp, err := client.AuthenticateWithLocalPIN(context.Background(), &authd.ALPRequest{
+				Username: tc.username,
+				Service:  tc.service,
+				Pin:      tc.pin,
+			})
+			if tc.wantErr {
+				require.Error(t, err, "AuthenticateWithLocalPIN should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "AuthenticateWithLocalPIN should not return an error, but did")
+			require.Equal(t, tc.wantGranted, resp.GetGranted(), "AuthenticateWithLocalPIN returned an unexpected result")
+			if !tc.wantGranted {
+				require.NotEmpty(t, resp.GetMsg(), "AuthenticateWithLocalPIN should explain why it was denied")
+			}
+
+			status, err := client.GetLocalPINStatus(context.Background(), &authd.GLPSRequest{Username: tc.username, Service: tc.service})
+			require.NoError(t, err, "GetLocalPINStatus should not return an error, but did")
+			require.Equal(t, tc.wantFailedAttempts, status.GetFailedAttempts(), "GetLocalPINStatus returned unexpected failed attempts")
+			require.Equal(t, tc.wantUsable, status.GetUsable(), "GetLocalPINStatus returned an unexpected usability")
+		})
+	}
+}
+
+func TestLastUsedAuthenticationMode(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		noPreviousAuthentication bool
+
+		wantAuthMode string
+	}{
+		"Return_the_mode_used_in_the_last_successful_authentication": {wantAuthMode: "mode1"},
+
+		"Return_no_mode_if_user_never_authenticated": {noPreviousAuthentication: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, nil, globalBrokerManager, &pm)
+
+			const username = "SAM_success_required_entry"
+			if !tc.noPreviousAuthentication {
+				authenticateWithMode(t, client, username, "mode1")
+			}
+
+			sessionID := startSession(t, client, username)
+			gamResp, err := client.GetAuthenticationModes(context.Background(), &authd.GAMRequest{
+				SessionId:          sessionID,
+				SupportedUiLayouts: []*authd.UILayout{requiredEntry},
+			})
+			require.NoError(t, err, "GetAuthenticationModes should not return an error, but did")
+			require.Equal(t, tc.wantAuthMode, gamResp.GetLastUsedAuthenticationModeId(),
+				"GetAuthenticationModes should return the expected last used authentication mode")
+		})
+	}
+}
+
+func TestIDGeneration(t *testing.T) {
+	t.Parallel()
+	usernamePrefix := t.Name()
+
+	tests := map[string]struct {
+		username string
+	}{
+		"Generate_ID": {username: "success"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			managerOpts := []users.Option{
+				users.WithIDGenerator(&idgenerator.IDGeneratorMock{
+					UIDsToGenerate: []uint32{1111},
+					GIDsToGenerate: []uint32{1111, 2222},
+				}),
+			}
+
+			m, err := users.NewManager(usersConfig, t.TempDir(), managerOpts...)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			sbResp, err := client.SelectBroker(context.Background(), &authd.SBRequest{
+				BrokerId: mockBrokerGeneratedID,
+				Username: usernamePrefix + testutils.IDSeparator + tc.username,
+				Mode:     authd.SessionMode_LOGIN,
+			})
+			require.NoError(t, err, "Setup: failed to create session for tests")
+
+			resp, err := client.IsAuthenticated(context.Background(), &authd.IARequest{SessionId: sbResp.GetSessionId()})
+			require.NoError(t, err, "Setup: could not authenticate user")
+			require.Equal(t, "granted", resp.GetAccess(), "Setup: authentication should be granted")
+
+			gotDB, err := db.Z_ForTests_DumpNormalizedYAML(userstestutils.GetManagerDB(m))
+			require.NoError(t, err, "Setup: failed to dump database for comparing")
+			golden.CheckOrUpdate(t, gotDB, golden.WithPath("cache.db"))
+		})
+	}
+}
+
+func TestSetDefaultBrokerForUser(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username           string
+		brokerID           string
+		currentUserNotRoot bool
+
+		wantErr bool
+	}{
+		"Set_default_broker_for_existing_user_with_no_broker":   {username: "usersetbroker"},
+		"Update_default_broker_for_existing_user_with_a_broker": {username: "userupdatebroker"},
+
+		"Error_when_setting_default_broker_to_local_broker": {username: "userlocalbroker", brokerID: brokers.LocalBrokerName, wantErr: true},
+		"Error_when_not_root":                               {username: "usersetbroker", currentUserNotRoot: true, wantErr: true},
+		"Error_when_username_is_empty":                      {wantErr: true},
+		"Error_when_user_does_not_exist_":                   {username: "doesnotexist", wantErr: true},
+		"Error_when_broker_does_not_exist":                  {username: "userwithbroker", brokerID: "does not exist", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "set-default-broker.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m, err := users.NewManager(usersConfig, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			if tc.brokerID == "" {
+				tc.brokerID = mockBrokerGeneratedID
+			}
+
+			sdbfuReq := &authd.SDBFURequest{
+				BrokerId: tc.brokerID,
+				Username: tc.username,
+			}
+			_, err = client.SetDefaultBrokerForUser(context.Background(), sdbfuReq)
+			if tc.wantErr {
+				require.Error(t, err, "SetDefaultBrokerForUser should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SetDefaultBrokerForUser should not return an error, but did")
+
+			gpbResp, err := client.GetPreviousBroker(context.Background(), &authd.GPBRequest{Username: tc.username})
+			require.NoError(t, err, "GetPreviousBroker should not return an error")
+			require.Equal(t, tc.brokerID, gpbResp.GetPreviousBroker(), "SetDefaultBrokerForUser should set the default broker as expected")
+
+			// Check that database has been updated too.
+			gotDB, err := db.Z_ForTests_DumpNormalizedYAML(userstestutils.GetManagerDB(m))
+			require.NoError(t, err, "Setup: failed to dump database for comparing")
+			golden.CheckOrUpdate(t, gotDB, golden.WithPath("cache.db"))
+		})
+	}
+}
+
+func TestResetBrokerForUser(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username              string
+		brokerSelectionExpiry time.Duration
+		noReset               bool
+		currentUserNotRoot    bool
+
+		wantErr bool
+	}{
+		"Reset_broker_of_user":               {username: "userresetbroker"},
+		"Broker_selection_expires_by_itself": {username: "userexpiredbroker", brokerSelectionExpiry: time.Nanosecond, noReset: true},
+
+		"Error_when_not_root":            {username: "userresetbroker", currentUserNotRoot: true, wantErr: true},
+		"Error_when_username_is_empty":   {wantErr: true},
+		"Error_when_user_does_not_exist": {username: "doesnotexist", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join(testutils.TestFamilyPath(t), "reset-broker.db"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			config := usersConfig
+			config.BrokerSelectionExpiry = tc.brokerSelectionExpiry
+			m, err := users.NewManager(config, dbDir)
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm)
+
+			if !tc.wantErr {
+				_, err = client.SetDefaultBrokerForUser(context.Background(), &authd.SDBFURequest{BrokerId: mockBrokerGeneratedID, Username: tc.username})
+				require.NoError(t, err, "Setup: SetDefaultBrokerForUser should not return an error")
+			}
+
+			if !tc.noReset {
+				_, err = client.ResetBrokerForUser(context.Background(), &authd.RBFURequest{Username: tc.username})
+				if tc.wantErr {
+					require.Error(t, err, "ResetBrokerForUser should return an error, but did not")
+					return
+				}
+				require.NoError(t, err, "ResetBrokerForUser should not return an error, but did")
+			}
+
+			gpbResp, err := client.GetPreviousBroker(context.Background(), &authd.GPBRequest{Username: tc.username})
+			require.NoError(t, err, "GetPreviousBroker should not return an error")
+			require.Empty(t, gpbResp.GetPreviousBroker(), "The user should select their broker again")
+		})
+	}
+}
+
+func TestEndSession(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		sessionID string
+
+		username           string
+		currentUserNotRoot bool
+
+		wantErr bool
+	}{
+		"Successfully_end_session": {username: "success"},
+
+		"Error_when_not_root":             {username: "success", currentUserNotRoot: true, wantErr: true},
+		"Error_when_sessionID_is_empty":   {sessionID: "-", wantErr: true},
+		"Error_when_sessionID_is_invalid": {sessionID: "invalid-session", wantErr: true},
+		"Error_when_ending_session":       {username: "ES_error", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, nil, globalBrokerManager, &pm)
+
+			switch tc.sessionID {
+			case "invalid-session":
+			case "-":
+				tc.sessionID = ""
+			default:
+				id := startSession(t, client, tc.username)
+				if tc.sessionID == "" {
+					tc.sessionID = id
+				}
+			}
+
+			// Now, set tests permissions for this use case
+			permissions.Z_ForTests_SetCurrentUserAsRoot(&pm, !tc.currentUserNotRoot)
+
+			esReq := &authd.ESRequest{
+				SessionId: tc.sessionID,
+			}
+			_, err := client.EndSession(context.Background(), esReq)
+			if tc.wantErr {
+				require.Error(t, err, "EndSession should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "EndSession should not return an error, but did")
+		})
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		usernames       []string
+		selectAuthMode  bool
+		endFirstSession bool
+
+		wantStage string
+	}{
+		"Successfully_list_no_sessions":                     {},
+		"Successfully_list_started_sessions":                {usernames: []string{"success", "success2"}, wantStage: "broker-selected"},
+		"Successfully_list_sessions_with_their_stage":       {usernames: []string{"SAM_success_required_entry"}, selectAuthMode: true, wantStage: "auth-mode-selected"},
+		"Successfully_list_sessions_without_ended_sessions": {usernames: []string{"success", "success2"}, endFirstSession: true, wantStage: "broker-selected"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, nil, globalBrokerManager, &pm)
+
+			start := time.Now().Truncate(time.Second)
+			var sessionIDs []string
+			for _, username := range tc.usernames {
+				sessionID := startSession(t, client, username)
+				if tc.selectAuthMode {
+					_, err := client.GetAuthenticationModes(context.Background(), &authd.GAMRequest{
+						SessionId:          sessionID,
+						SupportedUiLayouts: []*authd.UILayout{requiredEntry},
+					})
+					require.NoError(t, err, "Setup: failed to get authentication modes for tests")
+					_, err = client.SelectAuthenticationMode(context.Background(), &authd.SAMRequest{
+						SessionId:            sessionID,
+						AuthenticationModeId: "mode1",
+					})
+					require.NoError(t, err, "Setup: failed to select authentication mode for tests")
+				}
+				sessionIDs = append(sessionIDs, sessionID)
+			}
+			if tc.endFirstSession {
+				_, err := client.EndSession(context.Background(), &authd.ESRequest{SessionId: sessionIDs[0]})
+				require.NoError(t, err, "Setup: failed to end session for tests")
+				sessionIDs = sessionIDs[1:]
+			}
+
+			resp, err := client.ListSessions(context.Background(), &authd.Empty{})
+			require.NoError(t, err, "ListSessions should not return an error, but did")
+
+			var gotSessionIDs []string
+			for _, s := range resp.GetSessions() {
+				gotSessionIDs = append(gotSessionIDs, s.GetSessionId())
+				require.Equal(t, mockBrokerGeneratedID, s.GetBrokerId(), "ListSessions returned an unexpected broker")
+				require.Contains(t, s.GetUsername(), t.Name(), "ListSessions returned an unexpected user")
+				require.Equal(t, tc.wantStage, s.GetStage(), "ListSessions returned an unexpected stage")
+				require.GreaterOrEqual(t, s.GetStartTime(), start.Unix(), "ListSessions returned an unexpected start time")
+			}
+			require.Equal(t, sessionIDs, gotSessionIDs, "ListSessions returned unexpected sessions")
+		})
+	}
+}
+
+func TestAbortSession(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		sessionID string
+
+		username           string
+		currentUserNotRoot bool
+
+		wantErr bool
+	}{
+		"Successfully_abort_session": {username: "success"},
+
+		"Error_when_not_root":             {username: "success", currentUserNotRoot: true, wantErr: true},
+		"Error_when_sessionID_is_empty":   {sessionID: "-", wantErr: true},
+		"Error_when_sessionID_is_invalid": {sessionID: "invalid-session", wantErr: true},
+		"Error_when_ending_session":       {username: "ES_error", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, nil, globalBrokerManager, &pm)
+
+			switch tc.sessionID {
+			case "invalid-session":
+			case "-":
+				tc.sessionID = ""
+			default:
+				id := startSession(t, client, tc.username)
+				if tc.sessionID == "" {
+					tc.sessionID = id
+				}
+			}
+
+			// Now, set tests permissions for this use case
+			permissions.Z_ForTests_SetCurrentUserAsRoot(&pm, !tc.currentUserNotRoot)
+
+			_, err := client.AbortSession(context.Background(), &authd.ASRequest{SessionId: tc.sessionID})
+			if tc.wantErr {
+				require.Error(t, err, "AbortSession should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "AbortSession should not return an error, but did")
+
+			resp, err := client.ListSessions(context.Background(), &authd.Empty{})
+			require.NoError(t, err, "ListSessions should not return an error, but did")
+			require.Empty(t, resp.GetSessions(), "Aborted session should not be listed anymore")
+
+			_, err = client.EndSession(context.Background(), &authd.ESRequest{SessionId: tc.sessionID})
+			require.Error(t, err, "EndSession should fail on an aborted session")
+		})
+	}
+}
+
+func TestAuthenticateHeadless(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		brokerID           string
+		username           string
+		authModeID         string
+		secret             string
+		currentUserNotRoot bool
+		loginPolicy        pam.LoginPolicy
+
+		wantAccess string
+		wantErr    bool
+		// wantUserAdded is set if the user is added to the database even though the access is not granted.
+		wantUserAdded bool
+		wantErrCode   codes.Code
+	}{
+		"Successfully_authenticate":                     {username: "HA_success", wantAccess: auth.Granted},
+		"Successfully_authenticate_with_requested_mode": {username: "HA_success", authModeID: "mode1", wantAccess: auth.Granted},
+
+		"Error_when_not_root":                              {username: "HA_success", currentUserNotRoot: true, wantErr: true},
+		"Error_when_secret_is_empty":                       {username: "HA_success", secret: "-", wantErr: true},
+		"Error_when_broker_does_not_exist":                 {username: "HA_success", brokerID: "does not exist", wantErr: true},
+		"Error_when_broker_offers_no_authentication_mode":  {username: "GAM_empty", wantErr: true},
+		"Error_when_requested_mode_is_not_available":       {username: "HA_success", authModeID: "does not exist", wantErr: true},
+		"Error_when_authentication_mode_needs_a_user":      {username: "SAM_success_required_entry", wantErr: true},
+		"Error_when_broker_requires_additional_steps":      {username: "HA_next", wantErr: true},
+		"Error_when_broker_fails_to_authenticate_the_user": {username: "IA_error", wantErr: true},
+		"Error_when_login_policy_does_not_allow_the_user": {
+			username: "HA_success", loginPolicy: pam.LoginPolicy{AllowedUsers: []string{"another-user"}},
+			wantErr: true, wantUserAdded: true, wantErrCode: codes.PermissionDenied,
+		},
+		"Error_when_user_has_no_role_matching_a_machine_tag": {
+			username: "HA_success", loginPolicy: pam.LoginPolicy{MachineTags: []string{"servers"}},
+			wantErr: true, wantUserAdded: true, wantErrCode: codes.PermissionDenied,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := users.NewManager(usersConfig, t.TempDir())
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+			pm := newPermissionManager(t, tc.currentUserNotRoot)
+			client := newPamClient(t, m, globalBrokerManager, &pm, pam.WithLoginPolicy(tc.loginPolicy))
+
+			if tc.brokerID == "" {
+				tc.brokerID = mockBrokerGeneratedID
+			}
+			switch tc.secret {
+			case "":
+				tc.secret = "my secret"
+			case "-":
+				tc.secret = ""
+			}
+			// Prefixes the username to avoid concurrency issues.
+			username := t.Name() + testutils.IDSeparator + tc.username
+
+			resp, err := client.AuthenticateHeadless(context.Background(), &authd.AHRequest{
+				BrokerId:             tc.brokerID,
+				Username:             username,
+				AuthenticationModeId: tc.authModeID,
+				Secret:               tc.secret,
+			})
+			if tc.wantErr {
+				if err == nil {
+					require.NotEqual(t, auth.Granted, resp.GetAccess(), "AuthenticateHeadless should not grant access")
+				}
+				if tc.wantErrCode != codes.OK {
+					require.Equal(t, tc.wantErrCode, status.Code(err), "AuthenticateHeadless returned an unexpected error code")
+				}
+				_, err = m.UserByName(username)
+				if tc.wantUserAdded {
+					require.NoError(t, err, "User should have been added to the database")
+					return
+				}
+				require.Error(t, err, "User should not have been added to the database")
+				return
+			}
+			require.NoError(t, err, "AuthenticateHeadless should not return an error, but did")
+			require.Equal(t, tc.wantAccess, resp.GetAccess(), "AuthenticateHeadless returned an unexpected access")
+
+			_, err = m.UserByName(username)
+			require.NoError(t, err, "User should have been added to the database")
+
+			lsResp, err := client.ListSessions(context.Background(), &authd.Empty{})
+			require.NoError(t, err, "ListSessions should not return an error, but did")
+			require.Empty(t, lsResp.GetSessions(), "Headless session should have been ended")
+		})
+	}
+}
+
+func TestDumpAndImportDatabase(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content string
+
+		wantErr bool
+	}{
+		"Successfully_import_the_dumped_database": {},
+
+		"Error_on_invalid_JSON":  {content: "not json", wantErr: true},
+		"Error_on_unknown_field": {content: `{"users": [], "unknown": 1}`, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client := newPamClient(t, nil, globalBrokerManager, &pm)
+			authenticateWithMode(t, client, "SAM_success_required_entry", "mode1")
+
+			dump, err := client.DumpDatabase(context.Background(), &authd.Empty{})
+			require.NoError(t, err, "DumpDatabase should not return an error, but did")
+			require.Contains(t, string(dump.GetContent()), `"gecos": "gecos for SAM_success_required_entry"`,
+				"DumpDatabase should return the authenticated user")
+
+			content := dump.GetContent()
+			if tc.content != "" {
+				content = []byte(tc.content)
+			}
+
+			otherClient := newPamClient(t, nil, globalBrokerManager, &pm)
+			_, err = otherClient.ImportDatabase(context.Background(), &authd.DatabaseDump{Content: content})
+			if tc.wantErr {
+				require.Error(t, err, "ImportDatabase should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "ImportDatabase should not return an error, but did")
+
+			got, err := otherClient.DumpDatabase(context.Background(), &authd.Empty{})
+			require.NoError(t, err, "DumpDatabase should not return an error, but did")
+			require.Equal(t, string(dump.GetContent()), string(got.GetContent()),
+				"DumpDatabase should return the imported content")
+		})
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		authenticatingUser string
+		shutdownTimeout    time.Duration
+
+		wantAccess string
+	}{
+		"End_sessions_without_in-flight_authentications":         {shutdownTimeout: time.Second},
+		"Let_in-flight_authentications_complete_before_timeout":  {authenticatingUser: "IA_timeout", shutdownTimeout: 5 * time.Second, wantAccess: auth.Denied},
+		"Cancel_in-flight_authentications_not_completed_in_time": {authenticatingUser: "IA_wait", shutdownTimeout: 100 * time.Millisecond, wantAccess: auth.Cancelled},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := newPermissionManager(t, false) // Allow starting the session (current user considered root)
+			client, service := newPamClientAndService(t, nil, globalBrokerManager, &pm)
+
+			idleSessionID := startSession(t, client, "success")
+
+			authenticationDone := make(chan struct{})
+			if tc.authenticatingUser != "" {
+				sessionID := startSession(t, client, tc.authenticatingUser)
+				go func() {
+					defer close(authenticationDone)
+					iaResp, err := client.IsAuthenticated(context.Background(), &authd.IARequest{
+						SessionId:          sessionID,
+						AuthenticationData: &authd.IARequest_AuthenticationData{},
+					})
+					require.NoError(t, err, "In-flight IsAuthenticated should not return an error")
+					require.Equal(t, tc.wantAccess, iaResp.GetAccess(), "In-flight IsAuthenticated returned an unexpected access")
+				}()
+				// Give some time for the authentication to start.
+				time.Sleep(100 * time.Millisecond)
+			} else {
+				close(authenticationDone)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.shutdownTimeout)
+			defer cancel()
+			service.Shutdown(ctx)
+			<-authenticationDone
+
+			resp, err := client.ListSessions(context.Background(), &authd.Empty{})
+			require.NoError(t, err, "ListSessions should not return an error, but did")
+			require.Empty(t, resp.GetSessions(), "All sessions should have been ended")
+
+			_, err = client.EndSession(context.Background(), &authd.ESRequest{SessionId: idleSessionID})
+			require.Error(t, err, "EndSession should fail on a session ended on shutdown")
+
+			_, err = client.SelectBroker(context.Background(), &authd.SBRequest{
+				BrokerId: mockBrokerGeneratedID,
+				Username: t.Name() + testutils.IDSeparator + "success",
+				Mode:     authd.SessionMode_LOGIN,
+			})
+			require.Equal(t, codes.Unavailable, status.Code(err), "SelectBroker should fail with Unavailable after shutdown")
+		})
+	}
+}
+
+func TestMockgpasswd(t *testing.T) {
+	localgroupstestutils.Mockgpasswd(t)
+}
+
+// initBrokers starts dbus mock brokers on the system bus. It returns its config path.
+func initBrokers() (brokerConfigPath string, cleanup func(), err error) {
+	tmpDir, err := os.MkdirTemp("", "authd-internal-pam-tests-")
+	if err != nil {
+		return "", nil, err
+	}
+
+	brokersConfPath := filepath.Join(tmpDir, "etc", "authd", "broker.d")
+	if err = os.MkdirAll(brokersConfPath, 0750); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return "", nil, err
+	}
+	_, brokerCleanup, err := testutils.StartBusBrokerMock(brokersConfPath, "BrokerMock")
+	if err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return "", nil, err
+	}
+
+	return brokersConfPath, func() {
+		brokerCleanup()
+		_ = os.RemoveAll(tmpDir)
+	}, nil
+}
+
+// newPAMClient returns a new GRPC PAM client for tests connected to brokerManager with the given database and
+// permissionmanager.
+// If the one passed is nil, this function will create the database and close it upon test teardown.
+func newPamClient(t *testing.T, m *users.Manager, brokerManager *brokers.Manager, pm *permissions.Manager, opts ...pam.Option) (client authd.PAMClient) {
+	t.Helper()
+
+	client, _ = newPamClientAndService(t, m, brokerManager, pm, opts...)
+	return client
+}
+
+// newPamClientAndService returns a client connected to a new PAM service, and the service itself.
+func newPamClientAndService(t *testing.T, m *users.Manager, brokerManager *brokers.Manager, pm *permissions.Manager, opts ...pam.Option) (client authd.PAMClient, service pam.Service) {
+	t.Helper()
+
+	socketPath, service := startPamService(t, m, brokerManager, pm, opts...)
+	return newPamClientForSocket(t, socketPath, compat.UnaryClientInterceptor), service
+}
+
+// startPamService starts a new PAM service and returns the socket it listens on, and the service itself.
+func startPamService(t *testing.T, m *users.Manager, brokerManager *brokers.Manager, pm *permissions.Manager, opts ...pam.Option) (socketPath string, service pam.Service) {
+	t.Helper()
+
+	// socket path is limited in length.
+	tmpDir, err := os.MkdirTemp("", "authd-socket-dir")
+	require.NoError(t, err, "Setup: could not setup temporary socket dir path")
+	t.Cleanup(func() { _ = os.RemoveAll(tmpDir) })
+	socketPath = filepath.Join(tmpDir, "authd.sock")
+
+	lis, err := net.Listen("unix", socketPath)
+	require.NoError(t, err, "Setup: could not create unix socket")
+
+	if m == nil {
+		m, err = users.NewManager(usersConfig, t.TempDir())
+		require.NoError(t, err, "Setup: could not create user manager")
+		t.Cleanup(func() { _ = m.Stop() })
+	}
+
+	service = pam.NewService(context.Background(), m, brokerManager, pm, opts...)
+
+	grpcServer := grpc.NewServer(permissions.WithUnixPeerCreds(), grpc.ChainUnaryInterceptor(enableCheckGlobalAccess(service), errmessages.RedactErrorInterceptor))
+	authd.RegisterPAMServer(grpcServer, service)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(func() {
+		grpcServer.Stop()
+		<-done
+	})
+
+	return socketPath, service
+}
+
+// newPamClientForSocket returns a client connected to the PAM service listening on socketPath, with the given
+// interceptors in addition to the ones of the PAM module.
+func newPamClientForSocket(t *testing.T, socketPath string, interceptors ...grpc.UnaryClientInterceptor) authd.PAMClient {
+	t.Helper()
+
+	interceptors = append([]grpc.UnaryClientInterceptor{errmessages.FormatErrorMessage}, interceptors...)
+	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithChainUnaryInterceptor(interceptors...))
+	require.NoError(t, err, "Setup: Could not connect to gRPC server")
+
+	t.Cleanup(func() { _ = conn.Close() }) // We don't care about the error on cleanup
+
+	return authd.NewPAMClient(conn)
+}
+
+// newPermissionManager factors out permission manager creation for tests.
 // this permission manager can then be tweaked for mimicking currentUser considered as root not.
 func newPermissionManager(t *testing.T, currentUserNotRoot bool) permissions.Manager {
 	t.Helper()
@@ -821,6 +2971,80 @@ func startSession(t *testing.T, client authd.PAMClient, username string) string
 	return sbResp.GetSessionId()
 }
 
+// authenticateWithMode is a helper that successfully authenticates the user on the mock broker with the given
+// authentication mode.
+func authenticateWithMode(t *testing.T, client authd.PAMClient, username, authMode string) {
+	t.Helper()
+
+	sessionID := startSession(t, client, username)
+	_, err := client.GetAuthenticationModes(context.Background(), &authd.GAMRequest{
+		SessionId:          sessionID,
+		SupportedUiLayouts: []*authd.UILayout{requiredEntry},
+	})
+	require.NoError(t, err, "Setup: failed to get authentication modes for tests")
+	_, err = client.SelectAuthenticationMode(context.Background(), &authd.SAMRequest{
+		SessionId:            sessionID,
+		AuthenticationModeId: authMode,
+	})
+	require.NoError(t, err, "Setup: failed to select authentication mode for tests")
+	iaResp, err := client.IsAuthenticated(context.Background(), &authd.IARequest{
+		SessionId:          sessionID,
+		AuthenticationData: &authd.IARequest_AuthenticationData{},
+	})
+	require.NoError(t, err, "Setup: failed to authenticate user for tests")
+	require.Equal(t, auth.Granted, iaResp.GetAccess(), "Setup: user should have been authenticated")
+	_, err = client.EndSession(context.Background(), &authd.ESRequest{SessionId: sessionID})
+	require.NoError(t, err, "Setup: failed to end session for tests")
+}
+
+// isAuthenticatedWithMode is a helper that selects the authentication mode of the session on the mock broker and
+// authenticates the user with it.
+func isAuthenticatedWithMode(t *testing.T, client authd.PAMClient, sessionID, authMode string) *authd.IAResponse {
+	t.Helper()
+
+	_, err := client.GetAuthenticationModes(context.Background(), &authd.GAMRequest{
+		SessionId:          sessionID,
+		SupportedUiLayouts: []*authd.UILayout{requiredEntry},
+	})
+	require.NoError(t, err, "Setup: failed to get authentication modes for tests")
+	_, err = client.SelectAuthenticationMode(context.Background(), &authd.SAMRequest{
+		SessionId:            sessionID,
+		AuthenticationModeId: authMode,
+	})
+	require.NoError(t, err, "Setup: failed to select authentication mode for tests")
+	iaResp, err := client.IsAuthenticated(context.Background(), &authd.IARequest{
+		SessionId:          sessionID,
+		AuthenticationData: &authd.IARequest_AuthenticationData{},
+	})
+	require.NoError(t, err, "Setup: failed to authenticate user for tests")
+	return iaResp
+}
+
+// answerConsent is a helper that answers the consent message pending in the session.
+func answerConsent(t *testing.T, client authd.PAMClient, sessionID string, consentLayout *authd.UILayout, answer string) {
+	t.Helper()
+
+	_, err := client.GetAuthenticationModes(context.Background(), &authd.GAMRequest{
+		SessionId:          sessionID,
+		SupportedUiLayouts: []*authd.UILayout{consentLayout},
+	})
+	require.NoError(t, err, "Setup: failed to get consent authentication mode for tests")
+	_, err = client.SelectAuthenticationMode(context.Background(), &authd.SAMRequest{
+		SessionId:            sessionID,
+		AuthenticationModeId: "consent",
+	})
+	require.NoError(t, err, "Setup: failed to select consent authentication mode for tests")
+	_, err = client.IsAuthenticated(context.Background(), &authd.IARequest{
+		SessionId: sessionID,
+		AuthenticationData: &authd.IARequest_AuthenticationData{
+			Item: &authd.IARequest_AuthenticationData_Consent{Consent: answer},
+		},
+	})
+	require.NoError(t, err, "Setup: failed to answer consent for tests")
+	_, err = client.EndSession(context.Background(), &authd.ESRequest{SessionId: sessionID})
+	require.NoError(t, err, "Setup: failed to end session for tests")
+}
+
 // setupGlobalBrokerMock creates and points to a test-wide system bus, registering the mock broker on it.
 func setupGlobalBrokerMock() (cleanup func(), err error) {
 	cleanup = func() {}
@@ -873,3 +3097,7 @@ func TestMain(m *testing.M) {
 
 	m.Run()
 }
+
+func ptrValue[T any](value T) *T {
+	return &value
+}
@@ -1,8 +1,24 @@
 package pam
 
-import "context"
+import (
+	"context"
 
-// CheckGlobalAccess denies all requests not coming from the root user.
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// CheckGlobalAccess denies all requests not coming from the root user, except the ones which are filtered
+// individually.
 func (s Service) CheckGlobalAccess(ctx context.Context, method string) error {
+	switch method {
+	case authd.PAM_SetLocalPIN_FullMethodName,
+		authd.PAM_GetUserAccountInfo_FullMethodName,
+		authd.PAM_SetUserDisplayName_FullMethodName,
+		authd.PAM_SetUserAvatar_FullMethodName:
+		// The users can manage their own account, which is checked by each method.
+		return nil
+	case authd.PAM_GetVersion_FullMethodName:
+		// The version is public, so that any user can check that authctl matches the daemon.
+		return nil
+	}
 	return s.permissionManager.IsRequestFromRoot(ctx)
 }
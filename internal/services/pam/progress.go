@@ -0,0 +1,37 @@
+package pam
+
+import (
+	"context"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/decorate"
+)
+
+// GetAuthenticationProgress returns the last progress reported by the broker while it authenticates the user of the
+// session, so that the PAM module can show it during long authentications.
+func (s Service) GetAuthenticationProgress(ctx context.Context, req *authd.GAPRequest) (resp *authd.GAPResponse, err error) {
+	defer decorate.OnError(&err, "can't get authentication progress")
+
+	sessionID := req.GetSessionId()
+	if sessionID == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no session ID provided")
+	}
+
+	broker, err := s.brokerManager.BrokerFromSessionID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := broker.AuthProgress(sessionID)
+	if !ok {
+		return &authd.GAPResponse{}, nil
+	}
+
+	resp = &authd.GAPResponse{Msg: p.Message, Cancellable: p.Cancellable}
+	if p.Percentage >= 0 {
+		percentage := uint32(min(p.Percentage, 100))
+		resp.Percentage = &percentage
+	}
+	return resp, nil
+}
@@ -0,0 +1,112 @@
+package pam
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ubuntu/authd/internal/clock"
+	"github.com/ubuntu/authd/log"
+)
+
+// RecentAuthenticationPolicy defines which PAM services can skip prompting the user when they recently
+// authenticated with a strong (phishing resistant) authentication mode.
+type RecentAuthenticationPolicy struct {
+	// Services is the list of PAM services (for example "sudo") allowed to rely on a recent authentication.
+	Services []string `mapstructure:"services"`
+	// AuthModes is the list of authentication mode IDs considered strong enough.
+	AuthModes []string `mapstructure:"auth_modes"`
+	// MaxAge is how long an authentication is considered recent.
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// enabled returns whether the policy allows any service to rely on a recent authentication.
+func (p RecentAuthenticationPolicy) enabled() bool {
+	return len(p.Services) > 0 && len(p.AuthModes) > 0 && p.MaxAge > 0
+}
+
+type strongAuthentication struct {
+	authMode string
+	time     time.Time
+}
+
+// strongAuthenticationScope is what a strong authentication allows to skip the prompts for: the same user on the same
+// terminal, like the tickets of sudo.
+type strongAuthenticationScope struct {
+	username string
+	tty      string
+}
+
+// recentAuthentications tracks the strong authentications performed by each user on each terminal.
+type recentAuthentications struct {
+	policy RecentAuthenticationPolicy
+	clock  clock.Clock
+
+	lastStrongAuth map[strongAuthenticationScope]strongAuthentication
+	mu             sync.Mutex
+}
+
+func newRecentAuthentications(policy RecentAuthenticationPolicy, c clock.Clock) *recentAuthentications {
+	return &recentAuthentications{
+		policy:         policy,
+		clock:          c,
+		lastStrongAuth: make(map[strongAuthenticationScope]strongAuthentication),
+	}
+}
+
+// isTerminal returns whether the PAM TTY identifies a terminal device. The PAM services which aren't attached to one
+// set a name shared by all their transactions instead, like "ssh" or the X display.
+func isTerminal(tty string) bool {
+	return strings.HasPrefix(tty, "/dev/")
+}
+
+// granted records a successful authentication of the user for the session on the terminal, if performed with a strong
+// authentication mode.
+func (r *recentAuthentications) granted(ctx context.Context, sessionID, username, tty, authMode string) {
+	if !r.policy.enabled() || !slices.Contains(r.policy.AuthModes, authMode) {
+		return
+	}
+	if !isTerminal(tty) {
+		log.Debugf(ctx, "%s: Not recording strong authentication of user %q: no terminal to scope it to (%q)", sessionID, username, tty)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log.Debugf(ctx, "%s: Recording strong authentication of user %q on %q with mode %q", sessionID, username, tty, authMode)
+	r.lastStrongAuth[strongAuthenticationScope{username: username, tty: tty}] = strongAuthentication{authMode: authMode, time: r.clock.Now()}
+}
+
+// check returns whether the user can be authenticated to the service on the terminal without being prompted again.
+// Each decision is logged as an audit record.
+func (r *recentAuthentications) check(ctx context.Context, username, tty, service string) bool {
+	if !r.policy.enabled() || !slices.Contains(r.policy.Services, service) {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scope := strongAuthenticationScope{username: username, tty: tty}
+	auth, ok := r.lastStrongAuth[scope]
+	if !ok || !isTerminal(tty) {
+		log.Noticef(ctx, "Audit: denied authentication of user %q to service %q on %q without prompt: no recent strong authentication on this terminal",
+			username, service, tty)
+		return false
+	}
+
+	age := r.clock.Now().Sub(auth.time)
+	if age > r.policy.MaxAge {
+		log.Noticef(ctx, "Audit: denied authentication of user %q to service %q on %q without prompt: last strong authentication with mode %q is too old (%s)",
+			username, service, tty, auth.authMode, age.Round(time.Second))
+		delete(r.lastStrongAuth, scope)
+		return false
+	}
+
+	log.Noticef(ctx, "Audit: granted authentication of user %q to service %q on %q without prompt: strong authentication with mode %q %s ago",
+		username, service, tty, auth.authMode, age.Round(time.Second))
+	return true
+}
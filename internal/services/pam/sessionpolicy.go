@@ -0,0 +1,67 @@
+package pam
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// SessionPolicy defines the actions performed by the PAM module when a session of a user handled by authd is opened.
+type SessionPolicy struct {
+	// RuntimeDir makes sure that the XDG runtime directory of the user exists and is owned by them, as its UID may
+	// have been used by another user before.
+	RuntimeDir bool `mapstructure:"runtime_dir"`
+	// LingerGroups is the list of group names whose members have systemd lingering enabled, so that their user
+	// services keep running once they log out.
+	LingerGroups []string `mapstructure:"linger_groups"`
+}
+
+// GetSessionActions returns the actions that the PAM module must perform when opening a session of the user.
+func (s Service) GetSessionActions(ctx context.Context, req *authd.GSARequest) (resp *authd.GSAResponse, err error) {
+	defer decorate.OnError(&err, "can't get session actions")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+
+	u, err := s.userManager.UserByName(req.GetUsername())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q is not handled by authd", req.GetUsername())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &authd.GSAResponse{
+		Uid:              u.UID,
+		Gid:              u.GID,
+		EnsureRuntimeDir: s.sessionPolicy.RuntimeDir,
+		EnableLinger:     s.lingerEnabled(ctx, u.Name),
+	}
+	log.Debugf(ctx, "Session actions of user %q: %v", u.Name, resp)
+	return resp, nil
+}
+
+// lingerEnabled returns whether the user is a member of one of the linger groups of the policy.
+func (s Service) lingerEnabled(ctx context.Context, username string) bool {
+	for _, g := range s.sessionPolicy.LingerGroups {
+		group, err := s.userManager.GroupByName(g)
+		if errors.Is(err, users.NoDataFoundError{}) {
+			continue
+		}
+		if err != nil {
+			log.Warningf(ctx, "Could not get group %q: %v", g, err)
+			continue
+		}
+		if slices.Contains(group.Users, username) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package pam
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Stages of an ongoing session.
+const (
+	// stageBrokerSelected is the stage of a session whose broker has been selected.
+	stageBrokerSelected = "broker-selected"
+	// stageAuthModeSelected is the stage of a session whose authentication mode has been selected.
+	stageAuthModeSelected = "auth-mode-selected"
+	// stageAuthenticating is the stage of a session waiting for the broker to authenticate the user.
+	stageAuthenticating = "authenticating"
+)
+
+// sessionInfo is the information the service keeps about an ongoing session.
+type sessionInfo struct {
+	id        string
+	username  string
+	brokerID  string
+	service   string
+	tty       string
+	mode      string
+	authMode  string
+	stage     string
+	startTime time.Time
+	// consent is set once the broker granted the access, if the user must still accept the consent message.
+	consent *pendingConsent
+}
+
+// sessions tracks the ongoing sessions.
+type sessions struct {
+	infos map[string]sessionInfo
+	mu    sync.Mutex
+}
+
+func newSessions() *sessions {
+	return &sessions{infos: make(map[string]sessionInfo)}
+}
+
+// add starts tracking the session of the user with the given broker, PAM service, terminal and session mode.
+func (s *sessions) add(sessionID, username, brokerID, service, tty, mode string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.infos[sessionID] = sessionInfo{
+		id:        sessionID,
+		username:  username,
+		brokerID:  brokerID,
+		service:   service,
+		tty:       tty,
+		mode:      mode,
+		stage:     stageBrokerSelected,
+		startTime: time.Now(),
+	}
+}
+
+// setAuthMode memorizes the authentication mode selected for the session.
+func (s *sessions) setAuthMode(sessionID, authMode string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, ok := s.infos[sessionID]
+	if !ok {
+		return
+	}
+	info.authMode = authMode
+	info.stage = stageAuthModeSelected
+	s.infos[sessionID] = info
+}
+
+// setStage updates the stage of the session.
+func (s *sessions) setStage(sessionID, stage string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, ok := s.infos[sessionID]
+	if !ok {
+		return
+	}
+	info.stage = stage
+	s.infos[sessionID] = info
+}
+
+// setConsent memorizes the consent message the user must accept before being granted the access, or forgets it if
+// consent is nil.
+func (s *sessions) setConsent(sessionID string, consent *pendingConsent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, ok := s.infos[sessionID]
+	if !ok {
+		return
+	}
+	info.consent = consent
+	s.infos[sessionID] = info
+}
+
+// get returns the information about the session, if it's tracked.
+func (s *sessions) get(sessionID string) (sessionInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, ok := s.infos[sessionID]
+	return info, ok
+}
+
+// list returns the information about all the tracked sessions, sorted by start time.
+func (s *sessions) list() []sessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.SortedFunc(maps.Values(s.infos), func(a, b sessionInfo) int {
+		return a.startTime.Compare(b.startTime)
+	})
+}
+
+// remove stops tracking the session.
+func (s *sessions) remove(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.infos, sessionID)
+}
@@ -0,0 +1,39 @@
+package pam
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// SetUserShell sets the login shell of a user who already logged in with their broker. It takes precedence over the
+// one provided by the broker.
+func (s Service) SetUserShell(ctx context.Context, req *authd.SUSRequest) (empty *authd.Empty, err error) {
+	defer decorate.OnError(&err, "can't set login shell of user")
+
+	if req.GetUsername() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no user name given")
+	}
+	if req.GetShell() == "" {
+		return nil, authderrors.New(authderrors.InvalidArgument, "no login shell given")
+	}
+
+	err = s.userManager.SetShell(req.GetUsername(), req.GetShell())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "user %q never logged in with authd", req.GetUsername())
+	}
+	if errors.Is(err, users.ErrInvalidOverride) {
+		return nil, authderrors.Wrap(authderrors.InvalidArgument, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "Login shell of user %q set to %q", req.GetUsername(), req.GetShell())
+	return &authd.Empty{}, nil
+}
@@ -0,0 +1,89 @@
+package pam
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/log"
+)
+
+// shutdown tracks the in-flight authentications, so that they can complete before the service stops.
+type shutdown struct {
+	mu       sync.Mutex
+	stopping bool
+	inFlight sync.WaitGroup
+}
+
+// errShuttingDown is returned to the requests that can't be handled anymore as the service is stopping.
+var errShuttingDown = authderrors.New(authderrors.Unavailable, "authd is shutting down")
+
+// check returns an error if the service is stopping.
+func (s *shutdown) check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopping {
+		return errShuttingDown
+	}
+	return nil
+}
+
+// startAuthentication tracks a new in-flight authentication and returns the function to call once it's done.
+func (s *shutdown) startAuthentication() (done func(), err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopping {
+		return nil, errShuttingDown
+	}
+	s.inFlight.Add(1)
+	return s.inFlight.Done, nil
+}
+
+// stop prevents any new authentication to start and waits for the in-flight ones to complete, or for the context
+// to be done. It returns whether all the in-flight authentications completed.
+func (s *shutdown) stop(ctx context.Context) (drained bool) {
+	s.mu.Lock()
+	s.stopping = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// wait waits for all the in-flight authentications to complete.
+func (s *shutdown) wait() {
+	s.inFlight.Wait()
+}
+
+// Shutdown stops accepting new sessions and authentications, lets the in-flight authentications complete until the
+// context is done and then ends all the remaining broker sessions, cancelling any pending authentication.
+func (s Service) Shutdown(ctx context.Context) {
+	log.Info(ctx, "Waiting for in-flight authentications to complete")
+	if !s.shutdown.stop(ctx) {
+		log.Warning(ctx, "In-flight authentications did not complete in time, cancelling them")
+	}
+
+	// The context may be done already, but we still want the brokers to clean up the sessions.
+	ctx = context.WithoutCancel(ctx)
+	for _, info := range s.sessions.list() {
+		log.Debugf(ctx, "%s: Ending session of user %q", info.id, info.username)
+		if err := s.brokerManager.AbortSession(ctx, info.id); err != nil {
+			log.Warningf(ctx, "%s: Could not end session: %v", info.id, err)
+		}
+		s.sessions.remove(info.id)
+	}
+
+	// Cancelled authentications return as soon as the broker acknowledges the cancellation.
+	s.shutdown.wait()
+	log.Debug(ctx, "All sessions have been ended")
+}
@@ -0,0 +1,43 @@
+package pam
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/authd/log"
+)
+
+// StepUpPolicy defines which PAM services re-authenticate the users who already logged in with a step-up session,
+// for which the brokers supporting it can offer lighter authentication modes (for example, a TOTP or a local PIN)
+// than the ones of a login.
+type StepUpPolicy struct {
+	// Services is the list of PAM services (for example "sudo") using step-up sessions.
+	Services []string `mapstructure:"services"`
+}
+
+// stepUpRequested returns whether the session of the user for the service must be a step-up one. That's only the
+// case if the user identity is cached, from a previous login with the same broker.
+func (s Service) stepUpRequested(ctx context.Context, username, brokerID, service string) bool {
+	if service == "" || !slices.Contains(s.stepUpPolicy.Services, service) {
+		return false
+	}
+
+	userBrokerID, err := s.userManager.BrokerForUser(username)
+	if errors.Is(err, users.NoDataFoundError{}) {
+		log.Debugf(ctx, "User %q never logged in, not using a step-up session for service %q", username, service)
+		return false
+	}
+	if err != nil {
+		log.Warningf(ctx, "Could not get the broker of user %q: %v", username, err)
+		return false
+	}
+	if userBrokerID != brokerID {
+		log.Debugf(ctx, "User %q did not log in with broker %q, not using a step-up session for service %q",
+			username, brokerID, service)
+		return false
+	}
+
+	return true
+}
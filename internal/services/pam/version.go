@@ -0,0 +1,22 @@
+package pam
+
+import (
+	"context"
+
+	"github.com/ubuntu/authd/internal/buildinfo"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+// GetVersion returns the version of the daemon, the versions of the protocols it speaks and its build metadata, so
+// that the clients can detect when they don't match the daemon.
+func (s Service) GetVersion(ctx context.Context, _ *authd.Empty) (*authd.GVResponse, error) {
+	info := buildinfo.Get()
+	return &authd.GVResponse{
+		Version:               info.Version,
+		GdmProtocolVersion:    info.GDMProtocolVersion,
+		BrokerProtocolVersion: info.BrokerProtocolVersion,
+		GoVersion:             info.GoVersion,
+		Revision:              info.Revision,
+		Modified:              info.Modified,
+	}, nil
+}
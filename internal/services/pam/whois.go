@@ -0,0 +1,55 @@
+package pam
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/users"
+	"github.com/ubuntu/decorate"
+)
+
+// WhoisUID returns the user a UID belongs to, or belonged to before being deleted, so that the administrators can
+// attribute the files still owned by the UIDs of the deleted users.
+func (s Service) WhoisUID(ctx context.Context, req *authd.WUIDRequest) (resp *authd.WUIDResponse, err error) {
+	defer decorate.OnError(&err, "can't find owner of UID")
+
+	o, err := s.userManager.UIDOwner(req.GetUid())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "UID %d never belonged to a user of authd", req.GetUid())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &authd.WUIDResponse{Username: o.Name}
+	if !o.DeletedAt.IsZero() {
+		resp.DeletedAt = o.DeletedAt.Unix()
+	}
+	return resp, nil
+}
+
+// WhoisID returns all the users and groups an ID belongs to, whether they are users and groups of authd, temporary
+// ones or local ones, so that the administrators can debug the collisions of IDs.
+func (s Service) WhoisID(ctx context.Context, req *authd.WIDRequest) (resp *authd.WIDResponse, err error) {
+	defer decorate.OnError(&err, "can't find owners of ID")
+
+	owners, err := s.userManager.OwnerOfID(req.GetId())
+	if errors.Is(err, users.NoDataFoundError{}) {
+		return nil, authderrors.Errorf(authderrors.NotFound, "ID %d doesn't belong to any user or group", req.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &authd.WIDResponse{}
+	for _, o := range owners {
+		owner := &authd.WIDResponse_Owner{Name: o.Name, Source: o.Source}
+		if !o.DeletedAt.IsZero() {
+			owner.DeletedAt = o.DeletedAt.Unix()
+		}
+		resp.Owners = append(resp.Owners, owner)
+	}
+	return resp, nil
+}
@@ -46,18 +46,27 @@ func New(args ...Option) Manager {
 func (m Manager) IsRequestFromRoot(ctx context.Context) (err error) {
 	defer decorate.OnError(&err, "permission denied")
 
+	uid, err := PeerUID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if uid != m.rootUID {
+		return fmt.Errorf(permErrorFmt, uid)
+	}
+
+	return nil
+}
+
+// PeerUID returns the UID of the user who performed the request, extracted from peerCredsInfo in the gRPC context.
+func PeerUID(ctx context.Context) (uint32, error) {
 	p, ok := peer.FromContext(ctx)
 	if !ok {
-		return errors.New("context request doesn't have gRPC peer information")
+		return 0, errors.New("context request doesn't have gRPC peer information")
 	}
 	pci, ok := p.AuthInfo.(peerCredsInfo)
 	if !ok {
-		return errors.New("context request doesn't have valid gRPC peer credential information")
-	}
-
-	if pci.uid != m.rootUID {
-		return fmt.Errorf(permErrorFmt, pci.uid)
+		return 0, errors.New("context request doesn't have valid gRPC peer credential information")
 	}
-
-	return nil
+	return pci.uid, nil
 }
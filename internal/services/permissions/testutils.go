@@ -4,6 +4,7 @@ package permissions
 // They are not exported, and guarded by testing assertions.
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"os/user"
@@ -11,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/ubuntu/authd/internal/testsdetection"
+	"google.golang.org/grpc/peer"
 )
 
 // Z_ForTests_WithCurrentUserAsRoot returns an Option that sets the rootUID to the current user's UID.
@@ -72,3 +74,12 @@ func Z_ForTests_DefaultCurrentUserAsRoot() {
 
 	defaultOptions.rootUID = currentUserUID()
 }
+
+// Z_ForTests_ContextWithPeerUID returns a context of a request performed by the user with the given UID.
+//
+// nolint:revive,nolintlint // We want to use underscores in the function name here.
+func Z_ForTests_ContextWithPeerUID(ctx context.Context, uid uint32) context.Context {
+	testsdetection.MustBeTesting()
+
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: peerCredsInfo{uid: uid}})
+}
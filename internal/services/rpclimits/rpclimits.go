@@ -0,0 +1,274 @@
+// Package rpclimits limits the gRPC requests the daemon handles, so that a runaway client can't starve the others,
+// and in particular the PAM authentications.
+package rpclimits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/clock"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/permissions"
+	"github.com/ubuntu/authd/log"
+	"google.golang.org/grpc"
+)
+
+const (
+	// defaultSlotWaitTimeout is how long a request waits for a free slot before being refused.
+	defaultSlotWaitTimeout = 5 * time.Second
+	// throttledLogInterval is the minimum interval between two logs about the requests refused to the same user.
+	throttledLogInterval = 10 * time.Second
+	// maxIdleBuckets is the number of rate limiting buckets kept before the ones of the idle users are removed.
+	maxIdleBuckets = 1024
+)
+
+// authenticationMethods are the methods the PAM module calls to authenticate the users. They are never limited, so
+// that the other requests can't prevent the users from logging in. The number of concurrent authentications is
+// limited by the PAM service itself.
+var authenticationMethods = map[string]bool{
+	authd.PAM_AvailableBrokers_FullMethodName:         true,
+	authd.PAM_GetPreviousBroker_FullMethodName:        true,
+	authd.PAM_SelectBroker_FullMethodName:             true,
+	authd.PAM_GetAuthenticationModes_FullMethodName:   true,
+	authd.PAM_SelectAuthenticationMode_FullMethodName: true,
+	authd.PAM_IsAuthenticated_FullMethodName:          true,
+	authd.PAM_EndSession_FullMethodName:               true,
+	authd.PAM_SetDefaultBrokerForUser_FullMethodName:  true,
+	authd.PAM_IsRecentlyAuthenticated_FullMethodName:  true,
+	authd.PAM_GetSessionActions_FullMethodName:        true,
+	authd.PAM_CheckLoginPolicy_FullMethodName:         true,
+	authd.PAM_GetLocalPINStatus_FullMethodName:        true,
+	authd.PAM_AuthenticateWithLocalPIN_FullMethodName: true,
+}
+
+// Config limits the requests handled by the daemon. Zero values mean no limit.
+type Config struct {
+	// MaxConcurrent is the maximum number of requests handled at the same time, apart from the authentication ones.
+	// Further requests wait for a slot to be available.
+	MaxConcurrent int `mapstructure:"max_concurrent"`
+	// RatePerUser is the number of requests per second each non-root user can make, apart from the authentication
+	// ones, after an initial burst. The requests above this rate are refused.
+	RatePerUser float64 `mapstructure:"rate_per_user"`
+	// Burst is the number of requests a user can make at once before being limited to RatePerUser. It defaults to
+	// the rate, rounded up.
+	Burst int `mapstructure:"burst"`
+}
+
+// Limiter limits the gRPC requests with a unary interceptor.
+type Limiter struct {
+	config          Config
+	slots           chan struct{}
+	slotWaitTimeout time.Duration
+	clock           clock.Clock
+
+	buckets   map[uint32]*bucket
+	bucketsMu sync.Mutex
+
+	authentication, other classMetrics
+}
+
+// bucket is the token bucket limiting the rate of the requests of a user.
+type bucket struct {
+	tokens     float64
+	last       time.Time
+	lastLogged time.Time
+}
+
+// classMetrics are the counters of a class of requests.
+type classMetrics struct {
+	inFlight  atomic.Int64
+	handled   atomic.Uint64
+	throttled atomic.Uint64
+	timedOut  atomic.Uint64
+}
+
+// Metrics are the counters of a class of requests handled by the daemon since it started.
+type Metrics struct {
+	// Class is "authentication" for the requests authenticating the users, and "other" for the others.
+	Class string
+	// InFlight is the number of requests being handled.
+	InFlight int64
+	// Handled is the number of requests which were handled.
+	Handled uint64
+	// Throttled is the number of requests refused because their user exceeded their rate.
+	Throttled uint64
+	// TimedOut is the number of requests refused because no slot got available in time.
+	TimedOut uint64
+}
+
+type options struct {
+	slotWaitTimeout time.Duration
+	clock           clock.Clock
+}
+
+// Option is a function that allows changing some of the default behaviors of the limiter.
+type Option func(*options)
+
+// WithSlotWaitTimeout sets how long a request waits for a free slot before being refused.
+// This option is only useful in tests.
+func WithSlotWaitTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.slotWaitTimeout = d
+	}
+}
+
+// WithClock makes the limiter use a specific clock to refill the rate limiting buckets.
+// This option is only useful in tests.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// New returns a limiter applying the configuration.
+func New(config Config, args ...Option) (*Limiter, error) {
+	if config.MaxConcurrent < 0 || config.RatePerUser < 0 || config.Burst < 0 {
+		return nil, errors.New("the RPC limits must not be negative")
+	}
+	if config.RatePerUser > 0 && config.Burst == 0 {
+		config.Burst = int(math.Ceil(config.RatePerUser))
+	}
+
+	opts := options{slotWaitTimeout: defaultSlotWaitTimeout, clock: clock.Default()}
+	for _, arg := range args {
+		arg(&opts)
+	}
+
+	l := &Limiter{
+		config:          config,
+		slotWaitTimeout: opts.slotWaitTimeout,
+		clock:           opts.clock,
+		buckets:         make(map[uint32]*bucket),
+	}
+	if config.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+	return l, nil
+}
+
+// UnaryInterceptor limits the unary requests. It must run after the peer credentials are available.
+func (l *Limiter) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	m := &l.other
+	if authenticationMethods[info.FullMethod] {
+		m = &l.authentication
+	} else {
+		release, err := l.acquire(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+	m.handled.Add(1)
+
+	return handler(ctx, req)
+}
+
+// acquire checks the rate of the user and waits for a free slot, returning the function to release it.
+func (l *Limiter) acquire(ctx context.Context, m *classMetrics) (release func(), err error) {
+	// The requests of root are the ones of the system services, like sshd and login, on which the authentications
+	// rely, so they are not rate limited.
+	if uid, err := permissions.PeerUID(ctx); err == nil && uid != 0 && !l.allow(ctx, uid) {
+		m.throttled.Add(1)
+		return nil, authderrors.Errorf(authderrors.ResourceExhausted, "too many requests from UID %d", uid)
+	}
+
+	if l.slots == nil {
+		return func() {}, nil
+	}
+
+	release = func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		m.timedOut.Add(1)
+		return nil, ctx.Err()
+	case <-l.clock.After(l.slotWaitTimeout):
+		m.timedOut.Add(1)
+		return nil, authderrors.New(authderrors.ResourceExhausted, "too many concurrent requests")
+	}
+}
+
+// allow returns whether the user can make another request, taking a token from their bucket.
+func (l *Limiter) allow(ctx context.Context, uid uint32) bool {
+	if l.config.RatePerUser == 0 {
+		return true
+	}
+
+	l.bucketsMu.Lock()
+	defer l.bucketsMu.Unlock()
+
+	now := l.clock.Now()
+	b, ok := l.buckets[uid]
+	if !ok {
+		l.removeIdleBuckets(now)
+		b = &bucket{tokens: float64(l.config.Burst), last: now}
+		l.buckets[uid] = b
+	}
+
+	b.tokens = min(float64(l.config.Burst), b.tokens+now.Sub(b.last).Seconds()*l.config.RatePerUser)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+
+	if now.Sub(b.lastLogged) >= throttledLogInterval {
+		log.Warningf(ctx, "Refusing requests from UID %d, which exceeds %g requests per second", uid, l.config.RatePerUser)
+		b.lastLogged = now
+	}
+	return false
+}
+
+// removeIdleBuckets removes the buckets of the users which didn't make requests long enough for them to be full
+// again, if there are too many buckets.
+func (l *Limiter) removeIdleBuckets(now time.Time) {
+	if len(l.buckets) < maxIdleBuckets {
+		return
+	}
+	for uid, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.config.RatePerUser >= float64(l.config.Burst) {
+			delete(l.buckets, uid)
+		}
+	}
+}
+
+// Metrics returns the counters of the authentication requests and of the other ones.
+func (l *Limiter) Metrics() []Metrics {
+	return []Metrics{
+		l.authentication.snapshot("authentication"),
+		l.other.snapshot("other"),
+	}
+}
+
+func (m *classMetrics) snapshot(class string) Metrics {
+	return Metrics{
+		Class:     class,
+		InFlight:  m.inFlight.Load(),
+		Handled:   m.handled.Load(),
+		Throttled: m.throttled.Load(),
+		TimedOut:  m.timedOut.Load(),
+	}
+}
+
+// String returns the metrics in a human readable form.
+func (m Metrics) String() string {
+	return fmt.Sprintf("%s requests: %d in flight, %d handled, %d throttled, %d timed out",
+		strings.ToUpper(m.Class[:1])+m.Class[1:], m.InFlight, m.Handled, m.Throttled, m.TimedOut)
+}
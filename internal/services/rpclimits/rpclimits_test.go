@@ -0,0 +1,194 @@
+package rpclimits_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/clock"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/permissions"
+	"github.com/ubuntu/authd/internal/services/rpclimits"
+	"google.golang.org/grpc"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config rpclimits.Config
+
+		wantErr bool
+	}{
+		"No_limits":              {},
+		"All_limits":             {config: rpclimits.Config{MaxConcurrent: 10, RatePerUser: 5, Burst: 20}},
+		"Rate_without_burst":     {config: rpclimits.Config{RatePerUser: 0.5}},
+		"Error_on_negative_max":  {config: rpclimits.Config{MaxConcurrent: -1}, wantErr: true},
+		"Error_on_negative_rate": {config: rpclimits.Config{RatePerUser: -1}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := rpclimits.New(tc.config)
+			if tc.wantErr {
+				require.Error(t, err, "New should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "New should not return an error, but did")
+		})
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	t.Parallel()
+
+	const otherMethod = authd.NSS_GetPasswdByName_FullMethodName
+	const authMethod = authd.PAM_IsAuthenticated_FullMethodName
+
+	type request struct {
+		method  string
+		uid     uint32
+		advance time.Duration
+
+		wantThrottled bool
+	}
+
+	tests := map[string]struct {
+		config   rpclimits.Config
+		requests []request
+
+		wantThrottled uint64
+	}{
+		"Handle_requests_without_limits": {
+			requests: []request{{method: otherMethod, uid: 1000}, {method: otherMethod, uid: 1000}, {method: otherMethod, uid: 1000}},
+		},
+		"Throttle_requests_of_user_above_burst": {
+			config: rpclimits.Config{RatePerUser: 1, Burst: 2},
+			requests: []request{
+				{method: otherMethod, uid: 1000},
+				{method: otherMethod, uid: 1000},
+				{method: otherMethod, uid: 1000, wantThrottled: true},
+			},
+			wantThrottled: 1,
+		},
+		"Allow_requests_again_at_the_configured_rate": {
+			config: rpclimits.Config{RatePerUser: 1, Burst: 1},
+			requests: []request{
+				{method: otherMethod, uid: 1000},
+				{method: otherMethod, uid: 1000, advance: 500 * time.Millisecond, wantThrottled: true},
+				{method: otherMethod, uid: 1000, advance: 500 * time.Millisecond},
+			},
+			wantThrottled: 1,
+		},
+		"Throttle_users_separately": {
+			config: rpclimits.Config{RatePerUser: 1, Burst: 1},
+			requests: []request{
+				{method: otherMethod, uid: 1000},
+				{method: otherMethod, uid: 1000, wantThrottled: true},
+				{method: otherMethod, uid: 1001},
+			},
+			wantThrottled: 1,
+		},
+		"Do_not_throttle_root": {
+			config:   rpclimits.Config{RatePerUser: 1, Burst: 1},
+			requests: []request{{method: otherMethod, uid: 0}, {method: otherMethod, uid: 0}, {method: otherMethod, uid: 0}},
+		},
+		"Do_not_throttle_authentication_requests": {
+			config: rpclimits.Config{RatePerUser: 1, Burst: 1},
+			requests: []request{
+				{method: otherMethod, uid: 1000},
+				{method: authMethod, uid: 1000},
+				{method: authMethod, uid: 1000},
+				{method: otherMethod, uid: 1000, wantThrottled: true},
+			},
+			wantThrottled: 1,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := clock.NewFake(time.Unix(1700000000, 0))
+			l, err := rpclimits.New(tc.config, rpclimits.WithClock(c))
+			require.NoError(t, err, "Setup: New should not return an error, but did")
+
+			var handled int
+			handler := func(ctx context.Context, req any) (any, error) {
+				handled++
+				return nil, nil
+			}
+
+			var wantHandled int
+			for i, r := range tc.requests {
+				c.Advance(r.advance)
+
+				ctx := permissions.Z_ForTests_ContextWithPeerUID(context.Background(), r.uid)
+				_, err := l.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: r.method}, handler)
+				if r.wantThrottled {
+					require.True(t, authderrors.Is(err, authderrors.ResourceExhausted), "Request %d should be throttled, got %v", i, err)
+					continue
+				}
+				require.NoError(t, err, "Request %d should not be throttled", i)
+				wantHandled++
+			}
+			require.Equal(t, wantHandled, handled, "The handler should be called for the requests which are not throttled")
+
+			var throttled uint64
+			for _, m := range l.Metrics() {
+				throttled += m.Throttled
+			}
+			require.Equal(t, tc.wantThrottled, throttled, "Metrics should count the throttled requests")
+		})
+	}
+}
+
+func TestConcurrencyLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		method string
+
+		wantTimedOut bool
+	}{
+		"Refuse_requests_when_no_slot_is_available_in_time": {method: authd.NSS_GetPasswdByName_FullMethodName, wantTimedOut: true},
+		"Do_not_limit_concurrent_authentication_requests":   {method: authd.PAM_IsAuthenticated_FullMethodName},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, err := rpclimits.New(rpclimits.Config{MaxConcurrent: 1}, rpclimits.WithSlotWaitTimeout(10*time.Millisecond))
+			require.NoError(t, err, "Setup: New should not return an error, but did")
+
+			ctx := permissions.Z_ForTests_ContextWithPeerUID(context.Background(), 1000)
+			info := &grpc.UnaryServerInfo{FullMethod: tc.method}
+
+			// A first request holds the only slot until the end of the test.
+			started, done := make(chan struct{}), make(chan struct{})
+			t.Cleanup(func() { close(done) })
+			go func() {
+				_, _ = l.UnaryInterceptor(ctx, nil, info, func(context.Context, any) (any, error) {
+					close(started)
+					<-done
+					return nil, nil
+				})
+			}()
+			<-started
+
+			_, err = l.UnaryInterceptor(ctx, nil, info, func(context.Context, any) (any, error) { return nil, nil })
+			if !tc.wantTimedOut {
+				require.NoError(t, err, "The request should be handled")
+				return
+			}
+			require.True(t, authderrors.Is(err, authderrors.ResourceExhausted), "The request should be refused, got %v", err)
+
+			m := l.Metrics()
+			require.Equal(t, "other", m[1].Class, "The second metrics should be the ones of the other requests")
+			require.Equal(t, int64(1), m[1].InFlight, "Metrics should count the request in flight")
+			require.Equal(t, uint64(1), m[1].TimedOut, "Metrics should count the timed out request")
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/pam"
+	"github.com/ubuntu/authd/internal/services/rpclimits"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// StateDump is a diagnostic snapshot of the state of the daemon.
+//
+// The session IDs, which allow acting on the sessions of the users, are redacted: they are replaced by a digest, so
+// that the same session can still be followed through the dump.
+type StateDump struct {
+	Time         time.Time             `json:"time"`
+	Sessions     []SessionState        `json:"sessions"`
+	Brokers      []BrokerState         `json:"brokers"`
+	Cache        CacheStats            `json:"cache"`
+	RPCMetrics   []rpclimits.Metrics   `json:"rpc_metrics"`
+	BruteForce   pam.BruteForceMetrics `json:"brute_force"`
+	RecentErrors []log.Record          `json:"recent_errors"`
+	Goroutines   string                `json:"goroutines"`
+}
+
+// SessionState is a session in progress.
+type SessionState struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	BrokerID  string    `json:"broker_id"`
+	Stage     string    `json:"stage"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// BrokerState is a broker available to the users, with the number of its sessions in progress.
+type BrokerState struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Sessions int    `json:"sessions"`
+}
+
+// CacheStats are the number of users and groups in the database, or the error reading them.
+type CacheStats struct {
+	Users  int    `json:"users"`
+	Groups int    `json:"groups"`
+	Error  string `json:"error,omitempty"`
+}
+
+// StateDump returns a diagnostic snapshot of the state of the daemon.
+func (m Manager) StateDump(ctx context.Context) StateDump {
+	d := StateDump{
+		Time:         time.Now(),
+		RPCMetrics:   m.RPCMetrics(),
+		BruteForce:   m.BruteForceMetrics(),
+		RecentErrors: log.RecentErrors(),
+		Goroutines:   goroutines(),
+	}
+
+	sessions, err := m.pamService.ListSessions(ctx, &authd.Empty{})
+	if err != nil {
+		log.Warningf(ctx, "Could not list the sessions for the state dump: %v", err)
+	}
+	brokerSessions := make(map[string]int)
+	var sessionIDs []string
+	for _, s := range sessions.GetSessions() {
+		sessionIDs = append(sessionIDs, s.GetSessionId())
+		brokerSessions[s.GetBrokerId()]++
+		d.Sessions = append(d.Sessions, SessionState{
+			ID:        redactSessionID(s.GetSessionId()),
+			Username:  s.GetUsername(),
+			BrokerID:  s.GetBrokerId(),
+			Stage:     s.GetStage(),
+			StartTime: time.Unix(s.GetStartTime(), 0),
+		})
+	}
+	// The session IDs may also be in the messages of the errors.
+	for i, r := range d.RecentErrors {
+		for _, id := range sessionIDs {
+			r.Message = strings.ReplaceAll(r.Message, id, redactSessionID(id))
+		}
+		d.RecentErrors[i] = r
+	}
+
+	for _, b := range m.brokerManager.AvailableBrokers() {
+		d.Brokers = append(d.Brokers, BrokerState{ID: b.ID, Name: b.Name, Sessions: brokerSessions[b.ID]})
+	}
+
+	users, err := m.userManager.AllUsers()
+	if err != nil {
+		d.Cache.Error = err.Error()
+		return d
+	}
+	groups, err := m.userManager.AllGroups()
+	if err != nil {
+		d.Cache.Error = err.Error()
+		return d
+	}
+	d.Cache.Users, d.Cache.Groups = len(users), len(groups)
+
+	return d
+}
+
+// WriteStateDump writes a diagnostic snapshot of the state of the daemon to a timestamped file in dir, readable by
+// root only, and returns its path.
+func (m Manager) WriteStateDump(ctx context.Context, dir string) (path string, err error) {
+	defer decorate.OnError(&err, "can't write state dump")
+
+	d := m.StateDump(ctx)
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path = filepath.Join(dir, fmt.Sprintf("authd-state-%s.json", d.Time.Format("20060102-150405.000")))
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// redactSessionID returns a digest of the session ID, which can't be used to act on the session.
+func redactSessionID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return "redacted-" + hex.EncodeToString(sum[:6])
+}
+
+// goroutines returns the stack traces of all the goroutines.
+func goroutines() string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -3,6 +3,10 @@ package testutils
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
 	"fmt"
 	"html/template"
 	"os"
@@ -13,7 +17,9 @@ import (
 
 	"github.com/godbus/dbus/v5"
 	"github.com/godbus/dbus/v5/introspect"
+	"github.com/ubuntu/authd/internal/brokers/auth"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
 )
 
 const (
@@ -43,6 +49,7 @@ name = %s
 brand_icon = mock_icon.png
 dbus_name = com.ubuntu.authd.%s
 dbus_object = /com/ubuntu/authd/%s
+step_up = true
 `
 
 type isAuthenticatedCtx struct {
@@ -53,6 +60,8 @@ type isAuthenticatedCtx struct {
 // BrokerBusMock is the D-Bus object that will answer calls for the broker mock.
 type BrokerBusMock struct {
 	name                   string
+	conn                   *dbus.Conn
+	objectPath             dbus.ObjectPath
 	isAuthenticatedCalls   map[string]isAuthenticatedCtx
 	isAuthenticatedCallsMu sync.RWMutex
 }
@@ -70,6 +79,8 @@ func StartBusBrokerMock(cfgDir string, brokerName string) (string, func(), error
 
 	bus := BrokerBusMock{
 		name:                   brokerName,
+		conn:                   conn,
+		objectPath:             dbus.ObjectPath(busObjectPath),
 		isAuthenticatedCalls:   map[string]isAuthenticatedCtx{},
 		isAuthenticatedCallsMu: sync.RWMutex{},
 	}
@@ -114,7 +125,7 @@ func StartBusBrokerMock(cfgDir string, brokerName string) (string, func(), error
 
 func writeConfig(cfgDir, name string) (string, error) {
 	cfgPath := filepath.Join(cfgDir, name+".conf")
-	s := fmt.Sprintf(brokerConfigTemplate, name, name, name, name)
+	s := fmt.Sprintf(brokerConfigTemplate, name, name, name)
 	if err := os.WriteFile(cfgPath, []byte(s), 0600); err != nil {
 		return "", err
 	}
@@ -130,6 +141,24 @@ func (b *BrokerBusMock) NewSession(username, lang, mode string) (sessionID, encr
 	if parsedUsername == "NS_no_id" {
 		return "", username + "_key", nil
 	}
+	if parsedUsername == "NS_unsupported_encryption_algorithm" {
+		return GenerateSessionID(username), "rsa-pkcs1:" + GenerateEncryptionKey(b.name), nil
+	}
+	if parsedUsername == "NS_encryption_algorithm" {
+		return GenerateSessionID(username), "rsa-oaep-sha256:" + GenerateEncryptionKey(b.name), nil
+	}
+	if strings.HasPrefix(parsedUsername, "HA_") {
+		// Headless authentications encrypt the secret on the daemon side, so they need a valid key.
+		key, err := rsaEncryptionKey()
+		if err != nil {
+			return "", "", dbus.MakeFailedError(err)
+		}
+		return GenerateSessionID(username), key, nil
+	}
+	if mode == auth.SessionModeStepUp {
+		// Step-up sessions get their own key, so that the tests can tell which mode was requested.
+		return GenerateSessionID(username), GenerateEncryptionKey(b.name + "-step-up"), nil
+	}
 	return GenerateSessionID(username), GenerateEncryptionKey(b.name), nil
 }
 
@@ -199,8 +228,42 @@ func (b *BrokerBusMock) SelectAuthenticationMode(sessionID, authenticationModeNa
 			layouts.Entry:   "entry_type",
 			"unknown_field": "unknown",
 		}, nil
+	case "SAM_code_entry":
+		return map[string]string{
+			layouts.Type:         layouts.Form,
+			layouts.Label:        "Enter the code sent to your phone",
+			layouts.Entry:        "entry_type",
+			layouts.Button:       "Resend code",
+			layouts.CodeLength:   "6",
+			layouts.CodeValidity: "30",
+		}, nil
+	case "SAM_cached_credentials":
+		return map[string]string{
+			layouts.Type:              layouts.Form,
+			layouts.Label:             "Enter your password",
+			layouts.Entry:             "entry_type",
+			layouts.CachedCredentials: layouts.True,
+		}, nil
+	case "SAM_smart_card":
+		return map[string]string{
+			layouts.Type:            layouts.SmartCard,
+			layouts.Label:           "Enter the PIN of your smart card",
+			layouts.Entry:           "entry_type",
+			layouts.SmartCardAction: layouts.SmartCardPIN,
+		}, nil
+	case "SAM_invalid_smart_card_action":
+		return map[string]string{
+			layouts.Type:            layouts.SmartCard,
+			layouts.SmartCardAction: "invalid action",
+		}, nil
 	case "SAM_error":
 		return nil, dbus.MakeFailedError(fmt.Errorf("broker %q: SelectAuthenticationMode errored out", b.name))
+	case "HA_success", "HA_next":
+		return map[string]string{
+			layouts.Type:  layouts.Form,
+			layouts.Label: "Enter your secret",
+			layouts.Entry: entries.CharsPassword,
+		}, nil
 	case "SAM_no_layout":
 		return nil, nil
 	case "SAM_empty_layout":
@@ -254,6 +317,14 @@ func (b *BrokerBusMock) IsAuthenticated(sessionID, authenticationData string) (a
 		access = authCancelled
 		data = ""
 
+	case "IA_progress":
+		if err := b.conn.Emit(b.objectPath, dbusInterface+".AuthProgress", sessionID, "Waiting for approval on your phone", int32(50), true); err != nil {
+			return "", "", dbus.MakeFailedError(fmt.Errorf("broker %q: could not report progress: %v", b.name, err))
+		}
+		<-ctx.Done()
+		access = authCancelled
+		data = ""
+
 	case "IA_second_call":
 		select {
 		case <-ctx.Done():
@@ -264,7 +335,7 @@ func (b *BrokerBusMock) IsAuthenticated(sessionID, authenticationData string) (a
 			data = fmt.Sprintf(`{"userinfo": %s}`, userInfoFromName(sessionID, nil))
 		}
 
-	case "IA_next":
+	case "IA_next", "HA_next":
 		access = authNext
 		data = ""
 
@@ -292,6 +363,38 @@ func (b *BrokerBusMock) IsAuthenticated(sessionID, authenticationData string) (a
 		access = authRetry
 		data = ""
 
+	case "IA_denied":
+		access = authDenied
+		data = `{"message": "invalid password"}`
+
+	case "IA_retry":
+		access = authRetry
+		data = `{"message": "invalid password, try again"}`
+
+	case "IA_denied_with_error_code":
+		access = authDenied
+		data = `{"message": "account locked by the administrator", "error_code": "account-locked"}`
+
+	case "IA_denied_with_unknown_error_code":
+		access = authDenied
+		data = `{"message": "something went wrong", "error_code": "unknown-code"}`
+
+	case "IA_retry_with_invalid_error_code":
+		access = authRetry
+		data = `{"message": "try again", "error_code": 42}`
+
+	case "IA_retry_with_encryption_key":
+		access = authRetry
+		data = `{"message": "try again", "encryption_key": "rsa-oaep-sha256:rotated-key"}`
+
+	case "IA_retry_with_unsupported_encryption_algorithm":
+		access = authRetry
+		data = `{"message": "try again", "encryption_key": "rsa-pkcs1:rotated-key"}`
+
+	case "IA_next_with_encryption_key":
+		access = authNext
+		data = `{"encryption_key": "rotated-key"}`
+
 	case "IA_next_with_data":
 		access = authNext
 		data = `{"message": "there should not be a message here"}`
@@ -361,6 +464,7 @@ func userInfoFromName(sessionID string, extraGroups []groupJSONInfo) string {
 	shell := "/bin/sh/" + parsedID
 	gecos := "gecos for " + parsedID
 	ugid := "ugid-" + parsedID
+	var avatarURL, gecosFullName string
 
 	switch parsedID {
 	case "IA_info_empty_user_name":
@@ -379,6 +483,14 @@ func userInfoFromName(sessionID string, extraGroups []groupJSONInfo) string {
 		home = "this is not a homedir"
 	case "IA_info_invalid_shell":
 		shell = "this is not a valid shell"
+	case "IA_info_avatar_url":
+		avatarURL = "https://example.com/avatars/" + parsedID + ".png"
+	case "IA_info_invalid_avatar_url":
+		avatarURL = "file:///etc/shadow"
+	case "IA_info_gecos_fields":
+		gecosFullName = "Full name for " + parsedID
+	case "IA_info_invalid_gecos_fields":
+		gecosFullName = "Name, with a comma"
 	}
 
 	groups := []groupJSONInfo{{Name: group, UGID: ugid}}
@@ -395,13 +507,15 @@ func userInfoFromName(sessionID string, extraGroups []groupJSONInfo) string {
 	}
 
 	user := struct {
-		Name   string
-		UUID   string
-		Dir    string
-		Shell  string
-		Groups []groupJSONInfo
-		Gecos  string
-	}{Name: name, Dir: home, Shell: shell, Groups: groups, Gecos: gecos}
+		Name          string
+		UUID          string
+		Dir           string
+		Shell         string
+		Groups        []groupJSONInfo
+		Gecos         string
+		AvatarURL     string
+		GecosFullName string
+	}{Name: name, Dir: home, Shell: shell, Groups: groups, Gecos: gecos, AvatarURL: avatarURL, GecosFullName: gecosFullName}
 
 	// only used for tests, we can ignore the template execution error as the returned data will be failing.
 	var buf bytes.Buffer
@@ -412,6 +526,12 @@ func userInfoFromName(sessionID string, extraGroups []groupJSONInfo) string {
 		"dir": "{{.Dir}}",
 		"shell": "{{.Shell}}",
 		"avatar": "avatar for {{.Name}}",
+		{{- if .AvatarURL}}
+		"avatar_url": "{{.AvatarURL}}",
+		{{- end}}
+		{{- if .GecosFullName}}
+		"gecos_fields": {"full_name": "{{.GecosFullName}}"},
+		{{- end}}
 		"groups": [ {{range $index, $g := .Groups}}
 			{{- if $index}}, {{end -}}
 			{"name": "{{.Name}}", "ugid": "{{.UGID}}"}
@@ -430,3 +550,16 @@ func GenerateSessionID(username string) string {
 func GenerateEncryptionKey(brokerName string) string {
 	return fmt.Sprintf("%s-key", brokerName)
 }
+
+// rsaEncryptionKey returns a valid base64 encoded RSA public key, generated once for all the tests.
+var rsaEncryptionKey = sync.OnceValues(func() (string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", err
+	}
+	pubASN1, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(pubASN1), nil
+})
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/clock"
 	"github.com/ubuntu/authd/internal/grpcutils"
 	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/internal/users/db"
@@ -113,6 +114,7 @@ paths:
 	cmd := exec.CommandContext(ctx, execPath, "-c", configPath)
 	opts.env = append(opts.env, os.Environ()...)
 	opts.env = append(opts.env, fmt.Sprintf("AUTHD_EXAMPLE_BROKER_SLEEP_MULTIPLIER=%f", SleepMultiplier()))
+	opts.env = append(opts.env, fmt.Sprintf("%s=%f", clock.ScaleEnv, SleepMultiplier()))
 	cmd.Env = AppendCovEnv(opts.env)
 
 	// This is the function that is called by CommandContext when the context is cancelled.
@@ -0,0 +1,156 @@
+// Package tracing records OpenTelemetry spans of the requests handled by authd, so that the latency of a login can be
+// broken down between the daemon, the brokers and the database.
+package tracing
+
+import (
+	"context"
+
+	"github.com/ubuntu/authd/internal/consts"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	tracerName  = "github.com/ubuntu/authd"
+	serviceName = "authd"
+)
+
+// Config is the configuration of the tracing.
+type Config struct {
+	// Endpoint is the address of the OTLP gRPC collector the spans are exported to. Tracing is disabled when it is
+	// empty.
+	Endpoint string `mapstructure:"otlp_endpoint"`
+	// Insecure disables the TLS connection to the collector, for example when it listens on the local host.
+	Insecure bool `mapstructure:"insecure"`
+}
+
+type options struct {
+	exporter sdktrace.SpanExporter
+}
+
+// Option is a function that allows changing some of the default behaviors of the tracing.
+type Option func(*options)
+
+// WithExporter exports the spans with the given exporter instead of the OTLP one of the configuration.
+//
+// This option is only useful in tests.
+func WithExporter(exporter sdktrace.SpanExporter) Option {
+	return func(o *options) {
+		o.exporter = exporter
+	}
+}
+
+// Setup installs the tracer provider exporting the spans to the configured collector. The returned function flushes
+// the pending spans and stops exporting them.
+// When no collector is configured, the spans are not recorded at all.
+func Setup(ctx context.Context, config Config, args ...Option) (shutdown func(context.Context) error, err error) {
+	defer decorate.OnError(&err, "could not set up tracing")
+
+	opts := options{}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	if config.Endpoint == "" && opts.exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter := opts.exporter
+	if exporter == nil {
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.Endpoint)}
+		if config.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		// The exporter connects lazily, so an unavailable collector doesn't prevent the daemon from starting.
+		exporter, err = otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+		attribute.String("service.version", consts.Version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Warningf(context.Background(), "Tracing: %v", err)
+	}))
+	log.Infof(ctx, "Exporting traces to %q", config.Endpoint)
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span which is a child of the one in the context, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error, if any, and ends the span. It is meant to be deferred with the named error of the function.
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+// UnaryServerInterceptor records a span for each request, continuing the trace of the client if it propagated one in
+// the metadata of the request.
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, info.FullMethod,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", info.FullMethod),
+		))
+	defer End(span, &err)
+
+	return handler(ctx, req)
+}
+
+// metadataCarrier allows the propagator to read the trace context from the gRPC metadata.
+type metadataCarrier metadata.MD
+
+// Get returns the first value of the key.
+func (c metadataCarrier) Get(key string) string {
+	v := metadata.MD(c).Get(key)
+	if len(v) == 0 {
+		return ""
+	}
+	return v[0]
+}
+
+// Set sets the value of the key.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys returns all the keys of the metadata.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,95 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	clientTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	clientSpanID  = "00f067aa0ba902b7"
+)
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{})
+	require.NoError(t, err, "Setup should not return an error, but did")
+	t.Cleanup(func() { require.NoError(t, shutdown(context.Background()), "Teardown: shutdown should not fail") })
+
+	_, span := tracing.Start(context.Background(), "test")
+	defer span.End()
+	require.False(t, span.IsRecording(), "Spans should not be recorded without an endpoint")
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tests := map[string]struct {
+		clientTraceparent bool
+		handlerErr        error
+
+		wantParentSpanID string
+	}{
+		"Record_a_span_for_the_request":          {},
+		"Continue_the_trace_of_the_client":       {clientTraceparent: true, wantParentSpanID: clientSpanID},
+		"Record_the_error_returned_by_a_request": {handlerErr: errors.New("some error")},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			exporter := keepingExporter{tracetest.NewInMemoryExporter()}
+			shutdown, err := tracing.Setup(context.Background(), tracing.Config{}, tracing.WithExporter(exporter))
+			require.NoError(t, err, "Setup: Setup should not return an error, but did")
+
+			ctx := context.Background()
+			if tc.clientTraceparent {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("traceparent", "00-"+clientTraceID+"-"+clientSpanID+"-01"))
+			}
+
+			handler := func(ctx context.Context, req any) (any, error) {
+				_, span := tracing.Start(ctx, "child")
+				tracing.End(span, &tc.handlerErr)
+				return nil, tc.handlerErr
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: "/authd.PAM/IsAuthenticated"}
+			_, err = tracing.UnaryServerInterceptor(ctx, nil, info, handler)
+			require.ErrorIs(t, err, tc.handlerErr, "The interceptor should return the error of the handler")
+
+			require.NoError(t, shutdown(context.Background()), "Shutdown should flush the spans without error")
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, 2, "There should be a span for the request and one for its child")
+			child, request := spans[0], spans[1]
+
+			require.Equal(t, "/authd.PAM/IsAuthenticated", request.Name, "The span of the request should be named after the method")
+			require.Equal(t, trace.SpanKindServer, request.SpanKind, "The span of the request should be a server span")
+			require.Equal(t, request.SpanContext.SpanID(), child.Parent.SpanID(), "Spans started in the handler should be children of the request")
+
+			if tc.wantParentSpanID != "" {
+				require.Equal(t, clientTraceID, request.SpanContext.TraceID().String(), "The request should continue the trace of the client")
+				require.Equal(t, tc.wantParentSpanID, request.Parent.SpanID().String(), "The request should be a child of the span of the client")
+			} else {
+				require.False(t, request.Parent.IsValid(), "The request should start a new trace")
+			}
+
+			wantStatus := codes.Unset
+			if tc.handlerErr != nil {
+				wantStatus = codes.Error
+			}
+			require.Equal(t, wantStatus, request.Status.Code, "Unexpected status of the request span")
+			require.Equal(t, wantStatus, child.Status.Code, "Unexpected status of the child span")
+		})
+	}
+}
+
+// keepingExporter keeps the exported spans after being shut down, so that they can be checked once flushed.
+type keepingExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (keepingExporter) Shutdown(context.Context) error { return nil }
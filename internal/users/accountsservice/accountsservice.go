@@ -0,0 +1,129 @@
+// Package accountsservice installs the pictures of the users where AccountsService, which the display managers use
+// to show the users, looks for them.
+package accountsservice
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ubuntu/decorate"
+)
+
+// DefaultDir is the directory where AccountsService stores the settings and the icons of the users.
+const DefaultDir = "/var/lib/AccountsService"
+
+// SetIcon installs the icon of the user in the AccountsService directory dir, and references it in the settings of
+// the user, keeping the other ones.
+func SetIcon(dir, username string, icon []byte) (err error) {
+	defer decorate.OnError(&err, "could not set the AccountsService icon of user %q", username)
+
+	if username == "" || strings.ContainsRune(username, filepath.Separator) || username == "." || username == ".." {
+		return fmt.Errorf("invalid user name %q", username)
+	}
+
+	iconsDir := filepath.Join(dir, "icons")
+	if err := os.MkdirAll(iconsDir, 0755); err != nil {
+		return err
+	}
+	usersDir := filepath.Join(dir, "users")
+	if err := os.MkdirAll(usersDir, 0700); err != nil {
+		return err
+	}
+
+	iconPath := filepath.Join(iconsDir, username)
+	if err := writeFile(iconPath, icon, 0644); err != nil {
+		return err
+	}
+
+	settingsPath := filepath.Join(usersDir, username)
+	settings, err := os.ReadFile(settingsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return writeFile(settingsPath, setKey(settings, "User", "Icon", iconPath), 0600)
+}
+
+// setKey returns the content of the key file with the key of the section set to value, adding the section if needed.
+func setKey(content []byte, section, key, value string) []byte {
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(content) == 0 {
+		lines = nil
+	}
+
+	entry := key + "=" + value
+	inSection := false
+	sectionEnd := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			if inSection {
+				break
+			}
+			inSection = trimmed == "["+section+"]"
+			if inSection {
+				sectionEnd = i + 1
+			}
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if k, _, ok := strings.Cut(trimmed, "="); ok && strings.TrimSpace(k) == key {
+			lines[i] = entry
+			return joinLines(lines)
+		}
+		if trimmed != "" {
+			sectionEnd = i + 1
+		}
+	}
+
+	if sectionEnd < 0 {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		return joinLines(append(lines, "["+section+"]", entry))
+	}
+	return joinLines(append(lines[:sectionEnd], append([]string{entry}, lines[sectionEnd:]...)...))
+}
+
+func joinLines(lines []string) []byte {
+	var buf bytes.Buffer
+	for _, l := range lines {
+		buf.WriteString(l + "\n")
+	}
+	return buf.Bytes()
+}
+
+// writeFile atomically replaces the content of the file.
+func writeFile(path string, content []byte, perm os.FileMode) (err error) {
+	defer decorate.OnError(&err, "could not write %s", path)
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".authd-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	defer tmp.Close()
+
+	if _, err := tmp.Write(content); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,72 @@
+package accountsservice_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/testutils/golden"
+	"github.com/ubuntu/authd/internal/users/accountsservice"
+)
+
+func TestSetIcon(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username     string
+		settingsFile string
+
+		wantErr bool
+	}{
+		"Set_icon_when_user_has_no_settings":                {},
+		"Set_icon_keeping_other_settings":                   {settingsFile: "without_icon.settings"},
+		"Set_icon_replacing_previous_one":                   {settingsFile: "with_icon.settings"},
+		"Set_icon_adding_user_section_to_existing_settings": {settingsFile: "without_user_section.settings"},
+
+		"Error_when_username_is_empty":         {username: "-", wantErr: true},
+		"Error_when_username_contains_a_slash": {username: "../user1", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			switch tc.username {
+			case "":
+				tc.username = "user1"
+			case "-":
+				tc.username = ""
+			}
+
+			dir := t.TempDir()
+			if tc.settingsFile != "" {
+				content, err := os.ReadFile(filepath.Join("testdata", tc.settingsFile))
+				require.NoError(t, err, "Setup: could not read settings file")
+				require.NoError(t, os.MkdirAll(filepath.Join(dir, "users"), 0700), "Setup: could not create users directory")
+				err = os.WriteFile(filepath.Join(dir, "users", tc.username), content, 0600)
+				require.NoError(t, err, "Setup: could not write settings file")
+			}
+
+			err := accountsservice.SetIcon(dir, tc.username, []byte("user1 picture"))
+			if tc.wantErr {
+				require.Error(t, err, "SetIcon should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "SetIcon should not return an error, but did")
+
+			icon, err := os.ReadFile(filepath.Join(dir, "icons", tc.username))
+			require.NoError(t, err, "SetIcon should have installed the icon")
+			require.Equal(t, "user1 picture", string(icon), "SetIcon installed an unexpected icon")
+
+			settingsPath := filepath.Join(dir, "users", tc.username)
+			fi, err := os.Stat(settingsPath)
+			require.NoError(t, err, "SetIcon should have written the settings")
+			require.Equal(t, os.FileMode(0600), fi.Mode().Perm(), "Settings should only be readable by root")
+
+			settings, err := os.ReadFile(settingsPath)
+			require.NoError(t, err, "SetIcon should have written the settings")
+			golden.CheckOrUpdate(t, strings.ReplaceAll(string(settings), dir, "ACCOUNTSSERVICE_DIR"))
+		})
+	}
+}
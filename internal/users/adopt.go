@@ -0,0 +1,110 @@
+package users
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/decorate"
+)
+
+// defaultPasswdFile is the file listing the local users, which can be adopted by the users of the brokers.
+const defaultPasswdFile = "/etc/passwd"
+
+// ErrInvalidAdoption is returned when a local user can't be adopted by a user of a broker.
+var ErrInvalidAdoption = errors.New("invalid user adoption")
+
+// AdoptUser migrates the local user to the management of authd: the user of the broker with the given name takes over
+// its UID, primary group and home directory on their next login, so that they keep owning their files without any
+// chown, instead of getting a new identity and home directory.
+func (m *Manager) AdoptUser(localName, brokerUser string) (err error) {
+	defer decorate.OnError(&err, "could not adopt local user %q as %q", localName, brokerUser)
+
+	m.updateUserMu.Lock()
+	defer m.updateUserMu.Unlock()
+
+	local, err := lookupLocalUser(m.passwdFile, localName)
+	if err != nil {
+		return err
+	}
+	if err := m.checkReservedName(brokerUser, ""); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAdoption, err)
+	}
+	if err := m.checkReservedUID(brokerUser, local.UID, ""); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAdoption, err)
+	}
+
+	if _, err := m.db.UserByName(brokerUser); err == nil {
+		return fmt.Errorf("%w: user %q already logged in with authd", ErrInvalidAdoption, brokerUser)
+	} else if !errors.Is(err, db.NoDataFoundError{}) {
+		return err
+	}
+	if u, err := m.db.UserByID(local.UID); err == nil {
+		return fmt.Errorf("%w: UID %d is already used by the authd user %q", ErrInvalidAdoption, local.UID, u.Name)
+	} else if !errors.Is(err, db.NoDataFoundError{}) {
+		return err
+	}
+	if a, err := m.db.AdoptedUserByName(brokerUser); err == nil {
+		return fmt.Errorf("%w: user %q already adopted the local user %q", ErrInvalidAdoption, brokerUser, a.LocalName)
+	} else if !errors.Is(err, db.NoDataFoundError{}) {
+		return err
+	}
+	if a, err := m.db.AdoptedUserByID(local.UID); err == nil {
+		return fmt.Errorf("%w: local user %q was already adopted by %q", ErrInvalidAdoption, localName, a.Name)
+	} else if !errors.Is(err, db.NoDataFoundError{}) {
+		return err
+	}
+
+	local.Name = brokerUser
+	return m.db.AddAdoptedUser(local)
+}
+
+// lookupLocalUser returns the adoption of the user listed in the passwd file, which is not yet associated with any
+// user of a broker. Only the users of the file can be adopted, not the ones of other NSS sources.
+func lookupLocalUser(passwdFile, name string) (db.AdoptedUserRow, error) {
+	f, err := os.Open(passwdFile)
+	if err != nil {
+		return db.AdoptedUserRow{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// The entries are in the format name:password:uid:gid:gecos:dir:shell.
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) != 7 || fields[0] != name {
+			continue
+		}
+
+		uid, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return db.AdoptedUserRow{}, fmt.Errorf("invalid UID of local user %q: %w", name, err)
+		}
+		gid, err := strconv.ParseUint(fields[3], 10, 32)
+		if err != nil {
+			return db.AdoptedUserRow{}, fmt.Errorf("invalid GID of local user %q: %w", name, err)
+		}
+		return db.AdoptedUserRow{LocalName: name, UID: uint32(uid), GID: uint32(gid), Dir: fields[5]}, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return db.AdoptedUserRow{}, err
+	}
+
+	return db.AdoptedUserRow{}, NoDataFoundError{}
+}
+
+// adoptedUser returns the local user adopted by the user of the broker, if any.
+func (m *Manager) adoptedUser(name string) (a db.AdoptedUserRow, adopted bool, err error) {
+	a, err = m.db.AdoptedUserByName(name)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return db.AdoptedUserRow{}, false, nil
+	}
+	if err != nil {
+		return db.AdoptedUserRow{}, false, err
+	}
+	return a, true, nil
+}
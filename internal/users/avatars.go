@@ -0,0 +1,149 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ubuntu/authd/internal/users/accountsservice"
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/types"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// maxAvatarSize is the maximum size of the pictures of the users, which AccountsService doesn't accept above it.
+	maxAvatarSize = 1 << 20
+	// avatarDownloadTimeout is the maximum time to download the picture of a user from the address given by the
+	// broker, which delays the login of the user.
+	avatarDownloadTimeout = 5 * time.Second
+)
+
+// ErrInvalidAvatar is returned when the picture set for a user is not one which AccountsService accepts.
+var ErrInvalidAvatar = errors.New("invalid avatar")
+
+// Avatar returns the picture of the user provided by their broker, and its MIME type.
+func (m *Manager) Avatar(username string) (content []byte, contentType string, err error) {
+	a, err := m.db.AvatarForUser(username)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return nil, "", NoDataFoundError{}
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return a.Content, http.DetectContentType(a.Content), nil
+}
+
+// SetAvatar changes the picture of the user, until the broker provides another one on the next login, and installs it
+// as the icon of the user in AccountsService.
+func (m *Manager) SetAvatar(username string, content []byte) (err error) {
+	defer decorate.OnError(&err, "could not set the avatar of user %q", username)
+
+	if err := checkAvatar(content); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAvatar, err)
+	}
+
+	unlock := m.userLocks.lock(username)
+	defer unlock()
+
+	err = m.db.SetAvatarForUser(username, "", content)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return NoDataFoundError{}
+	}
+	if err != nil {
+		return err
+	}
+	if err := m.installAvatar(username, content); err != nil {
+		return err
+	}
+
+	m.notifyUserUpdated(username, false)
+	return nil
+}
+
+// updateAvatar stores the picture of the user provided by the broker, downloading it if the broker only gave its
+// address, and installs it as the icon of the user in AccountsService, so that the display managers show it.
+//
+// A picture downloaded from an address is cached, and only downloaded again once the broker gives another address.
+func (m *Manager) updateAvatar(u types.UserInfo) (err error) {
+	if len(u.AvatarData) == 0 && u.AvatarURL == "" {
+		return nil
+	}
+	defer decorate.OnError(&err, "could not update the avatar of user %q", u.Name)
+
+	cached, err := m.db.AvatarForUser(u.Name)
+	if err != nil && !errors.Is(err, db.NoDataFoundError{}) {
+		return err
+	}
+
+	content, url := u.AvatarData, ""
+	if len(content) == 0 {
+		if cached.URL == u.AvatarURL {
+			return nil
+		}
+		url = u.AvatarURL
+		if content, err = downloadAvatar(url); err != nil {
+			return err
+		}
+	} else if cached.URL == "" && bytes.Equal(cached.Content, content) {
+		return nil
+	}
+
+	if err := checkAvatar(content); err != nil {
+		return err
+	}
+	if err := m.db.SetAvatarForUser(u.Name, url, content); err != nil {
+		return err
+	}
+	return m.installAvatar(u.Name, content)
+}
+
+// installAvatar installs the picture as the icon of the user in AccountsService, if it's installed.
+func (m *Manager) installAvatar(username string, content []byte) error {
+	if _, err := os.Stat(m.accountsServiceDir); errors.Is(err, os.ErrNotExist) {
+		log.Debugf(context.Background(), "AccountsService is not installed, not setting the icon of user %q", username)
+		return nil
+	}
+	return accountsservice.SetIcon(m.accountsServiceDir, username, content)
+}
+
+// downloadAvatar returns the picture of a user downloaded from the given address.
+func downloadAvatar(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), avatarDownloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download %s: %s", url, resp.Status)
+	}
+
+	// Read one more byte than allowed to detect pictures which are too large.
+	return io.ReadAll(io.LimitReader(resp.Body, maxAvatarSize+1))
+}
+
+// checkAvatar checks that the content is a picture which AccountsService accepts.
+func checkAvatar(content []byte) error {
+	if len(content) > maxAvatarSize {
+		return fmt.Errorf("picture is larger than %d bytes", maxAvatarSize)
+	}
+	if contentType := http.DetectContentType(content); !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("content is not a picture but %s", contentType)
+	}
+	return nil
+}
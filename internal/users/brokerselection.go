@@ -0,0 +1,32 @@
+package users
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ubuntu/authd/internal/users/db"
+)
+
+// BrokerSelectionExpired returns whether the user must select their broker again, because they selected it longer ago
+// than the configured expiry. The selections made before their time was recorded never expire.
+func (m *Manager) BrokerSelectionExpired(username string) (bool, error) {
+	if m.config.BrokerSelectionExpiry <= 0 {
+		return false, nil
+	}
+
+	selectedAt, err := m.db.BrokerSelectedAt(username)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return time.Since(selectedAt) > m.config.BrokerSelectionExpiry, nil
+}
+
+// ResetBrokerForUser forgets the broker the user last authenticated with, so that they select one again on their next
+// login.
+func (m *Manager) ResetBrokerForUser(username string) error {
+	return m.db.ResetBrokerForUser(username)
+}
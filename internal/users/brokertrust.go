@@ -0,0 +1,83 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/ubuntu/authd/internal/users/types"
+	"github.com/ubuntu/authd/log"
+)
+
+// ErrInvalidUserInfo is returned when the home directory or the shell of a user provided by a broker can't be used in
+// the user entries.
+var ErrInvalidUserInfo = errors.New("invalid user information")
+
+// BrokerTrustConfig relaxes the validation of the users provided by a broker.
+type BrokerTrustConfig struct {
+	// AllowedShells are the login shells the users of the broker can have in addition to the ones listed in
+	// /etc/shells.
+	AllowedShells []string `mapstructure:"allowed_shells"`
+	// AnyShell allows the users of the broker to have any login shell given as an absolute path.
+	AnyShell bool `mapstructure:"any_shell"`
+}
+
+// sanitizeUserInfo cleans the home directory and the shell of the user provided by the broker. It returns an error,
+// and records it for auditing, if they would inject malformed passwd entries or if the shell is not listed in the
+// shells file nor allowed for the broker.
+func (m *Manager) sanitizeUserInfo(u *types.UserInfo, brokerName string) error {
+	dir, err := SanitizePath(u.Dir)
+	if err != nil {
+		return refuseUserInfo(u.Name, brokerName, fmt.Sprintf("home directory %q %v", u.Dir, err))
+	}
+	shell, err := SanitizePath(u.Shell)
+	if err != nil {
+		return refuseUserInfo(u.Name, brokerName, fmt.Sprintf("shell %q %v", u.Shell, err))
+	}
+
+	trust := m.brokerTrust(brokerName)
+	if !trust.AnyShell && !slices.Contains(trust.AllowedShells, shell) {
+		listed, err := isListedShell(m.shellsFile, shell)
+		if err != nil {
+			return err
+		}
+		if !listed {
+			return refuseUserInfo(u.Name, brokerName, fmt.Sprintf("shell %q is not listed in %s", shell, m.shellsFile))
+		}
+	}
+
+	u.Dir, u.Shell = dir, shell
+	return nil
+}
+
+// brokerTrust returns the trust configured for the broker, which is empty if none was configured.
+func (m *Manager) brokerTrust(brokerName string) BrokerTrustConfig {
+	for name, trust := range m.config.BrokerTrust {
+		if strings.EqualFold(name, brokerName) {
+			return trust
+		}
+	}
+	return BrokerTrustConfig{}
+}
+
+// SanitizePath returns the cleaned path, or an error if it's not absolute or contains characters which can't be in a
+// passwd entry.
+func SanitizePath(path string) (string, error) {
+	if strings.ContainsRune(path, ':') || strings.ContainsFunc(path, unicode.IsControl) {
+		return "", errors.New("contains ':' or a control character")
+	}
+	if !filepath.IsAbs(path) {
+		return "", errors.New("is not an absolute path")
+	}
+	return filepath.Clean(path), nil
+}
+
+// refuseUserInfo records for auditing that the user provided by the broker was refused, and returns the error.
+func refuseUserInfo(name, brokerName, reason string) error {
+	log.Noticef(context.Background(), "Audit: refused user %q provided by broker %q: %s", name, brokerName, reason)
+	return fmt.Errorf("%w: %s", ErrInvalidUserInfo, reason)
+}
@@ -0,0 +1,81 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"os/user"
+	"strconv"
+
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/decorate"
+)
+
+// ErrInvalidUIDChange is returned when the UID of a user can't be changed to the requested one.
+var ErrInvalidUIDChange = errors.New("invalid UID change")
+
+// ChangeUID changes the UID of the user of authd with the given UID, for example when the IDs of the users become the
+// deterministic ones on an existing machine. It returns the name of the user.
+//
+// Changing the UID again after a successful change is not an error, so that the migration of the files of the user
+// can be resumed.
+func (m *Manager) ChangeUID(oldUID, newUID uint32) (name string, err error) {
+	defer decorate.OnError(&err, "could not change UID %d to %d", oldUID, newUID)
+
+	m.updateUserMu.Lock()
+	defer m.updateUserMu.Unlock()
+
+	if oldUID == newUID {
+		return "", fmt.Errorf("%w: the UIDs are the same", ErrInvalidUIDChange)
+	}
+
+	u, err := m.db.UserByID(oldUID)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return m.changedUIDOwner(oldUID, newUID)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := m.checkReservedUID(u.Name, newUID, ""); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidUIDChange, err)
+	}
+	if other, err := m.db.UserByID(newUID); err == nil {
+		return "", fmt.Errorf("%w: UID %d is already used by the authd user %q", ErrInvalidUIDChange, newUID, other.Name)
+	} else if !errors.Is(err, db.NoDataFoundError{}) {
+		return "", err
+	}
+	if d, err := m.db.DeletedUserByID(newUID); err == nil && d.Name != u.Name {
+		return "", fmt.Errorf("%w: UID %d belonged to the deleted user %q", ErrInvalidUIDChange, newUID, d.Name)
+	} else if err != nil && !errors.Is(err, db.NoDataFoundError{}) {
+		return "", err
+	}
+	if other, err := user.LookupId(strconv.FormatUint(uint64(newUID), 10)); err == nil {
+		return "", fmt.Errorf("%w: UID %d is already used by the user %q", ErrInvalidUIDChange, newUID, other.Username)
+	}
+
+	if err := m.db.ChangeUserUID(oldUID, newUID); err != nil {
+		return "", err
+	}
+	m.updateNSSSnapshot()
+	return u.Name, nil
+}
+
+// changedUIDOwner returns the name of the user whose UID was already changed from oldUID to newUID.
+func (m *Manager) changedUIDOwner(oldUID, newUID uint32) (string, error) {
+	d, err := m.db.DeletedUserByID(oldUID)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return "", NoDataFoundError{}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	u, err := m.db.UserByID(newUID)
+	if errors.Is(err, db.NoDataFoundError{}) || (err == nil && u.Name != d.Name) {
+		return "", NoDataFoundError{}
+	}
+	if err != nil {
+		return "", err
+	}
+	return u.Name, nil
+}
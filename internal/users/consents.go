@@ -0,0 +1,42 @@
+package users
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/decorate"
+)
+
+// ConsentAccepted returns whether the user already accepted the consent message. It returns false if the user accepted
+// another message since changed, or if the user never logged in.
+func (m *Manager) ConsentAccepted(username, message string) (bool, error) {
+	c, err := m.db.ConsentForUser(username)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return c.MessageHash == consentMessageHash(message), nil
+}
+
+// RecordConsent records that the user accepted the consent message now, so that it's not shown to them again until it
+// changes.
+func (m *Manager) RecordConsent(username, message string) (err error) {
+	defer decorate.OnError(&err, "could not record the consent of user %q", username)
+
+	err = m.db.SetConsentForUser(username, consentMessageHash(message), time.Now())
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return NoDataFoundError{}
+	}
+	return err
+}
+
+// consentMessageHash returns the hash identifying the consent message, which is stored instead of the message itself.
+func consentMessageHash(message string) string {
+	h := sha256.Sum256([]byte(message))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// AdoptedUserRow represents a row in the adopted_users table, which maps a user of a broker to the local user whose
+// identity it takes over.
+type AdoptedUserRow struct {
+	Name      string
+	LocalName string `yaml:"local_name"`
+	UID       uint32
+	GID       uint32
+	Dir       string
+}
+
+const adoptedUserColumns = "name, local_name, uid, gid, dir"
+
+// AdoptedUserByName returns the local user adopted by the user of the broker with this name, or an error if the
+// database is corrupted or no local user was adopted by it.
+func (m *Manager) AdoptedUserByName(name string) (AdoptedUserRow, error) {
+	row := m.db.QueryRow(fmt.Sprintf(`SELECT %s FROM adopted_users WHERE name = ?`, adoptedUserColumns), name)
+	return scanAdoptedUser(row, name)
+}
+
+// AdoptedUserByID returns the adoption of the local user with this UID, or an error if the database is corrupted or
+// the local user was not adopted.
+func (m *Manager) AdoptedUserByID(uid uint32) (AdoptedUserRow, error) {
+	row := m.db.QueryRow(fmt.Sprintf(`SELECT %s FROM adopted_users WHERE uid = ?`, adoptedUserColumns), uid)
+	return scanAdoptedUser(row, strconv.FormatUint(uint64(uid), 10))
+}
+
+func scanAdoptedUser(row *sql.Row, key string) (AdoptedUserRow, error) {
+	var a AdoptedUserRow
+	err := row.Scan(&a.Name, &a.LocalName, &a.UID, &a.GID, &a.Dir)
+	if errors.Is(err, sql.ErrNoRows) {
+		return AdoptedUserRow{}, NoDataFoundError{key: key, table: "adopted_users"}
+	}
+	if err != nil {
+		return AdoptedUserRow{}, fmt.Errorf("query error: %w", err)
+	}
+	return a, nil
+}
+
+// AddAdoptedUser records that the user of the broker takes over the identity of the local user.
+func (m *Manager) AddAdoptedUser(a AdoptedUserRow) error {
+	query := fmt.Sprintf(`INSERT INTO adopted_users (%s) VALUES (?, ?, ?, ?, ?)`, adoptedUserColumns)
+	if _, err := m.db.Exec(query, a.Name, a.LocalName, a.UID, a.GID, a.Dir); err != nil {
+		return fmt.Errorf("failed to add adopted user: %w", err)
+	}
+	return nil
+}
+
+// allAdoptedUsers returns all rows of the adopted_users table.
+func allAdoptedUsers(db queryable) ([]AdoptedUserRow, error) {
+	rows, err := db.Query(fmt.Sprintf(`SELECT %s FROM adopted_users`, adoptedUserColumns))
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	var adopted []AdoptedUserRow
+	for rows.Next() {
+		var a AdoptedUserRow
+		if err := rows.Scan(&a.Name, &a.LocalName, &a.UID, &a.GID, &a.Dir); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		adopted = append(adopted, a)
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return adopted, nil
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// userToAuthModeRow represents a row in the users_to_auth_modes table.
+type userToAuthModeRow struct {
+	UID      uint32
+	BrokerID string `yaml:"broker_id"`
+	AuthMode string `yaml:"auth_mode"`
+}
+
+// LastAuthModeForUser returns the authentication mode the user last successfully authenticated with using the given
+// broker, or an error if the database is corrupted or no entry was found.
+func (m *Manager) LastAuthModeForUser(username, brokerID string) (string, error) {
+	query := `SELECT auth_mode FROM users_to_auth_modes
+		JOIN users ON users_to_auth_modes.uid = users.uid
+		WHERE users.name = ? AND users_to_auth_modes.broker_id = ?`
+	row := m.db.QueryRow(query, username, brokerID)
+
+	var authMode string
+	err := row.Scan(&authMode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", NoDataFoundError{key: username, table: "users_to_auth_modes"}
+	}
+	if err != nil {
+		return "", fmt.Errorf("query error: %w", err)
+	}
+
+	return authMode, nil
+}
+
+// UpdateLastAuthModeForUser memorizes the authentication mode the user successfully authenticated with using the given
+// broker.
+func (m *Manager) UpdateLastAuthModeForUser(username, brokerID, authMode string) error {
+	return m.writes.write(func(tx *sql.Tx) error {
+		query := `INSERT INTO users_to_auth_modes (uid, broker_id, auth_mode)
+			SELECT uid, ?, ? FROM users WHERE name = ?
+			ON CONFLICT (uid, broker_id) DO UPDATE SET auth_mode = excluded.auth_mode`
+		res, err := tx.Exec(query, brokerID, authMode, username)
+		if err != nil {
+			return fmt.Errorf("failed to update authentication mode for user: %w", err)
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return NoDataFoundError{table: "users", key: username}
+		}
+
+		return nil
+	})
+}
+
+// allUserAuthModes returns all rows of the users_to_auth_modes table.
+func allUserAuthModes(db queryable) ([]userToAuthModeRow, error) {
+	rows, err := db.Query(`SELECT uid, broker_id, auth_mode FROM users_to_auth_modes`)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	var authModes []userToAuthModeRow
+	for rows.Next() {
+		var r userToAuthModeRow
+		if err := rows.Scan(&r.UID, &r.BrokerID, &r.AuthMode); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		authModes = append(authModes, r)
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return authModes, nil
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// AvatarRow represents a row in the avatars table.
+type AvatarRow struct {
+	UID     uint32
+	URL     string
+	Content []byte
+}
+
+// AvatarForUser returns the picture of the user, or an error if the database is corrupted or the user has no picture.
+func (m *Manager) AvatarForUser(username string) (AvatarRow, error) {
+	query := `SELECT avatars.uid, url, content FROM avatars
+		JOIN users ON avatars.uid = users.uid
+		WHERE users.name = ?`
+	row := m.db.QueryRow(query, username)
+
+	var a AvatarRow
+	err := row.Scan(&a.UID, &a.URL, &a.Content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return AvatarRow{}, NoDataFoundError{key: username, table: "avatars"}
+	}
+	if err != nil {
+		return AvatarRow{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return a, nil
+}
+
+// SetAvatarForUser stores the picture of the user and the address it was downloaded from, if any, replacing any
+// previous one.
+func (m *Manager) SetAvatarForUser(username, url string, content []byte) error {
+	query := `INSERT INTO avatars (uid, url, content)
+		SELECT uid, ?, ? FROM users WHERE name = ?
+		ON CONFLICT (uid) DO UPDATE SET url = excluded.url, content = excluded.content`
+	res, err := m.db.Exec(query, url, content, username)
+	if err != nil {
+		return fmt.Errorf("failed to set avatar: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return NoDataFoundError{key: username, table: "users"}
+	}
+	return nil
+}
+
+// allAvatars returns all rows of the avatars table.
+func allAvatars(db queryable) ([]AvatarRow, error) {
+	rows, err := db.Query(`SELECT uid, url, content FROM avatars`)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	var avatars []AvatarRow
+	for rows.Next() {
+		var a AvatarRow
+		if err := rows.Scan(&a.UID, &a.URL, &a.Content); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		avatars = append(avatars, a)
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return avatars, nil
+}
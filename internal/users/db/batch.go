@@ -0,0 +1,141 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// maxBatchSize is the largest number of writes committed in a single transaction, which bounds how long a write waits
+// for the batches queued before it.
+const maxBatchSize = 64
+
+// errClosed is returned for the writes made after the database was closed.
+var errClosed = errors.New("database is closed")
+
+// batchedWrite is a write waiting to be committed with the other ones of its batch.
+type batchedWrite struct {
+	fn   func(tx *sql.Tx) error
+	done chan error
+}
+
+// writeBatcher coalesces the writes made at the same time, for example when a whole classroom logs in at once, in a
+// single transaction, so that the database file is synced once per batch instead of once per login.
+//
+// A write made while no transaction is in progress is committed right away. The ones made during a transaction are
+// queued and committed together as soon as it ends, so a write never waits for more than the transaction in progress
+// and the one of its batch.
+//
+// A write only returns once its batch is committed, so the callers get the same durability as with their own
+// transaction, and the journal of the updates in progress keeps covering the updates interrupted by a crash. Each write
+// runs in its own savepoint, so that a failing write doesn't roll back the other ones of its batch.
+type writeBatcher struct {
+	db      *sql.DB
+	maxSize int
+
+	pending  []batchedWrite
+	flushing bool
+	closed   bool
+	mu       sync.Mutex
+
+	// flushMu is held while batches are committed.
+	flushMu sync.Mutex
+	commits atomic.Uint64
+}
+
+func newWriteBatcher(db *sql.DB) *writeBatcher {
+	return &writeBatcher{db: db, maxSize: maxBatchSize}
+}
+
+// write runs fn in the transaction of the next batch, and returns its error or the one of the transaction.
+func (b *writeBatcher) write(fn func(tx *sql.Tx) error) error {
+	w := batchedWrite{fn: fn, done: make(chan error, 1)}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return errClosed
+	}
+	b.pending = append(b.pending, w)
+	// The first write made while no batch is being committed commits the queued ones until there are none left.
+	lead := !b.flushing
+	b.flushing = true
+	b.mu.Unlock()
+
+	if lead {
+		b.flush()
+	}
+	return <-w.done
+}
+
+// flush commits the pending writes by batches, until there are none left.
+func (b *writeBatcher) flush() {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	for {
+		b.mu.Lock()
+		n := min(len(b.pending), b.maxSize)
+		if n == 0 {
+			b.flushing = false
+			b.mu.Unlock()
+			return
+		}
+		batch := b.pending[:n:n]
+		b.pending = b.pending[n:]
+		b.mu.Unlock()
+
+		errs, err := b.commit(batch)
+		for i, w := range batch {
+			if err != nil {
+				w.done <- err
+				continue
+			}
+			w.done <- errs[i]
+		}
+	}
+}
+
+// commit runs the writes of the batch in a transaction, each in its own savepoint, and returns their errors, or the
+// error of the transaction if it failed as a whole.
+func (b *writeBatcher) commit(batch []batchedWrite) (errs []error, err error) {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		err = commitOrRollBackTransaction(err, tx)
+		if err == nil {
+			b.commits.Add(1)
+		}
+	}()
+
+	for _, w := range batch {
+		if _, err := tx.Exec(`SAVEPOINT batched_write`); err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		writeErr := w.fn(tx)
+		if writeErr != nil {
+			if _, err := tx.Exec(`ROLLBACK TO batched_write`); err != nil {
+				return nil, fmt.Errorf("failed to roll back to savepoint: %w", err)
+			}
+		}
+		if _, err := tx.Exec(`RELEASE batched_write`); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+		errs = append(errs, writeErr)
+	}
+	return errs, nil
+}
+
+// close refuses the next writes and waits for the pending ones to be committed.
+func (b *writeBatcher) close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+}
@@ -1,4 +1,7 @@
 // Package bbolt handles transaction with the deprecated bbolt database
+//
+// Deprecated: the bbolt database is only read to migrate its data to the SQLite one, which is the single source of
+// truth of the users and groups. Its types must not be used outside of the migration.
 package bbolt
 
 import (
@@ -11,6 +14,7 @@ import (
 	"sync"
 	"syscall"
 
+	"github.com/ubuntu/authd/internal/authderrors"
 	"github.com/ubuntu/decorate"
 	"go.etcd.io/bbolt"
 )
@@ -47,7 +51,9 @@ type Database struct {
 	mu sync.RWMutex
 }
 
-// UserDB is the public type that is shared to external packages.
+// UserDB is a user as stored in the bbolt database.
+//
+// Deprecated: use types.UserEntry and types.ShadowEntry, or db.UserRow.
 type UserDB struct {
 	Name  string
 	UID   uint32
@@ -66,6 +72,8 @@ type UserDB struct {
 }
 
 // GroupDB is the struct stored in json format in the bucket.
+//
+// Deprecated: use types.GroupEntry, or db.GroupRow.
 type GroupDB struct {
 	Name  string
 	GID   uint32
@@ -265,3 +273,6 @@ func (err NoDataFoundError) Error() string {
 
 // Is makes this error insensitive to the key and bucket name.
 func (NoDataFoundError) Is(target error) bool { return target == NoDataFoundError{} }
+
+// Code classifies this error as a not found error.
+func (NoDataFoundError) Code() authderrors.Code { return authderrors.NotFound }
@@ -7,24 +7,6 @@ import (
 	"go.etcd.io/bbolt"
 )
 
-// NewUserDB creates a new UserDB.
-func NewUserDB(name string, uid, gid uint32, gecos, dir, shell string) UserDB {
-	return UserDB{
-		Name:           name,
-		UID:            uid,
-		GID:            gid,
-		Gecos:          gecos,
-		Dir:            dir,
-		Shell:          shell,
-		LastPwdChange:  -1,
-		MaxPwdAge:      -1,
-		PwdWarnPeriod:  -1,
-		PwdInactivity:  -1,
-		MinPwdAge:      -1,
-		ExpirationDate: -1,
-	}
-}
-
 // UserByID returns a user matching this uid or an error if the database is corrupted or no entry was found.
 func (c *Database) UserByID(uid uint32) (UserDB, error) {
 	return getUser(c, userByIDBucketName, uid)
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BrokerSelectedAt returns the time at which the user last selected their broker, or an error if the database is
+// corrupted or the time was never recorded.
+func (m *Manager) BrokerSelectedAt(username string) (time.Time, error) {
+	query := `SELECT selected_at FROM broker_selections
+		JOIN users ON broker_selections.uid = users.uid
+		WHERE users.name = ?`
+	row := m.db.QueryRow(query, username)
+
+	var selectedAt int64
+	err := row.Scan(&selectedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, NoDataFoundError{key: username, table: "broker_selections"}
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return time.Unix(selectedAt, 0), nil
+}
+
+// ResetBrokerForUser forgets the broker the user last authenticated with, so that they select one again on their next
+// login.
+func (m *Manager) ResetBrokerForUser(username string) error {
+	return m.writes.write(func(tx *sql.Tx) error {
+		query := `UPDATE users SET broker_id = '' WHERE name = ?`
+		res, err := tx.Exec(query, username)
+		if err != nil {
+			return fmt.Errorf("failed to reset broker for user: %w", err)
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return NoDataFoundError{table: "users", key: username}
+		}
+
+		query = `DELETE FROM broker_selections WHERE uid = (SELECT uid FROM users WHERE name = ?)`
+		if _, err := tx.Exec(query, username); err != nil {
+			return fmt.Errorf("failed to remove broker selection time: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// brokerSelectionRow represents a row in the broker_selections table.
+type brokerSelectionRow struct {
+	UID        uint32
+	SelectedAt int64 `yaml:"selected_at"`
+}
+
+// allBrokerSelections returns all rows of the broker_selections table.
+func allBrokerSelections(db queryable) ([]brokerSelectionRow, error) {
+	rows, err := db.Query(`SELECT uid, selected_at FROM broker_selections`)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	var selections []brokerSelectionRow
+	for rows.Next() {
+		var s brokerSelectionRow
+		if err := rows.Scan(&s.UID, &s.SelectedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		selections = append(selections, s)
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return selections, nil
+}
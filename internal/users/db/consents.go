@@ -0,0 +1,80 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConsentRow represents a row in the consents table.
+type ConsentRow struct {
+	UID         uint32
+	MessageHash string `yaml:"message_hash"`
+	AcceptedAt  int64  `yaml:"accepted_at"`
+}
+
+// ConsentForUser returns the consent message last accepted by the user, or an error if the database is corrupted or
+// the user never accepted any.
+func (m *Manager) ConsentForUser(username string) (ConsentRow, error) {
+	query := `SELECT consents.uid, message_hash, accepted_at FROM consents
+		JOIN users ON consents.uid = users.uid
+		WHERE users.name = ?`
+	row := m.db.QueryRow(query, username)
+
+	var c ConsentRow
+	err := row.Scan(&c.UID, &c.MessageHash, &c.AcceptedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ConsentRow{}, NoDataFoundError{key: username, table: "consents"}
+	}
+	if err != nil {
+		return ConsentRow{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return c, nil
+}
+
+// SetConsentForUser records that the user accepted the consent message with the given hash at the given time,
+// replacing any previous one.
+func (m *Manager) SetConsentForUser(username, messageHash string, acceptedAt time.Time) error {
+	query := `INSERT INTO consents (uid, message_hash, accepted_at)
+		SELECT uid, ?, ? FROM users WHERE name = ?
+		ON CONFLICT (uid) DO UPDATE SET message_hash = excluded.message_hash, accepted_at = excluded.accepted_at`
+	res, err := m.db.Exec(query, messageHash, acceptedAt.Unix(), username)
+	if err != nil {
+		return fmt.Errorf("failed to set consent: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return NoDataFoundError{key: username, table: "users"}
+	}
+	return nil
+}
+
+// allConsents returns all rows of the consents table.
+func allConsents(db queryable) ([]ConsentRow, error) {
+	rows, err := db.Query(`SELECT uid, message_hash, accepted_at FROM consents`)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	var consents []ConsentRow
+	for rows.Next() {
+		var c ConsentRow
+		if err := rows.Scan(&c.UID, &c.MessageHash, &c.AcceptedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		consents = append(consents, c)
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return consents, nil
+}
@@ -12,8 +12,8 @@ import (
 	"sync"
 	"syscall"
 
-	// sqlite3 driver.
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
+	"github.com/ubuntu/authd/internal/authderrors"
 	"github.com/ubuntu/authd/internal/fileutils"
 	"github.com/ubuntu/authd/internal/users/db/bbolt"
 	"github.com/ubuntu/authd/log"
@@ -23,6 +23,25 @@ var (
 	filename = "authd.sqlite3"
 	//go:embed sql/create_schema.sql
 	createSchema string
+	// Tables added after the initial schema, which must also be created in existing databases.
+	//go:embed sql/create_users_to_auth_modes.sql
+	createUsersToAuthModesTable string
+	//go:embed sql/create_local_pins.sql
+	createLocalPINsTable string
+	//go:embed sql/create_avatars.sql
+	createAvatarsTable string
+	//go:embed sql/create_user_overrides.sql
+	createUserOverridesTable string
+	//go:embed sql/create_consents.sql
+	createConsentsTable string
+	//go:embed sql/create_deleted_users.sql
+	createDeletedUsersTable string
+	//go:embed sql/create_user_roles.sql
+	createUserRolesTable string
+	//go:embed sql/create_adopted_users.sql
+	createAdoptedUsersTable string
+	//go:embed sql/create_broker_selections.sql
+	createBrokerSelectionsTable string
 )
 
 // Manager is an abstraction to interact with the database.
@@ -30,6 +49,8 @@ type Manager struct {
 	db   *sql.DB
 	path string
 	mu   sync.RWMutex
+	// writes coalesces the writes made on each login.
+	writes *writeBatcher
 }
 
 // queryable is an interface to execute SQL queries. Both sql.DB and sql.Tx implement this interface.
@@ -40,7 +61,9 @@ type queryable interface {
 }
 
 // New creates a new database manager by creating or opening the underlying database.
-func New(dbDir string) (*Manager, error) {
+func New(dbDir string) (m *Manager, err error) {
+	defer func() { err = classifyCorruption(err) }()
+
 	dbPath := filepath.Join(dbDir, filename)
 
 	exists, err := fileutils.FileExists(dbPath)
@@ -77,7 +100,35 @@ func New(dbDir string) (*Manager, error) {
 		}
 	}
 
-	return &Manager{db: db, path: dbPath, mu: sync.RWMutex{}}, nil
+	if _, err = db.Exec(createUsersToAuthModesTable); err != nil {
+		return nil, fmt.Errorf("failed to create authentication modes table: %w", err)
+	}
+	if _, err = db.Exec(createLocalPINsTable); err != nil {
+		return nil, fmt.Errorf("failed to create local PINs table: %w", err)
+	}
+	if _, err = db.Exec(createAvatarsTable); err != nil {
+		return nil, fmt.Errorf("failed to create avatars table: %w", err)
+	}
+	if _, err = db.Exec(createUserOverridesTable); err != nil {
+		return nil, fmt.Errorf("failed to create user overrides table: %w", err)
+	}
+	if _, err = db.Exec(createConsentsTable); err != nil {
+		return nil, fmt.Errorf("failed to create consents table: %w", err)
+	}
+	if _, err = db.Exec(createDeletedUsersTable); err != nil {
+		return nil, fmt.Errorf("failed to create deleted users table: %w", err)
+	}
+	if _, err = db.Exec(createAdoptedUsersTable); err != nil {
+		return nil, fmt.Errorf("failed to create adopted users table: %w", err)
+	}
+	if _, err = db.Exec(createUserRolesTable); err != nil {
+		return nil, fmt.Errorf("failed to create user roles table: %w", err)
+	}
+	if _, err = db.Exec(createBrokerSelectionsTable); err != nil {
+		return nil, fmt.Errorf("failed to create broker selections table: %w", err)
+	}
+
+	return &Manager{db: db, path: dbPath, mu: sync.RWMutex{}, writes: newWriteBatcher(db)}, nil
 }
 
 // checkOwnerAndPermissions checks if the database file has secure owner and permissions.
@@ -159,15 +210,7 @@ func (m *Manager) migrateData(dbDir string) (err error) {
 			return err
 		}
 
-		user := UserRow{
-			Name:     u.Name,
-			UID:      u.UID,
-			GID:      u.GID,
-			Gecos:    u.Gecos,
-			Dir:      u.Dir,
-			Shell:    u.Shell,
-			BrokerID: brokerID,
-		}
+		user := userRowFromBbolt(u, brokerID)
 
 		log.Debugf(context.Background(), "Migrating user %v", user.Name)
 		if err := insertUser(tx, user); err != nil {
@@ -182,7 +225,7 @@ func (m *Manager) migrateData(dbDir string) (err error) {
 	}
 
 	for _, g := range bboltGroups {
-		group := GroupRow{Name: g.Name, GID: g.GID, UGID: g.UGID}
+		group := groupRowFromBbolt(g)
 
 		log.Debugf(context.Background(), "Migrating group %v", group.Name)
 		if err := insertGroup(tx, group); err != nil {
@@ -228,12 +271,38 @@ func (m *Manager) migrateData(dbDir string) (err error) {
 	return nil
 }
 
+// userRowFromBbolt returns the UserRow of a user of the deprecated bbolt database. Its shadow fields are dropped, as
+// they were never set to anything else than the defaults.
+func userRowFromBbolt(u bbolt.UserDB, brokerID string) UserRow {
+	user := NewUserRow(u.Name, u.UID, u.GID, u.Gecos, u.Dir, u.Shell)
+	user.BrokerID = brokerID
+	return user
+}
+
+// groupRowFromBbolt returns the GroupRow of a group of the deprecated bbolt database. Its members are migrated
+// separately.
+func groupRowFromBbolt(g bbolt.GroupDB) GroupRow {
+	return NewGroupRow(g.Name, g.GID, g.UGID)
+}
+
 // Close closes the db and signal the monitoring goroutine to stop.
 func (m *Manager) Close() error {
 	log.Debugf(context.Background(), "Closing database")
+	m.writes.close()
 	return m.db.Close()
 }
 
+// CheckHealth returns an error if the database can't be read. It blocks as long as another connection holds a lock
+// preventing the reads, for instance during a write transaction which doesn't complete.
+func (m *Manager) CheckHealth() error {
+	var n int
+	err := m.db.QueryRow("SELECT 1 FROM users LIMIT 1").Scan(&n)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("database is not healthy: %w", err)
+	}
+	return nil
+}
+
 // Filename returns the name of the database file.
 func Filename() string {
 	return filename
@@ -255,8 +324,22 @@ func (err NoDataFoundError) Error() string {
 	return fmt.Sprintf("no result matching %v in %v", err.key, err.table)
 }
 
-// Is makes this error insensitive to the key and table names.
-func (NoDataFoundError) Is(target error) bool { return target == NoDataFoundError{} }
+// Is makes this error insensitive to the key and table names, and makes it match authderrors.ErrNotFound.
+func (NoDataFoundError) Is(target error) bool {
+	return target == NoDataFoundError{} || target == authderrors.ErrNotFound
+}
+
+// Code classifies this error as a not found error.
+func (NoDataFoundError) Code() authderrors.Code { return authderrors.NotFound }
+
+// classifyCorruption attaches the errors caused by a corrupted database file to authderrors.ErrCorrupted.
+func classifyCorruption(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrCorrupt || sqliteErr.Code == sqlite3.ErrNotADB) {
+		return authderrors.ErrCorrupted.Wrap(err)
+	}
+	return err
+}
 
 func closeRows(rows *sql.Rows) {
 	if err := rows.Close(); err != nil {
@@ -2,13 +2,19 @@ package db_test
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"io/fs"
 	"os"
 	"os/user"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/authderrors"
 	"github.com/ubuntu/authd/internal/testutils/golden"
 	"github.com/ubuntu/authd/internal/users/db"
 	"github.com/ubuntu/authd/log"
@@ -25,13 +31,14 @@ func TestNew(t *testing.T) {
 		perm            *fs.FileMode
 		corruptedDbFile bool
 
-		wantErr bool
+		wantErr     bool
+		wantErrType error
 	}{
 		"New_without_any_initialized_database": {},
 		"New_with_already_existing_database":   {dbFile: "multiple_users_and_groups"},
 
 		"Error_on_non_existent_db_dir":                   {dbFile: "-", wantErr: true},
-		"Error_on_corrupted_db_file":                     {corruptedDbFile: true, wantErr: true},
+		"Error_on_corrupted_db_file":                     {corruptedDbFile: true, wantErr: true, wantErrType: authderrors.ErrCorrupted},
 		"Error_on_insecure_permissions_on_database_file": {dbFile: "multiple_users_and_groups", perm: &perm0666, wantErr: true},
 		"Error_on_unreadable_database_file":              {dbFile: "multiple_users_and_groups", perm: &perm0000, wantErr: true},
 	}
@@ -72,6 +79,9 @@ func TestNew(t *testing.T) {
 			m, err := db.New(dbDir)
 			if tc.wantErr {
 				require.Error(t, err, "New should return an error but didn't")
+				if tc.wantErrType != nil {
+					require.ErrorIs(t, err, tc.wantErrType, "New should return the expected error")
+				}
 				return
 			}
 			require.NoError(t, err)
@@ -154,6 +164,7 @@ func TestUpdateUserEntry(t *testing.T) {
 		groupCases  []string
 		localGroups []string
 		dbFile      string
+		cancelCtx   bool
 
 		wantErr bool
 	}{
@@ -187,6 +198,7 @@ func TestUpdateUserEntry(t *testing.T) {
 		"Error_when_group_has_same_name_and_ugid_but_different_gid": {groupCases: []string{"group1-different-gid"}, dbFile: "one_user_and_group", wantErr: true},
 		"Error_when_group_has_same_name_and_gid_but_different_ugid": {groupCases: []string{"group1-different-ugid"}, dbFile: "one_user_and_group", wantErr: true},
 		"Error_when_group_has_same_name_but_different_gid_and_ugid": {groupCases: []string{"group1-different-gid-and-ugid"}, dbFile: "one_user_and_group", wantErr: true},
+		"Error_when_context_is_cancelled":                           {cancelCtx: true, wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -208,7 +220,13 @@ func TestUpdateUserEntry(t *testing.T) {
 			}
 			user.GID = groups[0].GID
 
-			err := c.UpdateUserEntry(user, groups, tc.localGroups)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelCtx {
+				cancel()
+			}
+
+			err := c.UpdateUserEntry(ctx, user, groups, tc.localGroups)
 			if err != nil {
 				log.Errorf(context.Background(), "UpdateUserEntry error: %v", err)
 			}
@@ -299,6 +317,188 @@ func TestAllUsers(t *testing.T) {
 	}
 }
 
+func TestAllUsersStream(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		dbFile    string
+		stopAfter int
+
+		wantUsers int
+	}{
+		"Get_no_user":        {},
+		"Get_one_user":       {dbFile: "one_user_and_group", wantUsers: 1},
+		"Get_multiple_users": {dbFile: "multiple_users_and_groups", wantUsers: 4},
+
+		"Stop_iterating_when_requested": {dbFile: "multiple_users_and_groups", stopAfter: 2, wantUsers: 2},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := initDB(t, tc.dbFile)
+
+			var got []db.UserRow
+			for u, err := range c.AllUsersStream() {
+				require.NoError(t, err, "AllUsersStream should not yield an error")
+				got = append(got, u)
+				if len(got) == tc.stopAfter {
+					break
+				}
+			}
+			require.Len(t, got, tc.wantUsers, "AllUsersStream should yield the expected number of users")
+
+			if tc.stopAfter > 0 {
+				return
+			}
+			want, err := c.AllUsers()
+			require.NoError(t, err, "Setup: AllUsers should not return an error")
+			require.Equal(t, want, got, "AllUsersStream should yield the same users as AllUsers")
+		})
+	}
+}
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		dbFile          string
+		withAuthModes   bool
+		withLocalGroups bool
+		withLocalPIN    bool
+		withAvatar      bool
+		withOverride    bool
+		withConsent     bool
+		withRoles       bool
+	}{
+		"Dump_empty_database":              {},
+		"Dump_multiple_users_and_groups":   {dbFile: "multiple_users_and_groups"},
+		"Dump_authentication_modes":        {dbFile: "multiple_users_and_groups", withAuthModes: true},
+		"Dump_memberships_of_local_groups": {dbFile: "one_user_and_group", withLocalGroups: true},
+		"Dump_local_PINs":                  {dbFile: "multiple_users_and_groups", withLocalPIN: true},
+		"Dump_avatars":                     {dbFile: "multiple_users_and_groups", withAvatar: true},
+		"Dump_user_overrides":              {dbFile: "multiple_users_and_groups", withOverride: true},
+		"Dump_consents":                    {dbFile: "multiple_users_and_groups", withConsent: true},
+		"Dump_roles":                       {dbFile: "multiple_users_and_groups", withRoles: true},
+		"Dump_deleted_and_adopted_users_and_broker_selections": {dbFile: "multiple_users_with_history"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := initDB(t, tc.dbFile)
+			if tc.withAuthModes {
+				err := c.UpdateLastAuthModeForUser("user1", "broker-id", "password")
+				require.NoError(t, err, "Setup: could not update authentication mode")
+				err = c.UpdateLastAuthModeForUser("user1", "other-broker-id", "qrcode")
+				require.NoError(t, err, "Setup: could not update authentication mode")
+			}
+			if tc.withLocalGroups {
+				u, err := c.UserByName("user1")
+				require.NoError(t, err, "Setup: could not get user")
+				groups, err := c.UserGroups(u.UID)
+				require.NoError(t, err, "Setup: could not get user groups")
+				err = c.UpdateUserEntry(context.Background(), u, groups, []string{"localgroup2", "localgroup1"})
+				require.NoError(t, err, "Setup: could not update user")
+			}
+			if tc.withLocalPIN {
+				err := c.SetLocalPINForUser("user1", "pin-hash")
+				require.NoError(t, err, "Setup: could not set local PIN")
+				_, err = c.IncrementLocalPINFailedAttempts("user1")
+				require.NoError(t, err, "Setup: could not record failed attempt")
+			}
+			if tc.withAvatar {
+				err := c.SetAvatarForUser("user1", "https://example.com/user1.png", []byte("user1 picture"))
+				require.NoError(t, err, "Setup: could not set avatar")
+				err = c.SetAvatarForUser("user2", "", []byte("user2 picture"))
+				require.NoError(t, err, "Setup: could not set avatar")
+			}
+			if tc.withOverride {
+				err := c.SetUserOverrideForUser("user1", db.UserOverride{Shell: "/bin/zsh", LocalGroups: []string{"sudo", "docker"}})
+				require.NoError(t, err, "Setup: could not set user override")
+			}
+			if tc.withConsent {
+				err := c.SetConsentForUser("user1", "message-hash", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+				require.NoError(t, err, "Setup: could not set consent")
+			}
+			if tc.withRoles {
+				err := c.SetRolesForUser("user1", []string{"servers", "dev-laptops"})
+				require.NoError(t, err, "Setup: could not set roles")
+			}
+
+			got, err := c.Dump()
+			require.NoError(t, err, "Dump should not return an error")
+
+			gotJSON, err := json.MarshalIndent(got, "", "  ")
+			require.NoError(t, err, "Dump should be JSON serializable")
+			golden.CheckOrUpdate(t, string(gotJSON))
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		dbFile string
+		// invalidGID is added to the groups of the first user, if set.
+		invalidGID uint32
+
+		wantErr bool
+	}{
+		"Import_in_empty_database":         {},
+		"Import_replaces_existing_content": {dbFile: "one_user_and_group"},
+
+		"Error_and_keep_content_on_membership_of_unexisting_group": {dbFile: "one_user_and_group", invalidGID: 4242, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			src := initDB(t, "multiple_users_and_groups")
+			err := src.UpdateLastAuthModeForUser("user2", "broker-id", "password")
+			require.NoError(t, err, "Setup: could not update authentication mode")
+			err = src.SetLocalPINForUser("user2", "pin-hash")
+			require.NoError(t, err, "Setup: could not set local PIN")
+			err = src.SetAvatarForUser("user2", "", []byte("user2 picture"))
+			require.NoError(t, err, "Setup: could not set avatar")
+			err = src.SetUserOverrideForUser("user2", db.UserOverride{Dir: "/srv/user2", Gecos: "User Two", LocalGroups: []string{"sudo"}})
+			require.NoError(t, err, "Setup: could not set user override")
+			err = src.SetConsentForUser("user2", "message-hash", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+			require.NoError(t, err, "Setup: could not set consent")
+			err = src.SetRolesForUser("user2", []string{"dev-laptops"})
+			require.NoError(t, err, "Setup: could not set roles")
+			err = src.UpdateBrokerForUser("user2", "broker-id")
+			require.NoError(t, err, "Setup: could not update broker")
+			err = src.AddAdoptedUser(db.AdoptedUserRow{Name: "user2@example.com", LocalName: "user2", UID: 2222, GID: 22222, Dir: "/home/user2"})
+			require.NoError(t, err, "Setup: could not add adopted user")
+			err = src.DeleteUser(4444)
+			require.NoError(t, err, "Setup: could not delete user")
+			dump, err := src.Dump()
+			require.NoError(t, err, "Setup: could not dump the source database")
+			if tc.invalidGID != 0 {
+				dump.Users[0].Groups = append(dump.Users[0].Groups, tc.invalidGID)
+			}
+
+			c := initDB(t, tc.dbFile)
+			err = c.Import(dump)
+			if tc.wantErr {
+				require.Error(t, err, "Import should return an error, but did not")
+			} else {
+				require.NoError(t, err, "Import should not return an error, but did")
+
+				got, err := c.Dump()
+				require.NoError(t, err, "Dump should not return an error")
+				require.Equal(t, dump, got, "Dump of the imported database should be the same as the imported one")
+			}
+
+			got, err := db.Z_ForTests_DumpNormalizedYAML(c)
+			require.NoError(t, err, "Created database should be valid yaml content")
+			golden.CheckOrUpdate(t, got)
+		})
+	}
+}
+
 func TestGroupByID(t *testing.T) {
 	t.Parallel()
 
@@ -462,6 +662,347 @@ func TestUpdateBrokerForUser(t *testing.T) {
 	require.Error(t, err, "UpdateBrokerForUser for a nonexistent user should return an error")
 }
 
+func TestResetBrokerForUser(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	// No broker selected yet
+	_, err := c.BrokerSelectedAt("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "BrokerSelectedAt should return NoDataFoundError before any selection")
+
+	before := time.Now().Truncate(time.Second)
+	err = c.UpdateBrokerForUser("user1", "ExampleBrokerID")
+	require.NoError(t, err, "Setup: UpdateBrokerForUser should not return an error")
+	selectedAt, err := c.BrokerSelectedAt("user1")
+	require.NoError(t, err, "BrokerSelectedAt should not return an error after a selection")
+	require.False(t, selectedAt.Before(before), "BrokerSelectedAt should return the time of the selection")
+
+	err = c.ResetBrokerForUser("user1")
+	require.NoError(t, err, "ResetBrokerForUser for an existent user should not return an error")
+	u, err := c.UserByName("user1")
+	require.NoError(t, err, "UserByName should not return an error")
+	require.Empty(t, u.BrokerID, "ResetBrokerForUser should forget the broker of the user")
+	_, err = c.BrokerSelectedAt("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "BrokerSelectedAt should return NoDataFoundError after a reset")
+
+	// Error when resetting the broker of a nonexistent user
+	err = c.ResetBrokerForUser("nonexistent")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "ResetBrokerForUser for a nonexistent user should return NoDataFoundError")
+}
+
+func TestWriteBatching(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	// Hold the writes until all the logins are queued, so that they are committed in a single batch.
+	release := c.BlockWrites()
+	const logins = 20
+	var wg sync.WaitGroup
+	errs := make([]error, logins)
+	for i := range logins {
+		username := "user1"
+		if i%2 == 1 {
+			username = "nonexistent"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.UpdateLastAuthModeForUser(username, fmt.Sprintf("broker%d", i), "password")
+		}()
+	}
+	require.Eventually(t, func() bool { return c.PendingWrites() == logins }, 5*time.Second, 10*time.Millisecond,
+		"Setup: all the writes should be queued")
+	commits := c.Commits()
+	release()
+	wg.Wait()
+
+	require.Equal(t, commits+1, c.Commits(), "The queued writes should be committed in a single transaction")
+	for i, err := range errs {
+		if i%2 == 1 {
+			require.ErrorIs(t, err, db.NoDataFoundError{}, "The write for a nonexistent user should fail")
+			continue
+		}
+		require.NoError(t, err, "The write for an existent user should not fail")
+		got, err := c.LastAuthModeForUser("user1", fmt.Sprintf("broker%d", i))
+		require.NoError(t, err, "The write should be committed despite the failure of other writes of its batch")
+		require.Equal(t, "password", got, "The committed write should store the authentication mode")
+	}
+
+	// The writes are refused once the database is closed.
+	require.NoError(t, c.Close(), "Close should not return an error")
+	require.Error(t, c.UpdateBrokerForUser("user1", "ExampleBrokerID"), "Writes should fail once the database is closed")
+}
+
+func TestUpdateGecosForUser(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	// Update GECOS for existent user
+	err := c.UpdateGecosForUser("user1", "Jane Doe,42")
+	require.NoError(t, err, "UpdateGecosForUser for an existent user should not return an error")
+
+	u, err := c.UserByName("user1")
+	require.NoError(t, err, "UserByName should not return an error")
+	require.Equal(t, "Jane Doe,42", u.Gecos, "UpdateGecosForUser should update the GECOS of the user")
+
+	// Error when updating GECOS for nonexistent user
+	err = c.UpdateGecosForUser("nonexistent", "Jane Doe,42")
+	require.Error(t, err, "UpdateGecosForUser for a nonexistent user should return an error")
+}
+
+func TestUpdateLastAuthModeForUser(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	// No authentication mode stored yet
+	_, err := c.LastAuthModeForUser("user1", "ExampleBrokerID")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "LastAuthModeForUser should return NoDataFoundError before any update")
+	require.ErrorIs(t, err, authderrors.ErrNotFound, "NoDataFoundError should match the shared not found error")
+
+	// Store and replace the authentication mode for an existent user
+	err = c.UpdateLastAuthModeForUser("user1", "ExampleBrokerID", "password")
+	require.NoError(t, err, "UpdateLastAuthModeForUser for an existent user should not return an error")
+	err = c.UpdateLastAuthModeForUser("user1", "ExampleBrokerID", "totp")
+	require.NoError(t, err, "UpdateLastAuthModeForUser should replace the previous authentication mode")
+	err = c.UpdateLastAuthModeForUser("user1", "OtherBrokerID", "qrcode")
+	require.NoError(t, err, "UpdateLastAuthModeForUser for another broker should not return an error")
+
+	got, err := c.LastAuthModeForUser("user1", "ExampleBrokerID")
+	require.NoError(t, err, "LastAuthModeForUser should not return an error")
+	require.Equal(t, "totp", got, "LastAuthModeForUser should return the last stored authentication mode")
+	got, err = c.LastAuthModeForUser("user1", "OtherBrokerID")
+	require.NoError(t, err, "LastAuthModeForUser should not return an error")
+	require.Equal(t, "qrcode", got, "LastAuthModeForUser should return the authentication mode of the given broker")
+
+	// Error when updating authentication mode for nonexistent user
+	err = c.UpdateLastAuthModeForUser("nonexistent", "ExampleBrokerID", "password")
+	require.Error(t, err, "UpdateLastAuthModeForUser for a nonexistent user should return an error")
+
+	// Authentication modes are removed with the user
+	err = c.DeleteUser(1111)
+	require.NoError(t, err, "DeleteUser should not return an error")
+	_, err = c.LastAuthModeForUser("user1", "ExampleBrokerID")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "LastAuthModeForUser should return NoDataFoundError for a deleted user")
+}
+
+func TestAvatarForUser(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	// No avatar stored yet
+	_, err := c.AvatarForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "AvatarForUser should return NoDataFoundError before any avatar is stored")
+
+	// Store an avatar downloaded from an URL
+	err = c.SetAvatarForUser("user1", "https://example.com/user1.png", []byte("picture1"))
+	require.NoError(t, err, "SetAvatarForUser for an existent user should not return an error")
+	got, err := c.AvatarForUser("user1")
+	require.NoError(t, err, "AvatarForUser should not return an error")
+	require.Equal(t, db.AvatarRow{UID: 1111, URL: "https://example.com/user1.png", Content: []byte("picture1")}, got,
+		"AvatarForUser should return the stored avatar")
+
+	// Replacing the avatar with one provided by the broker
+	err = c.SetAvatarForUser("user1", "", []byte("picture2"))
+	require.NoError(t, err, "SetAvatarForUser should replace the previous avatar")
+	got, err = c.AvatarForUser("user1")
+	require.NoError(t, err, "AvatarForUser should not return an error")
+	require.Equal(t, db.AvatarRow{UID: 1111, Content: []byte("picture2")}, got, "SetAvatarForUser should replace the URL and the content")
+
+	// Error when storing an avatar for nonexistent user
+	err = c.SetAvatarForUser("nonexistent", "", []byte("picture"))
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "SetAvatarForUser for a nonexistent user should return NoDataFoundError")
+
+	// Avatars are removed with the user
+	err = c.DeleteUser(1111)
+	require.NoError(t, err, "DeleteUser should not return an error")
+	_, err = c.AvatarForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "AvatarForUser should return NoDataFoundError for a deleted user")
+}
+
+func TestUserOverrideForUser(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "multiple_users_and_groups")
+
+	// No override stored yet
+	_, err := c.UserOverrideForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "UserOverrideForUser should return NoDataFoundError before any override is stored")
+
+	// Store and replace the overrides
+	for _, o := range []db.UserOverride{
+		{Shell: "/bin/zsh", LocalGroups: []string{"sudo", "docker"}},
+		{Dir: "/srv/user1", Gecos: "User One"},
+	} {
+		err = c.SetUserOverrideForUser("user1", o)
+		require.NoError(t, err, "SetUserOverrideForUser for an existent user should not return an error")
+		got, err := c.UserOverrideForUser("user1")
+		require.NoError(t, err, "UserOverrideForUser should not return an error")
+		require.Equal(t, o, got, "UserOverrideForUser should return the stored override")
+	}
+	u, err := c.UserByName("user1")
+	require.NoError(t, err, "UserByName should not return an error")
+	require.Equal(t, "/home/user1", u.Dir, "SetUserOverrideForUser should not change the attributes provided by the broker")
+
+	err = c.SetUserOverrideForUser("user2", db.UserOverride{Shell: "/bin/zsh"})
+	require.NoError(t, err, "SetUserOverrideForUser for an existent user should not return an error")
+	all, err := c.AllUserOverrides()
+	require.NoError(t, err, "AllUserOverrides should not return an error")
+	require.Equal(t, map[string]db.UserOverride{
+		"user1": {Dir: "/srv/user1", Gecos: "User One"},
+		"user2": {Shell: "/bin/zsh"},
+	}, all, "AllUserOverrides should return the overrides of all the users")
+
+	// Storing an empty override removes it
+	err = c.SetUserOverrideForUser("user1", db.UserOverride{})
+	require.NoError(t, err, "SetUserOverrideForUser with an empty override should not return an error")
+	_, err = c.UserOverrideForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "UserOverrideForUser should return NoDataFoundError after the override is removed")
+
+	// Error when storing an override for nonexistent user
+	err = c.SetUserOverrideForUser("nonexistent", db.UserOverride{Shell: "/bin/zsh"})
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "SetUserOverrideForUser for a nonexistent user should return NoDataFoundError")
+	err = c.SetUserOverrideForUser("nonexistent", db.UserOverride{})
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "SetUserOverrideForUser for a nonexistent user should return NoDataFoundError")
+
+	// Overrides are removed with the user
+	err = c.DeleteUser(2222)
+	require.NoError(t, err, "DeleteUser should not return an error")
+	_, err = c.UserOverrideForUser("user2")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "UserOverrideForUser should return NoDataFoundError for a deleted user")
+}
+
+func TestLocalPINForUser(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	// No local PIN registered yet
+	_, err := c.LocalPINForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "LocalPINForUser should return NoDataFoundError before any registration")
+	_, err = c.IncrementLocalPINFailedAttempts("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "IncrementLocalPINFailedAttempts should return NoDataFoundError without PIN")
+	err = c.ResetLocalPINFailedAttempts("user1")
+	require.NoError(t, err, "ResetLocalPINFailedAttempts should not return an error without PIN")
+
+	// Register a PIN and record failed attempts
+	err = c.SetLocalPINForUser("user1", "hash1")
+	require.NoError(t, err, "SetLocalPINForUser for an existent user should not return an error")
+	for want := 1; want <= 2; want++ {
+		got, err := c.IncrementLocalPINFailedAttempts("user1")
+		require.NoError(t, err, "IncrementLocalPINFailedAttempts should not return an error")
+		require.Equal(t, want, got, "IncrementLocalPINFailedAttempts should return the number of failed attempts")
+	}
+	got, err := c.LocalPINForUser("user1")
+	require.NoError(t, err, "LocalPINForUser should not return an error")
+	require.Equal(t, db.LocalPINRow{UID: 1111, Hash: "hash1", FailedAttempts: 2}, got, "LocalPINForUser should return the registered PIN")
+
+	// Resetting the failed attempts keeps the PIN
+	err = c.ResetLocalPINFailedAttempts("user1")
+	require.NoError(t, err, "ResetLocalPINFailedAttempts should not return an error")
+	got, err = c.LocalPINForUser("user1")
+	require.NoError(t, err, "LocalPINForUser should not return an error")
+	require.Equal(t, db.LocalPINRow{UID: 1111, Hash: "hash1"}, got, "ResetLocalPINFailedAttempts should reset the failed attempts")
+
+	// Replacing the PIN resets the failed attempts
+	_, err = c.IncrementLocalPINFailedAttempts("user1")
+	require.NoError(t, err, "IncrementLocalPINFailedAttempts should not return an error")
+	err = c.SetLocalPINForUser("user1", "hash2")
+	require.NoError(t, err, "SetLocalPINForUser should replace the previous PIN")
+	got, err = c.LocalPINForUser("user1")
+	require.NoError(t, err, "LocalPINForUser should not return an error")
+	require.Equal(t, db.LocalPINRow{UID: 1111, Hash: "hash2"}, got, "SetLocalPINForUser should reset the failed attempts")
+
+	// Error when registering a PIN for nonexistent user
+	err = c.SetLocalPINForUser("nonexistent", "hash")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "SetLocalPINForUser for a nonexistent user should return NoDataFoundError")
+
+	// Deleting the PIN
+	err = c.DeleteLocalPINForUser("user1")
+	require.NoError(t, err, "DeleteLocalPINForUser should not return an error")
+	_, err = c.LocalPINForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "LocalPINForUser should return NoDataFoundError for a deleted PIN")
+	err = c.DeleteLocalPINForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "DeleteLocalPINForUser should return NoDataFoundError without PIN")
+
+	// Local PINs are removed with the user
+	err = c.SetLocalPINForUser("user1", "hash")
+	require.NoError(t, err, "SetLocalPINForUser should not return an error")
+	err = c.DeleteUser(1111)
+	require.NoError(t, err, "DeleteUser should not return an error")
+	_, err = c.LocalPINForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "LocalPINForUser should return NoDataFoundError for a deleted user")
+}
+
+func TestConsentForUser(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	// No consent accepted yet
+	_, err := c.ConsentForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "ConsentForUser should return NoDataFoundError before any acceptance")
+
+	// Accept a message, then another one
+	for _, hash := range []string{"hash1", "hash2"} {
+		acceptedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		err = c.SetConsentForUser("user1", hash, acceptedAt)
+		require.NoError(t, err, "SetConsentForUser for an existent user should not return an error")
+		got, err := c.ConsentForUser("user1")
+		require.NoError(t, err, "ConsentForUser should not return an error")
+		require.Equal(t, db.ConsentRow{UID: 1111, MessageHash: hash, AcceptedAt: acceptedAt.Unix()}, got,
+			"ConsentForUser should return the last accepted message")
+	}
+
+	// Error when accepting a message for nonexistent user
+	err = c.SetConsentForUser("nonexistent", "hash", time.Now())
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "SetConsentForUser for a nonexistent user should return NoDataFoundError")
+
+	// Consents are removed with the user
+	err = c.DeleteUser(1111)
+	require.NoError(t, err, "DeleteUser should not return an error")
+	_, err = c.ConsentForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "ConsentForUser should return NoDataFoundError for a deleted user")
+}
+
+func TestRolesForUser(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	// No roles given yet
+	got, err := c.RolesForUser("user1")
+	require.NoError(t, err, "RolesForUser should not return an error")
+	require.Empty(t, got, "RolesForUser should return no roles before any is set")
+
+	// Set roles, then replace them
+	for _, roles := range [][]string{{"dev-laptops", "admins"}, {"servers"}, nil} {
+		err = c.SetRolesForUser("user1", roles)
+		require.NoError(t, err, "SetRolesForUser for an existent user should not return an error")
+		got, err := c.RolesForUser("user1")
+		require.NoError(t, err, "RolesForUser should not return an error")
+		require.ElementsMatch(t, roles, got, "RolesForUser should return the last roles set")
+	}
+
+	// Error for nonexistent user
+	err = c.SetRolesForUser("nonexistent", []string{"admins"})
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "SetRolesForUser for a nonexistent user should return NoDataFoundError")
+	_, err = c.RolesForUser("nonexistent")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "RolesForUser for a nonexistent user should return NoDataFoundError")
+
+	// Roles are removed with the user
+	err = c.SetRolesForUser("user1", []string{"admins"})
+	require.NoError(t, err, "SetRolesForUser should not return an error")
+	err = c.DeleteUser(1111)
+	require.NoError(t, err, "DeleteUser should not return an error")
+	_, err = c.RolesForUser("user1")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "RolesForUser should return NoDataFoundError for a deleted user")
+}
+
 func TestRemoveDb(t *testing.T) {
 	t.Parallel()
 
@@ -476,6 +1017,65 @@ func TestRemoveDb(t *testing.T) {
 	require.ErrorIs(t, db.RemoveDB(dbDir), fs.ErrNotExist, "RemoveDB should return os.ErrNotExist on the second call")
 }
 
+func TestCheckHealth(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "multiple_users_and_groups")
+	require.NoError(t, c.CheckHealth(), "CheckHealth should not return an error on a healthy database")
+
+	require.NoError(t, c.Close(), "Setup: could not close the database")
+	require.Error(t, c.CheckHealth(), "CheckHealth should return an error once the database is closed")
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		dbFile          string
+		corruptedDbFile bool
+		danglingGroup   bool
+
+		wantErr bool
+	}{
+		"Valid_database": {dbFile: "multiple_users_and_groups"},
+
+		"Error_on_non_existent_database":           {wantErr: true},
+		"Error_on_corrupted_database":              {corruptedDbFile: true, wantErr: true},
+		"Error_on_reference_to_non_existent_group": {dbFile: "multiple_users_and_groups", danglingGroup: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbDir := t.TempDir()
+			dbPath := filepath.Join(dbDir, db.Z_ForTests_DBName())
+			if tc.dbFile != "" {
+				err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", tc.dbFile+".db.yaml"), dbDir)
+				require.NoError(t, err, "Setup: could not create database from testdata")
+			}
+			if tc.corruptedDbFile {
+				err := os.WriteFile(dbPath, []byte("corrupted"), 0600)
+				require.NoError(t, err, "Setup: could not write corrupted database file")
+			}
+			if tc.danglingGroup {
+				// The foreign keys are not enforced by default, which allows to insert an inconsistent row.
+				sqlDB, err := sql.Open("sqlite3", dbPath)
+				require.NoError(t, err, "Setup: could not open database")
+				_, err = sqlDB.Exec("INSERT INTO users_to_groups (uid, gid) VALUES (1111, 12345)")
+				require.NoError(t, err, "Setup: could not insert inconsistent row")
+				require.NoError(t, sqlDB.Close(), "Setup: could not close database")
+			}
+
+			err := db.CheckIntegrity(dbDir)
+			if tc.wantErr {
+				require.Error(t, err, "CheckIntegrity should return an error but didn't")
+				return
+			}
+			require.NoError(t, err, "CheckIntegrity should not return an error but did")
+		})
+	}
+}
+
 func TestDeleteUser(t *testing.T) {
 	t.Parallel()
 
@@ -515,8 +1115,57 @@ func TestDeleteUser(t *testing.T) {
 	}
 }
 
+func TestDeletedUserByID(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	// The UID is not archived while the user exists
+	_, err := c.DeletedUserByID(1111)
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "DeletedUserByID should return NoDataFoundError for an existing user")
+
+	before := time.Now().Unix()
+	err = c.DeleteUser(1111)
+	require.NoError(t, err, "DeleteUser should not return an error")
+
+	got, err := c.DeletedUserByID(1111)
+	require.NoError(t, err, "DeletedUserByID should not return an error for a deleted user")
+	require.Equal(t, uint32(1111), got.UID, "DeletedUserByID should return the UID of the deleted user")
+	require.Equal(t, "user1", got.Name, "DeletedUserByID should return the name of the deleted user")
+	require.GreaterOrEqual(t, got.DeletedAt, before, "DeletedUserByID should return when the user was deleted")
+
+	// Other UIDs were never used
+	_, err = c.DeletedUserByID(2222)
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "DeletedUserByID should return NoDataFoundError for an unused UID")
+}
+
+func TestAdoptedUsers(t *testing.T) {
+	t.Parallel()
+
+	c := initDB(t, "one_user_and_group")
+
+	_, err := c.AdoptedUserByName("brokeruser")
+	require.ErrorIs(t, err, db.NoDataFoundError{}, "AdoptedUserByName should return NoDataFoundError before the adoption")
+
+	want := db.AdoptedUserRow{Name: "brokeruser", LocalName: "localuser", UID: 4242, GID: 4343, Dir: "/home/localuser"}
+	err = c.AddAdoptedUser(want)
+	require.NoError(t, err, "AddAdoptedUser should not return an error")
+
+	got, err := c.AdoptedUserByName("brokeruser")
+	require.NoError(t, err, "AdoptedUserByName should not return an error for an adopting user")
+	require.Equal(t, want, got, "AdoptedUserByName should return the adoption")
+
+	got, err = c.AdoptedUserByID(4242)
+	require.NoError(t, err, "AdoptedUserByID should not return an error for an adopted UID")
+	require.Equal(t, want, got, "AdoptedUserByID should return the adoption")
+
+	// A local user can only be adopted once
+	err = c.AddAdoptedUser(db.AdoptedUserRow{Name: "otheruser", LocalName: "localuser", UID: 4242, GID: 4343, Dir: "/home/localuser"})
+	require.Error(t, err, "AddAdoptedUser should return an error when the UID is already adopted")
+}
+
 // initDB returns a new database ready to be used alongside its database directory.
-func initDB(t *testing.T, dbFile string) *db.Manager {
+func initDB(t testing.TB, dbFile string) *db.Manager {
 	t.Helper()
 
 	dbDir, err := os.MkdirTemp("", "authd-db-test-*")
@@ -557,6 +1206,67 @@ func requireGetAssertions[E any](t *testing.T, got E, wantErr bool, wantErrType,
 	golden.CheckOrUpdateYAML(t, got)
 }
 
+// benchmarkUsers is the number of users in the database of the benchmarks.
+const benchmarkUsers = 100_000
+
+func BenchmarkUserByName(b *testing.B) {
+	c := initBenchmarkDB(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		_, err := c.UserByName(fmt.Sprintf("user%d", i%benchmarkUsers))
+		require.NoError(b, err, "UserByName should not return an error")
+	}
+}
+
+func BenchmarkAllUsers(b *testing.B) {
+	c := initBenchmarkDB(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		users, err := c.AllUsers()
+		require.NoError(b, err, "AllUsers should not return an error")
+		require.Len(b, users, benchmarkUsers, "AllUsers should return all users")
+	}
+}
+
+func BenchmarkAllUsersStream(b *testing.B) {
+	c := initBenchmarkDB(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		var n int
+		for _, err := range c.AllUsersStream() {
+			require.NoError(b, err, "AllUsersStream should not yield an error")
+			n++
+		}
+		require.Equal(b, benchmarkUsers, n, "AllUsersStream should yield all users")
+	}
+}
+
+// initBenchmarkDB returns a database containing benchmarkUsers users.
+func initBenchmarkDB(b *testing.B) *db.Manager {
+	b.Helper()
+
+	c := initDB(b, "")
+
+	users := make([]db.UserRow, 0, benchmarkUsers)
+	for i := range benchmarkUsers {
+		name := fmt.Sprintf("user%d", i)
+		//nolint:gosec // The UIDs of the benchmarks are small enough.
+		u := db.NewUserRow(name, uint32(10000+i), uint32(10000+i), "gecos", "/home/"+name, "/bin/bash")
+		u.BrokerID = "broker-id"
+		users = append(users, u)
+	}
+	err := c.InsertUsersForBenchmarks(users)
+	require.NoError(b, err, "Setup: could not insert users")
+
+	return c
+}
+
 func TestMain(m *testing.M) {
 	log.SetLevel(log.DebugLevel)
 
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DeletedUserRow represents a row in the deleted_users table, which keeps the UIDs of the deleted users so that they
+// are not given to new users, and that the files still owned by them can be attributed.
+type DeletedUserRow struct {
+	UID       uint32
+	Name      string
+	DeletedAt int64 `yaml:"deleted_at"`
+}
+
+// DeletedUserByID returns the deleted user which had this UID, or an error if the database is corrupted or no user
+// with this UID was deleted.
+func (m *Manager) DeletedUserByID(uid uint32) (DeletedUserRow, error) {
+	row := m.db.QueryRow(`SELECT uid, name, deleted_at FROM deleted_users WHERE uid = ?`, uid)
+
+	var d DeletedUserRow
+	err := row.Scan(&d.UID, &d.Name, &d.DeletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DeletedUserRow{}, NoDataFoundError{key: strconv.FormatUint(uint64(uid), 10), table: "deleted_users"}
+	}
+	if err != nil {
+		return DeletedUserRow{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return d, nil
+}
+
+// archiveDeletedUser records that the user was deleted, replacing any previous user which had the same UID.
+func archiveDeletedUser(db queryable, u UserRow, deletedAt time.Time) error {
+	query := `INSERT INTO deleted_users (uid, name, deleted_at) VALUES (?, ?, ?)
+		ON CONFLICT (uid) DO UPDATE SET name = excluded.name, deleted_at = excluded.deleted_at`
+	if _, err := db.Exec(query, u.UID, u.Name, deletedAt.Unix()); err != nil {
+		return fmt.Errorf("failed to archive deleted user: %w", err)
+	}
+	return nil
+}
+
+// allDeletedUsers returns all rows of the deleted_users table.
+func allDeletedUsers(db queryable) ([]DeletedUserRow, error) {
+	rows, err := db.Query(`SELECT uid, name, deleted_at FROM deleted_users`)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	var deleted []DeletedUserRow
+	for rows.Next() {
+		var d DeletedUserRow
+		if err := rows.Scan(&d.UID, &d.Name, &d.DeletedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		deleted = append(deleted, d)
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,346 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ubuntu/authd/log"
+)
+
+// Dump is the whole content of the database, exported and imported by the administrators to debug or to migrate the
+// users of a machine to another one.
+type Dump struct {
+	Users  []DumpUser  `json:"users"`
+	Groups []DumpGroup `json:"groups"`
+	// DeletedUsers are the users which were deleted, whose UIDs are not given to new users.
+	DeletedUsers []DumpDeletedUser `json:"deleted_users,omitempty"`
+	// AdoptedUsers are the local users whose identity is taken over by users of a broker.
+	AdoptedUsers []DumpAdoptedUser `json:"adopted_users,omitempty"`
+}
+
+// DumpUser is a user of the database, with its group memberships, the authentication modes it last used, its local
+// PIN, its picture, the consent message it accepted, its roles and when it last selected its broker.
+type DumpUser struct {
+	Name     string `json:"name"`
+	UID      uint32 `json:"uid"`
+	GID      uint32 `json:"gid"`
+	Gecos    string `json:"gecos"`
+	Dir      string `json:"dir"`
+	Shell    string `json:"shell"`
+	BrokerID string `json:"broker_id"`
+
+	// Groups are the GIDs of the authd groups the user is a member of.
+	Groups []uint32 `json:"groups"`
+	// LocalGroups are the names of the local groups the user was added to.
+	LocalGroups []string `json:"local_groups"`
+	// AuthModes are the authentication modes the user last successfully used, per broker ID.
+	AuthModes map[string]string `json:"auth_modes,omitempty"`
+	// LocalPIN is the local PIN the user registered, if any.
+	LocalPIN *DumpLocalPIN `json:"local_pin,omitempty"`
+	// Avatar is the picture of the user provided by their broker, if any.
+	Avatar *DumpAvatar `json:"avatar,omitempty"`
+	// Override are the attributes of the user set locally, which take precedence over the ones provided by the broker,
+	// if any.
+	Override *UserOverride `json:"override,omitempty"`
+	// Consent is the consent message the user last accepted, if any.
+	Consent *DumpConsent `json:"consent,omitempty"`
+	// Roles are the roles the broker gave to the user on their last login.
+	Roles []string `json:"roles,omitempty"`
+	// BrokerSelectedAt is when the user last selected their broker, if it was recorded.
+	BrokerSelectedAt *time.Time `json:"broker_selected_at,omitempty"`
+}
+
+// DumpLocalPIN is the local PIN registered by a user.
+type DumpLocalPIN struct {
+	Hash           string `json:"hash"`
+	FailedAttempts int    `json:"failed_attempts"`
+}
+
+// DumpAvatar is the picture of a user.
+type DumpAvatar struct {
+	URL     string `json:"url,omitempty"`
+	Content []byte `json:"content"`
+}
+
+// DumpConsent is the consent message accepted by a user.
+type DumpConsent struct {
+	MessageHash string    `json:"message_hash"`
+	AcceptedAt  time.Time `json:"accepted_at"`
+}
+
+// DumpDeletedUser is a user which was deleted.
+type DumpDeletedUser struct {
+	Name      string    `json:"name"`
+	UID       uint32    `json:"uid"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// DumpAdoptedUser is a local user whose identity is taken over by the user of a broker with the given name.
+type DumpAdoptedUser struct {
+	Name      string `json:"name"`
+	LocalName string `json:"local_name"`
+	UID       uint32 `json:"uid"`
+	GID       uint32 `json:"gid"`
+	Dir       string `json:"dir"`
+}
+
+// DumpGroup is a group of the database.
+type DumpGroup struct {
+	Name string `json:"name"`
+	GID  uint32 `json:"gid"`
+	UGID string `json:"ugid"`
+}
+
+// Dump returns the whole content of the database, sorted by UID and GID, and by name for the adopted users.
+func (m *Manager) Dump() (d Dump, err error) {
+	// Use a transaction so that the content is consistent.
+	tx, err := m.db.Begin()
+	if err != nil {
+		return Dump{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		err = commitOrRollBackTransaction(err, tx)
+	}()
+
+	users, err := allUsers(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	groups, err := allGroups(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	userGroups, err := allUserGroupsInternal(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	userAuthModes, err := allUserAuthModes(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	localPINs, err := allLocalPINs(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	avatars, err := allAvatars(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	overrides, err := allUserOverrideRows(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	consents, err := allConsents(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	roles, err := allUserRoles(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	brokerSelections, err := allBrokerSelections(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	deletedUsers, err := allDeletedUsers(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+	adoptedUsers, err := allAdoptedUsers(tx)
+	if err != nil {
+		return Dump{}, err
+	}
+
+	d = Dump{Users: []DumpUser{}, Groups: []DumpGroup{}}
+	for _, u := range users {
+		localGroups, err := userLocalGroups(tx, u.UID)
+		if err != nil {
+			return Dump{}, err
+		}
+		sort.Strings(localGroups)
+
+		du := DumpUser{
+			Name:        u.Name,
+			UID:         u.UID,
+			GID:         u.GID,
+			Gecos:       u.Gecos,
+			Dir:         u.Dir,
+			Shell:       u.Shell,
+			BrokerID:    u.BrokerID,
+			Groups:      []uint32{},
+			LocalGroups: []string{},
+		}
+		du.LocalGroups = append(du.LocalGroups, localGroups...)
+		for _, ug := range userGroups {
+			if ug.UID == u.UID {
+				du.Groups = append(du.Groups, ug.GID)
+			}
+		}
+		sort.Slice(du.Groups, func(i, j int) bool { return du.Groups[i] < du.Groups[j] })
+		for _, am := range userAuthModes {
+			if am.UID != u.UID {
+				continue
+			}
+			if du.AuthModes == nil {
+				du.AuthModes = make(map[string]string)
+			}
+			du.AuthModes[am.BrokerID] = am.AuthMode
+		}
+		for _, p := range localPINs {
+			if p.UID == u.UID {
+				du.LocalPIN = &DumpLocalPIN{Hash: p.Hash, FailedAttempts: p.FailedAttempts}
+			}
+		}
+		for _, a := range avatars {
+			if a.UID == u.UID {
+				du.Avatar = &DumpAvatar{URL: a.URL, Content: a.Content}
+			}
+		}
+		for _, o := range overrides {
+			if o.UID == u.UID {
+				override := o.override()
+				du.Override = &override
+			}
+		}
+		for _, c := range consents {
+			if c.UID == u.UID {
+				du.Consent = &DumpConsent{MessageHash: c.MessageHash, AcceptedAt: time.Unix(c.AcceptedAt, 0).UTC()}
+			}
+		}
+		for _, r := range roles {
+			if r.UID == u.UID {
+				du.Roles = append(du.Roles, r.Role)
+			}
+		}
+		for _, s := range brokerSelections {
+			if s.UID == u.UID {
+				selectedAt := time.Unix(s.SelectedAt, 0).UTC()
+				du.BrokerSelectedAt = &selectedAt
+			}
+		}
+
+		d.Users = append(d.Users, du)
+	}
+	sort.Slice(d.Users, func(i, j int) bool { return d.Users[i].UID < d.Users[j].UID })
+
+	for _, g := range groups {
+		d.Groups = append(d.Groups, DumpGroup{Name: g.Name, GID: g.GID, UGID: g.UGID})
+	}
+	sort.Slice(d.Groups, func(i, j int) bool { return d.Groups[i].GID < d.Groups[j].GID })
+
+	for _, du := range deletedUsers {
+		d.DeletedUsers = append(d.DeletedUsers, DumpDeletedUser{Name: du.Name, UID: du.UID, DeletedAt: time.Unix(du.DeletedAt, 0).UTC()})
+	}
+	sort.Slice(d.DeletedUsers, func(i, j int) bool { return d.DeletedUsers[i].UID < d.DeletedUsers[j].UID })
+
+	for _, a := range adoptedUsers {
+		d.AdoptedUsers = append(d.AdoptedUsers, DumpAdoptedUser(a))
+	}
+	sort.Slice(d.AdoptedUsers, func(i, j int) bool { return d.AdoptedUsers[i].Name < d.AdoptedUsers[j].Name })
+
+	return d, nil
+}
+
+// Import replaces the whole content of the database with the given one. The database is left unchanged on error.
+func (m *Manager) Import(d Dump) (err error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		err = commitOrRollBackTransaction(err, tx)
+	}()
+
+	for _, table := range []string{"broker_selections", "adopted_users", "deleted_users", "user_roles", "consents", "user_overrides", "avatars", "local_pins", "users_to_auth_modes", "users_to_local_groups", "users_to_groups", "users", "groups"} {
+		//nolint:gosec // The table names are not user input.
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", table)); err != nil {
+			return fmt.Errorf("failed to clear table %q: %w", table, err)
+		}
+	}
+
+	for _, g := range d.Groups {
+		if err := insertGroup(tx, NewGroupRow(g.Name, g.GID, g.UGID)); err != nil {
+			return err
+		}
+	}
+
+	for _, u := range d.Users {
+		user := NewUserRow(u.Name, u.UID, u.GID, u.Gecos, u.Dir, u.Shell)
+		user.BrokerID = u.BrokerID
+		if err := insertUser(tx, user); err != nil {
+			return err
+		}
+		for _, gid := range u.Groups {
+			if err := addUserToGroup(tx, u.UID, gid); err != nil {
+				return fmt.Errorf("failed to add user %q to group %d: %w", u.Name, gid, err)
+			}
+		}
+		for _, g := range u.LocalGroups {
+			if err := addUserToLocalGroup(tx, u.UID, g); err != nil {
+				return err
+			}
+		}
+		for brokerID, authMode := range u.AuthModes {
+			query := `INSERT INTO users_to_auth_modes (uid, broker_id, auth_mode) VALUES (?, ?, ?)`
+			if _, err := tx.Exec(query, u.UID, brokerID, authMode); err != nil {
+				return fmt.Errorf("failed to add authentication mode of user %q: %w", u.Name, err)
+			}
+		}
+		if u.LocalPIN != nil {
+			query := `INSERT INTO local_pins (uid, hash, failed_attempts) VALUES (?, ?, ?)`
+			if _, err := tx.Exec(query, u.UID, u.LocalPIN.Hash, u.LocalPIN.FailedAttempts); err != nil {
+				return fmt.Errorf("failed to add local PIN of user %q: %w", u.Name, err)
+			}
+		}
+		if u.Avatar != nil {
+			query := `INSERT INTO avatars (uid, url, content) VALUES (?, ?, ?)`
+			if _, err := tx.Exec(query, u.UID, u.Avatar.URL, u.Avatar.Content); err != nil {
+				return fmt.Errorf("failed to add avatar of user %q: %w", u.Name, err)
+			}
+		}
+		if u.Override != nil {
+			o := u.Override
+			query := `INSERT INTO user_overrides (uid, shell, dir, gecos, local_groups) VALUES (?, ?, ?, ?, ?)`
+			if _, err := tx.Exec(query, u.UID, o.Shell, o.Dir, o.Gecos, strings.Join(o.LocalGroups, ",")); err != nil {
+				return fmt.Errorf("failed to add override of user %q: %w", u.Name, err)
+			}
+		}
+		if u.Consent != nil {
+			query := `INSERT INTO consents (uid, message_hash, accepted_at) VALUES (?, ?, ?)`
+			if _, err := tx.Exec(query, u.UID, u.Consent.MessageHash, u.Consent.AcceptedAt.Unix()); err != nil {
+				return fmt.Errorf("failed to add consent of user %q: %w", u.Name, err)
+			}
+		}
+		for _, role := range u.Roles {
+			if err := addUserRole(tx, u.UID, role); err != nil {
+				return fmt.Errorf("failed to add role of user %q: %w", u.Name, err)
+			}
+		}
+		if u.BrokerSelectedAt != nil {
+			query := `INSERT INTO broker_selections (uid, selected_at) VALUES (?, ?)`
+			if _, err := tx.Exec(query, u.UID, u.BrokerSelectedAt.Unix()); err != nil {
+				return fmt.Errorf("failed to add broker selection time of user %q: %w", u.Name, err)
+			}
+		}
+	}
+
+	for _, du := range d.DeletedUsers {
+		query := `INSERT INTO deleted_users (uid, name, deleted_at) VALUES (?, ?, ?)`
+		if _, err := tx.Exec(query, du.UID, du.Name, du.DeletedAt.Unix()); err != nil {
+			return fmt.Errorf("failed to add deleted user %q: %w", du.Name, err)
+		}
+	}
+
+	for _, a := range d.AdoptedUsers {
+		query := fmt.Sprintf(`INSERT INTO adopted_users (%s) VALUES (?, ?, ?, ?, ?)`, adoptedUserColumns)
+		if _, err := tx.Exec(query, a.Name, a.LocalName, a.UID, a.GID, a.Dir); err != nil {
+			return fmt.Errorf("failed to add adopted user %q: %w", a.Name, err)
+		}
+	}
+
+	log.Debugf(context.Background(), "Imported %d users and %d groups", len(d.Users), len(d.Groups))
+	return nil
+}
@@ -1,6 +1,46 @@
 package db
 
+import "fmt"
+
 // Path exposes the path to the database file for testing.
 func (m *Manager) Path() string {
 	return m.path
 }
+
+// InsertUsersForBenchmarks inserts the users in a single transaction, which is much faster than updating them one by
+// one.
+func (m *Manager) InsertUsersForBenchmarks(users []UserRow) (err error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = commitOrRollBackTransaction(err, tx)
+	}()
+
+	query := fmt.Sprintf(`INSERT INTO users (%s) VALUES (?, ?, ?, ?, ?, ?, ?)`, allUserColumns)
+	for _, u := range users {
+		if _, err := tx.Exec(query, u.Name, u.UID, u.GID, u.Gecos, u.Dir, u.Shell, u.BrokerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BlockWrites holds the writes made until the returned function is called, which commits them in a single batch.
+func (m *Manager) BlockWrites() (release func()) {
+	m.writes.flushMu.Lock()
+	return m.writes.flushMu.Unlock
+}
+
+// PendingWrites returns the number of writes waiting to be committed.
+func (m *Manager) PendingWrites() int {
+	m.writes.mu.Lock()
+	defer m.writes.mu.Unlock()
+	return len(m.writes.pending)
+}
+
+// Commits returns the number of transactions committed by batches of writes.
+func (m *Manager) Commits() uint64 {
+	return m.writes.commits.Load()
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/decorate"
+)
+
+// CheckIntegrity checks, without modifying it, that the database in dbDir is not corrupted and that its references
+// between users and groups are consistent. It can be called while the database is used by the daemon.
+func CheckIntegrity(dbDir string) (err error) {
+	dbPath := filepath.Join(dbDir, filename)
+	defer decorate.OnError(&err, "integrity check of database %q failed", dbPath)
+	defer func() { err = classifyCorruption(err) }()
+
+	if err := checkOwnerAndPermissions(dbPath); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rows, err := db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return err
+	}
+	var problems []string
+	for rows.Next() {
+		var problem string
+		if err := rows.Scan(&problem); err != nil {
+			rows.Close()
+			return err
+		}
+		if problem != "ok" {
+			problems = append(problems, problem)
+		}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return authderrors.ErrCorrupted.Errorf("database is corrupted: %s", strings.Join(problems, "; "))
+	}
+
+	// Each row of the foreign key check is a reference to a missing row.
+	rows, err = db.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var fkID int
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			rows.Close()
+			return err
+		}
+		problems = append(problems, fmt.Sprintf("row %d of table %q references a missing row of table %q", rowID.Int64, table, parent))
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return authderrors.ErrCorrupted.Errorf("database is inconsistent: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// LocalPINRow represents a row in the local_pins table.
+type LocalPINRow struct {
+	UID            uint32
+	Hash           string
+	FailedAttempts int `yaml:"failed_attempts"`
+}
+
+// LocalPINForUser returns the local PIN registered by the user, or an error if the database is corrupted or the user
+// has no PIN.
+func (m *Manager) LocalPINForUser(username string) (LocalPINRow, error) {
+	query := `SELECT local_pins.uid, hash, failed_attempts FROM local_pins
+		JOIN users ON local_pins.uid = users.uid
+		WHERE users.name = ?`
+	row := m.db.QueryRow(query, username)
+
+	var p LocalPINRow
+	err := row.Scan(&p.UID, &p.Hash, &p.FailedAttempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return LocalPINRow{}, NoDataFoundError{key: username, table: "local_pins"}
+	}
+	if err != nil {
+		return LocalPINRow{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return p, nil
+}
+
+// SetLocalPINForUser registers the hash of the local PIN of the user, replacing any previous one and resetting its
+// failed attempts.
+func (m *Manager) SetLocalPINForUser(username, hash string) error {
+	query := `INSERT INTO local_pins (uid, hash, failed_attempts)
+		SELECT uid, ?, 0 FROM users WHERE name = ?
+		ON CONFLICT (uid) DO UPDATE SET hash = excluded.hash, failed_attempts = 0`
+	res, err := m.db.Exec(query, hash, username)
+	if err != nil {
+		return fmt.Errorf("failed to set local PIN: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return NoDataFoundError{key: username, table: "users"}
+	}
+	return nil
+}
+
+// DeleteLocalPINForUser removes the local PIN of the user.
+func (m *Manager) DeleteLocalPINForUser(username string) error {
+	query := `DELETE FROM local_pins WHERE uid = (SELECT uid FROM users WHERE name = ?)`
+	res, err := m.db.Exec(query, username)
+	if err != nil {
+		return fmt.Errorf("failed to delete local PIN: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return NoDataFoundError{key: username, table: "local_pins"}
+	}
+	return nil
+}
+
+// IncrementLocalPINFailedAttempts records a failed attempt to authenticate with the local PIN of the user, and returns
+// the number of failed attempts since the last successful authentication.
+func (m *Manager) IncrementLocalPINFailedAttempts(username string) (int, error) {
+	query := `UPDATE local_pins SET failed_attempts = failed_attempts + 1
+		WHERE uid = (SELECT uid FROM users WHERE name = ?)
+		RETURNING failed_attempts`
+	var attempts int
+	err := m.db.QueryRow(query, username).Scan(&attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, NoDataFoundError{key: username, table: "local_pins"}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to update local PIN failed attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// ResetLocalPINFailedAttempts forgets the failed attempts to authenticate with the local PIN of the user, if any.
+func (m *Manager) ResetLocalPINFailedAttempts(username string) error {
+	query := `UPDATE local_pins SET failed_attempts = 0 WHERE uid = (SELECT uid FROM users WHERE name = ?)`
+	if _, err := m.db.Exec(query, username); err != nil {
+		return fmt.Errorf("failed to reset local PIN failed attempts: %w", err)
+	}
+	return nil
+}
+
+// allLocalPINs returns all rows of the local_pins table.
+func allLocalPINs(db queryable) ([]LocalPINRow, error) {
+	rows, err := db.Query(`SELECT uid, hash, failed_attempts FROM local_pins`)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	var pins []LocalPINRow
+	for rows.Next() {
+		var p LocalPINRow
+		if err := rows.Scan(&p.UID, &p.Hash, &p.FailedAttempts); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		pins = append(pins, p)
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return pins, nil
+}
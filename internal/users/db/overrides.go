@@ -0,0 +1,149 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// UserOverride are the attributes of a user set locally by the administrator, which take precedence over the ones
+// provided by the broker. The empty attributes are not overridden.
+type UserOverride struct {
+	Shell string `json:"shell,omitempty"`
+	Dir   string `json:"dir,omitempty"`
+	Gecos string `json:"gecos,omitempty"`
+	// LocalGroups are the local groups the user is added to on each login, in addition to the ones of the broker.
+	LocalGroups []string `json:"local_groups,omitempty"`
+}
+
+// IsEmpty returns whether the override doesn't change any attribute.
+func (o UserOverride) IsEmpty() bool {
+	return o.Shell == "" && o.Dir == "" && o.Gecos == "" && len(o.LocalGroups) == 0
+}
+
+// userOverrideRow represents a row in the user_overrides table.
+type userOverrideRow struct {
+	UID         uint32
+	Shell       string
+	Dir         string
+	Gecos       string
+	LocalGroups string `yaml:"local_groups"`
+}
+
+func (r userOverrideRow) override() UserOverride {
+	o := UserOverride{Shell: r.Shell, Dir: r.Dir, Gecos: r.Gecos}
+	if r.LocalGroups != "" {
+		o.LocalGroups = strings.Split(r.LocalGroups, ",")
+	}
+	return o
+}
+
+// UserOverrideForUser returns the attributes of the user set locally, or an error if the database is corrupted or no
+// attribute was set locally for the user.
+func (m *Manager) UserOverrideForUser(username string) (UserOverride, error) {
+	query := `SELECT user_overrides.uid, user_overrides.shell, user_overrides.dir, user_overrides.gecos, local_groups
+		FROM user_overrides JOIN users ON user_overrides.uid = users.uid
+		WHERE users.name = ?`
+	row := m.db.QueryRow(query, username)
+
+	var r userOverrideRow
+	err := row.Scan(&r.UID, &r.Shell, &r.Dir, &r.Gecos, &r.LocalGroups)
+	if errors.Is(err, sql.ErrNoRows) {
+		return UserOverride{}, NoDataFoundError{key: username, table: "user_overrides"}
+	}
+	if err != nil {
+		return UserOverride{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return r.override(), nil
+}
+
+// SetUserOverrideForUser stores the attributes of the user set locally, replacing the previous ones. An empty
+// override removes them.
+func (m *Manager) SetUserOverrideForUser(username string, o UserOverride) (err error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		err = commitOrRollBackTransaction(err, tx)
+	}()
+
+	var uid uint32
+	err = tx.QueryRow(`SELECT uid FROM users WHERE name = ?`, username).Scan(&uid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NoDataFoundError{key: username, table: "users"}
+	}
+	if err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+
+	if o.IsEmpty() {
+		if _, err := tx.Exec(`DELETE FROM user_overrides WHERE uid = ?`, uid); err != nil {
+			return fmt.Errorf("failed to delete user override: %w", err)
+		}
+		return nil
+	}
+
+	query := `INSERT INTO user_overrides (uid, shell, dir, gecos, local_groups) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (uid) DO UPDATE SET shell = excluded.shell, dir = excluded.dir, gecos = excluded.gecos,
+			local_groups = excluded.local_groups`
+	if _, err := tx.Exec(query, uid, o.Shell, o.Dir, o.Gecos, strings.Join(o.LocalGroups, ",")); err != nil {
+		return fmt.Errorf("failed to set user override: %w", err)
+	}
+	return nil
+}
+
+// AllUserOverrides returns the attributes set locally of all the users, indexed by user name.
+func (m *Manager) AllUserOverrides() (map[string]UserOverride, error) {
+	query := `SELECT users.name, user_overrides.shell, user_overrides.dir, user_overrides.gecos, local_groups
+		FROM user_overrides JOIN users ON user_overrides.uid = users.uid`
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	overrides := make(map[string]UserOverride)
+	for rows.Next() {
+		var name string
+		var r userOverrideRow
+		if err := rows.Scan(&name, &r.Shell, &r.Dir, &r.Gecos, &r.LocalGroups); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		overrides[name] = r.override()
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// allUserOverrideRows returns all rows of the user_overrides table.
+func allUserOverrideRows(db queryable) ([]userOverrideRow, error) {
+	rows, err := db.Query(`SELECT uid, shell, dir, gecos, local_groups FROM user_overrides`)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	var overrides []userOverrideRow
+	for rows.Next() {
+		var r userOverrideRow
+		if err := rows.Scan(&r.UID, &r.Shell, &r.Dir, &r.Gecos, &r.LocalGroups); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		overrides = append(overrides, r)
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return overrides, nil
+}
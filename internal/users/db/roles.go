@@ -0,0 +1,111 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+)
+
+// userRoleRow represents a row in the user_roles table.
+type userRoleRow struct {
+	UID  uint32
+	Role string
+}
+
+// RolesForUser returns the roles the broker gave to the user on their last login, sorted by name, or an error if the
+// database is corrupted or the user doesn't exist.
+func (m *Manager) RolesForUser(username string) ([]string, error) {
+	u, err := userByName(m.db, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return userRoles(m.db, u.UID)
+}
+
+// SetRolesForUser replaces the roles of the user with the given ones.
+func (m *Manager) SetRolesForUser(username string, roles []string) error {
+	return m.writes.write(func(tx *sql.Tx) error {
+		return setRolesForUser(tx, username, roles)
+	})
+}
+
+// setRolesForUser replaces the roles of the user in the transaction.
+func setRolesForUser(tx *sql.Tx, username string, roles []string) error {
+	u, err := userByName(tx, username)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM user_roles WHERE uid = ?`, u.UID); err != nil {
+		return fmt.Errorf("failed to remove roles: %w", err)
+	}
+	for _, role := range roles {
+		if err := addUserRole(tx, u.UID, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addUserRole(db queryable, uid uint32, role string) error {
+	if _, err := db.Exec(`INSERT OR IGNORE INTO user_roles (uid, role) VALUES (?, ?)`, uid, role); err != nil {
+		return fmt.Errorf("failed to add role %q: %w", role, err)
+	}
+	return nil
+}
+
+func userRoles(db queryable, uid uint32) ([]string, error) {
+	rows, err := db.Query(`SELECT role FROM user_roles WHERE uid = ? ORDER BY role`, uid)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	var roles []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return roles, nil
+}
+
+// allUserRoles returns all rows of the user_roles table, sorted by UID and role.
+func allUserRoles(db queryable) ([]userRoleRow, error) {
+	rows, err := db.Query(`SELECT uid, role FROM user_roles`)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer closeRows(rows)
+
+	var roles []userRoleRow
+	for rows.Next() {
+		var r userRoleRow
+		if err := rows.Scan(&r.UID, &r.Role); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		roles = append(roles, r)
+	}
+
+	// Check for errors from iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].UID != roles[j].UID {
+			return roles[i].UID < roles[j].UID
+		}
+		return roles[i].Role < roles[j].Role
+	})
+	return roles, nil
+}
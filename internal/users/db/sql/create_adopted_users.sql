@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS adopted_users (
+    name       TEXT PRIMARY KEY, -- The name of the user provided by the broker
+    local_name TEXT NOT NULL,    -- The name of the local user whose identity is reused
+    uid        INT NOT NULL,     -- The UID of the local user
+    gid        INT NOT NULL,     -- The primary GID of the local user
+    dir        TEXT NOT NULL     -- The home directory of the local user
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_adopted_user_uid" ON adopted_users ("uid");
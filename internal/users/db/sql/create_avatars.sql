@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS avatars (
+    uid     INT PRIMARY KEY,
+    url     TEXT NOT NULL DEFAULT '', -- The address the picture was downloaded from, empty if the broker provided it
+    content BLOB NOT NULL,            -- The picture of the user
+    FOREIGN KEY (uid) REFERENCES users (uid) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS broker_selections (
+    uid         INT PRIMARY KEY,
+    selected_at INT NOT NULL, -- The Unix time at which the user last selected their broker
+    FOREIGN KEY (uid) REFERENCES users (uid) ON DELETE CASCADE
+);
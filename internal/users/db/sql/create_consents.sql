@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS consents (
+    uid          INT PRIMARY KEY,
+    message_hash TEXT NOT NULL, -- The SHA-256 hash of the consent message the user accepted
+    accepted_at  INT NOT NULL,  -- The Unix time at which the user accepted it
+    FOREIGN KEY (uid) REFERENCES users (uid) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS deleted_users (
+    uid        INT PRIMARY KEY,
+    name       TEXT NOT NULL, -- The name of the user the UID belonged to
+    deleted_at INT NOT NULL   -- The Unix time at which the user was deleted
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS local_pins (
+    uid             INT PRIMARY KEY,
+    hash            TEXT NOT NULL,          -- The Argon2id hash of the PIN, in the PHC string format
+    failed_attempts INT NOT NULL DEFAULT 0, -- The failed attempts since the last successful authentication
+    FOREIGN KEY (uid) REFERENCES users (uid) ON DELETE CASCADE
+);
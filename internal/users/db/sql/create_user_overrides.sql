@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_overrides (
+    uid          INT PRIMARY KEY,
+    shell        TEXT NOT NULL DEFAULT '', -- The login shell set locally, empty if not overridden
+    dir          TEXT NOT NULL DEFAULT '', -- The home directory set locally, empty if not overridden
+    gecos        TEXT NOT NULL DEFAULT '', -- The GECOS field set locally, empty if not overridden
+    local_groups TEXT NOT NULL DEFAULT '', -- The comma-separated local groups the user is always added to
+    FOREIGN KEY (uid) REFERENCES users (uid) ON DELETE CASCADE
+);
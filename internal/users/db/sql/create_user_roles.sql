@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS user_roles (
+    uid  INT NOT NULL,
+    role TEXT NOT NULL, -- A role of the user provided by their broker on their last login
+    PRIMARY KEY (uid, role),
+    FOREIGN KEY (uid) REFERENCES users (uid) ON DELETE CASCADE
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS users_to_auth_modes (
+    uid       INT NOT NULL,
+    broker_id TEXT NOT NULL,
+    auth_mode TEXT NOT NULL,  -- The authentication mode last successfully used with the broker
+    PRIMARY KEY (uid, broker_id),
+    FOREIGN KEY (uid) REFERENCES users (uid) ON DELETE CASCADE
+);
@@ -60,14 +60,139 @@ func Z_ForTests_DumpNormalizedYAML(c *Manager) (string, error) {
 		return userGroups[i].UID < userGroups[j].UID
 	})
 
+	// Get all rows from the users_to_auth_modes table.
+	userAuthModes, err := allUserAuthModes(c.db)
+	if err != nil {
+		return "", err
+	}
+
+	// Sort the userAuthModes by UID.
+	sort.Slice(userAuthModes, func(i, j int) bool {
+		if userAuthModes[i].UID == userAuthModes[j].UID {
+			return userAuthModes[i].BrokerID < userAuthModes[j].BrokerID
+		}
+		return userAuthModes[i].UID < userAuthModes[j].UID
+	})
+
+	// Get all rows from the local_pins table.
+	localPINs, err := allLocalPINs(c.db)
+	if err != nil {
+		return "", err
+	}
+
+	// Sort the localPINs by UID.
+	sort.Slice(localPINs, func(i, j int) bool {
+		return localPINs[i].UID < localPINs[j].UID
+	})
+
+	// Get all rows from the avatars table.
+	avatars, err := allAvatars(c.db)
+	if err != nil {
+		return "", err
+	}
+
+	// Sort the avatars by UID, and store their content as strings, which are marshalled as binary if needed.
+	sort.Slice(avatars, func(i, j int) bool {
+		return avatars[i].UID < avatars[j].UID
+	})
+	var avatarRows []avatarYAMLRow
+	for _, a := range avatars {
+		avatarRows = append(avatarRows, avatarYAMLRow{UID: a.UID, URL: a.URL, Content: string(a.Content)})
+	}
+
+	// Get all rows from the user_overrides table.
+	userOverrides, err := allUserOverrideRows(c.db)
+	if err != nil {
+		return "", err
+	}
+
+	// Sort the userOverrides by UID.
+	sort.Slice(userOverrides, func(i, j int) bool {
+		return userOverrides[i].UID < userOverrides[j].UID
+	})
+
+	// Get all rows from the consents table.
+	consents, err := allConsents(c.db)
+	if err != nil {
+		return "", err
+	}
+
+	// Sort the consents by UID.
+	sort.Slice(consents, func(i, j int) bool {
+		return consents[i].UID < consents[j].UID
+	})
+
+	// Get all rows from the deleted_users table.
+	deletedUsers, err := allDeletedUsers(c.db)
+	if err != nil {
+		return "", err
+	}
+
+	// Sort the deletedUsers by UID, and reset their deletion time, which depends on when the tests ran.
+	sort.Slice(deletedUsers, func(i, j int) bool {
+		return deletedUsers[i].UID < deletedUsers[j].UID
+	})
+	for i := range deletedUsers {
+		deletedUsers[i].DeletedAt = 0
+	}
+
+	// Get all rows from the adopted_users table.
+	adoptedUsers, err := allAdoptedUsers(c.db)
+	if err != nil {
+		return "", err
+	}
+
+	// Sort the adoptedUsers by UID.
+	sort.Slice(adoptedUsers, func(i, j int) bool {
+		return adoptedUsers[i].UID < adoptedUsers[j].UID
+	})
+
+	// Get all rows from the broker_selections table.
+	brokerSelections, err := allBrokerSelections(c.db)
+	if err != nil {
+		return "", err
+	}
+
+	// Sort the brokerSelections by UID, and reset their selection time, which depends on when the tests ran.
+	sort.Slice(brokerSelections, func(i, j int) bool {
+		return brokerSelections[i].UID < brokerSelections[j].UID
+	})
+	for i := range brokerSelections {
+		brokerSelections[i].SelectedAt = 0
+	}
+
+	// Get all rows from the user_roles table, sorted by UID and role.
+	userRoles, err := allUserRoles(c.db)
+	if err != nil {
+		return "", err
+	}
+
 	content := struct {
-		Users         []UserRow        `yaml:"users"`
-		Groups        []GroupRow       `yaml:"groups"`
-		UsersToGroups []userToGroupRow `yaml:"users_to_groups"`
+		Users            []UserRow            `yaml:"users"`
+		Groups           []GroupRow           `yaml:"groups"`
+		UsersToGroups    []userToGroupRow     `yaml:"users_to_groups"`
+		UsersToAuthModes []userToAuthModeRow  `yaml:"users_to_auth_modes,omitempty"`
+		LocalPINs        []LocalPINRow        `yaml:"local_pins,omitempty"`
+		Avatars          []avatarYAMLRow      `yaml:"avatars,omitempty"`
+		UserOverrides    []userOverrideRow    `yaml:"user_overrides,omitempty"`
+		Consents         []ConsentRow         `yaml:"consents,omitempty"`
+		DeletedUsers     []DeletedUserRow     `yaml:"deleted_users,omitempty"`
+		AdoptedUsers     []AdoptedUserRow     `yaml:"adopted_users,omitempty"`
+		UserRoles        []userRoleRow        `yaml:"user_roles,omitempty"`
+		BrokerSelections []brokerSelectionRow `yaml:"broker_selections,omitempty"`
 	}{
-		Users:         users,
-		Groups:        groups,
-		UsersToGroups: userGroups,
+		Users:            users,
+		Groups:           groups,
+		UsersToGroups:    userGroups,
+		UsersToAuthModes: userAuthModes,
+		LocalPINs:        localPINs,
+		Avatars:          avatarRows,
+		UserOverrides:    userOverrides,
+		Consents:         consents,
+		DeletedUsers:     deletedUsers,
+		AdoptedUsers:     adoptedUsers,
+		UserRoles:        userRoles,
+		BrokerSelections: brokerSelections,
 	}
 
 	// Marshal the content into a YAML string.
@@ -79,6 +204,13 @@ func Z_ForTests_DumpNormalizedYAML(c *Manager) (string, error) {
 	return string(yamlData), nil
 }
 
+// avatarYAMLRow is a row of the avatars table, as dumped in YAML.
+type avatarYAMLRow struct {
+	UID     uint32
+	URL     string
+	Content string
+}
+
 // Z_ForTests_DBName returns the name of the database.
 //
 // nolint:revive,nolintlint // We want to use underscores in the function name here.
@@ -138,7 +270,7 @@ func createDBFromYAMLReader(r io.Reader, destDir string) (err error) {
 		}
 	}()
 
-	tablesInOrder := []string{"users", "groups", "users_to_groups"}
+	tablesInOrder := []string{"users", "groups", "users_to_groups", "users_to_auth_modes", "local_pins", "avatars", "user_overrides", "consents", "deleted_users", "adopted_users", "user_roles", "broker_selections"}
 
 	// Insert data
 	for _, table := range tablesInOrder {
@@ -2,26 +2,34 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/mattn/go-sqlite3"
+	"github.com/ubuntu/authd/internal/faults"
 	"github.com/ubuntu/authd/log"
 )
 
 // UpdateUserEntry inserts or updates user and group records from the user information.
-func (m *Manager) UpdateUserEntry(user UserRow, authdGroups []GroupRow, localGroups []string) (err error) {
-	// Start a transaction
-	tx, err := m.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
+// Nothing is written if ctx is done before the update is queued, but it can't be withdrawn from its batch afterwards.
+func (m *Manager) UpdateUserEntry(ctx context.Context, user UserRow, authdGroups []GroupRow, localGroups []string) (err error) {
+	if _, err := faults.Inject(ctx, faults.CacheWrite, nil); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	// Ensure the transaction is committed or rolled back
-	defer func() {
-		err = commitOrRollBackTransaction(err, tx)
-	}()
+	// The update is committed with the other writes made at the same time.
+	return m.writes.write(func(tx *sql.Tx) error {
+		return updateUserEntry(tx, user, authdGroups, localGroups)
+	})
+}
 
+// updateUserEntry inserts or updates user and group records in the transaction.
+func updateUserEntry(tx *sql.Tx, user UserRow, authdGroups []GroupRow, localGroups []string) error {
 	/* 1. Handle user update */
 	if err := handleUserUpdate(tx, user); err != nil {
 		return err
@@ -150,10 +158,37 @@ func handleUsersToLocalGroupsUpdate(db queryable, uid uint32, localGroups []stri
 
 // UpdateBrokerForUser updates the last broker the user successfully authenticated with.
 func (m *Manager) UpdateBrokerForUser(username, brokerID string) error {
-	query := `UPDATE users SET broker_id = ? WHERE name = ?`
-	res, err := m.db.Exec(query, brokerID, username)
+	return m.writes.write(func(tx *sql.Tx) error {
+		query := `UPDATE users SET broker_id = ? WHERE name = ?`
+		res, err := tx.Exec(query, brokerID, username)
+		if err != nil {
+			return fmt.Errorf("failed to update broker for user: %w", err)
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return NoDataFoundError{table: "users", key: username}
+		}
+
+		query = `INSERT INTO broker_selections (uid, selected_at)
+			SELECT uid, ? FROM users WHERE name = ?
+			ON CONFLICT (uid) DO UPDATE SET selected_at = excluded.selected_at`
+		if _, err := tx.Exec(query, time.Now().Unix(), username); err != nil {
+			return fmt.Errorf("failed to record broker selection time: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UpdateGecosForUser updates the GECOS field of the user.
+func (m *Manager) UpdateGecosForUser(username, gecos string) error {
+	query := `UPDATE users SET gecos = ? WHERE name = ?`
+	res, err := m.db.Exec(query, gecos, username)
 	if err != nil {
-		return fmt.Errorf("failed to update broker for user: %w", err)
+		return fmt.Errorf("failed to update GECOS for user: %w", err)
 	}
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
@@ -6,7 +6,11 @@ import (
 
 // UserLocalGroups returns all local groups for a given user or an error if the database is corrupted or no entry was found.
 func (m *Manager) UserLocalGroups(uid uint32) ([]string, error) {
-	rows, err := m.db.Query(`SELECT group_name FROM users_to_local_groups WHERE uid = ?`, uid)
+	return userLocalGroups(m.db, uid)
+}
+
+func userLocalGroups(db queryable, uid uint32) ([]string, error) {
+	rows, err := db.Query(`SELECT group_name FROM users_to_local_groups WHERE uid = ?`, uid)
 	if err != nil {
 		return nil, fmt.Errorf("query error: %w", err)
 	}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"iter"
 	"strconv"
+	"time"
 
 	"github.com/ubuntu/authd/log"
 )
@@ -62,8 +64,12 @@ func userByID(db queryable, uid uint32) (UserRow, error) {
 
 // UserByName returns a user matching this name or an error if the database is corrupted or no entry was found.
 func (m *Manager) UserByName(name string) (UserRow, error) {
+	return userByName(m.db, name)
+}
+
+func userByName(db queryable, name string) (UserRow, error) {
 	query := fmt.Sprintf(`SELECT %s FROM users WHERE name = ?`, publicUserColumns)
-	row := m.db.QueryRow(query, name)
+	row := db.QueryRow(query, name)
 
 	var u UserRow
 	err := row.Scan(&u.Name, &u.UID, &u.GID, &u.Gecos, &u.Dir, &u.Shell, &u.BrokerID)
@@ -83,31 +89,52 @@ func (m *Manager) AllUsers() ([]UserRow, error) {
 }
 
 func allUsers(db queryable) ([]UserRow, error) {
-	query := fmt.Sprintf(`SELECT %s FROM users`, allUserColumns)
-	rows, err := db.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("query error: %w", err)
-	}
-	defer closeRows(rows)
-
 	var users []UserRow
-	for rows.Next() {
-		var u UserRow
-		err := rows.Scan(&u.Name, &u.UID, &u.GID, &u.Gecos, &u.Dir, &u.Shell, &u.BrokerID)
+	for u, err := range allUsersStream(db) {
 		if err != nil {
-			return nil, fmt.Errorf("scan error: %w", err)
+			return nil, err
 		}
 		users = append(users, u)
 	}
 
-	// Check for errors from iteration
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows iteration error: %w", err)
-	}
-
 	return users, nil
 }
 
+// AllUsersStream returns an iterator over all users, reading them one by one from the database instead of loading all
+// of them in memory. If the database is corrupted, the error is yielded and the iteration stops.
+func (m *Manager) AllUsersStream() iter.Seq2[UserRow, error] {
+	return allUsersStream(m.db)
+}
+
+func allUsersStream(db queryable) iter.Seq2[UserRow, error] {
+	return func(yield func(UserRow, error) bool) {
+		query := fmt.Sprintf(`SELECT %s FROM users`, allUserColumns)
+		rows, err := db.Query(query)
+		if err != nil {
+			yield(UserRow{}, fmt.Errorf("query error: %w", err))
+			return
+		}
+		defer closeRows(rows)
+
+		for rows.Next() {
+			var u UserRow
+			err := rows.Scan(&u.Name, &u.UID, &u.GID, &u.Gecos, &u.Dir, &u.Shell, &u.BrokerID)
+			if err != nil {
+				yield(UserRow{}, fmt.Errorf("scan error: %w", err))
+				return
+			}
+			if !yield(u, nil) {
+				return
+			}
+		}
+
+		// Check for errors from iteration
+		if err = rows.Err(); err != nil {
+			yield(UserRow{}, fmt.Errorf("rows iteration error: %w", err))
+		}
+	}
+}
+
 // insertOrUpdateUserByID inserts or, if a user with the same name or UID already exists, updates the user in the database.
 func insertOrUpdateUserByID(db queryable, u UserRow) error {
 	exists, err := userExists(db, u)
@@ -165,20 +192,75 @@ func updateUserByID(db queryable, u UserRow) error {
 	return nil
 }
 
-// DeleteUser removes the user from the database.
-func (m *Manager) DeleteUser(uid uint32) error {
-	query := `DELETE FROM users WHERE uid = ?`
-	res, err := m.db.Exec(query, uid)
+// DeleteUser removes the user from the database. Its UID is archived with its name, so that it's not given to a new
+// user which would then own the files of the deleted one.
+func (m *Manager) DeleteUser(uid uint32) (err error) {
+	tx, err := m.db.Begin()
 	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		err = commitOrRollBackTransaction(err, tx)
+	}()
+
+	u, err := userByID(tx, uid)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM users WHERE uid = ?`, uid); err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
-	rowsAffected, err := res.RowsAffected()
+
+	return archiveDeletedUser(tx, u, time.Now())
+}
+
+// userUIDTables are the tables referencing the users by UID, which are updated when the UID of a user changes.
+var userUIDTables = []string{
+	"users",
+	"users_to_groups",
+	"users_to_local_groups",
+	"users_to_auth_modes",
+	"user_overrides",
+	"local_pins",
+	"consents",
+	"avatars",
+	"adopted_users",
+	"user_roles",
+	"broker_selections",
+}
+
+// ChangeUserUID changes the UID of the user in all the tables referencing it. The previous UID is archived like the
+// ones of the deleted users, so that it's not given to a new user while files are still owned by it.
+func (m *Manager) ChangeUserUID(oldUID, newUID uint32) (err error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		err = commitOrRollBackTransaction(err, tx)
+	}()
+
+	u, err := userByID(tx, oldUID)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return err
 	}
-	if rowsAffected == 0 {
-		return NoDataFoundError{table: "users", key: strconv.FormatUint(uint64(uid), 10)}
+
+	// The references to the user are only consistent again once all the tables are updated.
+	if _, err := tx.Exec(`PRAGMA defer_foreign_keys = ON`); err != nil {
+		return fmt.Errorf("failed to defer foreign keys checks: %w", err)
+	}
+	for _, table := range userUIDTables {
+		//nolint:gosec // The table names are constants.
+		if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET uid = ? WHERE uid = ?`, table), newUID, oldUID); err != nil {
+			return fmt.Errorf("failed to update UID in table %q: %w", table, err)
+		}
 	}
 
-	return nil
+	// The new UID is not archived anymore if it belonged to the user before.
+	if _, err := tx.Exec(`DELETE FROM deleted_users WHERE uid = ? AND name = ?`, newUID, u.Name); err != nil {
+		return fmt.Errorf("failed to remove archived UID: %w", err)
+	}
+
+	return archiveDeletedUser(tx, u, time.Now())
 }
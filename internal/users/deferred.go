@@ -0,0 +1,116 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/ubuntu/authd/internal/users/journal"
+	"github.com/ubuntu/authd/internal/users/localentries"
+	"github.com/ubuntu/authd/log"
+)
+
+// deferredUpdatesRetryInterval is the delay between two attempts to apply the local groups updates deferred because
+// the group file was locked.
+const deferredUpdatesRetryInterval = time.Minute
+
+// deferLocalGroupsUpdate queues the update of the local groups of the user, which is kept in the journal, to be applied
+// in the background once the group file isn't locked anymore, instead of failing the login of the user.
+func (m *Manager) deferLocalGroupsUpdate(username string) {
+	m.deferredUpdatesMu.Lock()
+	defer m.deferredUpdatesMu.Unlock()
+
+	m.deferredUpdates[username] = struct{}{}
+	if m.deferredUpdatesRunning {
+		return
+	}
+	m.deferredUpdatesRunning = true
+	go m.retryDeferredUpdates()
+}
+
+// retryDeferredUpdates applies the deferred updates periodically, until there are none left or the manager is stopped.
+func (m *Manager) retryDeferredUpdates() {
+	ticker := time.NewTicker(deferredUpdatesRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if m.applyDeferredUpdates() == 0 {
+			return
+		}
+	}
+}
+
+// applyDeferredUpdates applies the deferred updates of the local groups and returns how many are still deferred.
+func (m *Manager) applyDeferredUpdates() (remaining int) {
+	m.deferredUpdatesMu.Lock()
+	usernames := slices.Collect(maps.Keys(m.deferredUpdates))
+	m.deferredUpdatesMu.Unlock()
+
+	for _, username := range usernames {
+		m.applyDeferredUpdate(username)
+	}
+
+	m.deferredUpdatesMu.Lock()
+	defer m.deferredUpdatesMu.Unlock()
+	remaining = len(m.deferredUpdates)
+	if remaining == 0 {
+		m.deferredUpdatesRunning = false
+	}
+	return remaining
+}
+
+// applyDeferredUpdate applies the deferred update of the local groups of the user, which stays deferred if the group
+// file is still locked.
+func (m *Manager) applyDeferredUpdate(username string) {
+	// The journal entry of the user can't change while it's applied, nor the update be deferred again.
+	unlock := m.userLocks.lock(username)
+	defer unlock()
+
+	if m.tryDeferredUpdate(username) {
+		return
+	}
+
+	m.deferredUpdatesMu.Lock()
+	defer m.deferredUpdatesMu.Unlock()
+	delete(m.deferredUpdates, username)
+}
+
+// tryDeferredUpdate applies the update of the local groups of the user kept in the journal, and returns whether it
+// must be retried later. It must be called with the lock of the user held.
+func (m *Manager) tryDeferredUpdate(username string) (retry bool) {
+	ops, err := m.journal.Pending()
+	if err != nil {
+		log.Warningf(context.Background(), "Could not apply deferred update of local groups of user %q: %v", username, err)
+		return true
+	}
+	i := slices.IndexFunc(ops, func(op journal.Operation) bool { return op.User == username })
+	if i < 0 {
+		// A later update of the user completed it.
+		return false
+	}
+
+	err = localentries.Update(context.Background(), username, ops[i].LocalGroups, ops[i].OldLocalGroups)
+	if errors.Is(err, localentries.ErrGroupFileLocked) {
+		log.Debugf(context.Background(), "Group file still locked, deferring update of local groups of user %q again", username)
+		return true
+	}
+	if err != nil {
+		// The update stays in the journal, so that it's retried on the next start.
+		log.Warningf(context.Background(), "Could not apply deferred update of local groups: %v", err)
+		return false
+	}
+
+	log.Infof(context.Background(), "Applied deferred update of local groups of user %q", username)
+	if err := m.journal.Done(username); err != nil {
+		log.Warningf(context.Background(), "%v", err)
+	}
+	return false
+}
@@ -19,15 +19,7 @@ func userEntryFromUserRow(u db.UserRow) types.UserEntry {
 
 // shadowEntryFromUserRow returns a ShadowEntry from a UserRow.
 func shadowEntryFromUserRow(u db.UserRow) types.ShadowEntry {
-	return types.ShadowEntry{
-		Name:           u.Name,
-		LastPwdChange:  -1,
-		MaxPwdAge:      -1,
-		PwdWarnPeriod:  -1,
-		PwdInactivity:  -1,
-		MinPwdAge:      -1,
-		ExpirationDate: -1,
-	}
+	return types.NewShadowEntry(u.Name)
 }
 
 // groupEntryFromGroupWithMembers returns a GroupEntry from a GroupRow.
@@ -41,3 +33,9 @@ func groupEntryFromGroupWithMembers(g db.GroupWithMembers) types.GroupEntry {
 
 // NoDataFoundError is the error returned when no entry is found in the db.
 type NoDataFoundError = db.NoDataFoundError
+
+// UserOverride are the attributes of a user set locally, which take precedence over the ones provided by the broker.
+type UserOverride = db.UserOverride
+
+// DatabaseDump is the whole content of the database.
+type DatabaseDump = db.Dump
@@ -0,0 +1,74 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/tempentries"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// maxDeletedUIDAttempts is the number of UIDs generated before giving up if they all belonged to deleted users.
+const maxDeletedUIDAttempts = 100
+
+// UIDOwner is the user a UID belongs to, or belonged to before it was deleted.
+type UIDOwner struct {
+	Name string
+	// DeletedAt is when the user was deleted, or the zero time if it still exists.
+	DeletedAt time.Time
+}
+
+// UIDOwner returns the user the UID belongs to, or the deleted user it belonged to, so that the files owned by the
+// UID can be attributed.
+func (m *Manager) UIDOwner(uid uint32) (o UIDOwner, err error) {
+	defer decorate.OnError(&err, "could not find the owner of UID %d", uid)
+
+	u, err := m.db.UserByID(uid)
+	if err == nil {
+		return UIDOwner{Name: u.Name}, nil
+	}
+	if !errors.Is(err, db.NoDataFoundError{}) {
+		return UIDOwner{}, err
+	}
+
+	d, err := m.db.DeletedUserByID(uid)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return UIDOwner{}, NoDataFoundError{}
+	}
+	if err != nil {
+		return UIDOwner{}, err
+	}
+	return UIDOwner{Name: d.Name, DeletedAt: time.Unix(d.DeletedAt, 0)}, nil
+}
+
+// deletedUIDsSkipper is an ID generator which never generates the UIDs of the deleted users, so that their files
+// are not owned by new users.
+type deletedUIDsSkipper struct {
+	tempentries.IDGenerator
+	m *Manager
+}
+
+// GenerateUID generates a UID with the wrapped generator, until it didn't belong to a deleted user.
+func (g deletedUIDsSkipper) GenerateUID(brokerName string) (uint32, error) {
+	for range maxDeletedUIDAttempts {
+		uid, err := g.IDGenerator.GenerateUID(brokerName)
+		if err != nil {
+			return 0, err
+		}
+
+		d, err := g.m.db.DeletedUserByID(uid)
+		if errors.Is(err, db.NoDataFoundError{}) {
+			return uid, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		log.Infof(context.Background(), "UID %d belonged to the deleted user %q, generating another one", uid, d.Name)
+	}
+
+	return 0, fmt.Errorf("could not generate a UID which did not belong to a deleted user after %d attempts", maxDeletedUIDAttempts)
+}
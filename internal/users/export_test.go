@@ -7,3 +7,11 @@ import (
 func (m *Manager) TemporaryRecords() *tempentries.TemporaryRecords {
 	return m.temporaryRecords
 }
+
+func (m *Manager) ApplyDeferredUpdates() int {
+	return m.applyDeferredUpdates()
+}
+
+func (m *Manager) LockedUsers() int {
+	return m.userLocks.len()
+}
@@ -0,0 +1,117 @@
+// Package gecos handles the structured subfields of the GECOS field of the users, as set by chfn.
+package gecos
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Fields are the subfields of the GECOS field, separated by commas.
+type Fields struct {
+	FullName  string `json:"full_name,omitempty"`
+	Room      string `json:"room,omitempty"`
+	WorkPhone string `json:"work_phone,omitempty"`
+	HomePhone string `json:"home_phone,omitempty"`
+
+	// Other is the rest of the GECOS field after the home phone, which is kept as is.
+	Other string `json:"-"`
+}
+
+// Update is a change of some of the subfields of the GECOS field. The nil fields are kept unchanged.
+type Update struct {
+	FullName  *string
+	Room      *string
+	WorkPhone *string
+	HomePhone *string
+}
+
+// Parse returns the subfields of the GECOS field.
+func Parse(gecos string) Fields {
+	parts := strings.SplitN(gecos, ",", 5)
+	parts = append(parts, make([]string, 5-len(parts))...)
+	return Fields{
+		FullName:  parts[0],
+		Room:      parts[1],
+		WorkPhone: parts[2],
+		HomePhone: parts[3],
+		Other:     parts[4],
+	}
+}
+
+// String returns the GECOS field made of the subfields, without the trailing empty ones.
+func (f Fields) String() string {
+	return strings.TrimRight(strings.Join([]string{f.FullName, f.Room, f.WorkPhone, f.HomePhone, f.Other}, ","), ",")
+}
+
+// Override returns the subfields with the non-empty ones of other taking precedence.
+func (f Fields) Override(other Fields) Fields {
+	for _, v := range []struct {
+		dst *string
+		src string
+	}{
+		{&f.FullName, other.FullName},
+		{&f.Room, other.Room},
+		{&f.WorkPhone, other.WorkPhone},
+		{&f.HomePhone, other.HomePhone},
+		{&f.Other, other.Other},
+	} {
+		if v.src != "" {
+			*v.dst = v.src
+		}
+	}
+	return f
+}
+
+// Apply returns the subfields changed by the update.
+func (f Fields) Apply(u Update) Fields {
+	for _, v := range []struct{ dst, src *string }{
+		{&f.FullName, u.FullName},
+		{&f.Room, u.Room},
+		{&f.WorkPhone, u.WorkPhone},
+		{&f.HomePhone, u.HomePhone},
+	} {
+		if v.src != nil {
+			*v.dst = *v.src
+		}
+	}
+	return f
+}
+
+// Validate checks that the subfields can be stored in the GECOS field, with the same rules as chfn.
+func (f Fields) Validate() error {
+	return validate(Update{FullName: &f.FullName, Room: &f.Room, WorkPhone: &f.WorkPhone, HomePhone: &f.HomePhone})
+}
+
+// Validate checks that the update changes some subfields, and that they can be stored in the GECOS field with the
+// same rules as chfn.
+func (u Update) Validate() error {
+	if u == (Update{}) {
+		return errors.New("no field to change")
+	}
+	return validate(u)
+}
+
+func validate(u Update) error {
+	for _, v := range []struct {
+		name  string
+		value *string
+	}{
+		{"full name", u.FullName},
+		{"room", u.Room},
+		{"work phone", u.WorkPhone},
+		{"home phone", u.HomePhone},
+	} {
+		if v.value == nil {
+			continue
+		}
+		if strings.ContainsAny(*v.value, `,:="`) {
+			return fmt.Errorf(`%s %q contains one of the forbidden characters ',', ':', '=' or '"'`, v.name, *v.value)
+		}
+		if strings.ContainsFunc(*v.value, unicode.IsControl) {
+			return fmt.Errorf("%s %q contains control characters", v.name, *v.value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package gecos_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/users/gecos"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		gecos string
+
+		want gecos.Fields
+	}{
+		"Parse_empty_GECOS":               {},
+		"Parse_full_name_only":            {gecos: "John Doe", want: gecos.Fields{FullName: "John Doe"}},
+		"Parse_multi-line_full_name":      {gecos: "John\nDoe", want: gecos.Fields{FullName: "John\nDoe"}},
+		"Parse_some_fields":               {gecos: "John Doe,,+1 555", want: gecos.Fields{FullName: "John Doe", WorkPhone: "+1 555"}},
+		"Parse_all_fields":                {gecos: "John Doe,B12,+1 555,+1 666", want: gecos.Fields{FullName: "John Doe", Room: "B12", WorkPhone: "+1 555", HomePhone: "+1 666"}},
+		"Parse_other_fields_after_phones": {gecos: "John Doe,B12,,,other,fields", want: gecos.Fields{FullName: "John Doe", Room: "B12", Other: "other,fields"}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := gecos.Parse(tc.gecos)
+			require.Equal(t, tc.want, got, "Parse returned unexpected fields")
+			require.Equal(t, tc.gecos, got.String(), "String should return the parsed GECOS")
+		})
+	}
+}
+
+func TestOverride(t *testing.T) {
+	t.Parallel()
+
+	local := gecos.Fields{FullName: "Local Name", Room: "B12", HomePhone: "+1 666"}
+
+	tests := map[string]struct {
+		other gecos.Fields
+
+		want gecos.Fields
+	}{
+		"Keep_fields_when_other_is_empty": {want: local},
+		"Override_fields_set_by_other": {
+			other: gecos.Fields{FullName: "Broker Name", WorkPhone: "+1 555"},
+			want:  gecos.Fields{FullName: "Broker Name", Room: "B12", WorkPhone: "+1 555", HomePhone: "+1 666"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, local.Override(tc.other), "Override returned unexpected fields")
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	empty, room := "", "C42"
+	f := gecos.Fields{FullName: "John Doe", Room: "B12", WorkPhone: "+1 555"}
+
+	got := f.Apply(gecos.Update{Room: &room, WorkPhone: &empty})
+	require.Equal(t, gecos.Fields{FullName: "John Doe", Room: "C42"}, got, "Apply should only change the given fields")
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	valid, comma, colon, equal, quote, newline := "John Doe", "Doe, John", "John:Doe", "John=Doe", `"John"`, "John\nDoe"
+
+	tests := map[string]struct {
+		update gecos.Update
+
+		wantErr bool
+	}{
+		"Valid_update":              {update: gecos.Update{FullName: &valid}},
+		"Valid_update_to_empty":     {update: gecos.Update{Room: new(string)}},
+		"Error_when_no_field_given": {wantErr: true},
+
+		"Error_when_field_contains_a_comma":          {update: gecos.Update{FullName: &comma}, wantErr: true},
+		"Error_when_field_contains_a_colon":          {update: gecos.Update{Room: &colon}, wantErr: true},
+		"Error_when_field_contains_an_equal_sign":    {update: gecos.Update{WorkPhone: &equal}, wantErr: true},
+		"Error_when_field_contains_a_quote":          {update: gecos.Update{HomePhone: &quote}, wantErr: true},
+		"Error_when_field_contains_control_chars":    {update: gecos.Update{FullName: &newline}, wantErr: true},
+		"Error_when_other_field_contains_a_comma":    {update: gecos.Update{FullName: &valid, Room: &comma}, wantErr: true},
+		"Error_when_other_field_contains_a_new_line": {update: gecos.Update{FullName: &valid, HomePhone: &newline}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.update.Validate()
+			if tc.wantErr {
+				require.Error(t, err, "Validate should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Validate should not return an error, but did")
+		})
+	}
+
+	require.NoError(t, gecos.Fields{FullName: valid}.Validate(), "Validate should accept valid fields")
+	require.Error(t, gecos.Fields{FullName: comma}.Validate(), "Validate should refuse fields with forbidden characters")
+}
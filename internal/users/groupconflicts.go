@@ -0,0 +1,70 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ubuntu/authd/internal/users/types"
+	"github.com/ubuntu/authd/log"
+)
+
+// The strategies handling the groups provided by the brokers whose name is already used by a local group.
+const (
+	// GroupConflictReject makes the login of the user fail.
+	GroupConflictReject = "reject"
+	// GroupConflictPrefix stores the group of the broker with its name prefixed.
+	GroupConflictPrefix = "prefix"
+	// GroupConflictUseLocal adds the user to the local group instead, with its GID.
+	GroupConflictUseLocal = "use_local"
+)
+
+// defaultGroupConflictPrefix is the prefix of the groups of the brokers renamed by the prefix strategy.
+const defaultGroupConflictPrefix = "authd-"
+
+// errLocalGroupConflict is returned when a group provided by a broker has the name of a local group.
+var errLocalGroupConflict = errors.New("group already exists on the system (but not in this authd instance)")
+
+// GroupConflictsConfig sets how the groups provided by the brokers whose name is already used by a local group, with
+// another GID, are handled.
+type GroupConflictsConfig struct {
+	// Strategy is one of GroupConflictReject, GroupConflictPrefix or GroupConflictUseLocal. An empty strategy rejects
+	// the conflicting groups.
+	Strategy string `mapstructure:"strategy"`
+	// Prefix is prepended to the names of the conflicting groups with the GroupConflictPrefix strategy.
+	Prefix string `mapstructure:"prefix"`
+}
+
+// check returns an error if the strategy is unknown, or if the prefix strategy has no prefix.
+func (c GroupConflictsConfig) check() error {
+	switch c.Strategy {
+	case "", GroupConflictReject, GroupConflictUseLocal:
+		return nil
+	case GroupConflictPrefix:
+		if c.Prefix == "" {
+			return errors.New("empty prefix for the group conflicts prefix strategy")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown group conflicts strategy %q", c.Strategy)
+	}
+}
+
+// resolveLocalGroupConflict returns the group to use instead of the given one of the broker, whose name is already
+// used by a local group, according to the configured strategy. The returned group has an empty UGID if the user must
+// be added to the local group instead.
+func (m *Manager) resolveLocalGroupConflict(g types.GroupInfo, conflictErr error) (types.GroupInfo, error) {
+	switch m.config.GroupConflicts.Strategy {
+	case GroupConflictPrefix:
+		name := m.config.GroupConflicts.Prefix + g.Name
+		log.Noticef(context.Background(), "Group %q already exists on the system, storing it as %q", g.Name, name)
+		g.Name = name
+		return g, m.checkGroupNameConflict(g.Name, g.UGID)
+	case GroupConflictUseLocal:
+		log.Noticef(context.Background(), "Group %q already exists on the system, using the local group instead", g.Name)
+		g.UGID = ""
+		return g, nil
+	default:
+		return g, conflictErr
+	}
+}
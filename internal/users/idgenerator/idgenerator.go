@@ -2,37 +2,129 @@
 package idgenerator
 
 import (
+	"cmp"
 	"crypto/rand"
+	"fmt"
 	"math/big"
+	"slices"
+	"strings"
 )
 
+// Range is an inclusive range of IDs.
+type Range struct {
+	Min uint32 `mapstructure:"min"`
+	Max uint32 `mapstructure:"max"`
+}
+
+// Ranges are the ranges in which UIDs and GIDs are generated.
+type Ranges struct {
+	UIDMin uint32 `mapstructure:"uid_min"`
+	UIDMax uint32 `mapstructure:"uid_max"`
+	GIDMin uint32 `mapstructure:"gid_min"`
+	GIDMax uint32 `mapstructure:"gid_max"`
+}
+
 // IDGenerator is an ID generator that generates UIDs and GIDs in a specific range.
 type IDGenerator struct {
 	UIDMin uint32
 	UIDMax uint32
 	GIDMin uint32
 	GIDMax uint32
+
+	// BrokerRanges overrides the ranges above for the users and groups of some brokers, indexed by broker name.
+	// Broker names are matched case-insensitively.
+	BrokerRanges map[string]Ranges
+
+	// ExcludedUIDs and ExcludedGIDs are never generated, for example because they are managed by another source like
+	// LDAP.
+	ExcludedUIDs []Range
+	ExcludedGIDs []Range
 }
 
-// GenerateUID generates a random UID in the configured range.
-func (g *IDGenerator) GenerateUID() (uint32, error) {
-	return generateID(g.UIDMin, g.UIDMax)
+// GenerateUID generates a random UID in the range configured for the broker.
+func (g *IDGenerator) GenerateUID(brokerName string) (uint32, error) {
+	r := g.rangesFor(brokerName)
+	return generateID(Range{Min: r.UIDMin, Max: r.UIDMax}, g.ExcludedUIDs)
 }
 
-// GenerateGID generates a random GID in the configured range.
-func (g *IDGenerator) GenerateGID() (uint32, error) {
-	return generateID(g.GIDMin, g.GIDMax)
+// GenerateGID generates a random GID in the range configured for the broker.
+func (g *IDGenerator) GenerateGID(brokerName string) (uint32, error) {
+	r := g.rangesFor(brokerName)
+	return generateID(Range{Min: r.GIDMin, Max: r.GIDMax}, g.ExcludedGIDs)
 }
 
-func generateID(minID, maxID uint32) (uint32, error) {
-	diff := int64(maxID - minID)
-	// Generate a cryptographically secure random number between 0 and diff
-	nBig, err := rand.Int(rand.Reader, big.NewInt(diff+1))
+// rangesFor returns the ranges configured for the broker, or the default ones if none are.
+func (g *IDGenerator) rangesFor(brokerName string) Ranges {
+	for name, r := range g.BrokerRanges {
+		if strings.EqualFold(name, brokerName) {
+			return r
+		}
+	}
+	return Ranges{UIDMin: g.UIDMin, UIDMax: g.UIDMax, GIDMin: g.GIDMin, GIDMax: g.GIDMax}
+}
+
+func generateID(r Range, excluded []Range) (uint32, error) {
+	available := availableRanges(r, excluded)
+
+	total := countIDs(available)
+	if total == 0 {
+		return 0, fmt.Errorf("all IDs between %d and %d are excluded", r.Min, r.Max)
+	}
+
+	// Generate a cryptographically secure random number between 0 and the number of available IDs
+	nBig, err := rand.Int(rand.Reader, new(big.Int).SetUint64(total))
 	if err != nil {
 		return 0, err
 	}
 
-	// Add minID to get a number in the desired range
-	//nolint:gosec // This conversion is safe because we only generate UIDs which are positive and smaller than uint32.
-	return uint32(nBig.Int64()) + minID, nil
+	// Map it to the available ranges
+	n := nBig.Uint64()
+	for _, a := range available {
+		size := uint64(a.Max-a.Min) + 1
+		if n < size {
+			//nolint:gosec // This conversion is safe because n is smaller than the size of a range of uint32.
+			return a.Min + uint32(n), nil
+		}
+		n -= size
+	}
+
+	// This can't happen, as n is smaller than the total of the sizes of the available ranges.
+	return 0, fmt.Errorf("could not generate an ID between %d and %d", r.Min, r.Max)
+}
+
+// CountAvailable returns the number of IDs of r which are not in any of the excluded ranges.
+func CountAvailable(r Range, excluded []Range) uint64 {
+	return countIDs(availableRanges(r, excluded))
+}
+
+func countIDs(ranges []Range) (n uint64) {
+	for _, r := range ranges {
+		n += uint64(r.Max-r.Min) + 1
+	}
+	return n
+}
+
+// availableRanges returns the disjoint ranges of IDs of r which are not in any of the excluded ranges, sorted.
+func availableRanges(r Range, excluded []Range) (available []Range) {
+	excluded = slices.Clone(excluded)
+	slices.SortFunc(excluded, func(a, b Range) int { return cmp.Compare(a.Min, b.Min) })
+
+	// We use an uint64 here, as the next ID after an excluded range may not fit in an uint32.
+	next := uint64(r.Min)
+	for _, e := range excluded {
+		if uint64(e.Max) < next || e.Min > r.Max {
+			continue
+		}
+		if uint64(e.Min) > next {
+			//nolint:gosec // This conversion is safe because next is smaller than e.Min.
+			available = append(available, Range{Min: uint32(next), Max: e.Min - 1})
+		}
+		next = uint64(e.Max) + 1
+	}
+	if next <= uint64(r.Max) {
+		//nolint:gosec // This conversion is safe because next is smaller than or equal to r.Max.
+		available = append(available, Range{Min: uint32(next), Max: r.Max})
+	}
+
+	return available
 }
@@ -1,6 +1,7 @@
 package idgenerator
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -10,22 +11,116 @@ func TestGenerateID(t *testing.T) {
 	t.Parallel()
 
 	tests := map[string]struct {
-		input string
-		idMin uint32
-		idMax uint32
+		idMin    uint32
+		idMax    uint32
+		excluded []Range
+
+		wantIDs []uint32
+		wantErr bool
 	}{
-		"Generated_ID_is_within_the_defined_range":        {input: "test", idMin: 1000, idMax: 2000},
-		"Generate_ID_with_minimum_ID_equal_to_maximum_ID": {input: "test", idMin: 1000, idMax: 1000},
+		"Generated_ID_is_within_the_defined_range":        {idMin: 1000, idMax: 2000},
+		"Generate_ID_with_minimum_ID_equal_to_maximum_ID": {idMin: 1000, idMax: 1000, wantIDs: []uint32{1000}},
+		"Generate_ID_with_maximum_ID_equal_to_the_maximum_uint32": {
+			idMin: math.MaxUint32 - 1, idMax: math.MaxUint32, excluded: []Range{{Min: math.MaxUint32 - 1, Max: math.MaxUint32 - 1}},
+			wantIDs: []uint32{math.MaxUint32},
+		},
+		"Generated_ID_is_not_in_the_excluded_ranges": {
+			idMin: 1000, idMax: 1010, excluded: []Range{{Min: 1003, Max: 1010}, {Min: 900, Max: 1001}},
+			wantIDs: []uint32{1002},
+		},
+		"Generated_ID_is_not_in_overlapping_excluded_ranges": {
+			idMin: 1000, idMax: 1010, excluded: []Range{{Min: 1000, Max: 1008}, {Min: 1002, Max: 1004}, {Min: 1010, Max: 1020}},
+			wantIDs: []uint32{1009},
+		},
+		"Excluded_ranges_outside_of_the_range_are_ignored": {
+			idMin: 1000, idMax: 1001, excluded: []Range{{Min: 0, Max: 999}, {Min: 1002, Max: 2000}},
+			wantIDs: []uint32{1000, 1001},
+		},
+
+		"Error_when_all_IDs_are_excluded": {idMin: 1000, idMax: 2000, excluded: []Range{{Min: 500, Max: 2500}}, wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			id, err := generateID(tc.idMin, tc.idMax)
-			require.NoError(t, err, "GenerateID should not have failed")
+			for range 10 {
+				id, err := generateID(Range{Min: tc.idMin, Max: tc.idMax}, tc.excluded)
+				if tc.wantErr {
+					require.Error(t, err, "GenerateID should have failed")
+					return
+				}
+				require.NoError(t, err, "GenerateID should not have failed")
+
+				require.GreaterOrEqual(t, id, tc.idMin, "GenerateID should return an ID greater or equal to the minimum")
+				require.LessOrEqual(t, id, tc.idMax, "GenerateID should return an ID less or equal to the maximum")
+				if tc.wantIDs != nil {
+					require.Contains(t, tc.wantIDs, id, "GenerateID should return one of the non excluded IDs")
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateUIDAndGID(t *testing.T) {
+	t.Parallel()
+
+	g := &IDGenerator{
+		UIDMin: 1000,
+		UIDMax: 1000,
+		GIDMin: 2000,
+		GIDMax: 2000,
+		BrokerRanges: map[string]Ranges{
+			"examplebroker": {UIDMin: 3000, UIDMax: 3000, GIDMin: 4000, GIDMax: 4001},
+		},
+		ExcludedGIDs: []Range{{Min: 4000, Max: 4000}},
+	}
+
+	tests := map[string]struct {
+		brokerName string
+
+		wantUID uint32
+		wantGID uint32
+	}{
+		"Generate_IDs_in_the_default_ranges_for_unknown_broker": {brokerName: "otherbroker", wantUID: 1000, wantGID: 2000},
+		"Generate_IDs_in_the_default_ranges_for_no_broker":      {wantUID: 1000, wantGID: 2000},
+		"Generate_IDs_in_the_ranges_of_the_broker":              {brokerName: "examplebroker", wantUID: 3000, wantGID: 4001},
+		"Broker_name_is_matched_case_insensitively":             {brokerName: "ExampleBroker", wantUID: 3000, wantGID: 4001},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			uid, err := g.GenerateUID(tc.brokerName)
+			require.NoError(t, err, "GenerateUID should not have failed")
+			require.Equal(t, tc.wantUID, uid, "GenerateUID should return the expected UID")
+
+			gid, err := g.GenerateGID(tc.brokerName)
+			require.NoError(t, err, "GenerateGID should not have failed")
+			require.Equal(t, tc.wantGID, gid, "GenerateGID should return the expected GID")
+		})
+	}
+}
+
+func TestCountAvailable(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		excluded []Range
+
+		want uint64
+	}{
+		"All_IDs_are_available_without_exclusions":      {want: 1001},
+		"Excluded_IDs_are_not_counted":                  {excluded: []Range{{Min: 1000, Max: 1099}, {Min: 1900, Max: 2500}}, want: 800},
+		"Overlapping_excluded_IDs_are_counted_once":     {excluded: []Range{{Min: 1100, Max: 1199}, {Min: 1150, Max: 1249}}, want: 851},
+		"No_IDs_are_available_if_all_are_excluded":      {excluded: []Range{{Min: 0, Max: math.MaxUint32}}, want: 0},
+		"Excluded_IDs_outside_of_the_range_are_ignored": {excluded: []Range{{Min: 0, Max: 999}, {Min: 2001, Max: 3000}}, want: 1001},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
 
-			require.GreaterOrEqual(t, id, tc.idMin, "GenerateID should return an ID greater or equal to the minimum")
-			require.LessOrEqual(t, id, tc.idMax, "GenerateID should return an ID less or equal to the maximum")
+			got := CountAvailable(Range{Min: 1000, Max: 2000}, tc.excluded)
+			require.Equal(t, tc.want, got, "CountAvailable should return the expected number of IDs")
 		})
 	}
 }
@@ -10,7 +10,7 @@ type IDGeneratorMock struct {
 }
 
 // GenerateUID generates a UID.
-func (g *IDGeneratorMock) GenerateUID() (uint32, error) {
+func (g *IDGeneratorMock) GenerateUID(string) (uint32, error) {
 	if len(g.UIDsToGenerate) == 0 {
 		return 0, fmt.Errorf("no more UIDs to generate")
 	}
@@ -20,7 +20,7 @@ func (g *IDGeneratorMock) GenerateUID() (uint32, error) {
 }
 
 // GenerateGID generates a GID.
-func (g *IDGeneratorMock) GenerateGID() (uint32, error) {
+func (g *IDGeneratorMock) GenerateGID(string) (uint32, error) {
 	if len(g.GIDsToGenerate) == 0 {
 		return 0, fmt.Errorf("no more GIDs to generate")
 	}
@@ -0,0 +1,138 @@
+// Package journal records the updates of the users which are in progress, so that the ones interrupted by a crash of
+// the daemon can be completed or discarded on the next start.
+//
+// An update of a user is committed to the database first, then to the local groups. The journal bridges the two: an
+// update is recorded before the database is changed and removed once the local groups are updated.
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/ubuntu/decorate"
+)
+
+// Filename is the name of the journal file in the directory of the database.
+const Filename = "pending-updates.json"
+
+// Operation is an update of a user which is in progress.
+type Operation struct {
+	User string `json:"user"`
+	UID  uint32 `json:"uid"`
+
+	// LocalGroups are the local groups the user is a member of once updated, and OldLocalGroups the ones it was a
+	// member of before the update.
+	LocalGroups    []string `json:"local_groups"`
+	OldLocalGroups []string `json:"old_local_groups"`
+}
+
+// Journal is the file recording the operations in progress.
+type Journal struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New returns the journal stored in dir. The file is only created when an operation is recorded.
+func New(dir string) *Journal {
+	return &Journal{path: filepath.Join(dir, Filename)}
+}
+
+// Begin records the operation, replacing any previous one of the same user.
+func (j *Journal) Begin(op Operation) (err error) {
+	defer decorate.OnError(&err, "could not record update of user %q in the journal", op.User)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	ops, err := j.read()
+	if err != nil {
+		return err
+	}
+	ops = slices.DeleteFunc(ops, func(o Operation) bool { return o.User == op.User })
+
+	return j.write(append(ops, op))
+}
+
+// Done removes the operation of the user from the journal once it's complete.
+func (j *Journal) Done(user string) (err error) {
+	defer decorate.OnError(&err, "could not remove update of user %q from the journal", user)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	ops, err := j.read()
+	if err != nil {
+		return err
+	}
+
+	return j.write(slices.DeleteFunc(ops, func(o Operation) bool { return o.User == user }))
+}
+
+// Pending returns the operations which were not completed.
+func (j *Journal) Pending() (ops []Operation, err error) {
+	defer decorate.OnError(&err, "could not read the journal")
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.read()
+}
+
+func (j *Journal) read() ([]Operation, error) {
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []Operation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// write atomically replaces the journal with the operations, or removes it if there are none, so that a crash while
+// writing it never leaves a truncated journal.
+func (j *Journal) write(ops []Operation) (err error) {
+	if len(ops) == 0 {
+		if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), Filename+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	defer tmp.Close()
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), j.path)
+}
@@ -0,0 +1,93 @@
+package journal_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/users/journal"
+)
+
+func TestJournal(t *testing.T) {
+	t.Parallel()
+
+	user1 := journal.Operation{User: "user1", UID: 1111, LocalGroups: []string{"localgroup1"}}
+	user1Again := journal.Operation{User: "user1", UID: 1111, LocalGroups: []string{"localgroup2"}, OldLocalGroups: []string{"localgroup1"}}
+	user2 := journal.Operation{User: "user2", UID: 2222}
+
+	tests := map[string]struct {
+		begin []journal.Operation
+		done  []string
+
+		wantPending []journal.Operation
+	}{
+		"No_pending_operations_in_a_new_journal": {},
+		"Record_an_operation":                    {begin: []journal.Operation{user1}, wantPending: []journal.Operation{user1}},
+		"Record_operations_of_several_users":     {begin: []journal.Operation{user1, user2}, wantPending: []journal.Operation{user1, user2}},
+		"Replace_the_operation_of_the_same_user": {begin: []journal.Operation{user1, user2, user1Again}, wantPending: []journal.Operation{user2, user1Again}},
+
+		"Remove_a_completed_operation":        {begin: []journal.Operation{user1, user2}, done: []string{"user1"}, wantPending: []journal.Operation{user2}},
+		"Remove_all_the_completed_operations": {begin: []journal.Operation{user1, user2}, done: []string{"user1", "user2"}},
+		"Ignore_unknown_completed_operation":  {begin: []journal.Operation{user1}, done: []string{"user2"}, wantPending: []journal.Operation{user1}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			j := journal.New(dir)
+
+			for _, op := range tc.begin {
+				require.NoError(t, j.Begin(op), "Begin should not return an error, but did")
+			}
+			for _, user := range tc.done {
+				require.NoError(t, j.Done(user), "Done should not return an error, but did")
+			}
+
+			// The pending operations are read from the file, as they would be after a restart.
+			got, err := journal.New(dir).Pending()
+			require.NoError(t, err, "Pending should not return an error, but did")
+			require.Equal(t, tc.wantPending, got, "Pending should return the operations which are not done")
+
+			if len(tc.wantPending) == 0 {
+				require.NoFileExists(t, filepath.Join(dir, journal.Filename), "The journal should be removed when empty")
+			}
+		})
+	}
+}
+
+func TestJournalErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content string
+		noDir   bool
+	}{
+		"Error_on_corrupted_journal":        {content: "not json"},
+		"Error_if_directory_does_not_exist": {noDir: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if tc.content != "" {
+				err := os.WriteFile(filepath.Join(dir, journal.Filename), []byte(tc.content), 0600)
+				require.NoError(t, err, "Setup: could not write journal")
+			}
+			if tc.noDir {
+				dir = filepath.Join(dir, "nonexistent")
+			}
+			j := journal.New(dir)
+
+			err := j.Begin(journal.Operation{User: "user1"})
+			require.Error(t, err, "Begin should return an error, but did not")
+
+			if tc.content != "" {
+				_, err = j.Pending()
+				require.Error(t, err, "Pending should return an error, but did not")
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package localentries
 
+import "time"
+
 // WithGroupPath overrides the default /etc/group path for tests.
 func WithGroupPath(p string) Option {
 	return func(o *options) {
@@ -20,3 +22,10 @@ func WithGetUsersFunc(getUsersFunc func() ([]string, error)) Option {
 		o.getUsersFunc = getUsersFunc
 	}
 }
+
+// WithLockWait overrides how long gpasswd is retried while the group file is locked for tests.
+func WithLockWait(d time.Duration) Option {
+	return func(o *options) {
+		o.lockWait = d
+	}
+}
@@ -11,32 +11,50 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ubuntu/authd/internal/sliceutils"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/decorate"
 )
 
+// ErrGroupFileLocked is returned when the group file stays locked by another process, like vigr or useradd, for longer
+// than the lock wait.
+var ErrGroupFileLocked = errors.New("group file is locked by another process")
+
+const (
+	// defaultLockWait is how long gpasswd is retried while the group file is locked by another process.
+	defaultLockWait = 10 * time.Second
+	// lockRetryInterval is the delay between two attempts while the group file is locked.
+	lockRetryInterval = 250 * time.Millisecond
+)
+
 var defaultOptions = options{
 	groupPath:    "/etc/group",
 	gpasswdCmd:   []string{"gpasswd"},
 	getUsersFunc: getPasswdUsernames,
+	lockWait:     defaultLockWait,
 }
 
 type options struct {
 	groupPath    string
 	gpasswdCmd   []string
 	getUsersFunc func() ([]string, error)
+	lockWait     time.Duration
 }
 
 // Option represents an optional function to override UpdateLocalGroups default values.
 type Option func(*options)
 
-var localGroupsMu = &sync.RWMutex{}
+// localGroupsMu serializes the updates of the group file, from reading the current groups of the user to running
+// gpasswd, so that concurrent updates of different users can't interleave.
+// It is not held while waiting for another process to release the lock of the group file.
+var localGroupsMu = &sync.Mutex{}
 
 // Update synchronizes for the given user the local group list with the current group list from UserInfo.
-func Update(username string, newGroups []string, oldGroups []string, args ...Option) (err error) {
-	log.Debugf(context.TODO(), "Updating local groups for user %q, new groups: %v, old groups: %v", username, newGroups, oldGroups)
+// It stops waiting for the group file to be unlocked when ctx is done, and returns the error of ctx.
+func Update(ctx context.Context, username string, newGroups []string, oldGroups []string, args ...Option) (err error) {
+	log.Debugf(ctx, "Updating local groups for user %q, new groups: %v, old groups: %v", username, newGroups, oldGroups)
 	defer decorate.OnError(&err, "could not update local groups for user %q", username)
 
 	opts := defaultOptions
@@ -44,19 +62,57 @@ func Update(username string, newGroups []string, oldGroups []string, args ...Opt
 		arg(&opts)
 	}
 
-	currentGroups, err := existingLocalGroups(username, opts.groupPath)
+	return withLocalGroupsLock(ctx, opts, func() error {
+		currentGroups, err := existingLocalGroups(username, opts.groupPath)
+		if err != nil {
+			return err
+		}
+
+		// Only add the user to the groups they are not part of yet, and only remove them from the ones they are part
+		// of, so that running the same update again doesn't change anything.
+		groupsToAdd := sliceutils.Difference(newGroups, currentGroups)
+		groupsToRemove := sliceutils.Difference(oldGroups, newGroups)
+		groupsToRemove = sliceutils.Intersection(groupsToRemove, currentGroups)
+
+		return applyChanges(username, groupsToAdd, groupsToRemove, opts)
+	})
+}
+
+// Reconcile adds the user to the local groups they should be part of but aren't, for example because the group file
+// was edited manually, and returns these groups.
+// The user is never removed from the other local groups, as the ones added by the administrator can't be told apart.
+func Reconcile(username string, groups []string, args ...Option) (added []string, err error) {
+	defer decorate.OnError(&err, "could not reconcile local groups of user %q", username)
+
+	opts := defaultOptions
+	for _, arg := range args {
+		arg(&opts)
+	}
+
+	err = withLocalGroupsLock(context.Background(), opts, func() error {
+		currentGroups, err := existingLocalGroups(username, opts.groupPath)
+		if err != nil {
+			return err
+		}
+
+		missing := sliceutils.Difference(groups, currentGroups)
+		// Keep the groups added by the previous attempts, that the group file being locked interrupted.
+		added = append(added, sliceutils.Difference(missing, added)...)
+		return applyChanges(username, missing, nil, opts)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return added, nil
+}
 
-	localGroupsMu.Lock()
-	defer localGroupsMu.Unlock()
-	groupsToAdd := sliceutils.Difference(newGroups, currentGroups)
-	log.Debugf(context.TODO(), "Adding to local groups: %v", groupsToAdd)
-	groupsToRemove := sliceutils.Difference(oldGroups, newGroups)
-	// Only remove user from groups which they are part of
-	groupsToRemove = sliceutils.Intersection(groupsToRemove, currentGroups)
-	log.Debugf(context.TODO(), "Removing from local groups: %v", groupsToRemove)
+// applyChanges adds the user to the groups to add and removes them from the groups to remove, then logs a summary of
+// the changes.
+func applyChanges(username string, groupsToAdd, groupsToRemove []string, opts options) error {
+	if len(groupsToAdd) == 0 && len(groupsToRemove) == 0 {
+		log.Debugf(context.TODO(), "Local groups of user %q are up to date", username)
+		return nil
+	}
 
 	for _, g := range groupsToRemove {
 		args := opts.gpasswdCmd[1:]
@@ -73,6 +129,7 @@ func Update(username string, newGroups []string, oldGroups []string, args ...Opt
 		}
 	}
 
+	log.Infof(context.TODO(), "Updated local groups of user %q: added to %v, removed from %v", username, groupsToAdd, groupsToRemove)
 	return nil
 }
 
@@ -91,11 +148,10 @@ func getPasswdUsernames() ([]string, error) {
 }
 
 // existingLocalGroups returns which groups from groupPath the user is part of.
+// The caller must hold localGroupsMu.
 func existingLocalGroups(user, groupPath string) (groups []string, err error) {
 	defer decorate.OnError(&err, "could not fetch existing local group")
 
-	localGroupsMu.RLock()
-	defer localGroupsMu.RUnlock()
 	f, err := os.Open(groupPath)
 	if err != nil {
 		return nil, err
@@ -140,23 +196,22 @@ func CleanUser(user string, args ...Option) (err error) {
 		arg(&opts)
 	}
 
-	// Get the list of local groups the user belong to
-	groups, err := existingLocalGroups(user, opts.groupPath)
-	if err != nil {
-		return err
-	}
-
-	localGroupsMu.Lock()
-	defer localGroupsMu.Unlock()
-	for _, group := range groups {
-		args := opts.gpasswdCmd[1:]
-		args = append(args, "--delete", user, group)
-		if err := runGPasswd(opts.gpasswdCmd[0], args...); err != nil {
+	return withLocalGroupsLock(context.Background(), opts, func() error {
+		// Get the list of local groups the user belong to
+		groups, err := existingLocalGroups(user, opts.groupPath)
+		if err != nil {
 			return err
 		}
-	}
+		for _, group := range groups {
+			args := opts.gpasswdCmd[1:]
+			args = append(args, "--delete", user, group)
+			if err := runGPasswd(opts.gpasswdCmd[0], args...); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // Clean removes all unexistent users from the local groups.
@@ -168,9 +223,6 @@ func Clean(args ...Option) (err error) {
 		arg(&opts)
 	}
 
-	localGroupsMu.Lock()
-	defer localGroupsMu.Unlock()
-
 	// Add the existingUsers to a map to speed up search
 	existingUsers := make(map[string]struct{})
 	usernames, err := opts.getUsersFunc()
@@ -186,6 +238,14 @@ func Clean(args ...Option) (err error) {
 		return errors.New("no existing users found, local groups won't be cleaned")
 	}
 
+	return withLocalGroupsLock(context.Background(), opts, func() error {
+		return cleanGroups(existingUsers, opts)
+	})
+}
+
+// cleanGroups removes the users not in existingUsers from the local groups.
+// The caller must hold localGroupsMu.
+func cleanGroups(existingUsers map[string]struct{}, opts options) (err error) {
 	// Get the list of local groups
 	f, err := os.Open(opts.groupPath)
 	if err != nil {
@@ -239,13 +299,43 @@ func Clean(args ...Option) (err error) {
 // are responsible for the user itself and parsing the output is not desired.
 func runGPasswd(cmdName string, args ...string) error {
 	cmd := exec.Command(cmdName, args...)
+	// Don't translate the output, as it's parsed to detect when the group file is locked.
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		if cmd.ProcessState.ExitCode() == 3 {
 			log.Infof(context.TODO(), "ignoring gpasswd error: %s", out)
 			return nil
 		}
+		// gpasswd fails right away if another process holds the lock of the group or gshadow file.
+		if strings.Contains(string(out), "cannot lock") {
+			return fmt.Errorf("%w: %q returned: %v\nOutput: %s", ErrGroupFileLocked, strings.Join(cmd.Args, " "), err, out)
+		}
 		return fmt.Errorf("%q returned: %v\nOutput: %s", strings.Join(cmd.Args, " "), err, out)
 	}
 	return nil
 }
+
+// withLocalGroupsLock runs f holding localGroupsMu, and runs it again while the group file is locked by another
+// process, until the lock wait of the options is over.
+// localGroupsMu is released while waiting, so f must read the group file again, as it may have changed meanwhile.
+// The wait stops early when ctx is done, but f is never interrupted, so that gpasswd doesn't leave the group file
+// half updated.
+func withLocalGroupsLock(ctx context.Context, opts options, f func() error) error {
+	deadline := time.Now().Add(opts.lockWait)
+	for {
+		localGroupsMu.Lock()
+		err := f()
+		localGroupsMu.Unlock()
+
+		if !errors.Is(err, ErrGroupFileLocked) || time.Now().Add(lockRetryInterval).After(deadline) {
+			return err
+		}
+		log.Debugf(ctx, "Group file is locked, retrying in %s", lockRetryInterval)
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
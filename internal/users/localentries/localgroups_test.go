@@ -1,9 +1,12 @@
 package localentries_test
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/ubuntu/authd/internal/testutils/golden"
@@ -20,8 +23,11 @@ func TestUpdatelocalentries(t *testing.T) {
 		newGroups     []string
 		oldGroups     []string
 		groupFilePath string
+		locked        bool
+		unlockAfter   time.Duration
 
-		wantErr bool
+		wantErr       bool
+		wantLockedErr bool
 	}{
 		// First insertion cases
 		"Insert_new_user_in_existing_files_with_no_users_in_our_group":             {groupFilePath: "no_users_in_our_groups.group"},
@@ -55,6 +61,10 @@ func TestUpdatelocalentries(t *testing.T) {
 		"Error_when_groups_file_is_malformed":         {groupFilePath: "malformed_file.group", wantErr: true},
 		"Error_on_any_unignored_add_gpasswd_error":    {username: "gpasswdfail", groupFilePath: "no_users.group", wantErr: true},
 		"Error_on_any_unignored_delete_gpasswd_error": {username: "gpasswdfail", groupFilePath: "gpasswdfail_in_deleted_group.group", wantErr: true},
+		"Error_when_group_file_stays_locked":          {groupFilePath: "no_users.group", locked: true, wantErr: true, wantLockedErr: true},
+
+		// Group file locked by another process
+		"Update_once_group_file_is_unlocked": {groupFilePath: "no_users.group", locked: true, unlockAfter: 300 * time.Millisecond},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -79,9 +89,19 @@ func TestUpdatelocalentries(t *testing.T) {
 				groupFilePath, destCmdsFile,
 			}
 
-			err := localentries.Update(tc.username, tc.newGroups, tc.oldGroups, localentries.WithGroupPath(groupFilePath), localentries.WithGpasswdCmd(cmdArgs))
+			if tc.locked {
+				require.NoError(t, os.WriteFile(destCmdsFile+".lock", nil, 0600), "Setup: could not lock the group file")
+			}
+			if tc.unlockAfter > 0 {
+				unlock := time.AfterFunc(tc.unlockAfter, func() { _ = os.Remove(destCmdsFile + ".lock") })
+				t.Cleanup(func() { unlock.Stop() })
+			}
+
+			err := localentries.Update(context.Background(), tc.username, tc.newGroups, tc.oldGroups, localentries.WithGroupPath(groupFilePath),
+				localentries.WithGpasswdCmd(cmdArgs), localentries.WithLockWait(time.Second))
 			if tc.wantErr {
 				require.Error(t, err, "Updatelocalentries should have failed")
+				require.Equal(t, tc.wantLockedErr, errors.Is(err, localentries.ErrGroupFileLocked), "Updatelocalentries should only return ErrGroupFileLocked when the group file is locked")
 			} else {
 				require.NoError(t, err, "Updatelocalentries should not have failed")
 			}
@@ -91,6 +111,122 @@ func TestUpdatelocalentries(t *testing.T) {
 	}
 }
 
+func TestUpdateWhileGroupFileIsLocked(t *testing.T) {
+	t.Parallel()
+
+	const lockWait = 2 * time.Second
+	groupFilePath := filepath.Join("testdata", "no_users.group")
+	gpasswdCmd := func(destCmdsFile string) []string {
+		return []string{"env", "GO_WANT_HELPER_PROCESS=1",
+			os.Args[0], "-test.run=TestMockgpasswd", "--",
+			groupFilePath, destCmdsFile,
+		}
+	}
+
+	lockedCmdsFile := filepath.Join(t.TempDir(), "gpasswd.output")
+	require.NoError(t, os.WriteFile(lockedCmdsFile+".lock", nil, 0600), "Setup: could not lock the group file")
+
+	start := time.Now()
+	lockedDone := make(chan error)
+	go func() {
+		// The user is added to two groups, but the lock wait applies to the whole update.
+		lockedDone <- localentries.Update(context.Background(), "lockeduser", []string{"localgroup1", "localgroup3"}, nil,
+			localentries.WithGroupPath(groupFilePath), localentries.WithGpasswdCmd(gpasswdCmd(lockedCmdsFile)),
+			localentries.WithLockWait(lockWait))
+	}()
+
+	// Let the first update wait for the lock of the group file.
+	time.Sleep(500 * time.Millisecond)
+
+	destCmdsFile := filepath.Join(t.TempDir(), "gpasswd.output")
+	err := localentries.Update(context.Background(), "myuser", []string{"localgroup1", "localgroup3"}, nil,
+		localentries.WithGroupPath(groupFilePath), localentries.WithGpasswdCmd(gpasswdCmd(destCmdsFile)))
+	require.NoError(t, err, "Update should not have failed")
+	select {
+	case err := <-lockedDone:
+		require.Fail(t, "Update should not wait for the other one, that is waiting for the group file lock", err)
+	default:
+	}
+
+	err = <-lockedDone
+	require.ErrorIs(t, err, localentries.ErrGroupFileLocked, "Update should fail when the group file stays locked")
+	require.Less(t, time.Since(start), lockWait+time.Second, "Update should only wait for the lock wait in total")
+
+	localentriestestutils.RequireGPasswdOutput(t, destCmdsFile, golden.Path(t))
+	require.NoFileExists(t, lockedCmdsFile, "Update should not have run gpasswd successfully while locked")
+}
+
+func TestUpdateStopsWaitingWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	groupFilePath := filepath.Join("testdata", "no_users.group")
+	destCmdsFile := filepath.Join(t.TempDir(), "gpasswd.output")
+	require.NoError(t, os.WriteFile(destCmdsFile+".lock", nil, 0600), "Setup: could not lock the group file")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := localentries.Update(ctx, "myuser", []string{"localgroup1"}, nil,
+		localentries.WithGroupPath(groupFilePath),
+		localentries.WithGpasswdCmd([]string{"env", "GO_WANT_HELPER_PROCESS=1",
+			os.Args[0], "-test.run=TestMockgpasswd", "--",
+			groupFilePath, destCmdsFile,
+		}),
+		localentries.WithLockWait(time.Minute))
+	require.ErrorIs(t, err, context.DeadlineExceeded, "Update should fail with the error of the context")
+	require.ErrorIs(t, err, localentries.ErrGroupFileLocked, "Update should report that the group file is locked")
+	require.Less(t, time.Since(start), 5*time.Second, "Update should stop waiting for the lock when the context is done")
+}
+
+func TestReconcileLocalGroups(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		username      string
+		groupFilePath string
+
+		wantAdded []string
+		wantErr   bool
+	}{
+		"Add_user_to_all_their_groups_when_in_none": {groupFilePath: "no_users.group", wantAdded: []string{"localgroup1", "localgroup3"}},
+		"Add_user_only_to_the_missing_group":        {groupFilePath: "user_in_one_group.group", wantAdded: []string{"localgroup3"}},
+		"No-Op_when_user_is_in_all_their_groups":    {groupFilePath: "user_in_both_groups.group"},
+		"Keep_user_in_other_groups":                 {groupFilePath: "user_in_many_groups.group", wantAdded: []string{"localgroup3"}},
+
+		"Error_on_missing_groups_file":         {groupFilePath: "does_not_exists.group", wantErr: true},
+		"Error_when_groups_file_is_malformed":  {groupFilePath: "malformed_file.group", wantErr: true},
+		"Error_on_any_unignored_gpasswd_error": {username: "gpasswdfail", groupFilePath: "no_users.group", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.username == "" {
+				tc.username = "myuser"
+			}
+
+			destCmdsFile := filepath.Join(t.TempDir(), "gpasswd.output")
+
+			groupFilePath := filepath.Join("testdata", tc.groupFilePath)
+			cmdArgs := []string{"env", "GO_WANT_HELPER_PROCESS=1",
+				os.Args[0], "-test.run=TestMockgpasswd", "--",
+				groupFilePath, destCmdsFile,
+			}
+
+			added, err := localentries.Reconcile(tc.username, []string{"localgroup1", "localgroup3"}, localentries.WithGroupPath(groupFilePath), localentries.WithGpasswdCmd(cmdArgs))
+			if tc.wantErr {
+				require.Error(t, err, "Reconcile should have failed")
+				return
+			}
+			require.NoError(t, err, "Reconcile should not have failed")
+			require.Equal(t, tc.wantAdded, added, "Reconcile should return the groups the user was added to")
+
+			localentriestestutils.RequireGPasswdOutput(t, destCmdsFile, golden.Path(t))
+		})
+	}
+}
+
 func TestCleanlocalentries(t *testing.T) {
 	t.Parallel()
 
@@ -1,6 +1,10 @@
 package localentries
 
-import "github.com/ubuntu/authd/internal/testsdetection"
+import (
+	"time"
+
+	"github.com/ubuntu/authd/internal/testsdetection"
+)
 
 var originalDefaultOptions = defaultOptions
 
@@ -34,3 +38,14 @@ func Z_ForTests_SetGpasswdCmd(gpasswdCmd []string) {
 
 	defaultOptions.gpasswdCmd = gpasswdCmd
 }
+
+// Z_ForTests_SetLockWait sets how long gpasswd is retried while the group file is locked for the defaultOptions.
+// Tests using this can't be run in parallel.
+// Call Z_ForTests_RestoreDefaultOptions to restore the original value.
+//
+// nolint:revive,nolintlint // We want to use underscores in the function name here.
+func Z_ForTests_SetLockWait(d time.Duration) {
+	testsdetection.MustBeTesting()
+
+	defaultOptions.lockWait = d
+}
@@ -47,6 +47,17 @@ func Mockgpasswd(_ *testing.T) {
 		os.Exit(3)
 	}
 
+	// The group file is locked by another process while the lock file next to the output file exists.
+	// As the real gpasswd, the error is translated unless the C locale is forced.
+	if _, err := os.Stat(outputFilePath + ".lock"); err == nil {
+		msg := "gpasswd : impossible de verrouiller %s ; réessayez plus tard.\n"
+		if os.Getenv("LC_ALL") == "C" {
+			msg = "gpasswd: cannot lock %s; try again later.\n"
+		}
+		fmt.Fprintf(os.Stderr, msg, groupsFilePath)
+		os.Exit(1)
+	}
+
 	// Other error
 	if slices.Contains(args, "gpasswdfail") {
 		fmt.Fprint(os.Stderr, "Error requested in mock")
@@ -68,6 +79,8 @@ func Mockgpasswd(_ *testing.T) {
 }
 
 // SetupGPasswdMock setup the gpasswd mock and return the path to the file where the commands will be written.
+// The group file is reported as locked while a file with the same path and the .lock extension exists, which gpasswd
+// isn't retried on.
 //
 // Tests that require this can not be run in parallel.
 func SetupGPasswdMock(t *testing.T, groupsFilePath string) string {
@@ -76,6 +89,7 @@ func SetupGPasswdMock(t *testing.T, groupsFilePath string) string {
 	t.Cleanup(localentries.Z_ForTests_RestoreDefaultOptions)
 
 	localentries.Z_ForTests_SetGroupPath(groupsFilePath)
+	localentries.Z_ForTests_SetLockWait(0)
 
 	destCmdsFile := filepath.Join(t.TempDir(), "gpasswd.output")
 	localentries.Z_ForTests_SetGpasswdCmd([]string{"env", "GO_WANT_HELPER_PROCESS=1",
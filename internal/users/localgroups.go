@@ -0,0 +1,54 @@
+package users
+
+import (
+	"errors"
+
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/localentries"
+	"github.com/ubuntu/decorate"
+)
+
+// ReconciledUser is a user of authd which was added back to some of its local groups.
+type ReconciledUser struct {
+	Name string
+	// AddedGroups are the local groups the user was added back to.
+	AddedGroups []string
+}
+
+// ReconcileLocalGroups adds the users of authd back to the local groups they should be part of but aren't, for example
+// because the group file was edited manually, and returns the users which were changed.
+// All the users are reconciled even if some of them fail.
+func (m *Manager) ReconcileLocalGroups() (reconciled []ReconciledUser, err error) {
+	defer decorate.OnError(&err, "could not reconcile local groups")
+
+	m.updateUserMu.Lock()
+	defer m.updateUserMu.Unlock()
+
+	users, err := m.db.AllUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs error
+	for _, u := range users {
+		groups, err := m.db.UserLocalGroups(u.UID)
+		if errors.Is(err, db.NoDataFoundError{}) || (err == nil && len(groups) == 0) {
+			continue
+		}
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+
+		added, err := localentries.Reconcile(u.Name, groups)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		if len(added) > 0 {
+			reconciled = append(reconciled, ReconciledUser{Name: u.Name, AddedGroups: added})
+		}
+	}
+
+	return reconciled, errs
+}
@@ -0,0 +1,91 @@
+// Package localpin handles the machine-local PINs that the users of authd can register to authenticate without their
+// broker. The PINs are only stored hashed with Argon2id.
+package localpin
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ubuntu/decorate"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// The Argon2id parameters recommended by OWASP.
+	hashTime    = 2
+	hashMemory  = 19 * 1024
+	hashThreads = 1
+
+	hashKeyLen  = 32
+	hashSaltLen = 16
+
+	// maxHashMemory bounds the memory used to verify a stored hash, in case it was tampered with.
+	maxHashMemory = 1024 * 1024
+)
+
+// Validate returns an error if the PIN is not made of at least minLength digits.
+func Validate(pin string, minLength int) error {
+	if utf8.RuneCountInString(pin) < minLength {
+		return fmt.Errorf("the PIN must be at least %d digits long", minLength)
+	}
+	if pin == "" || strings.Trim(pin, "0123456789") != "" {
+		return errors.New("the PIN must only contain digits")
+	}
+	return nil
+}
+
+// Hash returns the Argon2id hash of the PIN, with a random salt, encoded in the PHC string format.
+func Hash(pin string) (string, error) {
+	salt := make([]byte, hashSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("could not generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(pin), salt, hashTime, hashMemory, hashThreads, hashKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, hashMemory, hashTime, hashThreads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify returns whether the PIN matches the hash returned by Hash.
+func Verify(pin, hash string) (ok bool, err error) {
+	defer decorate.OnError(&err, "could not verify PIN")
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return false, errors.New("unsupported hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, fmt.Errorf("invalid hash version: %w", err)
+	}
+	if version != argon2.Version {
+		return false, fmt.Errorf("unsupported hash version %d", version)
+	}
+
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, fmt.Errorf("invalid hash parameters: %w", err)
+	}
+	if memory == 0 || memory > maxHashMemory || time == 0 || threads == 0 {
+		return false, fmt.Errorf("invalid hash parameters %q", parts[3])
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, fmt.Errorf("invalid hash salt: %w", err)
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(want) == 0 {
+		return false, fmt.Errorf("invalid hash key: %w", err)
+	}
+
+	got := argon2.IDKey([]byte(pin), salt, time, memory, threads, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
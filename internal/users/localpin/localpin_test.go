@@ -0,0 +1,103 @@
+package localpin_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/users/localpin"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		pin       string
+		minLength int
+
+		wantErr bool
+	}{
+		"Valid_PIN":                    {pin: "123456", minLength: 6},
+		"Valid_PIN_without_min_length": {pin: "1"},
+
+		"Error_when_PIN_is_too_short":        {pin: "12345", minLength: 6, wantErr: true},
+		"Error_when_PIN_is_empty":            {wantErr: true},
+		"Error_when_PIN_contains_non_digits": {pin: "12345a", minLength: 6, wantErr: true},
+		"Error_when_PIN_contains_spaces":     {pin: "123 456", minLength: 6, wantErr: true},
+		"Error_when_PIN_contains_other_digits": {
+			pin: "١٢٣٤٥٦", minLength: 6, wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := localpin.Validate(tc.pin, tc.minLength)
+			if tc.wantErr {
+				require.Error(t, err, "Validate should have failed")
+				return
+			}
+			require.NoError(t, err, "Validate should not fail")
+		})
+	}
+}
+
+func TestHashAndVerify(t *testing.T) {
+	t.Parallel()
+
+	hash, err := localpin.Hash("123456")
+	require.NoError(t, err, "Hash should not fail")
+	require.True(t, strings.HasPrefix(hash, "$argon2id$v=19$"), "Hash should be encoded in the PHC string format")
+	require.NotContains(t, hash, "123456", "Hash should not contain the PIN")
+
+	other, err := localpin.Hash("123456")
+	require.NoError(t, err, "Hash should not fail")
+	require.NotEqual(t, hash, other, "Hashes of the same PIN should use different salts")
+
+	ok, err := localpin.Verify("123456", hash)
+	require.NoError(t, err, "Verify should not fail")
+	require.True(t, ok, "Verify should accept the hashed PIN")
+
+	ok, err = localpin.Verify("654321", hash)
+	require.NoError(t, err, "Verify should not fail")
+	require.False(t, ok, "Verify should refuse another PIN")
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	// A well formed hash with low cost parameters, to be completed with a key.
+	const validHash = "$argon2id$v=19$m=32,t=1,p=1$AAAAAAAAAAAAAAAAAAAAAA$"
+
+	tests := map[string]struct {
+		pin  string
+		hash string
+
+		want    bool
+		wantErr bool
+	}{
+		"Refuses_PIN_not_matching_the_hash": {pin: "123456", hash: validHash + "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"},
+
+		"Error_when_hash_is_empty":                 {pin: "123456", wantErr: true},
+		"Error_when_hash_uses_another_algorithm":   {pin: "123456", hash: "$argon2i$v=19$m=32,t=1,p=1$AAAA$AAAA", wantErr: true},
+		"Error_when_hash_uses_another_version":     {pin: "123456", hash: "$argon2id$v=16$m=32,t=1,p=1$AAAA$AAAA", wantErr: true},
+		"Error_when_hash_has_invalid_parameters":   {pin: "123456", hash: "$argon2id$v=19$m=32$AAAA$AAAA", wantErr: true},
+		"Error_when_hash_requires_too_much_memory": {pin: "123456", hash: "$argon2id$v=19$m=4294967295,t=1,p=1$AAAA$AAAA", wantErr: true},
+		"Error_when_hash_has_no_passes":            {pin: "123456", hash: "$argon2id$v=19$m=32,t=0,p=1$AAAA$AAAA", wantErr: true},
+		"Error_when_hash_has_invalid_salt":         {pin: "123456", hash: "$argon2id$v=19$m=32,t=1,p=1$!!!!$AAAA", wantErr: true},
+		"Error_when_hash_has_empty_key":            {pin: "123456", hash: "$argon2id$v=19$m=32,t=1,p=1$AAAA$", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := localpin.Verify(tc.pin, tc.hash)
+			if tc.wantErr {
+				require.Error(t, err, "Verify should have failed")
+				return
+			}
+			require.NoError(t, err, "Verify should not fail")
+			require.Equal(t, tc.want, got, "Verify returned an unexpected result")
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package users
+
+import (
+	"errors"
+
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/localpin"
+	"github.com/ubuntu/decorate"
+)
+
+// ErrLocalPINLocked is returned when the local PIN of a user can't be used anymore, because of too many failed
+// attempts. A successful authentication with the broker of the user unlocks it.
+var ErrLocalPINLocked = errors.New("local PIN is locked after too many failed attempts")
+
+// SetLocalPIN registers the machine-local PIN of the user, replacing any previous one. The PIN is only stored hashed.
+func (m *Manager) SetLocalPIN(username, pin string) (err error) {
+	defer decorate.OnError(&err, "could not set the local PIN of user %q", username)
+
+	hash, err := localpin.Hash(pin)
+	if err != nil {
+		return err
+	}
+
+	m.localPINsMu.Lock()
+	defer m.localPINsMu.Unlock()
+
+	err = m.db.SetLocalPINForUser(username, hash)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return NoDataFoundError{}
+	}
+	return err
+}
+
+// RemoveLocalPIN removes the local PIN of the user.
+func (m *Manager) RemoveLocalPIN(username string) error {
+	m.localPINsMu.Lock()
+	defer m.localPINsMu.Unlock()
+
+	err := m.db.DeleteLocalPINForUser(username)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return NoDataFoundError{}
+	}
+	return err
+}
+
+// LocalPINFailedAttempts returns the number of failed attempts to authenticate with the local PIN of the user since
+// the last successful authentication, or a NoDataFoundError if the user has no local PIN.
+func (m *Manager) LocalPINFailedAttempts(username string) (int, error) {
+	p, err := m.db.LocalPINForUser(username)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return 0, NoDataFoundError{}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return p.FailedAttempts, nil
+}
+
+// CheckLocalPIN returns whether the PIN matches the local PIN of the user. Failed attempts are counted, and once
+// maxAttempts of them are reached, ErrLocalPINLocked is returned until ResetLocalPINFailedAttempts is called.
+// A maxAttempts of 0 or less means no limit.
+func (m *Manager) CheckLocalPIN(username, pin string, maxAttempts int) (ok bool, err error) {
+	defer decorate.OnError(&err, "could not check the local PIN of user %q", username)
+
+	// Serialize the checks, so that concurrent attempts can't exceed the limit.
+	m.localPINsMu.Lock()
+	defer m.localPINsMu.Unlock()
+
+	p, err := m.db.LocalPINForUser(username)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return false, NoDataFoundError{}
+	}
+	if err != nil {
+		return false, err
+	}
+	if maxAttempts > 0 && p.FailedAttempts >= maxAttempts {
+		return false, ErrLocalPINLocked
+	}
+
+	ok, err = localpin.Verify(pin, p.Hash)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return true, m.db.ResetLocalPINFailedAttempts(username)
+	}
+
+	if _, err := m.db.IncrementLocalPINFailedAttempts(username); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// ResetLocalPINFailedAttempts unlocks the local PIN of the user, if any, after a successful authentication with
+// their broker.
+func (m *Manager) ResetLocalPINFailedAttempts(username string) error {
+	m.localPINsMu.Lock()
+	defer m.localPINsMu.Unlock()
+
+	return m.db.ResetLocalPINFailedAttempts(username)
+}
@@ -7,12 +7,19 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"slices"
 	"sync"
 	"syscall"
+	"time"
 
+	"github.com/ubuntu/authd/internal/consts"
+	"github.com/ubuntu/authd/internal/users/accountsservice"
 	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/gecos"
 	"github.com/ubuntu/authd/internal/users/idgenerator"
+	"github.com/ubuntu/authd/internal/users/journal"
 	"github.com/ubuntu/authd/internal/users/localentries"
+	"github.com/ubuntu/authd/internal/users/subids"
 	"github.com/ubuntu/authd/internal/users/tempentries"
 	"github.com/ubuntu/authd/internal/users/types"
 	"github.com/ubuntu/authd/log"
@@ -25,6 +32,36 @@ type Config struct {
 	UIDMax uint32 `mapstructure:"uid_max"`
 	GIDMin uint32 `mapstructure:"gid_min"`
 	GIDMax uint32 `mapstructure:"gid_max"`
+
+	// BrokerIDRanges overrides the UID and GID ranges for the users and groups of some brokers, indexed by broker name.
+	BrokerIDRanges map[string]idgenerator.Ranges `mapstructure:"broker_id_ranges"`
+	// ExcludedUIDs and ExcludedGIDs are the IDs which are never assigned, because they are used by other sources.
+	ExcludedUIDs []idgenerator.Range `mapstructure:"excluded_uids"`
+	ExcludedGIDs []idgenerator.Range `mapstructure:"excluded_gids"`
+
+	// SubIDs configures the allocation of subordinate UIDs and GIDs to the users, used by rootless containers.
+	SubIDs subids.Config `mapstructure:"subordinate_ids"`
+
+	// StorageHooks are the commands run on the first login of the users of a broker to set up their storage, indexed
+	// by broker name.
+	StorageHooks map[string][]string `mapstructure:"storage_hooks"`
+
+	// GroupConflicts sets how the groups of the brokers whose name is already used by a local group are handled.
+	GroupConflicts GroupConflictsConfig `mapstructure:"group_conflicts"`
+
+	// ReservedAccounts protects the system accounts from being created or shadowed by the users of the brokers.
+	ReservedAccounts ReservedAccountsConfig `mapstructure:"reserved_accounts"`
+
+	// BrokerTrust relaxes the validation of the users provided by some brokers, indexed by broker name.
+	BrokerTrust map[string]BrokerTrustConfig `mapstructure:"broker_trust"`
+
+	// BrokerSelectionExpiry is the duration after which the users select their broker again on login. The broker they
+	// selected is kept forever if it's zero.
+	BrokerSelectionExpiry time.Duration `mapstructure:"broker_selection_expiry"`
+
+	// NSSSnapshot makes the daemon maintain a snapshot of the passwd and group entries, which the NSS module maps in
+	// memory to look them up without querying the daemon.
+	NSSSnapshot bool `mapstructure:"nss_snapshot"`
 }
 
 // DefaultConfig is the default configuration for the user manager.
@@ -33,6 +70,14 @@ var DefaultConfig = Config{
 	UIDMax: 1999999999,
 	GIDMin: 1000000000,
 	GIDMax: 1999999999,
+	SubIDs: subids.DefaultConfig,
+	GroupConflicts: GroupConflictsConfig{
+		Strategy: GroupConflictReject,
+		Prefix:   defaultGroupConflictPrefix,
+	},
+	ReservedAccounts: ReservedAccountsConfig{
+		MinID: defaultReservedMinID,
+	},
 }
 
 // Manager is the manager for any user related operation.
@@ -40,11 +85,43 @@ type Manager struct {
 	db               *db.Manager
 	config           Config
 	temporaryRecords *tempentries.TemporaryRecords
+	subIDs           *subids.Manager
+	journal          *journal.Journal
 	updateUserMu     sync.Mutex
+	localPINsMu      sync.Mutex
+
+	// userLocks serializes the updates of each user, which can take a while when the group file is locked or the
+	// picture of the user is downloaded, without blocking the ones of the other users.
+	userLocks userLocks
+
+	accountsServiceDir string
+	shellsFile         string
+	passwdFile         string
+	groupFile          string
+
+	nssSnapshotPath string
+	nssSnapshotMu   sync.Mutex
+
+	newUserHandlers   []func(name string, uid uint32)
+	newUserHandlersMu sync.RWMutex
+
+	userUpdatedHandlers   []func(name string, isNew bool)
+	userUpdatedHandlersMu sync.RWMutex
+
+	// deferredUpdates are the users whose local groups are updated in the background, as the group file was locked.
+	deferredUpdates        map[string]struct{}
+	deferredUpdatesRunning bool
+	deferredUpdatesMu      sync.Mutex
+	stop                   chan struct{}
 }
 
 type options struct {
-	idGenerator tempentries.IDGenerator
+	idGenerator        tempentries.IDGenerator
+	accountsServiceDir string
+	shellsFile         string
+	passwdFile         string
+	groupFile          string
+	nssSnapshotPath    string
 }
 
 // Option is a function that allows changing some of the default behaviors of the manager.
@@ -58,41 +135,105 @@ func WithIDGenerator(g tempentries.IDGenerator) Option {
 	}
 }
 
+// WithAccountsServiceDir makes the manager install the pictures of the users in a specific AccountsService directory.
+// This option is only useful in tests.
+func WithAccountsServiceDir(dir string) Option {
+	return func(o *options) {
+		o.accountsServiceDir = dir
+	}
+}
+
+// WithShellsFile makes the manager check the login shells set locally against a specific list of valid login shells.
+// This option is only useful in tests.
+func WithShellsFile(path string) Option {
+	return func(o *options) {
+		o.shellsFile = path
+	}
+}
+
+// WithPasswdFile makes the manager look up the local users to adopt in a specific passwd file.
+// This option is only useful in tests.
+func WithPasswdFile(path string) Option {
+	return func(o *options) {
+		o.passwdFile = path
+	}
+}
+
+// WithGroupFile makes the manager look up the local groups in a specific group file.
+// This option is only useful in tests.
+func WithGroupFile(path string) Option {
+	return func(o *options) {
+		o.groupFile = path
+	}
+}
+
+// WithNSSSnapshotPath makes the manager write the snapshot of the NSS entries to a specific path.
+// This option is only useful in tests.
+func WithNSSSnapshotPath(path string) Option {
+	return func(o *options) {
+		o.nssSnapshotPath = path
+	}
+}
+
 // NewManager creates a new user manager.
 func NewManager(config Config, dbDir string, args ...Option) (m *Manager, err error) {
 	log.Debugf(context.Background(), "Creating user manager with config: %+v", config)
 
-	opts := &options{}
+	opts := &options{accountsServiceDir: accountsservice.DefaultDir, shellsFile: defaultShellsFile, passwdFile: defaultPasswdFile,
+		groupFile: defaultGroupFile, nssSnapshotPath: consts.DefaultNSSSnapshotPath,
+	}
 	for _, arg := range args {
 		arg(opts)
 	}
 
 	if opts.idGenerator == nil {
-		// Check that the ID ranges are valid.
-		if config.UIDMin >= config.UIDMax {
-			return nil, errors.New("UID_MIN must be less than UID_MAX")
-		}
-		if config.GIDMin >= config.GIDMax {
-			return nil, errors.New("GID_MIN must be less than GID_MAX")
-		}
-		// Check that the number of possible UIDs is at least twice the number of possible pre-auth users.
-		numUIDs := config.UIDMax - config.UIDMin
-		minNumUIDs := uint32(tempentries.MaxPreAuthUsers * 2)
-		if numUIDs < minNumUIDs {
-			return nil, fmt.Errorf("UID range configured via UID_MIN and UID_MAX is too small (%d), must be at least %d", numUIDs, minNumUIDs)
-		}
-
-		opts.idGenerator = &idgenerator.IDGenerator{
+		if err := checkIDRanges(idgenerator.Ranges{
 			UIDMin: config.UIDMin,
 			UIDMax: config.UIDMax,
 			GIDMin: config.GIDMin,
 			GIDMax: config.GIDMax,
+		}, config.ExcludedUIDs, config.ExcludedGIDs, config.ReservedAccounts); err != nil {
+			return nil, err
+		}
+		for name, r := range config.BrokerIDRanges {
+			if err := checkIDRanges(r, config.ExcludedUIDs, config.ExcludedGIDs, config.ReservedAccounts); err != nil {
+				return nil, fmt.Errorf("invalid ID ranges for broker %q: %w", name, err)
+			}
+		}
+
+		opts.idGenerator = &idgenerator.IDGenerator{
+			UIDMin:       config.UIDMin,
+			UIDMax:       config.UIDMax,
+			GIDMin:       config.GIDMin,
+			GIDMax:       config.GIDMax,
+			BrokerRanges: config.BrokerIDRanges,
+			ExcludedUIDs: config.ExcludedUIDs,
+			ExcludedGIDs: config.ExcludedGIDs,
 		}
 	}
 
 	m = &Manager{
-		config:           config,
-		temporaryRecords: tempentries.NewTemporaryRecords(opts.idGenerator),
+		config:             config,
+		accountsServiceDir: opts.accountsServiceDir,
+		shellsFile:         opts.shellsFile,
+		passwdFile:         opts.passwdFile,
+		groupFile:          opts.groupFile,
+		nssSnapshotPath:    opts.nssSnapshotPath,
+		deferredUpdates:    make(map[string]struct{}),
+		stop:               make(chan struct{}),
+	}
+	m.temporaryRecords = tempentries.NewTemporaryRecords(deletedUIDsSkipper{IDGenerator: opts.idGenerator, m: m})
+
+	if err := checkStorageHooks(config.StorageHooks); err != nil {
+		return nil, err
+	}
+	if err := config.GroupConflicts.check(); err != nil {
+		return nil, err
+	}
+
+	m.subIDs, err = subids.NewManager(config.SubIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	m.db, err = db.New(dbDir)
@@ -100,69 +241,180 @@ func NewManager(config Config, dbDir string, args ...Option) (m *Manager, err er
 		return nil, err
 	}
 
+	m.journal = journal.New(dbDir)
+	m.recoverPendingUpdates()
+
+	if err := m.cleanSubIDs(); err != nil {
+		log.Warningf(context.Background(), "Could not remove subordinate IDs of users which don't exist anymore: %v", err)
+	}
+
+	m.updateNSSSnapshot()
+
 	return m, nil
 }
 
+// checkIDRanges checks that the ID ranges are valid, that they don't contain IDs reserved to the system accounts and
+// that enough IDs are left in them once the excluded ones are removed.
+func checkIDRanges(r idgenerator.Ranges, excludedUIDs, excludedGIDs []idgenerator.Range, reserved ReservedAccountsConfig) error {
+	if r.UIDMin >= r.UIDMax {
+		return errors.New("UID_MIN must be less than UID_MAX")
+	}
+	if r.GIDMin >= r.GIDMax {
+		return errors.New("GID_MIN must be less than GID_MAX")
+	}
+	if err := reserved.checkRanges(r); err != nil {
+		return err
+	}
+	for _, e := range append(slices.Clone(excludedUIDs), excludedGIDs...) {
+		if e.Min > e.Max {
+			return fmt.Errorf("invalid excluded ID range %d-%d: minimum is greater than maximum", e.Min, e.Max)
+		}
+	}
+
+	// Check that the number of possible UIDs is at least twice the number of possible pre-auth users.
+	numUIDs := idgenerator.CountAvailable(idgenerator.Range{Min: r.UIDMin, Max: r.UIDMax}, excludedUIDs)
+	minNumUIDs := uint64(tempentries.MaxPreAuthUsers * 2)
+	if numUIDs < minNumUIDs {
+		return fmt.Errorf("UID range configured via UID_MIN and UID_MAX is too small (%d), must be at least %d", numUIDs, minNumUIDs)
+	}
+	if idgenerator.CountAvailable(idgenerator.Range{Min: r.GIDMin, Max: r.GIDMax}, excludedGIDs) == 0 {
+		return errors.New("all GIDs between GID_MIN and GID_MAX are excluded")
+	}
+
+	return nil
+}
+
 // Stop closes the underlying db.
 func (m *Manager) Stop() error {
+	close(m.stop)
 	return m.db.Close()
 }
 
+// CheckHealth returns an error if the database can't be read, and blocks while it's locked.
+func (m *Manager) CheckHealth() error {
+	return m.db.CheckHealth()
+}
+
 // UpdateUser updates the user information in the db.
-func (m *Manager) UpdateUser(u types.UserInfo) (err error) {
+// New UIDs and GIDs are generated in the ranges configured for the broker which authenticated the user.
+// When ctx is done, the storage hook is stopped and the user is not added to the database. If the user was already
+// added, the update of the local groups is deferred instead, as the login of the user must not be left half done.
+// The lookups in the database are not interrupted, as they are local and short.
+func (m *Manager) UpdateUser(ctx context.Context, u types.UserInfo, brokerName string) (err error) {
 	defer decorate.OnError(&err, "failed to update user %q", u.Name)
 
 	if u.Name == "" {
 		return errors.New("empty username")
 	}
+	if err := m.checkReservedName(u.Name, brokerName); err != nil {
+		return err
+	}
+	if err := m.sanitizeUserInfo(&u, brokerName); err != nil {
+		return err
+	}
 
 	var uid uint32
+	var isNewUser, isTemporaryUser bool
+
+	// Serialize the concurrent logins of the same user, for example on the console and through SSH, until their local
+	// groups are updated, so that an update can't undo the changes of another one.
+	unlockUser := m.userLocks.lock(u.Name)
+	defer unlockUser()
 
 	// Prevent a TOCTOU race condition between the check for existence in our database and the registration of the
 	// temporary user/group records. This does not prevent a race condition where a user is created by some other NSS
 	// source, but that is handled in the temporaryRecords.RegisterUser and temporaryRecords.RegisterGroup functions.
+	// The lock is released once the user is committed to the database, as the next steps only concern this user: the
+	// updates of the group file are serialized by localentries itself.
 	m.updateUserMu.Lock()
-	defer m.updateUserMu.Unlock()
+	unlockUpdates := sync.OnceFunc(m.updateUserMu.Unlock)
+	defer unlockUpdates()
+
+	// The users who adopted a local user take over its identity instead of getting a new one.
+	adopted, isAdopted, err := m.adoptedUser(u.Name)
+	if err != nil {
+		return err
+	}
+	if isAdopted {
+		u.Dir = adopted.Dir
+	}
 
 	// Check if the user already exists in the database
 	oldUser, err := m.db.UserByName(u.Name)
 	if err != nil && !errors.Is(err, db.NoDataFoundError{}) {
 		return fmt.Errorf("could not get user %q: %w", u.Name, err)
 	}
-	if errors.Is(err, db.NoDataFoundError{}) {
+	if errors.Is(err, db.NoDataFoundError{}) && isAdopted {
+		// The UID is already the one of the local user, so no temporary user is needed to keep it unique.
+		isNewUser = true
+		uid = adopted.UID
+		if err := m.checkReservedUID(u.Name, uid, brokerName); err != nil {
+			return err
+		}
+		m.notifyNewUser(u.Name, uid)
+	} else if errors.Is(err, db.NoDataFoundError{}) {
 		// Check if the user exists on the system
 		existingUser, err := user.Lookup(u.Name)
 		var unknownUserErr user.UnknownUserError
 		if !errors.As(err, &unknownUserErr) {
-			log.Errorf(context.Background(), "User already exists on the system: %+v", existingUser)
-			return fmt.Errorf("user %q already exists on the system (but not in this authd instance)", u.Name)
+			log.Errorf(ctx, "User already exists on the system: %+v", existingUser)
+			return fmt.Errorf("user %q already exists on the system (but not in this authd instance), it can be migrated with 'authctl user adopt'", u.Name)
 		}
 
+		isNewUser = true
+
 		// The user does not exist, so we generate a unique UID for it. To avoid that a user with the same UID is
 		// created by some other NSS source, this also registers a temporary user in our NSS handler. We remove that
 		// temporary user before returning from this function, at which point the user is added to the database (so we
 		// don't need the temporary user anymore to keep the UID unique).
 		var cleanup func()
-		uid, cleanup, err = m.temporaryRecords.RegisterUser(u.Name)
+		uid, cleanup, err = m.temporaryRecords.RegisterUser(u.Name, brokerName)
 		if err != nil {
 			return fmt.Errorf("could not register user %q: %w", u.Name, err)
 		}
 		defer cleanup()
+		isTemporaryUser = true
+		m.notifyNewUser(u.Name, uid)
 	} else {
 		// The user already exists in the database, use the existing UID to avoid permission issues.
 		uid = oldUser.UID
+		if err := m.checkReservedUID(u.Name, uid, brokerName); err != nil {
+			return err
+		}
 	}
 
-	// Prepend the user private group
-	u.Groups = append([]types.GroupInfo{{Name: u.Name, UGID: u.Name}}, u.Groups...)
+	override, err := m.userOverride(u.Name)
+	if err != nil {
+		return err
+	}
+
+	// Prepend the user private group, unless the user keeps the primary group of the adopted local user.
+	firstBrokerGroup := 0
+	if !isAdopted {
+		u.Groups = append([]types.GroupInfo{{Name: u.Name, UGID: u.Name}}, u.Groups...)
+		firstBrokerGroup = 1
+	}
 
 	var groupRows []db.GroupRow
 	var localGroups []string
-	for _, g := range u.Groups {
+	for i, g := range u.Groups {
 		if g.Name == "" {
 			return fmt.Errorf("empty group name for user %q", u.Name)
 		}
 
+		// It's not a local group, so before storing it in the database, check if a group with the same name already
+		// exists. The conflicts with local groups are resolved with the configured strategy, except for the user
+		// private group.
+		if g.UGID != "" {
+			err := m.checkGroupNameConflict(g.Name, g.UGID)
+			if i >= firstBrokerGroup && errors.Is(err, errLocalGroupConflict) {
+				g, err = m.resolveLocalGroupConflict(g, err)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
 		if g.UGID == "" {
 			// An empty UGID means that the group is local, i.e. it's not stored in the database but expected to be
 			// already present in /etc/group.
@@ -170,12 +422,6 @@ func (m *Manager) UpdateUser(u types.UserInfo) (err error) {
 			continue
 		}
 
-		// It's not a local group, so before storing it in the database, check if a group with the same name already
-		// exists.
-		if err := m.checkGroupNameConflict(g.Name, g.UGID); err != nil {
-			return err
-		}
-
 		// Check if the group already exists in the database
 		oldGroup, err := m.findGroup(g)
 		if err != nil && !errors.Is(err, db.NoDataFoundError{}) {
@@ -187,7 +433,7 @@ func (m *Manager) UpdateUser(u types.UserInfo) (err error) {
 			// call above, this also registers a temporary group in our NSS handler. We remove that temporary group
 			// before returning from this function, at which point the group is added to the database (so we don't need
 			// the temporary group anymore to keep the GID unique).
-			gid, cleanup, err := m.temporaryRecords.RegisterGroup(g.Name)
+			gid, cleanup, err := m.temporaryRecords.RegisterGroup(g.Name, brokerName)
 			if err != nil {
 				return fmt.Errorf("could not generate GID for group %q: %v", g.Name, err)
 			}
@@ -203,30 +449,114 @@ func (m *Manager) UpdateUser(u types.UserInfo) (err error) {
 		groupRows = append(groupRows, db.NewGroupRow(g.Name, *g.GID, g.UGID))
 	}
 
+	// The user is always added to the local groups set locally, in addition to the ones of the broker.
+	for _, g := range override.LocalGroups {
+		if !slices.Contains(localGroups, g) {
+			localGroups = append(localGroups, g)
+		}
+	}
+
 	oldLocalGroups, err := m.db.UserLocalGroups(uid)
 	if err != nil && !errors.Is(err, db.NoDataFoundError{}) {
 		return err
 	}
 
 	// Update user information in the db.
-	userPrivateGroup := groupRows[0]
-	userRow := db.NewUserRow(u.Name, uid, userPrivateGroup.GID, u.Gecos, u.Dir, u.Shell)
-	if err := m.db.UpdateUserEntry(userRow, groupRows, localGroups); err != nil {
+	var gid uint32
+	if isAdopted {
+		gid = adopted.GID
+	} else {
+		gid = groupRows[0].GID
+	}
+	userRow := db.NewUserRow(u.Name, uid, gid, mergeGecos(oldUser.Gecos, u), u.Dir, u.Shell)
+
+	// Until the user is added to the database, which can take a while if its storage is set up, the lookups of the
+	// user are served by its temporary record. Give it its final attributes, so that the services resolving the user
+	// during its first login, like systemd-logind, see the same user before and after it's added to the database.
+	if isTemporaryUser {
+		if err := m.temporaryRecords.CompleteTemporaryUser(applyOverride(userEntryFromUserRow(userRow), override)); err != nil {
+			return fmt.Errorf("could not complete temporary record of user %q: %w", u.Name, err)
+		}
+	}
+
+	// Set up the storage of new users before adding them to the database, so that it's retried on the next login if
+	// it fails. The adopted users keep the home directory of the local user, which is already set up.
+	if isNewUser && !isAdopted {
+		if err := m.runStorageHook(ctx, brokerName, userRow, u.StorageSecret); err != nil {
+			return err
+		}
+	}
+
+	// Record the update before committing it, so that the local groups are still updated if the daemon stops between
+	// the database and the group file.
+	if err := m.journal.Begin(journal.Operation{
+		User: u.Name, UID: uid, LocalGroups: localGroups, OldLocalGroups: oldLocalGroups,
+	}); err != nil {
+		return err
+	}
+
+	if err := m.db.UpdateUserEntry(ctx, userRow, groupRows, localGroups); err != nil {
 		return err
 	}
+	m.updateNSSSnapshot()
 
-	// Update local groups.
-	if err := localentries.Update(u.Name, localGroups, oldLocalGroups); err != nil {
+	// The roles are cached so that the login policy can be evaluated when the broker can't be reached. Keeping the
+	// previous ones could give the user an access they lost, so the update fails if they can't be replaced.
+	if err := m.db.SetRolesForUser(u.Name, u.Roles); err != nil {
 		return err
 	}
+	unlockUpdates()
 
-	if err = checkHomeDirOwnership(userRow.Dir, userRow.UID, userRow.GID); err != nil {
+	// Update local groups. If another tool keeps the group file locked, or if ctx is done while waiting for it, the
+	// update is left in the journal and applied in the background, rather than failing the login of a user who is
+	// already in the database.
+	err = localentries.Update(ctx, u.Name, localGroups, oldLocalGroups)
+	if errors.Is(err, localentries.ErrGroupFileLocked) {
+		log.Warningf(ctx, "Deferring update of local groups: %v", err)
+		m.deferLocalGroupsUpdate(u.Name)
+	} else if err != nil {
+		return err
+	} else if err := m.journal.Done(u.Name); err != nil {
+		log.Warningf(ctx, "%v", err)
+	}
+
+	if err = checkHomeDirOwnership(applyOverride(userEntryFromUserRow(userRow), override).Dir, userRow.UID, userRow.GID); err != nil {
 		return fmt.Errorf("failed to check home directory owner and group: %w", err)
 	}
 
+	// Not being able to run rootless containers should not prevent the user from logging in.
+	if err := m.subIDs.Allocate(u.Name); err != nil {
+		log.Warningf(ctx, "%v", err)
+	}
+
+	// Neither should not being able to show the picture of the user.
+	if err := m.updateAvatar(u); err != nil {
+		log.Warningf(ctx, "%v", err)
+	}
+
+	m.notifyUserUpdated(u.Name, isNewUser)
+
 	return nil
 }
 
+// cleanSubIDs removes the subordinate IDs allocated to users which are not in the database anymore.
+func (m *Manager) cleanSubIDs() error {
+	if !m.subIDs.Enabled() {
+		return nil
+	}
+
+	users, err := m.db.AllUsers()
+	if err != nil {
+		return err
+	}
+
+	var usernames []string
+	for _, u := range users {
+		usernames = append(usernames, u.Name)
+	}
+	return m.subIDs.Clean(usernames)
+}
+
 // checkGroupNameConflict checks if a group with the given name already exists.
 // If it does, it checks if it has the same UGID.
 func (m *Manager) checkGroupNameConflict(name string, ugid string) error {
@@ -243,7 +573,7 @@ func (m *Manager) checkGroupNameConflict(name string, ugid string) error {
 		var unknownGroupErr user.UnknownGroupError
 		if !errors.As(err, &unknownGroupErr) {
 			log.Errorf(context.Background(), "Group already exists on the system: %+v", existingGroup)
-			return fmt.Errorf("group %q already exists on the system (but not in this authd instance)", name)
+			return fmt.Errorf("%w: %q", errLocalGroupConflict, name)
 		}
 		// The group does not exist on the system, so we can proceed.
 		return nil
@@ -325,6 +655,11 @@ func (m *Manager) BrokerForUser(username string) (string, error) {
 	return u.BrokerID, nil
 }
 
+// RolesForUser returns the roles the broker gave to the user on their last login.
+func (m *Manager) RolesForUser(username string) ([]string, error) {
+	return m.db.RolesForUser(username)
+}
+
 // UpdateBrokerForUser updates the broker ID for the given user.
 func (m *Manager) UpdateBrokerForUser(username, brokerID string) error {
 	if err := m.db.UpdateBrokerForUser(username, brokerID); err != nil {
@@ -334,6 +669,65 @@ func (m *Manager) UpdateBrokerForUser(username, brokerID string) error {
 	return nil
 }
 
+// UpdateGecos changes the subfields of the GECOS field of the given user stored in the db, until the broker provides
+// other values for them on the next login.
+func (m *Manager) UpdateGecos(username string, update gecos.Update) (err error) {
+	defer decorate.OnError(&err, "failed to update GECOS of user %q", username)
+
+	if err := update.Validate(); err != nil {
+		return err
+	}
+
+	m.updateUserMu.Lock()
+	defer m.updateUserMu.Unlock()
+
+	u, err := m.db.UserByName(username)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return NoDataFoundError{}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := m.db.UpdateGecosForUser(username, gecos.Parse(u.Gecos).Apply(update).String()); err != nil {
+		return err
+	}
+	m.updateNSSSnapshot()
+
+	m.notifyUserUpdated(username, false)
+	return nil
+}
+
+// mergeGecos returns the GECOS field of the user, made of the subfields provided by the broker, which take precedence
+// over the ones of the given GECOS field stored in the db, if they are not empty.
+func mergeGecos(oldGecos string, u types.UserInfo) string {
+	fields := gecos.Parse(u.Gecos)
+	if u.GecosFields != nil {
+		fields = fields.Override(*u.GecosFields)
+	}
+	return gecos.Parse(oldGecos).Override(fields).String()
+}
+
+// LastAuthModeForUser returns the authentication mode the user last successfully authenticated with using the given
+// broker.
+func (m *Manager) LastAuthModeForUser(username, brokerID string) (string, error) {
+	authMode, err := m.db.LastAuthModeForUser(username, brokerID)
+	if err != nil && errors.Is(err, db.NoDataFoundError{}) {
+		return "", NoDataFoundError{}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return authMode, nil
+}
+
+// UpdateLastAuthModeForUser memorizes the authentication mode the user successfully authenticated with using the
+// given broker.
+func (m *Manager) UpdateLastAuthModeForUser(username, brokerID, authMode string) error {
+	return m.db.UpdateLastAuthModeForUser(username, brokerID, authMode)
+}
+
 // UserByName returns the user information for the given user name.
 func (m *Manager) UserByName(username string) (types.UserEntry, error) {
 	usr, err := m.db.UserByName(username)
@@ -344,7 +738,11 @@ func (m *Manager) UserByName(username string) (types.UserEntry, error) {
 	if err != nil {
 		return types.UserEntry{}, err
 	}
-	return userEntryFromUserRow(usr), nil
+	o, err := m.userOverride(usr.Name)
+	if err != nil {
+		return types.UserEntry{}, err
+	}
+	return applyOverride(userEntryFromUserRow(usr), o), nil
 }
 
 // UserByID returns the user information for the given user ID.
@@ -357,23 +755,30 @@ func (m *Manager) UserByID(uid uint32) (types.UserEntry, error) {
 	if err != nil {
 		return types.UserEntry{}, err
 	}
-	return userEntryFromUserRow(usr), nil
+	o, err := m.userOverride(usr.Name)
+	if err != nil {
+		return types.UserEntry{}, err
+	}
+	return applyOverride(userEntryFromUserRow(usr), o), nil
 }
 
 // AllUsers returns all users.
 func (m *Manager) AllUsers() ([]types.UserEntry, error) {
 	// We don't return temporary users here, because they are not interesting to the user and would clutter the output
 	// of `getent passwd`. Other tools should check `getpwnam`/`getpwuid` to check for conflicts, like `useradd` does.
-	usrs, err := m.db.AllUsers()
+	overrides, err := m.db.AllUserOverrides()
 	if err != nil {
 		return nil, err
 	}
 
 	var usrEntries []types.UserEntry
-	for _, usr := range usrs {
-		usrEntries = append(usrEntries, userEntryFromUserRow(usr))
+	for usr, err := range m.db.AllUsersStream() {
+		if err != nil {
+			return nil, err
+		}
+		usrEntries = append(usrEntries, applyOverride(userEntryFromUserRow(usr), overrides[usr.Name]))
 	}
-	return usrEntries, err
+	return usrEntries, nil
 }
 
 // GroupByName returns the group information for the given group name.
@@ -420,6 +825,14 @@ func (m *Manager) AllGroups() ([]types.GroupEntry, error) {
 // ShadowByName returns the shadow information for the given user name.
 func (m *Manager) ShadowByName(username string) (types.ShadowEntry, error) {
 	usr, err := m.db.UserByName(username)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		// Check if the user is a temporary user, so that its shadow entry is consistent with its passwd entry.
+		tmp, err := m.temporaryRecords.UserByName(username)
+		if err != nil {
+			return types.ShadowEntry{}, err
+		}
+		return types.NewShadowEntry(tmp.Name), nil
+	}
 	if err != nil {
 		return types.ShadowEntry{}, err
 	}
@@ -428,21 +841,79 @@ func (m *Manager) ShadowByName(username string) (types.ShadowEntry, error) {
 
 // AllShadows returns all shadow entries.
 func (m *Manager) AllShadows() ([]types.ShadowEntry, error) {
-	usrs, err := m.db.AllUsers()
-	if err != nil {
-		return nil, err
-	}
-
 	var shadowEntries []types.ShadowEntry
-	for _, usr := range usrs {
+	for usr, err := range m.db.AllUsersStream() {
+		if err != nil {
+			return nil, err
+		}
 		shadowEntries = append(shadowEntries, shadowEntryFromUserRow(usr))
 	}
-	return shadowEntries, err
+	return shadowEntries, nil
+}
+
+// DumpDatabase returns the whole content of the database.
+func (m *Manager) DumpDatabase() (DatabaseDump, error) {
+	return m.db.Dump()
+}
+
+// ImportDatabase replaces the whole content of the database with the given one.
+func (m *Manager) ImportDatabase(d DatabaseDump) (err error) {
+	defer decorate.OnError(&err, "could not import the database")
+
+	// Don't let a concurrent update of a user mix with the imported content.
+	m.updateUserMu.Lock()
+	defer m.updateUserMu.Unlock()
+
+	if err := m.db.Import(d); err != nil {
+		return err
+	}
+	m.updateNSSSnapshot()
+	for _, u := range d.Users {
+		m.notifyNewUser(u.Name, u.UID)
+	}
+	return nil
 }
 
 // RegisterUserPreAuth registers a temporary user with a unique UID in our NSS handler (in memory, not in the database).
 //
 // The temporary user record is removed when UpdateUser is called with the same username.
 func (m *Manager) RegisterUserPreAuth(name string) (uint32, error) {
-	return m.temporaryRecords.RegisterPreAuthUser(name)
+	uid, err := m.temporaryRecords.RegisterPreAuthUser(name)
+	if err != nil {
+		return 0, err
+	}
+	m.notifyNewUser(name, uid)
+	return uid, nil
+}
+
+// OnNewUser registers a function which is called with the name and UID of each new user, as soon as it can be
+// looked up (including as a temporary user).
+func (m *Manager) OnNewUser(f func(name string, uid uint32)) {
+	m.newUserHandlersMu.Lock()
+	defer m.newUserHandlersMu.Unlock()
+	m.newUserHandlers = append(m.newUserHandlers, f)
+}
+
+func (m *Manager) notifyNewUser(name string, uid uint32) {
+	m.newUserHandlersMu.RLock()
+	defer m.newUserHandlersMu.RUnlock()
+	for _, f := range m.newUserHandlers {
+		f(name, uid)
+	}
+}
+
+// OnUserUpdated registers a function which is called with the name of each user stored in the database after logging
+// in, or whose attributes were changed locally, and whether it's a new user.
+func (m *Manager) OnUserUpdated(f func(name string, isNew bool)) {
+	m.userUpdatedHandlersMu.Lock()
+	defer m.userUpdatedHandlersMu.Unlock()
+	m.userUpdatedHandlers = append(m.userUpdatedHandlers, f)
+}
+
+func (m *Manager) notifyUserUpdated(name string, isNew bool) {
+	m.userUpdatedHandlersMu.RLock()
+	defer m.userUpdatedHandlersMu.RUnlock()
+	for _, f := range m.userUpdatedHandlers {
+		f(name, isNew)
+	}
 }
@@ -2,15 +2,25 @@ package users_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/faults"
 	"github.com/ubuntu/authd/internal/testutils/golden"
 	"github.com/ubuntu/authd/internal/users"
 	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/gecos"
 	"github.com/ubuntu/authd/internal/users/idgenerator"
+	"github.com/ubuntu/authd/internal/users/journal"
 	localgroupstestutils "github.com/ubuntu/authd/internal/users/localentries/testutils"
 	userstestutils "github.com/ubuntu/authd/internal/users/testutils"
 	"github.com/ubuntu/authd/internal/users/types"
@@ -25,11 +35,23 @@ func TestNewManager(t *testing.T) {
 		uidMax          uint32
 		gidMin          uint32
 		gidMax          uint32
+		brokerIDRanges  map[string]idgenerator.Ranges
+		excludedUIDs    []idgenerator.Range
+		excludedGIDs    []idgenerator.Range
+		storageHooks    map[string][]string
+		groupConflicts  users.GroupConflictsConfig
 
 		wantErr bool
 	}{
 		"Successfully_create_manager_with_default_config": {},
 		"Successfully_create_manager_with_custom_config":  {uidMin: 10000, uidMax: 20000, gidMin: 10000, gidMax: 20000},
+		"Successfully_create_manager_with_broker_ID_ranges": {brokerIDRanges: map[string]idgenerator.Ranges{
+			"broker": {UIDMin: 10000, UIDMax: 20000, GIDMin: 10000, GIDMax: 20000},
+		}},
+		"Successfully_create_manager_with_excluded_IDs": {
+			excludedUIDs: []idgenerator.Range{{Min: 1000000000, Max: 1099999999}},
+			excludedGIDs: []idgenerator.Range{{Min: 1000000000, Max: 1099999999}},
+		},
 
 		// Corrupted databases
 		"Error_when_database_is_corrupted":     {corruptedDbFile: true, wantErr: true},
@@ -37,6 +59,23 @@ func TestNewManager(t *testing.T) {
 		"Error_if_UID_MIN_is_equal_to_UID_MAX": {uidMin: 1000, uidMax: 1000, wantErr: true},
 		"Error_if_GID_MIN_is_equal_to_GID_MAX": {gidMin: 1000, gidMax: 1000, wantErr: true},
 		"Error_if_UID_range_is_too_small":      {uidMin: 1000, uidMax: 2000, wantErr: true},
+		"Error_if_UID_range_is_too_small_once_excluded_UIDs_are_removed": {
+			excludedUIDs: []idgenerator.Range{{Min: 1000000000, Max: 1999996000}}, wantErr: true,
+		},
+		"Error_if_all_GIDs_are_excluded":                {excludedGIDs: []idgenerator.Range{{Min: 0, Max: 1999999999}}, wantErr: true},
+		"Error_if_excluded_range_is_invalid":            {excludedUIDs: []idgenerator.Range{{Min: 2000, Max: 1000}}, wantErr: true},
+		"Error_if_storage_hook_is_empty":                {storageHooks: map[string][]string{"broker": {}}, wantErr: true},
+		"Error_if_storage_hook_is_not_an_absolute_path": {storageHooks: map[string][]string{"broker": {"hook"}}, wantErr: true},
+		"Error_if_group_conflicts_strategy_is_unknown":  {groupConflicts: users.GroupConflictsConfig{Strategy: "rename"}, wantErr: true},
+		"Error_if_group_conflicts_prefix_is_empty":      {groupConflicts: users.GroupConflictsConfig{Strategy: users.GroupConflictPrefix}, wantErr: true},
+		"Error_if_broker_ID_range_is_invalid": {brokerIDRanges: map[string]idgenerator.Ranges{
+			"broker": {UIDMin: 20000, UIDMax: 10000, GIDMin: 10000, GIDMax: 20000},
+		}, wantErr: true},
+		"Error_if_UID_range_contains_reserved_IDs": {uidMin: 500, wantErr: true},
+		"Error_if_GID_range_contains_reserved_IDs": {gidMin: 500, wantErr: true},
+		"Error_if_broker_ID_range_contains_reserved_IDs": {brokerIDRanges: map[string]idgenerator.Ranges{
+			"broker": {UIDMin: 100, UIDMax: 20000, GIDMin: 10000, GIDMax: 20000},
+		}, wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -71,6 +110,13 @@ func TestNewManager(t *testing.T) {
 			if tc.gidMax != 0 {
 				config.GIDMax = tc.gidMax
 			}
+			config.BrokerIDRanges = tc.brokerIDRanges
+			config.ExcludedUIDs = tc.excludedUIDs
+			config.ExcludedGIDs = tc.excludedGIDs
+			config.StorageHooks = tc.storageHooks
+			if tc.groupConflicts != (users.GroupConflictsConfig{}) {
+				config.GroupConflicts = tc.groupConflicts
+			}
 
 			m, err := users.NewManager(config, dbDir)
 			if tc.wantErr {
@@ -118,6 +164,8 @@ func TestUpdateUser(t *testing.T) {
 		"same-name-different-uid": {UserInfo: types.UserInfo{Name: "user1"}, UID: 3333},
 		"different-name-same-uid": {UserInfo: types.UserInfo{Name: "newuser1"}, UID: 1111},
 		"user-exists-on-system":   {UserInfo: types.UserInfo{Name: "root"}, UID: 1111},
+		// The "users" group exists on the system, but not the "users" user.
+		"private-group-exists-on-system": {UserInfo: types.UserInfo{Name: "users"}, UID: 1111},
 	}
 
 	groupsCases := map[string][]groupCase{
@@ -149,8 +197,12 @@ func TestUpdateUser(t *testing.T) {
 
 		dbFile          string
 		localGroupsFile string
+		groupConflicts  string
+		reservedNames   []string
+		reservedMinID   uint32
 
 		wantErr     bool
+		wantErrType error
 		noOutput    bool
 		wantSameUID bool
 	}{
@@ -160,6 +212,8 @@ func TestUpdateUser(t *testing.T) {
 		"GID_does_not_change_if_group_with_same_UGID_exists":                {groupsCase: "different-name-same-ugid", dbFile: "one_user_and_group"},
 		"GID_does_not_change_if_group_with_same_name_and_empty_UGID_exists": {groupsCase: "authd-group", dbFile: "group-with-empty-UGID"},
 		"Removing_last_user_from_a_group_keeps_the_group_record":            {groupsCase: "no-groups", dbFile: "one_user_and_group"},
+		"Prefix_group_conflicting_with_local_group":                         {groupsCase: "group-exists-on-system", groupConflicts: users.GroupConflictPrefix},
+		"Use_local_group_conflicting_with_group_of_broker":                  {groupsCase: "group-exists-on-system", groupConflicts: users.GroupConflictUseLocal, localGroupsFile: "root.group"},
 
 		"Error_if_user_has_no_username":                           {userCase: "nameless", wantErr: true, noOutput: true},
 		"Error_if_group_has_no_name":                              {groupsCase: "nameless-group", wantErr: true, noOutput: true},
@@ -167,6 +221,10 @@ func TestUpdateUser(t *testing.T) {
 		"Error_if_group_with_same_name_but_different_UGID_exists": {groupsCase: "authd-group", dbFile: "one_user_and_group", wantErr: true, noOutput: true},
 		"Error_if_user_exists_on_system":                          {userCase: "user-exists-on-system", wantErr: true, noOutput: true},
 		"Error_if_group_exists_on_system":                         {groupsCase: "group-exists-on-system", wantErr: true, noOutput: true},
+		"Error_if_user_private_group_exists_on_system":            {userCase: "private-group-exists-on-system", groupConflicts: users.GroupConflictUseLocal, wantErr: true, noOutput: true},
+		"Error_if_user_name_is_a_system_account":                  {userCase: "user-exists-on-system", wantErrType: users.ErrReservedAccount, noOutput: true},
+		"Error_if_user_name_is_reserved":                          {reservedNames: []string{"user1"}, wantErrType: users.ErrReservedAccount, noOutput: true},
+		"Error_if_UID_of_existing_user_is_reserved":               {dbFile: "one_user_and_group", reservedMinID: 2000, wantErrType: users.ErrReservedAccount, noOutput: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -210,8 +268,18 @@ func TestUpdateUser(t *testing.T) {
 					UIDsToGenerate: []uint32{user.UID},
 					GIDsToGenerate: gids,
 				}),
+				users.WithShellsFile(filepath.Join("testdata", "shells")),
 			}
-			m := newManagerForTests(t, dbDir, managerOpts...)
+			config := users.DefaultConfig
+			if tc.groupConflicts != "" {
+				config.GroupConflicts.Strategy = tc.groupConflicts
+			}
+			config.ReservedAccounts.Names = tc.reservedNames
+			if tc.reservedMinID != 0 {
+				config.ReservedAccounts.MinID = tc.reservedMinID
+			}
+			m, err := users.NewManager(config, dbDir, managerOpts...)
+			require.NoError(t, err, "Setup: could not create user manager")
 
 			var oldUID uint32
 			if tc.wantSameUID {
@@ -220,11 +288,11 @@ func TestUpdateUser(t *testing.T) {
 				oldUID = oldUser.UID
 			}
 
-			err := m.UpdateUser(user.UserInfo)
+			err = m.UpdateUser(context.Background(), user.UserInfo, "")
 			log.Debugf(context.Background(), "UpdateUser error: %v", err)
 
-			requireErrorAssertions(t, err, nil, tc.wantErr)
-			if tc.wantErr && tc.noOutput {
+			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
+			if (tc.wantErr || tc.wantErrType != nil) && tc.noOutput {
 				return
 			}
 
@@ -244,6 +312,302 @@ func TestUpdateUser(t *testing.T) {
 	}
 }
 
+func TestUpdateUserSanitizesHomeAndShell(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		dir           string
+		shell         string
+		allowedShells []string
+		anyShell      bool
+
+		wantDir     string
+		wantShell   string
+		wantErrType error
+	}{
+		"Keep_valid_home_and_shell":                {dir: "/home/user1", shell: "/bin/bash"},
+		"Clean_home_and_shell":                     {dir: "/home//user1/", shell: "/bin/../bin/bash", wantDir: "/home/user1", wantShell: "/bin/bash"},
+		"Allow_shell_not_listed_allowed_to_broker": {dir: "/home/user1", shell: "/usr/bin/fish", allowedShells: []string{"/usr/bin/fish"}},
+		"Allow_any_shell_to_trusted_broker":        {dir: "/home/user1", shell: "/opt/shell/bin/shell", anyShell: true},
+
+		"Error_if_home_is_not_absolute":         {dir: "home/user1", shell: "/bin/bash", wantErrType: users.ErrInvalidUserInfo},
+		"Error_if_home_is_empty":                {dir: "", shell: "/bin/bash", wantErrType: users.ErrInvalidUserInfo},
+		"Error_if_home_contains_a_colon":        {dir: "/home/user1:0:0", shell: "/bin/bash", wantErrType: users.ErrInvalidUserInfo},
+		"Error_if_home_contains_a_newline":      {dir: "/home/user1\nroot::0:0::/root:/bin/bash", shell: "/bin/bash", wantErrType: users.ErrInvalidUserInfo},
+		"Error_if_shell_is_not_absolute":        {dir: "/home/user1", shell: "bash", anyShell: true, wantErrType: users.ErrInvalidUserInfo},
+		"Error_if_shell_contains_a_newline":     {dir: "/home/user1", shell: "/bin/bash\n", anyShell: true, wantErrType: users.ErrInvalidUserInfo},
+		"Error_if_shell_is_not_listed":          {dir: "/home/user1", shell: "/usr/bin/fish", wantErrType: users.ErrInvalidUserInfo},
+		"Error_if_shell_is_allowed_to_no_other": {dir: "/home/user1", shell: "/usr/bin/fish", allowedShells: []string{"/usr/bin/tcsh"}, wantErrType: users.ErrInvalidUserInfo},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			config := users.DefaultConfig
+			config.BrokerTrust = map[string]users.BrokerTrustConfig{
+				"Broker": {AllowedShells: tc.allowedShells, AnyShell: tc.anyShell},
+			}
+			m, err := users.NewManager(config, t.TempDir(),
+				users.WithIDGenerator(&idgenerator.IDGeneratorMock{UIDsToGenerate: []uint32{1111}, GIDsToGenerate: []uint32{11111}}),
+				users.WithShellsFile(filepath.Join("testdata", "shells")))
+			require.NoError(t, err, "Setup: could not create user manager")
+			t.Cleanup(func() { _ = m.Stop() })
+
+			err = m.UpdateUser(context.Background(), types.UserInfo{Name: "user1", Dir: tc.dir, Shell: tc.shell}, "broker")
+			if tc.wantErrType != nil {
+				require.ErrorIs(t, err, tc.wantErrType, "UpdateUser should return the expected error")
+				_, err = m.UserByName("user1")
+				require.Error(t, err, "UpdateUser should not add the refused user")
+				return
+			}
+			require.NoError(t, err, "UpdateUser should not return an error, but did")
+
+			if tc.wantDir == "" {
+				tc.wantDir = tc.dir
+			}
+			if tc.wantShell == "" {
+				tc.wantShell = tc.shell
+			}
+			got, err := m.UserByName("user1")
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			require.Equal(t, tc.wantDir, got.Dir, "UpdateUser should store the sanitized home directory")
+			require.Equal(t, tc.wantShell, got.Shell, "UpdateUser should store the sanitized shell")
+		})
+	}
+}
+
+func TestStorageHooks(t *testing.T) {
+	tests := map[string]struct {
+		brokerName  string
+		hookArgs    []string
+		existingDB  bool
+		loginsCount int
+		cancelCtx   bool
+
+		wantErr bool
+	}{
+		"Run_hook_on_first_login":                 {brokerName: "broker"},
+		"Run_hook_of_broker_matching_its_name":    {brokerName: "Broker"},
+		"Run_hook_only_once_on_multiple_logins":   {brokerName: "broker", loginsCount: 2},
+		"Do_not_run_hook_for_existing_users":      {brokerName: "broker", existingDB: true},
+		"Do_not_run_hook_for_another_broker_user": {brokerName: "otherbroker"},
+
+		"Error_when_hook_fails":           {brokerName: "broker", hookArgs: []string{"1"}, wantErr: true},
+		"Error_when_request_is_cancelled": {brokerName: "broker", cancelCtx: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dbDir := t.TempDir()
+			if tc.existingDB {
+				err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "one_user_and_group.db.yaml"), dbDir)
+				require.NoError(t, err, "Setup: could not create database from testdata")
+			}
+			if tc.loginsCount == 0 {
+				tc.loginsCount = 1
+			}
+
+			hookOutput := filepath.Join(t.TempDir(), "hook.output")
+			hook, err := filepath.Abs(filepath.Join("testdata", "storage-hook"))
+			require.NoError(t, err, "Setup: could not get storage hook path")
+
+			config := users.DefaultConfig
+			config.StorageHooks = map[string][]string{"broker": append([]string{hook, hookOutput}, tc.hookArgs...)}
+			var gids []uint32
+			for i := range tc.loginsCount {
+				gids = append(gids, uint32(33333+2*i), uint32(33334+2*i))
+			}
+			m, err := users.NewManager(config, dbDir, users.WithIDGenerator(&idgenerator.IDGeneratorMock{
+				UIDsToGenerate: []uint32{1111},
+				GIDsToGenerate: gids,
+			}))
+			require.NoError(t, err, "Setup: NewManager should not return an error, but did")
+			t.Cleanup(func() { _ = m.Stop() })
+
+			u := types.UserInfo{
+				Name:          "user1",
+				Dir:           "/home/user1",
+				Shell:         "/bin/bash",
+				Groups:        []types.GroupInfo{{Name: "group1", UGID: "12345678"}},
+				StorageSecret: "my storage secret",
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelCtx {
+				cancel()
+			}
+			for range tc.loginsCount {
+				err = m.UpdateUser(ctx, u, tc.brokerName)
+				if tc.wantErr {
+					require.Error(t, err, "UpdateUser should return an error, but did not")
+					_, err = m.UserByName(u.Name)
+					require.Error(t, err, "User should not be added to the database when the storage hook fails")
+					return
+				}
+				require.NoError(t, err, "UpdateUser should not return an error, but did")
+			}
+
+			got, err := os.ReadFile(hookOutput)
+			if errors.Is(err, os.ErrNotExist) {
+				got = []byte("<hook not run>\n")
+			} else {
+				require.NoError(t, err, "Setup: could not read hook output")
+			}
+			golden.CheckOrUpdate(t, string(got))
+		})
+	}
+}
+
+func TestAvatars(t *testing.T) {
+	t.Parallel()
+
+	picture := []byte("GIF89a user1 picture")
+
+	tests := map[string]struct {
+		avatarData    []byte
+		avatarPath    string
+		loginsCount   int
+		noAccountsDir bool
+
+		wantAvatar    bool
+		wantDownloads int
+	}{
+		"Store_avatar_provided_by_broker":                       {avatarData: picture, wantAvatar: true},
+		"Store_avatar_downloaded_from_URL":                      {avatarPath: "/user1.gif", wantAvatar: true, wantDownloads: 1},
+		"Download_avatar_only_once_if_URL_did_not_change":       {avatarPath: "/user1.gif", loginsCount: 2, wantAvatar: true, wantDownloads: 1},
+		"Store_avatar_without_AccountsService_icon_if_disabled": {avatarData: picture, noAccountsDir: true, wantAvatar: true},
+		"No_avatar_if_broker_did_not_provide_any":               {},
+
+		"Ignore_avatar_which_is_not_a_picture":        {avatarData: []byte("not a picture")},
+		"Ignore_avatar_which_is_too_large":            {avatarPath: "/large.gif", wantDownloads: 1},
+		"Ignore_avatar_which_could_not_be_downloaded": {avatarPath: "/not-found.gif", wantDownloads: 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var downloads atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				downloads.Add(1)
+				switch r.URL.Path {
+				case "/user1.gif":
+					_, _ = w.Write(picture)
+				case "/large.gif":
+					_, _ = w.Write(append([]byte("GIF89a"), make([]byte, 1<<20)...))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			accountsDir := filepath.Join(t.TempDir(), "AccountsService")
+			if !tc.noAccountsDir {
+				require.NoError(t, os.Mkdir(accountsDir, 0700), "Setup: could not create AccountsService directory")
+			}
+			if tc.loginsCount == 0 {
+				tc.loginsCount = 1
+			}
+
+			var gids []uint32
+			for i := range tc.loginsCount {
+				gids = append(gids, uint32(33333+2*i), uint32(33334+2*i))
+			}
+			m := newManagerForTests(t, t.TempDir(),
+				users.WithIDGenerator(&idgenerator.IDGeneratorMock{UIDsToGenerate: []uint32{1111}, GIDsToGenerate: gids}),
+				users.WithAccountsServiceDir(accountsDir),
+			)
+
+			u := types.UserInfo{
+				Name:       "user1",
+				Dir:        "/home/user1",
+				Shell:      "/bin/bash",
+				Groups:     []types.GroupInfo{{Name: "group1", UGID: "12345678"}},
+				AvatarData: tc.avatarData,
+			}
+			if tc.avatarPath != "" {
+				u.AvatarURL = server.URL + tc.avatarPath
+			}
+			for range tc.loginsCount {
+				err := m.UpdateUser(context.Background(), u, "broker")
+				require.NoError(t, err, "UpdateUser should not return an error, even if the avatar can't be stored")
+			}
+			require.Equal(t, tc.wantDownloads, int(downloads.Load()), "Avatar was not downloaded the expected number of times")
+
+			content, contentType, err := m.Avatar(u.Name)
+			icon, iconErr := os.ReadFile(filepath.Join(accountsDir, "icons", u.Name))
+			if !tc.wantAvatar {
+				require.ErrorIs(t, err, users.NoDataFoundError{}, "Avatar should return NoDataFoundError")
+				require.ErrorIs(t, iconErr, os.ErrNotExist, "No AccountsService icon should be installed")
+				return
+			}
+			require.NoError(t, err, "Avatar should not return an error, but did")
+			require.Equal(t, picture, content, "Avatar returned an unexpected picture")
+			require.Equal(t, "image/gif", contentType, "Avatar returned an unexpected content type")
+
+			if tc.noAccountsDir {
+				require.NoDirExists(t, accountsDir, "AccountsService directory should not be created")
+				return
+			}
+			require.NoError(t, iconErr, "AccountsService icon should be installed")
+			require.Equal(t, picture, icon, "AccountsService icon should be the picture of the user")
+			settings, err := os.ReadFile(filepath.Join(accountsDir, "users", u.Name))
+			require.NoError(t, err, "AccountsService settings of the user should be written")
+			require.Contains(t, string(settings), "Icon="+filepath.Join(accountsDir, "icons", u.Name),
+				"AccountsService settings should reference the icon")
+		})
+	}
+}
+
+func TestSetAvatar(t *testing.T) {
+	t.Parallel()
+
+	picture := []byte("GIF89a user1 picture")
+
+	tests := map[string]struct {
+		username string
+		content  []byte
+
+		wantErrType error
+	}{
+		"Set_avatar_of_user": {username: "user1", content: picture},
+
+		"Error_if_content_is_not_a_picture": {username: "user1", content: []byte("not a picture"), wantErrType: users.ErrInvalidAvatar},
+		"Error_if_user_does_not_exist":      {username: "doesnotexist", content: picture, wantErrType: users.NoDataFoundError{}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			accountsDir := filepath.Join(t.TempDir(), "AccountsService")
+			require.NoError(t, os.Mkdir(accountsDir, 0700), "Setup: could not create AccountsService directory")
+			m := newManagerForTests(t, t.TempDir(),
+				users.WithIDGenerator(&idgenerator.IDGeneratorMock{UIDsToGenerate: []uint32{1111}, GIDsToGenerate: []uint32{33333, 33334}}),
+				users.WithAccountsServiceDir(accountsDir),
+			)
+			err := m.UpdateUser(context.Background(), types.UserInfo{
+				Name:   "user1",
+				Dir:    "/home/user1",
+				Shell:  "/bin/bash",
+				Groups: []types.GroupInfo{{Name: "group1", UGID: "12345678"}},
+			}, "broker")
+			require.NoError(t, err, "Setup: UpdateUser should not return an error, but did")
+
+			err = m.SetAvatar(tc.username, tc.content)
+			if tc.wantErrType != nil {
+				require.ErrorIs(t, err, tc.wantErrType, "SetAvatar should return the expected error")
+				return
+			}
+			require.NoError(t, err, "SetAvatar should not return an error, but did")
+
+			content, _, err := m.Avatar(tc.username)
+			require.NoError(t, err, "Avatar should not return an error, but did")
+			require.Equal(t, tc.content, content, "Avatar should return the picture set")
+			icon, err := os.ReadFile(filepath.Join(accountsDir, "icons", tc.username))
+			require.NoError(t, err, "AccountsService icon should be installed")
+			require.Equal(t, tc.content, icon, "AccountsService icon should be the picture set")
+		})
+	}
+}
+
 func TestBrokerForUser(t *testing.T) {
 	tests := map[string]struct {
 		username string
@@ -325,24 +689,18 @@ func TestUpdateBrokerForUser(t *testing.T) {
 	}
 }
 
-//nolint:dupl // This is not a duplicate test
-func TestUserByIDAndName(t *testing.T) {
+func TestBrokerSelectionExpired(t *testing.T) {
 	tests := map[string]struct {
-		uid        uint32
-		username   string
-		dbFile     string
-		isTempUser bool
+		expiry      time.Duration
+		noSelection bool
 
-		wantErr     bool
-		wantErrType error
+		wantExpired bool
 	}{
-		"Successfully_get_user_by_ID":             {uid: 1111, dbFile: "multiple_users_and_groups"},
-		"Successfully_get_user_by_name":           {username: "user1", dbFile: "multiple_users_and_groups"},
-		"Successfully_get_temporary_user_by_ID":   {dbFile: "multiple_users_and_groups", isTempUser: true},
-		"Successfully_get_temporary_user_by_name": {username: "tempuser1", dbFile: "multiple_users_and_groups", isTempUser: true},
+		"Selection_does_not_expire_by_default":          {},
+		"Selection_does_not_expire_before_expiry":       {expiry: time.Hour},
+		"Selection_without_recorded_time_never_expires": {expiry: time.Nanosecond, noSelection: true},
 
-		"Error_if_user_does_not_exist_-_by_ID":   {uid: 0, dbFile: "multiple_users_and_groups", wantErrType: db.NoDataFoundError{}},
-		"Error_if_user_does_not_exist_-_by_name": {username: "doesnotexist", dbFile: "multiple_users_and_groups", wantErrType: db.NoDataFoundError{}},
+		"Selection_expires_after_expiry": {expiry: time.Nanosecond, wantExpired: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -350,50 +708,34 @@ func TestUserByIDAndName(t *testing.T) {
 			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
 
 			dbDir := t.TempDir()
-			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "multiple_users_and_groups.db.yaml"), dbDir)
 			require.NoError(t, err, "Setup: could not create database from testdata")
+			config := users.DefaultConfig
+			config.BrokerSelectionExpiry = tc.expiry
+			m, err := users.NewManager(config, dbDir)
+			require.NoError(t, err, "Setup: NewManager should not return an error")
 
-			m := newManagerForTests(t, dbDir)
-
-			if tc.isTempUser {
-				tc.uid, _, err = m.TemporaryRecords().RegisterUser("tempuser1")
-				require.NoError(t, err, "RegisterUser should not return an error, but did")
-			}
-
-			var user types.UserEntry
-			if tc.username != "" {
-				user, err = m.UserByName(tc.username)
-			} else {
-				user, err = m.UserByID(tc.uid)
-			}
-
-			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
-			if tc.wantErrType != nil || tc.wantErr {
-				return
-			}
-
-			// Registering a temporary user creates it with a random UID and random gecos, so we have to make it
-			// deterministic before comparing it with the golden file
-			if tc.isTempUser {
-				require.Equal(t, tc.uid, user.UID)
-				user.UID = 0
-				require.NotEmpty(t, user.Gecos)
-				user.Gecos = ""
+			if !tc.noSelection {
+				err = m.UpdateBrokerForUser("user1", "ExampleBrokerID")
+				require.NoError(t, err, "Setup: UpdateBrokerForUser should not return an error")
 			}
 
-			golden.CheckOrUpdateYAML(t, user)
+			expired, err := m.BrokerSelectionExpired("user1")
+			require.NoError(t, err, "BrokerSelectionExpired should not return an error")
+			require.Equal(t, tc.wantExpired, expired, "BrokerSelectionExpired should return the expected value")
 		})
 	}
 }
 
-func TestAllUsers(t *testing.T) {
+func TestResetBrokerForUser(t *testing.T) {
 	tests := map[string]struct {
-		dbFile string
+		username string
 
-		wantErr     bool
 		wantErrType error
 	}{
-		"Successfully_get_all_users": {dbFile: "multiple_users_and_groups"},
+		"Successfully_reset_broker_for_user": {username: "user1"},
+
+		"Error_if_user_does_not_exist": {username: "doesnotexist", wantErrType: db.NoDataFoundError{}},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -401,165 +743,1060 @@ func TestAllUsers(t *testing.T) {
 			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
 
 			dbDir := t.TempDir()
-			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "multiple_users_and_groups.db.yaml"), dbDir)
 			require.NoError(t, err, "Setup: could not create database from testdata")
 			m := newManagerForTests(t, dbDir)
 
-			got, err := m.AllUsers()
+			err = m.ResetBrokerForUser(tc.username)
 
-			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
-			if tc.wantErrType != nil || tc.wantErr {
+			requireErrorAssertions(t, err, tc.wantErrType, false)
+			if tc.wantErrType != nil {
 				return
 			}
 
-			golden.CheckOrUpdateYAML(t, got)
+			brokerID, err := m.BrokerForUser(tc.username)
+			require.NoError(t, err, "BrokerForUser should not return an error")
+			require.Empty(t, brokerID, "ResetBrokerForUser should forget the broker of the user")
 		})
 	}
 }
 
-//nolint:dupl // This is not a duplicate test
-func TestGroupByIDAndName(t *testing.T) {
+func TestUpdateGecos(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(s string) *string { return &s }
+
 	tests := map[string]struct {
-		gid         uint32
-		groupname   string
-		dbFile      string
-		isTempGroup bool
+		username    string
+		update      gecos.Update
+		login       bool
+		brokerGecos string
+		gecosFields *gecos.Fields
 
+		wantGecos   string
 		wantErr     bool
 		wantErrType error
 	}{
-		"Successfully_get_group_by_ID":             {gid: 11111, dbFile: "multiple_users_and_groups"},
-		"Successfully_get_group_by_name":           {groupname: "group1", dbFile: "multiple_users_and_groups"},
-		"Successfully_get_temporary_group_by_ID":   {dbFile: "multiple_users_and_groups", isTempGroup: true},
-		"Successfully_get_temporary_group_by_name": {groupname: "tempgroup1", dbFile: "multiple_users_and_groups", isTempGroup: true},
+		"Successfully_change_full_name":                 {update: gecos.Update{FullName: ptr("Jane Doe")}, wantGecos: "Jane Doe"},
+		"Successfully_change_room_and_phones":           {update: gecos.Update{Room: ptr("42"), WorkPhone: ptr("555-0100"), HomePhone: ptr("555-0199")}, wantGecos: "User1 gecos\nOn multiple lines,42,555-0100,555-0199"},
+		"Successfully_clear_full_name":                  {update: gecos.Update{FullName: ptr("")}, wantGecos: ""},
+		"Broker_values_take_precedence_on_next_login":   {update: gecos.Update{FullName: ptr("Jane Doe"), Room: ptr("42")}, login: true, brokerGecos: "Broker name,,555-0100", wantGecos: "Broker name,42,555-0100"},
+		"Local_values_are_kept_if_broker_provides_none": {update: gecos.Update{FullName: ptr("Jane Doe"), Room: ptr("42")}, login: true, wantGecos: "Jane Doe,42"},
+		"Broker_GECOS_fields_take_precedence_over_GECOS": {
+			update:      gecos.Update{HomePhone: ptr("555-0199")},
+			login:       true,
+			brokerGecos: "Broker name,Room 1",
+			gecosFields: &gecos.Fields{FullName: "Jane Doe", WorkPhone: "555-0100"},
+			wantGecos:   "Jane Doe,Room 1,555-0100,555-0199",
+		},
 
-		"Error_if_group_does_not_exist_-_by_ID":   {gid: 0, dbFile: "multiple_users_and_groups", wantErrType: db.NoDataFoundError{}},
-		"Error_if_group_does_not_exist_-_by_name": {groupname: "doesnotexist", dbFile: "multiple_users_and_groups", wantErrType: db.NoDataFoundError{}},
+		"Error_if_user_does_not_exist": {username: "doesnotexist", update: gecos.Update{FullName: ptr("Jane Doe")}, wantErrType: users.NoDataFoundError{}},
+		"Error_if_no_field_to_change":  {update: gecos.Update{}, wantErr: true},
+		"Error_if_field_is_invalid":    {update: gecos.Update{Room: ptr("42,43")}, wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			// We don't care about the output of gpasswd in this test, but we still need to mock it.
-			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
-
-			dbDir := t.TempDir()
-			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
-			require.NoError(t, err, "Setup: could not create database from testdata")
-			m := newManagerForTests(t, dbDir)
-
-			if tc.isTempGroup {
-				tc.gid, _, err = m.TemporaryRecords().RegisterGroup("tempgroup1")
-				require.NoError(t, err, "RegisterGroup should not return an error, but did")
-			}
+			t.Parallel()
 
-			var group types.GroupEntry
-			if tc.groupname != "" {
-				group, err = m.GroupByName(tc.groupname)
-			} else {
-				group, err = m.GroupByID(tc.gid)
+			if tc.username == "" {
+				tc.username = "user1"
 			}
 
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "one_user_and_group.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir, users.WithIDGenerator(&idgenerator.IDGeneratorMock{GIDsToGenerate: []uint32{22222}}))
+			var updated []string
+			m.OnUserUpdated(func(name string, isNew bool) {
+				require.False(t, isNew, "Updated user should not be notified as a new user")
+				updated = append(updated, name)
+			})
+
+			err = m.UpdateGecos(tc.username, tc.update)
 			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
 			if tc.wantErrType != nil || tc.wantErr {
+				require.Empty(t, updated, "No user should be notified as updated on error")
 				return
 			}
-
-			// Registering a temporary group creates it with a random GID and random passwd, so we have to make it
-			// deterministic before comparing it with the golden file
-			if tc.isTempGroup {
-				require.Equal(t, tc.gid, group.GID)
-				group.GID = 0
-				require.NotEmpty(t, group.Passwd)
-				group.Passwd = ""
+			require.Equal(t, []string{tc.username}, updated, "User should be notified as updated")
+
+			if tc.login {
+				err = m.UpdateUser(context.Background(), types.UserInfo{
+					Name:        tc.username,
+					Gecos:       tc.brokerGecos,
+					GecosFields: tc.gecosFields,
+					Dir:         "/home/" + tc.username,
+					Shell:       "/bin/bash",
+					Groups:      []types.GroupInfo{{Name: "group1", UGID: "12345678"}},
+				}, "broker")
+				require.NoError(t, err, "UpdateUser should not return an error, but did")
 			}
 
-			golden.CheckOrUpdateYAML(t, group)
+			u, err := m.UserByName(tc.username)
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			require.Equal(t, tc.wantGecos, u.Gecos, "GECOS field of the user is not the expected one")
 		})
 	}
 }
 
-func TestAllGroups(t *testing.T) {
+func TestSetShell(t *testing.T) {
+	t.Parallel()
+
 	tests := map[string]struct {
-		dbFile string
+		username string
+		shell    string
+		login    bool
 
 		wantErr     bool
 		wantErrType error
 	}{
-		"Successfully_get_all_groups": {dbFile: "multiple_users_and_groups"},
+		"Successfully_set_shell":                  {shell: "/usr/bin/zsh"},
+		"Shell_set_locally_is_kept_on_next_login": {shell: "/usr/bin/zsh", login: true},
+		"Successfully_set_other_listed_shell":     {shell: "/bin/sh"},
+
+		"Error_if_user_does_not_exist":     {username: "doesnotexist", shell: "/usr/bin/zsh", wantErrType: users.NoDataFoundError{}},
+		"Error_if_shell_is_not_listed":     {shell: "/usr/bin/fish", wantErrType: users.ErrInvalidOverride},
+		"Error_if_shell_is_not_absolute":   {shell: "bash", wantErrType: users.ErrInvalidOverride},
+		"Error_if_shell_is_a_comment_line": {shell: "# /etc/shells: valid login shells", wantErrType: users.ErrInvalidOverride},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			// We don't care about the output of gpasswd in this test, but we still need to mock it.
-			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+			t.Parallel()
+
+			if tc.username == "" {
+				tc.username = "user1"
+			}
 
 			dbDir := t.TempDir()
-			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "one_user_and_group.db.yaml"), dbDir)
 			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir,
+				users.WithIDGenerator(&idgenerator.IDGeneratorMock{GIDsToGenerate: []uint32{22222}}),
+				users.WithShellsFile(filepath.Join("testdata", "shells")),
+			)
 
-			m := newManagerForTests(t, dbDir)
-
-			got, err := m.AllGroups()
-
+			err = m.SetShell(tc.username, tc.shell)
 			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
 			if tc.wantErrType != nil || tc.wantErr {
 				return
 			}
 
-			golden.CheckOrUpdateYAML(t, got)
+			if tc.login {
+				err = m.UpdateUser(context.Background(), types.UserInfo{
+					Name:   tc.username,
+					Dir:    "/home/" + tc.username,
+					Shell:  "/bin/bash",
+					Groups: []types.GroupInfo{{Name: "group1", UGID: "12345678"}},
+				}, "broker")
+				require.NoError(t, err, "UpdateUser should not return an error, but did")
+			}
+
+			u, err := m.UserByName(tc.username)
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			require.Equal(t, tc.shell, u.Shell, "Login shell of the user is not the expected one")
 		})
 	}
 }
 
-func TestShadowByName(t *testing.T) {
+func TestUserOverrides(t *testing.T) {
 	tests := map[string]struct {
 		username string
-		dbFile   string
+		override users.UserOverride
+		login    bool
+		unset    bool
 
+		wantEntry   types.UserEntry
 		wantErr     bool
 		wantErrType error
 	}{
-		"Successfully_get_shadow_by_name": {username: "user1", dbFile: "multiple_users_and_groups"},
+		"Override_shell_home_and_GECOS": {
+			override:  users.UserOverride{Shell: "/usr/bin/zsh", Dir: "/srv/user1", Gecos: "Jane Doe,42"},
+			wantEntry: types.UserEntry{Shell: "/usr/bin/zsh", Dir: "/srv/user1", Gecos: "Jane Doe,42"},
+		},
+		"Override_is_kept_on_next_login": {
+			override:  users.UserOverride{Shell: "/usr/bin/zsh"},
+			login:     true,
+			wantEntry: types.UserEntry{Shell: "/usr/bin/zsh", Dir: "/home/user1", Gecos: "gecos for user1"},
+		},
+		"Add_local_groups_on_next_login": {
+			override:  users.UserOverride{LocalGroups: []string{"localgroup3", "localgroup1", "localgroup3"}},
+			login:     true,
+			wantEntry: types.UserEntry{Shell: "/bin/bash", Dir: "/home/user1", Gecos: "gecos for user1"},
+		},
+		"Removing_override_restores_attributes_of_broker": {
+			override:  users.UserOverride{Shell: "/usr/bin/zsh", Dir: "/srv/user1"},
+			unset:     true,
+			wantEntry: types.UserEntry{Shell: "/bin/bash", Dir: "/home/user1", Gecos: "User1 gecos\nOn multiple lines"},
+		},
 
-		"Error_if_shadow_does_not_exist": {username: "doesnotexist", dbFile: "multiple_users_and_groups", wantErrType: db.NoDataFoundError{}},
+		"Error_if_user_does_not_exist":         {username: "doesnotexist", override: users.UserOverride{Shell: "/usr/bin/zsh"}, wantErrType: users.NoDataFoundError{}},
+		"Error_if_shell_is_not_listed":         {override: users.UserOverride{Shell: "/usr/bin/fish"}, wantErrType: users.ErrInvalidOverride},
+		"Error_if_home_is_not_absolute":        {override: users.UserOverride{Dir: "home/user1"}, wantErrType: users.ErrInvalidOverride},
+		"Error_if_GECOS_is_invalid":            {override: users.UserOverride{Gecos: "Jane:Doe"}, wantErrType: users.ErrInvalidOverride},
+		"Error_if_local_group_name_is_invalid": {override: users.UserOverride{LocalGroups: []string{"local group"}}, wantErrType: users.ErrInvalidOverride},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			// We don't care about the output of gpasswd in this test, but we still need to mock it.
-			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+			destCmdsFile := localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "users_in_groups.group"))
+
+			if tc.username == "" {
+				tc.username = "user1"
+			}
 
 			dbDir := t.TempDir()
-			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "one_user_and_group.db.yaml"), dbDir)
 			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir,
+				users.WithIDGenerator(&idgenerator.IDGeneratorMock{GIDsToGenerate: []uint32{22222}}),
+				users.WithShellsFile(filepath.Join("testdata", "shells")),
+			)
 
-			m := newManagerForTests(t, dbDir)
-
-			got, err := m.ShadowByName(tc.username)
-
+			err = m.UpdateUserOverride(tc.username, func(o *users.UserOverride) { *o = tc.override })
 			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
 			if tc.wantErrType != nil || tc.wantErr {
 				return
 			}
 
-			golden.CheckOrUpdateYAML(t, got)
+			if tc.unset {
+				err = m.UpdateUserOverride(tc.username, func(o *users.UserOverride) { *o = users.UserOverride{} })
+				require.NoError(t, err, "UpdateUserOverride should not return an error when removing the override")
+			}
+			if tc.login {
+				err = m.UpdateUser(context.Background(), types.UserInfo{
+					Name:   tc.username,
+					Gecos:  "gecos for " + tc.username,
+					Dir:    "/home/" + tc.username,
+					Shell:  "/bin/bash",
+					Groups: []types.GroupInfo{{Name: "group1", UGID: "12345678"}},
+				}, "broker")
+				require.NoError(t, err, "UpdateUser should not return an error, but did")
+			}
+
+			tc.wantEntry.Name = tc.username
+			tc.wantEntry.UID = 1111
+			byName, err := m.UserByName(tc.username)
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			tc.wantEntry.GID = byName.GID
+			require.Equal(t, tc.wantEntry, byName, "UserByName should return the attributes set locally")
+			byID, err := m.UserByID(byName.UID)
+			require.NoError(t, err, "UserByID should not return an error, but did")
+			require.Equal(t, tc.wantEntry, byID, "UserByID should return the attributes set locally")
+			all, err := m.AllUsers()
+			require.NoError(t, err, "AllUsers should not return an error, but did")
+			require.Equal(t, []types.UserEntry{tc.wantEntry}, all, "AllUsers should return the attributes set locally")
+
+			got, err := db.Z_ForTests_DumpNormalizedYAML(userstestutils.GetManagerDB(m))
+			require.NoError(t, err, "Created database should be valid yaml content")
+			golden.CheckOrUpdate(t, got)
+
+			localgroupstestutils.RequireGPasswdOutput(t, destCmdsFile, golden.Path(t)+".gpasswd.output")
 		})
 	}
 }
 
-func TestAllShadows(t *testing.T) {
+func TestLastAuthModeForUser(t *testing.T) {
 	tests := map[string]struct {
-		dbFile string
+		username        string
+		storeAuthMode   bool
+		requestedBroker string
 
-		wantErr bool
+		wantAuthMode  string
+		wantUpdateErr bool
+		wantErrType   error
 	}{
-		"Successfully_get_all_users": {dbFile: "multiple_users_and_groups"},
+		"Successfully_get_last_auth_mode_for_user": {storeAuthMode: true, wantAuthMode: "password"},
+
+		"Error_if_user_has_no_auth_mode_stored":             {wantErrType: users.NoDataFoundError{}},
+		"Error_if_user_has_no_auth_mode_for_the_broker":     {storeAuthMode: true, requestedBroker: "other-broker", wantErrType: users.NoDataFoundError{}},
+		"Error_when_storing_auth_mode_for_nonexistent_user": {username: "doesnotexist", storeAuthMode: true, wantUpdateErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			// We don't care about the output of gpasswd in this test, but we still need to mock it.
 			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
 
-			dbDir := t.TempDir()
-			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
-			require.NoError(t, err, "Setup: could not create database from testdata")
+			if tc.username == "" {
+				tc.username = "user1"
+			}
+			if tc.requestedBroker == "" {
+				tc.requestedBroker = "broker-id"
+			}
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "multiple_users_and_groups.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir)
+
+			if tc.storeAuthMode {
+				err = m.UpdateLastAuthModeForUser(tc.username, "broker-id", "password")
+				if tc.wantUpdateErr {
+					require.Error(t, err, "UpdateLastAuthModeForUser should return an error, but did not")
+					return
+				}
+				require.NoError(t, err, "UpdateLastAuthModeForUser should not return an error, but did")
+			}
+
+			authMode, err := m.LastAuthModeForUser(tc.username, tc.requestedBroker)
+			requireErrorAssertions(t, err, tc.wantErrType, false)
+			if tc.wantErrType != nil {
+				return
+			}
+
+			require.Equal(t, tc.wantAuthMode, authMode, "LastAuthModeForUser should return the expected authentication mode, but did not")
+		})
+	}
+}
+
+func TestConsents(t *testing.T) {
+	tests := map[string]struct {
+		username       string
+		acceptedBefore string
+		message        string
+
+		want        bool
+		wantErrType error
+	}{
+		"Accepted_message":                    {acceptedBefore: "Terms of use", message: "Terms of use", want: true},
+		"Message_never_accepted":              {message: "Terms of use"},
+		"Message_changed_since_acceptance":    {acceptedBefore: "Terms of use", message: "New terms of use"},
+		"Message_of_user_who_never_logged_in": {username: "doesnotexist", message: "Terms of use"},
+
+		"Error_when_recording_consent_of_nonexistent_user": {
+			username: "doesnotexist", acceptedBefore: "Terms of use", wantErrType: users.NoDataFoundError{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			if tc.username == "" {
+				tc.username = "user1"
+			}
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "multiple_users_and_groups.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir)
+
+			if tc.acceptedBefore != "" {
+				err = m.RecordConsent(tc.username, tc.acceptedBefore)
+				if tc.wantErrType != nil {
+					require.ErrorIs(t, err, tc.wantErrType, "RecordConsent should return the expected error")
+					return
+				}
+				require.NoError(t, err, "RecordConsent should not return an error, but did")
+			}
+
+			got, err := m.ConsentAccepted(tc.username, tc.message)
+			require.NoError(t, err, "ConsentAccepted should not return an error, but did")
+			require.Equal(t, tc.want, got, "ConsentAccepted returned an unexpected result")
+		})
+	}
+}
+
+func TestUIDOwner(t *testing.T) {
+	tests := map[string]struct {
+		uid uint32
+
+		want        users.UIDOwner
+		wantErrType error
+	}{
+		"Owner_of_UID_of_existing_user": {uid: 1111, want: users.UIDOwner{Name: "user1"}},
+		"Owner_of_UID_of_deleted_user":  {uid: 2222, want: users.UIDOwner{Name: "olduser", DeletedAt: time.Unix(1700000000, 0)}},
+
+		"Error_if_UID_was_never_used": {uid: 3333, wantErrType: users.NoDataFoundError{}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "one_user_and_deleted_user.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir)
+
+			got, err := m.UIDOwner(tc.uid)
+			if tc.wantErrType != nil {
+				require.ErrorIs(t, err, tc.wantErrType, "UIDOwner should return the expected error")
+				return
+			}
+			require.NoError(t, err, "UIDOwner should not return an error, but did")
+			require.Equal(t, tc.want, got, "UIDOwner returned an unexpected owner")
+		})
+	}
+}
+
+func TestLookupsOfUserBeingAdded(t *testing.T) {
+	// We don't care about the output of gpasswd in this test, but we still need to mock it.
+	_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "users_in_groups.group"))
+
+	// The storage hook blocks until the test checked the lookups, which happen before the user is in the database.
+	hookDir := t.TempDir()
+	hook := filepath.Join(hookDir, "hook")
+	started, resume := filepath.Join(hookDir, "started"), filepath.Join(hookDir, "resume")
+	err := os.WriteFile(hook, []byte(`#!/bin/sh
+touch "$1"
+while [ ! -e "$2" ]; do sleep 0.01; done
+`), 0700)
+	require.NoError(t, err, "Setup: could not write storage hook")
+
+	config := users.DefaultConfig
+	config.StorageHooks = map[string][]string{"broker": {hook, started, resume}}
+	m, err := users.NewManager(config, t.TempDir(), users.WithIDGenerator(&idgenerator.IDGeneratorMock{
+		UIDsToGenerate: []uint32{1111},
+		GIDsToGenerate: []uint32{33333, 33334},
+	}))
+	require.NoError(t, err, "Setup: NewManager should not return an error, but did")
+	t.Cleanup(func() { _ = m.Stop() })
+
+	updateErr := make(chan error)
+	go func() {
+		updateErr <- m.UpdateUser(context.Background(), types.UserInfo{
+			Name:   "user1",
+			Gecos:  "User 1",
+			Dir:    "/home/user1",
+			Shell:  "/bin/bash",
+			Groups: []types.GroupInfo{{Name: "group1", UGID: "12345678"}},
+		}, "broker")
+	}()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(started)
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond, "Setup: the storage hook should be started")
+
+	want := types.UserEntry{Name: "user1", UID: 1111, GID: 33333, Gecos: "User 1", Dir: "/home/user1", Shell: "/bin/bash"}
+
+	u, err := m.UserByName("user1")
+	require.NoError(t, err, "UserByName should find the user being added")
+	require.Equal(t, want, u, "UserByName should return the final attributes of the user being added")
+
+	u, err = m.UserByID(1111)
+	require.NoError(t, err, "UserByID should find the user being added")
+	require.Equal(t, want, u, "UserByID should return the final attributes of the user being added")
+
+	_, err = m.ShadowByName("user1")
+	require.NoError(t, err, "ShadowByName should find the user being added")
+
+	g, err := m.GroupByID(33333)
+	require.NoError(t, err, "GroupByID should find the private group of the user being added")
+	require.Equal(t, "user1", g.Name, "GroupByID should return the private group of the user being added")
+
+	err = os.WriteFile(resume, nil, 0600)
+	require.NoError(t, err, "Setup: could not resume the storage hook")
+	require.NoError(t, <-updateErr, "UpdateUser should not return an error, but did")
+
+	u, err = m.UserByName("user1")
+	require.NoError(t, err, "UserByName should find the added user")
+	require.Equal(t, want, u, "The added user should have the attributes it had while being added")
+}
+
+func TestOwnerOfID(t *testing.T) {
+	tests := map[string]struct {
+		id             uint32
+		preAuthLoginAs string
+
+		want        []users.IDOwner
+		wantErrType error
+	}{
+		"Owners_of_UID_of_user_clashing_with_local_user": {id: 1111, want: []users.IDOwner{
+			{Name: "user1", Source: users.IDSourceUser},
+			{Name: "clashinguser", Source: users.IDSourceLocalUser},
+		}},
+		"Owner_of_UID_of_deleted_user": {id: 2222, want: []users.IDOwner{
+			{Name: "olduser", Source: users.IDSourceDeletedUser, DeletedAt: time.Unix(1700000000, 0)},
+		}},
+		"Owner_of_UID_of_pre_auth_user": {id: 3333, preAuthLoginAs: "newuser@example.com", want: []users.IDOwner{
+			{Name: "newuser@example.com", Source: users.IDSourcePreAuthUser},
+		}},
+		"Owner_of_UID_of_local_user":  {id: 4242, want: []users.IDOwner{{Name: "localuser", Source: users.IDSourceLocalUser}}},
+		"Owner_of_GID_of_group":       {id: 11111, want: []users.IDOwner{{Name: "group1", Source: users.IDSourceGroup}}},
+		"Owner_of_GID_of_local_group": {id: 41, want: []users.IDOwner{{Name: "localgroup1", Source: users.IDSourceLocalGroup}}},
+
+		"Error_if_ID_was_never_used": {id: 6666, wantErrType: users.NoDataFoundError{}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "one_user_and_deleted_user.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir,
+				users.WithIDGenerator(&idgenerator.IDGeneratorMock{UIDsToGenerate: []uint32{3333}}),
+				users.WithPasswdFile(filepath.Join("testdata", "passwd", "passwd")),
+				users.WithGroupFile(filepath.Join("testdata", "groups", "users_in_groups.group")))
+
+			if tc.preAuthLoginAs != "" {
+				_, err := m.RegisterUserPreAuth(tc.preAuthLoginAs)
+				require.NoError(t, err, "Setup: RegisterUserPreAuth should not return an error, but did")
+			}
+
+			got, err := m.OwnerOfID(tc.id)
+			if tc.wantErrType != nil {
+				require.ErrorIs(t, err, tc.wantErrType, "OwnerOfID should return the expected error")
+				return
+			}
+			require.NoError(t, err, "OwnerOfID should not return an error, but did")
+			require.Equal(t, tc.want, got, "OwnerOfID returned unexpected owners")
+		})
+	}
+}
+
+func TestUpdateUserDoesNotReuseUIDsOfDeletedUsers(t *testing.T) {
+	// We don't care about the output of gpasswd in this test, but we still need to mock it.
+	_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "users_in_groups.group"))
+
+	dbDir := t.TempDir()
+	err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "one_user_and_deleted_user.db.yaml"), dbDir)
+	require.NoError(t, err, "Setup: could not create database from testdata")
+	m := newManagerForTests(t, dbDir, users.WithIDGenerator(&idgenerator.IDGeneratorMock{
+		UIDsToGenerate: []uint32{2222, 3333},
+		GIDsToGenerate: []uint32{33333},
+	}))
+
+	err = m.UpdateUser(context.Background(), types.UserInfo{Name: "newuser", Dir: "/home/newuser", Shell: "/bin/bash"}, "")
+	require.NoError(t, err, "UpdateUser should not return an error, but did")
+
+	u, err := m.UserByName("newuser")
+	require.NoError(t, err, "UserByName should not return an error, but did")
+	require.Equal(t, uint32(3333), u.UID, "UpdateUser should not give the UID of a deleted user to a new user")
+}
+
+//nolint:paralleltest // The faults are global to the process.
+func TestUpdateUserRecoversFromCacheWriteFailure(t *testing.T) {
+	// We don't care about the output of gpasswd in this test, but we still need to mock it.
+	_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "users_in_groups.group"))
+
+	m := newManagerForTests(t, t.TempDir())
+	faults.Set(faults.CacheWrite, faults.Fault{Err: errors.New("disk full"), Times: 1})
+	t.Cleanup(func() { faults.Remove(faults.CacheWrite) })
+
+	u := types.UserInfo{Name: "newuser", Dir: "/home/newuser", Shell: "/bin/bash"}
+	err := m.UpdateUser(context.Background(), u, "")
+	require.Error(t, err, "UpdateUser should return an error when the cache can't be written")
+
+	_, err = m.UserByName(u.Name)
+	require.ErrorIs(t, err, users.NoDataFoundError{}, "The user should not be partially added to the cache")
+
+	err = m.UpdateUser(context.Background(), u, "")
+	require.NoError(t, err, "UpdateUser should not return an error once the cache can be written again")
+
+	_, err = m.UserByName(u.Name)
+	require.NoError(t, err, "The user should be in the cache once the cache can be written again")
+}
+
+func TestAdoptUser(t *testing.T) {
+	tests := map[string]struct {
+		localName      string
+		brokerUser     string
+		alreadyAdopted [][2]string
+
+		wantErrType error
+	}{
+		"Adopt_local_user": {localName: "localuser", brokerUser: "brokeruser"},
+
+		"Error_if_local_user_does_not_exist":          {localName: "doesnotexist", brokerUser: "brokeruser", wantErrType: users.NoDataFoundError{}},
+		"Error_if_user_of_broker_already_logged_in":   {localName: "localuser", brokerUser: "user1", wantErrType: users.ErrInvalidAdoption},
+		"Error_if_UID_is_used_by_a_user_of_authd":     {localName: "clashinguser", brokerUser: "brokeruser", wantErrType: users.ErrInvalidAdoption},
+		"Error_if_UID_is_reserved":                    {localName: "daemon", brokerUser: "brokeruser", wantErrType: users.ErrInvalidAdoption},
+		"Error_if_name_of_user_of_broker_is_reserved": {localName: "localuser", brokerUser: "root", wantErrType: users.ErrInvalidAdoption},
+		"Error_if_local_user_was_already_adopted": {
+			localName: "localuser", brokerUser: "brokeruser",
+			alreadyAdopted: [][2]string{{"localuser", "otherbrokeruser"}},
+			wantErrType:    users.ErrInvalidAdoption,
+		},
+		"Error_if_user_of_broker_already_adopted_a_local_user": {
+			localName: "localuser", brokerUser: "brokeruser",
+			alreadyAdopted: [][2]string{{"otherlocaluser", "brokeruser"}},
+			wantErrType:    users.ErrInvalidAdoption,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "users_in_groups.group"))
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "one_user_and_group.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir, users.WithPasswdFile(filepath.Join("testdata", "passwd", "passwd")))
+
+			for _, a := range tc.alreadyAdopted {
+				err := m.AdoptUser(a[0], a[1])
+				require.NoError(t, err, "Setup: AdoptUser should not return an error, but did")
+			}
+
+			err = m.AdoptUser(tc.localName, tc.brokerUser)
+			if tc.wantErrType != nil {
+				require.ErrorIs(t, err, tc.wantErrType, "AdoptUser should return the expected error")
+				return
+			}
+			require.NoError(t, err, "AdoptUser should not return an error, but did")
+
+			err = m.UpdateUser(context.Background(), types.UserInfo{Name: tc.brokerUser, Dir: "/home/" + tc.brokerUser, Shell: "/bin/bash"}, "")
+			require.NoError(t, err, "UpdateUser should not return an error, but did")
+
+			u, err := m.UserByName(tc.brokerUser)
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			require.Equal(t, uint32(4242), u.UID, "The adopting user should have the UID of the local user")
+			require.Equal(t, uint32(4343), u.GID, "The adopting user should have the primary group of the local user")
+			require.Equal(t, "/home/localuser", u.Dir, "The adopting user should have the home directory of the local user")
+
+			// The adoption is kept on the next logins.
+			err = m.UpdateUser(context.Background(), types.UserInfo{Name: tc.brokerUser, Dir: "/home/" + tc.brokerUser, Shell: "/bin/bash"}, "")
+			require.NoError(t, err, "UpdateUser should not return an error on the next login, but did")
+			u, err = m.UserByName(tc.brokerUser)
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			require.Equal(t, uint32(4242), u.UID, "The adopting user should keep the UID of the local user")
+			require.Equal(t, uint32(4343), u.GID, "The adopting user should keep the primary group of the local user")
+		})
+	}
+}
+
+func TestChangeUID(t *testing.T) {
+	tests := map[string]struct {
+		oldUID          uint32
+		newUID          uint32
+		changeTwice     bool
+		brokerSelection bool
+
+		wantErrType error
+	}{
+		"Change_UID":                                 {oldUID: 1111, newUID: 3333},
+		"Change_UID_again_to_resume":                 {oldUID: 1111, newUID: 3333, changeTwice: true},
+		"Change_UID_of_user_with_a_broker_selection": {oldUID: 1111, newUID: 3333, brokerSelection: true},
+
+		"Error_if_UID_does_not_belong_to_a_user":    {oldUID: 4444, newUID: 3333, wantErrType: users.NoDataFoundError{}},
+		"Error_if_UIDs_are_the_same":                {oldUID: 1111, newUID: 1111, wantErrType: users.ErrInvalidUIDChange},
+		"Error_if_new_UID_is_reserved":              {oldUID: 1111, newUID: 10, wantErrType: users.ErrInvalidUIDChange},
+		"Error_if_new_UID_belonged_to_deleted_user": {oldUID: 1111, newUID: 2222, wantErrType: users.ErrInvalidUIDChange},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "one_user_and_deleted_user.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			config := users.DefaultConfig
+			config.BrokerSelectionExpiry = time.Nanosecond
+			m, err := users.NewManager(config, dbDir)
+			require.NoError(t, err, "Setup: NewManager should not return an error, but did")
+
+			if tc.brokerSelection {
+				err := m.UpdateBrokerForUser("user1", "broker-id")
+				require.NoError(t, err, "Setup: UpdateBrokerForUser should not return an error, but did")
+			}
+
+			if tc.changeTwice {
+				_, err := m.ChangeUID(tc.oldUID, tc.newUID)
+				require.NoError(t, err, "Setup: ChangeUID should not return an error, but did")
+			}
+
+			got, err := m.ChangeUID(tc.oldUID, tc.newUID)
+			if tc.wantErrType != nil {
+				require.ErrorIs(t, err, tc.wantErrType, "ChangeUID should return the expected error")
+				return
+			}
+			require.NoError(t, err, "ChangeUID should not return an error, but did")
+			require.Equal(t, "user1", got, "ChangeUID returned an unexpected user name")
+
+			u, err := m.UserByID(tc.newUID)
+			require.NoError(t, err, "UserByID should find the user with the new UID")
+			require.Equal(t, "user1", u.Name, "UserByID returned an unexpected user")
+
+			g, err := m.GroupByID(11111)
+			require.NoError(t, err, "GroupByID should not return an error, but did")
+			require.Equal(t, []string{"user1"}, g.Users, "The user should still be member of their groups")
+
+			_, err = m.UserByID(tc.oldUID)
+			require.ErrorIs(t, err, users.NoDataFoundError{}, "UserByID should not find the user with the old UID")
+
+			o, err := m.UIDOwner(tc.oldUID)
+			require.NoError(t, err, "UIDOwner should not return an error for the old UID")
+			require.Equal(t, "user1", o.Name, "The old UID should be attributed to the user")
+			require.False(t, o.DeletedAt.IsZero(), "The old UID should be archived")
+
+			if !tc.brokerSelection {
+				return
+			}
+			broker, err := m.BrokerForUser("user1")
+			require.NoError(t, err, "BrokerForUser should not return an error, but did")
+			require.Equal(t, "broker-id", broker, "The user should keep their broker")
+			// The selection only expires if its time is still recorded for the user.
+			expired, err := m.BrokerSelectionExpired("user1")
+			require.NoError(t, err, "BrokerSelectionExpired should not return an error, but did")
+			require.True(t, expired, "The broker selection time should be kept with the new UID")
+		})
+	}
+}
+
+func TestNSSSnapshot(t *testing.T) {
+	tests := map[string]struct {
+		enabled     bool
+		oldSnapshot bool
+
+		wantSnapshot bool
+	}{
+		"Write_snapshot_if_enabled":         {enabled: true, wantSnapshot: true},
+		"Replace_old_snapshot_if_enabled":   {enabled: true, oldSnapshot: true, wantSnapshot: true},
+		"Do_not_write_snapshot_if_disabled": {},
+		"Remove_old_snapshot_if_disabled":   {oldSnapshot: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "users_in_groups.group"))
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "one_user_and_group.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			snapshotPath := filepath.Join(t.TempDir(), "nss.snapshot")
+			if tc.oldSnapshot {
+				err := os.WriteFile(snapshotPath, []byte("old snapshot"), 0600)
+				require.NoError(t, err, "Setup: could not write old snapshot")
+			}
+
+			config := users.DefaultConfig
+			config.NSSSnapshot = tc.enabled
+			m, err := users.NewManager(config, dbDir, users.WithNSSSnapshotPath(snapshotPath))
+			require.NoError(t, err, "NewManager should not return an error, but did")
+
+			if !tc.wantSnapshot {
+				require.NoFileExists(t, snapshotPath, "The snapshot should not exist")
+				return
+			}
+
+			snapshot, err := os.ReadFile(snapshotPath)
+			require.NoError(t, err, "The snapshot should be written when the manager is created")
+			require.Contains(t, string(snapshot), "user1", "The snapshot should contain the existing users")
+
+			err = m.UpdateUser(context.Background(), types.UserInfo{Name: "newuser", Dir: "/home/newuser", Shell: "/bin/bash"}, "")
+			require.NoError(t, err, "UpdateUser should not return an error, but did")
+
+			snapshot, err = os.ReadFile(snapshotPath)
+			require.NoError(t, err, "The snapshot should still exist after an update")
+			require.Contains(t, string(snapshot), "newuser", "The snapshot should contain the new users")
+		})
+	}
+}
+
+func TestCheckLocalPIN(t *testing.T) {
+	tests := map[string]struct {
+		username      string
+		noPIN         bool
+		failedBefore  int
+		maxAttempts   int
+		resetAttempts bool
+		pin           string
+
+		want            bool
+		wantFailedAfter int
+		wantSetErr      bool
+		wantErrType     error
+		wantLocked      bool
+	}{
+		"Accept_registered_PIN":                    {pin: "123456", want: true},
+		"Accept_registered_PIN_and_reset_attempts": {failedBefore: 2, maxAttempts: 3, pin: "123456", want: true},
+		"Refuse_other_PIN_and_count_the_attempt":   {failedBefore: 1, maxAttempts: 3, pin: "654321", wantFailedAfter: 2},
+		"Refuse_other_PIN_without_limit":           {failedBefore: 5, pin: "654321", wantFailedAfter: 6},
+		"Accept_PIN_unlocked_by_reset":             {failedBefore: 3, maxAttempts: 3, resetAttempts: true, pin: "123456", want: true},
+
+		"Error_if_PIN_is_locked":                      {failedBefore: 3, maxAttempts: 3, pin: "123456", wantLocked: true, wantFailedAfter: 3},
+		"Error_if_user_has_no_PIN":                    {noPIN: true, pin: "123456", wantErrType: users.NoDataFoundError{}},
+		"Error_when_setting_PIN_for_nonexistent_user": {username: "doesnotexist", wantSetErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			if tc.username == "" {
+				tc.username = "user1"
+			}
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "multiple_users_and_groups.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir)
+
+			if !tc.noPIN {
+				err = m.SetLocalPIN(tc.username, "123456")
+				if tc.wantSetErr {
+					require.ErrorIs(t, err, users.NoDataFoundError{}, "SetLocalPIN should return NoDataFoundError, but did not")
+					return
+				}
+				require.NoError(t, err, "SetLocalPIN should not return an error, but did")
+			}
+			for range tc.failedBefore {
+				ok, err := m.CheckLocalPIN(tc.username, "000000", 0)
+				require.NoError(t, err, "Setup: CheckLocalPIN should not return an error")
+				require.False(t, ok, "Setup: CheckLocalPIN should refuse another PIN")
+			}
+			if tc.resetAttempts {
+				require.NoError(t, m.ResetLocalPINFailedAttempts(tc.username), "Setup: could not reset failed attempts")
+			}
+
+			got, err := m.CheckLocalPIN(tc.username, tc.pin, tc.maxAttempts)
+			if tc.wantLocked {
+				require.ErrorIs(t, err, users.ErrLocalPINLocked, "CheckLocalPIN should return ErrLocalPINLocked, but did not")
+			} else {
+				requireErrorAssertions(t, err, tc.wantErrType, false)
+			}
+			if tc.wantErrType != nil {
+				return
+			}
+			require.Equal(t, tc.want, got, "CheckLocalPIN should return the expected result")
+
+			attempts, err := m.LocalPINFailedAttempts(tc.username)
+			require.NoError(t, err, "LocalPINFailedAttempts should not return an error")
+			require.Equal(t, tc.wantFailedAfter, attempts, "LocalPINFailedAttempts should return the expected number of attempts")
+
+			require.NoError(t, m.RemoveLocalPIN(tc.username), "RemoveLocalPIN should not return an error")
+			_, err = m.CheckLocalPIN(tc.username, tc.pin, tc.maxAttempts)
+			require.ErrorIs(t, err, users.NoDataFoundError{}, "CheckLocalPIN should return NoDataFoundError once the PIN is removed")
+		})
+	}
+}
+
+//nolint:dupl // This is not a duplicate test
+func TestUserByIDAndName(t *testing.T) {
+	tests := map[string]struct {
+		uid        uint32
+		username   string
+		dbFile     string
+		isTempUser bool
+
+		wantErr     bool
+		wantErrType error
+	}{
+		"Successfully_get_user_by_ID":             {uid: 1111, dbFile: "multiple_users_and_groups"},
+		"Successfully_get_user_by_name":           {username: "user1", dbFile: "multiple_users_and_groups"},
+		"Successfully_get_temporary_user_by_ID":   {dbFile: "multiple_users_and_groups", isTempUser: true},
+		"Successfully_get_temporary_user_by_name": {username: "tempuser1", dbFile: "multiple_users_and_groups", isTempUser: true},
+
+		"Error_if_user_does_not_exist_-_by_ID":   {uid: 0, dbFile: "multiple_users_and_groups", wantErrType: db.NoDataFoundError{}},
+		"Error_if_user_does_not_exist_-_by_name": {username: "doesnotexist", dbFile: "multiple_users_and_groups", wantErrType: db.NoDataFoundError{}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m := newManagerForTests(t, dbDir)
+
+			if tc.isTempUser {
+				tc.uid, _, err = m.TemporaryRecords().RegisterUser("tempuser1", "")
+				require.NoError(t, err, "RegisterUser should not return an error, but did")
+			}
+
+			var user types.UserEntry
+			if tc.username != "" {
+				user, err = m.UserByName(tc.username)
+			} else {
+				user, err = m.UserByID(tc.uid)
+			}
+
+			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
+			if tc.wantErrType != nil || tc.wantErr {
+				return
+			}
+
+			// Registering a temporary user creates it with a random UID and random gecos, so we have to make it
+			// deterministic before comparing it with the golden file
+			if tc.isTempUser {
+				require.Equal(t, tc.uid, user.UID)
+				user.UID = 0
+				require.NotEmpty(t, user.Gecos)
+				user.Gecos = ""
+			}
+
+			golden.CheckOrUpdateYAML(t, user)
+		})
+	}
+}
+
+func TestAllUsers(t *testing.T) {
+	tests := map[string]struct {
+		dbFile string
+
+		wantErr     bool
+		wantErrType error
+	}{
+		"Successfully_get_all_users": {dbFile: "multiple_users_and_groups"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir)
+
+			got, err := m.AllUsers()
+
+			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
+			if tc.wantErrType != nil || tc.wantErr {
+				return
+			}
+
+			golden.CheckOrUpdateYAML(t, got)
+		})
+	}
+}
+
+//nolint:dupl // This is not a duplicate test
+func TestGroupByIDAndName(t *testing.T) {
+	tests := map[string]struct {
+		gid         uint32
+		groupname   string
+		dbFile      string
+		isTempGroup bool
+
+		wantErr     bool
+		wantErrType error
+	}{
+		"Successfully_get_group_by_ID":             {gid: 11111, dbFile: "multiple_users_and_groups"},
+		"Successfully_get_group_by_name":           {groupname: "group1", dbFile: "multiple_users_and_groups"},
+		"Successfully_get_temporary_group_by_ID":   {dbFile: "multiple_users_and_groups", isTempGroup: true},
+		"Successfully_get_temporary_group_by_name": {groupname: "tempgroup1", dbFile: "multiple_users_and_groups", isTempGroup: true},
+
+		"Error_if_group_does_not_exist_-_by_ID":   {gid: 0, dbFile: "multiple_users_and_groups", wantErrType: db.NoDataFoundError{}},
+		"Error_if_group_does_not_exist_-_by_name": {groupname: "doesnotexist", dbFile: "multiple_users_and_groups", wantErrType: db.NoDataFoundError{}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+			m := newManagerForTests(t, dbDir)
+
+			if tc.isTempGroup {
+				tc.gid, _, err = m.TemporaryRecords().RegisterGroup("tempgroup1", "")
+				require.NoError(t, err, "RegisterGroup should not return an error, but did")
+			}
+
+			var group types.GroupEntry
+			if tc.groupname != "" {
+				group, err = m.GroupByName(tc.groupname)
+			} else {
+				group, err = m.GroupByID(tc.gid)
+			}
+
+			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
+			if tc.wantErrType != nil || tc.wantErr {
+				return
+			}
+
+			// Registering a temporary group creates it with a random GID and random passwd, so we have to make it
+			// deterministic before comparing it with the golden file
+			if tc.isTempGroup {
+				require.Equal(t, tc.gid, group.GID)
+				group.GID = 0
+				require.NotEmpty(t, group.Passwd)
+				group.Passwd = ""
+			}
+
+			golden.CheckOrUpdateYAML(t, group)
+		})
+	}
+}
+
+func TestAllGroups(t *testing.T) {
+	tests := map[string]struct {
+		dbFile string
+
+		wantErr     bool
+		wantErrType error
+	}{
+		"Successfully_get_all_groups": {dbFile: "multiple_users_and_groups"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m := newManagerForTests(t, dbDir)
+
+			got, err := m.AllGroups()
+
+			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
+			if tc.wantErrType != nil || tc.wantErr {
+				return
+			}
+
+			golden.CheckOrUpdateYAML(t, got)
+		})
+	}
+}
+
+func TestShadowByName(t *testing.T) {
+	tests := map[string]struct {
+		username string
+		dbFile   string
+
+		wantErr     bool
+		wantErrType error
+	}{
+		"Successfully_get_shadow_by_name": {username: "user1", dbFile: "multiple_users_and_groups"},
+
+		"Error_if_shadow_does_not_exist": {username: "doesnotexist", dbFile: "multiple_users_and_groups", wantErrType: db.NoDataFoundError{}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			m := newManagerForTests(t, dbDir)
+
+			got, err := m.ShadowByName(tc.username)
+
+			requireErrorAssertions(t, err, tc.wantErrType, tc.wantErr)
+			if tc.wantErrType != nil || tc.wantErr {
+				return
+			}
+
+			golden.CheckOrUpdateYAML(t, got)
+		})
+	}
+}
+
+func TestAllShadows(t *testing.T) {
+	tests := map[string]struct {
+		dbFile string
+
+		wantErr bool
+	}{
+		"Successfully_get_all_users": {dbFile: "multiple_users_and_groups"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, "empty.group")
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", tc.dbFile+".db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
 
 			m := newManagerForTests(t, dbDir)
 
@@ -593,6 +1830,227 @@ func requireErrorAssertions(t *testing.T, gotErr, wantErrType error, wantErr boo
 	require.NoError(t, gotErr, "Error should not be returned")
 }
 
+func TestRecoverPendingUpdates(t *testing.T) {
+	tests := map[string]struct {
+		pending    []journal.Operation
+		groupsFile string
+
+		wantPending []journal.Operation
+	}{
+		"Complete_update_committed_to_the_database": {pending: []journal.Operation{
+			{User: "user1", UID: 1111, OldLocalGroups: []string{"localgroup1"}},
+		}},
+		"Discard_update_of_user_not_in_the_database": {pending: []journal.Operation{
+			{User: "newuser", UID: 5555, LocalGroups: []string{"localgroup1"}},
+		}},
+		"Discard_update_of_user_with_another_UID": {pending: []journal.Operation{
+			{User: "user1", UID: 5555, OldLocalGroups: []string{"localgroup1"}},
+		}},
+		"Discard_update_with_local_groups_not_committed": {pending: []journal.Operation{
+			{User: "user1", UID: 1111, LocalGroups: []string{"localgroup3"}},
+		}},
+		"Recover_updates_of_several_users": {pending: []journal.Operation{
+			{User: "user1", UID: 1111, OldLocalGroups: []string{"localgroup1"}},
+			{User: "newuser", UID: 5555, LocalGroups: []string{"localgroup1"}},
+		}},
+
+		"Keep_update_which_can_not_be_completed": {
+			pending: []journal.Operation{
+				{User: "user1", UID: 1111, OldLocalGroups: []string{"gpasswdfail"}},
+			},
+			groupsFile: "user_in_gpasswdfail_group.group",
+			wantPending: []journal.Operation{
+				{User: "user1", UID: 1111, OldLocalGroups: []string{"gpasswdfail"}},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.groupsFile == "" {
+				tc.groupsFile = "users_in_groups.group"
+			}
+			destCmdsFile := localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", tc.groupsFile))
+
+			dbDir := t.TempDir()
+			err := db.Z_ForTests_CreateDBFromYAML(filepath.Join("testdata", "db", "multiple_users_and_groups.db.yaml"), dbDir)
+			require.NoError(t, err, "Setup: could not create database from testdata")
+
+			j := journal.New(dbDir)
+			for _, op := range tc.pending {
+				require.NoError(t, j.Begin(op), "Setup: could not record pending operation")
+			}
+
+			_ = newManagerForTests(t, dbDir)
+
+			got, err := j.Pending()
+			require.NoError(t, err, "Pending should not return an error, but did")
+			require.Equal(t, tc.wantPending, got, "Only the updates which could not be recovered should be pending")
+
+			localgroupstestutils.RequireGPasswdOutput(t, destCmdsFile, golden.Path(t)+".gpasswd.output")
+		})
+	}
+}
+
+func TestUpdateUserLeavesNoPendingUpdate(t *testing.T) {
+	// We don't care about the output of gpasswd in this test, but we still need to mock it.
+	_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "users_in_groups.group"))
+
+	dbDir := t.TempDir()
+	m := newManagerForTests(t, dbDir)
+
+	gid := uint32(11111)
+	err := m.UpdateUser(context.Background(), types.UserInfo{
+		Name:   "user1",
+		Dir:    "/home/user1",
+		Shell:  "/bin/bash",
+		Groups: []types.GroupInfo{{Name: "group1", GID: &gid, UGID: "1"}, {Name: "localgroup1"}},
+	}, "")
+	require.NoError(t, err, "UpdateUser should not return an error, but did")
+
+	require.NoFileExists(t, filepath.Join(dbDir, journal.Filename), "The journal should be empty after a complete update")
+}
+
+func TestUpdateUserDefersLocalGroupsWhenGroupFileLocked(t *testing.T) {
+	destCmdsFile := localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "users_in_groups.group"))
+	require.NoError(t, os.WriteFile(destCmdsFile+".lock", nil, 0600), "Setup: could not lock the group file")
+
+	dbDir := t.TempDir()
+	m := newManagerForTests(t, dbDir)
+	t.Cleanup(func() { _ = m.Stop() })
+
+	gid := uint32(11111)
+	err := m.UpdateUser(context.Background(), types.UserInfo{
+		Name:   "user1",
+		Dir:    "/home/user1",
+		Shell:  "/bin/bash",
+		Groups: []types.GroupInfo{{Name: "group1", GID: &gid, UGID: "1"}, {Name: "localgroup3"}},
+	}, "")
+	require.NoError(t, err, "UpdateUser should not fail when the group file is locked")
+
+	_, err = m.UserByName("user1")
+	require.NoError(t, err, "The user should be kept in the database when the group file is locked")
+	pending, err := journal.New(dbDir).Pending()
+	require.NoError(t, err, "Pending should not return an error, but did")
+	require.Len(t, pending, 1, "The update of the local groups should be kept in the journal")
+	require.Equal(t, 1, m.ApplyDeferredUpdates(), "The update should stay deferred while the group file is locked")
+
+	require.NoError(t, os.Remove(destCmdsFile+".lock"), "Setup: could not unlock the group file")
+	require.Zero(t, m.ApplyDeferredUpdates(), "The deferred update should be applied once the group file is unlocked")
+	require.NoFileExists(t, filepath.Join(dbDir, journal.Filename), "The journal should be empty once the deferred update is applied")
+
+	localgroupstestutils.RequireGPasswdOutput(t, destCmdsFile, golden.Path(t)+".gpasswd.output")
+}
+
+func TestUpdateUserConcurrently(t *testing.T) {
+	// We don't care about the output of gpasswd in this test, but we still need to mock it.
+	_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "users_in_groups.group"))
+
+	dbDir := t.TempDir()
+	m := newManagerForTests(t, dbDir)
+
+	const logins = 10
+	gid := uint32(11111)
+	var wg sync.WaitGroup
+	errs := make(chan error, logins)
+	for i := range logins {
+		localGroup := "localgroup1"
+		if i%2 == 1 {
+			localGroup = "localgroup3"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- m.UpdateUser(context.Background(), types.UserInfo{
+				Name:   "user1",
+				Gecos:  fmt.Sprintf("login %d", i),
+				Dir:    "/home/user1",
+				Shell:  "/bin/bash",
+				Groups: []types.GroupInfo{{Name: "group1", GID: &gid, UGID: "1"}, {Name: localGroup}},
+			}, "")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err, "UpdateUser should not return an error when the same user logs in concurrently")
+	}
+
+	u, err := m.UserByName("user1")
+	require.NoError(t, err, "UserByName should not return an error, but did")
+	require.Regexp(t, `^login \d$`, u.Gecos, "The user should have the attributes of one of the logins")
+
+	g, err := m.GroupByName("group1")
+	require.NoError(t, err, "GroupByName should not return an error, but did")
+	require.Equal(t, []string{"user1"}, g.Users, "The user should be a member of their group only once")
+
+	require.NoFileExists(t, filepath.Join(dbDir, journal.Filename), "No update should be left pending")
+	require.Zero(t, m.LockedUsers(), "The locks of the user should be released")
+}
+
+func TestReconcileLocalGroups(t *testing.T) {
+	tests := map[string]struct {
+		groupsFile string
+
+		want []users.ReconciledUser
+	}{
+		"Add_user_back_to_the_local_groups_they_are_missing_from": {
+			groupsFile: "gpasswdfail_in_deleted_group.group",
+			want:       []users.ReconciledUser{{Name: "user1", AddedGroups: []string{"localgroup1"}}},
+		},
+		"No-Op_when_users_are_in_their_local_groups": {groupsFile: "users_in_groups.group"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// We don't care about the output of gpasswd in this test, but we still need to mock it.
+			_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", tc.groupsFile))
+
+			m := newManagerForTests(t, t.TempDir())
+
+			gid := uint32(11111)
+			err := m.UpdateUser(context.Background(), types.UserInfo{
+				Name:   "user1",
+				Dir:    "/home/user1",
+				Shell:  "/bin/bash",
+				Groups: []types.GroupInfo{{Name: "group1", GID: &gid, UGID: "1"}, {Name: "localgroup1"}},
+			}, "")
+			require.NoError(t, err, "Setup: UpdateUser should not return an error, but did")
+
+			got, err := m.ReconcileLocalGroups()
+			require.NoError(t, err, "ReconcileLocalGroups should not return an error, but did")
+			require.Equal(t, tc.want, got, "ReconcileLocalGroups should return the users added back to their local groups")
+		})
+	}
+}
+
+func TestRolesForUser(t *testing.T) {
+	// We don't care about the output of gpasswd in this test, but we still need to mock it.
+	_ = localgroupstestutils.SetupGPasswdMock(t, filepath.Join("testdata", "groups", "empty.group"))
+
+	m := newManagerForTests(t, t.TempDir())
+
+	gid := uint32(11111)
+	user := types.UserInfo{
+		Name:   "user1",
+		Dir:    "/home/user1",
+		Shell:  "/bin/bash",
+		Groups: []types.GroupInfo{{Name: "group1", GID: &gid, UGID: "1"}},
+	}
+
+	// The roles of each login replace the previous ones.
+	for _, roles := range [][]string{{"dev-laptops", "admins"}, {"servers"}, nil} {
+		user.Roles = roles
+		err := m.UpdateUser(context.Background(), user, "")
+		require.NoError(t, err, "UpdateUser should not return an error, but did")
+
+		got, err := m.RolesForUser("user1")
+		require.NoError(t, err, "RolesForUser should not return an error, but did")
+		require.ElementsMatch(t, roles, got, "RolesForUser should return the roles of the last login")
+	}
+
+	_, err := m.RolesForUser("nonexistent")
+	require.ErrorIs(t, err, users.NoDataFoundError{}, "RolesForUser should return NoDataFoundError for an unknown user")
+}
+
 func newManagerForTests(t *testing.T, dbDir string, opts ...users.Option) *users.Manager {
 	t.Helper()
 
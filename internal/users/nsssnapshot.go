@@ -0,0 +1,44 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/ubuntu/authd/internal/users/nsssnapshot"
+	"github.com/ubuntu/authd/log"
+)
+
+// updateNSSSnapshot regenerates the snapshot of the NSS entries after they changed, if it's enabled. Otherwise, it
+// removes the snapshot left when it was enabled, so that the NSS module doesn't use outdated entries.
+//
+// The snapshot doesn't contain the temporary records, which the NSS module always asks the daemon for.
+func (m *Manager) updateNSSSnapshot() {
+	m.nssSnapshotMu.Lock()
+	defer m.nssSnapshotMu.Unlock()
+
+	if m.config.NSSSnapshot {
+		err := m.writeNSSSnapshot()
+		if err == nil {
+			return
+		}
+		log.Warningf(context.Background(), "Could not update the NSS snapshot, the NSS module will query the daemon: %v", err)
+	}
+
+	if err := os.Remove(m.nssSnapshotPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warningf(context.Background(), "Could not remove the NSS snapshot: %v", err)
+	}
+}
+
+func (m *Manager) writeNSSSnapshot() error {
+	users, err := m.AllUsers()
+	if err != nil {
+		return err
+	}
+	groups, err := m.AllGroups()
+	if err != nil {
+		return err
+	}
+	return nsssnapshot.Write(m.nssSnapshotPath, users, groups)
+}
@@ -0,0 +1,129 @@
+// Package nsssnapshot writes the snapshot of the passwd and group entries of authd, which the NSS module maps in
+// memory to look up the users and groups without querying the daemon.
+//
+// The snapshot is a binary file in little-endian byte order, whatever the byte order of the machine:
+//
+//	header:  magic ("AUTHDNSS", 8 bytes), version (u32), number of passwd entries (u32), number of group entries (u32)
+//	passwd:  uid (u32), gid (u32), name, passwd, gecos, dir, shell
+//	group:   gid (u32), name, passwd, number of members (u32), members
+//
+// where each string is its length in bytes (u32) followed by its bytes, without a terminating NUL.
+package nsssnapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ubuntu/authd/internal/users/types"
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// Magic identifies the snapshot files.
+	Magic = "AUTHDNSS"
+	// Version is the version of the format of the snapshot, which is increased on incompatible changes.
+	Version uint32 = 1
+)
+
+// Write atomically replaces the snapshot at path with the passwd and group entries, so that the NSS module never maps
+// a partially written snapshot.
+func Write(path string, users []types.UserEntry, groups []types.GroupEntry) (err error) {
+	defer decorate.OnError(&err, "could not write NSS snapshot %s", path)
+
+	data, err := Encode(users, groups)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".authd-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	defer tmp.Close()
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	// The entries are public, like the ones of /etc/passwd and /etc/group. The shadow entries are never written.
+	if err := tmp.Chmod(0644); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Encode returns the snapshot of the passwd and group entries.
+func Encode(users []types.UserEntry, groups []types.GroupEntry) ([]byte, error) {
+	e := encoder{}
+	e.buf.WriteString(Magic)
+	e.uint32(Version)
+	e.len(len(users))
+	e.len(len(groups))
+
+	for _, u := range users {
+		e.uint32(u.UID)
+		e.uint32(u.GID)
+		// The password is always in the shadow entries, like for the gRPC service.
+		for _, s := range []string{u.Name, "x", u.Gecos, u.Dir, u.Shell} {
+			e.string(s)
+		}
+	}
+	for _, g := range groups {
+		e.uint32(g.GID)
+		e.string(g.Name)
+		e.string(g.Passwd)
+		e.len(len(g.Users))
+		for _, m := range g.Users {
+			e.string(m)
+		}
+	}
+
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// encoder writes the values in the byte order of the snapshot, remembering the first error.
+type encoder struct {
+	buf bytes.Buffer
+	err error
+}
+
+func (e *encoder) uint32(v uint32) {
+	e.buf.Write(binary.LittleEndian.AppendUint32(nil, v))
+}
+
+func (e *encoder) len(n int) {
+	if n > math.MaxUint32 {
+		e.err = fmt.Errorf("too many entries: %d", n)
+		return
+	}
+	e.uint32(uint32(n))
+}
+
+func (e *encoder) string(s string) {
+	if strings.ContainsRune(s, 0) {
+		// The NSS entries are C strings.
+		e.err = fmt.Errorf("invalid NUL character in %q", s)
+		return
+	}
+	e.len(len(s))
+	e.buf.WriteString(s)
+}
@@ -0,0 +1,128 @@
+package nsssnapshot_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/users/nsssnapshot"
+	"github.com/ubuntu/authd/internal/users/types"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	users := []types.UserEntry{
+		{Name: "user1", UID: 1111, GID: 11111, Gecos: "User 1", Dir: "/home/user1", Shell: "/bin/bash"},
+		{Name: "user2", UID: 2222, GID: 22222, Dir: "/home/user2", Shell: "/bin/zsh"},
+	}
+	groups := []types.GroupEntry{
+		{Name: "group1", GID: 11111, Users: []string{"user1", "user2"}},
+		{Name: "group2", GID: 22222, Passwd: "x"},
+	}
+
+	tests := map[string]struct {
+		users            []types.UserEntry
+		groups           []types.GroupEntry
+		existingSnapshot bool
+		noDir            bool
+
+		wantErr bool
+	}{
+		"Write_entries":                     {users: users, groups: groups},
+		"Write_no_entries":                  {},
+		"Replace_snapshot":                  {users: users, groups: groups},
+		"Error_on_NUL_char":                 {users: []types.UserEntry{{Name: "user\x001"}}, wantErr: true},
+		"Error_if_directory_does_not_exist": {users: users, noDir: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "nss.snapshot")
+			if tc.noDir {
+				path = filepath.Join(t.TempDir(), "doesnotexist", "nss.snapshot")
+			}
+			if tc.existingSnapshot {
+				require.NoError(t, nsssnapshot.Write(path, nil, nil), "Setup: could not write previous snapshot")
+			}
+
+			err := nsssnapshot.Write(path, tc.users, tc.groups)
+			if tc.wantErr {
+				require.Error(t, err, "Write should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Write should not return an error, but did")
+
+			fi, err := os.Stat(path)
+			require.NoError(t, err, "The snapshot should exist")
+			require.Equal(t, os.FileMode(0644), fi.Mode().Perm(), "The snapshot should be readable by everyone")
+			entries, err := os.ReadDir(filepath.Dir(path))
+			require.NoError(t, err, "Setup: could not read snapshot directory")
+			require.Len(t, entries, 1, "No temporary file should be left")
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err, "Setup: could not read snapshot")
+			gotUsers, gotGroups := decode(t, data)
+
+			wantUsers := append([]types.UserEntry{}, tc.users...)
+			wantGroups := make([]types.GroupEntry, 0, len(tc.groups))
+			for _, g := range tc.groups {
+				if g.Users == nil {
+					g.Users = []string{}
+				}
+				wantGroups = append(wantGroups, g)
+			}
+			require.Equal(t, wantUsers, gotUsers, "The snapshot should contain the passwd entries")
+			require.Equal(t, wantGroups, gotGroups, "The snapshot should contain the group entries")
+		})
+	}
+}
+
+// decode parses the snapshot like the NSS module does.
+func decode(t *testing.T, data []byte) (users []types.UserEntry, groups []types.GroupEntry) {
+	t.Helper()
+
+	require.Equal(t, nsssnapshot.Magic, string(data[:8]), "The snapshot should start with the magic")
+	data = data[8:]
+
+	u32 := func() uint32 {
+		t.Helper()
+		require.GreaterOrEqual(t, len(data), 4, "The snapshot is truncated")
+		v := binary.LittleEndian.Uint32(data)
+		data = data[4:]
+		return v
+	}
+	str := func() string {
+		t.Helper()
+		n := int(u32())
+		require.GreaterOrEqual(t, len(data), n, "The snapshot is truncated")
+		s := string(data[:n])
+		data = data[n:]
+		return s
+	}
+
+	require.Equal(t, nsssnapshot.Version, u32(), "The snapshot should have the current version")
+	nUsers, nGroups := u32(), u32()
+
+	users = []types.UserEntry{}
+	for range nUsers {
+		u := types.UserEntry{UID: u32(), GID: u32(), Name: str()}
+		require.Equal(t, "x", str(), "The password should always be in the shadow entries")
+		u.Gecos, u.Dir, u.Shell = str(), str(), str()
+		users = append(users, u)
+	}
+	groups = []types.GroupEntry{}
+	for range nGroups {
+		g := types.GroupEntry{GID: u32(), Name: str(), Passwd: str(), Users: []string{}}
+		for range u32() {
+			g.Users = append(g.Users, str())
+		}
+		groups = append(groups, g)
+	}
+	require.Empty(t, data, "The snapshot should not contain trailing data")
+
+	return users, groups
+}
@@ -0,0 +1,103 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/gecos"
+	"github.com/ubuntu/authd/internal/users/types"
+	"github.com/ubuntu/decorate"
+)
+
+// ErrInvalidOverride is returned when an attribute of a user set locally is not valid.
+var ErrInvalidOverride = errors.New("invalid user override")
+
+// UpdateUserOverride changes the attributes of the user set locally by the administrator with the given function. They
+// take precedence over the ones provided by the broker, which are kept unchanged in the database, so that removing the
+// override restores them.
+func (m *Manager) UpdateUserOverride(username string, update func(o *UserOverride)) (err error) {
+	defer decorate.OnError(&err, "could not update the override of user %q", username)
+
+	m.updateUserMu.Lock()
+	defer m.updateUserMu.Unlock()
+
+	o, err := m.db.UserOverrideForUser(username)
+	if err != nil && !errors.Is(err, db.NoDataFoundError{}) {
+		return err
+	}
+
+	update(&o)
+	o.LocalGroups = slices.Compact(slices.Sorted(slices.Values(o.LocalGroups)))
+	if err := m.checkOverride(o); err != nil {
+		return err
+	}
+
+	err = m.db.SetUserOverrideForUser(username, o)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return NoDataFoundError{}
+	}
+	if err != nil {
+		return err
+	}
+	m.updateNSSSnapshot()
+
+	m.notifyUserUpdated(username, false)
+	return nil
+}
+
+// UserOverrides returns the attributes set locally of all the users, indexed by user name.
+func (m *Manager) UserOverrides() (map[string]UserOverride, error) {
+	return m.db.AllUserOverrides()
+}
+
+// userOverride returns the attributes set locally of the user, which are empty if none was set.
+func (m *Manager) userOverride(username string) (UserOverride, error) {
+	o, err := m.db.UserOverrideForUser(username)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return UserOverride{}, nil
+	}
+	return o, err
+}
+
+// checkOverride returns ErrInvalidOverride if an attribute of the override can't be used in the user entries.
+func (m *Manager) checkOverride(o UserOverride) error {
+	if o.Shell != "" {
+		if err := checkShell(m.shellsFile, o.Shell); err != nil {
+			return err
+		}
+	}
+	if o.Dir != "" && !filepath.IsAbs(o.Dir) {
+		return fmt.Errorf("%w: home directory %q is not an absolute path", ErrInvalidOverride, o.Dir)
+	}
+	if strings.ContainsAny(o.Gecos, ":\n") {
+		return fmt.Errorf("%w: GECOS %q contains ':' or a newline", ErrInvalidOverride, o.Gecos)
+	}
+	if err := gecos.Parse(o.Gecos).Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidOverride, err)
+	}
+	for _, g := range o.LocalGroups {
+		if g == "" || strings.ContainsAny(g, ",: \t\n") {
+			return fmt.Errorf("%w: invalid local group name %q", ErrInvalidOverride, g)
+		}
+	}
+	return nil
+}
+
+// applyOverride returns the user entry with the attributes set locally taking precedence over the ones provided by the
+// broker.
+func applyOverride(u types.UserEntry, o UserOverride) types.UserEntry {
+	if o.Shell != "" {
+		u.Shell = o.Shell
+	}
+	if o.Dir != "" {
+		u.Dir = o.Dir
+	}
+	if o.Gecos != "" {
+		u.Gecos = o.Gecos
+	}
+	return u
+}
@@ -0,0 +1,131 @@
+package users
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/decorate"
+)
+
+// defaultGroupFile is the file listing the local groups.
+const defaultGroupFile = "/etc/group"
+
+// The sources an ID can be owned by.
+const (
+	// IDSourceUser is a user of authd.
+	IDSourceUser = "user"
+	// IDSourceDeletedUser is a user of authd which was deleted.
+	IDSourceDeletedUser = "deleted-user"
+	// IDSourceTemporaryUser is a user being added to the database after authenticating.
+	IDSourceTemporaryUser = "temporary-user"
+	// IDSourcePreAuthUser is a user registered before authenticating, for example by SSH.
+	IDSourcePreAuthUser = "pre-auth-user"
+	// IDSourceLocalUser is a user of the local passwd file.
+	IDSourceLocalUser = "local-user"
+	// IDSourceGroup is a group of authd.
+	IDSourceGroup = "group"
+	// IDSourceTemporaryGroup is a group being added to the database after its user authenticated.
+	IDSourceTemporaryGroup = "temporary-group"
+	// IDSourceLocalGroup is a group of the local group file.
+	IDSourceLocalGroup = "local-group"
+)
+
+// IDOwner is a user or group an ID belongs to.
+type IDOwner struct {
+	Name string
+	// Source is where the owner comes from, one of the IDSource constants.
+	Source string
+	// DeletedAt is when the user was deleted, or the zero time if it still exists.
+	DeletedAt time.Time
+}
+
+// OwnerOfID returns all the users and groups the ID belongs to, whether they are users and groups of authd, temporary
+// ones or local ones, so that the collisions of IDs can be debugged.
+// The users are returned first, then the groups.
+func (m *Manager) OwnerOfID(id uint32) (owners []IDOwner, err error) {
+	defer decorate.OnError(&err, "could not find the owners of ID %d", id)
+
+	u, err := m.db.UserByID(id)
+	if err == nil {
+		owners = append(owners, IDOwner{Name: u.Name, Source: IDSourceUser})
+	} else if !errors.Is(err, db.NoDataFoundError{}) {
+		return nil, err
+	}
+
+	d, err := m.db.DeletedUserByID(id)
+	if err == nil {
+		owners = append(owners, IDOwner{Name: d.Name, Source: IDSourceDeletedUser, DeletedAt: time.Unix(d.DeletedAt, 0)})
+	} else if !errors.Is(err, db.NoDataFoundError{}) {
+		return nil, err
+	}
+
+	if p, err := m.temporaryRecords.PreAuthUserByID(id); err == nil {
+		// The name of a pre-auth user is random, the login name it was registered for is in its GECOS.
+		owners = append(owners, IDOwner{Name: p.Gecos, Source: IDSourcePreAuthUser})
+	} else if t, err := m.temporaryRecords.UserByID(id); err == nil {
+		owners = append(owners, IDOwner{Name: t.Name, Source: IDSourceTemporaryUser})
+	}
+
+	local, err := localOwnersOfID(m.passwdFile, id, IDSourceLocalUser)
+	if err != nil {
+		return nil, err
+	}
+	owners = append(owners, local...)
+
+	g, err := m.db.GroupByID(id)
+	if err == nil {
+		owners = append(owners, IDOwner{Name: g.Name, Source: IDSourceGroup})
+	} else if !errors.Is(err, db.NoDataFoundError{}) {
+		return nil, err
+	}
+
+	if t, err := m.temporaryRecords.GroupByID(id); err == nil {
+		owners = append(owners, IDOwner{Name: t.Name, Source: IDSourceTemporaryGroup})
+	}
+
+	local, err = localOwnersOfID(m.groupFile, id, IDSourceLocalGroup)
+	if err != nil {
+		return nil, err
+	}
+	owners = append(owners, local...)
+
+	if len(owners) == 0 {
+		return nil, NoDataFoundError{}
+	}
+	return owners, nil
+}
+
+// localOwnersOfID returns the entries of the passwd or group file whose UID or GID is the given ID. Both files have the
+// name in the first field and the ID in the third one.
+func localOwnersOfID(path string, id uint32, source string) (owners []IDOwner, err error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 3 {
+			continue
+		}
+		if entryID, err := strconv.ParseUint(fields[2], 10, 32); err != nil || uint32(entryID) != id {
+			continue
+		}
+		owners = append(owners, IDOwner{Name: fields[0], Source: source})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return owners, nil
+}
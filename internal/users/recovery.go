@@ -0,0 +1,87 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/internal/users/journal"
+	"github.com/ubuntu/authd/internal/users/localentries"
+	"github.com/ubuntu/authd/log"
+)
+
+// recoverPendingUpdates completes or discards the updates of users which were interrupted by a stop of the daemon.
+//
+// The updates committed to the database are rolled forward by updating the local groups, which the daemon may not
+// have done. The other ones are rolled back by discarding them: nothing was changed yet, and they are done again on the
+// next login of the user.
+// An update which can't be recovered is kept in the journal to be retried on the next start, or in the background if
+// the group file is locked.
+func (m *Manager) recoverPendingUpdates() {
+	ops, err := m.journal.Pending()
+	if err != nil {
+		log.Warningf(context.Background(), "Could not recover interrupted updates of users: %v", err)
+		return
+	}
+
+	for _, op := range ops {
+		err := m.recoverUpdate(op)
+		if errors.Is(err, localentries.ErrGroupFileLocked) {
+			log.Warningf(context.Background(), "Deferring interrupted update of user %q: %v", op.User, err)
+			m.deferLocalGroupsUpdate(op.User)
+			continue
+		}
+		if err != nil {
+			log.Warningf(context.Background(), "Could not recover interrupted update of user %q: %v", op.User, err)
+			continue
+		}
+		if err := m.journal.Done(op.User); err != nil {
+			log.Warningf(context.Background(), "%v", err)
+		}
+	}
+}
+
+// recoverUpdate rolls the update forward if it was committed to the database.
+func (m *Manager) recoverUpdate(op journal.Operation) error {
+	committed, err := m.isUpdateCommitted(op)
+	if err != nil {
+		return err
+	}
+	if !committed {
+		log.Infof(context.Background(), "Discarding interrupted update of user %q, which was not committed", op.User)
+		return nil
+	}
+
+	log.Infof(context.Background(), "Completing interrupted update of user %q", op.User)
+	return localentries.Update(context.Background(), op.User, op.LocalGroups, op.OldLocalGroups)
+}
+
+// isUpdateCommitted returns whether the user is in the database with the UID and local groups of the update.
+func (m *Manager) isUpdateCommitted(op journal.Operation) (bool, error) {
+	u, err := m.db.UserByName(op.User)
+	if errors.Is(err, db.NoDataFoundError{}) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if u.UID != op.UID {
+		return false, nil
+	}
+
+	localGroups, err := m.db.UserLocalGroups(op.UID)
+	if err != nil && !errors.Is(err, db.NoDataFoundError{}) {
+		return false, err
+	}
+
+	return sameGroups(localGroups, op.LocalGroups), nil
+}
+
+// sameGroups returns whether both lists contain the same groups, whatever their order.
+func sameGroups(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
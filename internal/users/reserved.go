@@ -0,0 +1,67 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ubuntu/authd/internal/users/idgenerator"
+	"github.com/ubuntu/authd/log"
+)
+
+// defaultReservedMinID is the lowest ID which can be assigned to the users and groups of the brokers, below which the
+// IDs are reserved to the system accounts.
+const defaultReservedMinID = 1000
+
+// systemAccounts are the names of the common system accounts, which the users of the brokers can never use.
+var systemAccounts = []string{
+	"root", "daemon", "bin", "sys", "sync", "games", "man", "lp", "mail", "news", "uucp", "proxy", "www-data",
+	"backup", "list", "irc", "gnats", "nobody", "messagebus", "sshd", "syslog",
+}
+
+// ErrReservedAccount is returned when a broker provides a user which would create or shadow a system account.
+var ErrReservedAccount = errors.New("reserved account")
+
+// ReservedAccountsConfig protects the system accounts from being created or shadowed by the users of the brokers.
+type ReservedAccountsConfig struct {
+	// Names are the user names refused in addition to the built-in list of system accounts.
+	Names []string `mapstructure:"names"`
+	// MinID is the lowest UID and GID which can be assigned to the users and groups of the brokers.
+	MinID uint32 `mapstructure:"min_id"`
+}
+
+// checkRanges returns an error if some IDs of the ranges are reserved to the system accounts.
+func (c ReservedAccountsConfig) checkRanges(r idgenerator.Ranges) error {
+	if r.UIDMin < c.MinID {
+		return fmt.Errorf("UID_MIN (%d) must not be lower than the minimum ID of the reserved accounts (%d)", r.UIDMin, c.MinID)
+	}
+	if r.GIDMin < c.MinID {
+		return fmt.Errorf("GID_MIN (%d) must not be lower than the minimum ID of the reserved accounts (%d)", r.GIDMin, c.MinID)
+	}
+	return nil
+}
+
+// checkReservedName returns an error, and records it for auditing, if the name of the user provided by the broker is
+// the one of a system account or is in the configured deny-list.
+func (m *Manager) checkReservedName(name, brokerName string) error {
+	if !slices.Contains(systemAccounts, name) && !slices.Contains(m.config.ReservedAccounts.Names, name) {
+		return nil
+	}
+	return refuseReservedAccount(name, brokerName, "the name is reserved")
+}
+
+// checkReservedUID returns an error, and records it for auditing, if the UID of the user provided by the broker is
+// reserved to the system accounts. It can only happen for users stored before the reserved IDs were configured.
+func (m *Manager) checkReservedUID(name string, uid uint32, brokerName string) error {
+	if uid >= m.config.ReservedAccounts.MinID {
+		return nil
+	}
+	return refuseReservedAccount(name, brokerName, fmt.Sprintf("the UID %d is reserved", uid))
+}
+
+// refuseReservedAccount records for auditing that the user provided by the broker was refused, and returns the error.
+func refuseReservedAccount(name, brokerName, reason string) error {
+	log.Noticef(context.Background(), "Audit: refused user %q provided by broker %q: %s", name, brokerName, reason)
+	return fmt.Errorf("%w: %s", ErrReservedAccount, reason)
+}
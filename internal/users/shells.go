@@ -0,0 +1,57 @@
+package users
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultShellsFile is the file listing the valid login shells.
+const defaultShellsFile = "/etc/shells"
+
+// SetShell sets the login shell of the user locally. It takes precedence over the one provided by the broker.
+func (m *Manager) SetShell(username, shell string) error {
+	return m.UpdateUserOverride(username, func(o *UserOverride) {
+		o.Shell = shell
+	})
+}
+
+// checkShell returns ErrInvalidOverride if the shell is not an absolute path listed in the given shells file.
+func checkShell(shellsFile, shell string) error {
+	if !filepath.IsAbs(shell) {
+		return fmt.Errorf("%w: shell %q is not an absolute path", ErrInvalidOverride, shell)
+	}
+
+	listed, err := isListedShell(shellsFile, shell)
+	if err != nil {
+		return err
+	}
+	if !listed {
+		return fmt.Errorf("%w: shell %q is not listed in %s", ErrInvalidOverride, shell, shellsFile)
+	}
+	return nil
+}
+
+// isListedShell returns whether the shell is listed in the given shells file.
+func isListedShell(shellsFile, shell string) (bool, error) {
+	f, err := os.Open(shellsFile)
+	if err != nil {
+		return false, fmt.Errorf("could not read valid login shells: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == shell {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("could not read valid login shells: %w", err)
+	}
+
+	return false, nil
+}
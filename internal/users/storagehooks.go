@@ -0,0 +1,88 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ubuntu/authd/internal/users/db"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// storageHookTimeout is the maximum time a storage hook can take to set up the storage of a user.
+const storageHookTimeout = 2 * time.Minute
+
+// checkStorageHooks checks that the storage hooks are commands with an absolute path.
+func checkStorageHooks(hooks map[string][]string) error {
+	for broker, hook := range hooks {
+		if len(hook) == 0 {
+			return fmt.Errorf("empty storage hook for broker %q", broker)
+		}
+		if !filepath.IsAbs(hook[0]) {
+			return fmt.Errorf("storage hook for broker %q is not an absolute path: %s", broker, hook[0])
+		}
+	}
+	return nil
+}
+
+// storageHook returns the storage hook configured for the broker, if any.
+func (m *Manager) storageHook(brokerName string) []string {
+	for name, hook := range m.config.StorageHooks {
+		if strings.EqualFold(name, brokerName) {
+			return hook
+		}
+	}
+	return nil
+}
+
+// runStorageHook runs the storage hook configured for the broker, if any, to set up the storage of a user on their
+// first login, for example by creating an encrypted home directory or by calling systemd-homed.
+//
+// The user details are passed in the environment of the hook, and the secret provided by the broker, which can be used
+// to unlock the volume, on its standard input. The hook is killed when ctx is done.
+func (m *Manager) runStorageHook(ctx context.Context, brokerName string, u db.UserRow, secret string) (err error) {
+	hook := m.storageHook(brokerName)
+	if hook == nil {
+		return nil
+	}
+	defer decorate.OnError(&err, "storage hook %q failed for user %q", hook[0], u.Name)
+
+	log.Infof(ctx, "Running storage hook %q for user %q", hook[0], u.Name)
+
+	hookCtx, cancel := context.WithTimeout(ctx, storageHookTimeout)
+	defer cancel()
+
+	//nolint:gosec // The hook is configured by the administrator.
+	cmd := exec.CommandContext(hookCtx, hook[0], hook[1:]...)
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"AUTHD_USER=" + u.Name,
+		fmt.Sprintf("AUTHD_UID=%d", u.UID),
+		fmt.Sprintf("AUTHD_GID=%d", u.GID),
+		"AUTHD_HOME=" + u.Dir,
+		"AUTHD_SHELL=" + u.Shell,
+		"AUTHD_BROKER=" + brokerName,
+	}
+	cmd.Stdin = strings.NewReader(secret)
+
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if errors.Is(hookCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", storageHookTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%v\nOutput: %s", err, out)
+	}
+	if len(out) > 0 {
+		log.Debugf(ctx, "Storage hook %q output: %s", hook[0], out)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package subids
+
+// WithSubUIDPath overrides the default /etc/subuid path for tests.
+func WithSubUIDPath(p string) Option {
+	return func(o *options) {
+		o.subuidPath = p
+	}
+}
+
+// WithSubGIDPath overrides the default /etc/subgid path for tests.
+func WithSubGIDPath(p string) Option {
+	return func(o *options) {
+		o.subgidPath = p
+	}
+}
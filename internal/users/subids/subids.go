@@ -0,0 +1,277 @@
+// Package subids manages the subordinate UIDs and GIDs of the users in /etc/subuid and /etc/subgid.
+package subids
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+// Config is the configuration of the subordinate IDs allocation.
+type Config struct {
+	// RangeSize is the number of subordinate IDs allocated to each user. 0 disables the allocation.
+	RangeSize uint32 `mapstructure:"range_size"`
+	// Min and Max delimit the IDs managed by authd. Only the entries in this range are ever removed.
+	Min uint32 `mapstructure:"min"`
+	Max uint32 `mapstructure:"max"`
+}
+
+// DefaultConfig is the default configuration of the subordinate IDs allocation.
+var DefaultConfig = Config{
+	RangeSize: 0,
+	Min:       2000000000,
+	Max:       4294967294,
+}
+
+var defaultOptions = options{
+	subuidPath: "/etc/subuid",
+	subgidPath: "/etc/subgid",
+}
+
+type options struct {
+	subuidPath string
+	subgidPath string
+}
+
+// Option represents an optional function to override the Manager default values.
+type Option func(*options)
+
+// Manager allocates ranges of subordinate UIDs and GIDs to the users.
+type Manager struct {
+	config Config
+	paths  []string
+	mu     sync.Mutex
+}
+
+// NewManager creates a new subordinate IDs manager.
+func NewManager(config Config, args ...Option) (*Manager, error) {
+	opts := defaultOptions
+	for _, arg := range args {
+		arg(&opts)
+	}
+
+	if config.RangeSize > 0 && (config.Min > config.Max || config.Max-config.Min < config.RangeSize-1) {
+		return nil, fmt.Errorf("subordinate IDs range %d-%d is too small for ranges of %d IDs", config.Min, config.Max, config.RangeSize)
+	}
+
+	return &Manager{
+		config: config,
+		paths:  []string{opts.subuidPath, opts.subgidPath},
+	}, nil
+}
+
+// Enabled returns true if subordinate IDs are allocated to the users.
+func (m *Manager) Enabled() bool {
+	return m.config.RangeSize > 0
+}
+
+// Allocate allocates a range of subordinate UIDs and GIDs to the user, if they don't have one already.
+func (m *Manager) Allocate(username string) (err error) {
+	defer decorate.OnError(&err, "could not allocate subordinate IDs to user %q", username)
+
+	if !m.Enabled() {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, p := range m.paths {
+		entries, err := readEntries(p)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(entries, func(e entry) bool { return e.name == username }) {
+			continue
+		}
+
+		start, err := m.freeRange(entries)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		entries = append(entries, entry{name: username, start: start, count: m.config.RangeSize, valid: true})
+		if err := writeEntries(p, entries); err != nil {
+			return err
+		}
+		log.Debugf(context.Background(), "Allocated subordinate IDs %d-%d to user %q in %s", start, start+m.config.RangeSize-1, username, p)
+	}
+
+	return nil
+}
+
+// Clean removes the subordinate IDs allocated by authd to the users which are not in existingUsers anymore.
+func (m *Manager) Clean(existingUsers []string) (err error) {
+	defer decorate.OnError(&err, "could not clean subordinate IDs")
+
+	if !m.Enabled() {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, p := range m.paths {
+		entries, err := readEntries(p)
+		if err != nil {
+			return err
+		}
+
+		kept := slices.DeleteFunc(slices.Clone(entries), func(e entry) bool {
+			return e.valid && m.managed(e) && !slices.Contains(existingUsers, e.name)
+		})
+		if len(kept) == len(entries) {
+			continue
+		}
+
+		if err := writeEntries(p, kept); err != nil {
+			return err
+		}
+		log.Infof(context.Background(), "Removed %d subordinate IDs entries of users which don't exist anymore from %s", len(entries)-len(kept), p)
+	}
+
+	return nil
+}
+
+// managed returns true if the entry is in the range of IDs managed by authd.
+func (m *Manager) managed(e entry) bool {
+	return e.start >= m.config.Min && uint64(e.start)+uint64(e.count)-1 <= uint64(m.config.Max)
+}
+
+// freeRange returns the start of the first range of the configured size which doesn't overlap with any entry.
+func (m *Manager) freeRange(entries []entry) (uint32, error) {
+	var used []entry
+	for _, e := range entries {
+		if e.valid && e.count > 0 {
+			used = append(used, e)
+		}
+	}
+	slices.SortFunc(used, func(a, b entry) int { return cmp.Compare(a.start, b.start) })
+
+	// We use an uint64 here, as the end of the ranges may not fit in an uint32.
+	start := uint64(m.config.Min)
+	size := uint64(m.config.RangeSize)
+	for _, e := range used {
+		if start+size <= uint64(e.start) {
+			break
+		}
+		start = max(start, uint64(e.start)+uint64(e.count))
+	}
+	if start+size-1 > uint64(m.config.Max) {
+		return 0, errors.New("no free range of subordinate IDs left")
+	}
+
+	//nolint:gosec // This conversion is safe because start is smaller than the configured maximum.
+	return uint32(start), nil
+}
+
+// entry is a line of a subordinate IDs file, whose format is:
+// name:start:count
+type entry struct {
+	name  string
+	start uint32
+	count uint32
+
+	// line is the original line, which is kept as is when it's not a valid entry.
+	line  string
+	valid bool
+}
+
+func readEntries(path string) (entries []entry, err error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		entries = append(entries, parseEntry(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func parseEntry(line string) entry {
+	e := entry{line: line}
+
+	elems := strings.Split(strings.TrimSpace(line), ":")
+	if len(elems) != 3 {
+		return e
+	}
+	start, err := strconv.ParseUint(elems[1], 10, 32)
+	if err != nil {
+		return e
+	}
+	count, err := strconv.ParseUint(elems[2], 10, 32)
+	if err != nil {
+		return e
+	}
+
+	e.name = elems[0]
+	e.start = uint32(start)
+	e.count = uint32(count)
+	e.valid = true
+	return e
+}
+
+func (e entry) String() string {
+	if !e.valid {
+		return e.line
+	}
+	return fmt.Sprintf("%s:%d:%d", e.name, e.start, e.count)
+}
+
+// writeEntries atomically replaces the content of the file with the entries.
+func writeEntries(path string, entries []entry) (err error) {
+	defer decorate.OnError(&err, "could not write %s", path)
+
+	var buf bytes.Buffer
+	for _, e := range entries {
+		buf.WriteString(e.String() + "\n")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".authd-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	defer tmp.Close()
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,157 @@
+package subids_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/testutils/golden"
+	"github.com/ubuntu/authd/internal/users/subids"
+)
+
+var testConfig = subids.Config{
+	RangeSize: 65536,
+	Min:       2000000000,
+	Max:       2000196607,
+}
+
+func TestNewManager(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config subids.Config
+
+		wantErr bool
+	}{
+		"Successfully_create_manager_with_default_config": {config: subids.DefaultConfig},
+		"Successfully_create_manager_with_custom_config":  {config: testConfig},
+		"Successfully_create_manager_with_range_size_equal_to_the_managed_range": {
+			config: subids.Config{RangeSize: 1000, Min: 1000, Max: 1999},
+		},
+		"Range_is_not_checked_when_disabled": {config: subids.Config{Min: 2000, Max: 1000}},
+
+		"Error_when_minimum_is_greater_than_maximum": {config: subids.Config{RangeSize: 1, Min: 2000, Max: 1000}, wantErr: true},
+		"Error_when_range_size_is_too_big":           {config: subids.Config{RangeSize: 1001, Min: 1000, Max: 1999}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := subids.NewManager(tc.config)
+			if tc.wantErr {
+				require.Error(t, err, "NewManager should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "NewManager should not return an error, but did")
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		subidsFile string
+		disabled   bool
+
+		wantErr bool
+	}{
+		"Allocate_first_range_when_files_do_not_exist":             {},
+		"Allocate_first_range_when_no_authd_users":                 {subidsFile: "no_authd_users.subids"},
+		"Allocate_range_after_other_authd_users":                   {subidsFile: "authd_users.subids"},
+		"Allocate_range_in_gap_between_authd_users":                {subidsFile: "authd_users_with_gap.subids"},
+		"Invalid_entries_are_kept_as_is":                           {subidsFile: "invalid_entries.subids"},
+		"No-Op_when_user_already_has_subordinate_IDs":              {subidsFile: "user_already_present.subids"},
+		"No-Op_when_allocation_is_disabled":                        {subidsFile: "no_authd_users.subids", disabled: true},
+		"No-Op_when_allocation_is_disabled_and_files_do_not_exist": {disabled: true},
+
+		"Error_when_no_free_range_is_left": {subidsFile: "full_range.subids", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			subuidPath := filepath.Join(dir, "subuid")
+			subgidPath := filepath.Join(dir, "subgid")
+			if tc.subidsFile != "" {
+				copyFile(t, filepath.Join("testdata", tc.subidsFile), subuidPath)
+				copyFile(t, filepath.Join("testdata", tc.subidsFile), subgidPath)
+			}
+
+			config := testConfig
+			if tc.disabled {
+				config.RangeSize = 0
+			}
+			m, err := subids.NewManager(config, subids.WithSubUIDPath(subuidPath), subids.WithSubGIDPath(subgidPath))
+			require.NoError(t, err, "Setup: NewManager should not return an error, but did")
+
+			err = m.Allocate("myuser")
+			if tc.wantErr {
+				require.Error(t, err, "Allocate should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Allocate should not return an error, but did")
+
+			// Allocating again must not change anything.
+			err = m.Allocate("myuser")
+			require.NoError(t, err, "Allocate should not return an error when called twice, but did")
+
+			golden.CheckOrUpdateFileTree(t, dir)
+		})
+	}
+}
+
+func TestClean(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		subidsFile    string
+		existingUsers []string
+		disabled      bool
+	}{
+		"Remove_entries_of_authd_users_which_do_not_exist_anymore": {subidsFile: "authd_users.subids", existingUsers: []string{"authduser2"}},
+		"Keep_entries_out_of_the_range_managed_by_authd":           {subidsFile: "no_authd_users.subids"},
+		"Keep_invalid_entries":                                     {subidsFile: "invalid_entries.subids"},
+		"No-Op_when_all_users_exist": {
+			subidsFile: "authd_users.subids", existingUsers: []string{"authduser1", "authduser2"},
+		},
+		"No-Op_when_files_do_not_exist":     {},
+		"No-Op_when_allocation_is_disabled": {subidsFile: "authd_users.subids", disabled: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			subuidPath := filepath.Join(dir, "subuid")
+			subgidPath := filepath.Join(dir, "subgid")
+			if tc.subidsFile != "" {
+				copyFile(t, filepath.Join("testdata", tc.subidsFile), subuidPath)
+				copyFile(t, filepath.Join("testdata", tc.subidsFile), subgidPath)
+			}
+
+			config := testConfig
+			if tc.disabled {
+				config.RangeSize = 0
+			}
+			m, err := subids.NewManager(config, subids.WithSubUIDPath(subuidPath), subids.WithSubGIDPath(subgidPath))
+			require.NoError(t, err, "Setup: NewManager should not return an error, but did")
+
+			err = m.Clean(tc.existingUsers)
+			require.NoError(t, err, "Clean should not return an error, but did")
+
+			golden.CheckOrUpdateFileTree(t, dir)
+		})
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	content, err := os.ReadFile(src)
+	require.NoError(t, err, "Setup: could not read %s", src)
+	err = os.WriteFile(dst, content, 0600)
+	require.NoError(t, err, "Setup: could not write %s", dst)
+}
@@ -68,9 +68,10 @@ func groupEntry(group groupRecord) types.GroupEntry {
 
 // RegisterGroup registers a temporary group with a unique GID in our NSS handler (in memory, not in the database).
 //
+// The GID is generated in the range configured for the broker which provided the group.
 // Returns the generated GID and a cleanup function that should be called to remove the temporary group once the group
 // was added to the database.
-func (r *temporaryGroupRecords) RegisterGroup(name string) (gid uint32, cleanup func(), err error) {
+func (r *temporaryGroupRecords) RegisterGroup(name, brokerName string) (gid uint32, cleanup func(), err error) {
 	r.registerMu.Lock()
 	defer r.registerMu.Unlock()
 
@@ -85,7 +86,7 @@ func (r *temporaryGroupRecords) RegisterGroup(name string) (gid uint32, cleanup
 
 	// Generate a GID until we find a unique one
 	for {
-		gid, err = r.idGenerator.GenerateGID()
+		gid, err = r.idGenerator.GenerateGID(brokerName)
 		if err != nil {
 			return 0, nil, err
 		}
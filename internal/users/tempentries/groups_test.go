@@ -42,7 +42,7 @@ func TestRegisterGroup(t *testing.T) {
 			idGeneratorMock := &idgenerator.IDGeneratorMock{GIDsToGenerate: tc.gidsToGenerate}
 			records := newTemporaryGroupRecords(idGeneratorMock)
 
-			gid, cleanup, err := records.RegisterGroup(tc.groupName)
+			gid, cleanup, err := records.RegisterGroup(tc.groupName, "")
 			if tc.wantErr {
 				require.Error(t, err, "RegisterGroup should return an error, but did not")
 				return
@@ -103,7 +103,7 @@ func TestGroupByIDAndName(t *testing.T) {
 			records := newTemporaryGroupRecords(idGeneratorMock)
 
 			if tc.registerGroup {
-				gid, cleanup, err := records.RegisterGroup(groupName)
+				gid, cleanup, err := records.RegisterGroup(groupName, "")
 				require.NoError(t, err, "RegisterGroup should not return an error, but did")
 				require.Equal(t, gidToGenerate, gid, "GID should be the one generated by the IDGenerator")
 
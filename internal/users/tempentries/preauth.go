@@ -135,7 +135,8 @@ func (r *preAuthUserRecords) RegisterPreAuthUser(loginName string) (uint32, erro
 
 	// Generate a UID until we find a unique one
 	for {
-		uid, err := r.idGenerator.GenerateUID()
+		// The broker of the user is not known yet, so the UID is generated in the default range.
+		uid, err := r.idGenerator.GenerateUID("")
 		if err != nil {
 			return 0, err
 		}
@@ -16,8 +16,8 @@ type NoDataFoundError = db.NoDataFoundError
 
 // IDGenerator is the interface that must be implemented by the ID generator.
 type IDGenerator interface {
-	GenerateUID() (uint32, error)
-	GenerateGID() (uint32, error)
+	GenerateUID(brokerName string) (uint32, error)
+	GenerateGID(brokerName string) (uint32, error)
 }
 
 // TemporaryRecords is the in-memory temporary user and group records.
@@ -48,6 +48,12 @@ func (r *TemporaryRecords) UserByID(uid uint32) (types.UserEntry, error) {
 	return user, err
 }
 
+// PreAuthUserByID returns the user information for the given user ID, if it was registered before the user
+// authenticated.
+func (r *TemporaryRecords) PreAuthUserByID(uid uint32) (types.UserEntry, error) {
+	return r.preAuthUserRecords.userByID(uid)
+}
+
 // UserByName returns the user information for the given user name.
 func (r *TemporaryRecords) UserByName(name string) (types.UserEntry, error) {
 	user, err := r.temporaryUserRecords.userByName(name)
@@ -59,9 +65,10 @@ func (r *TemporaryRecords) UserByName(name string) (types.UserEntry, error) {
 
 // RegisterUser registers a temporary user with a unique UID in our NSS handler (in memory, not in the database).
 //
+// The UID is generated in the range configured for the broker which authenticated the user.
 // Returns the generated UID and a cleanup function that should be called to remove the temporary user once the user was
 // added to the database.
-func (r *TemporaryRecords) RegisterUser(name string) (uid uint32, cleanup func(), err error) {
+func (r *TemporaryRecords) RegisterUser(name, brokerName string) (uid uint32, cleanup func(), err error) {
 	r.temporaryUserRecords.registerMu.Lock()
 	defer r.temporaryUserRecords.registerMu.Unlock()
 
@@ -87,7 +94,7 @@ func (r *TemporaryRecords) RegisterUser(name string) (uid uint32, cleanup func()
 
 	// Generate a UID until we find a unique one
 	for {
-		uid, err = r.idGenerator.GenerateUID()
+		uid, err = r.idGenerator.GenerateUID(brokerName)
 		if err != nil {
 			return 0, nil, err
 		}
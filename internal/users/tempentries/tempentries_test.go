@@ -71,7 +71,7 @@ func TestRegisterUser(t *testing.T) {
 				require.NoError(t, err, "addPreAuthUser should not return an error, but did")
 			}
 
-			uid, cleanup, err := records.RegisterUser(tc.userName)
+			uid, cleanup, err := records.RegisterUser(tc.userName, "")
 			if tc.wantErr {
 				require.Error(t, err, "RegisterUser should return an error, but did not")
 				return
@@ -134,7 +134,7 @@ func TestUserByIDAndName(t *testing.T) {
 			userRecords := records.temporaryUserRecords
 
 			if tc.registerUser {
-				uid, cleanup, err := records.RegisterUser(userName)
+				uid, cleanup, err := records.RegisterUser(userName, "")
 				require.NoError(t, err, "RegisterUser should not return an error, but did")
 				require.Equal(t, uidToGenerate, uid, "UID should be the one generated by the IDGenerator")
 
@@ -163,6 +163,61 @@ func TestUserByIDAndName(t *testing.T) {
 	}
 }
 
+func TestCompleteTemporaryUser(t *testing.T) {
+	t.Parallel()
+
+	userName := "authd-temp-users-test"
+	uidToGenerate := uint32(12345)
+
+	tests := map[string]struct {
+		registerUser bool
+		name         string
+		uid          uint32
+
+		wantErr bool
+	}{
+		"Successfully_complete_a_temporary_user": {registerUser: true},
+
+		"Error_when_user_is_not_registered":          {wantErr: true},
+		"Error_when_UID_is_not_the_one_of_the_user":  {registerUser: true, uid: 54321, wantErr: true},
+		"Error_when_name_is_not_the_one_of_the_user": {registerUser: true, name: "other-user", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.name == "" {
+				tc.name = userName
+			}
+			if tc.uid == 0 {
+				tc.uid = uidToGenerate
+			}
+
+			idGeneratorMock := &idgenerator.IDGeneratorMock{UIDsToGenerate: []uint32{uidToGenerate}}
+			records := NewTemporaryRecords(idGeneratorMock)
+
+			if tc.registerUser {
+				_, cleanup, err := records.RegisterUser(userName, "")
+				require.NoError(t, err, "RegisterUser should not return an error, but did")
+				defer cleanup()
+			}
+
+			want := types.UserEntry{Name: tc.name, UID: tc.uid, GID: 23456, Gecos: "Temp User", Dir: "/home/temp", Shell: "/bin/bash"}
+			err := records.CompleteTemporaryUser(want)
+			if tc.wantErr {
+				require.Error(t, err, "CompleteTemporaryUser should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "CompleteTemporaryUser should not return an error, but did")
+
+			user, err := records.UserByName(userName)
+			require.NoError(t, err, "UserByName should not return an error, but did")
+			require.Equal(t, want, user, "The temporary user should have the attributes it was completed with")
+		})
+	}
+}
+
 func checkUser(t *testing.T, user types.UserEntry) {
 	t.Helper()
 
@@ -14,7 +14,10 @@ import (
 type userRecord struct {
 	name  string
 	uid   uint32
+	gid   uint32
 	gecos string
+	dir   string
+	shell string
 }
 
 type temporaryUserRecords struct {
@@ -62,16 +65,40 @@ func (r *temporaryUserRecords) userByName(name string) (types.UserEntry, error)
 }
 
 func userEntry(user userRecord) types.UserEntry {
-	// TODO: Should we set the GID to something else than 0 (i.e. the GID of the root primary group)?
+	// Until the attributes of the user are known, its GID is the one of the root primary group.
+	dir, shell := user.dir, user.shell
+	if dir == "" {
+		dir = "/nonexistent"
+	}
+	if shell == "" {
+		shell = "/usr/sbin/nologin"
+	}
 	return types.UserEntry{
 		Name:  user.name,
 		UID:   user.uid,
+		GID:   user.gid,
 		Gecos: user.gecos,
-		Dir:   "/nonexistent",
-		Shell: "/usr/sbin/nologin",
+		Dir:   dir,
+		Shell: shell,
 	}
 }
 
+// CompleteTemporaryUser sets the attributes the temporary user with the same name and UID will have once it's added to
+// the database, so that the lookups happening until then return them instead of placeholders.
+func (r *temporaryUserRecords) CompleteTemporaryUser(u types.UserEntry) error {
+	r.rwMu.Lock()
+	defer r.rwMu.Unlock()
+
+	user, ok := r.users[u.UID]
+	if !ok || user.name != u.Name {
+		return NoDataFoundError{}
+	}
+
+	user.gid, user.gecos, user.dir, user.shell = u.GID, u.Gecos, u.Dir, u.Shell
+	r.users[u.UID] = user
+	return nil
+}
+
 // uniqueNameAndUID returns true if the given UID is unique in the system. It returns false if the UID is already assigned to
 // a user by any NSS source (except the given temporary user).
 func (r *temporaryUserRecords) uniqueNameAndUID(name string, uid uint32, tmpID string) (bool, error) {
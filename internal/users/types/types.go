@@ -1,6 +1,8 @@
 // Package types provides types for the users package.
 package types
 
+import "github.com/ubuntu/authd/internal/users/gecos"
+
 // UserInfo is the user information returned by the broker.
 type UserInfo struct {
 	Name  string
@@ -10,6 +12,26 @@ type UserInfo struct {
 	Shell string
 
 	Groups []GroupInfo
+
+	// StorageSecret is an optional secret provided by the broker to unlock the storage of the user.
+	StorageSecret string `json:"storage_secret,omitempty"`
+
+	// AvatarData is an optional picture of the user provided by the broker, and AvatarURL the address to download it
+	// from, if the broker doesn't provide its content.
+	AvatarData []byte `json:"avatar_data,omitempty"`
+	AvatarURL  string `json:"avatar_url,omitempty"`
+
+	// GecosFields are the optional structured subfields of the GECOS field provided by the broker. The non-empty ones
+	// take precedence over the ones in Gecos and over the ones changed locally.
+	GecosFields *gecos.Fields `json:"gecos_fields,omitempty"`
+
+	// ConsentMessage is an optional message provided by the broker that the user must accept to log in. It's only
+	// shown again when it changes.
+	ConsentMessage string `json:"consent_message,omitempty"`
+
+	// Roles are the optional roles of the user provided by the broker, which the login policy can require to log in
+	// on this machine.
+	Roles []string `json:"roles,omitempty"`
 }
 
 // GroupInfo is the group information returned by the broker.
@@ -40,6 +62,20 @@ type ShadowEntry struct {
 	ExpirationDate int
 }
 
+// NewShadowEntry returns the shadow entry of the user with the given name. authd doesn't manage the aging of the
+// passwords, so all the other fields are unset.
+func NewShadowEntry(name string) ShadowEntry {
+	return ShadowEntry{
+		Name:           name,
+		LastPwdChange:  -1,
+		MaxPwdAge:      -1,
+		PwdWarnPeriod:  -1,
+		PwdInactivity:  -1,
+		MinPwdAge:      -1,
+		ExpirationDate: -1,
+	}
+}
+
 // GroupEntry is the group information sent to the NSS service.
 type GroupEntry struct {
 	Name   string
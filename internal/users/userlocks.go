@@ -0,0 +1,49 @@
+package users
+
+import "sync"
+
+// userLocks serializes the operations on the same user, without blocking the ones on other users.
+type userLocks struct {
+	locks map[string]*userLock
+	mu    sync.Mutex
+}
+
+// userLock is the lock of a user, which is removed once no operation holds or waits for it.
+type userLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lock blocks until the lock of the user is acquired, and returns the function to release it.
+func (l *userLocks) lock(username string) (unlock func()) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*userLock)
+	}
+	ul, ok := l.locks[username]
+	if !ok {
+		ul = &userLock{}
+		l.locks[username] = ul
+	}
+	ul.refs++
+	l.mu.Unlock()
+
+	ul.mu.Lock()
+	return func() {
+		ul.mu.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		ul.refs--
+		if ul.refs == 0 {
+			delete(l.locks, username)
+		}
+	}
+}
+
+// len returns the number of users whose lock is held or waited for.
+func (l *userLocks) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.locks)
+}
@@ -141,6 +141,10 @@ func logf(context context.Context, level Level, format string, args ...interface
 		return
 	}
 
+	if level >= WarnLevel {
+		recordRecentError(level, fmt.Sprintf(format, args...))
+	}
+
 	handlersMu.RLock()
 	handler := handlers[level]
 	handlersMu.RUnlock()
@@ -220,3 +220,26 @@ func TestSetHandler(t *testing.T) {
 		require.False(t, handlerCalled, "Handler should not have been called")
 	}
 }
+
+func TestRecentErrors(t *testing.T) {
+	// This can't be parallel.
+	defaultLevel := log.GetLevel()
+	t.Cleanup(func() {
+		log.SetLevel(defaultLevel)
+		log.SetHandler(nil)
+	})
+	log.SetLevel(log.DebugLevel)
+	log.SetHandler(func(context.Context, log.Level, string, ...interface{}) {})
+
+	log.Infof(context.Background(), "not recorded")
+	for i := range 60 {
+		log.Warningf(context.Background(), "warning %d", i)
+	}
+	log.Error(context.Background(), "last error")
+
+	got := log.RecentErrors()
+	require.Len(t, got, 50, "Only the last warnings and errors should be kept")
+	require.Equal(t, "warning 11", got[0].Message, "The oldest warnings should be dropped")
+	require.Equal(t, "last error", got[len(got)-1].Message, "The last error should be kept")
+	require.Equal(t, "ERROR", got[len(got)-1].Level, "The level of the error should be recorded")
+}
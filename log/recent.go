@@ -0,0 +1,40 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// maxRecentErrors is the number of warnings and errors kept for the diagnostics of the daemon.
+const maxRecentErrors = 50
+
+// Record is a warning or an error which was logged.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+}
+
+var (
+	recentErrors   = make([]Record, 0, maxRecentErrors)
+	recentErrorsMu sync.Mutex
+)
+
+// recordRecentError keeps the warning or error, dropping the oldest one if there are too many.
+func recordRecentError(level Level, msg string) {
+	recentErrorsMu.Lock()
+	defer recentErrorsMu.Unlock()
+
+	if len(recentErrors) == maxRecentErrors {
+		recentErrors = append(recentErrors[:0], recentErrors[1:]...)
+	}
+	recentErrors = append(recentErrors, Record{Time: time.Now(), Level: level.String(), Message: msg})
+}
+
+// RecentErrors returns the last warnings and errors which were logged, from the oldest to the newest.
+func RecentErrors() []Record {
+	recentErrorsMu.Lock()
+	defer recentErrorsMu.Unlock()
+
+	return append([]Record(nil), recentErrors...)
+}
@@ -6,6 +6,7 @@ use tokio::runtime::Builder;
 use tonic::Request;
 
 use crate::client::{self, authd};
+use crate::snapshot;
 use authd::GroupEntry;
 
 pub struct AuthdGroup;
@@ -57,8 +58,14 @@ fn get_all_entries() -> Response<Vec<Group>> {
     })
 }
 
-/// get_entry_by_gid connects to the grpc server and asks for the group entry with the given gid.
+/// get_entry_by_gid returns the group entry with the given gid from the snapshot of authd, or connects to the grpc
+/// server and asks for it.
 fn get_entry_by_gid(gid: gid_t) -> Response<Group> {
+    // The snapshot doesn't contain the temporary groups, which are only known by the daemon.
+    if let Some(g) = snapshot::group_by_gid(gid) {
+        return Response::Success(g);
+    }
+
     let rt = match Builder::new_current_thread().enable_all().build() {
         Ok(rt) => rt,
         Err(e) => {
@@ -88,8 +95,13 @@ fn get_entry_by_gid(gid: gid_t) -> Response<Group> {
     })
 }
 
-/// get_entry_by_name connects to the grpc server and asks for the group entry with the given name.
+/// get_entry_by_name returns the group entry with the given name from the snapshot of authd, or connects to the grpc
+/// server and asks for it.
 fn get_entry_by_name(name: String) -> Response<Group> {
+    if let Some(g) = snapshot::group_by_name(&name) {
+        return Response::Success(g);
+    }
+
     let rt = match Builder::new_current_thread().enable_all().build() {
         Ok(rt) => rt,
         Err(e) => {
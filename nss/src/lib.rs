@@ -20,6 +20,8 @@ mod logs;
 
 mod client;
 
+mod snapshot;
+
 const CONNECTION_TIMEOUT: Duration = Duration::from_secs(1);
 const REQUEST_TIMEOUT: Duration = Duration::from_secs(5);
 
@@ -7,6 +7,7 @@ use tokio::runtime::Builder;
 use tonic::Request;
 
 use crate::client::{self, authd};
+use crate::snapshot;
 use authd::PasswdEntry;
 
 pub struct AuthdPasswd;
@@ -58,8 +59,14 @@ fn get_all_entries() -> Response<Vec<Passwd>> {
     })
 }
 
-/// get_entry_by_uid connects to the grpc server and asks for the passwd entry with the given uid.
+/// get_entry_by_uid returns the passwd entry with the given uid from the snapshot of authd, or connects to the grpc
+/// server and asks for it.
 fn get_entry_by_uid(uid: uid_t) -> Response<Passwd> {
+    // The snapshot doesn't contain the temporary users, which are only known by the daemon.
+    if let Some(p) = snapshot::passwd_by_uid(uid) {
+        return Response::Success(p);
+    }
+
     let rt = match Builder::new_current_thread().enable_all().build() {
         Ok(rt) => rt,
         Err(e) => {
@@ -89,8 +96,13 @@ fn get_entry_by_uid(uid: uid_t) -> Response<Passwd> {
     })
 }
 
-/// get_entry_by_name connects to the grpc server and asks for the passwd entry with the given name.
+/// get_entry_by_name returns the passwd entry with the given name from the snapshot of authd, or connects to the grpc
+/// server and asks for it.
 fn get_entry_by_name(name: String) -> Response<Passwd> {
+    if let Some(p) = snapshot::passwd_by_name(&name) {
+        return Response::Success(p);
+    }
+
     let rt = match Builder::new_current_thread().enable_all().build() {
         Ok(rt) => rt,
         Err(e) => {
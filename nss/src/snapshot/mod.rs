@@ -0,0 +1,213 @@
+use crate::info;
+use libc::{gid_t, uid_t};
+use libnss::group::Group;
+use libnss::passwd::Passwd;
+use std::fs::File;
+use std::os::unix::io::AsRawFd;
+
+/// MAGIC identifies the snapshot files written by authd.
+const MAGIC: &[u8] = b"AUTHDNSS";
+
+/// VERSION is the version of the format of the snapshot supported by this module.
+const VERSION: u32 = 1;
+
+/// snapshot_path returns the path of the snapshot of the NSS entries maintained by authd.
+///
+/// It uses the AUTHD_NSS_SNAPSHOT env value if set and the custom_socket feature is enabled,
+/// otherwise it uses the default path.
+fn snapshot_path() -> String {
+    #[cfg(feature = "custom_socket")]
+    if let Ok(s) = std::env::var("AUTHD_NSS_SNAPSHOT") {
+        return s;
+    }
+    "/run/authd-nss.snapshot".to_string()
+}
+
+/// passwd_by_uid returns the passwd entry with the given uid from the snapshot, if any.
+pub fn passwd_by_uid(uid: uid_t) -> Option<Passwd> {
+    find_passwd(|p| p.uid == uid)
+}
+
+/// passwd_by_name returns the passwd entry with the given name from the snapshot, if any.
+pub fn passwd_by_name(name: &str) -> Option<Passwd> {
+    find_passwd(|p| p.name == name)
+}
+
+/// group_by_gid returns the group entry with the given gid from the snapshot, if any.
+pub fn group_by_gid(gid: gid_t) -> Option<Group> {
+    find_group(|g| g.gid == gid)
+}
+
+/// group_by_name returns the group entry with the given name from the snapshot, if any.
+pub fn group_by_name(name: &str) -> Option<Group> {
+    find_group(|g| g.name == name)
+}
+
+/// find_passwd returns the first passwd entry of the snapshot matching the predicate.
+fn find_passwd(pred: impl Fn(&Passwd) -> bool) -> Option<Passwd> {
+    with_snapshot(|r, n_passwd, _| {
+        for _ in 0..n_passwd {
+            let p = r.passwd()?;
+            if pred(&p) {
+                return Some(p);
+            }
+        }
+        None
+    })
+}
+
+/// find_group returns the first group entry of the snapshot matching the predicate.
+fn find_group(pred: impl Fn(&Group) -> bool) -> Option<Group> {
+    with_snapshot(|r, n_passwd, n_groups| {
+        // The group entries are after the passwd ones.
+        for _ in 0..n_passwd {
+            r.passwd()?;
+        }
+        for _ in 0..n_groups {
+            let g = r.group()?;
+            if pred(&g) {
+                return Some(g);
+            }
+        }
+        None
+    })
+}
+
+/// with_snapshot maps the snapshot in memory and calls f with a reader positioned after its header, and the numbers
+/// of passwd and group entries. None is returned if the snapshot doesn't exist or is not valid, in which case the
+/// daemon must be queried.
+fn with_snapshot<T>(f: impl FnOnce(&mut Reader, u32, u32) -> Option<T>) -> Option<T> {
+    let mapping = Mapping::open(&snapshot_path())?;
+    let mut r = Reader {
+        data: mapping.bytes(),
+    };
+
+    if !r.data.starts_with(MAGIC) {
+        info!("invalid NSS snapshot, ignoring it");
+        return None;
+    }
+    r.data = &r.data[MAGIC.len()..];
+    let version = r.u32()?;
+    if version != VERSION {
+        info!("unsupported NSS snapshot version {}, ignoring it", version);
+        return None;
+    }
+
+    let n_passwd = r.u32()?;
+    let n_groups = r.u32()?;
+    f(&mut r, n_passwd, n_groups)
+}
+
+/// Mapping is a read-only memory mapping of the snapshot, which is unmapped when dropped.
+///
+/// authd never modifies the snapshot in place but atomically replaces it, so the mapped file never changes.
+struct Mapping {
+    ptr: *mut libc::c_void,
+    len: usize,
+}
+
+impl Mapping {
+    /// open maps the file at the given path in memory.
+    fn open(path: &str) -> Option<Mapping> {
+        let file = File::open(path).ok()?;
+        let len = usize::try_from(file.metadata().ok()?.len()).ok()?;
+        if len == 0 {
+            return None;
+        }
+
+        // SAFETY: the file is mapped read-only and privately, and the mapping is checked for errors.
+        let ptr = unsafe {
+            libc::mmap(
+                std::ptr::null_mut(),
+                len,
+                libc::PROT_READ,
+                libc::MAP_PRIVATE,
+                file.as_raw_fd(),
+                0,
+            )
+        };
+        if ptr == libc::MAP_FAILED {
+            info!(
+                "could not map NSS snapshot: {}",
+                std::io::Error::last_os_error()
+            );
+            return None;
+        }
+
+        Some(Mapping { ptr, len })
+    }
+
+    /// bytes returns the content of the mapped file.
+    fn bytes(&self) -> &[u8] {
+        // SAFETY: the mapping is valid and readable for len bytes until it's dropped.
+        unsafe { std::slice::from_raw_parts(self.ptr as *const u8, self.len) }
+    }
+}
+
+impl Drop for Mapping {
+    fn drop(&mut self) {
+        // SAFETY: the mapping was created by mmap with the same length and is not used anymore.
+        unsafe {
+            libc::munmap(self.ptr, self.len);
+        }
+    }
+}
+
+/// Reader decodes the values of the snapshot, which are in little-endian byte order on all architectures.
+/// All its methods return None if the snapshot is truncated or not valid.
+struct Reader<'a> {
+    data: &'a [u8],
+}
+
+impl<'a> Reader<'a> {
+    /// u32 reads an unsigned 32-bit integer.
+    fn u32(&mut self) -> Option<u32> {
+        let (v, rest) = self.data.split_first_chunk::<4>()?;
+        self.data = rest;
+        Some(u32::from_le_bytes(*v))
+    }
+
+    /// string reads a string prefixed by its length.
+    fn string(&mut self) -> Option<String> {
+        let len = usize::try_from(self.u32()?).ok()?;
+        if self.data.len() < len {
+            return None;
+        }
+        let (v, rest) = self.data.split_at(len);
+        self.data = rest;
+        String::from_utf8(v.to_vec()).ok()
+    }
+
+    /// passwd reads a passwd entry.
+    fn passwd(&mut self) -> Option<Passwd> {
+        let uid = self.u32()?;
+        let gid = self.u32()?;
+        Some(Passwd {
+            name: self.string()?,
+            passwd: self.string()?,
+            uid,
+            gid,
+            gecos: self.string()?,
+            dir: self.string()?,
+            shell: self.string()?,
+        })
+    }
+
+    /// group reads a group entry.
+    fn group(&mut self) -> Option<Group> {
+        let gid = self.u32()?;
+        let name = self.string()?;
+        let passwd = self.string()?;
+        let n_members = self.u32()?;
+        let mut members = Vec::new();
+        for _ in 0..n_members {
+            members.push(self.string()?);
+        }
+        Some(Group {
+            name,
+            passwd,
+            gid,
+            members,
+        })
+    }
+}
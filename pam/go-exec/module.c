@@ -1240,8 +1240,6 @@ do_pam_action (pam_handle_t *pamh,
   switch (action)
     {
     case action_type_setcred:
-    case action_type_open_session:
-    case action_type_close_session:
       return PAM_IGNORE;
     default:
       break;
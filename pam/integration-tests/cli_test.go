@@ -16,6 +16,7 @@ import (
 	"github.com/ubuntu/authd/internal/testutils/golden"
 	localgroupstestutils "github.com/ubuntu/authd/internal/users/localentries/testutils"
 	"github.com/ubuntu/authd/pam/internal/pam_test"
+	"github.com/ubuntu/authd/pam/pamtest"
 )
 
 var daemonPath string
@@ -177,7 +178,7 @@ func TestCLIAuthenticate(t *testing.T) {
 		},
 		"Exit_if_authd_is_stopped": {
 			tape:            "authd_stopped",
-			stopDaemonAfter: sleepDuration(defaultSleepValues[authdSleepLong] * 5),
+			stopDaemonAfter: pamtest.SleepDuration(defaultSleepValues[authdSleepLong] * 5),
 		},
 
 		"Error_if_cannot_connect_to_authd": {
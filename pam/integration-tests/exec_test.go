@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/ubuntu/authd/internal/testutils"
 	"github.com/ubuntu/authd/pam/internal/pam_test"
+	"github.com/ubuntu/authd/pam/pamtest"
 )
 
 var execModuleSources = []string{"./pam/go-exec/module.c"}
@@ -828,8 +829,29 @@ func TestExecModuleUnimplementedActions(t *testing.T) {
 
 	tx := preparePamTransaction(t, libPath, execClient, nil, "an-user")
 	require.Error(t, tx.SetCred(pam.Flags(0)), pam.ErrIgnore)
-	require.Error(t, tx.OpenSession(pam.Flags(0)), pam.ErrIgnore)
-	require.Error(t, tx.CloseSession(pam.Flags(0)), pam.ErrIgnore)
+}
+
+func TestExecModuleSessionActions(t *testing.T) {
+	t.Parallel()
+	t.Cleanup(pam_test.MaybeDoLeakCheck)
+
+	if !pam.CheckPamHasStartConfdir() {
+		t.Fatal("can't test with this libpam version!")
+	}
+
+	libPath := buildExecModule(t)
+	execClient := buildExecClient(t)
+
+	// The user has not been authenticated by authd and the daemon is not running, so there's no session to
+	// set up nor to record.
+	args := []string{
+		"socket=" + filepath.Join(t.TempDir(), "authd.sock"),
+		"connection_timeout=1",
+	}
+	tx := preparePamTransaction(t, libPath, execClient, args, "an-user")
+	require.NoError(t, tx.SetItem(pam.Tty, "/dev/pts/3"), "Setup: Setting the TTY failed")
+	require.ErrorIs(t, tx.OpenSession(pam.Flags(0)), pam.ErrIgnore)
+	require.ErrorIs(t, tx.CloseSession(pam.Flags(0)), pam.ErrIgnore)
 }
 
 func getModuleArgs(t *testing.T, clientPath string, args []string) []string {
@@ -842,7 +864,7 @@ func getModuleArgs(t *testing.T, clientPath string, args []string) []string {
 
 	logFile := os.Stderr.Name()
 	if !testutils.IsVerbose() {
-		logFile = prepareFileLogging(t, "exec-module.log")
+		logFile = pamtest.PrepareFileLogging(t, "exec-module.log")
 	}
 	moduleArgs = append(moduleArgs, "--exec-log", logFile)
 
@@ -857,7 +879,7 @@ func getModuleArgs(t *testing.T, clientPath string, args []string) []string {
 			clientArgsPath := filepath.Join(t.TempDir(), "client-args-file")
 			require.NoError(t, os.WriteFile(clientArgsPath, []byte(strings.Join(args, "\t")), 0600),
 				"Setup: Creation of client args file failed")
-			saveArtifactsForDebugOnCleanup(t, []string{clientArgsPath})
+			pamtest.SaveArtifactsForDebugOnCleanup(t, []string{clientArgsPath})
 			return append(moduleArgs, "-client-args-file", clientArgsPath)
 		}
 	}
@@ -901,7 +923,7 @@ func preparePamTransactionForServiceFile(t *testing.T, serviceFile string, user
 	} else {
 		tx, err = pam.StartConfDir(filepath.Base(serviceFile), user, nil, filepath.Dir(serviceFile))
 	}
-	saveArtifactsForDebugOnCleanup(t, []string{serviceFile})
+	pamtest.SaveArtifactsForDebugOnCleanup(t, []string{serviceFile})
 	require.NoError(t, err, "PAM: Error to initialize module")
 	require.NotNil(t, tx, "PAM: Transaction is not set")
 	t.Cleanup(func() { require.NoError(t, tx.End(), "PAM: can't end transaction") })
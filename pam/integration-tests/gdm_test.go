@@ -21,6 +21,7 @@ import (
 	"github.com/ubuntu/authd/pam/internal/gdm_test"
 	"github.com/ubuntu/authd/pam/internal/pam_test"
 	"github.com/ubuntu/authd/pam/internal/proto"
+	"github.com/ubuntu/authd/pam/pamtest"
 )
 
 func enableGdmExtension() {
@@ -822,12 +823,12 @@ func TestGdmModule(t *testing.T) {
 			socketPath, _ := sharedAuthd(t)
 			moduleArgs := []string{"socket=" + socketPath}
 
-			gdmLog := prepareFileLogging(t, "authd-pam-gdm.log")
+			gdmLog := pamtest.PrepareFileLogging(t, "authd-pam-gdm.log")
 			moduleArgs = append(moduleArgs, "debug=true", "logfile="+gdmLog)
 			moduleArgs = append(moduleArgs, tc.moduleArgs...)
 
 			serviceFile := createServiceFile(t, "gdm-authd", libPath, moduleArgs)
-			saveArtifactsForDebugOnCleanup(t, []string{serviceFile})
+			pamtest.SaveArtifactsForDebugOnCleanup(t, []string{serviceFile})
 
 			pamUser := vhsTestUserName(t, "gdm")
 			if tc.pamUserPrefix != "" {
@@ -889,7 +890,7 @@ func TestGdmModule(t *testing.T) {
 
 			var err error
 			select {
-			case <-time.After(sleepDuration(30 * time.Second)):
+			case <-time.After(pamtest.SleepDuration(30 * time.Second)):
 				timedOut = true
 				t.Fatal("Authentication timed out!")
 			case err = <-authResult:
@@ -903,13 +904,13 @@ func TestGdmModule(t *testing.T) {
 			gdm_test.RequireEqualData(t, tc.wantAuthResponses, gh.authResponses,
 				"Authentication responses do not match")
 
-			requirePreviousBrokerForUser(t, socketPath, "", pamUser)
+			pamtest.RequirePreviousBrokerForUser(t, socketPath, "", pamUser)
 
 			require.ErrorIs(t, gh.tx.AcctMgmt(pamFlags), tc.wantAcctMgmtErr,
 				"Account Management PAM Error messages do not match")
 
 			if tc.wantError != nil {
-				requirePreviousBrokerForUser(t, socketPath, "", pamUser)
+				pamtest.RequirePreviousBrokerForUser(t, socketPath, "", pamUser)
 				return
 			}
 
@@ -917,7 +918,7 @@ func TestGdmModule(t *testing.T) {
 			require.NoError(t, err, "Can't get the pam user")
 			require.Equal(t, pamUser, user, "PAM user name does not match expected")
 
-			requirePreviousBrokerForUser(t, socketPath, gh.selectedBrokerName, user)
+			pamtest.RequirePreviousBrokerForUser(t, socketPath, gh.selectedBrokerName, user)
 		})
 	}
 }
@@ -932,11 +933,11 @@ func TestGdmModuleAuthenticateWithoutGdmExtension(t *testing.T) {
 	socketPath, _ := sharedAuthd(t)
 	moduleArgs = append(moduleArgs, "socket="+socketPath)
 
-	gdmLog := prepareFileLogging(t, "authd-pam-gdm.log")
+	gdmLog := pamtest.PrepareFileLogging(t, "authd-pam-gdm.log")
 	moduleArgs = append(moduleArgs, "debug=true", "logfile="+gdmLog)
 
 	serviceFile := createServiceFile(t, "gdm-authd", libPath, moduleArgs)
-	saveArtifactsForDebugOnCleanup(t, []string{serviceFile})
+	pamtest.SaveArtifactsForDebugOnCleanup(t, []string{serviceFile})
 	pamUser := vhsTestUserName(t, "gdm")
 	gh := newGdmTestModuleHandler(t, serviceFile, pamUser)
 	t.Cleanup(func() { require.NoError(t, gh.tx.End(), "PAM: can't end transaction") })
@@ -953,7 +954,7 @@ func TestGdmModuleAuthenticateWithoutGdmExtension(t *testing.T) {
 
 	require.ErrorIs(t, gh.tx.Authenticate(pamFlags), pam_test.ErrIgnore,
 		"Authentication should be ignored")
-	requirePreviousBrokerForUser(t, socketPath, "", pamUser)
+	pamtest.RequirePreviousBrokerForUser(t, socketPath, "", pamUser)
 }
 
 func TestGdmModuleAcctMgmtWithoutGdmExtension(t *testing.T) {
@@ -966,11 +967,11 @@ func TestGdmModuleAcctMgmtWithoutGdmExtension(t *testing.T) {
 	socketPath, _ := sharedAuthd(t)
 	moduleArgs = append(moduleArgs, "socket="+socketPath)
 
-	gdmLog := prepareFileLogging(t, "authd-pam-gdm.log")
+	gdmLog := pamtest.PrepareFileLogging(t, "authd-pam-gdm.log")
 	moduleArgs = append(moduleArgs, "debug=true", "logfile="+gdmLog)
 
 	serviceFile := createServiceFile(t, "gdm-authd", libPath, moduleArgs)
-	saveArtifactsForDebugOnCleanup(t, []string{serviceFile})
+	pamtest.SaveArtifactsForDebugOnCleanup(t, []string{serviceFile})
 	pamUser := vhsTestUserName(t, "gdm")
 	gh := newGdmTestModuleHandler(t, serviceFile, pamUser)
 	t.Cleanup(func() { require.NoError(t, gh.tx.End(), "PAM: can't end transaction") })
@@ -993,7 +994,7 @@ func TestGdmModuleAcctMgmtWithoutGdmExtension(t *testing.T) {
 	}
 
 	require.NoError(t, gh.tx.Authenticate(pamFlags), "Setup: Authentication failed")
-	requirePreviousBrokerForUser(t, socketPath, "", pamUser)
+	pamtest.RequirePreviousBrokerForUser(t, socketPath, "", pamUser)
 
 	// We disable gdm extension support, as if it was the case when the module is loaded
 	// again from the exec module.
@@ -1002,7 +1003,7 @@ func TestGdmModuleAcctMgmtWithoutGdmExtension(t *testing.T) {
 
 	require.ErrorIs(t, gh.tx.AcctMgmt(pamFlags), pam_test.ErrIgnore,
 		"Account Management PAM Error message do not match")
-	requirePreviousBrokerForUser(t, socketPath, "", pamUser)
+	pamtest.RequirePreviousBrokerForUser(t, socketPath, "", pamUser)
 }
 
 func buildPAMModule(t *testing.T) string {
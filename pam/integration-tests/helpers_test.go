@@ -2,10 +2,7 @@ package main_test
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"io/fs"
-	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,27 +10,15 @@ import (
 	"strings"
 	"sync"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/require"
-	"github.com/ubuntu/authd/internal/grpcutils"
-	"github.com/ubuntu/authd/internal/proto/authd"
-	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/internal/testutils"
-	"github.com/ubuntu/authd/internal/testutils/golden"
 	localgroupstestutils "github.com/ubuntu/authd/internal/users/localentries/testutils"
 	"github.com/ubuntu/authd/pam/internal/pam_test"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/ubuntu/authd/pam/pamtest"
 	"gorbe.io/go/osrelease"
 )
 
-var (
-	authdTestSessionTime  = time.Now()
-	authdArtifactsDir     string
-	authdArtifactsDirSync sync.Once
-)
-
 type authdInstance struct {
 	mu                sync.Mutex
 	refCount          uint64
@@ -200,107 +185,6 @@ func buildPAMExecChild(t *testing.T) string {
 	return authdPam
 }
 
-func prepareFileLogging(t *testing.T, fileName string) string {
-	t.Helper()
-
-	cliLog := filepath.Join(t.TempDir(), fileName)
-	saveArtifactsForDebugOnCleanup(t, []string{cliLog})
-	t.Cleanup(func() {
-		out, err := os.ReadFile(cliLog)
-		if errors.Is(err, fs.ErrNotExist) {
-			return
-		}
-		require.NoError(t, err, "Teardown: Impossible to read PAM client logs")
-		t.Log(string(out))
-	})
-
-	return cliLog
-}
-
-func requirePreviousBrokerForUser(t *testing.T, socketPath string, brokerName string, user string) {
-	t.Helper()
-
-	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(errmessages.FormatErrorMessage))
-	require.NoError(t, err, "Can't connect to authd socket")
-
-	t.Cleanup(func() { conn.Close() })
-	require.NoError(t, grpcutils.WaitForConnection(context.TODO(), conn,
-		sleepDuration(30*time.Second)))
-	pamClient := authd.NewPAMClient(conn)
-	brokers, err := pamClient.AvailableBrokers(context.TODO(), nil)
-	require.NoError(t, err, "Can't get available brokers")
-	prevBroker, err := pamClient.GetPreviousBroker(context.TODO(), &authd.GPBRequest{Username: user})
-	require.NoError(t, err, "Can't get previous broker")
-	var prevBrokerID string
-	for _, b := range brokers.BrokersInfos {
-		if b.Name == brokerName {
-			prevBrokerID = b.Id
-		}
-	}
-	require.Equal(t, prevBroker.PreviousBroker, prevBrokerID)
-}
-
-func artifactsPath(t *testing.T) string {
-	t.Helper()
-
-	authdArtifactsDirSync.Do(func() {
-		defer func() { t.Logf("Saving test artifacts at %s", authdArtifactsDir) }()
-
-		// We need to copy the artifacts to another directory, since the test directory will be cleaned up.
-		authdArtifactsDir = os.Getenv("AUTHD_TEST_ARTIFACTS_PATH")
-		if authdArtifactsDir != "" {
-			if err := os.MkdirAll(authdArtifactsDir, 0750); err != nil && !os.IsExist(err) {
-				require.NoError(t, err, "TearDown: could not create artifacts directory %q", authdArtifactsDir)
-			}
-			return
-		}
-
-		st := authdTestSessionTime
-		folderName := fmt.Sprintf("authd-test-artifacts-%d-%02d-%02dT%02d:%02d:%02d.%d-",
-			st.Year(), st.Month(), st.Day(), st.Hour(), st.Minute(), st.Second(),
-			st.UnixMilli())
-
-		var err error
-		authdArtifactsDir, err = os.MkdirTemp(os.TempDir(), folderName)
-		require.NoError(t, err, "TearDown: could not create artifacts directory %q", authdArtifactsDir)
-	})
-
-	return authdArtifactsDir
-}
-
-// saveArtifactsForDebug saves the specified artifacts to a temporary directory if the test failed.
-func saveArtifactsForDebug(t *testing.T, artifacts []string) {
-	t.Helper()
-	if !t.Failed() {
-		return
-	}
-
-	tmpDir := filepath.Join(artifactsPath(t), golden.Path(t))
-	err := os.MkdirAll(tmpDir, 0750)
-	require.NoError(t, err, "TearDown: could not create temporary directory %q for artifacts", tmpDir)
-
-	// Copy the artifacts to the temporary directory.
-	for _, artifact := range artifacts {
-		content, err := os.ReadFile(artifact)
-		if err != nil {
-			t.Logf("Could not read artifact %q: %v", artifact, err)
-			continue
-		}
-		if err := os.WriteFile(filepath.Join(tmpDir, filepath.Base(artifact)), content, 0600); err != nil {
-			t.Logf("Could not write artifact %q: %v", artifact, err)
-		}
-	}
-}
-
-func saveArtifactsForDebugOnCleanup(t *testing.T, artifacts []string) {
-	t.Helper()
-	t.Cleanup(func() { saveArtifactsForDebug(t, artifacts) })
-}
-
-func sleepDuration(in time.Duration) time.Duration {
-	return time.Duration(math.Round(float64(in) * testutils.SleepMultiplier()))
-}
-
 // prependBinToPath returns the value of the GOPATH defined in go env prepended to PATH.
 func prependBinToPath(t *testing.T) string {
 	t.Helper()
@@ -319,7 +203,7 @@ func prepareGPasswdFiles(t *testing.T) (string, string) {
 	gpasswdOutput := filepath.Join(t.TempDir(), "gpasswd.output")
 	groupsFile := filepath.Join(testutils.TestFamilyPath(t), "gpasswd.group")
 
-	saveArtifactsForDebugOnCleanup(t, []string{gpasswdOutput, groupsFile})
+	pamtest.SaveArtifactsForDebugOnCleanup(t, []string{gpasswdOutput, groupsFile})
 
 	return gpasswdOutput, groupsFile
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/ubuntu/authd/internal/testutils/golden"
 	localgroupstestutils "github.com/ubuntu/authd/internal/users/localentries/testutils"
 	"github.com/ubuntu/authd/pam/internal/pam_test"
+	"github.com/ubuntu/authd/pam/pamtest"
 )
 
 var (
@@ -74,11 +75,11 @@ func testSSHAuthenticate(t *testing.T, sharedSSHd bool) {
 	//#nosec:G204 - we control the command arguments in tests
 	out, err := exec.Command("ssh-keygen", "-q", "-f", sshdHostKey, "-N", "", "-t", "ed25519").CombinedOutput()
 	require.NoError(t, err, "Setup: Failed generating SSH host key: %s", out)
-	saveArtifactsForDebugOnCleanup(t, []string{sshdHostKey})
+	pamtest.SaveArtifactsForDebugOnCleanup(t, []string{sshdHostKey})
 
 	pubKey, err := os.ReadFile(sshdHostKey + ".pub")
 	require.NoError(t, err, "Setup: Can't read sshd host public key")
-	saveArtifactsForDebugOnCleanup(t, []string{sshdHostKey + ".pub"})
+	pamtest.SaveArtifactsForDebugOnCleanup(t, []string{sshdHostKey + ".pub"})
 
 	const tapeCommand = "ssh ${AUTHD_PAM_SSH_USER}@localhost ${AUTHD_PAM_SSH_ARGS}"
 	defaultTapeSettings := []tapeSetting{{vhsHeight, 1000}, {vhsWidth, 1500}}
@@ -391,7 +392,7 @@ func createSshdServiceFile(t *testing.T, module, execChild, socketPath string) s
 		{Action: pam_test.Session, Control: pam_test.Requisite, Module: pam_test.Permit.String()},
 	})
 	require.NoError(t, err, "Setup: Creation of service file %s", pamServiceName)
-	saveArtifactsForDebugOnCleanup(t, []string{serviceFile})
+	pamtest.SaveArtifactsForDebugOnCleanup(t, []string{serviceFile})
 
 	return serviceFile
 }
@@ -433,7 +434,7 @@ func sshdCommand(t *testing.T, port, hostKey, forcedCommand string, env []string
 	if daemonize {
 		pidFile = filepath.Join(t.TempDir(), "sshd.pid")
 		logFile = filepath.Join(t.TempDir(), "sshd-daemon.log")
-		saveArtifactsForDebugOnCleanup(t, []string{logFile})
+		pamtest.SaveArtifactsForDebugOnCleanup(t, []string{logFile})
 
 		runModeArgs = []string{
 			"-E", logFile,
@@ -526,7 +527,7 @@ func startSSHd(t *testing.T, hostKey, forcedCommand string, env []string, daemon
 		sshdLog := filepath.Join(t.TempDir(), "sshd.log")
 		require.NoError(t, os.WriteFile(sshdLog, []byte(sshdStderr.String()), 0600),
 			"TearDown: Saving sshd log")
-		saveArtifactsForDebug(t, []string{sshdLog})
+		pamtest.SaveArtifactsForDebug(t, []string{sshdLog})
 	})
 
 	t.Cleanup(func() {
@@ -543,7 +544,7 @@ func startSSHd(t *testing.T, hostKey, forcedCommand string, env []string, daemon
 
 		t.Log("Waiting for sshd to be terminated")
 		select {
-		case <-time.After(sleepDuration(5 * time.Second)):
+		case <-time.After(pamtest.SleepDuration(5 * time.Second)):
 			require.NoError(t, sshd.Process.Kill(), "TearDown: Killing SSHd failed")
 			if !testing.Verbose() {
 				t.Logf("SSHd stopped (killed)\n ##### STDERR #####\n %s \n ##### END #####",
@@ -596,7 +597,7 @@ func startSSHd(t *testing.T, hostKey, forcedCommand string, env []string, daemon
 	sshdStarted := make(chan error)
 	go func() {
 		for {
-			conn, err := net.DialTimeout("tcp", ":"+sshdPort, sleepDuration(1*time.Second))
+			conn, err := net.DialTimeout("tcp", ":"+sshdPort, pamtest.SleepDuration(1*time.Second))
 			if errors.Is(err, syscall.ECONNREFUSED) {
 				continue
 			}
@@ -619,7 +620,7 @@ func startSSHd(t *testing.T, hostKey, forcedCommand string, env []string, daemon
 	}()
 
 	select {
-	case <-time.After(sleepDuration(5 * time.Second)):
+	case <-time.After(pamtest.SleepDuration(5 * time.Second)):
 		_ = sshd.Process.Kill()
 		if !testing.Verbose() {
 			t.Logf("SSHd stopped (killed)\n ##### STDERR #####\n %s \n ##### END #####",
@@ -24,6 +24,7 @@ import (
 	"github.com/ubuntu/authd/internal/services/permissions"
 	"github.com/ubuntu/authd/internal/testutils"
 	"github.com/ubuntu/authd/pam/internal/pam_test"
+	"github.com/ubuntu/authd/pam/pamtest"
 )
 
 const (
@@ -110,7 +111,7 @@ var (
 		authdSleepQrCodeReselection: 700 * time.Millisecond,
 	}
 
-	defaultConnectionTimeout = sleepDuration(3*time.Second) / time.Millisecond
+	defaultConnectionTimeout = pamtest.SleepDuration(3*time.Second) / time.Millisecond
 
 	vhsSleepRegex = regexp.MustCompile(
 		`(?m)\$\{?(AUTHD_SLEEP_[A-Z_]+)\}?(\s?([*/]+)\s?([\d.]+))?(.*)$`)
@@ -203,7 +204,7 @@ type clientOptions struct {
 func (td *tapeData) AddClientOptions(t *testing.T, opts clientOptions) {
 	t.Helper()
 
-	logFile := prepareFileLogging(t, "authd-pam-test-client.log")
+	logFile := pamtest.PrepareFileLogging(t, "authd-pam-test-client.log")
 	td.Env[pam_test.RunnerEnvLogFile] = logFile
 	td.Env[pam_test.RunnerEnvTestName] = t.Name()
 
@@ -263,7 +264,7 @@ func (td tapeData) RunVhs(t *testing.T, testType vhsTestType, outDir string, cli
 	if testutils.IsRace() {
 		raceLog = filepath.Join(t.TempDir(), "gorace.log")
 		cmd.Env = append(cmd.Env, fmt.Sprintf("GORACE=log_path=%s", raceLog))
-		saveArtifactsForDebugOnCleanup(t, []string{raceLog})
+		pamtest.SaveArtifactsForDebugOnCleanup(t, []string{raceLog})
 	}
 
 	cmd.Args = append(cmd.Args, td.PrepareTape(t, testType, outDir))
@@ -300,7 +301,7 @@ func (td tapeData) String() string {
 	for s, v := range td.Settings {
 		switch vv := v.(type) {
 		case time.Duration:
-			v = fmt.Sprintf("%dms", sleepDuration(vv).Milliseconds())
+			v = fmt.Sprintf("%dms", pamtest.SleepDuration(vv).Milliseconds())
 		case string:
 			if s == vhsWaitPattern {
 				// VHS wait pattern can be a regex, so don't quote it by default.
@@ -411,7 +412,7 @@ func (td tapeData) ExpectedOutput(t *testing.T, outputDir string) string {
 		tempOutput := filepath.Join(t.TempDir(), fmt.Sprintf("%s_sanitized.txt", baseName))
 		require.NoError(t, os.WriteFile(tempOutput, []byte(got), 0600),
 			"TearDown: Saving sanitized output file %q", tempOutput)
-		saveArtifactsForDebug(t, []string{tempOutput})
+		pamtest.SaveArtifactsForDebug(t, []string{tempOutput})
 	})
 
 	return got
@@ -451,7 +452,7 @@ func (td tapeData) PrepareTape(t *testing.T, testType vhsTestType, outputPath st
 		// Note that not sleeping enough may lead to a system hang, so keep it
 		// in mind if tests are failing in CI with with error code 143.
 		fmt.Sprintf("Sleep %dms",
-			sleepDuration(defaultSleepValues[authdSleepDefault]).Milliseconds()),
+			pamtest.SleepDuration(defaultSleepValues[authdSleepDefault]).Milliseconds()),
 	}, "\n"))
 
 	tapePath := filepath.Join(outputPath, td.Name)
@@ -466,7 +467,7 @@ func (td tapeData) PrepareTape(t *testing.T, testType vhsTestType, outputPath st
 	for _, o := range td.Outputs {
 		artifacts = append(artifacts, filepath.Join(outputPath, o))
 	}
-	saveArtifactsForDebugOnCleanup(t, artifacts)
+	pamtest.SaveArtifactsForDebugOnCleanup(t, artifacts)
 
 	return tapePath
 }
@@ -497,7 +498,7 @@ func evaluateTapeVariables(t *testing.T, tapeString string, td tapeData, testTyp
 
 		replaceRegex := regexp.MustCompile(fmt.Sprintf(`(?m)%s$`, regexp.QuoteMeta(fullMatch)))
 		tapeString = replaceRegex.ReplaceAllString(tapeString,
-			fmt.Sprintf("%dms%s", sleepDuration(sleep).Milliseconds(), rest))
+			fmt.Sprintf("%dms%s", pamtest.SleepDuration(sleep).Milliseconds(), rest))
 	}
 
 	if td.Command == "" {
@@ -2,10 +2,6 @@ package adapter
 
 import (
 	"context"
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/sha512"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -14,13 +10,17 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/authderrors"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/i18n"
+	"github.com/ubuntu/authd/internal/passwordstrength"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 	pam_proto "github.com/ubuntu/authd/pam/internal/proto"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/ubuntu/authd/pam/internal/richtext"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -28,16 +28,28 @@ const (
 	// delivered to the brokers, but also it's used to compute the time we should
 	// wait for the fully cancellation to have completed once delivered.
 	cancellationWait = time.Millisecond * 10
+
+	// authenticationSlotRetryWait is the time we wait before retrying an authentication
+	// request that the daemon could not handle because of too many concurrent ones.
+	authenticationSlotRetryWait = time.Second
+
+	// authProgressPollInterval is how often we fetch the progress reported by the broker while authenticating.
+	authProgressPollInterval = 500 * time.Millisecond
 )
 
+// authProgressSpinner are the frames of the spinner shown while the broker reports a progress.
+var authProgressSpinner = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
 var (
-	errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000"))
+	errorStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000"))
+	completedStepStyle = lipgloss.NewStyle().Faint(true)
+	currentStepStyle   = lipgloss.NewStyle().Bold(true)
 )
 
 // sendIsAuthenticated sends the authentication secrets or wait request to the brokers.
 // The event will contain the returned value from the broker.
 func sendIsAuthenticated(ctx context.Context, client authd.PAMClient, sessionID string,
-	authData *authd.IARequest_AuthenticationData, secret *string) tea.Cmd {
+	authData *authd.IARequest_AuthenticationData, plainTextSecret *secret) tea.Cmd {
 	return func() (msg tea.Msg) {
 		log.Debugf(context.TODO(), "Authentication request for session %q: %#v",
 			sessionID, authData.Item)
@@ -50,31 +62,54 @@ func sendIsAuthenticated(ctx context.Context, client authd.PAMClient, sessionID
 			SessionId:          sessionID,
 			AuthenticationData: authData,
 		})
+		if authderrors.Is(err, authderrors.ResourceExhausted) {
+			// The daemon is handling too many authentications, so let's wait and try again.
+			return tea.Sequence(
+				sendEvent(authenticationSlotWaiting{}),
+				func() tea.Msg {
+					select {
+					case <-ctx.Done():
+					case <-appClock.After(authenticationSlotRetryWait):
+					}
+					return sendIsAuthenticated(ctx, client, sessionID, authData, plainTextSecret)()
+				},
+			)()
+		}
 		if err != nil {
-			if st := status.Convert(err); st.Code() == codes.Canceled {
+			if authderrors.Is(err, authderrors.Canceled) {
 				// Note that this error is only the client-side error, so being here doesn't
 				// mean the cancellation on broker side is fully completed.
 
 				// Wait for the cancellation requests to have been delivered and actually handled.
 				// The multiplier can be increased to avoid that we return the cancelled event too
 				// early, but it implies slowing down the UI responses.
-				<-time.After(cancellationWait * 3)
+				<-appClock.After(cancellationWait * 3)
 
 				return isAuthenticatedResultReceived{
 					access: auth.Cancelled,
-					secret: secret,
+					secret: plainTextSecret,
 				}
 			}
+			plainTextSecret.wipe()
 			return pamError{
-				status: pam.ErrSystem,
+				status: PamStatusFromError(err, pam.ErrSystem),
 				msg:    fmt.Sprintf("authentication status failure: %v", err),
 			}
 		}
 
+		var encryptionKey *encryption.PublicKey
+		if res.GetEncryptionKey() != "" {
+			if encryptionKey, err = encryption.NewPublicKey(res.GetEncryptionAlgorithm(), res.GetEncryptionKey()); err != nil {
+				plainTextSecret.wipe()
+				return pamError{status: pam.ErrSystem, msg: fmt.Sprintf("invalid rotated encryption key: %v", err)}
+			}
+		}
+
 		return isAuthenticatedResultReceived{
-			access: res.Access,
-			msg:    res.Msg,
-			secret: secret,
+			access:        res.Access,
+			msg:           res.Msg,
+			secret:        plainTextSecret,
+			encryptionKey: encryptionKey,
 		}
 	}
 }
@@ -83,6 +118,38 @@ func sendIsAuthenticated(ctx context.Context, client authd.PAMClient, sessionID
 // with the given password or wait has been requested.
 type isAuthenticatedRequested struct {
 	item authd.IARequestAuthenticationDataItem
+	// secret is the challenge to send, which is only set in item once encrypted.
+	secret *secret
+}
+
+// newIsAuthenticatedRequested returns the authentication request for the given item, moving its challenge, if any,
+// to a secret.
+func newIsAuthenticatedRequested(item authd.IARequestAuthenticationDataItem) isAuthenticatedRequested {
+	challenge, ok := item.(*authd.IARequest_AuthenticationData_Challenge)
+	if !ok {
+		return isAuthenticatedRequested{item: item}
+	}
+	return isAuthenticatedRequested{secret: newSecret(challenge.Challenge)}
+}
+
+// pollAuthProgress fetches the progress reported by the broker for the authentication in progress, after waiting
+// for authProgressPollInterval, until the context is cancelled.
+func pollAuthProgress(ctx context.Context, client authd.PAMClient, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-appClock.After(authProgressPollInterval):
+		}
+
+		res, err := client.GetAuthenticationProgress(ctx, &authd.GAPRequest{SessionId: sessionID})
+		if err != nil {
+			// Not all the daemons can report the progress, so just stop polling it.
+			log.Debugf(ctx, "Could not get the authentication progress: %v", err)
+			return nil
+		}
+		return authProgressPolled{ctx: ctx, progress: res}
+	}
 }
 
 // isAuthenticatedRequestedSend is the internal event signaling that the authentication
@@ -96,8 +163,23 @@ type isAuthenticatedRequestedSend struct {
 // and data that was retrieved.
 type isAuthenticatedResultReceived struct {
 	access string
-	secret *string
+	secret *secret
 	msg    string
+	// encryptionKey is the new key of the broker, if it rotated it.
+	encryptionKey *encryption.PublicKey
+}
+
+// authProgressPolled is the internal event with the progress of the authentication, as fetched from the daemon.
+type authProgressPolled struct {
+	ctx      context.Context
+	progress *authd.GAPResponse
+}
+
+// authProgressReceived signals that the broker reported a new progress of the authentication.
+type authProgressReceived struct {
+	msg         string
+	percentage  *uint32
+	cancellable bool
 }
 
 // isAuthenticatedCancelled is the event to cancel the auth request.
@@ -105,6 +187,10 @@ type isAuthenticatedCancelled struct {
 	msg string
 }
 
+// authenticationSlotWaiting signals that the authentication request is waiting for the
+// daemon to have an available slot.
+type authenticationSlotWaiting struct{}
+
 // reselectAuthMode signals to restart auth mode selection with the same id (to resend sms or
 // reenable the broker).
 type reselectAuthMode struct{}
@@ -123,16 +209,41 @@ type authenticationComponent interface {
 type authenticationModel struct {
 	client     authd.PAMClient
 	clientType PamClientType
+	catalog    *i18n.Catalog
 
 	currentModel     authenticationComponent
 	currentSessionID string
 	currentBrokerID  string
-	currentSecret    string
+	currentSecret    *secret
 	currentLayout    string
 
+	// minStrength is the minimum strength of the new password required by the broker.
+	minStrength passwordstrength.Score
+
+	// deviceCodeHelpers copies the device codes to the clipboard and shows their URL as hyperlinks.
+	deviceCodeHelpers bool
+
+	// currentStepLabel is the label of the authentication mode of the current step, as provided by the broker.
+	currentStepLabel string
+	// completedSteps are the labels of the steps already completed in a multi-factor authentication.
+	completedSteps []string
+
 	authTracker *authTracker
 
-	encryptionKey *rsa.PublicKey
+	encryptionKey *encryption.PublicKey
+
+	// cachedCredentialsLayout is the layout to show if the authentication with cached credentials in progress fails.
+	cachedCredentialsLayout *authd.UILayout
+	// firstPassLayout is the layout to show if the password of the previous PAM modules, which is being used for the
+	// authentication, is refused. The authentication fails instead if firstPassRequired is set.
+	firstPassLayout   *authd.UILayout
+	firstPassRequired bool
+
+	// authProgress is the last progress reported by the broker for the authentication in progress, and
+	// authProgressFrames the number of times it was fetched, to animate the spinner.
+	authProgress       *authd.GAPResponse
+	authProgressFrames int
+	stopAuthProgress   func()
 
 	errorMsg string
 }
@@ -154,21 +265,34 @@ type errMsgToDisplay struct {
 // newPasswordCheck is sent to request a new password quality check.
 type newPasswordCheck struct {
 	ctx      context.Context
-	password string
+	password *secret
 }
 
 // newPasswordCheckResult returns the password quality check result.
+// The password is only set if the check succeeded and it's needed to proceed with the authentication.
 type newPasswordCheckResult struct {
 	ctx      context.Context
-	password string
+	password *secret
 	msg      string
 }
 
+// newPasswordStrengthCheck is sent to request the estimation of the strength of the new password being typed.
+type newPasswordStrengthCheck struct {
+	password *secret
+}
+
+// newPasswordStrengthResult returns the estimated strength of the new password, with the minimum one.
+type newPasswordStrengthResult struct {
+	score    passwordstrength.Score
+	minScore passwordstrength.Score
+}
+
 // newAuthenticationModel initializes a authenticationModel which needs to be Compose then.
-func newAuthenticationModel(client authd.PAMClient, clientType PamClientType) authenticationModel {
+func newAuthenticationModel(client authd.PAMClient, clientType PamClientType, catalog *i18n.Catalog) authenticationModel {
 	return authenticationModel{
 		client:      client,
 		clientType:  clientType,
+		catalog:     catalog,
 		authTracker: &authTracker{cond: sync.NewCond(&sync.Mutex{})},
 	}
 }
@@ -195,14 +319,39 @@ func (m *authenticationModel) Update(msg tea.Msg) (authModel authenticationModel
 
 	case newPasswordCheck:
 		currentSecret := m.currentSecret
+		clientType := m.clientType
+		minStrength := m.minStrength
+		catalog := m.catalog
 		return *m, func() tea.Msg {
 			res := newPasswordCheckResult{ctx: msg.ctx, password: msg.password}
-			if err := checkPasswordQuality(currentSecret, msg.password); err != nil {
+			err := currentSecret.with(func(oldPassword []byte) error {
+				return msg.password.with(func(newPassword []byte) error {
+					if err := checkPasswordQuality(catalog, oldPassword, newPassword); err != nil {
+						return err
+					}
+					return checkPasswordStrength(catalog, newPassword, minStrength)
+				})
+			})
+			if err != nil {
 				res.msg = err.Error()
 			}
+			// The interactive terminal model sends the password again once confirmed.
+			if err != nil || clientType == InteractiveTerminal {
+				msg.password.wipe()
+				res.password = nil
+			}
 			return res
 		}
 
+	case newPasswordStrengthCheck:
+		var score passwordstrength.Score
+		_ = msg.password.with(func(password []byte) error {
+			score = passwordstrength.Estimate(password)
+			return nil
+		})
+		msg.password.wipe()
+		return *m, sendEvent(newPasswordStrengthResult{score: score, minScore: m.minStrength})
+
 	case newPasswordCheckResult:
 		if m.clientType != Gdm {
 			// This may be handled by the current model, so don't return early.
@@ -211,11 +360,8 @@ func (m *authenticationModel) Update(msg tea.Msg) (authModel authenticationModel
 
 		if msg.msg == "" {
 			return *m, sendEvent(isAuthenticatedRequestedSend{
-				ctx: msg.ctx,
-				isAuthenticatedRequested: isAuthenticatedRequested{
-					// TODO(UDENG-5844): Rename this to "secret" once all broker installations support the auth data field "secret".
-					item: &authd.IARequest_AuthenticationData_Challenge{Challenge: msg.password},
-				},
+				ctx:                      msg.ctx,
+				isAuthenticatedRequested: isAuthenticatedRequested{secret: msg.password},
 			})
 		}
 
@@ -245,7 +391,7 @@ func (m *authenticationModel) Update(msg tea.Msg) (authModel authenticationModel
 			// to cancel in the broker side.
 			// So let's wait a bit in such case (we may be even too much generous), before delivering
 			// the actual cancellation.
-			<-time.After(cancellationWait)
+			<-appClock.After(cancellationWait)
 			cancel()
 		}
 
@@ -257,9 +403,8 @@ func (m *authenticationModel) Update(msg tea.Msg) (authModel authenticationModel
 		return *m, func() tea.Msg {
 			authTracker.waitAndStart(cancelFunc)
 
-			secret, hasSecret := msg.item.(*authd.IARequest_AuthenticationData_Challenge)
-			if hasSecret && clientType == Gdm && currentLayout == layouts.NewPassword {
-				return newPasswordCheck{ctx: ctx, password: secret.Challenge}
+			if msg.secret != nil && clientType == Gdm && currentLayout == layouts.NewPassword {
+				return newPasswordCheck{ctx: ctx, password: msg.secret}
 			}
 
 			return isAuthenticatedRequestedSend{msg, ctx}
@@ -267,13 +412,43 @@ func (m *authenticationModel) Update(msg tea.Msg) (authModel authenticationModel
 
 	case isAuthenticatedRequestedSend:
 		log.Debugf(context.TODO(), "%#v", msg)
-		// no password value, pass it as is
-		plainTextSecret, err := msg.encryptSecretIfPresent(m.encryptionKey)
-		if err != nil {
+		if err := msg.encryptSecretIfPresent(m.encryptionKey); err != nil {
+			msg.secret.wipe()
 			return *m, sendEvent(pamError{status: pam.ErrSystem, msg: fmt.Sprintf("could not encrypt password payload: %v", err)})
 		}
 
-		return *m, sendIsAuthenticated(msg.ctx, m.client, m.currentSessionID, &authd.IARequest_AuthenticationData{Item: msg.item}, plainTextSecret)
+		if m.stopAuthProgress != nil {
+			m.stopAuthProgress()
+		}
+		pollCtx, stopAuthProgress := context.WithCancel(msg.ctx)
+		m.stopAuthProgress = stopAuthProgress
+		m.authProgress = &authd.GAPResponse{}
+		m.authProgressFrames = 0
+
+		return *m, tea.Batch(
+			sendIsAuthenticated(msg.ctx, m.client, m.currentSessionID, &authd.IARequest_AuthenticationData{Item: msg.item}, msg.secret),
+			pollAuthProgress(pollCtx, m.client, m.currentSessionID),
+		)
+
+	case authProgressPolled:
+		if msg.ctx.Err() != nil {
+			// The authentication completed in the meantime.
+			return *m, nil
+		}
+
+		m.authProgressFrames++
+		next := pollAuthProgress(msg.ctx, m.client, m.currentSessionID)
+		msg.progress.Msg = richtext.Sanitize(msg.progress.GetMsg())
+		if proto.Equal(msg.progress, m.authProgress) {
+			return *m, next
+		}
+
+		m.authProgress = msg.progress
+		return *m, tea.Batch(next, sendEvent(authProgressReceived{
+			msg:         m.authProgress.Msg,
+			percentage:  m.authProgress.Percentage,
+			cancellable: m.authProgress.Cancellable,
+		}))
 
 	case isAuthenticatedCancelled:
 		log.Debugf(context.TODO(), "%#v", msg)
@@ -282,48 +457,86 @@ func (m *authenticationModel) Update(msg tea.Msg) (authModel authenticationModel
 	case isAuthenticatedResultReceived:
 		log.Debugf(context.TODO(), "%#v", msg)
 
-		// Resets password if the authentication wasn't successful.
+		if msg.encryptionKey != nil {
+			// The next secrets must be encrypted with the new key of the broker.
+			m.encryptionKey = msg.encryptionKey
+		}
+
+		// Keeps the password only if it's needed by the next authentication step, and wipe it otherwise.
 		defer func() {
 			// the returned authModel is a copy of function-level's `m` at this point!
 			m := &authModel
-			if msg.secret != nil &&
-				(msg.access == auth.Granted || msg.access == auth.Next) {
-				m.currentSecret = *msg.secret
+			if msg.secret != nil && msg.access == auth.Next {
+				m.currentSecret.wipe()
+				m.currentSecret = msg.secret
+			} else {
+				msg.secret.wipe()
 			}
 
 			if msg.access != auth.Next && msg.access != auth.Retry {
 				m.currentModel = nil
 			}
+			m.cachedCredentialsLayout = nil
+			m.firstPassLayout = nil
 			m.authTracker.reset()
+			if m.stopAuthProgress != nil {
+				m.stopAuthProgress()
+				m.stopAuthProgress = nil
+			}
+			m.authProgress = nil
 		}()
 
 		switch msg.access {
 		case auth.Granted:
-			infoMsg, err := dataToMsg(msg.msg)
+			infoMsg, err := dataToMsg(m.catalog, msg.msg)
 			if err != nil {
 				return *m, sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
 			}
-			return *m, sendEvent(PamSuccess{BrokerID: m.currentBrokerID, msg: infoMsg})
+			if infoMsg == "" && m.cachedCredentialsLayout != nil {
+				infoMsg = m.catalog.G("Authenticated with saved credentials")
+			}
+			return *m, sendEvent(PamSuccess{BrokerID: m.currentBrokerID, msg: plainText(infoMsg)})
 
 		case auth.Retry:
-			errorMsg, err := dataToMsg(msg.msg)
+			if m.cachedCredentialsLayout != nil {
+				// The cached credentials could not be used, so let's ask the user for the challenge.
+				log.Infof(context.TODO(), "Authentication with cached credentials failed, showing the challenge")
+				layout := proto.Clone(m.cachedCredentialsLayout).(*authd.UILayout)
+				layout.CachedCredentials = nil
+				return *m, sendEvent(UILayoutReceived{layout: layout})
+			}
+
+			errorMsg, err := dataToMsg(m.catalog, msg.msg)
 			if err != nil {
 				return *m, sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
 			}
+			if m.firstPassLayout != nil {
+				if m.firstPassRequired {
+					if errorMsg == "" {
+						errorMsg = m.catalog.G("Authentication failure")
+					}
+					return *m, sendEvent(pamError{status: pam.ErrAuth, msg: plainText(errorMsg)})
+				}
+				// The password of the previous modules is not the right one, so let's ask the user for it.
+				log.Infof(context.TODO(), "Authentication with the password of the previous PAM modules failed, showing the challenge")
+				return *m, sendEvent(UILayoutReceived{layout: m.firstPassLayout})
+			}
 			m.errorMsg = errorMsg
 			return *m, sendEvent(startAuthentication{})
 
 		case auth.Denied:
-			errMsg, err := dataToMsg(msg.msg)
+			errMsg, err := dataToMsg(m.catalog, msg.msg)
 			if err != nil {
 				return *m, sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
 			}
 			if errMsg == "" {
-				errMsg = "Access denied"
+				errMsg = m.catalog.G("Access denied")
 			}
-			return *m, sendEvent(pamError{status: pam.ErrAuth, msg: errMsg})
+			return *m, sendEvent(pamError{status: pamStatusFromErrorCode(dataToErrorCode(msg.msg)), msg: plainText(errMsg)})
 
 		case auth.Next:
+			m.completedSteps = append(m.completedSteps, m.currentStepLabel)
+			m.currentStepLabel = ""
 			return *m, sendEvent(GetAuthenticationModesRequested{})
 
 		case auth.Cancelled:
@@ -388,36 +601,65 @@ func (m *authenticationModel) Blur() {
 
 // Compose initialize the authentication model to be used.
 // It creates and attaches the sub layout models based on UILayout.
-func (m *authenticationModel) Compose(brokerID, sessionID string, encryptionKey *rsa.PublicKey, layout *authd.UILayout) tea.Cmd {
+func (m *authenticationModel) Compose(brokerID, sessionID string, encryptionKey *encryption.PublicKey, authModeLabel string, layout *authd.UILayout) tea.Cmd {
 	m.currentBrokerID = brokerID
 	m.currentSessionID = sessionID
 	m.encryptionKey = encryptionKey
 	m.currentLayout = layout.Type
+	m.currentStepLabel = authModeLabel
 
 	m.errorMsg = ""
 
+	m.minStrength = passwordstrength.VeryWeak
+	if s := layout.GetMinStrength(); s != "" {
+		minStrength, err := passwordstrength.ParseScore(s)
+		if err != nil {
+			return sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
+		}
+		m.minStrength = minStrength
+	}
+
 	if m.clientType != InteractiveTerminal {
 		return tea.Sequence(sendEvent(ChangeStage{pam_proto.Stage_challenge}),
 			sendEvent(startAuthentication{}))
 	}
 
+	// The buttons can't be styled.
+	button := plainText(layout.GetButton())
+
 	switch layout.Type {
 	case layouts.Form:
-		form := newFormModel(layout.GetLabel(), layout.GetEntry(), layout.GetButton(), layout.GetWait() == layouts.True)
+		code, err := parseCodeOptions(layout)
+		if err != nil {
+			return sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
+		}
+		form := newFormModel(layout.GetLabel(), layout.GetEntry(), button, layout.GetWait() == layouts.True, code,
+			m.catalog)
 		m.currentModel = form
 
 	case layouts.QrCode:
 		qrcodeModel, err := newQRCodeModel(layout.GetContent(), layout.GetCode(),
-			layout.GetLabel(), layout.GetButton(), layout.GetWait() == layouts.True)
+			layout.GetLabel(), button, layout.GetWait() == layouts.True, m.deviceCodeHelpers, m.catalog)
 		if err != nil {
 			return sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
 		}
 		m.currentModel = qrcodeModel
 
 	case layouts.NewPassword:
-		newPasswordModel := newNewPasswordModel(layout.GetLabel(), layout.GetEntry(), layout.GetButton())
+		newPasswordModel := newNewPasswordModel(layout.GetLabel(), layout.GetEntry(), button, m.minStrength, m.catalog)
 		m.currentModel = newPasswordModel
 
+	case layouts.SmartCard:
+		smartcardModel, err := newSmartCardModel(layout.GetSmartcardAction(), layout.GetLabel(), layout.GetContent(),
+			layout.GetEntry(), button, layout.GetWait() == layouts.True, m.catalog)
+		if err != nil {
+			return sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
+		}
+		m.currentModel = smartcardModel
+
+	case layouts.Consent:
+		m.currentModel = newConsentModel(layout.GetLabel(), layout.GetContent(), m.catalog)
+
 	default:
 		return sendEvent(pamError{
 			status: pam.ErrSystem,
@@ -431,6 +673,42 @@ func (m *authenticationModel) Compose(brokerID, sessionID string, encryptionKey
 		sendEvent(startAuthentication{}))
 }
 
+// AuthenticateWithCachedCredentials initializes the authentication model to let the broker authenticate the user with
+// cached credentials, without any user interaction. The layout is shown as usual if the broker can't.
+func (m *authenticationModel) AuthenticateWithCachedCredentials(brokerID, sessionID string, encryptionKey *encryption.PublicKey, authModeLabel string, layout *authd.UILayout) tea.Cmd {
+	m.currentBrokerID = brokerID
+	m.currentSessionID = sessionID
+	m.encryptionKey = encryptionKey
+	m.currentLayout = layout.Type
+	m.currentStepLabel = authModeLabel
+	m.currentModel = nil
+	m.cachedCredentialsLayout = layout
+
+	m.errorMsg = ""
+
+	return sendEvent(isAuthenticatedRequested{
+		item: &authd.IARequest_AuthenticationData_Wait{Wait: layouts.True},
+	})
+}
+
+// AuthenticateWithFirstPass initializes the authentication model to authenticate the user with the password collected
+// by the previous modules of the PAM stack, without any user interaction. If the password is refused, the layout is
+// shown as usual, unless the password is required.
+func (m *authenticationModel) AuthenticateWithFirstPass(brokerID, sessionID string, encryptionKey *encryption.PublicKey, authModeLabel string, layout *authd.UILayout, password *secret, required bool) tea.Cmd {
+	m.currentBrokerID = brokerID
+	m.currentSessionID = sessionID
+	m.encryptionKey = encryptionKey
+	m.currentLayout = layout.Type
+	m.currentStepLabel = authModeLabel
+	m.currentModel = nil
+	m.firstPassLayout = layout
+	m.firstPassRequired = required
+
+	m.errorMsg = ""
+
+	return sendEvent(isAuthenticatedRequested{secret: password})
+}
+
 // View renders a text view of the authentication UI.
 func (m authenticationModel) View() string {
 	if m.currentModel == nil {
@@ -441,9 +719,13 @@ func (m authenticationModel) View() string {
 	}
 	contents := []string{m.currentModel.View()}
 
+	if progress := m.authProgressView(); progress != "" {
+		contents = append(contents, progress)
+	}
+
 	errMsg := m.errorMsg
 	if errMsg != "" {
-		contents = append(contents, errorStyle.Render(errMsg))
+		contents = append(contents, errorStyle.Render(renderRichText(errMsg)))
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left,
@@ -451,6 +733,54 @@ func (m authenticationModel) View() string {
 	)
 }
 
+// authProgressView renders the progress reported by the broker for the authentication in progress, if any.
+func (m authenticationModel) authProgressView() string {
+	if m.authProgress.GetMsg() == "" {
+		return ""
+	}
+
+	spinner := authProgressSpinner[m.authProgressFrames%len(authProgressSpinner)]
+	progress := fmt.Sprintf("%s %s", spinner, renderRichText(m.authProgress.GetMsg()))
+	if m.authProgress.Percentage != nil {
+		progress = fmt.Sprintf("%s (%d%%)", progress, m.authProgress.GetPercentage())
+	}
+	if !m.authProgress.GetCancellable() {
+		return progress
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, progress, m.catalog.G("Press Esc to cancel."))
+}
+
+// StepsView renders the progress of a multi-factor authentication, if more than one step is required.
+func (m authenticationModel) StepsView() string {
+	if len(m.completedSteps) == 0 {
+		return ""
+	}
+
+	var contents []string
+	for i, label := range m.completedSteps {
+		contents = append(contents, completedStepStyle.Render(fmt.Sprintf(m.catalog.G("✓ Step %d: %s"), i+1, label)))
+	}
+
+	current := fmt.Sprintf(m.catalog.G("Step %d"), len(m.completedSteps)+1)
+	if m.currentStepLabel != "" {
+		current = fmt.Sprintf(m.catalog.G("Step %d: %s"), len(m.completedSteps)+1, m.currentStepLabel)
+	}
+	contents = append(contents,
+		currentStepStyle.Render(current),
+		m.catalog.G("Press Esc to go back, Ctrl+C to cancel."),
+		"")
+
+	return lipgloss.JoinVertical(lipgloss.Left, contents...)
+}
+
+// ResetSteps forgets about the steps completed in a multi-factor authentication, and wipes the secret used by them.
+func (m *authenticationModel) ResetSteps() {
+	m.completedSteps = nil
+	m.currentStepLabel = ""
+	m.currentSecret.wipe()
+	m.currentSecret = nil
+}
+
 // Resets zeroes any internal state on the authenticationModel.
 func (m *authenticationModel) Reset() tea.Cmd {
 	log.Debugf(context.TODO(), "%T: Reset", m)
@@ -458,11 +788,13 @@ func (m *authenticationModel) Reset() tea.Cmd {
 	m.currentSessionID = ""
 	m.currentBrokerID = ""
 	m.currentLayout = ""
+	m.currentStepLabel = ""
 	return m.cancelIsAuthenticated()
 }
 
 // dataToMsg returns the data message from a given JSON message.
-func dataToMsg(data string) (string, error) {
+// The default messages of the error codes are translated with the catalog.
+func dataToMsg(catalog *i18n.Catalog, data string) (string, error) {
 	if data == "" {
 		return "", nil
 	}
@@ -479,25 +811,55 @@ func dataToMsg(data string) (string, error) {
 	if !ok {
 		return "", fmt.Errorf("no message entry in json data from provider: %v", v)
 	}
-	return r, nil
+	if r == "" {
+		r = errorCodeMessage(catalog, v[auth.ErrorCodeKey])
+	}
+	return richtext.Sanitize(r), nil
 }
 
-func (authData *isAuthenticatedRequestedSend) encryptSecretIfPresent(publicKey *rsa.PublicKey) (*string, error) {
-	// no password value, pass it as is
-	secret, ok := authData.item.(*authd.IARequest_AuthenticationData_Challenge)
-	if !ok {
-		return nil, nil
+// dataToErrorCode returns the error code from a given JSON message, if any.
+func dataToErrorCode(data string) string {
+	v := make(map[string]string)
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return ""
 	}
+	return v[auth.ErrorCodeKey]
+}
 
-	ciphertext, err := rsa.EncryptOAEP(sha512.New(), rand.Reader, publicKey, []byte(secret.Challenge), nil)
-	if err != nil {
-		return nil, err
+// errorCodeMessage returns the default message to show to the user for a broker error code.
+func errorCodeMessage(catalog *i18n.Catalog, code string) string {
+	switch code {
+	case auth.ErrorCodeNetwork:
+		return catalog.G("The authentication provider could not be reached")
+	case auth.ErrorCodeInvalidCredentials:
+		return catalog.G("Invalid credentials")
+	case auth.ErrorCodeAccountLocked:
+		return catalog.G("The account is locked")
+	case auth.ErrorCodeMFARequired:
+		return catalog.G("Multi-factor authentication is required")
+	case auth.ErrorCodeConsentDenied:
+		return catalog.G("The required consent has been denied")
+	default:
+		return ""
+	}
+}
+
+func (authData *isAuthenticatedRequestedSend) encryptSecretIfPresent(publicKey *encryption.PublicKey) error {
+	// no password value, pass the item as is
+	if authData.secret == nil {
+		return nil
 	}
 
-	// encrypt it to base64 and replace the password with it
-	base64Encoded := base64.StdEncoding.EncodeToString(ciphertext)
-	authData.item = &authd.IARequest_AuthenticationData_Challenge{Challenge: base64Encoded}
-	return &secret.Challenge, nil
+	// encrypt it to base64 and send it as the challenge
+	return authData.secret.with(func(plainText []byte) error {
+		encrypted, err := publicKey.EncryptBytes(plainText)
+		if err != nil {
+			return err
+		}
+		// TODO(UDENG-5844): Rename this to "secret" once all broker installations support the auth data field "secret".
+		authData.item = &authd.IARequest_AuthenticationData_Challenge{Challenge: encrypted}
+		return nil
+	})
 }
 
 // wait waits for the current authentication to be completed.
@@ -0,0 +1,85 @@
+package adapter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/proto/authd"
+)
+
+func TestAuthenticationModelStepsView(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		completedSteps []string
+		currentStep    string
+		resetSteps     bool
+
+		wantView string
+	}{
+		"No_steps_for_a_single_factor_authentication": {
+			currentStep: "Password",
+		},
+		"Second_step_of_a_multi_factor_authentication": {
+			completedSteps: []string{"Password"},
+			currentStep:    "Phone",
+			wantView: "✓ Step 1: Password\n" +
+				"Step 2: Phone\n" +
+				"Press Esc to go back, Ctrl+C to cancel.\n",
+		},
+		"Third_step_of_a_multi_factor_authentication": {
+			completedSteps: []string{"Password", "Phone"},
+			currentStep:    "Security key",
+			wantView: "✓ Step 1: Password\n" +
+				"✓ Step 2: Phone\n" +
+				"Step 3: Security key\n" +
+				"Press Esc to go back, Ctrl+C to cancel.\n",
+		},
+		"Current_step_without_label": {
+			completedSteps: []string{"Password"},
+			wantView: "✓ Step 1: Password\n" +
+				"Step 2\n" +
+				"Press Esc to go back, Ctrl+C to cancel.\n",
+		},
+		"No_steps_after_reset": {
+			completedSteps: []string{"Password", "Phone"},
+			currentStep:    "Security key",
+			resetSteps:     true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := newAuthenticationModel(nil, InteractiveTerminal, nil)
+			compose := func(label string) {
+				m.Compose("broker-id", "session-id", nil, label, &authd.UILayout{
+					Type:  layouts.Form,
+					Label: ptrValue("Secret"),
+					Entry: ptrValue(entries.CharsPassword),
+				})
+			}
+
+			for _, step := range tc.completedSteps {
+				compose(step)
+				m, _ = m.Update(isAuthenticatedResultReceived{access: auth.Next})
+			}
+			compose(tc.currentStep)
+
+			if tc.resetSteps {
+				m.ResetSteps()
+			}
+
+			// The lines are padded to the same width, as they are joined as a block.
+			var lines []string
+			for _, l := range strings.Split(m.StepsView(), "\n") {
+				lines = append(lines, strings.TrimRight(l, " "))
+			}
+			require.Equal(t, tc.wantView, strings.Join(lines, "\n"), "Steps view is not the expected one")
+		})
+	}
+}
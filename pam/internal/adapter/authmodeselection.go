@@ -3,6 +3,7 @@ package adapter
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -11,6 +12,7 @@ import (
 	"github.com/msteinert/pam/v2"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
 	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 )
@@ -36,7 +38,8 @@ type supportedUILayoutsSet struct{}
 
 // authModesReceived is the internal event signalling that the supported authentication modes have been received.
 type authModesReceived struct {
-	authModes []*authd.GAMResponse_AuthenticationMode
+	authModes          []*authd.GAMResponse_AuthenticationMode
+	lastUsedAuthModeID string
 }
 
 // authModeSelected is the internal event signalling that the an authentication mode has been selected.
@@ -57,7 +60,7 @@ func selectAuthMode(id string) tea.Cmd {
 }
 
 // newAuthModeSelectionModel initializes an empty list with default options of authModeSelectionModel.
-func newAuthModeSelectionModel(clientType PamClientType) authModeSelectionModel {
+func newAuthModeSelectionModel(clientType PamClientType, catalog *i18n.Catalog) authModeSelectionModel {
 	// FIXME: decouple UI from data model.
 	if clientType != InteractiveTerminal {
 		return authModeSelectionModel{
@@ -67,7 +70,7 @@ func newAuthModeSelectionModel(clientType PamClientType) authModeSelectionModel
 	}
 
 	l := list.New(nil, itemLayout{}, 80, 24)
-	l.Title = "Select your authentication method"
+	l.Title = catalog.G("Select your authentication method")
 	l.SetShowStatusBar(false)
 	l.SetShowHelp(false)
 	l.DisableQuitKeybindings()
@@ -95,30 +98,58 @@ func (m *authModeSelectionModel) Init() tea.Cmd {
 			entries.CharsPassword,
 		)
 		rendersQrCode := true
+		smartCardActions := layouts.RequiredItems(
+			layouts.SmartCardInsert,
+			layouts.SmartCardPIN,
+			layouts.SmartCardTouch,
+		)
+
+		minStrengths := layouts.OptionalItems("0", "1", "2", "3", "4")
 
 		return supportedUILayoutsReceived{
 			layouts: []*authd.UILayout{
 				{
-					Type:   layouts.Form,
-					Label:  &required,
-					Entry:  &supportedEntries,
-					Wait:   &layouts.OptionalWithBooleans,
-					Button: &optional,
+					Type:         layouts.Form,
+					Label:        &required,
+					Entry:        &supportedEntries,
+					Wait:         &layouts.OptionalWithBooleans,
+					Button:       &optional,
+					CodeLength:   &optional,
+					CodeValidity: &optional,
+					// This is handled by the authentication model, without showing the form.
+					CachedCredentials: &layouts.OptionalWithBooleans,
+				},
+				{
+					Type:              layouts.QrCode,
+					Content:           &required,
+					Code:              &optional,
+					Wait:              &layouts.RequiredWithBooleans,
+					Label:             &optional,
+					Button:            &optional,
+					RendersQrcode:     &rendersQrCode,
+					CachedCredentials: &layouts.OptionalWithBooleans,
 				},
 				{
-					Type:          layouts.QrCode,
-					Content:       &required,
-					Code:          &optional,
-					Wait:          &layouts.RequiredWithBooleans,
-					Label:         &optional,
-					Button:        &optional,
-					RendersQrcode: &rendersQrCode,
+					Type:        layouts.NewPassword,
+					Label:       &required,
+					Entry:       &supportedEntries,
+					Button:      &optional,
+					MinStrength: &minStrengths,
 				},
 				{
-					Type:   layouts.NewPassword,
-					Label:  &required,
-					Entry:  &supportedEntries,
-					Button: &optional,
+					Type:              layouts.SmartCard,
+					SmartcardAction:   &smartCardActions,
+					Label:             &optional,
+					Content:           &optional,
+					Entry:             &supportedEntries,
+					Wait:              &layouts.OptionalWithBooleans,
+					Button:            &optional,
+					CachedCredentials: &layouts.OptionalWithBooleans,
+				},
+				{
+					Type:    layouts.Consent,
+					Label:   &optional,
+					Content: &required,
 				},
 			},
 		}
@@ -160,8 +191,10 @@ func (m authModeSelectionModel) Update(msg tea.Msg) (authModeSelectionModel, tea
 		}
 
 		cmds := []tea.Cmd{m.SetItems(allAuthModes)}
-		// Autoselect first auth mode if any.
-		if firstAuthModeID != "" {
+		// Autoselect the auth mode the user last authenticated with, or the first one if any.
+		if validAuthModeID(msg.lastUsedAuthModeID, m.availableAuthModes) {
+			cmds = append(cmds, selectAuthMode(msg.lastUsedAuthModeID))
+		} else if firstAuthModeID != "" {
 			cmds = append(cmds, selectAuthMode(firstAuthModeID))
 		}
 
@@ -281,7 +314,8 @@ func validAuthModeID(id string, authModes []*authd.GAMResponse_AuthenticationMod
 }
 
 // getAuthenticationModes returns available authentication mode for this broker from authd.
-func getAuthenticationModes(client authd.PAMClient, sessionID string, uiLayouts []*authd.UILayout) tea.Cmd {
+// If allowedModes is not empty, only the modes with those IDs are returned.
+func getAuthenticationModes(client authd.PAMClient, sessionID string, uiLayouts []*authd.UILayout, allowedModes []string) tea.Cmd {
 	return func() tea.Msg {
 		gamReq := &authd.GAMRequest{
 			SessionId:          sessionID,
@@ -291,7 +325,7 @@ func getAuthenticationModes(client authd.PAMClient, sessionID string, uiLayouts
 		gamResp, err := client.GetAuthenticationModes(context.Background(), gamReq)
 		if err != nil {
 			return pamError{
-				status: pam.ErrSystem,
+				status: PamStatusFromError(err, pam.ErrSystem),
 				msg:    fmt.Sprintf("could not get authentication modes: %v", err),
 			}
 		}
@@ -303,12 +337,34 @@ func getAuthenticationModes(client authd.PAMClient, sessionID string, uiLayouts
 				msg:    "no supported authentication mode available for this provider",
 			}
 		}
+		if len(allowedModes) > 0 {
+			authModes = slices.DeleteFunc(authModes, func(a *authd.GAMResponse_AuthenticationMode) bool {
+				return !slices.Contains(allowedModes, a.Id)
+			})
+			if len(authModes) == 0 {
+				return pamError{
+					status: pam.ErrCredUnavail,
+					msg:    "no authentication mode of this provider is allowed for this service",
+				}
+			}
+		}
 		log.Debug(context.TODO(), "authModes", authModes)
 
 		return authModesReceived{
-			authModes: authModes,
+			authModes:          authModes,
+			lastUsedAuthModeID: gamResp.GetLastUsedAuthenticationModeId(),
+		}
+	}
+}
+
+// currentAuthModeLabel returns the label of the currently selected authentication mode.
+func (m authModeSelectionModel) currentAuthModeLabel() string {
+	for _, a := range m.availableAuthModes {
+		if a.Id == m.currentAuthModeSelectedID {
+			return a.Label
 		}
 	}
+	return ""
 }
 
 // Resets zeroes any internal state on the authModeSelectionModel.
@@ -10,6 +10,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/authd/pam/internal/proto"
@@ -22,6 +24,7 @@ type brokerSelectionModel struct {
 
 	client     authd.PAMClient
 	clientType PamClientType
+	catalog    *i18n.Catalog
 
 	availableBrokers []*authd.ABResponse_BrokerInfo
 }
@@ -37,7 +40,10 @@ type brokerSelected struct {
 }
 
 // brokerSelectionRequired is the internal event that a broker needs to be selected.
-type brokerSelectionRequired struct{}
+type brokerSelectionRequired struct {
+	// forced is set if the user previously used a broker, but is asked to select one again.
+	forced bool
+}
 
 // selectBroker selects a given broker.
 func selectBroker(brokerID string) tea.Cmd {
@@ -49,9 +55,9 @@ func selectBroker(brokerID string) tea.Cmd {
 }
 
 // newBrokerSelectionModel initializes an empty list with default options of brokerSelectionModel.
-func newBrokerSelectionModel(client authd.PAMClient, clientType PamClientType) brokerSelectionModel {
+func newBrokerSelectionModel(client authd.PAMClient, clientType PamClientType, catalog *i18n.Catalog) brokerSelectionModel {
 	l := list.New(nil, itemLayout{}, 80, 24)
-	l.Title = "Select your provider"
+	l.Title = catalog.G("Select your provider")
 	l.SetShowStatusBar(false)
 	l.SetShowHelp(false)
 	l.DisableQuitKeybindings()
@@ -64,6 +70,7 @@ func newBrokerSelectionModel(client authd.PAMClient, clientType PamClientType) b
 		Model:      l,
 		client:     client,
 		clientType: clientType,
+		catalog:    catalog,
 	}
 }
 
@@ -83,7 +90,7 @@ func (m brokerSelectionModel) Update(msg tea.Msg) (brokerSelectionModel, tea.Cmd
 		if len(msg.brokers) == 0 {
 			return m, sendEvent(pamError{
 				status: pam.ErrAuthinfoUnavail,
-				msg:    "No brokers available",
+				msg:    m.catalog.G("No brokers available"),
 			})
 		}
 		m.availableBrokers = msg.brokers
@@ -193,11 +200,13 @@ func (m *brokerSelectionModel) Blur() {
 }
 
 // AutoSelectForUser requests if any previous broker was used by this user to automatically selects it.
-func AutoSelectForUser(client authd.PAMClient, username string) tea.Cmd {
+// The default broker, if not nil, overrides the one the daemon selects for users which never used any.
+func AutoSelectForUser(client authd.PAMClient, username string, defaultBroker *string) tea.Cmd {
 	return func() tea.Msg {
 		r, err := client.GetPreviousBroker(context.TODO(),
 			&authd.GPBRequest{
-				Username: username,
+				Username:      username,
+				DefaultBroker: defaultBroker,
 			})
 		// We keep a chance to manually select the broker, not a blocker issue.
 		if err != nil {
@@ -213,6 +222,30 @@ func AutoSelectForUser(client authd.PAMClient, username string) tea.Cmd {
 	}
 }
 
+// ForceSelectForUser requests the user to select their broker, even if they previously used one. The users handled by
+// the local broker are still left to the other PAM modules.
+func ForceSelectForUser(client authd.PAMClient, username string, defaultBroker *string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := client.GetPreviousBroker(context.TODO(),
+			&authd.GPBRequest{
+				Username:      username,
+				DefaultBroker: defaultBroker,
+			})
+		if err != nil {
+			log.Infof(context.TODO(), "can't get previous broker for %q", username)
+			return brokerSelectionRequired{}
+		}
+		switch brokerID := r.GetPreviousBroker(); brokerID {
+		case "":
+			return brokerSelectionRequired{}
+		case brokers.LocalBrokerName:
+			return selectBroker(brokerID)()
+		}
+
+		return brokerSelectionRequired{forced: true}
+	}
+}
+
 // WillCaptureEscape returns if this broker may capture Esc typing on keyboard.
 func (m brokerSelectionModel) WillCaptureEscape() bool {
 	return m.FilterState() == list.Filtering
@@ -268,7 +301,7 @@ func getAvailableBrokers(client authd.PAMClient) tea.Cmd {
 		brokersInfo, err := client.AvailableBrokers(context.TODO(), &authd.Empty{})
 		if err != nil {
 			return pamError{
-				status: pam.ErrSystem,
+				status: PamStatusFromError(err, pam.ErrSystem),
 				msg:    fmt.Sprintf("could not get current available brokers: %v", err),
 			}
 		}
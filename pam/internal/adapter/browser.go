@@ -0,0 +1,182 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+)
+
+// DefaultBrowserLauncher is the command opening the verification URLs of the link-based authentication modes.
+const DefaultBrowserLauncher = "/usr/bin/xdg-open"
+
+// browserLauncherEnv are the environment variables of the graphical session passed to the browser launcher.
+var browserLauncherEnv = []string{
+	"DISPLAY",
+	"WAYLAND_DISPLAY",
+	"XAUTHORITY",
+	"XDG_RUNTIME_DIR",
+	"XDG_SESSION_TYPE",
+	"XDG_CURRENT_DESKTOP",
+	"DBUS_SESSION_BUS_ADDRESS",
+	"LANG",
+	"LANGUAGE",
+}
+
+// maxVerificationURLLength is the maximum length of the verification URLs passed to the browser launcher.
+const maxVerificationURLLength = 2048
+
+// getuid returns the real user ID of the PAM application, it's overridden in tests.
+var getuid = os.Getuid
+
+// browserLaunchSupported returns whether the module runs in the local graphical session of an unprivileged user, like
+// for sudo in a terminal emulator, in which a browser can be opened.
+// The browser is never opened for GDM, as the greeter has no user session, nor for polkit, as its agent runs the
+// module through a setuid helper whose environment is not the one of the session. It's never opened as root either.
+func browserLaunchSupported(mTx pam.ModuleTransaction, clientType PamClientType) bool {
+	if clientType == Gdm {
+		return false
+	}
+	if service, _ := mTx.GetItem(pam.Service); service == polkitServiceName {
+		return false
+	}
+	if getuid() == 0 {
+		return false
+	}
+	return !isSSHSession(mTx) && hasGraphicalDisplay()
+}
+
+// openVerificationURL opens the verification URL of the link-based authentication modes in the browser, if a
+// launcher is set.
+func (m *UIModel) openVerificationURL(layout *authd.UILayout) tea.Cmd {
+	if m.browserLauncher == "" || layout.GetType() != layouts.QrCode {
+		return nil
+	}
+	verificationURL := layout.GetContent()
+	if verificationURL == m.openedURL {
+		return nil
+	}
+	m.openedURL = verificationURL
+
+	launcher := m.browserLauncher
+	return func() tea.Msg {
+		if err := openBrowser(launcher, verificationURL); err != nil {
+			log.Warningf(context.TODO(), "Could not open the verification URL in the browser: %v", err)
+		}
+		return nil
+	}
+}
+
+// openBrowser launches the browser launcher with the verification URL, as the user running the PAM application. The
+// launcher is detached, so that the authentication continues while the browser is opened.
+func openBrowser(launcher, verificationURL string) error {
+	if err := checkVerificationURL(verificationURL); err != nil {
+		return err
+	}
+	if err := checkBrowserLauncher(launcher); err != nil {
+		return err
+	}
+
+	// Applications like sudo and polkit run with the real user ID of the user, who owns the graphical session.
+	uid, gid := getuid(), os.Getgid()
+	if uid == 0 {
+		return errors.New("refusing to open a browser as root")
+	}
+	u, err := user.LookupId(fmt.Sprint(uid))
+	if err != nil {
+		return fmt.Errorf("could not find the user opening the browser: %w", err)
+	}
+
+	env := []string{
+		"PATH=/usr/local/bin:/usr/bin:/bin",
+		"HOME=" + u.HomeDir,
+		"USER=" + u.Username,
+		"LOGNAME=" + u.Username,
+	}
+	for _, name := range browserLauncherEnv {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	if _, ok := os.LookupEnv("XDG_RUNTIME_DIR"); !ok {
+		env = append(env, fmt.Sprintf("XDG_RUNTIME_DIR=/run/user/%d", uid))
+	}
+
+	//nolint:gosec // The launcher is checked to be owned by root and the URL to be a web one.
+	cmd := exec.Command(launcher, verificationURL)
+	cmd.Env = env
+	cmd.Dir = "/"
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if os.Geteuid() == 0 {
+		// Drop the privileges of the setuid applications, including their supplementary groups.
+		cmd.SysProcAttr.Credential = &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid), Groups: []uint32{}}
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("could not launch %q: %w", launcher, err)
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			log.Debugf(context.TODO(), "Browser launcher %q exited: %v", launcher, err)
+		}
+	}()
+
+	return nil
+}
+
+// checkVerificationURL returns an error if the URL is not a web one which can be safely passed to the launcher.
+func checkVerificationURL(verificationURL string) error {
+	if len(verificationURL) > maxVerificationURLLength {
+		return fmt.Errorf("verification URL is longer than %d characters", maxVerificationURLLength)
+	}
+	if strings.HasPrefix(verificationURL, "-") || strings.IndexFunc(verificationURL, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}) >= 0 {
+		return fmt.Errorf("invalid verification URL %q", verificationURL)
+	}
+	u, err := url.Parse(verificationURL)
+	if err != nil {
+		return fmt.Errorf("invalid verification URL %q: %w", verificationURL, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("verification URL %q is not a https one", verificationURL)
+	}
+	if u.User != nil {
+		return fmt.Errorf("verification URL %q must not contain credentials", verificationURL)
+	}
+	return nil
+}
+
+// checkBrowserLauncher returns an error if the launcher is not an executable that only root can modify.
+func checkBrowserLauncher(launcher string) error {
+	if !filepath.IsAbs(launcher) {
+		return fmt.Errorf("browser launcher %q is not an absolute path", launcher)
+	}
+	fi, err := os.Stat(launcher)
+	if err != nil {
+		return fmt.Errorf("invalid browser launcher: %w", err)
+	}
+	if !fi.Mode().IsRegular() || fi.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("browser launcher %q is not an executable", launcher)
+	}
+	if fi.Mode().Perm()&0022 != 0 {
+		return fmt.Errorf("browser launcher %q is writable by other users than its owner", launcher)
+	}
+	if st, ok := fi.Sys().(*syscall.Stat_t); !ok || st.Uid != 0 {
+		return fmt.Errorf("browser launcher %q is not owned by root", launcher)
+	}
+	return nil
+}
@@ -34,13 +34,13 @@ func (b buttonModel) Init() tea.Cmd {
 func (b buttonModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case startAuthentication:
-		b.selectionTime = time.Now()
+		b.selectionTime = appClock.Now()
 
 	// Key presses
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "enter":
-			now := time.Now()
+			now := appClock.Now()
 			if now.Sub(b.selectionTime) < reselectionWaitTime {
 				log.Debug(context.TODO(), "Button press ignored, too fast!")
 				return &b, nil
@@ -3,12 +3,12 @@ package adapter
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/msteinert/pam/v2"
 	"github.com/ubuntu/authd/internal/brokers"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 )
@@ -21,33 +21,29 @@ func sendEvent(msg tea.Msg) tea.Cmd {
 }
 
 // startBrokerSession returns the sessionID after marking a broker as current.
-func startBrokerSession(client authd.PAMClient, brokerID, username string, mode authd.SessionMode) tea.Cmd {
+// The service is the PAM service requesting the session, which the daemon uses to decide if it's a step-up one, and
+// the tty is the terminal of the PAM transaction, which the recent strong authentications are scoped to.
+func startBrokerSession(client authd.PAMClient, brokerID, username, locale, service, tty string, mode authd.SessionMode) tea.Cmd {
 	return func() tea.Msg {
 		if brokerID == brokers.LocalBrokerName {
 			return pamError{status: pam.ErrIgnore}
 		}
 
 		// Start a transaction for this user with the broker.
-		// TODO: gdm case?
-		lang := "C"
-		for _, e := range []string{"LANG", "LC_MESSAGES", "LC_ALL"} {
-			l := os.Getenv(e)
-			if l != "" {
-				lang = l
-			}
-		}
-		lang = strings.TrimSuffix(lang, ".UTF-8")
+		lang := strings.TrimSuffix(locale, ".UTF-8")
 
 		sbReq := &authd.SBRequest{
 			BrokerId: brokerID,
 			Username: username,
 			Lang:     lang,
 			Mode:     mode,
+			Service:  service,
+			Tty:      tty,
 		}
 
 		sbResp, err := client.SelectBroker(context.TODO(), sbReq)
 		if err != nil {
-			return pamError{status: pam.ErrSystem, msg: fmt.Sprintf("can't select broker: %v", err)}
+			return pamError{status: PamStatusFromError(err, pam.ErrSystem), msg: fmt.Sprintf("can't select broker: %v", err)}
 		}
 
 		sessionID := sbResp.GetSessionId()
@@ -59,10 +55,18 @@ func startBrokerSession(client authd.PAMClient, brokerID, username string, mode
 			return pamError{status: pam.ErrSystem, msg: "no encryption key returned by broker"}
 		}
 
+		algorithm := sbResp.GetEncryptionAlgorithm()
+		if algorithm == "" {
+			// Older daemons don't send the algorithm, which is always the default one.
+			algorithm = encryption.DefaultAlgorithm
+		}
+
 		return SessionStarted{
-			brokerID:      brokerID,
-			sessionID:     sessionID,
-			encryptionKey: encryptionKey,
+			brokerID:            brokerID,
+			username:            username,
+			sessionID:           sessionID,
+			encryptionKey:       encryptionKey,
+			encryptionAlgorithm: algorithm,
 		}
 	}
 }
@@ -78,7 +82,7 @@ func getLayout(client authd.PAMClient, sessionID, authModeID string) tea.Cmd {
 		if err != nil {
 			// TODO: probably go back to broker selection here
 			return pamError{
-				status: pam.ErrSystem,
+				status: PamStatusFromError(err, pam.ErrSystem),
 				msg:    fmt.Sprintf("can't select authentication mode: %v", err),
 			}
 		}
@@ -121,3 +125,17 @@ func endSession(client authd.PAMClient, currentSession *sessionInfo) tea.Cmd {
 		return SessionEnded{}
 	}
 }
+
+// endStaleSession requests the broker to end a session that is not the current one anymore.
+// Contrary to endSession, no [SessionEnded] event is sent as the current session is not affected.
+func endStaleSession(client authd.PAMClient, sessionID string) tea.Cmd {
+	if sessionID == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, err := client.EndSession(context.Background(), &authd.ESRequest{SessionId: sessionID}); err != nil {
+			log.Infof(context.Background(), "Could not end stale session %q. Considering already done", sessionID)
+		}
+		return nil
+	}
+}
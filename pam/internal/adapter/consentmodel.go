@@ -0,0 +1,93 @@
+package adapter
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/i18n"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+)
+
+// consentModel is the consent layout type showing a message the user must accept or decline.
+type consentModel struct {
+	label   string
+	content string
+
+	buttons    []*buttonModel
+	focusIndex int
+}
+
+// newConsentModel initializes and returns a new consentModel, with the accept button focused.
+func newConsentModel(label, content string, catalog *i18n.Catalog) consentModel {
+	return consentModel{
+		label:   label,
+		content: content,
+		buttons: []*buttonModel{
+			{label: catalog.G("Accept")},
+			{label: catalog.G("Decline")},
+		},
+	}
+}
+
+// Init initializes consentModel.
+func (m consentModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles events and actions.
+func (m consentModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case buttonSelectionEvent:
+		// The accept button is the first one.
+		answer := layouts.False
+		if msg.model == m.buttons[0] {
+			answer = layouts.True
+		}
+		return m, sendEvent(isAuthenticatedRequested{
+			item: &authd.IARequest_AuthenticationData_Consent{Consent: answer},
+		})
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "tab", "shift+tab", "left", "right":
+			m.buttons[m.focusIndex].Blur()
+			m.focusIndex = (m.focusIndex + 1) % len(m.buttons)
+			return m, m.buttons[m.focusIndex].Focus()
+		}
+	}
+
+	model, cmd := m.buttons[m.focusIndex].Update(msg)
+	m.buttons[m.focusIndex] = convertTo[*buttonModel](model)
+	return m, cmd
+}
+
+// View renders a text view of the consent message.
+func (m consentModel) View() string {
+	fields := []string{renderRichText(m.label), "", renderRichText(m.content)}
+	for _, b := range m.buttons {
+		fields = append(fields, b.View())
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, fields...)
+}
+
+// Focus focuses this model.
+func (m consentModel) Focus() tea.Cmd {
+	log.Debugf(context.TODO(), "%T: Focus", m)
+	return m.buttons[m.focusIndex].Focus()
+}
+
+// Blur releases the focus from this model.
+func (m consentModel) Blur() {
+	log.Debugf(context.TODO(), "%T: Blur", m)
+	m.buttons[m.focusIndex].Blur()
+}
+
+// Focused returns whether this model is focused.
+func (m consentModel) Focused() bool {
+	// This is always considered focused.
+	return true
+}
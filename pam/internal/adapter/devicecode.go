@@ -0,0 +1,71 @@
+package adapter
+
+import (
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/msteinert/pam/v2"
+	"github.com/muesli/termenv"
+)
+
+// graphicalSessionTypes are the XDG session types of the graphical sessions, whose terminal emulators support the
+// clipboard and hyperlinks escape sequences, unlike the virtual consoles.
+var graphicalSessionTypes = []string{"x11", "wayland"}
+
+// deviceCodeOutput returns the terminal output the clipboard and hyperlinks escape sequences are written to.
+var deviceCodeOutput = termenv.DefaultOutput
+
+// deviceCodeHelpersSupported returns whether the module runs in a terminal emulator of the local graphical session of
+// the user, like for sudo, in which the device codes can be copied to the clipboard and their URL shown as hyperlinks.
+func deviceCodeHelpersSupported(mTx pam.ModuleTransaction) bool {
+	if isSSHSession(mTx) {
+		// The clipboard would be the one of the remote machine, if supported at all.
+		return false
+	}
+	if os.Getenv("TERM") == "linux" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return hasGraphicalDisplay()
+}
+
+// hasGraphicalDisplay returns whether the module runs in a graphical session.
+func hasGraphicalDisplay() bool {
+	if slices.Contains(graphicalSessionTypes, os.Getenv("XDG_SESSION_TYPE")) {
+		return true
+	}
+	return os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("DISPLAY") != ""
+}
+
+// copyToClipboard copies the text to the clipboard of the terminal emulator, with the OSC 52 escape sequence.
+func copyToClipboard(text string) {
+	deviceCodeOutput().Copy(text)
+}
+
+// stripControlCharacters returns the text provided by the broker without the control characters, so that it can't
+// inject escape sequences in the terminal.
+func stripControlCharacters(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// isWebURL returns whether the text is the URL of a web page, which can be shown as a hyperlink.
+func isWebURL(text string) bool {
+	if strings.ContainsFunc(text, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) {
+		// The URL would end the hyperlink escape sequence early.
+		return false
+	}
+	u, err := url.Parse(text)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+// hyperlink returns the text as a hyperlink to the URL, with the OSC 8 escape sequence.
+func hyperlink(url, text string) string {
+	return deviceCodeOutput().Hyperlink(url, text)
+}
@@ -2,13 +2,17 @@ package adapter
 
 import (
 	"context"
+	"fmt"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
 	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 )
@@ -21,10 +25,46 @@ type formModel struct {
 	focusIndex      int
 
 	wait bool
+
+	code          codeOptions
+	codeExpiresAt time.Time
+
+	catalog *i18n.Catalog
+}
+
+// codeOptions are the options of the forms used to enter a one time code.
+type codeOptions struct {
+	// length is the number of characters of the code, after which it's submitted automatically.
+	length int
+	// validity is how long the code is valid for.
+	validity time.Duration
+}
+
+// codeCountdownTick is the event refreshing the countdown of the code validity.
+type codeCountdownTick struct {
+	expiresAt time.Time
+}
+
+// parseCodeOptions returns the code options of the layout, if any.
+func parseCodeOptions(layout *authd.UILayout) (opts codeOptions, err error) {
+	if l := layout.GetCodeLength(); l != "" {
+		opts.length, err = strconv.Atoi(l)
+		if err != nil || opts.length <= 0 {
+			return codeOptions{}, fmt.Errorf("invalid code length: %q", l)
+		}
+	}
+	if v := layout.GetCodeValidity(); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds <= 0 {
+			return codeOptions{}, fmt.Errorf("invalid code validity: %q", v)
+		}
+		opts.validity = time.Duration(seconds) * time.Second
+	}
+	return opts, nil
 }
 
 // newFormModel initializes and return a new formModel.
-func newFormModel(label, entryType, buttonLabel string, wait bool) formModel {
+func newFormModel(label, entryType, buttonLabel string, wait bool, code codeOptions, catalog *i18n.Catalog) formModel {
 	var focusableModels []authenticationComponent
 
 	// TODO: add digits and force validation.
@@ -39,11 +79,21 @@ func newFormModel(label, entryType, buttonLabel string, wait bool) formModel {
 		focusableModels = append(focusableModels, button)
 	}
 
+	var codeExpiresAt time.Time
+	if code.validity > 0 {
+		codeExpiresAt = appClock.Now().Add(code.validity)
+	}
+
 	return formModel{
 		label: label,
 		wait:  wait,
 
 		focusableModels: focusableModels,
+
+		code:          code,
+		codeExpiresAt: codeExpiresAt,
+
+		catalog: catalog,
 	}
 }
 
@@ -53,9 +103,21 @@ func (m formModel) Init() tea.Cmd {
 	for _, c := range m.focusableModels {
 		commands = append(commands, c.Init())
 	}
+	commands = append(commands, m.codeCountdown())
 	return tea.Batch(commands...)
 }
 
+// codeCountdown schedules the next refresh of the countdown, until the code expires.
+func (m formModel) codeCountdown() tea.Cmd {
+	if m.codeExpiresAt.IsZero() || !appClock.Now().Before(m.codeExpiresAt) {
+		return nil
+	}
+	expiresAt := m.codeExpiresAt
+	return tick(time.Second, func(time.Time) tea.Msg {
+		return codeCountdownTick{expiresAt: expiresAt}
+	})
+}
+
 // Update handles events and actions.
 func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg.(type) {
@@ -77,6 +139,13 @@ func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
+	case codeCountdownTick:
+		// Ignore the ticks of the countdowns of previous codes.
+		if !msg.expiresAt.Equal(m.codeExpiresAt) {
+			return m, nil
+		}
+		return m, m.codeCountdown()
+
 	// Key presses
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -88,9 +157,7 @@ func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch entry := entry.(type) {
 			case *textinputModel:
 				return m, sendEvent(isAuthenticatedRequested{
-					item: &authd.IARequest_AuthenticationData_Challenge{
-						Challenge: entry.Value(),
-					},
+					secret: newSecret(entry.Value()),
 				})
 			}
 
@@ -109,11 +176,33 @@ func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, cmd
 		}
+
+		// Submit the code as soon as it's complete.
+		previous := m.focusedEntryValue()
+		cmd := m.updateFocusModel(msg)
+		if code := m.focusedEntryValue(); m.code.length > 0 && code != previous && len([]rune(code)) == m.code.length {
+			return m, tea.Sequence(cmd, sendEvent(isAuthenticatedRequested{
+				secret: newSecret(code),
+			}))
+		}
+		return m, cmd
 	}
 
 	return m, m.updateFocusModel(msg)
 }
 
+// focusedEntryValue returns the value of the focused entry, if any.
+func (m formModel) focusedEntryValue() string {
+	if m.focusIndex >= len(m.focusableModels) {
+		return ""
+	}
+	entry, ok := m.focusableModels[m.focusIndex].(*textinputModel)
+	if !ok {
+		return ""
+	}
+	return entry.Value()
+}
+
 func (m *formModel) updateFocusModel(msg tea.Msg) tea.Cmd {
 	if m.focusIndex >= len(m.focusableModels) {
 		return nil
@@ -129,18 +218,31 @@ func (m formModel) View() string {
 	var fields []string
 
 	if m.label != "" {
-		fields = append(fields, m.label)
+		fields = append(fields, renderRichText(m.label))
 	}
 
 	for _, fm := range m.focusableModels {
 		fields = append(fields, fm.View())
 	}
 
+	if !m.codeExpiresAt.IsZero() {
+		fields = append(fields, m.codeCountdownView())
+	}
+
 	return lipgloss.JoinVertical(lipgloss.Left,
 		fields...,
 	)
 }
 
+// codeCountdownView renders the remaining validity of the code.
+func (m formModel) codeCountdownView() string {
+	remaining := m.codeExpiresAt.Sub(appClock.Now()).Round(time.Second)
+	if remaining <= 0 {
+		return m.catalog.G("The code has expired")
+	}
+	return fmt.Sprintf(m.catalog.G("The code expires in %s"), remaining)
+}
+
 // Focus focuses this model.
 func (m formModel) Focus() tea.Cmd {
 	log.Debugf(context.TODO(), "%T: Focus", m)
@@ -9,6 +9,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/msteinert/pam/v2"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/authd/pam/internal/gdm"
@@ -17,13 +18,24 @@ import (
 
 const (
 	gdmPollFrequency time.Duration = time.Millisecond * 16
+
+	// defaultGdmDrainTimeout is how long we wait for the GDM conversations in progress to complete on exit.
+	defaultGdmDrainTimeout = time.Second
 )
 
 type gdmModel struct {
 	pamMTx pam.ModuleTransaction
 
+	// drainTimeout is how long to wait for the conversations in progress to complete on exit.
+	drainTimeout time.Duration
+	// catalog contains the translations of the messages sent to GDM.
+	catalog *i18n.Catalog
+
 	waitingAuth bool
 
+	// unlock is set if GDM is unlocking the screen of an already opened session, as advertised in its hello reply.
+	unlock bool
+
 	// Given the bubbletea async nature we may end up receiving and forwarding
 	// events after we've got a PamReturnStatus and even after the PAM module
 	// has returned to libpam caller (since go goroutines can still be alive).
@@ -41,13 +53,16 @@ type gdmIsAuthenticatedResultReceived isAuthenticatedResultReceived
 
 // Init initializes the main model orchestrator.
 func (m *gdmModel) Init() tea.Cmd {
+	// Conversations may have been stopped by a previous transaction in the same process.
+	gdm.ResumeConversations()
+
 	return tea.Sequence(m.protoHello(),
 		requestUICapabilities(m.pamMTx),
 		m.pollGdm())
 }
 
 func (m *gdmModel) protoHello() tea.Cmd {
-	reply, err := gdm.SendData(m.pamMTx, &gdm.Data{Type: gdm.DataType_hello})
+	reply, err := gdm.SendData(m.pamMTx, &gdm.Data{Type: gdm.DataType_hello, Hello: gdm.NewHelloData()})
 	if err != nil {
 		return sendEvent(pamError{
 			status: pam.ErrCredUnavail,
@@ -67,6 +82,8 @@ func (m *gdmModel) protoHello() tea.Cmd {
 		})
 	}
 	log.Debugf(context.TODO(), "Gdm Reply is %v", reply)
+	gdm.NegotiatePayloadLimits(reply.Hello)
+	m.unlock = reply.Hello.GetUnlock()
 	return nil
 }
 
@@ -107,6 +124,11 @@ func (m *gdmModel) pollGdm() tea.Cmd {
 	for _, result := range gdmPollResults {
 		switch res := result.Data.(type) {
 		case *gdm.EventData_UserSelected:
+			if currentUser, _ := m.pamMTx.GetItem(pam.User); m.waitingAuth &&
+				currentUser != res.UserSelected.UserId {
+				// The user has been switched while authenticating, so the current request is dropped.
+				commands = append(commands, sendEvent(isAuthenticatedCancelled{}))
+			}
 			commands = append(commands, sendUserSelected(res.UserSelected.UserId))
 
 		case *gdm.EventData_BrokerSelected:
@@ -138,13 +160,23 @@ func (m *gdmModel) pollGdm() tea.Cmd {
 					status: pam.ErrSystem, msg: "missing auth requested",
 				})
 			}
-			commands = append(commands, sendEvent(isAuthenticatedRequested{
-				item: res.IsAuthenticatedRequested.GetAuthenticationData().Item,
-			}))
+			commands = append(commands, sendEvent(newIsAuthenticatedRequested(
+				res.IsAuthenticatedRequested.GetAuthenticationData().Item,
+			)))
 
 		case *gdm.EventData_ReselectAuthMode:
 			commands = append(commands, sendEvent(reselectAuthMode{}))
 
+		case *gdm.EventData_NewPasswordChanged:
+			if res.NewPasswordChanged == nil {
+				return sendEvent(pamError{
+					status: pam.ErrSystem, msg: "missing new password changed",
+				})
+			}
+			commands = append(commands, sendEvent(newPasswordStrengthCheck{
+				password: newSecret(res.NewPasswordChanged.Password),
+			}))
+
 		case *gdm.EventData_IsAuthenticatedCancelled:
 			commands = append(commands, sendEvent(isAuthenticatedCancelled{}))
 
@@ -192,7 +224,7 @@ func (m gdmModel) Update(msg tea.Msg) (gdmModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case gdmPollDone:
 		return m, tea.Sequence(
-			tea.Tick(gdmPollFrequency, func(time.Time) tea.Msg { return nil }),
+			tick(gdmPollFrequency, func(time.Time) tea.Msg { return nil }),
 			m.pollGdm())
 
 	case userSelected:
@@ -238,12 +270,30 @@ func (m gdmModel) Update(msg tea.Msg) (gdmModel, tea.Cmd) {
 	case reselectAuthMode:
 		m.waitingAuth = false
 
+	case newPasswordStrengthResult:
+		return m, m.emitEvent(&gdm.EventData_PasswordStrengthEstimated{
+			PasswordStrengthEstimated: &gdm.Events_PasswordStrengthEstimated{
+				Score:    uint32(msg.score),
+				MinScore: uint32(msg.minScore),
+				Label:    msg.score.Label(m.catalog),
+			},
+		})
+
+	case authProgressReceived:
+		return m, m.emitEvent(&gdm.EventData_AuthProgress{
+			AuthProgress: &gdm.Events_AuthProgress{
+				Message:     msg.msg,
+				Percentage:  msg.percentage,
+				Cancellable: msg.cancellable,
+			},
+		})
+
 	case isAuthenticatedResultReceived:
 		return m, sendEvent(gdmIsAuthenticatedResultReceived(msg))
 
 	case gdmIsAuthenticatedResultReceived:
 		access := msg.access
-		authMsg, err := dataToMsg(msg.msg)
+		authMsg, err := dataToMsg(m.catalog, msg.msg)
 		if err != nil {
 			return m, sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
 		}
@@ -309,23 +359,17 @@ func (m gdmModel) changeStage(s proto.Stage) tea.Cmd {
 }
 
 func (m gdmModel) stopConversations() gdmModel {
-	// We're about to exit: let's ensure that all the messages have been processed.
-
-	wait := make(chan struct{})
-	go func() {
-		for {
-			time.Sleep(gdmPollFrequency + 1)
-			if !gdm.ConversationInProgress() {
-				break
-			}
-		}
-		wait <- struct{}{}
-	}()
+	// We're about to exit: let's ensure that all the messages have been processed
+	// and that no new conversation is started afterwards.
+	drainTimeout := m.drainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = defaultGdmDrainTimeout
+	}
 
-	select {
-	case <-wait:
-	case <-time.After(time.Second):
-		log.Error(context.TODO(), "Failed waiting for GDM tasks completion")
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	if err := gdm.StopConversations(ctx); err != nil {
+		log.Errorf(context.TODO(), "Failed waiting for GDM tasks completion: %v", err)
 	}
 
 	m.conversationsStopped = true
@@ -21,6 +21,7 @@ type gdmConvHandler struct {
 	mu           *sync.Mutex
 	t            *testing.T
 	protoVersion uint32
+	unlock       bool
 	convError    map[string]error
 
 	wantRequests        []gdm.RequestType
@@ -122,7 +123,7 @@ func (h *gdmConvHandler) handleGdmData(gdmData *gdm.Data) (*gdm.Data, error) {
 	case gdm.DataType_hello:
 		return &gdm.Data{
 			Type:  gdm.DataType_hello,
-			Hello: &gdm.HelloData{Version: h.protoVersion},
+			Hello: &gdm.HelloData{Version: h.protoVersion, Unlock: &h.unlock},
 		}, nil
 
 	case gdm.DataType_request:
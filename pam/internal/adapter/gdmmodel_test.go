@@ -16,7 +16,9 @@ import (
 	"github.com/msteinert/pam/v2"
 	"github.com/stretchr/testify/require"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/pam/internal/gdm"
 	"github.com/ubuntu/authd/pam/internal/gdm_test"
@@ -27,6 +29,9 @@ import (
 
 var gdmTestPrivateKey *rsa.PrivateKey
 
+// gdmTestRotatedPrivateKey is the key the broker rotates to.
+var gdmTestRotatedPrivateKey *rsa.PrivateKey
+
 const gdmTestIgnoredMessage string = "<ignored>"
 
 func TestGdmModel(t *testing.T) {
@@ -71,6 +76,15 @@ func TestGdmModel(t *testing.T) {
 		}, nil),
 		pam_test.WithUILayout(newPasswordUILayoutID, "New Password form", pam_test.NewPasswordUILayout()),
 	}
+	firstPassClientOptions := append(slices.Clone(singleBrokerClientOptions),
+		pam_test.WithUILayout(passwordUILayoutID, "Password authentication", &authd.UILayout{
+			Type:  layouts.Form,
+			Label: ptrValue("Password"),
+			Entry: ptrValue(entries.CharsPassword),
+		}),
+		pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+		pam_test.WithIsAuthenticatedWantSecret("gdm-good-password"),
+	)
 	multiBrokerClientOptions := append(slices.Clone(singleBrokerClientOptions),
 		pam_test.WithAvailableBrokers([]*authd.ABResponse_BrokerInfo{
 			firstBrokerInfo, secondBrokerInfo,
@@ -85,7 +99,10 @@ func TestGdmModel(t *testing.T) {
 		commands         []tea.Cmd
 		gdmEvents        []*gdm.EventData
 		pamUser          string
+		pamAuthtok       string
+		firstPass        FirstPassPolicy
 		protoVersion     uint32
+		unlock           bool
 		convError        map[string]error
 		timeout          time.Duration
 
@@ -95,6 +112,7 @@ func TestGdmModel(t *testing.T) {
 		wantGdmAuthRes     []*authd.IAResponse
 		wantNoGdmRequests  []gdm.RequestType
 		wantNoGdmEvents    []gdm.EventType
+		wantNoGdmStages    []pam_proto.Stage
 		wantNoBrokers      bool
 		wantSelectedBroker string
 		wantStage          pam_proto.Stage
@@ -226,6 +244,59 @@ func TestGdmModel(t *testing.T) {
 			wantStage:      pam_proto.Stage_challenge,
 			wantExitStatus: gdmTestEarlyStopExitStatus,
 		},
+		"Broker_selection_stage_restarted_on_client-side_user_switch_during_authentication": {
+			gdmEvents: []*gdm.EventData{
+				gdm_test.SelectUserEvent("gdm-selected-user"),
+			},
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_brokerSelection,
+					events: []*gdm.EventData{
+						gdm_test.SelectBrokerEvent(firstBrokerInfo.Id),
+					},
+					commands: []tea.Cmd{
+						sendEvent(gdmTestWaitForStage{
+							stage: pam_proto.Stage_challenge,
+							events: []*gdm.EventData{
+								gdm_test.SelectUserEvent("gdm-switched-user"),
+							},
+							commands: []tea.Cmd{
+								sendEvent(gdmTestWaitForStage{stage: pam_proto.Stage_brokerSelection}),
+							},
+						}),
+					},
+				},
+			},
+			wantMessages: []tea.Msg{
+				userSelected{"gdm-selected-user"},
+				userSelected{"gdm-switched-user"},
+			},
+			wantUsername:       "gdm-switched-user",
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmAuthRes:     []*authd.IAResponse{{Access: auth.Cancelled}},
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> password
+				gdm.RequestType_changeStage, // -> broker Selection for the new user
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModesReceived,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_userSelected,
+			},
+			wantNoGdmEvents: []gdm.EventType{
+				gdm.EventType_authEvent,
+			},
+			wantStage:      pam_proto.Stage_brokerSelection,
+			wantExitStatus: gdmTestEarlyStopExitStatus,
+		},
 		"Authenticated_with_preset_PAM_user_and_server-side_broker_and_authMode_selection": {
 			clientOptions: append(slices.Clone(singleBrokerClientOptions),
 				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
@@ -691,6 +762,307 @@ func TestGdmModel(t *testing.T) {
 			wantStage:      pam_proto.Stage_challenge,
 			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
 		},
+		"Authenticated_with_the_password_of_the_previous_modules_with_use_first_pass": {
+			clientOptions:      firstPassClientOptions,
+			pamUser:            "pam-preset-user-with-first-pass",
+			pamAuthtok:         "gdm-good-password",
+			firstPass:          UseFirstPass,
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+			},
+			wantGdmAuthRes:  []*authd.IAResponse{{Access: auth.Granted}},
+			wantNoGdmStages: []pam_proto.Stage{pam_proto.Stage_challenge},
+			wantStage:       pam_proto.Stage_authModeSelection,
+			wantExitStatus:  PamSuccess{BrokerID: firstBrokerInfo.Id},
+		},
+		"Authenticated_with_the_password_of_the_previous_modules_with_try_first_pass": {
+			clientOptions:      firstPassClientOptions,
+			pamUser:            "pam-preset-user-with-first-pass",
+			pamAuthtok:         "gdm-good-password",
+			firstPass:          TryFirstPass,
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+			},
+			wantGdmAuthRes:  []*authd.IAResponse{{Access: auth.Granted}},
+			wantNoGdmStages: []pam_proto.Stage{pam_proto.Stage_challenge},
+			wantStage:       pam_proto.Stage_authModeSelection,
+			wantExitStatus:  PamSuccess{BrokerID: firstBrokerInfo.Id},
+		},
+		"Authenticated_prompting_for_the_password_if_the_previous_modules_one_is_empty_with_try_first_pass": {
+			clientOptions: firstPassClientOptions,
+			pamUser:       "pam-preset-user-with-first-pass",
+			firstPass:     TryFirstPass,
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Challenge{
+							Challenge: "gdm-good-password",
+						}}),
+					},
+				},
+			},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> password
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantGdmAuthRes: []*authd.IAResponse{{Access: auth.Granted}},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+		},
+		"Authenticated_prompting_for_the_password_if_the_previous_modules_one_is_rejected_with_try_first_pass": {
+			clientOptions: append(slices.Clone(firstPassClientOptions),
+				pam_test.WithIsAuthenticatedMaxRetries(1),
+			),
+			pamUser:    "pam-preset-user-with-first-pass",
+			pamAuthtok: "gdm-bad-password",
+			firstPass:  TryFirstPass,
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Challenge{
+							Challenge: "gdm-good-password",
+						}}),
+					},
+				},
+			},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> password
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantGdmAuthRes: []*authd.IAResponse{
+				{Access: auth.Retry},
+				{Access: auth.Granted},
+			},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+		},
+		"Authenticated_unlocking_with_preset_PAM_user_and_previous_broker_without_selection_stages": {
+			clientOptions: append(slices.Clone(multiBrokerClientOptions),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithIsAuthenticatedWantSecret("gdm-good-password")),
+			pamUser: "pam-preset-user-unlocking-with-previous-broker",
+			unlock:  true,
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Challenge{
+							Challenge: "gdm-good-password",
+						}}),
+					},
+				},
+			},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> password
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantNoGdmStages: []pam_proto.Stage{
+				pam_proto.Stage_brokerSelection,
+				pam_proto.Stage_authModeSelection,
+			},
+			wantGdmAuthRes: []*authd.IAResponse{{Access: auth.Granted}},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+		},
+		"Authenticated_unlocking_with_preset_PAM_user_and_local_PIN": {
+			clientOptions: append(slices.Clone(multiBrokerClientOptions),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithLocalPIN("123456", 3)),
+			pamUser: "pam-preset-user-unlocking-with-local-pin",
+			unlock:  true,
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Challenge{
+							Challenge: "123456",
+						}}),
+					},
+				},
+			},
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> PIN
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantNoGdmEvents: []gdm.EventType{
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+			},
+			wantNoGdmStages: []pam_proto.Stage{
+				pam_proto.Stage_brokerSelection,
+				pam_proto.Stage_authModeSelection,
+			},
+			wantGdmAuthRes: []*authd.IAResponse{{Access: auth.Granted}},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{},
+		},
+		"Authenticated_unlocking_with_preset_PAM_user_and_local_PIN_after_retry": {
+			clientOptions: append(slices.Clone(multiBrokerClientOptions),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithLocalPIN("123456", 3)),
+			pamUser: "pam-preset-user-unlocking-with-local-pin",
+			unlock:  true,
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Challenge{
+							Challenge: "654321",
+						}}),
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Challenge{
+							Challenge: "123456",
+						}}),
+					},
+				},
+			},
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> PIN
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent, // retry
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent, // granted
+			},
+			wantNoGdmEvents: []gdm.EventType{
+				gdm.EventType_brokerSelected,
+			},
+			wantGdmAuthRes: []*authd.IAResponse{
+				{Access: auth.Retry, Msg: "Invalid PIN"},
+				{Access: auth.Granted},
+			},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{},
+		},
+		"Authenticated_unlocking_with_preset_PAM_user_and_previous_broker_after_local_PIN_got_locked": {
+			clientOptions: append(slices.Clone(multiBrokerClientOptions),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithIsAuthenticatedWantSecret("gdm-good-password"),
+				pam_test.WithLocalPIN("123456", 1)),
+			pamUser: "pam-preset-user-unlocking-with-locked-local-pin",
+			unlock:  true,
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Challenge{
+							Challenge: "654321",
+						}}),
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Challenge{
+							Challenge: "gdm-good-password",
+						}}),
+					},
+				},
+			},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent,
+			},
+			wantGdmAuthRes: []*authd.IAResponse{{Access: auth.Granted}},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+		},
+		"Authenticated_with_preset_PAM_user_and_server-side_broker_and_authMode_selection_and_after_encryption_key_rotation": {
+			clientOptions: append(slices.Clone(singleBrokerClientOptions),
+				pam_test.WithGetPreviousBrokerReturn(firstBrokerInfo.Id, nil),
+				pam_test.WithIsAuthenticatedWantSecret("gdm-good-password"),
+				pam_test.WithIsAuthenticatedMaxRetries(1),
+				pam_test.WithEncryptionKeyRotation(gdmTestRotatedPrivateKey, encryption.RSAOAEPSHA256),
+			),
+			pamUser: "pam-preset-user-and-daemon-selected-broker",
+			messages: []tea.Msg{
+				gdmTestWaitForStage{
+					stage: pam_proto.Stage_challenge,
+					commands: []tea.Cmd{
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Challenge{
+							Challenge: "gdm-bad-password",
+						}}),
+						sendEvent(gdmTestSendAuthDataWhenReady{&authd.IARequest_AuthenticationData_Challenge{
+							Challenge: "gdm-good-password",
+						}}),
+					},
+				},
+			},
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+				gdm.RequestType_changeStage, // -> password
+			},
+			wantGdmEvents: []gdm.EventType{
+				gdm.EventType_userSelected,
+				gdm.EventType_brokersReceived,
+				gdm.EventType_brokerSelected,
+				gdm.EventType_authModeSelected,
+				gdm.EventType_uiLayoutReceived,
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent, // retry
+				gdm.EventType_startAuthentication,
+				gdm.EventType_authEvent, // granted
+			},
+			wantMessages: []tea.Msg{
+				startAuthentication{},
+				startAuthentication{},
+			},
+			wantGdmAuthRes: []*authd.IAResponse{
+				{Access: auth.Retry},
+				{Access: auth.Granted},
+			},
+			wantStage:      pam_proto.Stage_challenge,
+			wantExitStatus: PamSuccess{BrokerID: firstBrokerInfo.Id},
+		},
 		"Authenticated_after_client-side_user_and_broker_and_authMode_selection": {
 			clientOptions: append(slices.Clone(multiBrokerClientOptions),
 				pam_test.WithIsAuthenticatedWantSecret("gdm-good-password"),
@@ -1456,6 +1828,44 @@ func TestGdmModel(t *testing.T) {
 		},
 
 		// Error cases
+		"Error_on_empty_password_of_the_previous_modules_with_use_first_pass": {
+			clientOptions:      firstPassClientOptions,
+			pamUser:            "pam-preset-user-with-first-pass",
+			firstPass:          UseFirstPass,
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+			},
+			wantNoGdmStages: []pam_proto.Stage{pam_proto.Stage_challenge},
+			wantStage:       pam_proto.Stage_authModeSelection,
+			wantExitStatus: pamError{
+				status: pam.ErrAuthtokRecovery,
+				msg:    "No password was provided by the previous authentication modules",
+			},
+		},
+		"Error_on_rejected_password_of_the_previous_modules_with_use_first_pass": {
+			clientOptions: append(slices.Clone(firstPassClientOptions),
+				pam_test.WithIsAuthenticatedMaxRetries(1),
+			),
+			pamUser:            "pam-preset-user-with-first-pass",
+			pamAuthtok:         "gdm-bad-password",
+			firstPass:          UseFirstPass,
+			wantSelectedBroker: firstBrokerInfo.Id,
+			wantGdmRequests: []gdm.RequestType{
+				gdm.RequestType_uiLayoutCapabilities,
+				gdm.RequestType_changeStage, // -> broker Selection
+				gdm.RequestType_changeStage, // -> authMode Selection
+			},
+			wantGdmAuthRes:  []*authd.IAResponse{{Access: auth.Retry}},
+			wantNoGdmStages: []pam_proto.Stage{pam_proto.Stage_challenge},
+			wantStage:       pam_proto.Stage_authModeSelection,
+			wantExitStatus: pamError{
+				status: pam.ErrAuth,
+				msg:    "Authentication failure",
+			},
+		},
 		"Error_on_no_UI_layouts": {
 			clientOptions: append(slices.Clone(singleBrokerClientOptions),
 				pam_test.WithUILayout(passwordUILayoutID, "", &authd.UILayout{}),
@@ -2109,7 +2519,8 @@ func TestGdmModel(t *testing.T) {
 		"Error_during_hello_conversation": {
 			convError: map[string]error{
 				gdm_test.DataToJSON(t, &gdm.Data{
-					Type: gdm.DataType_hello,
+					Type:  gdm.DataType_hello,
+					Hello: gdm.NewHelloData(),
 				}): errors.New("this is an hello error"),
 			},
 			wantExitStatus: pamError{
@@ -2227,6 +2638,7 @@ func TestGdmModel(t *testing.T) {
 				t:                    t,
 				mu:                   &gdmMutex,
 				protoVersion:         gdm.ProtoVersion,
+				unlock:               tc.unlock,
 				convError:            tc.convError,
 				currentStageChanged:  *sync.NewCond(&gdmMutex),
 				pendingEventsFlushed: make(chan struct{}),
@@ -2241,8 +2653,13 @@ func TestGdmModel(t *testing.T) {
 			uiModel := UIModel{
 				PamMTx:     pam_test.NewModuleTransactionDummy(gdmHandler),
 				ClientType: Gdm,
+				FirstPass:  tc.firstPass,
 				client:     tc.client,
 			}
+			if tc.firstPass != IgnoreFirstPass {
+				// The password of the previous modules is only used when logging in.
+				uiModel.SessionMode = authd.SessionMode_LOGIN
+			}
 			appState := gdmTestUIModel{
 				UIModel:             uiModel,
 				gdmHandler:          gdmHandler,
@@ -2261,6 +2678,9 @@ func TestGdmModel(t *testing.T) {
 			if tc.pamUser != "" {
 				require.NoError(t, uiModel.PamMTx.SetItem(pam.User, tc.pamUser))
 			}
+			if tc.pamAuthtok != "" {
+				require.NoError(t, uiModel.PamMTx.SetItem(pam.Authtok, tc.pamAuthtok))
+			}
 			if tc.pamUser != "" && tc.wantUsername == "" {
 				tc.wantUsername = tc.pamUser
 			}
@@ -2403,6 +2823,10 @@ func TestGdmModel(t *testing.T) {
 				require.NotContains(t, gdmHandler.handledRequests, req)
 			}
 
+			for _, stage := range tc.wantNoGdmStages {
+				require.NotContains(t, gdmHandler.stageChanges, stage)
+			}
+
 			if tc.wantStage != gdmTestIgnoreStage {
 				require.Equal(t, tc.wantStage, gdmHandler.currentStage,
 					"GDM Stage does not match with expected one")
@@ -2464,6 +2888,10 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		panic(fmt.Sprintf("could not create an valid rsa key: %v", err))
 	}
+	gdmTestRotatedPrivateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(fmt.Sprintf("could not create an valid rsa key: %v", err))
+	}
 	defer pam_test.MaybeDoLeakCheck()
 
 	m.Run()
@@ -3,23 +3,23 @@ package adapter
 
 import (
 	"context"
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/base64"
 	"fmt"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
 	"github.com/ubuntu/authd/internal/consts"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 	pam_proto "github.com/ubuntu/authd/pam/internal/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
-	"google.golang.org/grpc/status"
 )
 
 // PamClientType indicates the type of the PAM client we're handling.
@@ -34,13 +34,27 @@ const (
 	Gdm
 )
 
+// FirstPassPolicy defines how the password collected by the previous modules of the PAM stack is used.
+type FirstPassPolicy int
+
+const (
+	// IgnoreFirstPass always prompts for the password.
+	IgnoreFirstPass FirstPassPolicy = iota
+	// TryFirstPass authenticates with the password collected by the previous modules, prompting for it if there's
+	// none or if it's refused.
+	TryFirstPass
+	// UseFirstPass authenticates with the password collected by the previous modules, failing if there's none or if
+	// it's refused.
+	UseFirstPass
+)
+
 var debug string
 
 // sessionInfo contains the global broker session information.
 type sessionInfo struct {
 	brokerID      string
 	sessionID     string
-	encryptionKey *rsa.PublicKey
+	encryptionKey *encryption.PublicKey
 }
 
 // UIModel is the global models orchestrator.
@@ -53,12 +67,52 @@ type UIModel struct {
 	ClientType PamClientType
 	// SessionMode is the mode of the session invoked by the module.
 	SessionMode authd.SessionMode
+	// PlainPrompts makes the native client use undecorated prompts, friendlier to screen readers.
+	PlainPrompts bool
+	// Silent prevents sending informational messages, as requested via PAM_SILENT.
+	Silent bool
+	// DeviceCodeHelpers copies the device codes to the clipboard and shows their URL as hyperlinks, in the terminal
+	// emulators of graphical sessions.
+	DeviceCodeHelpers bool
+	// BrowserLauncher is the command opening the verification URLs of the link-based authentication modes in the
+	// graphical session of the user, if not empty.
+	BrowserLauncher string
+	// GdmDrainTimeout is how long to wait for the GDM conversations in progress to complete on exit.
+	GdmDrainTimeout time.Duration
+	// DefaultBroker overrides the broker the daemon selects for users which never logged in, if set.
+	DefaultBroker *string
+	// FirstPass defines how the password collected by the previous modules of the PAM stack is used.
+	FirstPass FirstPassPolicy
+	// AllowedAuthModes are the IDs of the only authentication modes the user can select, all of them if empty.
+	AllowedAuthModes []string
+	// SkipBrokerSelection leaves the users who never used any broker to the other PAM modules, instead of asking them
+	// to select one.
+	SkipBrokerSelection bool
+	// SelectBroker asks the users to select their broker on each login, instead of selecting the one they previously
+	// used.
+	SelectBroker bool
+	// Catalog contains the translations for the locale of the PAM transaction.
+	Catalog *i18n.Catalog
 
 	// client is the [authd.PAMClient] handle used to communicate with authd.
 	client authd.PAMClient
 
 	sessionStartingForBroker string
-	currentSession           *sessionInfo
+	// sessionUsername is the user the current (or starting) broker session is for.
+	sessionUsername string
+	currentSession  *sessionInfo
+	// firstPassUsed is set once the password collected by the previous PAM modules has been used, so that it's only
+	// sent once.
+	firstPassUsed bool
+	// unlock is set if the authentication unlocks the screen of an already opened session, so that the broker and
+	// the authentication mode the user previously logged in with are reused without any selection stage.
+	unlock bool
+	// localPINUnlock is set while the user unlocks their session with their local PIN, instead of their broker.
+	localPINUnlock bool
+	// browserLauncher is the browser launcher, if supported by the current client and session.
+	browserLauncher string
+	// openedURL is the last verification URL opened in the browser, so that it's not opened again on retries.
+	openedURL string
 
 	healthCheckCancel      func()
 	userSelectionModel     userSelectionModel
@@ -86,9 +140,11 @@ type BrokerSelected struct {
 
 // SessionStarted signals that we started a session with a given broker.
 type SessionStarted struct {
-	brokerID      string
-	sessionID     string
-	encryptionKey string
+	brokerID            string
+	username            string
+	sessionID           string
+	encryptionKey       string
+	encryptionAlgorithm string
 }
 
 // GetAuthenticationModesRequested signals that a model needs to get the broker authentication modes.
@@ -122,14 +178,21 @@ func (m *UIModel) Init() tea.Cmd {
 
 	switch m.ClientType {
 	case Gdm:
-		m.gdmModel = gdmModel{pamMTx: m.PamMTx}
+		m.gdmModel = gdmModel{pamMTx: m.PamMTx, drainTimeout: m.GdmDrainTimeout, catalog: m.Catalog}
 		cmds = append(cmds, m.gdmModel.Init())
+		m.unlock = m.gdmModel.unlock
 	case Native:
 		var nssClient authd.NSSClient
 		if m.Conn != nil && isSSHSession(m.PamMTx) {
 			nssClient = authd.NewNSSClient(m.Conn)
 		}
-		m.nativeModel = nativeModel{pamMTx: m.PamMTx, nssClient: nssClient}
+		m.nativeModel = nativeModel{
+			pamMTx:       m.PamMTx,
+			nssClient:    nssClient,
+			plainPrompts: m.PlainPrompts,
+			silent:       m.Silent,
+			catalog:      m.Catalog,
+		}
 		cmds = append(cmds, m.nativeModel.Init())
 	}
 
@@ -140,18 +203,24 @@ func (m *UIModel) Init() tea.Cmd {
 		})
 	}
 
-	m.userSelectionModel = newUserSelectionModel(m.PamMTx, m.ClientType)
+	m.userSelectionModel = newUserSelectionModel(m.PamMTx, m.ClientType, m.Catalog)
 	cmds = append(cmds, m.userSelectionModel.Init())
 
-	m.brokerSelectionModel = newBrokerSelectionModel(m.client, m.ClientType)
+	m.brokerSelectionModel = newBrokerSelectionModel(m.client, m.ClientType, m.Catalog)
 	cmds = append(cmds, m.brokerSelectionModel.Init())
 
-	m.authModeSelectionModel = newAuthModeSelectionModel(m.ClientType)
+	m.authModeSelectionModel = newAuthModeSelectionModel(m.ClientType, m.Catalog)
 	cmds = append(cmds, m.authModeSelectionModel.Init())
 
-	m.authenticationModel = newAuthenticationModel(m.client, m.ClientType)
+	m.authenticationModel = newAuthenticationModel(m.client, m.ClientType, m.Catalog)
+	m.authenticationModel.deviceCodeHelpers = m.DeviceCodeHelpers && m.ClientType == InteractiveTerminal &&
+		deviceCodeHelpersSupported(m.PamMTx)
 	cmds = append(cmds, m.authenticationModel.Init())
 
+	if m.BrowserLauncher != "" && browserLaunchSupported(m.PamMTx, m.ClientType) {
+		m.browserLauncher = m.BrowserLauncher
+	}
+
 	m.healthCheckCancel = func() {}
 	cmds = append(cmds, m.startHealthCheck())
 
@@ -171,7 +240,7 @@ func (m *UIModel) startHealthCheck() tea.Cmd {
 	return func() tea.Msg {
 		for {
 			r, err := healthClient.Check(ctx, hcReq)
-			if status.Convert(err).Code() == codes.Canceled {
+			if authderrors.Is(err, authderrors.Canceled) {
 				return nil
 			}
 			if err != nil {
@@ -188,7 +257,7 @@ func (m *UIModel) startHealthCheck() tea.Cmd {
 				}
 			}
 
-			<-time.After(500 * time.Millisecond)
+			<-appClock.After(500 * time.Millisecond)
 		}
 	}
 }
@@ -201,7 +270,7 @@ func (m *UIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if log.IsLevelEnabled(log.DebugLevel) {
 			debugKey := m.currentStage() != pam_proto.Stage_challenge
 			switch msg.String() {
-			case "enter", "ctrl+c", "esc", "tab", "shift+tab":
+			case "enter", "ctrl+c", "ctrl+r", "esc", "tab", "shift+tab":
 				debugKey = true
 			}
 			if debugKey {
@@ -215,6 +284,15 @@ func (m *UIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				status: pam.ErrAbort,
 				msg:    "cancel requested",
 			})
+		case "ctrl+r":
+			// Let the user switch to another broker than the one selected automatically.
+			if m.ClientType != InteractiveTerminal {
+				break
+			}
+			switch m.currentStage() {
+			case pam_proto.Stage_authModeSelection, pam_proto.Stage_challenge:
+				return m, m.changeStage(pam_proto.Stage_brokerSelection)
+			}
 		case "esc":
 			if m.brokerSelectionModel.WillCaptureEscape() || m.authModeSelectionModel.WillCaptureEscape() {
 				break
@@ -257,51 +335,63 @@ func (m *UIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.sessionUsername != "" && m.sessionUsername != m.username() {
+			// The user has been changed while an authentication was in progress (as it happens when
+			// switching user at the GDM greeter), so we restart from the broker selection for the new one.
+			return m, m.switchUser()
+		}
+
+		if m.unlock {
+			service, _ := m.PamMTx.GetItem(pam.Service)
+			return m, unlockForUser(m.client, m.username(), service, m.DefaultBroker)
+		}
+
 		// Got user and brokers? Time to auto or manually select.
-		return m, AutoSelectForUser(m.client, m.username())
+		if m.SelectBroker {
+			return m, ForceSelectForUser(m.client, m.username(), m.DefaultBroker)
+		}
+		return m, AutoSelectForUser(m.client, m.username(), m.DefaultBroker)
 
 	case BrokerSelected:
 		log.Debugf(context.TODO(), "%#v", msg)
 		if m.sessionStartingForBroker == "" {
 			m.sessionStartingForBroker = msg.BrokerID
-			return m, startBrokerSession(m.client, msg.BrokerID, m.username(), m.SessionMode)
+			m.sessionUsername = m.username()
+			service, _ := m.PamMTx.GetItem(pam.Service)
+			tty, _ := m.PamMTx.GetItem(pam.Tty)
+			return m, startBrokerSession(m.client, msg.BrokerID, m.username(), PamLocale(m.PamMTx), service, tty, m.SessionMode)
 		}
 		if m.sessionStartingForBroker != msg.BrokerID {
 			return m, tea.Sequence(endSession(m.client, m.currentSession), sendEvent(msg))
 		}
 	case SessionStarted:
 		log.Debugf(context.TODO(), "%#v", msg)
-		m.sessionStartingForBroker = ""
-		pubASN1, err := base64.StdEncoding.DecodeString(msg.encryptionKey)
-		if err != nil {
-			return m, sendEvent(pamError{
-				status: pam.ErrSystem,
-				msg:    fmt.Sprintf("encryption key sent by broker is not a valid base64 encoded string: %v", err),
-			})
+		if msg.username != m.sessionUsername {
+			// The user has been changed while this session was starting.
+			log.Infof(context.TODO(), "Ending session %q started for previous user %q", msg.sessionID, msg.username)
+			return m, endStaleSession(m.client, msg.sessionID)
 		}
-
-		pubKey, err := x509.ParsePKIXPublicKey(pubASN1)
+		m.sessionStartingForBroker = ""
+		encryptionKey, err := encryption.NewPublicKey(msg.encryptionAlgorithm, msg.encryptionKey)
 		if err != nil {
-			return m, sendEvent(pamError{
-				status: pam.ErrSystem,
-				msg:    fmt.Sprintf("encryption key send by broker is not valid: %v", err),
-			})
-		}
-		rsaPublicKey, ok := pubKey.(*rsa.PublicKey)
-		if !ok {
-			return m, sendEvent(pamError{
-				status: pam.ErrSystem,
-				msg:    fmt.Sprintf("expected encryption key sent by broker to be  RSA public key, got %T", pubKey),
-			})
+			return m, sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
 		}
 
 		m.currentSession = &sessionInfo{
 			brokerID:      msg.brokerID,
 			sessionID:     msg.sessionID,
-			encryptionKey: rsaPublicKey,
+			encryptionKey: encryptionKey,
 		}
+		m.authenticationModel.ResetSteps()
 		return m, sendEvent(GetAuthenticationModesRequested{})
 
+	case brokerSelectionRequired:
+		if !m.SkipBrokerSelection || msg.forced {
+			break
+		}
+		log.Infof(context.TODO(), "Broker selection is skipped, leaving user %q to the other PAM modules", m.username())
+		return m, sendEvent(pamError{status: pam.ErrIgnore})
+
 	case ChangeStage:
 		log.Debugf(context.TODO(), "%#v", msg)
 		return m, m.changeStage(msg.Stage)
@@ -312,8 +402,13 @@ func (m *UIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.unlock {
+			// The authentication mode the user last logged in with is selected without showing the selection.
+			return m, getAuthenticationModes(m.client, m.currentSession.sessionID, m.authModeSelectionModel.SupportedUILayouts(), m.AllowedAuthModes)
+		}
+
 		return m, tea.Sequence(
-			getAuthenticationModes(m.client, m.currentSession.sessionID, m.authModeSelectionModel.SupportedUILayouts()),
+			getAuthenticationModes(m.client, m.currentSession.sessionID, m.authModeSelectionModel.SupportedUILayouts(), m.AllowedAuthModes),
 			m.changeStage(pam_proto.Stage_authModeSelection),
 		)
 
@@ -342,22 +437,81 @@ func (m *UIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.currentSession == nil {
 			return m, nil
 		}
+		msg.layout = sanitizeLayout(msg.layout)
+
+		if msg.layout.GetCachedCredentials() == layouts.True {
+			// The broker can authenticate the user without any interaction, so we don't show the challenge at all.
+			return m, m.authenticationModel.AuthenticateWithCachedCredentials(
+				m.currentSession.brokerID,
+				m.currentSession.sessionID,
+				m.currentSession.encryptionKey,
+				m.authModeSelectionModel.currentAuthModeLabel(),
+				msg.layout,
+			)
+		}
+		if cmd := m.authenticateWithFirstPass(msg.layout); cmd != nil {
+			return m, cmd
+		}
 
 		return m, tea.Sequence(
 			m.authenticationModel.Compose(
 				m.currentSession.brokerID,
 				m.currentSession.sessionID,
 				m.currentSession.encryptionKey,
+				m.authModeSelectionModel.currentAuthModeLabel(),
 				msg.layout,
 			),
 			m.updateClientModel(msg),
+			m.openVerificationURL(msg.layout),
 		)
 
 	case SessionEnded:
 		log.Debugf(context.TODO(), "%#v", msg)
 		m.sessionStartingForBroker = ""
+		m.sessionUsername = ""
 		m.currentSession = nil
+		m.authenticationModel.ResetSteps()
 		return m, nil
+
+	case localPINRequested:
+		log.Debugf(context.TODO(), "%#v", msg)
+		m.localPINUnlock = true
+		return m, tea.Sequence(
+			m.updateClientModel(UILayoutReceived{layout: localPINLayout(m.Catalog)}),
+			m.changeStage(pam_proto.Stage_challenge),
+			sendEvent(startAuthentication{}),
+		)
+
+	case localPINLocked:
+		log.Debugf(context.TODO(), "%#v", msg)
+		m.localPINUnlock = false
+		return m, tea.Sequence(
+			m.showProgressMessage(msg.msg),
+			AutoSelectForUser(m.client, m.username(), m.DefaultBroker),
+		)
+
+	case isAuthenticatedRequested:
+		if !m.localPINUnlock {
+			break
+		}
+		log.Debugf(context.TODO(), "%#v", msg)
+		if msg.secret.isEmpty() {
+			// An empty PIN unlocks with the broker the user previously logged in with.
+			m.localPINUnlock = false
+			return m, AutoSelectForUser(m.client, m.username(), m.DefaultBroker)
+		}
+		service, _ := m.PamMTx.GetItem(pam.Service)
+		return m, authenticateWithLocalPIN(m.client, m.username(), service, msg.secret)
+
+	case isAuthenticatedResultReceived:
+		if msg.encryptionKey != nil && m.currentSession != nil {
+			log.Debugf(context.TODO(), "Broker rotated the encryption key of session %q", m.currentSession.sessionID)
+			m.currentSession.encryptionKey = msg.encryptionKey
+		}
+
+	case authenticationSlotWaiting:
+		log.Debugf(context.TODO(), "%#v", msg)
+		return m, m.showProgressMessage(m.Catalog.G("Waiting for an available authentication slot..."))
 	}
 
 	var cmd tea.Cmd
@@ -401,8 +555,10 @@ func (m *UIModel) View() string {
 	case pam_proto.Stage_brokerSelection:
 		view.WriteString(m.brokerSelectionModel.View())
 	case pam_proto.Stage_authModeSelection:
+		view.WriteString(m.authenticationModel.StepsView())
 		view.WriteString(m.authModeSelectionModel.View())
 	case pam_proto.Stage_challenge:
+		view.WriteString(m.authenticationModel.StepsView())
 		view.WriteString(m.authenticationModel.View())
 	default:
 		view.WriteString("INVALID STAGE")
@@ -463,7 +619,9 @@ func (m *UIModel) changeStage(s pam_proto.Stage) tea.Cmd {
 		commands = append(commands, endSession(m.client, m.currentSession), m.userSelectionModel.Focus())
 
 	case pam_proto.Stage_brokerSelection:
+		// Going back to the broker selection cancels any multi-factor authentication in progress.
 		m.authModeSelectionModel.Reset()
+		m.authenticationModel.ResetSteps()
 		commands = append(commands, endSession(m.client, m.currentSession), m.brokerSelectionModel.Focus())
 
 	case pam_proto.Stage_authModeSelection:
@@ -483,6 +641,44 @@ func (m *UIModel) changeStage(s pam_proto.Stage) tea.Cmd {
 	return tea.Sequence(commands...)
 }
 
+// showProgressMessage shows an informational message about the progress of a request, unless silent.
+func (m *UIModel) showProgressMessage(msg string) tea.Cmd {
+	if m.Silent {
+		return nil
+	}
+	if m.ClientType == InteractiveTerminal {
+		return sendEvent(errMsgToDisplay{msg: msg})
+	}
+	return func() tea.Msg {
+		if _, err := m.PamMTx.StartStringConv(pam.TextInfo, msg); err != nil {
+			log.Warningf(context.TODO(), "Impossible to send PAM message: %v", err)
+		}
+		return nil
+	}
+}
+
+// switchUser drops any authentication in progress for the previous user and restarts the broker selection
+// for the newly selected one.
+func (m *UIModel) switchUser() tea.Cmd {
+	log.Infof(context.TODO(), "User changed from %q to %q during authentication, restarting it",
+		m.sessionUsername, m.username())
+
+	var sessionID string
+	if m.currentSession != nil {
+		sessionID = m.currentSession.sessionID
+	}
+	m.sessionStartingForBroker = ""
+	m.sessionUsername = ""
+	m.currentSession = nil
+
+	return tea.Sequence(
+		m.authenticationModel.Reset(),
+		endStaleSession(m.client, sessionID),
+		m.changeStage(pam_proto.Stage_brokerSelection),
+		AutoSelectForUser(m.client, m.username(), m.DefaultBroker),
+	)
+}
+
 // MsgFilter is the handler for the UI model.
 func (m *UIModel) MsgFilter(model tea.Model, msg tea.Msg) tea.Msg {
 	if m.ClientType != Gdm {
@@ -516,3 +712,40 @@ func (m UIModel) username() string {
 func (m UIModel) availableBrokers() []*authd.ABResponse_BrokerInfo {
 	return m.brokerSelectionModel.availableBrokers
 }
+
+// authenticateWithFirstPass returns the command authenticating with the password collected by the previous modules of
+// the PAM stack, if it has to be used for the layout, or nil otherwise.
+func (m *UIModel) authenticateWithFirstPass(layout *authd.UILayout) tea.Cmd {
+	if m.FirstPass == IgnoreFirstPass || m.firstPassUsed || m.SessionMode != authd.SessionMode_LOGIN {
+		return nil
+	}
+	if layout.GetType() != layouts.Form || layout.GetEntry() != entries.CharsPassword {
+		return nil
+	}
+	m.firstPassUsed = true
+
+	password, err := m.PamMTx.GetItem(pam.Authtok)
+	if err != nil {
+		log.Warningf(context.TODO(), "Could not get the password of the previous PAM modules: %v", err)
+	}
+	if password == "" {
+		if m.FirstPass == UseFirstPass {
+			return sendEvent(pamError{
+				status: pam.ErrAuthtokRecovery,
+				msg:    m.Catalog.G("No password was provided by the previous authentication modules"),
+			})
+		}
+		return nil
+	}
+
+	log.Debugf(context.TODO(), "Authenticating with the password of the previous PAM modules")
+	return m.authenticationModel.AuthenticateWithFirstPass(
+		m.currentSession.brokerID,
+		m.currentSession.sessionID,
+		m.currentSession.encryptionKey,
+		m.authModeSelectionModel.currentAuthModeLabel(),
+		layout,
+		newSecret(password),
+		m.FirstPass == UseFirstPass,
+	)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/ubuntu/authd/internal/brokers/auth"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
 	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/authd/pam/internal/proto"
@@ -31,12 +32,18 @@ type nativeModel struct {
 	authModes        []*authd.GAMResponse_AuthenticationMode
 	selectedAuthMode string
 	uiLayout         *authd.UILayout
+	// authProgressMsg is the last progress message reported by the broker for the authentication in progress.
+	authProgressMsg string
 
 	serviceName          string
 	interactive          bool
+	plainPrompts         bool
+	silent               bool
 	currentStage         proto.Stage
 	busy                 bool
 	userSelectionAllowed bool
+
+	catalog *i18n.Catalog
 }
 
 const (
@@ -100,34 +107,62 @@ func (m *nativeModel) Init() tea.Cmd {
 			entries.Digits,
 			entries.DigitsPassword,
 		)
+		smartCardActions := layouts.RequiredItems(
+			layouts.SmartCardInsert,
+			layouts.SmartCardPIN,
+			layouts.SmartCardTouch,
+		)
+
+		minStrengths := layouts.OptionalItems("0", "1", "2", "3", "4")
 
 		supportedLayouts := supportedUILayoutsReceived{
 			layouts: []*authd.UILayout{
 				{
-					Type:   layouts.Form,
-					Label:  &required,
-					Entry:  &supportedEntries,
-					Wait:   &layouts.OptionalWithBooleans,
-					Button: &optional,
+					Type:         layouts.Form,
+					Label:        &required,
+					Entry:        &supportedEntries,
+					Wait:         &layouts.OptionalWithBooleans,
+					Button:       &optional,
+					CodeLength:   &optional,
+					CodeValidity: &optional,
+					// This is handled by the authentication model, without showing the form.
+					CachedCredentials: &layouts.OptionalWithBooleans,
+				},
+				{
+					Type:        layouts.NewPassword,
+					Label:       &required,
+					Entry:       &supportedEntries,
+					Button:      &optional,
+					MinStrength: &minStrengths,
+				},
+				{
+					Type:              layouts.SmartCard,
+					SmartcardAction:   &smartCardActions,
+					Label:             &optional,
+					Content:           &optional,
+					Entry:             &supportedEntries,
+					Wait:              &layouts.OptionalWithBooleans,
+					Button:            &optional,
+					CachedCredentials: &layouts.OptionalWithBooleans,
 				},
 				{
-					Type:   layouts.NewPassword,
-					Label:  &required,
-					Entry:  &supportedEntries,
-					Button: &optional,
+					Type:    layouts.Consent,
+					Label:   &optional,
+					Content: &required,
 				},
 			},
 		}
 
 		if supportsQrCode {
 			supportedLayouts.layouts = append(supportedLayouts.layouts, &authd.UILayout{
-				Type:          layouts.QrCode,
-				Content:       &required,
-				Code:          &optional,
-				Wait:          &layouts.RequiredWithBooleans,
-				Label:         &optional,
-				Button:        &optional,
-				RendersQrcode: &rendersQrCode,
+				Type:              layouts.QrCode,
+				Content:           &required,
+				Code:              &optional,
+				Wait:              &layouts.RequiredWithBooleans,
+				Label:             &optional,
+				Button:            &optional,
+				RendersQrcode:     &rendersQrCode,
+				CachedCredentials: &layouts.OptionalWithBooleans,
 			})
 		}
 
@@ -240,7 +275,7 @@ func (m nativeModel) Update(msg tea.Msg) (nativeModel, tea.Cmd) {
 		if len(m.availableBrokers) < 1 {
 			return m, sendEvent(pamError{
 				status: pam.ErrSystem,
-				msg:    "No brokers available to select",
+				msg:    m.catalog.G("No brokers available to select"),
 			})
 		}
 
@@ -266,7 +301,7 @@ func (m nativeModel) Update(msg tea.Msg) (nativeModel, tea.Cmd) {
 		if len(m.authModes) < 1 {
 			return m, sendEvent(pamError{
 				status: pam.ErrSystem,
-				msg:    "Can't authenticate without authentication modes",
+				msg:    m.catalog.G("Can't authenticate without authentication modes"),
 			})
 		}
 
@@ -280,7 +315,8 @@ func (m nativeModel) Update(msg tea.Msg) (nativeModel, tea.Cmd) {
 		m.selectedAuthMode = msg.id
 
 	case UILayoutReceived:
-		m.uiLayout = msg.layout
+		// The prompts can't be styled.
+		m.uiLayout = plainLayout(msg.layout)
 
 	case startAuthentication:
 		return m, m.requestStageChange(pam_proto.Stage_challenge)
@@ -298,21 +334,31 @@ func (m nativeModel) Update(msg tea.Msg) (nativeModel, tea.Cmd) {
 			}
 			return m, m.newPasswordChallenge(nil)
 		}
-		return m, m.newPasswordChallenge(&msg.password)
+		return m, m.newPasswordChallenge(msg.password)
+
+	case authProgressReceived:
+		// Only the message is shown, as the prompts can't be updated in place.
+		if msg.msg == m.authProgressMsg {
+			return m, nil
+		}
+		m.authProgressMsg = msg.msg
+		return m, maybeSendPamError(m.sendInfo(plainText(msg.msg)))
 
 	case isAuthenticatedResultReceived:
+		m.authProgressMsg = ""
 		access := msg.access
-		authMsg, err := dataToMsg(msg.msg)
+		authMsg, err := dataToMsg(m.catalog, msg.msg)
 		if cmd := maybeSendPamError(err); cmd != nil {
 			return m, cmd
 		}
+		authMsg = plainText(authMsg)
 
 		switch access {
 		case auth.Granted:
-			return m, maybeSendPamError(m.sendInfo(authMsg))
+			return m, maybeSendPamError(m.sendNotice(authMsg))
 		case auth.Next:
 			m.uiLayout = nil
-			return m, maybeSendPamError(m.sendInfo(authMsg))
+			return m, maybeSendPamError(m.sendNotice(authMsg))
 		case auth.Retry:
 			return m, maybeSendPamError(m.sendError(authMsg))
 		case auth.Denied:
@@ -321,7 +367,7 @@ func (m nativeModel) Update(msg tea.Msg) (nativeModel, tea.Cmd) {
 		case auth.Cancelled:
 			return m, sendEvent(isAuthenticatedCancelled{})
 		default:
-			return m, maybeSendPamError(m.sendError("Access %q is not valid", access))
+			return m, maybeSendPamError(m.sendError(m.catalog.G("Access %q is not valid"), access))
 		}
 
 	case isAuthenticatedCancelled:
@@ -345,6 +391,9 @@ func (m nativeModel) checkForPromptReplyValidity(reply string) error {
 
 func (m nativeModel) promptForInput(style pam.Style, inputStyle inputPromptStyle, prompt string) (string, error) {
 	format := "%s"
+	if m.plainPrompts {
+		inputStyle = inputPromptStyleInline
+	}
 	if m.interactive {
 		switch inputStyle {
 		case inputPromptStyleInline:
@@ -381,7 +430,7 @@ func (m nativeModel) promptForNumericInputUntilValid(style pam.Style, prompt str
 		return value, err
 	}
 
-	err = m.sendError("Unsupported input")
+	err = m.sendError(m.catalog.G("Unsupported input"))
 	if err != nil {
 		return -1, err
 	}
@@ -410,26 +459,40 @@ func (m nativeModel) sendInfo(infoMsg string, args ...any) error {
 	return err
 }
 
+// sendNotice sends an informational message that is not needed to proceed, so it's
+// not sent when we're requested to be silent.
+func (m nativeModel) sendNotice(infoMsg string, args ...any) error {
+	if m.silent {
+		return nil
+	}
+	return m.sendInfo(infoMsg, args...)
+}
+
 type choicePair struct {
 	id    string
 	label string
 }
 
 func (m nativeModel) promptForChoiceWithMessage(title string, message string, choices []choicePair, prompt string) (string, error) {
-	msg := fmt.Sprintf("== %s ==\n", title)
+	titleFormat, choiceFormat := "== %s ==\n", "  %d. %s"
+	if m.plainPrompts {
+		titleFormat, choiceFormat = "%s\n", "%d. %s"
+	}
+
+	msg := fmt.Sprintf(titleFormat, title)
 	if message != "" {
 		msg += message + "\n"
 	}
 
 	for i, choice := range choices {
-		msg += fmt.Sprintf("  %d. %s", i+1, choice.label)
+		msg += fmt.Sprintf(choiceFormat, i+1, choice.label)
 		if i < len(choices)-1 {
 			msg += "\n"
 		}
 	}
 
 	if m.canGoBack() {
-		msg += fmt.Sprintf("\nOr enter '%s' to %s", nativeCancelKey,
+		msg += "\n" + fmt.Sprintf(m.catalog.G("Or enter '%s' to %s"), nativeCancelKey,
 			m.goBackActionLabel())
 	}
 
@@ -444,7 +507,7 @@ func (m nativeModel) promptForChoiceWithMessage(title string, message string, ch
 		// TODO: Maybe add support for default selection...
 
 		if idx < 1 || idx > len(choices) {
-			if err := m.sendError("Invalid selection"); err != nil {
+			if err := m.sendError(m.catalog.G("Invalid selection")); err != nil {
 				return "", err
 			}
 			continue
@@ -470,7 +533,7 @@ func (m nativeModel) startAsyncOp(cmd func() tea.Cmd) (nativeModel, tea.Cmd) {
 }
 
 func (m nativeModel) userSelection() tea.Cmd {
-	user, err := m.promptForInput(pam.PromptEchoOn, inputPromptStyleInline, "Username")
+	user, err := m.promptForInput(pam.PromptEchoOn, inputPromptStyleInline, m.catalog.G("Username"))
 	if errors.Is(err, errEmptyResponse) {
 		return sendEvent(nativeUserSelection{})
 	}
@@ -507,14 +570,14 @@ func (m nativeModel) brokerSelection() tea.Cmd {
 		choices = append(choices, choicePair{id: b.Id, label: b.Name})
 	}
 
-	id, err := m.promptForChoice("Provider selection", choices, "Choose your provider")
+	id, err := m.promptForChoice(m.catalog.G("Provider selection"), choices, m.catalog.G("Choose your provider"))
 	if errors.Is(err, errGoBack) {
 		return sendEvent(nativeGoBack{})
 	}
 	if err != nil {
 		return sendEvent(pamError{
 			status: pam.ErrSystem,
-			msg:    fmt.Sprintf("Provider selection error: %v", err),
+			msg:    fmt.Sprintf(m.catalog.G("Provider selection error: %v"), err),
 		})
 	}
 	return sendEvent(brokerSelected{brokerID: id})
@@ -526,8 +589,8 @@ func (m nativeModel) authModeSelection() tea.Cmd {
 		choices = append(choices, choicePair{id: am.Id, label: am.Label})
 	}
 
-	id, err := m.promptForChoice("Authentication method selection", choices,
-		"Choose your authentication method")
+	id, err := m.promptForChoice(m.catalog.G("Authentication method selection"), choices,
+		m.catalog.G("Choose your authentication method"))
 	if errors.Is(err, errGoBack) {
 		return sendEvent(nativeGoBack{})
 	}
@@ -537,7 +600,7 @@ func (m nativeModel) authModeSelection() tea.Cmd {
 	if err != nil {
 		return sendEvent(pamError{
 			status: pam.ErrSystem,
-			msg:    fmt.Sprintf("Authentication method selection error: %v", err),
+			msg:    fmt.Sprintf(m.catalog.G("Authentication method selection error: %v"), err),
 		})
 	}
 
@@ -548,7 +611,7 @@ func (m nativeModel) startChallenge() tea.Cmd {
 	if m.uiLayout == nil {
 		return sendEvent(pamError{
 			status: pam.ErrSystem,
-			msg:    "Can't authenticate without ui layout selected",
+			msg:    m.catalog.G("Can't authenticate without ui layout selected"),
 		})
 	}
 
@@ -562,7 +625,7 @@ func (m nativeModel) startChallenge() tea.Cmd {
 		if !hasWait {
 			return sendEvent(pamError{
 				status: pam.ErrSystem,
-				msg:    "Can't handle qrcode without waiting",
+				msg:    m.catalog.G("Can't handle qrcode without waiting"),
 			})
 		}
 		return m.handleQrCode()
@@ -570,10 +633,16 @@ func (m nativeModel) startChallenge() tea.Cmd {
 	case layouts.NewPassword:
 		return m.handleNewPassword()
 
+	case layouts.SmartCard:
+		return m.handleSmartCard(hasWait)
+
+	case layouts.Consent:
+		return m.handleConsent()
+
 	default:
 		return sendEvent(pamError{
 			status: pam.ErrSystem,
-			msg:    fmt.Sprintf("Unknown layout type: %q", m.uiLayout.Type),
+			msg:    fmt.Sprintf(m.catalog.G("Unknown layout type: %q"), m.uiLayout.Type),
 		})
 	}
 }
@@ -589,17 +658,17 @@ func (m nativeModel) selectedAuthModeLabel(fallback string) string {
 }
 
 func (m nativeModel) handleFormChallenge(hasWait bool) tea.Cmd {
-	authMode := m.selectedAuthModeLabel("Authentication")
+	authMode := m.selectedAuthModeLabel(m.catalog.G("Authentication"))
 
 	if buttonLabel := m.uiLayout.GetButton(); buttonLabel != "" {
 		choices := []choicePair{
-			{id: "continue", label: fmt.Sprintf("Proceed with %s", authMode)},
+			{id: "continue", label: fmt.Sprintf(m.catalog.G("Proceed with %s"), authMode)},
 		}
 		if buttonLabel := m.uiLayout.GetButton(); buttonLabel != "" {
 			choices = append(choices, choicePair{id: layouts.Button, label: buttonLabel})
 		}
 
-		id, err := m.promptForChoice(authMode, choices, "Choose action")
+		id, err := m.promptForChoice(authMode, choices, m.catalog.G("Choose action"))
 		if errors.Is(err, errGoBack) {
 			return sendEvent(nativeGoBack{})
 		}
@@ -621,23 +690,35 @@ func (m nativeModel) handleFormChallenge(hasWait bool) tea.Cmd {
 	if prompt == "" {
 		return sendEvent(pamError{
 			status: pam.ErrSystem,
-			msg:    fmt.Sprintf("No label provided for entry %q", m.uiLayout.GetEntry()),
+			msg:    fmt.Sprintf(m.catalog.G("No label provided for entry %q"), m.uiLayout.GetEntry()),
 		})
 	}
 
-	instructions := "Enter '%[1]s' to cancel the request and %[2]s"
+	instructions := m.catalog.G("Enter '%[1]s' to cancel the request and %[2]s")
 	if hasWait {
-		// Duplicating some contents here, as it will be better for translators once we've them
-		instructions = "Leave the input field empty to wait for the alternative authentication method " +
-			"or enter '%[1]s' to %[2]s"
+		// Duplicating some contents here, as it is better for translators.
+		instructions = m.catalog.G("Leave the input field empty to wait for the alternative authentication method or enter '%[1]s' to %[2]s")
 		if m.uiLayout.GetEntry() == "" {
-			instructions = "Press Enter to wait for authentication " +
-				"or enter '%[1]s' to %[2]s"
+			instructions = m.catalog.G("Press Enter to wait for authentication or enter '%[1]s' to %[2]s")
 		}
 	}
 
 	instructions = fmt.Sprintf(instructions, nativeCancelKey, m.goBackActionLabel())
-	if cmd := maybeSendPamError(m.sendInfo("== %s ==\n%s", authMode, instructions)); cmd != nil {
+	code, err := parseCodeOptions(m.uiLayout)
+	if err != nil {
+		return sendEvent(pamError{status: pam.ErrSystem, msg: err.Error()})
+	}
+	if code.validity > 0 {
+		// We can't show a countdown with the PAM conversation, so we only tell how long the code is valid for.
+		instructions += "\n" + fmt.Sprintf(m.catalog.G("The code expires in %s"), code.validity)
+	}
+	sendInstructions := m.sendNotice
+	if hasWait {
+		// The user can't know how to wait for the authentication without the instructions,
+		// so they are needed even when we're requested to be silent.
+		sendInstructions = m.sendInfo
+	}
+	if cmd := maybeSendPamError(sendInstructions("== %s ==\n%s", authMode, instructions)); cmd != nil {
 		return cmd
 	}
 
@@ -656,7 +737,7 @@ func (m nativeModel) handleFormChallenge(hasWait bool) tea.Cmd {
 	}
 
 	return sendEvent(isAuthenticatedRequested{
-		item: &authd.IARequest_AuthenticationData_Challenge{Challenge: secret},
+		secret: newSecret(secret),
 	})
 }
 
@@ -671,7 +752,7 @@ func (m nativeModel) promptForSecret(prompt string) (string, error) {
 	case entries.DigitsPassword:
 		return m.promptForNumericInputAsString(pam.PromptEchoOff, prompt)
 	default:
-		return "", fmt.Errorf("Unhandled entry %q", m.uiLayout.GetEntry())
+		return "", fmt.Errorf(m.catalog.G("Unhandled entry %q"), m.uiLayout.GetEntry())
 	}
 }
 
@@ -695,7 +776,7 @@ func (m nativeModel) handleQrCode() tea.Cmd {
 	if err != nil {
 		return sendEvent(pamError{
 			status: pam.ErrSystem,
-			msg:    fmt.Sprintf("Can't generate qrcode: %v", err),
+			msg:    fmt.Sprintf(m.catalog.G("Can't generate qrcode: %v"), err),
 		})
 	}
 
@@ -712,6 +793,10 @@ func (m nativeModel) handleQrCode() tea.Cmd {
 		firstQrCodeLine = m.uiLayout.GetContent()
 	}
 
+	if m.plainPrompts {
+		firstQrCodeLine = ""
+	}
+
 	centeredContent := centerString(m.uiLayout.GetContent(), firstQrCodeLine)
 	qrcodeView = append(qrcodeView, centeredContent)
 
@@ -719,18 +804,20 @@ func (m nativeModel) handleQrCode() tea.Cmd {
 		qrcodeView = append(qrcodeView, centerString(code, firstQrCodeLine))
 	}
 
-	// Ass some extra vertical space to improve readability
-	qrcodeView = append(qrcodeView, " ")
+	if !m.plainPrompts {
+		// Ass some extra vertical space to improve readability
+		qrcodeView = append(qrcodeView, " ")
+	}
 
 	choices := []choicePair{
-		{id: layouts.Wait, label: "Wait for authentication result"},
+		{id: layouts.Wait, label: m.catalog.G("Wait for authentication result")},
 	}
 	if buttonLabel := m.uiLayout.GetButton(); buttonLabel != "" {
 		choices = append(choices, choicePair{id: layouts.Button, label: buttonLabel})
 	}
 
-	id, err := m.promptForChoiceWithMessage(m.selectedAuthModeLabel("QR code"),
-		strings.Join(qrcodeView, "\n"), choices, "Choose action")
+	id, err := m.promptForChoiceWithMessage(m.selectedAuthModeLabel(m.catalog.G("QR code")),
+		strings.Join(qrcodeView, "\n"), choices, m.catalog.G("Choose action"))
 	if errors.Is(err, errGoBack) {
 		return sendEvent(nativeGoBack{})
 	}
@@ -752,6 +839,11 @@ func (m nativeModel) handleQrCode() tea.Cmd {
 }
 
 func (m nativeModel) isQrcodeRenderingSupported() bool {
+	if m.plainPrompts {
+		// A QR code drawn with characters can't be read by screen readers or braille terminals.
+		return false
+	}
+
 	switch m.serviceName {
 	case polkitServiceName:
 		return false
@@ -774,17 +866,130 @@ func centerString(s string, reference string) string {
 	return padding + s + padding
 }
 
+func (m nativeModel) handleSmartCard(hasWait bool) tea.Cmd {
+	action := m.uiLayout.GetSmartcardAction()
+	switch action {
+	case layouts.SmartCardPIN:
+		return m.handleSmartCardPIN()
+	case layouts.SmartCardInsert, layouts.SmartCardTouch:
+	default:
+		return sendEvent(pamError{
+			status: pam.ErrSystem,
+			msg:    fmt.Sprintf(m.catalog.G("Unknown smart card action: %q"), action),
+		})
+	}
+
+	if !hasWait {
+		return sendEvent(pamError{
+			status: pam.ErrSystem,
+			msg:    m.catalog.G("Can't handle smart card without waiting"),
+		})
+	}
+
+	message := m.uiLayout.GetLabel()
+	if message == "" {
+		message = m.catalog.G("Insert your smart card")
+		if action == layouts.SmartCardTouch {
+			message = m.catalog.G("Touch your smart card")
+		}
+	}
+	if content := m.uiLayout.GetContent(); content != "" {
+		message = fmt.Sprintf("%s\n%s", message, content)
+	}
+
+	// Without any other choice, we just wait for the user to act on their card.
+	buttonLabel := m.uiLayout.GetButton()
+	if buttonLabel == "" {
+		title := m.selectedAuthModeLabel(m.catalog.G("Smart card"))
+		if cmd := maybeSendPamError(m.sendNotice("== %s ==\n%s", title, message)); cmd != nil {
+			return cmd
+		}
+		return sendAuthWaitCommand()
+	}
+
+	choices := []choicePair{
+		{id: layouts.Wait, label: m.catalog.G("Wait for authentication result")},
+		{id: layouts.Button, label: buttonLabel},
+	}
+	id, err := m.promptForChoiceWithMessage(m.selectedAuthModeLabel(m.catalog.G("Smart card")),
+		message, choices, m.catalog.G("Choose action"))
+	if errors.Is(err, errGoBack) {
+		return sendEvent(nativeGoBack{})
+	}
+	if errors.Is(err, errEmptyResponse) {
+		return sendAuthWaitCommand()
+	}
+	if err != nil {
+		return maybeSendPamError(err)
+	}
+
+	switch id {
+	case layouts.Button:
+		return sendEvent(reselectAuthMode{})
+	case layouts.Wait:
+		return sendAuthWaitCommand()
+	default:
+		return nil
+	}
+}
+
+func (m nativeModel) handleConsent() tea.Cmd {
+	choices := []choicePair{
+		{id: layouts.True, label: m.catalog.G("Accept")},
+		{id: layouts.False, label: m.catalog.G("Decline")},
+	}
+	title := m.uiLayout.GetLabel()
+	if title == "" {
+		title = m.selectedAuthModeLabel(m.catalog.G("Terms of use"))
+	}
+
+	id, err := m.promptForChoiceWithMessage(title, m.uiLayout.GetContent(), choices, m.catalog.G("Choose action"))
+	if errors.Is(err, errGoBack) {
+		return sendEvent(nativeGoBack{})
+	}
+	if errors.Is(err, errEmptyResponse) {
+		return sendEvent(nativeChallengeRequested{})
+	}
+	if err != nil {
+		return maybeSendPamError(err)
+	}
+
+	return sendEvent(isAuthenticatedRequested{
+		item: &authd.IARequest_AuthenticationData_Consent{Consent: id},
+	})
+}
+
+func (m nativeModel) handleSmartCardPIN() tea.Cmd {
+	label, entry, button := m.uiLayout.GetLabel(), m.uiLayout.GetEntry(), m.uiLayout.GetButton()
+	if label == "" {
+		label = m.catalog.G("PIN")
+	}
+	if entry == "" {
+		// The PIN must never be echoed, so we default to a password entry.
+		entry = entries.CharsPassword
+	}
+
+	// The PIN is requested as a form, without changing the layout shared with the other models.
+	m.uiLayout = &authd.UILayout{
+		Type:   layouts.Form,
+		Label:  &label,
+		Entry:  &entry,
+		Button: &button,
+	}
+	return m.handleFormChallenge(false)
+}
+
 func (m nativeModel) handleNewPassword() tea.Cmd {
 	if buttonLabel := m.uiLayout.GetButton(); buttonLabel != "" {
 		choices := []choicePair{
-			{id: "continue", label: "Proceed with password update"},
+			{id: "continue", label: m.catalog.G("Proceed with password update")},
 		}
 		if buttonLabel := m.uiLayout.GetButton(); buttonLabel != "" {
 			choices = append(choices, choicePair{id: layouts.Button, label: buttonLabel})
 		}
 
-		label := m.selectedAuthModeLabel("Password Update")
-		id, err := m.promptForChoice(label, choices, "Choose action")
+		label := m.selectedAuthModeLabel(m.catalog.G("Password Update"))
+		id, err := m.promptForChoice(label, choices, m.catalog.G("Choose action"))
 		if errors.Is(err, errGoBack) {
 			return sendEvent(nativeGoBack{})
 		}
@@ -804,42 +1009,46 @@ func (m nativeModel) handleNewPassword() tea.Cmd {
 	return m.newPasswordChallenge(nil)
 }
 
-func (m nativeModel) newPasswordChallenge(previousPassword *string) tea.Cmd {
+func (m nativeModel) newPasswordChallenge(previousPassword *secret) tea.Cmd {
 	if previousPassword == nil {
-		instructions := fmt.Sprintf("Enter '%[1]s' to cancel the request and %[2]s",
+		instructions := fmt.Sprintf(m.catalog.G("Enter '%[1]s' to cancel the request and %[2]s"),
 			nativeCancelKey, m.goBackActionLabel())
-		title := m.selectedAuthModeLabel("Password Update")
-		if cmd := maybeSendPamError(m.sendInfo("== %s ==\n%s", title, instructions)); cmd != nil {
+		title := m.selectedAuthModeLabel(m.catalog.G("Password Update"))
+		if cmd := maybeSendPamError(m.sendNotice("== %s ==\n%s", title, instructions)); cmd != nil {
 			return cmd
 		}
 	}
 
 	prompt := m.uiLayout.GetLabel()
 	if previousPassword != nil {
-		prompt = "Confirm Password"
+		prompt = m.catalog.G("Confirm Password")
 	}
 
-	password, err := m.promptForSecret(prompt)
+	value, err := m.promptForSecret(prompt)
 	if errors.Is(err, errGoBack) {
+		previousPassword.wipe()
 		return sendEvent(nativeGoBack{})
 	}
 	if err != nil && !errors.Is(err, errEmptyResponse) {
+		previousPassword.wipe()
 		return maybeSendPamError(err)
 	}
 
+	password := newSecret(value)
 	if previousPassword == nil {
 		return sendEvent(newPasswordCheck{password: password})
 	}
-	if password != *previousPassword {
-		err := m.sendError("Password entries don't match")
+	matching := password.equal(previousPassword)
+	previousPassword.wipe()
+	if !matching {
+		password.wipe()
+		err := m.sendError(m.catalog.G("Password entries don't match"))
 		if err != nil {
 			return maybeSendPamError(err)
 		}
 		return m.newPasswordChallenge(nil)
 	}
-	return sendEvent(isAuthenticatedRequested{
-		item: &authd.IARequest_AuthenticationData_Challenge{Challenge: password},
-	})
+	return sendEvent(isAuthenticatedRequested{secret: password})
 }
 
 func (m nativeModel) goBackCommand() (nativeModel, tea.Cmd) {
@@ -882,15 +1091,15 @@ func (m nativeModel) previousStage() pam_proto.Stage {
 func (m nativeModel) goBackActionLabel() string {
 	switch m.previousStage() {
 	case proto.Stage_authModeSelection:
-		return "go back to select the authentication method"
+		return m.catalog.G("go back to select the authentication method")
 	case proto.Stage_brokerSelection:
-		return "go back to choose the provider"
+		return m.catalog.G("go back to choose the provider")
 	case proto.Stage_challenge:
-		return "go back to authentication"
+		return m.catalog.G("go back to authentication")
 	case proto.Stage_userSelection:
-		return "go back to user selection"
+		return m.catalog.G("go back to user selection")
 	}
-	return "go back"
+	return m.catalog.G("go back")
 }
 
 func sendAuthWaitCommand() tea.Cmd {
@@ -0,0 +1,292 @@
+package adapter
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/msteinert/pam/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/pam/internal/pam_test"
+)
+
+func TestNativeModelSupportedLayouts(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		service string
+
+		wantQrCode bool
+	}{
+		"QR_code_layout_is_supported_by_login":        {service: "login", wantQrCode: true},
+		"QR_code_layout_is_supported_by_sudo":         {service: "sudo", wantQrCode: true},
+		"QR_code_layout_is_not_supported_by_polkit":   {service: polkitServiceName},
+		"QR_code_layout_is_supported_without_service": {wantQrCode: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mTx := pam_test.NewModuleTransactionDummy(nil)
+			require.NoError(t, mTx.SetItem(pam.Service, tc.service), "Setup: Setting the service failed")
+
+			m := nativeModel{pamMTx: mTx}
+			msg := m.Init()()
+			supported, ok := msg.(supportedUILayoutsReceived)
+			require.True(t, ok, "Init should return the supported layouts, got %#v", msg)
+
+			var types []string
+			for _, l := range supported.layouts {
+				types = append(types, l.GetType())
+			}
+			require.Subset(t, types, []string{layouts.Form, layouts.NewPassword},
+				"Form and new password layouts should always be supported")
+
+			if !tc.wantQrCode {
+				require.NotContains(t, types, layouts.QrCode, "QR code layout should not be supported")
+				return
+			}
+			require.Contains(t, types, layouts.QrCode, "QR code layout should be supported")
+		})
+	}
+}
+
+func TestNativeModelPlainPrompts(t *testing.T) {
+	t.Parallel()
+
+	qrCodeLayout := &authd.UILayout{
+		Type:    layouts.QrCode,
+		Label:   ptrValue("Scan the QR code or open the URL"),
+		Content: ptrValue("https://login.example.com/device"),
+		Code:    ptrValue("1337"),
+		Wait:    ptrValue(layouts.True),
+	}
+
+	tests := map[string]struct {
+		plainPrompts bool
+		interactive  bool
+		prompt       func(m nativeModel) error
+
+		wantMessages []convMessage
+	}{
+		"Choice_prompt_is_decorated": {
+			prompt: promptForTestChoice,
+			wantMessages: []convMessage{
+				{pam.TextInfo, "== Title ==\nMessage\n  1. First\n  2. Second"},
+				{pam.PromptEchoOn, "Choose"},
+			},
+		},
+		"Choice_prompt_is_plain": {
+			plainPrompts: true,
+			prompt:       promptForTestChoice,
+			wantMessages: []convMessage{
+				{pam.TextInfo, "Title\nMessage\n1. First\n2. Second"},
+				{pam.PromptEchoOn, "Choose"},
+			},
+		},
+		"Multi_line_input_prompt_is_decorated": {
+			interactive: true,
+			prompt: func(m nativeModel) error {
+				_, err := m.promptForInput(pam.PromptEchoOff, inputPromptStyleMultiLine, "Password")
+				return err
+			},
+			wantMessages: []convMessage{{pam.PromptEchoOff, "Password:\n> "}},
+		},
+		"Multi_line_input_prompt_is_inline_if_plain": {
+			plainPrompts: true,
+			interactive:  true,
+			prompt: func(m nativeModel) error {
+				_, err := m.promptForInput(pam.PromptEchoOff, inputPromptStyleMultiLine, "Password")
+				return err
+			},
+			wantMessages: []convMessage{{pam.PromptEchoOff, "Password: "}},
+		},
+		"QR_code_contents_are_centered": {
+			prompt: func(m nativeModel) error {
+				m.uiLayout = qrCodeLayout
+				return commandError(m.handleQrCode())
+			},
+			wantMessages: []convMessage{
+				{pam.TextInfo, "== QR code ==\n" +
+					"Scan the QR code or open the URL\n" +
+					"https://login.example.com/device\n" +
+					"              1337              \n" +
+					" \n" +
+					"  1. Wait for authentication result"},
+				{pam.PromptEchoOn, "Choose action"},
+			},
+		},
+		"QR_code_contents_are_not_padded_if_plain": {
+			plainPrompts: true,
+			prompt: func(m nativeModel) error {
+				m.uiLayout = qrCodeLayout
+				return commandError(m.handleQrCode())
+			},
+			wantMessages: []convMessage{
+				{pam.TextInfo, "QR code\n" +
+					"Scan the QR code or open the URL\n" +
+					"https://login.example.com/device\n" +
+					"1337\n" +
+					"1. Wait for authentication result"},
+				{pam.PromptEchoOn, "Choose action"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var messages []convMessage
+			mTx := pam_test.NewModuleTransactionDummy(recordingConvHandler(&messages, "1"))
+
+			m := nativeModel{pamMTx: mTx, plainPrompts: tc.plainPrompts, interactive: tc.interactive}
+			require.NoError(t, tc.prompt(m), "Prompting should not fail")
+			require.Equal(t, tc.wantMessages, messages, "Unexpected conversation messages")
+		})
+	}
+}
+
+func TestNativeModelSilent(t *testing.T) {
+	t.Parallel()
+
+	passwordLayout := &authd.UILayout{
+		Type:  layouts.Form,
+		Label: ptrValue("Password:"),
+		Entry: ptrValue(entries.CharsPassword),
+	}
+	passwordOrWaitLayout := &authd.UILayout{
+		Type:  layouts.Form,
+		Label: ptrValue("Password:"),
+		Entry: ptrValue(entries.CharsPassword),
+		Wait:  ptrValue(layouts.True),
+	}
+	authResult := func(access string) func(m nativeModel) tea.Cmd {
+		return func(m nativeModel) tea.Cmd {
+			_, cmd := m.Update(isAuthenticatedResultReceived{access: access, msg: `{"message": "Broker message"}`})
+			return cmd
+		}
+	}
+	formChallenge := func(layout *authd.UILayout, hasWait bool) func(m nativeModel) tea.Cmd {
+		return func(m nativeModel) tea.Cmd {
+			m.uiLayout = layout
+			return m.handleFormChallenge(hasWait)
+		}
+	}
+
+	tests := map[string]struct {
+		silent         bool
+		noConversation bool
+		run            func(m nativeModel) tea.Cmd
+
+		wantStyles []pam.Style
+		wantErr    error
+	}{
+		"Granted_message_is_sent":                   {run: authResult(auth.Granted), wantStyles: []pam.Style{pam.TextInfo}},
+		"Granted_message_is_not_sent_if_silent":     {silent: true, run: authResult(auth.Granted)},
+		"Next_message_is_not_sent_if_silent":        {silent: true, run: authResult(auth.Next)},
+		"Retry_message_is_sent_if_silent":           {silent: true, run: authResult(auth.Retry), wantStyles: []pam.Style{pam.ErrorMsg}},
+		"Form_instructions_are_sent":                {run: formChallenge(passwordLayout, false), wantStyles: []pam.Style{pam.TextInfo, pam.PromptEchoOff}},
+		"Form_instructions_are_not_sent_if_silent":  {silent: true, run: formChallenge(passwordLayout, false), wantStyles: []pam.Style{pam.PromptEchoOff}},
+		"Form_wait_instructions_are_sent_if_silent": {silent: true, run: formChallenge(passwordOrWaitLayout, true), wantStyles: []pam.Style{pam.TextInfo, pam.PromptEchoOff}},
+
+		// Error cases.
+		"Error_if_there_is_no_conversation": {
+			noConversation: true,
+			run:            authResult(auth.Granted),
+			wantErr:        pam.ErrConv,
+		},
+		"Error_if_there_is_no_conversation_to_send_the_error_when_silent": {
+			silent:         true,
+			noConversation: true,
+			run:            authResult(auth.Retry),
+			wantErr:        pam.ErrConv,
+		},
+		"Error_if_there_is_no_conversation_to_prompt_when_silent": {
+			silent:         true,
+			noConversation: true,
+			run:            formChallenge(passwordLayout, false),
+			wantErr:        pam.ErrConv,
+		},
+		"Error_if_there_is_no_conversation_to_send_the_wait_instructions_when_silent": {
+			silent:         true,
+			noConversation: true,
+			run:            formChallenge(passwordOrWaitLayout, true),
+			wantErr:        pam.ErrConv,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var messages []convMessage
+			var convHandler pam.ConversationHandler = recordingConvHandler(&messages, "password")
+			if tc.noConversation {
+				convHandler = nil
+			}
+			mTx := pam_test.NewModuleTransactionDummy(convHandler)
+
+			m := nativeModel{pamMTx: mTx, silent: tc.silent}
+			err := commandError(tc.run(m))
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr, "The command should have failed")
+				return
+			}
+			require.NoError(t, err, "The command should not fail")
+
+			var styles []pam.Style
+			for _, msg := range messages {
+				styles = append(styles, msg.style)
+			}
+			require.Equal(t, tc.wantStyles, styles, "Unexpected conversation messages")
+		})
+	}
+}
+
+type convMessage struct {
+	style pam.Style
+	msg   string
+}
+
+// recordingConvHandler returns a conversation handler saving the messages it receives and replying
+// to all the prompts with reply.
+func recordingConvHandler(messages *[]convMessage, reply string) pam.ConversationFunc {
+	return func(style pam.Style, msg string) (string, error) {
+		*messages = append(*messages, convMessage{style, msg})
+		if style == pam.PromptEchoOn || style == pam.PromptEchoOff {
+			return reply, nil
+		}
+		return "", nil
+	}
+}
+
+func promptForTestChoice(m nativeModel) error {
+	id, err := m.promptForChoiceWithMessage("Title", "Message",
+		[]choicePair{{id: "first", label: "First"}, {id: "second", label: "Second"}}, "Choose")
+	if err != nil {
+		return err
+	}
+	if id != "first" {
+		return fmt.Errorf("unexpected choice %q", id)
+	}
+	return nil
+}
+
+// commandError returns the PAM error that the command generated, if any.
+func commandError(cmd tea.Cmd) error {
+	if cmd == nil {
+		return nil
+	}
+	if msg, ok := cmd().(pamError); ok {
+		return fmt.Errorf("%w: %s", msg.Status(), msg.Message())
+	}
+	return nil
+}
+
+func ptrValue[T any](value T) *T {
+	return &value
+}
@@ -2,13 +2,17 @@ package adapter
 
 import (
 	"context"
+	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
 	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/i18n"
+	"github.com/ubuntu/authd/internal/passwordstrength"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 )
@@ -19,6 +23,10 @@ type newPasswordModel struct {
 	label     string
 	skippable bool
 
+	// minStrength is the minimum strength of the new password, shown with the strength meter.
+	minStrength passwordstrength.Score
+	catalog     *i18n.Catalog
+
 	passwordEntries []*textinputModel
 	passwordLabels  []string
 	focusableModels []authenticationComponent
@@ -26,7 +34,7 @@ type newPasswordModel struct {
 }
 
 // newNewPasswordModel initializes and return a new newPasswordModel.
-func newNewPasswordModel(label, entryType, buttonLabel string) newPasswordModel {
+func newNewPasswordModel(label, entryType, buttonLabel string, minStrength passwordstrength.Score, catalog *i18n.Catalog) newPasswordModel {
 	var focusableModels []authenticationComponent
 	var passwordEntries []*textinputModel
 	var skippable bool
@@ -48,11 +56,13 @@ func newNewPasswordModel(label, entryType, buttonLabel string) newPasswordModel
 	}
 
 	return newPasswordModel{
-		label:     label,
-		skippable: skippable,
+		label:       label,
+		skippable:   skippable,
+		minStrength: minStrength,
+		catalog:     catalog,
 
 		passwordEntries: passwordEntries,
-		passwordLabels:  []string{"New password:", "Confirm password:"},
+		passwordLabels:  []string{catalog.G("New password:"), catalog.G("Confirm password:")},
 		focusableModels: focusableModels,
 	}
 }
@@ -123,7 +133,7 @@ func (m newPasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// First entry is focused
 				if m.focusIndex == 0 {
 					// Check password quality
-					return m, sendEvent(newPasswordCheck{password: m.passwordEntries[0].Value()})
+					return m, sendEvent(newPasswordCheck{password: newSecret(m.passwordEntries[0].Value())})
 				}
 
 				// Second entry is focused
@@ -131,12 +141,12 @@ func (m newPasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Check both entries are matching
 					if m.passwordEntries[0].Value() != m.passwordEntries[1].Value() {
 						m.Clear()
-						return m, sendEvent(errMsgToDisplay{msg: "Password entries don't match"})
+						return m, sendEvent(errMsgToDisplay{msg: m.catalog.G("Password entries don't match")})
 					}
 				}
 
 				return m, sendEvent(isAuthenticatedRequested{
-					item: &authd.IARequest_AuthenticationData_Challenge{Challenge: entry.Value()},
+					secret: newSecret(entry.Value()),
 				})
 			}
 
@@ -160,7 +170,7 @@ func (m *newPasswordModel) updateFocusModel(msg tea.Msg) tea.Cmd {
 
 // View renders a text view of the form.
 func (m newPasswordModel) View() string {
-	fields := []string{m.label, ""}
+	fields := []string{renderRichText(m.label), ""}
 
 	for i, fm := range m.focusableModels {
 		switch entry := fm.(type) {
@@ -170,6 +180,9 @@ func (m newPasswordModel) View() string {
 			if m.focusIndex == 1 && m.passwordEntries[0].Value() != "" || i == 0 {
 				fields = append(fields, []string{m.passwordLabels[i], entry.View()}...)
 			}
+			if i == 0 && entry.Value() != "" {
+				fields = append(fields, m.strengthMeter(entry.Value()))
+			}
 		case *buttonModel:
 			fields = append(fields, entry.View())
 		}
@@ -245,3 +258,23 @@ func (m *newPasswordModel) Clear() {
 		fm.Focus()
 	}
 }
+
+// strengthMeter renders the estimated strength of the new password, with the minimum one if it's not reached.
+func (m newPasswordModel) strengthMeter(password string) string {
+	score := passwordstrength.Estimate([]byte(password))
+	bar := strings.Repeat("■", int(score)+1) + strings.Repeat("□", int(passwordstrength.VeryStrong-score))
+	meter := fmt.Sprintf(m.catalog.G("Strength: %s %s"), bar, score.Label(m.catalog))
+	if score < m.minStrength {
+		meter += " " + fmt.Sprintf(m.catalog.G("(at least %s required)"), m.minStrength.Label(m.catalog))
+	}
+	return meter
+}
+
+// checkPasswordStrength checks that the estimated strength of the new password is at least the minimum one.
+func checkPasswordStrength(catalog *i18n.Catalog, newPassword []byte, minStrength passwordstrength.Score) error {
+	if score := passwordstrength.Estimate(newPassword); score < minStrength {
+		return fmt.Errorf(catalog.G("The password is too weak (%s), it must be at least %s"),
+			score.Label(catalog), minStrength.Label(catalog))
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/ubuntu/authd/internal/i18n"
+	"github.com/ubuntu/authd/internal/pwquality"
+	"github.com/ubuntu/authd/log"
+)
+
+// checkBuiltinPasswordQuality checks the quality of the new password with the built-in rules, following the settings
+// of the pwquality configuration.
+func checkBuiltinPasswordQuality(catalog *i18n.Catalog, oldPassword, newPassword []byte) error {
+	settings, err := pwquality.LoadSettings(pwquality.DefaultConfigPath)
+	if err != nil {
+		log.Warningf(context.TODO(), "Checking the password quality with the default settings: %v", err)
+	}
+	return settings.Check(catalog, oldPassword, newPassword)
+}
@@ -0,0 +1,11 @@
+//go:build nopwquality
+
+package adapter
+
+import "github.com/ubuntu/authd/internal/i18n"
+
+// checkPasswordQuality checks the quality of the new password with the built-in rules, as this build doesn't use the
+// pwquality library.
+func checkPasswordQuality(catalog *i18n.Catalog, oldPassword, newPassword []byte) error {
+	return checkBuiltinPasswordQuality(catalog, oldPassword, newPassword)
+}
@@ -1,3 +1,5 @@
+//go:build !nopwquality
+
 package adapter
 
 // #cgo pkg-config: pwquality
@@ -6,16 +8,20 @@ package adapter
 import "C"
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"sync"
 	"unsafe"
+
+	"github.com/ubuntu/authd/internal/i18n"
+	"github.com/ubuntu/authd/log"
 )
 
 var passwordQualityMu sync.Mutex
 
 // checkPasswordQuality checks the quality of the new password using the pwquality library.
-func checkPasswordQuality(oldPassword, newPassword string) error {
+// The catalog is only used to translate the errors of the built-in rules.
+func checkPasswordQuality(catalog *i18n.Catalog, oldPassword, newPassword []byte) error {
 	passwordQualityMu.Lock()
 	defer passwordQualityMu.Unlock()
 
@@ -28,18 +34,20 @@ func checkPasswordQuality(oldPassword, newPassword string) error {
 	var auxErr *C.char
 	auxErrPointer := unsafe.Pointer(auxErr)
 
-	// Load pwquality configuration (from /etc/security/pwquality.conf)
+	// Load pwquality configuration (from /etc/security/pwquality.conf). If the library can't read it, the new password
+	// is still checked, with the built-in rules.
 	if ret := C.pwquality_read_config(pwq, nil, &auxErrPointer); ret < 0 {
 		var buf [C.PWQ_MAX_ERROR_MESSAGE_LEN]C.char
 		errMsg := C.GoString(C.pwquality_strerror(&buf[0], C.size_t(len(buf)), ret, auxErrPointer))
-		return fmt.Errorf("can't ready pwquality configuration: %s", errMsg)
+		log.Warningf(context.TODO(), "Can't read pwquality configuration, using the built-in rules: %s", errMsg)
+		return checkBuiltinPasswordQuality(catalog, oldPassword, newPassword)
 	}
 
-	oldC := C.CString(oldPassword)
-	defer C.free(unsafe.Pointer(oldC))
+	oldC, freeOld := cSecret(oldPassword)
+	defer freeOld()
 
-	newC := C.CString(newPassword)
-	defer C.free(unsafe.Pointer(newC))
+	newC, freeNew := cSecret(newPassword)
+	defer freeNew()
 
 	if ret := C.pwquality_check(pwq, newC, oldC, nil, &auxErrPointer); ret < 0 {
 		var buf [C.PWQ_MAX_ERROR_MESSAGE_LEN]C.char
@@ -48,3 +56,16 @@ func checkPasswordQuality(oldPassword, newPassword string) error {
 	}
 	return nil
 }
+
+// cSecret returns a C string copy of the secret, and the function zeroing and freeing it.
+func cSecret(secret []byte) (*C.char, func()) {
+	size := C.size_t(len(secret) + 1)
+	cs := (*C.char)(C.calloc(size, 1))
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(cs)), size)
+	copy(buf, secret)
+
+	return cs, func() {
+		clear(buf)
+		C.free(unsafe.Pointer(cs))
+	}
+}
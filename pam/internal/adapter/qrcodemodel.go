@@ -11,6 +11,7 @@ import (
 	"github.com/muesli/termenv"
 	"github.com/skip2/go-qrcode"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"github.com/ubuntu/authd/log"
 )
@@ -27,15 +28,28 @@ type qrcodeModel struct {
 	qrCode  *qrcode.QRCode
 
 	wait bool
+
+	// deviceCodeHelpers copies the code to the clipboard and shows the content as a hyperlink.
+	deviceCodeHelpers bool
+	codeCopied        bool
+
+	catalog *i18n.Catalog
 }
 
+// deviceCodeCopied is sent once the device code has been copied to the clipboard.
+type deviceCodeCopied struct{}
+
 // newQRCodeModel initializes and return a new qrcodeModel.
-func newQRCodeModel(content, code, label, buttonLabel string, wait bool) (qrcodeModel, error) {
+func newQRCodeModel(content, code, label, buttonLabel string, wait, deviceCodeHelpers bool, catalog *i18n.Catalog) (qrcodeModel, error) {
 	var button *authReselectButtonModel
 	if buttonLabel != "" {
 		button = newAuthReselectionButtonModel(buttonLabel)
 	}
 
+	// The content and the code are provided by the broker, and are rendered or copied as they are.
+	content = stripControlCharacters(content)
+	code = stripControlCharacters(code)
+
 	qrCode, err := qrcode.New(content, qrcode.Medium)
 	if err != nil {
 		return qrcodeModel{}, fmt.Errorf("can't generate QR code: %v", err)
@@ -48,6 +62,10 @@ func newQRCodeModel(content, code, label, buttonLabel string, wait bool) (qrcode
 		code:        code,
 		qrCode:      qrCode,
 		wait:        wait,
+
+		deviceCodeHelpers: deviceCodeHelpers,
+
+		catalog: catalog,
 	}, nil
 }
 
@@ -60,12 +78,24 @@ func (m qrcodeModel) Init() tea.Cmd {
 func (m qrcodeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg.(type) {
 	case startAuthentication:
-		if !m.wait {
-			return m, nil
+		var cmds []tea.Cmd
+		if m.deviceCodeHelpers && m.code != "" && !m.codeCopied {
+			code := m.code
+			cmds = append(cmds, func() tea.Msg {
+				copyToClipboard(code)
+				return deviceCodeCopied{}
+			})
+		}
+		if m.wait {
+			cmds = append(cmds, sendEvent(isAuthenticatedRequested{
+				item: &authd.IARequest_AuthenticationData_Wait{Wait: layouts.True},
+			}))
 		}
-		return m, sendEvent(isAuthenticatedRequested{
-			item: &authd.IARequest_AuthenticationData_Wait{Wait: layouts.True},
-		})
+		return m, tea.Batch(cmds...)
+
+	case deviceCodeCopied:
+		m.codeCopied = true
+		return m, nil
 	}
 
 	model, cmd := m.buttonModel.Update(msg)
@@ -94,7 +124,7 @@ func (m qrcodeModel) renderQrCode() (qr string) {
 func (m qrcodeModel) View() string {
 	fields := []string{}
 	if m.label != "" {
-		fields = append(fields, m.label, "")
+		fields = append(fields, renderRichText(m.label), "")
 	}
 
 	qr := m.renderQrCode()
@@ -106,11 +136,18 @@ func (m qrcodeModel) View() string {
 	if lipgloss.Width(m.content) < qrcodeWidth {
 		renderedContent = style.Render(m.content)
 	}
+	if m.deviceCodeHelpers && isWebURL(m.content) {
+		// The hyperlink is only added once centered, as the escape sequences have no width.
+		renderedContent = strings.Replace(renderedContent, m.content, hyperlink(m.content, m.content), 1)
+	}
 	fields = append(fields, renderedContent)
 
 	if m.code != "" {
 		fields = append(fields, style.Render(m.code))
 	}
+	if m.codeCopied {
+		fields = append(fields, style.Render(m.catalog.G("(copied to the clipboard)")))
+	}
 
 	if m.buttonModel != nil {
 		fields = append(fields, style.Render(m.buttonModel.View()))
@@ -0,0 +1,146 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQRCodeModelDeviceCodeHelpers(t *testing.T) {
+	// These tests can't be parallel as they change the terminal output of the device code helpers.
+	const url = "https://login.example.com/device"
+
+	tests := map[string]struct {
+		content   string
+		code      string
+		noHelpers bool
+
+		wantCopied      string
+		wantNotCopied   bool
+		wantHyperlink   string
+		wantNoHyperlink bool
+		wantNotInView   string
+		wantNoEscapes   bool
+	}{
+		"Copies_the_code_and_shows_the_URL_as_hyperlink": {
+			content:       url,
+			code:          "ABCD-1234",
+			wantCopied:    "ABCD-1234",
+			wantHyperlink: url,
+		},
+		"Shows_the_URL_as_hyperlink_without_code": {
+			content:       url,
+			wantHyperlink: url,
+			wantNotCopied: true,
+		},
+		"Copies_the_code_without_hyperlink_for_non_web_content": {
+			content:         "Use the code in the authenticator application",
+			code:            "ABCD-1234",
+			wantCopied:      "ABCD-1234",
+			wantNoHyperlink: true,
+		},
+		"Strips_control_characters_from_the_code": {
+			content:       url,
+			code:          "ABCD\x1b]52;c;ZXZpbA==\a-1234",
+			wantCopied:    "ABCD]52;c;ZXZpbA==-1234",
+			wantHyperlink: url,
+			wantNotInView: "\x1b]52;c;ZXZpbA==",
+		},
+		"Strips_control_characters_from_the_content": {
+			content:       "https://login.example.com/\x1b]8;;https://evil.example.com\x1b\\device",
+			wantNotCopied: true,
+			wantHyperlink: "https://login.example.com/]8;;https://evil.example.com\\device",
+			wantNotInView: "\x1b]8;;https://evil.example.com",
+		},
+		"Strips_C1_control_characters_from_the_code": {
+			content:       url,
+			code:          "ABCD\u009b31m-1234",
+			wantCopied:    "ABCD31m-1234",
+			wantHyperlink: url,
+			wantNotInView: "\u009b",
+		},
+
+		"No_hyperlink_for_URL_with_spaces": {
+			content:         "https://login.example.com/ device",
+			wantNotCopied:   true,
+			wantNoHyperlink: true,
+		},
+		"No_hyperlink_for_non_web_URL": {
+			content:         "file:///etc/passwd",
+			wantNotCopied:   true,
+			wantNoHyperlink: true,
+		},
+		"No_copy_nor_hyperlink_if_helpers_are_not_supported": {
+			content:         url,
+			code:            "ABCD-1234",
+			noHelpers:       true,
+			wantNotCopied:   true,
+			wantNoHyperlink: true,
+			wantNoEscapes:   true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TERM", "xterm-256color")
+
+			var out bytes.Buffer
+			deviceCodeOutput = func() *termenv.Output { return termenv.NewOutput(&out) }
+			t.Cleanup(func() { deviceCodeOutput = termenv.DefaultOutput })
+
+			m, err := newQRCodeModel(tc.content, tc.code, "", "", false, !tc.noHelpers, nil)
+			require.NoError(t, err, "Setup: Creating the QR code model failed")
+
+			var model tea.Model = m
+			_, cmd := model.Update(startAuthentication{})
+			for _, msg := range runCommands(cmd) {
+				model, _ = model.Update(msg)
+			}
+			view := model.View()
+
+			if tc.wantNotCopied {
+				require.Empty(t, out.String(), "Nothing should be copied to the clipboard")
+				require.NotContains(t, view, "(copied to the clipboard)",
+					"The code should not be shown as copied")
+			} else {
+				require.Equal(t, "\x1b]52;c;"+base64.StdEncoding.EncodeToString([]byte(tc.wantCopied))+"\a",
+					out.String(), "The code should be copied to the clipboard with OSC 52")
+				require.Contains(t, view, "(copied to the clipboard)", "The code should be shown as copied")
+			}
+
+			if tc.wantNoHyperlink {
+				require.NotContains(t, view, "\x1b]8;;", "The content should not be shown as hyperlink")
+			} else {
+				require.Contains(t, view, "\x1b]8;;"+tc.wantHyperlink+"\x1b\\"+tc.wantHyperlink+"\x1b]8;;\x1b\\",
+					"The content should be shown as hyperlink with OSC 8")
+			}
+
+			if tc.wantNotInView != "" {
+				require.NotContains(t, view, tc.wantNotInView, "The broker strings should be sanitized")
+			}
+			if tc.wantNoEscapes {
+				require.NotContains(t, view, "\x1b]", "No OSC escape sequence should be in the view")
+			}
+		})
+	}
+}
+
+// runCommands runs the command and the batched ones it may return, returning the messages they generated.
+func runCommands(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, c := range batch {
+		msgs = append(msgs, runCommands(c)...)
+	}
+	return msgs
+}
@@ -2,6 +2,8 @@ package adapter
 
 import (
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/brokers/auth"
 )
 
 // Various signalling return messaging to PAM.
@@ -49,3 +51,34 @@ func (p pamError) Message() string {
 	}
 	return p.status.Error()
 }
+
+// PamStatusFromError returns the PAM error matching the classification of err.
+// If err is not classified or there's no matching PAM error, fallback is returned.
+func PamStatusFromError(err error, fallback pam.Error) pam.Error {
+	switch authderrors.CodeOf(err) {
+	case authderrors.NotFound:
+		return pam.ErrUserUnknown
+	case authderrors.PermissionDenied:
+		return pam.ErrPermDenied
+	case authderrors.Unavailable, authderrors.Timeout:
+		return pam.ErrAuthinfoUnavail
+	case authderrors.Canceled:
+		return pam.ErrAbort
+	default:
+		return fallback
+	}
+}
+
+// pamStatusFromErrorCode returns the PAM error matching the error code a broker denied the access with.
+func pamStatusFromErrorCode(code string) pam.Error {
+	switch code {
+	case auth.ErrorCodeNetwork:
+		return pam.ErrAuthinfoUnavail
+	case auth.ErrorCodeAccountLocked, auth.ErrorCodeConsentDenied:
+		return pam.ErrPermDenied
+	case auth.ErrorCodeMFARequired:
+		return pam.ErrCredInsufficient
+	default:
+		return pam.ErrAuth
+	}
+}
@@ -0,0 +1,52 @@
+package adapter
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/pam/internal/richtext"
+	"google.golang.org/protobuf/proto"
+)
+
+// boldStyle is the style of the bold text in the labels and messages of the brokers.
+var boldStyle = lipgloss.NewStyle().Bold(true)
+
+// renderRichText renders the label or message of a broker, which can use a subset of markdown, in the terminal.
+func renderRichText(s string) string {
+	return richtext.Render(s, func(b string) string { return boldStyle.Render(b) })
+}
+
+// plainText renders the label or message of a broker without any style, for the prompts and PAM messages.
+func plainText(s string) string {
+	return richtext.Render(s, nil)
+}
+
+// sanitizeLayout returns a copy of the layout whose texts only use the subset of markdown supported by the clients.
+func sanitizeLayout(layout *authd.UILayout) *authd.UILayout {
+	return mapLayoutTexts(layout, richtext.Sanitize)
+}
+
+// plainLayout returns a copy of the sanitized layout whose texts are rendered without any style.
+func plainLayout(layout *authd.UILayout) *authd.UILayout {
+	return mapLayoutTexts(layout, plainText)
+}
+
+// mapLayoutTexts returns a copy of the layout with f applied to the texts shown to the user. The content of the QR
+// codes is not a text but the encoded data, so it's kept as is.
+func mapLayoutTexts(layout *authd.UILayout, f func(string) string) *authd.UILayout {
+	if layout == nil {
+		return nil
+	}
+
+	r := proto.Clone(layout).(*authd.UILayout)
+	fields := []*string{r.Label, r.Button}
+	if r.GetType() != layouts.QrCode {
+		fields = append(fields, r.Content)
+	}
+	for _, field := range fields {
+		if field != nil {
+			*field = f(*field)
+		}
+	}
+	return r
+}
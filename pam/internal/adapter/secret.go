@@ -0,0 +1,97 @@
+package adapter
+
+import (
+	"crypto/subtle"
+	"errors"
+	"sync"
+)
+
+// errSecretWiped is returned when using a secret which has already been wiped.
+var errSecretWiped = errors.New("secret has already been wiped")
+
+// secret is a buffer holding a password, a PIN or a one time code, which is wiped once it's not needed anymore.
+//
+// Secrets are passed around the models by pointer rather than as strings, so that the messages retained by the
+// bubbletea runtime only refer to a buffer which is zeroed once the secret has been encrypted for the broker, or once
+// the authentication is cancelled.
+type secret struct {
+	mu    sync.Mutex
+	buf   []byte
+	wiped bool
+}
+
+// newSecret returns a secret holding a copy of value.
+func newSecret(value string) *secret {
+	return &secret{buf: []byte(value)}
+}
+
+// with calls f with the content of the secret, which must not be retained once f returns.
+// A nil secret is considered empty.
+func (s *secret) with(f func(b []byte) error) error {
+	if s == nil {
+		return f(nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.wiped {
+		return errSecretWiped
+	}
+	return f(s.buf)
+}
+
+// equal returns whether both secrets have the same content, in constant time.
+func (s *secret) equal(other *secret) bool {
+	if s == other {
+		return !s.isWiped()
+	}
+
+	var equal bool
+	_ = s.with(func(a []byte) error {
+		return other.with(func(b []byte) error {
+			equal = subtle.ConstantTimeCompare(a, b) == 1
+			return nil
+		})
+	})
+	return equal
+}
+
+// isEmpty returns whether the secret is empty.
+func (s *secret) isEmpty() bool {
+	var empty bool
+	_ = s.with(func(b []byte) error {
+		empty = len(b) == 0
+		return nil
+	})
+	return empty
+}
+
+// isWiped returns whether the secret has been wiped.
+func (s *secret) isWiped() bool {
+	return errors.Is(s.with(func([]byte) error { return nil }), errSecretWiped)
+}
+
+// wipe zeroes the content of the secret, which can't be used anymore. It's a no-op on a nil or wiped secret.
+func (s *secret) wipe() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.buf)
+	s.buf = nil
+	s.wiped = true
+}
+
+// String never returns the content of the secret, so that it can't be leaked when logging the messages holding it.
+func (s *secret) String() string {
+	return "<secret>"
+}
+
+// GoString never returns the content of the secret, so that it can't be leaked when logging the messages holding it.
+func (s *secret) GoString() string {
+	return s.String()
+}
@@ -0,0 +1,82 @@
+package adapter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value string
+		other *secret
+		wipe  bool
+
+		wantEmpty bool
+		wantEqual bool
+		wantErr   bool
+	}{
+		"Secret_holds_its_value":                 {value: "goodpass", other: newSecret("goodpass"), wantEqual: true},
+		"Secret_differs_from_another_value":      {value: "goodpass", other: newSecret("badpass")},
+		"Secret_differs_from_a_value_prefix":     {value: "goodpass", other: newSecret("good")},
+		"Empty_secret_is_equal_to_a_nil_secret":  {value: "", wantEmpty: true, wantEqual: true},
+		"Wiped_secret_is_not_equal_to_any_value": {value: "goodpass", other: newSecret("goodpass"), wipe: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newSecret(tc.value)
+			if tc.wipe {
+				s.wipe()
+				require.True(t, s.isWiped(), "Secret should be wiped")
+			}
+
+			var got string
+			err := s.with(func(b []byte) error {
+				got = string(b)
+				return nil
+			})
+			if tc.wantErr {
+				require.ErrorIs(t, err, errSecretWiped, "Using a wiped secret should fail")
+			} else {
+				require.NoError(t, err, "Using the secret should not fail")
+				require.Equal(t, tc.value, got, "Secret should hold its value")
+			}
+
+			require.Equal(t, tc.wantEmpty, s.isEmpty(), "Secret emptiness is not the expected one")
+			require.Equal(t, tc.wantEqual, s.equal(tc.other), "Secrets comparison is not the expected one")
+			require.Equal(t, !tc.wipe, s.equal(s), "Secret should be equal to itself unless wiped")
+		})
+	}
+}
+
+func TestSecretWipeClearsTheBuffer(t *testing.T) {
+	t.Parallel()
+
+	s := newSecret("goodpass")
+	var buf []byte
+	_ = s.with(func(b []byte) error {
+		buf = b
+		return nil
+	})
+
+	s.wipe()
+	require.Equal(t, make([]byte, len("goodpass")), buf, "Wiping the secret should zero its content")
+
+	// Wiping again, or wiping a nil secret, is a no-op.
+	s.wipe()
+	(*secret)(nil).wipe()
+}
+
+func TestSecretIsNotPrinted(t *testing.T) {
+	t.Parallel()
+
+	msg := isAuthenticatedRequested{secret: newSecret("goodpass")}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		require.NotContains(t, fmt.Sprintf(format, msg), "goodpass", "Secret should not be printed with %q", format)
+	}
+}
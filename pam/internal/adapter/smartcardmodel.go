@@ -0,0 +1,125 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/i18n"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+)
+
+// smartcardModel is the smart card layout type waiting for the user to insert or touch their card.
+type smartcardModel struct {
+	label       string
+	content     string
+	buttonModel *authReselectButtonModel
+
+	wait bool
+}
+
+// newSmartCardModel initializes and returns the model for the smart card action of the layout.
+// The PIN entry is handled as a form.
+func newSmartCardModel(action, label, content, entry, buttonLabel string, wait bool, catalog *i18n.Catalog) (authenticationComponent, error) {
+	switch action {
+	case layouts.SmartCardPIN:
+		if entry == "" {
+			entry = entries.CharsPassword
+		}
+		return newFormModel(label, entry, buttonLabel, false, codeOptions{}, catalog), nil
+	case layouts.SmartCardInsert, layouts.SmartCardTouch:
+	default:
+		return nil, fmt.Errorf("unknown smart card action: %q", action)
+	}
+
+	if label == "" {
+		label = catalog.G("Insert your smart card")
+		if action == layouts.SmartCardTouch {
+			label = catalog.G("Touch your smart card")
+		}
+	}
+
+	var button *authReselectButtonModel
+	if buttonLabel != "" {
+		button = newAuthReselectionButtonModel(buttonLabel)
+	}
+
+	return smartcardModel{
+		label:       label,
+		content:     content,
+		buttonModel: button,
+		wait:        wait,
+	}, nil
+}
+
+// Init initializes smartcardModel.
+func (m smartcardModel) Init() tea.Cmd {
+	if m.buttonModel == nil {
+		return nil
+	}
+	return m.buttonModel.Init()
+}
+
+// Update handles events and actions.
+func (m smartcardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg.(type) {
+	case startAuthentication:
+		if !m.wait {
+			return m, nil
+		}
+		return m, sendEvent(isAuthenticatedRequested{
+			item: &authd.IARequest_AuthenticationData_Wait{Wait: layouts.True},
+		})
+	}
+
+	if m.buttonModel == nil {
+		return m, nil
+	}
+	model, cmd := m.buttonModel.Update(msg)
+	m.buttonModel = convertTo[*authReselectButtonModel](model)
+
+	return m, cmd
+}
+
+// View renders a text view of the smart card action.
+func (m smartcardModel) View() string {
+	fields := []string{renderRichText(m.label)}
+	if m.content != "" {
+		fields = append(fields, "", renderRichText(m.content))
+	}
+	if m.buttonModel != nil {
+		fields = append(fields, "", m.buttonModel.View())
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		fields...,
+	)
+}
+
+// Focus focuses this model.
+func (m smartcardModel) Focus() tea.Cmd {
+	log.Debugf(context.TODO(), "%T: Focus", m)
+	if m.buttonModel == nil {
+		return nil
+	}
+	return m.buttonModel.Focus()
+}
+
+// Blur releases the focus from this model.
+func (m smartcardModel) Blur() {
+	log.Debugf(context.TODO(), "%T: Blur", m)
+	if m.buttonModel == nil {
+		return
+	}
+	m.buttonModel.Blur()
+}
+
+// Focused returns whether this model is focused.
+func (m smartcardModel) Focused() bool {
+	// This is always considered focused.
+	return true
+}
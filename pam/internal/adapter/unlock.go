@@ -0,0 +1,98 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/layouts"
+	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
+	"github.com/ubuntu/authd/internal/i18n"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+)
+
+// localPINRequested signals that the user can unlock their session with their local PIN, without their broker.
+type localPINRequested struct{}
+
+// localPINLocked signals that the local PIN of the user has been locked after too many failed attempts, so that the
+// user has to unlock their session with their broker.
+type localPINLocked struct {
+	msg string
+}
+
+// localPINLayout returns the layout of the local PIN prompt.
+func localPINLayout(catalog *i18n.Catalog) *authd.UILayout {
+	label := catalog.G("Enter your PIN")
+	entry := entries.DigitsPassword
+	return &authd.UILayout{
+		Type:  layouts.Form,
+		Label: &label,
+		Entry: &entry,
+	}
+}
+
+// unlockForUser returns the command starting the unlock of the session of the user: with their local PIN if the
+// daemon allows it for the service, or with the broker they previously logged in with otherwise.
+func unlockForUser(client authd.PAMClient, username, service string, defaultBroker *string) tea.Cmd {
+	return func() tea.Msg {
+		status, err := client.GetLocalPINStatus(context.TODO(), &authd.GLPSRequest{
+			Username: username,
+			Service:  service,
+		})
+		if err != nil {
+			log.Warningf(context.TODO(), "Could not get local PIN status of user %q: %v", username, err)
+		}
+		if status.GetUsable() {
+			return localPINRequested{}
+		}
+		return AutoSelectForUser(client, username, defaultBroker)()
+	}
+}
+
+// authenticateWithLocalPIN returns the command checking the local PIN of the user. The result is sent as the one of
+// an authentication, so that the client models handle it as such, unless the local PIN got locked.
+func authenticateWithLocalPIN(client authd.PAMClient, username, service string, pin *secret) tea.Cmd {
+	return func() tea.Msg {
+		defer pin.wipe()
+
+		var resp *authd.ALPResponse
+		err := pin.with(func(b []byte) (err error) {
+			resp, err = client.AuthenticateWithLocalPIN(context.TODO(), &authd.ALPRequest{
+				Username: username,
+				Service:  service,
+				Pin:      string(b),
+			})
+			return err
+		})
+		if err != nil {
+			return pamError{
+				status: PamStatusFromError(err, pam.ErrSystem),
+				msg:    fmt.Sprintf("local PIN authentication failure: %v", err),
+			}
+		}
+		if resp.GetGranted() {
+			return isAuthenticatedResultReceived{access: auth.Granted}
+		}
+
+		status, err := client.GetLocalPINStatus(context.TODO(), &authd.GLPSRequest{
+			Username: username,
+			Service:  service,
+		})
+		if err != nil || !status.GetUsable() {
+			return localPINLocked{msg: resp.GetMsg()}
+		}
+
+		msg, err := json.Marshal(resp.GetMsg())
+		if err != nil {
+			return pamError{status: pam.ErrSystem, msg: fmt.Sprintf("could not encode %q error: %v", resp.GetMsg(), err)}
+		}
+		return isAuthenticatedResultReceived{
+			access: auth.Retry,
+			msg:    fmt.Sprintf(`{"message": %s}`, msg),
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/authd/pam/internal/proto"
 )
@@ -18,8 +19,11 @@ type userSelectionModel struct {
 
 	pamMTx     pam.ModuleTransaction
 	clientType PamClientType
+	catalog    *i18n.Catalog
 	enabled    bool
 	selected   bool
+	// pamUserPreset is whether the user was already set by PAM when the model was initialized.
+	pamUserPreset bool
 }
 
 // userSelected events to report that a new username has been selected.
@@ -38,15 +42,15 @@ func sendUserSelected(username string) tea.Cmd {
 }
 
 // newUserSelectionModel returns an initialized userSelectionModel.
-func newUserSelectionModel(pamMTx pam.ModuleTransaction, clientType PamClientType) userSelectionModel {
+func newUserSelectionModel(pamMTx pam.ModuleTransaction, clientType PamClientType, catalog *i18n.Catalog) userSelectionModel {
 	u := textinput.New()
 	if clientType != InteractiveTerminal {
 		// Cursor events are racy: https://github.com/charmbracelet/bubbletea/issues/909.
 		// FIXME: Avoid initializing the text input Model at all.
 		u.Cursor.SetMode(cursor.CursorHide)
 	}
-	u.Prompt = "Username: " // TODO: i18n
-	u.Placeholder = "user name"
+	u.Prompt = catalog.G("Username: ")
+	u.Placeholder = catalog.G("user name")
 
 	//TODO: u.Validate
 	return userSelectionModel{
@@ -54,11 +58,17 @@ func newUserSelectionModel(pamMTx pam.ModuleTransaction, clientType PamClientTyp
 
 		pamMTx:     pamMTx,
 		clientType: clientType,
+		catalog:    catalog,
 	}
 }
 
 // Init initializes userSelectionModel.
 func (m *userSelectionModel) Init() tea.Cmd {
+	pamUser, err := m.pamMTx.GetItem(pam.User)
+	if cmd := maybeSendPamError(err); cmd != nil {
+		return cmd
+	}
+	m.pamUserPreset = pamUser != ""
 	return nil
 }
 
@@ -84,10 +94,12 @@ func (m userSelectionModel) Update(msg tea.Msg) (userSelectionModel, tea.Cmd) {
 			return m, cmd
 		}
 		differentUser := msg.username != currentUser
-		if !m.enabled && currentUser != "" && differentUser {
+		// The user can be changed (as it happens when switching user at the GDM greeter), but only if it was
+		// not preset by PAM.
+		if m.pamUserPreset && differentUser {
 			return m, sendEvent(pamError{
 				status: pam.ErrPermDenied,
-				msg: fmt.Sprintf("Changing username %q to %q is not allowed",
+				msg: fmt.Sprintf(m.catalog.G("Changing username %q to %q is not allowed"),
 					currentUser, msg.username),
 			})
 		}
@@ -7,10 +7,13 @@ import (
 	"math"
 	"os"
 	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/term"
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/clock"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/log"
 )
 
@@ -20,6 +23,10 @@ var (
 
 	isTerminalTTYValue bool
 	isTerminalTTYOnce  sync.Once
+
+	// appClock is the clock used by the models to tell the time and wait. Its durations can be scaled through
+	// clock.ScaleEnv, for example on slow machines.
+	appClock = clock.Default()
 )
 
 // convertTo converts an interface I value to T. It will panic (progamming error) if this is not the case.
@@ -28,6 +35,13 @@ func convertTo[T any, I any](elem I) T {
 	return any(elem).(T)
 }
 
+// tick is like tea.Tick, but waits on appClock.
+func tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return fn(<-appClock.After(d))
+	}
+}
+
 // TeaHeadlessOptions gets the options to run a bubbletea program in headless mode.
 func TeaHeadlessOptions() ([]tea.ProgramOption, error) {
 	// Explicitly set the output to something so that the program
@@ -123,6 +137,15 @@ func IsTerminalTTY(mTx pam.ModuleTransaction) bool {
 	return isTerminalTTYValue
 }
 
+// PamLocale returns the locale to use for the user facing messages, preferring
+// the one defined in the PAM environment to the one of the current process.
+func PamLocale(mTx pam.ModuleTransaction) string {
+	if l := i18n.LocaleFromEnv(mTx.GetEnv); l != "C" {
+		return l
+	}
+	return i18n.LocaleFromEnv(os.Getenv)
+}
+
 func maybeSendPamError(err error) tea.Cmd {
 	if err == nil {
 		return nil
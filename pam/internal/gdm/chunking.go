@@ -0,0 +1,260 @@
+package gdm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/log"
+)
+
+const (
+	// DefaultMaxPayloadSize is the maximum size of the messages we send and accept.
+	DefaultMaxPayloadSize = uint32(64 * 1024)
+
+	// minMaxPayloadSize is the minimum payload size we accept to split data into chunks.
+	minMaxPayloadSize = uint32(1024)
+	// chunkOverhead is the space reserved in each message for the chunk JSON envelope.
+	chunkOverhead = 256
+	// maxAssembledSize is the maximum size of the data once reassembled and decompressed.
+	maxAssembledSize = 16 * 1024 * 1024
+)
+
+// ErrInvalidChunk is returned when a received chunk does not match the data being reassembled.
+var ErrInvalidChunk = errors.New("invalid chunk")
+
+// payloadLimits are the limits of the data exchanged with GDM, as negotiated in the hello handshake.
+type payloadLimits struct {
+	mu sync.RWMutex
+	// maxSize is the maximum size of a message, 0 means that GDM does not support chunks.
+	maxSize     uint32
+	compression bool
+}
+
+var limits = &payloadLimits{}
+
+func (l *payloadLimits) get() (maxSize uint32, compression bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.maxSize, l.compression
+}
+
+var chunkIDs atomic.Uint32
+
+// NewHelloData returns the hello data advertising our protocol capabilities.
+func NewHelloData() *HelloData {
+	maxPayloadSize := DefaultMaxPayloadSize
+	supportsCompression := true
+	return &HelloData{
+		Version:             ProtoVersion,
+		MaxPayloadSize:      &maxPayloadSize,
+		SupportsCompression: &supportsCompression,
+	}
+}
+
+// NegotiatePayloadLimits sets the limits of the data sent to GDM based on the capabilities it advertised.
+// If GDM does not advertise a maximum payload size, then data is never split into chunks.
+func NegotiatePayloadLimits(peer *HelloData) {
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	limits.maxSize = min(peer.GetMaxPayloadSize(), DefaultMaxPayloadSize)
+	if limits.maxSize > 0 && limits.maxSize < minMaxPayloadSize {
+		log.Warningf(context.TODO(), "GDM maximum payload size %d is too small, using %d",
+			limits.maxSize, minMaxPayloadSize)
+		limits.maxSize = minMaxPayloadSize
+	}
+	limits.compression = limits.maxSize > 0 && peer.GetSupportsCompression()
+	log.Debugf(context.TODO(), "GDM payload limits: max size %d, compression %v",
+		limits.maxSize, limits.compression)
+}
+
+// splitInChunks splits the data in chunks whose JSON representation fits in maxSize,
+// compressing it first if allowed and worth it.
+func splitInChunks(data []byte, maxSize uint32, compress bool) ([]*Data, error) {
+	if maxSize < minMaxPayloadSize {
+		return nil, fmt.Errorf("maximum payload size %d is too small", maxSize)
+	}
+
+	compressed := false
+	if compress {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("can't compress data: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("can't compress data: %w", err)
+		}
+		if buf.Len() < len(data) {
+			data = buf.Bytes()
+			compressed = true
+		}
+	}
+
+	// The payload is base64 encoded in the JSON message, so it takes 4/3 of its size.
+	chunkSize := int(maxSize-chunkOverhead) / 4 * 3
+	total := (len(data) + chunkSize - 1) / chunkSize
+	id := chunkIDs.Add(1)
+
+	chunks := make([]*Data, 0, total)
+	for i := 0; i < total; i++ {
+		payload := data[i*chunkSize : min((i+1)*chunkSize, len(data))]
+		chunks = append(chunks, &Data{
+			Type: DataType_chunk,
+			Chunk: &ChunkData{
+				Id:         id,
+				Index:      uint32(i),
+				Total:      uint32(total),
+				Compressed: compressed,
+				Payload:    payload,
+			},
+		})
+	}
+
+	return chunks, nil
+}
+
+// chunksAssembler reassembles the data received in chunks.
+type chunksAssembler struct {
+	current *ChunkData
+	buf     bytes.Buffer
+}
+
+// add adds a chunk to the data being reassembled, returning the whole data once complete.
+func (a *chunksAssembler) add(c *ChunkData) (data []byte, complete bool, err error) {
+	if c.GetTotal() == 0 || c.GetIndex() >= c.GetTotal() {
+		return nil, false, fmt.Errorf("%w: index %d of %d", ErrInvalidChunk, c.GetIndex(), c.GetTotal())
+	}
+
+	if c.GetIndex() == 0 {
+		a.reset()
+	} else if a.current == nil || a.current.Id != c.Id || a.current.Total != c.Total ||
+		a.current.Compressed != c.Compressed || a.current.Index+1 != c.Index {
+		a.reset()
+		return nil, false, fmt.Errorf("%w: unexpected chunk %d of %d for data %d", ErrInvalidChunk,
+			c.GetIndex(), c.GetTotal(), c.GetId())
+	}
+
+	if a.buf.Len()+len(c.GetPayload()) > maxAssembledSize {
+		a.reset()
+		return nil, false, fmt.Errorf("%w: data exceeds %d bytes", ErrInvalidChunk, maxAssembledSize)
+	}
+	a.buf.Write(c.GetPayload())
+	a.current = c
+
+	if c.GetIndex()+1 < c.GetTotal() {
+		return nil, false, nil
+	}
+
+	defer a.reset()
+	if !c.GetCompressed() {
+		return bytes.Clone(a.buf.Bytes()), true, nil
+	}
+
+	r, err := gzip.NewReader(&a.buf)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: can't decompress data: %w", ErrInvalidChunk, err)
+	}
+	data, err = io.ReadAll(io.LimitReader(r, maxAssembledSize+1))
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: can't decompress data: %w", ErrInvalidChunk, err)
+	}
+	if len(data) > maxAssembledSize {
+		return nil, false, fmt.Errorf("%w: decompressed data exceeds %d bytes", ErrInvalidChunk, maxAssembledSize)
+	}
+
+	return data, true, nil
+}
+
+func (a *chunksAssembler) reset() {
+	a.current = nil
+	a.buf.Reset()
+}
+
+// parseChunk returns the chunk data if the JSON value is a chunk.
+func parseChunk(jsonValue []byte) *ChunkData {
+	// Avoid parsing all the messages (polls are very frequent), when not needed.
+	if !bytes.Contains(jsonValue, []byte(`"chunk"`)) {
+		return nil
+	}
+
+	if len(jsonValue) > maxDataSize {
+		return nil
+	}
+
+	var d Data
+	if err := dataUnmarshalOptions.Unmarshal(jsonValue, &d); err != nil || d.Type != DataType_chunk {
+		return nil
+	}
+	return d.Chunk
+}
+
+// sendChunked sends the data split in chunks, returning the reply to the last one.
+func sendChunked(pamMTx pam.ModuleTransaction, data []byte, maxSize uint32, compress bool) ([]byte, error) {
+	chunks, err := splitInChunks(data, maxSize, compress)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf(context.TODO(), "Sending %d bytes to GDM in %d chunks", len(data), len(chunks))
+	for i, chunk := range chunks {
+		chunkJSON, err := chunk.JSON()
+		if err != nil {
+			return nil, err
+		}
+		reply, err := sendToGdm(pamMTx, chunkJSON)
+		if err != nil {
+			return nil, err
+		}
+		if i == len(chunks)-1 {
+			return reply, nil
+		}
+
+		ack, err := NewDataFromJSON(reply)
+		if err != nil {
+			return nil, err
+		}
+		if ack.Type != DataType_chunkAck {
+			return nil, fmt.Errorf("gdm replied to chunk %d of %d with unexpected type: %v",
+				i, len(chunks), ack.Type)
+		}
+	}
+
+	return nil, errors.New("no chunks to send")
+}
+
+// receiveChunked gets all the chunks of a reply whose first chunk has already been received
+// and returns the reassembled data.
+func receiveChunked(pamMTx pam.ModuleTransaction, first *ChunkData) ([]byte, error) {
+	var assembler chunksAssembler
+	ackJSON, err := (&Data{Type: DataType_chunkAck}).JSON()
+	if err != nil {
+		return nil, err
+	}
+
+	chunk := first
+	for {
+		data, complete, err := assembler.add(chunk)
+		if err != nil {
+			return nil, err
+		}
+		if complete {
+			return data, nil
+		}
+
+		reply, err := sendToGdm(pamMTx, ackJSON)
+		if err != nil {
+			return nil, err
+		}
+		if chunk = parseChunk(reply); chunk == nil {
+			return nil, fmt.Errorf("%w: gdm interrupted chunked reply", ErrInvalidChunk)
+		}
+	}
+}
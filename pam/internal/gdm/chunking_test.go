@@ -0,0 +1,163 @@
+package gdm
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunksSplitAndReassemble(t *testing.T) {
+	t.Parallel()
+
+	compressible := bytes.Repeat([]byte(`{"id":"some-broker","name":"Some broker"},`), 5000)
+	random := make([]byte, 100*1024)
+	_, _ = rand.Read(random)
+
+	testCases := map[string]struct {
+		data     []byte
+		maxSize  uint32
+		compress bool
+
+		wantChunks     int
+		wantCompressed bool
+	}{
+		"Data_fitting_in_a_single_chunk": {
+			data:       []byte(`{"type":"hello"}`),
+			maxSize:    DefaultMaxPayloadSize,
+			wantChunks: 1,
+		},
+		"Data_split_in_multiple_chunks": {
+			data:       compressible,
+			maxSize:    minMaxPayloadSize,
+			wantChunks: 365,
+		},
+		"Data_compressed_before_being_split": {
+			data:           compressible,
+			maxSize:        minMaxPayloadSize,
+			compress:       true,
+			wantChunks:     2,
+			wantCompressed: true,
+		},
+		"Data_not_compressed_if_not_worth_it": {
+			data:       random,
+			maxSize:    4 * 1024,
+			compress:   true,
+			wantChunks: 36,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			chunks, err := splitInChunks(tc.data, tc.maxSize, tc.compress)
+			require.NoError(t, err, "Splitting data should not fail")
+			require.Len(t, chunks, tc.wantChunks, "Number of chunks does not match")
+
+			var assembler chunksAssembler
+			for i, chunk := range chunks {
+				require.Equal(t, tc.wantCompressed, chunk.Chunk.Compressed, "Chunk compression does not match")
+
+				chunkJSON, err := chunk.JSON()
+				require.NoError(t, err, "Chunk should be serializable")
+				require.LessOrEqual(t, len(chunkJSON), int(tc.maxSize), "Chunk exceeds the maximum size")
+				require.Equal(t, chunk.Chunk, parseChunk(chunkJSON), "Parsed chunk does not match")
+
+				data, complete, err := assembler.add(chunk.Chunk)
+				require.NoError(t, err, "Adding chunk should not fail")
+				if i < len(chunks)-1 {
+					require.False(t, complete, "Data should not be complete before the last chunk")
+					continue
+				}
+				require.True(t, complete, "Data should be complete after the last chunk")
+				require.Equal(t, tc.data, data, "Reassembled data does not match")
+			}
+		})
+	}
+}
+
+func TestChunksAssemblerErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		chunks []*ChunkData
+	}{
+		"Error_on_chunk_without_total": {
+			chunks: []*ChunkData{{Id: 1}},
+		},
+		"Error_on_chunk_index_out_of_range": {
+			chunks: []*ChunkData{{Id: 1, Index: 2, Total: 2}},
+		},
+		"Error_on_chunk_not_starting_from_first": {
+			chunks: []*ChunkData{{Id: 1, Index: 1, Total: 3}},
+		},
+		"Error_on_chunk_skipped": {
+			chunks: []*ChunkData{{Id: 1, Total: 3}, {Id: 1, Index: 2, Total: 3}},
+		},
+		"Error_on_chunk_of_different_data": {
+			chunks: []*ChunkData{{Id: 1, Total: 2}, {Id: 2, Index: 1, Total: 2}},
+		},
+		"Error_on_chunk_with_different_total": {
+			chunks: []*ChunkData{{Id: 1, Total: 2}, {Id: 1, Index: 1, Total: 3}},
+		},
+		"Error_on_invalid_compressed_data": {
+			chunks: []*ChunkData{{Id: 1, Total: 1, Compressed: true, Payload: []byte("not gzip")}},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var assembler chunksAssembler
+			var err error
+			for _, chunk := range tc.chunks {
+				if _, _, err = assembler.add(chunk); err != nil {
+					break
+				}
+			}
+			require.ErrorIs(t, err, ErrInvalidChunk, "Adding chunks should fail")
+		})
+	}
+}
+
+func TestNegotiatePayloadLimits(t *testing.T) {
+	// This test can't be parallel as it changes the global limits.
+	t.Cleanup(func() { NegotiatePayloadLimits(&HelloData{}) })
+
+	ptr := func(v uint32) *uint32 { return &v }
+	supported := true
+
+	testCases := map[string]struct {
+		peer *HelloData
+
+		wantMaxSize     uint32
+		wantCompression bool
+	}{
+		"No_chunking_if_peer_does_not_advertise_any_size": {
+			peer: &HelloData{Version: ProtoVersion, SupportsCompression: &supported},
+		},
+		"Peer_size_is_used_if_smaller": {
+			peer:        &HelloData{Version: ProtoVersion, MaxPayloadSize: ptr(4096)},
+			wantMaxSize: 4096,
+		},
+		"Default_size_is_used_if_peer_size_is_bigger": {
+			peer:            &HelloData{MaxPayloadSize: ptr(DefaultMaxPayloadSize * 2), SupportsCompression: &supported},
+			wantMaxSize:     DefaultMaxPayloadSize,
+			wantCompression: true,
+		},
+		"Minimum_size_is_used_if_peer_size_is_too_small": {
+			peer:        &HelloData{MaxPayloadSize: ptr(10)},
+			wantMaxSize: minMaxPayloadSize,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			NegotiatePayloadLimits(tc.peer)
+
+			maxSize, compression := limits.get()
+			require.Equal(t, tc.wantMaxSize, maxSize, "Maximum payload size does not match")
+			require.Equal(t, tc.wantCompression, compression, "Compression does not match")
+		})
+	}
+}
@@ -7,26 +7,113 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
-	"sync/atomic"
+	"sync"
 
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/faults"
 	"github.com/ubuntu/authd/log"
 )
 
-var conversations atomic.Int32
+var conversations = &conversationsTracker{}
 var secretRegex = regexp.MustCompile(`"secret"\s*:\s*"(?:[^"\\]|\\.)*"`)
 
 // TODO(UDENG-5844): Remove this once the auth data field has been renamed to "secret".
 var secretRegexOld = regexp.MustCompile(`"challenge"\s*:\s*"(?:[^"\\]|\\.)*"`)
 
+// ErrConversationsStopped is returned when trying to start a conversation after they have been stopped.
+var ErrConversationsStopped = errors.New("conversations with GDM have been stopped")
+
+// conversationsTracker keeps track of the conversations in progress with GDM.
+type conversationsTracker struct {
+	mu      sync.Mutex
+	count   int
+	stopped bool
+	// idle is closed once there are no more conversations in progress.
+	idle chan struct{}
+}
+
+// start registers a new conversation, unless conversations have been stopped.
+func (t *conversationsTracker) start() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stopped {
+		return ErrConversationsStopped
+	}
+	if t.count == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.count++
+	return nil
+}
+
+// done unregisters a conversation that has completed.
+func (t *conversationsTracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.count--
+	if t.count == 0 {
+		close(t.idle)
+	}
+}
+
+// inProgress returns whether there are conversations in progress.
+func (t *conversationsTracker) inProgress() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count > 0
+}
+
+// stop prevents new conversations from starting and waits for the ones in progress to complete,
+// or for the context to be done.
+func (t *conversationsTracker) stop(ctx context.Context) error {
+	t.mu.Lock()
+	t.stopped = true
+	idle := t.idle
+	inProgress := t.count > 0
+	t.mu.Unlock()
+
+	if !inProgress {
+		return nil
+	}
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// resume allows new conversations to be started again.
+func (t *conversationsTracker) resume() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = false
+}
+
 // ConversationInProgress checks if conversations are currently active.
 func ConversationInProgress() bool {
-	return conversations.Load() > 0
+	return conversations.inProgress()
+}
+
+// StopConversations prevents any further conversation with GDM from being started and waits for the
+// ones in progress to be completed. If the context is done before, its error is returned.
+func StopConversations(ctx context.Context) error {
+	return conversations.stop(ctx)
+}
+
+// ResumeConversations allows conversations with GDM to be started again after they have been stopped.
+func ResumeConversations() {
+	conversations.resume()
 }
 
 func sendToGdm(pamMTx pam.ModuleTransaction, data []byte) ([]byte, error) {
-	conversations.Add(1)
-	defer conversations.Add(-1)
+	if err := conversations.start(); err != nil {
+		return nil, err
+	}
+	defer conversations.done()
 	binReq, err := NewBinaryJSONProtoRequest(data)
 	if err != nil {
 		return nil, err
@@ -57,7 +144,21 @@ func sendData(pamMTx pam.ModuleTransaction, d *Data) ([]byte, error) {
 	if d.Type != DataType_poll {
 		log.Debugf(context.TODO(), "Sending to GDM: %s", bytes)
 	}
-	return sendToGdm(pamMTx, bytes)
+
+	var reply []byte
+	if maxSize, compress := limits.get(); maxSize > 0 && len(bytes) > int(maxSize) {
+		reply, err = sendChunked(pamMTx, bytes, maxSize, compress)
+	} else {
+		reply, err = sendToGdm(pamMTx, bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if chunk := parseChunk(reply); chunk != nil {
+		return receiveChunked(pamMTx, chunk)
+	}
+	return reply, nil
 }
 
 // SendData sends the data to the PAM Module and returns the parsed Data.
@@ -181,10 +282,23 @@ func EmitEvent(pamMTx pam.ModuleTransaction, event Event) error {
 		evType = EventType_userSelected
 	case *EventData_StartAuthentication:
 		evType = EventType_startAuthentication
+	case *EventData_NewPasswordChanged:
+		evType = EventType_newPasswordChanged
+	case *EventData_PasswordStrengthEstimated:
+		evType = EventType_passwordStrengthEstimated
+	case *EventData_AuthProgress:
+		evType = EventType_authProgress
 	default:
 		return fmt.Errorf("no known event type %#v", event)
 	}
 
+	if _, err := faults.Inject(context.TODO(), faults.GDMEvent, nil); errors.Is(err, faults.ErrDropped) {
+		log.Debugf(context.TODO(), "Dropping event %s: %v", evType, err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
 	// We don't mind checking the result content, we only care it being well formatted.
 	_, err := SendData(pamMTx, &Data{
 		Type:  DataType_event,
@@ -202,7 +316,14 @@ func EmitEvent(pamMTx pam.ModuleTransaction, event Event) error {
 // functions as gdm conversation callbacks.
 type DataConversationFunc func(*Data) (*Data, error)
 
+// incomingChunks reassembles the data received in chunks by the conversation callbacks.
+var incomingChunks = struct {
+	sync.Mutex
+	chunksAssembler
+}{}
+
 // RespondPAMBinary is a conversation callback adapter.
+// Data received in chunks is acknowledged and reassembled before being passed to the callback.
 func (f DataConversationFunc) RespondPAMBinary(ptr pam.BinaryPointer) (pam.BinaryPointer, error) {
 	json, err := decodeJSONProtoMessage(ptr)
 	if err != nil {
@@ -212,11 +333,30 @@ func (f DataConversationFunc) RespondPAMBinary(ptr pam.BinaryPointer) (pam.Binar
 	if err != nil {
 		return nil, err
 	}
+	if gdmData.Type == DataType_chunk {
+		incomingChunks.Lock()
+		json, complete, err := incomingChunks.add(gdmData.Chunk)
+		incomingChunks.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		if !complete {
+			return newBinaryData(&Data{Type: DataType_chunkAck})
+		}
+		if gdmData, err = NewDataFromJSON(json); err != nil {
+			return nil, err
+		}
+	}
 	retData, err := f(gdmData)
 	if err != nil {
 		return nil, err
 	}
-	json, err = retData.JSON()
+	return newBinaryData(retData)
+}
+
+// newBinaryData returns the binary representation of the data to be sent as conversation reply.
+func newBinaryData(d *Data) (pam.BinaryPointer, error) {
+	json, err := d.JSON()
 	if err != nil {
 		return nil, err
 	}
@@ -2,10 +2,12 @@ package gdm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/msteinert/pam/v2"
 	"github.com/stretchr/testify/require"
@@ -846,6 +848,18 @@ func TestDataEmitEvent(t *testing.T) {
 			event:         &EventData_StartAuthentication{},
 			wantEventType: EventType_startAuthentication,
 		},
+		"Emit_event_NewPasswordChanged": {
+			event:         &EventData_NewPasswordChanged{},
+			wantEventType: EventType_newPasswordChanged,
+		},
+		"Emit_event_PasswordStrengthEstimated": {
+			event:         &EventData_PasswordStrengthEstimated{},
+			wantEventType: EventType_passwordStrengthEstimated,
+		},
+		"Emit_event_AuthProgress": {
+			event:         &EventData_AuthProgress{},
+			wantEventType: EventType_authProgress,
+		},
 
 		// Error cases
 		"Error_on_nil_event": {
@@ -897,3 +911,62 @@ func TestDataEmitEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestConversationsTrackerStop(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		conversationsInProgress int
+		completeConversations   bool
+
+		wantErr error
+	}{
+		"Stops_immediately_without_conversations_in_progress": {},
+		"Stops_once_conversations_in_progress_are_completed": {
+			conversationsInProgress: 3,
+			completeConversations:   true,
+		},
+
+		// Error cases
+		"Error_if_conversations_in_progress_are_not_completed_in_time": {
+			conversationsInProgress: 1,
+			wantErr:                 context.DeadlineExceeded,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tracker := &conversationsTracker{}
+			for range tc.conversationsInProgress {
+				require.NoError(t, tracker.start(), "Setup: Starting conversation should not fail")
+			}
+			require.Equal(t, tc.conversationsInProgress > 0, tracker.inProgress(),
+				"Setup: Conversations in progress do not match")
+
+			if tc.completeConversations {
+				go func() {
+					for range tc.conversationsInProgress {
+						time.Sleep(10 * time.Millisecond)
+						tracker.done()
+					}
+				}()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			t.Cleanup(cancel)
+
+			err := tracker.stop(ctx)
+			require.ErrorIs(t, err, tc.wantErr, "Stop error does not match")
+			if tc.wantErr == nil {
+				require.False(t, tracker.inProgress(), "Conversations should not be in progress")
+			}
+
+			require.ErrorIs(t, tracker.start(), ErrConversationsStopped,
+				"Starting a conversation after stopping should fail")
+
+			tracker.resume()
+			require.NoError(t, tracker.start(), "Starting a conversation after resuming should not fail")
+		})
+	}
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.35.1
+// 	protoc-gen-go v1.36.5
 // 	protoc        v3.21.12
 // source: gdm.proto
 
@@ -13,6 +13,7 @@ import (
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -41,6 +42,10 @@ const (
 	DataType_poll DataType = 6
 	// DataType_pollResponse is a poll response DataType.
 	DataType_pollResponse DataType = 7
+	// DataType_chunk is a chunk of a larger data DataType.
+	DataType_chunk DataType = 8
+	// DataType_chunkAck is a chunk acknowledgement DataType.
+	DataType_chunkAck DataType = 9
 )
 
 // Enum value maps for DataType.
@@ -54,6 +59,8 @@ var (
 		5: "response",
 		6: "poll",
 		7: "pollResponse",
+		8: "chunk",
+		9: "chunkAck",
 	}
 	DataType_value = map[string]int32{
 		"unknownType":  0,
@@ -64,6 +71,8 @@ var (
 		"response":     5,
 		"poll":         6,
 		"pollResponse": 7,
+		"chunk":        8,
+		"chunkAck":     9,
 	}
 )
 
@@ -183,6 +192,12 @@ const (
 	EventType_isAuthenticatedCancelled EventType = 11
 	// EventType_stageChanged is stage changed EventType.
 	EventType_stageChanged EventType = 12
+	// EventType_newPasswordChanged is a new password changed EventType.
+	EventType_newPasswordChanged EventType = 13
+	// EventType_passwordStrengthEstimated is a password strength estimated EventType.
+	EventType_passwordStrengthEstimated EventType = 14
+	// EventType_authProgress is an authentication progress EventType.
+	EventType_authProgress EventType = 15
 )
 
 // Enum value maps for EventType.
@@ -201,21 +216,27 @@ var (
 		10: "isAuthenticatedRequested",
 		11: "isAuthenticatedCancelled",
 		12: "stageChanged",
+		13: "newPasswordChanged",
+		14: "passwordStrengthEstimated",
+		15: "authProgress",
 	}
 	EventType_value = map[string]int32{
-		"unknownEvent":             0,
-		"userSelected":             1,
-		"brokersReceived":          2,
-		"brokerSelected":           3,
-		"authModesReceived":        4,
-		"authModeSelected":         5,
-		"reselectAuthMode":         6,
-		"authEvent":                7,
-		"uiLayoutReceived":         8,
-		"startAuthentication":      9,
-		"isAuthenticatedRequested": 10,
-		"isAuthenticatedCancelled": 11,
-		"stageChanged":             12,
+		"unknownEvent":              0,
+		"userSelected":              1,
+		"brokersReceived":           2,
+		"brokerSelected":            3,
+		"authModesReceived":         4,
+		"authModeSelected":          5,
+		"reselectAuthMode":          6,
+		"authEvent":                 7,
+		"uiLayoutReceived":          8,
+		"startAuthentication":       9,
+		"isAuthenticatedRequested":  10,
+		"isAuthenticatedCancelled":  11,
+		"stageChanged":              12,
+		"newPasswordChanged":        13,
+		"passwordStrengthEstimated": 14,
+		"authProgress":              15,
 	}
 )
 
@@ -247,16 +268,16 @@ func (EventType) EnumDescriptor() ([]byte, []int) {
 }
 
 type Data struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          DataType               `protobuf:"varint,1,opt,name=type,proto3,enum=gdm.DataType" json:"type,omitempty"`
+	Hello         *HelloData             `protobuf:"bytes,2,opt,name=hello,proto3,oneof" json:"hello,omitempty"`
+	Request       *RequestData           `protobuf:"bytes,3,opt,name=request,proto3,oneof" json:"request,omitempty"`
+	Response      *ResponseData          `protobuf:"bytes,4,opt,name=response,proto3,oneof" json:"response,omitempty"`
+	Event         *EventData             `protobuf:"bytes,5,opt,name=event,proto3,oneof" json:"event,omitempty"`
+	PollResponse  []*EventData           `protobuf:"bytes,6,rep,name=pollResponse,proto3" json:"pollResponse,omitempty"`
+	Chunk         *ChunkData             `protobuf:"bytes,7,opt,name=chunk,proto3,oneof" json:"chunk,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Type         DataType      `protobuf:"varint,1,opt,name=type,proto3,enum=gdm.DataType" json:"type,omitempty"`
-	Hello        *HelloData    `protobuf:"bytes,2,opt,name=hello,proto3,oneof" json:"hello,omitempty"`
-	Request      *RequestData  `protobuf:"bytes,3,opt,name=request,proto3,oneof" json:"request,omitempty"`
-	Response     *ResponseData `protobuf:"bytes,4,opt,name=response,proto3,oneof" json:"response,omitempty"`
-	Event        *EventData    `protobuf:"bytes,5,opt,name=event,proto3,oneof" json:"event,omitempty"`
-	PollResponse []*EventData  `protobuf:"bytes,6,rep,name=pollResponse,proto3" json:"pollResponse,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Data) Reset() {
@@ -331,12 +352,25 @@ func (x *Data) GetPollResponse() []*EventData {
 	return nil
 }
 
+func (x *Data) GetChunk() *ChunkData {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
 type HelloData struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// maxPayloadSize is the maximum size of a message that can be handled, 0 means unlimited.
+	MaxPayloadSize *uint32 `protobuf:"varint,2,opt,name=maxPayloadSize,proto3,oneof" json:"maxPayloadSize,omitempty"`
+	// supportsCompression is set if the compressed chunks can be handled.
+	SupportsCompression *bool `protobuf:"varint,3,opt,name=supportsCompression,proto3,oneof" json:"supportsCompression,omitempty"`
+	// unlock is set by GDM when the authentication unlocks the screen of an already opened session. The broker and
+	// the authentication mode the user previously logged in with are then reused without any selection stage.
+	Unlock        *bool `protobuf:"varint,4,opt,name=unlock,proto3,oneof" json:"unlock,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *HelloData) Reset() {
@@ -376,15 +410,116 @@ func (x *HelloData) GetVersion() uint32 {
 	return 0
 }
 
-type Requests struct {
-	state         protoimpl.MessageState
+func (x *HelloData) GetMaxPayloadSize() uint32 {
+	if x != nil && x.MaxPayloadSize != nil {
+		return *x.MaxPayloadSize
+	}
+	return 0
+}
+
+func (x *HelloData) GetSupportsCompression() bool {
+	if x != nil && x.SupportsCompression != nil {
+		return *x.SupportsCompression
+	}
+	return false
+}
+
+func (x *HelloData) GetUnlock() bool {
+	if x != nil && x.Unlock != nil {
+		return *x.Unlock
+	}
+	return false
+}
+
+type ChunkData struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id identifies the data that the chunk is part of.
+	Id uint32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// index is the position of the chunk in the data, starting from 0.
+	Index uint32 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	// total is the number of chunks the data is split into.
+	Total uint32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	// compressed is set if the data has been gzip compressed before being split.
+	Compressed    bool   `protobuf:"varint,4,opt,name=compressed,proto3" json:"compressed,omitempty"`
+	Payload       []byte `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChunkData) Reset() {
+	*x = ChunkData{}
+	mi := &file_gdm_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkData) ProtoMessage() {}
+
+func (x *ChunkData) ProtoReflect() protoreflect.Message {
+	mi := &file_gdm_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkData.ProtoReflect.Descriptor instead.
+func (*ChunkData) Descriptor() ([]byte, []int) {
+	return file_gdm_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChunkData) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ChunkData) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ChunkData) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ChunkData) GetCompressed() bool {
+	if x != nil {
+		return x.Compressed
+	}
+	return false
+}
+
+func (x *ChunkData) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type Requests struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Requests) Reset() {
 	*x = Requests{}
-	mi := &file_gdm_proto_msgTypes[2]
+	mi := &file_gdm_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -396,7 +531,7 @@ func (x *Requests) String() string {
 func (*Requests) ProtoMessage() {}
 
 func (x *Requests) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[2]
+	mi := &file_gdm_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -409,25 +544,24 @@ func (x *Requests) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Requests.ProtoReflect.Descriptor instead.
 func (*Requests) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{2}
+	return file_gdm_proto_rawDescGZIP(), []int{3}
 }
 
 type RequestData struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Type RequestType `protobuf:"varint,1,opt,name=type,proto3,enum=gdm.RequestType" json:"type,omitempty"`
-	// Types that are assignable to Data:
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  RequestType            `protobuf:"varint,1,opt,name=type,proto3,enum=gdm.RequestType" json:"type,omitempty"`
+	// Types that are valid to be assigned to Data:
 	//
 	//	*RequestData_UiLayoutCapabilities
 	//	*RequestData_ChangeStage
-	Data isRequestData_Data `protobuf_oneof:"data"`
+	Data          isRequestData_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RequestData) Reset() {
 	*x = RequestData{}
-	mi := &file_gdm_proto_msgTypes[3]
+	mi := &file_gdm_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -439,7 +573,7 @@ func (x *RequestData) String() string {
 func (*RequestData) ProtoMessage() {}
 
 func (x *RequestData) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[3]
+	mi := &file_gdm_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -452,7 +586,7 @@ func (x *RequestData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestData.ProtoReflect.Descriptor instead.
 func (*RequestData) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{3}
+	return file_gdm_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *RequestData) GetType() RequestType {
@@ -462,23 +596,27 @@ func (x *RequestData) GetType() RequestType {
 	return RequestType_unknownRequest
 }
 
-func (m *RequestData) GetData() isRequestData_Data {
-	if m != nil {
-		return m.Data
+func (x *RequestData) GetData() isRequestData_Data {
+	if x != nil {
+		return x.Data
 	}
 	return nil
 }
 
 func (x *RequestData) GetUiLayoutCapabilities() *Requests_UiLayoutCapabilities {
-	if x, ok := x.GetData().(*RequestData_UiLayoutCapabilities); ok {
-		return x.UiLayoutCapabilities
+	if x != nil {
+		if x, ok := x.Data.(*RequestData_UiLayoutCapabilities); ok {
+			return x.UiLayoutCapabilities
+		}
 	}
 	return nil
 }
 
 func (x *RequestData) GetChangeStage() *Requests_ChangeStage {
-	if x, ok := x.GetData().(*RequestData_ChangeStage); ok {
-		return x.ChangeStage
+	if x != nil {
+		if x, ok := x.Data.(*RequestData_ChangeStage); ok {
+			return x.ChangeStage
+		}
 	}
 	return nil
 }
@@ -500,14 +638,14 @@ func (*RequestData_UiLayoutCapabilities) isRequestData_Data() {}
 func (*RequestData_ChangeStage) isRequestData_Data() {}
 
 type Responses struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Responses) Reset() {
 	*x = Responses{}
-	mi := &file_gdm_proto_msgTypes[4]
+	mi := &file_gdm_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -519,7 +657,7 @@ func (x *Responses) String() string {
 func (*Responses) ProtoMessage() {}
 
 func (x *Responses) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[4]
+	mi := &file_gdm_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -532,25 +670,24 @@ func (x *Responses) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Responses.ProtoReflect.Descriptor instead.
 func (*Responses) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{4}
+	return file_gdm_proto_rawDescGZIP(), []int{5}
 }
 
 type ResponseData struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Type RequestType `protobuf:"varint,1,opt,name=type,proto3,enum=gdm.RequestType" json:"type,omitempty"`
-	// Types that are assignable to Data:
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  RequestType            `protobuf:"varint,1,opt,name=type,proto3,enum=gdm.RequestType" json:"type,omitempty"`
+	// Types that are valid to be assigned to Data:
 	//
 	//	*ResponseData_Ack
 	//	*ResponseData_UiLayoutCapabilities
-	Data isResponseData_Data `protobuf_oneof:"data"`
+	Data          isResponseData_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ResponseData) Reset() {
 	*x = ResponseData{}
-	mi := &file_gdm_proto_msgTypes[5]
+	mi := &file_gdm_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -562,7 +699,7 @@ func (x *ResponseData) String() string {
 func (*ResponseData) ProtoMessage() {}
 
 func (x *ResponseData) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[5]
+	mi := &file_gdm_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -575,7 +712,7 @@ func (x *ResponseData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResponseData.ProtoReflect.Descriptor instead.
 func (*ResponseData) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{5}
+	return file_gdm_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ResponseData) GetType() RequestType {
@@ -585,23 +722,27 @@ func (x *ResponseData) GetType() RequestType {
 	return RequestType_unknownRequest
 }
 
-func (m *ResponseData) GetData() isResponseData_Data {
-	if m != nil {
-		return m.Data
+func (x *ResponseData) GetData() isResponseData_Data {
+	if x != nil {
+		return x.Data
 	}
 	return nil
 }
 
 func (x *ResponseData) GetAck() *Responses_Ack {
-	if x, ok := x.GetData().(*ResponseData_Ack); ok {
-		return x.Ack
+	if x != nil {
+		if x, ok := x.Data.(*ResponseData_Ack); ok {
+			return x.Ack
+		}
 	}
 	return nil
 }
 
 func (x *ResponseData) GetUiLayoutCapabilities() *Responses_UiLayoutCapabilities {
-	if x, ok := x.GetData().(*ResponseData_UiLayoutCapabilities); ok {
-		return x.UiLayoutCapabilities
+	if x != nil {
+		if x, ok := x.Data.(*ResponseData_UiLayoutCapabilities); ok {
+			return x.UiLayoutCapabilities
+		}
 	}
 	return nil
 }
@@ -623,14 +764,14 @@ func (*ResponseData_Ack) isResponseData_Data() {}
 func (*ResponseData_UiLayoutCapabilities) isResponseData_Data() {}
 
 type Events struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events) Reset() {
 	*x = Events{}
-	mi := &file_gdm_proto_msgTypes[6]
+	mi := &file_gdm_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -642,7 +783,7 @@ func (x *Events) String() string {
 func (*Events) ProtoMessage() {}
 
 func (x *Events) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[6]
+	mi := &file_gdm_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -655,16 +796,13 @@ func (x *Events) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events.ProtoReflect.Descriptor instead.
 func (*Events) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6}
+	return file_gdm_proto_rawDescGZIP(), []int{7}
 }
 
 type EventData struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Type EventType `protobuf:"varint,1,opt,name=type,proto3,enum=gdm.EventType" json:"type,omitempty"`
-	// Types that are assignable to Data:
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=gdm.EventType" json:"type,omitempty"`
+	// Types that are valid to be assigned to Data:
 	//
 	//	*EventData_BrokersReceived
 	//	*EventData_BrokerSelected
@@ -678,12 +816,17 @@ type EventData struct {
 	//	*EventData_StartAuthentication
 	//	*EventData_UserSelected
 	//	*EventData_IsAuthenticatedCancelled
-	Data isEventData_Data `protobuf_oneof:"data"`
+	//	*EventData_NewPasswordChanged
+	//	*EventData_PasswordStrengthEstimated
+	//	*EventData_AuthProgress
+	Data          isEventData_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *EventData) Reset() {
 	*x = EventData{}
-	mi := &file_gdm_proto_msgTypes[7]
+	mi := &file_gdm_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -695,7 +838,7 @@ func (x *EventData) String() string {
 func (*EventData) ProtoMessage() {}
 
 func (x *EventData) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[7]
+	mi := &file_gdm_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -708,7 +851,7 @@ func (x *EventData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EventData.ProtoReflect.Descriptor instead.
 func (*EventData) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{7}
+	return file_gdm_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *EventData) GetType() EventType {
@@ -718,93 +861,144 @@ func (x *EventData) GetType() EventType {
 	return EventType_unknownEvent
 }
 
-func (m *EventData) GetData() isEventData_Data {
-	if m != nil {
-		return m.Data
+func (x *EventData) GetData() isEventData_Data {
+	if x != nil {
+		return x.Data
 	}
 	return nil
 }
 
 func (x *EventData) GetBrokersReceived() *Events_BrokersReceived {
-	if x, ok := x.GetData().(*EventData_BrokersReceived); ok {
-		return x.BrokersReceived
+	if x != nil {
+		if x, ok := x.Data.(*EventData_BrokersReceived); ok {
+			return x.BrokersReceived
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetBrokerSelected() *Events_BrokerSelected {
-	if x, ok := x.GetData().(*EventData_BrokerSelected); ok {
-		return x.BrokerSelected
+	if x != nil {
+		if x, ok := x.Data.(*EventData_BrokerSelected); ok {
+			return x.BrokerSelected
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetAuthModesReceived() *Events_AuthModesReceived {
-	if x, ok := x.GetData().(*EventData_AuthModesReceived); ok {
-		return x.AuthModesReceived
+	if x != nil {
+		if x, ok := x.Data.(*EventData_AuthModesReceived); ok {
+			return x.AuthModesReceived
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetAuthModeSelected() *Events_AuthModeSelected {
-	if x, ok := x.GetData().(*EventData_AuthModeSelected); ok {
-		return x.AuthModeSelected
+	if x != nil {
+		if x, ok := x.Data.(*EventData_AuthModeSelected); ok {
+			return x.AuthModeSelected
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetIsAuthenticatedRequested() *Events_IsAuthenticatedRequested {
-	if x, ok := x.GetData().(*EventData_IsAuthenticatedRequested); ok {
-		return x.IsAuthenticatedRequested
+	if x != nil {
+		if x, ok := x.Data.(*EventData_IsAuthenticatedRequested); ok {
+			return x.IsAuthenticatedRequested
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetStageChanged() *Events_StageChanged {
-	if x, ok := x.GetData().(*EventData_StageChanged); ok {
-		return x.StageChanged
+	if x != nil {
+		if x, ok := x.Data.(*EventData_StageChanged); ok {
+			return x.StageChanged
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetUiLayoutReceived() *Events_UiLayoutReceived {
-	if x, ok := x.GetData().(*EventData_UiLayoutReceived); ok {
-		return x.UiLayoutReceived
+	if x != nil {
+		if x, ok := x.Data.(*EventData_UiLayoutReceived); ok {
+			return x.UiLayoutReceived
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetAuthEvent() *Events_AuthEvent {
-	if x, ok := x.GetData().(*EventData_AuthEvent); ok {
-		return x.AuthEvent
+	if x != nil {
+		if x, ok := x.Data.(*EventData_AuthEvent); ok {
+			return x.AuthEvent
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetReselectAuthMode() *Events_ReselectAuthMode {
-	if x, ok := x.GetData().(*EventData_ReselectAuthMode); ok {
-		return x.ReselectAuthMode
+	if x != nil {
+		if x, ok := x.Data.(*EventData_ReselectAuthMode); ok {
+			return x.ReselectAuthMode
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetStartAuthentication() *Events_StartAuthentication {
-	if x, ok := x.GetData().(*EventData_StartAuthentication); ok {
-		return x.StartAuthentication
+	if x != nil {
+		if x, ok := x.Data.(*EventData_StartAuthentication); ok {
+			return x.StartAuthentication
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetUserSelected() *Events_UserSelected {
-	if x, ok := x.GetData().(*EventData_UserSelected); ok {
-		return x.UserSelected
+	if x != nil {
+		if x, ok := x.Data.(*EventData_UserSelected); ok {
+			return x.UserSelected
+		}
 	}
 	return nil
 }
 
 func (x *EventData) GetIsAuthenticatedCancelled() *Events_IsAuthenticatedCancelled {
-	if x, ok := x.GetData().(*EventData_IsAuthenticatedCancelled); ok {
-		return x.IsAuthenticatedCancelled
+	if x != nil {
+		if x, ok := x.Data.(*EventData_IsAuthenticatedCancelled); ok {
+			return x.IsAuthenticatedCancelled
+		}
+	}
+	return nil
+}
+
+func (x *EventData) GetNewPasswordChanged() *Events_NewPasswordChanged {
+	if x != nil {
+		if x, ok := x.Data.(*EventData_NewPasswordChanged); ok {
+			return x.NewPasswordChanged
+		}
+	}
+	return nil
+}
+
+func (x *EventData) GetPasswordStrengthEstimated() *Events_PasswordStrengthEstimated {
+	if x != nil {
+		if x, ok := x.Data.(*EventData_PasswordStrengthEstimated); ok {
+			return x.PasswordStrengthEstimated
+		}
+	}
+	return nil
+}
+
+func (x *EventData) GetAuthProgress() *Events_AuthProgress {
+	if x != nil {
+		if x, ok := x.Data.(*EventData_AuthProgress); ok {
+			return x.AuthProgress
+		}
 	}
 	return nil
 }
@@ -861,6 +1055,18 @@ type EventData_IsAuthenticatedCancelled struct {
 	IsAuthenticatedCancelled *Events_IsAuthenticatedCancelled `protobuf:"bytes,21,opt,name=isAuthenticatedCancelled,proto3,oneof"`
 }
 
+type EventData_NewPasswordChanged struct {
+	NewPasswordChanged *Events_NewPasswordChanged `protobuf:"bytes,22,opt,name=newPasswordChanged,proto3,oneof"`
+}
+
+type EventData_PasswordStrengthEstimated struct {
+	PasswordStrengthEstimated *Events_PasswordStrengthEstimated `protobuf:"bytes,23,opt,name=passwordStrengthEstimated,proto3,oneof"`
+}
+
+type EventData_AuthProgress struct {
+	AuthProgress *Events_AuthProgress `protobuf:"bytes,24,opt,name=authProgress,proto3,oneof"`
+}
+
 func (*EventData_BrokersReceived) isEventData_Data() {}
 
 func (*EventData_BrokerSelected) isEventData_Data() {}
@@ -885,15 +1091,21 @@ func (*EventData_UserSelected) isEventData_Data() {}
 
 func (*EventData_IsAuthenticatedCancelled) isEventData_Data() {}
 
+func (*EventData_NewPasswordChanged) isEventData_Data() {}
+
+func (*EventData_PasswordStrengthEstimated) isEventData_Data() {}
+
+func (*EventData_AuthProgress) isEventData_Data() {}
+
 type Requests_UiLayoutCapabilities struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Requests_UiLayoutCapabilities) Reset() {
 	*x = Requests_UiLayoutCapabilities{}
-	mi := &file_gdm_proto_msgTypes[8]
+	mi := &file_gdm_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -905,7 +1117,7 @@ func (x *Requests_UiLayoutCapabilities) String() string {
 func (*Requests_UiLayoutCapabilities) ProtoMessage() {}
 
 func (x *Requests_UiLayoutCapabilities) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[8]
+	mi := &file_gdm_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -918,20 +1130,19 @@ func (x *Requests_UiLayoutCapabilities) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Requests_UiLayoutCapabilities.ProtoReflect.Descriptor instead.
 func (*Requests_UiLayoutCapabilities) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{2, 0}
+	return file_gdm_proto_rawDescGZIP(), []int{3, 0}
 }
 
 type Requests_ChangeStage struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         proto.Stage            `protobuf:"varint,1,opt,name=stage,proto3,enum=pam.Stage" json:"stage,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Stage proto.Stage `protobuf:"varint,1,opt,name=stage,proto3,enum=pam.Stage" json:"stage,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Requests_ChangeStage) Reset() {
 	*x = Requests_ChangeStage{}
-	mi := &file_gdm_proto_msgTypes[9]
+	mi := &file_gdm_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -943,7 +1154,7 @@ func (x *Requests_ChangeStage) String() string {
 func (*Requests_ChangeStage) ProtoMessage() {}
 
 func (x *Requests_ChangeStage) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[9]
+	mi := &file_gdm_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -956,7 +1167,7 @@ func (x *Requests_ChangeStage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Requests_ChangeStage.ProtoReflect.Descriptor instead.
 func (*Requests_ChangeStage) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{2, 1}
+	return file_gdm_proto_rawDescGZIP(), []int{3, 1}
 }
 
 func (x *Requests_ChangeStage) GetStage() proto.Stage {
@@ -967,14 +1178,14 @@ func (x *Requests_ChangeStage) GetStage() proto.Stage {
 }
 
 type Responses_Ack struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Responses_Ack) Reset() {
 	*x = Responses_Ack{}
-	mi := &file_gdm_proto_msgTypes[10]
+	mi := &file_gdm_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -986,7 +1197,7 @@ func (x *Responses_Ack) String() string {
 func (*Responses_Ack) ProtoMessage() {}
 
 func (x *Responses_Ack) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[10]
+	mi := &file_gdm_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -999,20 +1210,19 @@ func (x *Responses_Ack) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Responses_Ack.ProtoReflect.Descriptor instead.
 func (*Responses_Ack) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{4, 0}
+	return file_gdm_proto_rawDescGZIP(), []int{5, 0}
 }
 
 type Responses_UiLayoutCapabilities struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	SupportedUiLayouts []*authd.UILayout `protobuf:"bytes,10,rep,name=supportedUiLayouts,proto3" json:"supportedUiLayouts,omitempty"`
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	SupportedUiLayouts []*authd.UILayout      `protobuf:"bytes,10,rep,name=supportedUiLayouts,proto3" json:"supportedUiLayouts,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *Responses_UiLayoutCapabilities) Reset() {
 	*x = Responses_UiLayoutCapabilities{}
-	mi := &file_gdm_proto_msgTypes[11]
+	mi := &file_gdm_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1024,7 +1234,7 @@ func (x *Responses_UiLayoutCapabilities) String() string {
 func (*Responses_UiLayoutCapabilities) ProtoMessage() {}
 
 func (x *Responses_UiLayoutCapabilities) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[11]
+	mi := &file_gdm_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1037,7 +1247,7 @@ func (x *Responses_UiLayoutCapabilities) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Responses_UiLayoutCapabilities.ProtoReflect.Descriptor instead.
 func (*Responses_UiLayoutCapabilities) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{4, 1}
+	return file_gdm_proto_rawDescGZIP(), []int{5, 1}
 }
 
 func (x *Responses_UiLayoutCapabilities) GetSupportedUiLayouts() []*authd.UILayout {
@@ -1048,16 +1258,15 @@ func (x *Responses_UiLayoutCapabilities) GetSupportedUiLayouts() []*authd.UILayo
 }
 
 type Events_BrokersReceived struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	BrokersInfos  []*authd.ABResponse_BrokerInfo `protobuf:"bytes,1,rep,name=brokersInfos,proto3" json:"brokersInfos,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	BrokersInfos []*authd.ABResponse_BrokerInfo `protobuf:"bytes,1,rep,name=brokersInfos,proto3" json:"brokersInfos,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_BrokersReceived) Reset() {
 	*x = Events_BrokersReceived{}
-	mi := &file_gdm_proto_msgTypes[12]
+	mi := &file_gdm_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1069,7 +1278,7 @@ func (x *Events_BrokersReceived) String() string {
 func (*Events_BrokersReceived) ProtoMessage() {}
 
 func (x *Events_BrokersReceived) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[12]
+	mi := &file_gdm_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1082,7 +1291,7 @@ func (x *Events_BrokersReceived) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_BrokersReceived.ProtoReflect.Descriptor instead.
 func (*Events_BrokersReceived) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 0}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 0}
 }
 
 func (x *Events_BrokersReceived) GetBrokersInfos() []*authd.ABResponse_BrokerInfo {
@@ -1093,16 +1302,15 @@ func (x *Events_BrokersReceived) GetBrokersInfos() []*authd.ABResponse_BrokerInf
 }
 
 type Events_BrokerSelected struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BrokerId      string                 `protobuf:"bytes,1,opt,name=brokerId,proto3" json:"brokerId,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	BrokerId string `protobuf:"bytes,1,opt,name=brokerId,proto3" json:"brokerId,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_BrokerSelected) Reset() {
 	*x = Events_BrokerSelected{}
-	mi := &file_gdm_proto_msgTypes[13]
+	mi := &file_gdm_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1114,7 +1322,7 @@ func (x *Events_BrokerSelected) String() string {
 func (*Events_BrokerSelected) ProtoMessage() {}
 
 func (x *Events_BrokerSelected) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[13]
+	mi := &file_gdm_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1127,7 +1335,7 @@ func (x *Events_BrokerSelected) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_BrokerSelected.ProtoReflect.Descriptor instead.
 func (*Events_BrokerSelected) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 1}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 1}
 }
 
 func (x *Events_BrokerSelected) GetBrokerId() string {
@@ -1138,16 +1346,15 @@ func (x *Events_BrokerSelected) GetBrokerId() string {
 }
 
 type Events_UserSelected struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	UserId string `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_UserSelected) Reset() {
 	*x = Events_UserSelected{}
-	mi := &file_gdm_proto_msgTypes[14]
+	mi := &file_gdm_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1159,7 +1366,7 @@ func (x *Events_UserSelected) String() string {
 func (*Events_UserSelected) ProtoMessage() {}
 
 func (x *Events_UserSelected) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[14]
+	mi := &file_gdm_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1172,7 +1379,7 @@ func (x *Events_UserSelected) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_UserSelected.ProtoReflect.Descriptor instead.
 func (*Events_UserSelected) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 2}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 2}
 }
 
 func (x *Events_UserSelected) GetUserId() string {
@@ -1183,14 +1390,14 @@ func (x *Events_UserSelected) GetUserId() string {
 }
 
 type Events_StartAuthentication struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_StartAuthentication) Reset() {
 	*x = Events_StartAuthentication{}
-	mi := &file_gdm_proto_msgTypes[15]
+	mi := &file_gdm_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1202,7 +1409,7 @@ func (x *Events_StartAuthentication) String() string {
 func (*Events_StartAuthentication) ProtoMessage() {}
 
 func (x *Events_StartAuthentication) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[15]
+	mi := &file_gdm_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1215,20 +1422,19 @@ func (x *Events_StartAuthentication) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_StartAuthentication.ProtoReflect.Descriptor instead.
 func (*Events_StartAuthentication) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 3}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 3}
 }
 
 type Events_AuthModesReceived struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState                  `protogen:"open.v1"`
+	AuthModes     []*authd.GAMResponse_AuthenticationMode `protobuf:"bytes,1,rep,name=authModes,proto3" json:"authModes,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	AuthModes []*authd.GAMResponse_AuthenticationMode `protobuf:"bytes,1,rep,name=authModes,proto3" json:"authModes,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_AuthModesReceived) Reset() {
 	*x = Events_AuthModesReceived{}
-	mi := &file_gdm_proto_msgTypes[16]
+	mi := &file_gdm_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1240,7 +1446,7 @@ func (x *Events_AuthModesReceived) String() string {
 func (*Events_AuthModesReceived) ProtoMessage() {}
 
 func (x *Events_AuthModesReceived) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[16]
+	mi := &file_gdm_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1253,7 +1459,7 @@ func (x *Events_AuthModesReceived) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_AuthModesReceived.ProtoReflect.Descriptor instead.
 func (*Events_AuthModesReceived) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 4}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 4}
 }
 
 func (x *Events_AuthModesReceived) GetAuthModes() []*authd.GAMResponse_AuthenticationMode {
@@ -1264,16 +1470,15 @@ func (x *Events_AuthModesReceived) GetAuthModes() []*authd.GAMResponse_Authentic
 }
 
 type Events_AuthModeSelected struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AuthModeId    string                 `protobuf:"bytes,1,opt,name=authModeId,proto3" json:"authModeId,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	AuthModeId string `protobuf:"bytes,1,opt,name=authModeId,proto3" json:"authModeId,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_AuthModeSelected) Reset() {
 	*x = Events_AuthModeSelected{}
-	mi := &file_gdm_proto_msgTypes[17]
+	mi := &file_gdm_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1285,7 +1490,7 @@ func (x *Events_AuthModeSelected) String() string {
 func (*Events_AuthModeSelected) ProtoMessage() {}
 
 func (x *Events_AuthModeSelected) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[17]
+	mi := &file_gdm_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1298,7 +1503,7 @@ func (x *Events_AuthModeSelected) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_AuthModeSelected.ProtoReflect.Descriptor instead.
 func (*Events_AuthModeSelected) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 5}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 5}
 }
 
 func (x *Events_AuthModeSelected) GetAuthModeId() string {
@@ -1309,16 +1514,15 @@ func (x *Events_AuthModeSelected) GetAuthModeId() string {
 }
 
 type Events_AuthEvent struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Response      *authd.IAResponse      `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Response *authd.IAResponse `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_AuthEvent) Reset() {
 	*x = Events_AuthEvent{}
-	mi := &file_gdm_proto_msgTypes[18]
+	mi := &file_gdm_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1330,7 +1534,7 @@ func (x *Events_AuthEvent) String() string {
 func (*Events_AuthEvent) ProtoMessage() {}
 
 func (x *Events_AuthEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[18]
+	mi := &file_gdm_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1343,7 +1547,7 @@ func (x *Events_AuthEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_AuthEvent.ProtoReflect.Descriptor instead.
 func (*Events_AuthEvent) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 6}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 6}
 }
 
 func (x *Events_AuthEvent) GetResponse() *authd.IAResponse {
@@ -1354,14 +1558,14 @@ func (x *Events_AuthEvent) GetResponse() *authd.IAResponse {
 }
 
 type Events_ReselectAuthMode struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_ReselectAuthMode) Reset() {
 	*x = Events_ReselectAuthMode{}
-	mi := &file_gdm_proto_msgTypes[19]
+	mi := &file_gdm_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1373,7 +1577,7 @@ func (x *Events_ReselectAuthMode) String() string {
 func (*Events_ReselectAuthMode) ProtoMessage() {}
 
 func (x *Events_ReselectAuthMode) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[19]
+	mi := &file_gdm_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1386,20 +1590,19 @@ func (x *Events_ReselectAuthMode) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_ReselectAuthMode.ProtoReflect.Descriptor instead.
 func (*Events_ReselectAuthMode) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 7}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 7}
 }
 
 type Events_IsAuthenticatedRequested struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state              protoimpl.MessageState              `protogen:"open.v1"`
 	AuthenticationData *authd.IARequest_AuthenticationData `protobuf:"bytes,1,opt,name=authentication_data,json=authenticationData,proto3" json:"authentication_data,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *Events_IsAuthenticatedRequested) Reset() {
 	*x = Events_IsAuthenticatedRequested{}
-	mi := &file_gdm_proto_msgTypes[20]
+	mi := &file_gdm_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1411,7 +1614,7 @@ func (x *Events_IsAuthenticatedRequested) String() string {
 func (*Events_IsAuthenticatedRequested) ProtoMessage() {}
 
 func (x *Events_IsAuthenticatedRequested) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[20]
+	mi := &file_gdm_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1424,7 +1627,7 @@ func (x *Events_IsAuthenticatedRequested) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_IsAuthenticatedRequested.ProtoReflect.Descriptor instead.
 func (*Events_IsAuthenticatedRequested) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 8}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 8}
 }
 
 func (x *Events_IsAuthenticatedRequested) GetAuthenticationData() *authd.IARequest_AuthenticationData {
@@ -1435,14 +1638,14 @@ func (x *Events_IsAuthenticatedRequested) GetAuthenticationData() *authd.IAReque
 }
 
 type Events_IsAuthenticatedCancelled struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_IsAuthenticatedCancelled) Reset() {
 	*x = Events_IsAuthenticatedCancelled{}
-	mi := &file_gdm_proto_msgTypes[21]
+	mi := &file_gdm_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1454,7 +1657,7 @@ func (x *Events_IsAuthenticatedCancelled) String() string {
 func (*Events_IsAuthenticatedCancelled) ProtoMessage() {}
 
 func (x *Events_IsAuthenticatedCancelled) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[21]
+	mi := &file_gdm_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1467,20 +1670,19 @@ func (x *Events_IsAuthenticatedCancelled) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_IsAuthenticatedCancelled.ProtoReflect.Descriptor instead.
 func (*Events_IsAuthenticatedCancelled) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 9}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 9}
 }
 
 type Events_StageChanged struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         proto.Stage            `protobuf:"varint,1,opt,name=stage,proto3,enum=pam.Stage" json:"stage,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Stage proto.Stage `protobuf:"varint,1,opt,name=stage,proto3,enum=pam.Stage" json:"stage,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_StageChanged) Reset() {
 	*x = Events_StageChanged{}
-	mi := &file_gdm_proto_msgTypes[22]
+	mi := &file_gdm_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1492,7 +1694,7 @@ func (x *Events_StageChanged) String() string {
 func (*Events_StageChanged) ProtoMessage() {}
 
 func (x *Events_StageChanged) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[22]
+	mi := &file_gdm_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1505,7 +1707,7 @@ func (x *Events_StageChanged) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_StageChanged.ProtoReflect.Descriptor instead.
 func (*Events_StageChanged) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 10}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 10}
 }
 
 func (x *Events_StageChanged) GetStage() proto.Stage {
@@ -1516,16 +1718,15 @@ func (x *Events_StageChanged) GetStage() proto.Stage {
 }
 
 type Events_UiLayoutReceived struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UiLayout      *authd.UILayout        `protobuf:"bytes,1,opt,name=uiLayout,proto3" json:"uiLayout,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	UiLayout *authd.UILayout `protobuf:"bytes,1,opt,name=uiLayout,proto3" json:"uiLayout,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Events_UiLayoutReceived) Reset() {
 	*x = Events_UiLayoutReceived{}
-	mi := &file_gdm_proto_msgTypes[23]
+	mi := &file_gdm_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1537,7 +1738,7 @@ func (x *Events_UiLayoutReceived) String() string {
 func (*Events_UiLayoutReceived) ProtoMessage() {}
 
 func (x *Events_UiLayoutReceived) ProtoReflect() protoreflect.Message {
-	mi := &file_gdm_proto_msgTypes[23]
+	mi := &file_gdm_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1550,7 +1751,7 @@ func (x *Events_UiLayoutReceived) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events_UiLayoutReceived.ProtoReflect.Descriptor instead.
 func (*Events_UiLayoutReceived) Descriptor() ([]byte, []int) {
-	return file_gdm_proto_rawDescGZIP(), []int{6, 11}
+	return file_gdm_proto_rawDescGZIP(), []int{7, 11}
 }
 
 func (x *Events_UiLayoutReceived) GetUiLayout() *authd.UILayout {
@@ -1560,12 +1761,183 @@ func (x *Events_UiLayoutReceived) GetUiLayout() *authd.UILayout {
 	return nil
 }
 
+// NewPasswordChanged is sent by GDM while the user types their new password, to show its strength.
+type Events_NewPasswordChanged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Password      string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Events_NewPasswordChanged) Reset() {
+	*x = Events_NewPasswordChanged{}
+	mi := &file_gdm_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Events_NewPasswordChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Events_NewPasswordChanged) ProtoMessage() {}
+
+func (x *Events_NewPasswordChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_gdm_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Events_NewPasswordChanged.ProtoReflect.Descriptor instead.
+func (*Events_NewPasswordChanged) Descriptor() ([]byte, []int) {
+	return file_gdm_proto_rawDescGZIP(), []int{7, 12}
+}
+
+func (x *Events_NewPasswordChanged) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// PasswordStrengthEstimated is the strength, from 0 (very weak) to 4 (very strong), of the new password.
+type Events_PasswordStrengthEstimated struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Score uint32                 `protobuf:"varint,1,opt,name=score,proto3" json:"score,omitempty"`
+	// minScore is the minimum strength required by the broker to submit the password.
+	MinScore uint32 `protobuf:"varint,2,opt,name=minScore,proto3" json:"minScore,omitempty"`
+	// label is the name of the score to show to the user.
+	Label         string `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Events_PasswordStrengthEstimated) Reset() {
+	*x = Events_PasswordStrengthEstimated{}
+	mi := &file_gdm_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Events_PasswordStrengthEstimated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Events_PasswordStrengthEstimated) ProtoMessage() {}
+
+func (x *Events_PasswordStrengthEstimated) ProtoReflect() protoreflect.Message {
+	mi := &file_gdm_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Events_PasswordStrengthEstimated.ProtoReflect.Descriptor instead.
+func (*Events_PasswordStrengthEstimated) Descriptor() ([]byte, []int) {
+	return file_gdm_proto_rawDescGZIP(), []int{7, 13}
+}
+
+func (x *Events_PasswordStrengthEstimated) GetScore() uint32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Events_PasswordStrengthEstimated) GetMinScore() uint32 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+func (x *Events_PasswordStrengthEstimated) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+// AuthProgress is the progress reported by the broker during a long authentication.
+type Events_AuthProgress struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// percentage is the completion of the authentication, from 0 to 100, if it's known.
+	Percentage *uint32 `protobuf:"varint,2,opt,name=percentage,proto3,oneof" json:"percentage,omitempty"`
+	// cancellable is set if the user can cancel the authentication.
+	Cancellable   bool `protobuf:"varint,3,opt,name=cancellable,proto3" json:"cancellable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Events_AuthProgress) Reset() {
+	*x = Events_AuthProgress{}
+	mi := &file_gdm_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Events_AuthProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Events_AuthProgress) ProtoMessage() {}
+
+func (x *Events_AuthProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_gdm_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Events_AuthProgress.ProtoReflect.Descriptor instead.
+func (*Events_AuthProgress) Descriptor() ([]byte, []int) {
+	return file_gdm_proto_rawDescGZIP(), []int{7, 14}
+}
+
+func (x *Events_AuthProgress) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Events_AuthProgress) GetPercentage() uint32 {
+	if x != nil && x.Percentage != nil {
+		return *x.Percentage
+	}
+	return 0
+}
+
+func (x *Events_AuthProgress) GetCancellable() bool {
+	if x != nil {
+		return x.Cancellable
+	}
+	return false
+}
+
 var File_gdm_proto protoreflect.FileDescriptor
 
-var file_gdm_proto_rawDesc = []byte{
+var file_gdm_proto_rawDesc = string([]byte{
 	0x0a, 0x09, 0x67, 0x64, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x67, 0x64, 0x6d,
 	0x1a, 0x0b, 0x61, 0x75, 0x74, 0x68, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x09, 0x70,
-	0x61, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc5, 0x02, 0x0a, 0x04, 0x44, 0x61, 0x74,
+	0x61, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xfa, 0x02, 0x0a, 0x04, 0x44, 0x61, 0x74,
 	0x61, 0x12, 0x21, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
 	0x0d, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
 	0x74, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x18, 0x02, 0x20,
@@ -1583,280 +1955,351 @@ var file_gdm_proto_rawDesc = []byte{
 	0x32, 0x0a, 0x0c, 0x70, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18,
 	0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e,
 	0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x0c, 0x70, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x42, 0x0a, 0x0a,
-	0x08, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x72, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
-	0x22, 0x25, 0x0a, 0x09, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x12, 0x18, 0x0a,
-	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07,
-	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x53, 0x0a, 0x08, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x73, 0x1a, 0x16, 0x0a, 0x14, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43,
-	0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x1a, 0x2f, 0x0a, 0x0b, 0x43,
-	0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x05, 0x73, 0x74,
-	0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x70, 0x61, 0x6d, 0x2e,
-	0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x22, 0xd4, 0x01, 0x0a,
-	0x0b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x24, 0x0a, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x67, 0x64, 0x6d,
-	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
-	0x70, 0x65, 0x12, 0x58, 0x0a, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61,
-	0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x22, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x2e,
-	0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69,
-	0x74, 0x69, 0x65, 0x73, 0x48, 0x00, 0x52, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74,
-	0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x3d, 0x0a, 0x0b,
-	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x67, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73,
-	0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x0b,
-	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x67, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x64,
-	0x61, 0x74, 0x61, 0x22, 0x6b, 0x0a, 0x09, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73,
-	0x1a, 0x05, 0x0a, 0x03, 0x41, 0x63, 0x6b, 0x1a, 0x57, 0x0a, 0x14, 0x55, 0x69, 0x4c, 0x61, 0x79,
-	0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
-	0x3f, 0x0a, 0x12, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x55, 0x69, 0x4c, 0x61,
-	0x79, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x75,
-	0x74, 0x68, 0x64, 0x2e, 0x55, 0x49, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x12, 0x73, 0x75,
-	0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x73,
-	0x22, 0xbf, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x61, 0x74,
-	0x61, 0x12, 0x24, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x10, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x79, 0x70,
-	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x26, 0x0a, 0x03, 0x61, 0x63, 0x6b, 0x18, 0x0a,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x03, 0x61, 0x63, 0x6b, 0x12,
-	0x59, 0x0a, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62,
-	0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e,
-	0x67, 0x64, 0x6d, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x2e, 0x55, 0x69,
-	0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69,
-	0x65, 0x73, 0x48, 0x00, 0x52, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61,
-	0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61,
-	0x74, 0x61, 0x22, 0xa9, 0x05, 0x0a, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x1a, 0x53, 0x0a,
-	0x0f, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64,
-	0x12, 0x40, 0x0a, 0x0c, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x64, 0x2e, 0x41,
-	0x42, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x49, 0x6e, 0x66,
-	0x6f, 0x73, 0x1a, 0x2c, 0x0a, 0x0e, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65,
-	0x63, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49, 0x64,
-	0x1a, 0x26, 0x0a, 0x0c, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64,
-	0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x15, 0x0a, 0x13, 0x53, 0x74, 0x61, 0x72,
-	0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a,
-	0x58, 0x0a, 0x11, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65,
-	0x69, 0x76, 0x65, 0x64, 0x12, 0x43, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x64, 0x2e,
-	0x47, 0x41, 0x4d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x09,
-	0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x1a, 0x32, 0x0a, 0x10, 0x41, 0x75, 0x74,
-	0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x1e, 0x0a,
-	0x0a, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x1a, 0x3a, 0x0a,
-	0x09, 0x41, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2d, 0x0a, 0x08, 0x72, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61,
-	0x75, 0x74, 0x68, 0x64, 0x2e, 0x49, 0x41, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52,
-	0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x1a, 0x12, 0x0a, 0x10, 0x52, 0x65, 0x73,
-	0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x1a, 0x70, 0x0a,
-	0x18, 0x49, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x54, 0x0a, 0x13, 0x61, 0x75, 0x74,
-	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74, 0x61,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x64, 0x2e, 0x49,
-	0x41, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x52, 0x12, 0x61, 0x75, 0x74,
-	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x1a,
-	0x1a, 0x0a, 0x18, 0x49, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74,
-	0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x1a, 0x30, 0x0a, 0x0c, 0x53,
-	0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x05, 0x73,
+	0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x44, 0x61,
+	0x74, 0x61, 0x48, 0x04, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x5f,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0xdc, 0x01, 0x0a, 0x09, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x44,
+	0x61, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2b, 0x0a,
+	0x0e, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a, 0x13, 0x73, 0x75,
+	0x70, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x13, 0x73, 0x75, 0x70, 0x70, 0x6f,
+	0x72, 0x74, 0x73, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01,
+	0x01, 0x12, 0x1b, 0x0a, 0x06, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x02, 0x52, 0x06, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x11,
+	0x0a, 0x0f, 0x5f, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69, 0x7a,
+	0x65, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x43, 0x6f,
+	0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x75, 0x6e,
+	0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x81, 0x01, 0x0a, 0x09, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x1e,
+	0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x53, 0x0a, 0x08, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x73, 0x1a, 0x16, 0x0a, 0x14, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74,
+	0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x1a, 0x2f, 0x0a, 0x0b,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x05, 0x73,
 	0x74, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x70, 0x61, 0x6d,
-	0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x1a, 0x3f, 0x0a,
-	0x10, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
-	0x64, 0x12, 0x2b, 0x0a, 0x08, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x64, 0x2e, 0x55, 0x49, 0x4c, 0x61,
-	0x79, 0x6f, 0x75, 0x74, 0x52, 0x08, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x22, 0xcd,
-	0x07, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x67, 0x64, 0x6d,
-	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x12, 0x47, 0x0a, 0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69,
-	0x76, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x64, 0x6d, 0x2e,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65,
-	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72,
-	0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x44, 0x0a, 0x0e, 0x62, 0x72, 0x6f,
-	0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x42,
-	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52,
-	0x0e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12,
-	0x4d, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65,
-	0x69, 0x76, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x64, 0x6d,
-	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65,
-	0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x48, 0x00, 0x52, 0x11, 0x61, 0x75, 0x74,
-	0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x4a,
-	0x0a, 0x10, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
-	0x65, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65,
-	0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x10, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f,
-	0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x62, 0x0a, 0x18, 0x69, 0x73,
-	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x67,
-	0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x49, 0x73, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x65, 0x64, 0x48, 0x00, 0x52, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69,
-	0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x3e,
-	0x0a, 0x0c, 0x73, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x0f,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x73, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x48, 0x00,
-	0x52, 0x0c, 0x73, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x4a,
-	0x0a, 0x10, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
-	0x65, 0x64, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65,
-	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x48, 0x00, 0x52, 0x10, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f,
-	0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x35, 0x0a, 0x09, 0x61, 0x75,
-	0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
-	0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x61, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x12, 0x4a, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74,
-	0x68, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x64,
-	0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63,
-	0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x48, 0x00, 0x52, 0x10, 0x72, 0x65, 0x73,
-	0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x53, 0x0a,
-	0x13, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x64, 0x6d,
-	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74,
-	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x13, 0x73,
-	0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x0c, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
-	0x65, 0x64, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
-	0x65, 0x64, 0x48, 0x00, 0x52, 0x0c, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
+	0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x22, 0xd4, 0x01,
+	0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x24, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x67, 0x64,
+	0x6d, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x58, 0x0a, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43,
+	0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x22, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73,
+	0x2e, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x48, 0x00, 0x52, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75,
+	0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x3d, 0x0a,
+	0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x67, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x73, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52,
+	0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x67, 0x65, 0x42, 0x06, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x22, 0x6b, 0x0a, 0x09, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x73, 0x1a, 0x05, 0x0a, 0x03, 0x41, 0x63, 0x6b, 0x1a, 0x57, 0x0a, 0x14, 0x55, 0x69, 0x4c, 0x61,
+	0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x3f, 0x0a, 0x12, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x55, 0x69, 0x4c,
+	0x61, 0x79, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61,
+	0x75, 0x74, 0x68, 0x64, 0x2e, 0x55, 0x49, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x12, 0x73,
+	0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74,
+	0x73, 0x22, 0xbf, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x24, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x10, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x26, 0x0a, 0x03, 0x61, 0x63, 0x6b, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x03, 0x61, 0x63, 0x6b,
+	0x12, 0x59, 0x0a, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23,
+	0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x2e, 0x55,
+	0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x48, 0x00, 0x52, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43,
+	0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x22, 0xc0, 0x07, 0x0a, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x1a, 0x53,
+	0x0a, 0x0f, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x12, 0x40, 0x0a, 0x0c, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x49, 0x6e, 0x66, 0x6f,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x64, 0x2e,
+	0x41, 0x42, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x42, 0x72, 0x6f, 0x6b, 0x65,
+	0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x49, 0x6e,
+	0x66, 0x6f, 0x73, 0x1a, 0x2c, 0x0a, 0x0e, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49,
+	0x64, 0x1a, 0x26, 0x0a, 0x0c, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x15, 0x0a, 0x13, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x1a, 0x58, 0x0a, 0x11, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x43, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x64,
+	0x2e, 0x47, 0x41, 0x4d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x41, 0x75, 0x74,
+	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x52,
+	0x09, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x1a, 0x32, 0x0a, 0x10, 0x41, 0x75,
+	0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x1e,
+	0x0a, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x1a, 0x3a,
+	0x0a, 0x09, 0x41, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2d, 0x0a, 0x08, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x61, 0x75, 0x74, 0x68, 0x64, 0x2e, 0x49, 0x41, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x1a, 0x12, 0x0a, 0x10, 0x52, 0x65,
+	0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x1a, 0x70,
+	0x0a, 0x18, 0x49, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x54, 0x0a, 0x13, 0x61, 0x75,
+	0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x64, 0x2e,
+	0x49, 0x41, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e,
+	0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x52, 0x12, 0x61, 0x75,
+	0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61,
+	0x1a, 0x1a, 0x0a, 0x18, 0x49, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x1a, 0x30, 0x0a, 0x0c,
+	0x53, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x70, 0x61,
+	0x6d, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x1a, 0x3f,
+	0x0a, 0x10, 0x55, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x64, 0x12, 0x2b, 0x0a, 0x08, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x64, 0x2e, 0x55, 0x49, 0x4c,
+	0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x08, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x1a,
+	0x30, 0x0a, 0x12, 0x4e, 0x65, 0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x1a, 0x63, 0x0a, 0x19, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x53, 0x74, 0x72,
+	0x65, 0x6e, 0x67, 0x74, 0x68, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x69, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x1a, 0x7e, 0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x50, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x23, 0x0a, 0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c,
+	0x61, 0x62, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x63, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x70, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x22, 0xc6, 0x09, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x47, 0x0a, 0x0f, 0x62, 0x72, 0x6f, 0x6b,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x42,
+	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x48, 0x00,
+	0x52, 0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x12, 0x44, 0x0a, 0x0e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x64, 0x6d, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53,
+	0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x4d, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x4d,
+	0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
+	0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x48, 0x00, 0x52, 0x11, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65,
+	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x4a, 0x0a, 0x10, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f,
+	0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75,
+	0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00,
+	0x52, 0x10, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
 	0x65, 0x64, 0x12, 0x62, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69,
-	0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x15,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x0e,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
 	0x73, 0x2e, 0x49, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65,
-	0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x48, 0x00, 0x52, 0x18, 0x69, 0x73,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x18, 0x69, 0x73,
+	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x3e, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x67, 0x65, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67,
+	0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x67, 0x65, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x4a, 0x0a, 0x10, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f,
+	0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55, 0x69,
+	0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x48, 0x00,
+	0x52, 0x10, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x64, 0x12, 0x35, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x18,
+	0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x09,
+	0x61, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x4a, 0x0a, 0x10, 0x72, 0x65, 0x73,
+	0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x12, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x2e, 0x52, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64,
+	0x65, 0x48, 0x00, 0x52, 0x10, 0x72, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74,
+	0x68, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x53, 0x0a, 0x13, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x75,
+	0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x13, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x13, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74, 0x68,
+	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x0c, 0x75, 0x73,
+	0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0c, 0x75, 0x73,
+	0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x62, 0x0a, 0x18, 0x69, 0x73,
 	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e,
-	0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x2a, 0x76,
-	0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x75, 0x6e,
-	0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x68,
-	0x65, 0x6c, 0x6c, 0x6f, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x10,
-	0x02, 0x12, 0x0c, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x6b, 0x10, 0x03, 0x12,
-	0x0b, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x10, 0x04, 0x12, 0x0c, 0x0a, 0x08,
-	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x10, 0x05, 0x12, 0x08, 0x0a, 0x04, 0x70, 0x6f,
-	0x6c, 0x6c, 0x10, 0x06, 0x12, 0x10, 0x0a, 0x0c, 0x70, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x10, 0x07, 0x2a, 0x82, 0x01, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x0e, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77,
-	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x75, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x10,
-	0x01, 0x12, 0x1d, 0x0a, 0x19, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x69, 0x65, 0x77, 0x10, 0x02,
-	0x12, 0x18, 0x0a, 0x14, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61,
-	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x63, 0x68,
-	0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x67, 0x65, 0x10, 0x04, 0x2a, 0xa7, 0x02, 0x0a, 0x09,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x75, 0x6e, 0x6b,
-	0x6e, 0x6f, 0x77, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x75,
-	0x73, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x13, 0x0a,
-	0x0f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64,
-	0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65,
-	0x63, 0x74, 0x65, 0x64, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f,
-	0x64, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x10, 0x04, 0x12, 0x14, 0x0a,
-	0x10, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65,
-	0x64, 0x10, 0x05, 0x12, 0x14, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41,
-	0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x10, 0x06, 0x12, 0x0d, 0x0a, 0x09, 0x61, 0x75, 0x74,
-	0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x10, 0x07, 0x12, 0x14, 0x0a, 0x10, 0x75, 0x69, 0x4c, 0x61,
-	0x79, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x10, 0x08, 0x12, 0x17,
-	0x0a, 0x13, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x09, 0x12, 0x1c, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74,
-	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x65, 0x64, 0x10, 0x0a, 0x12, 0x1c, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65,
-	0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65,
-	0x64, 0x10, 0x0b, 0x12, 0x10, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e,
-	0x67, 0x65, 0x64, 0x10, 0x0c, 0x42, 0x21, 0x5a, 0x1f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x75, 0x62, 0x75, 0x6e, 0x74, 0x75, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x64,
-	0x2f, 0x70, 0x61, 0x6d, 0x2f, 0x67, 0x64, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
+	0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x67,
+	0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x49, 0x73, 0x41, 0x75, 0x74, 0x68,
+	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c,
+	0x65, 0x64, 0x48, 0x00, 0x52, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x50,
+	0x0a, 0x12, 0x6e, 0x65, 0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x64, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x67, 0x64, 0x6d,
+	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x4e, 0x65, 0x77, 0x50, 0x61, 0x73, 0x73, 0x77,
+	0x6f, 0x72, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x48, 0x00, 0x52, 0x12, 0x6e, 0x65,
+	0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64,
+	0x12, 0x65, 0x0a, 0x19, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x53, 0x74, 0x72, 0x65,
+	0x6e, 0x67, 0x74, 0x68, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x18, 0x17, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x2e, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x53, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x19, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x53, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x45, 0x73,
+	0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x12, 0x3e, 0x0a, 0x0c, 0x61, 0x75, 0x74, 0x68, 0x50,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x67, 0x64, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x50,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x0c, 0x61, 0x75, 0x74, 0x68, 0x50,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x2a,
+	0x8f, 0x01, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b,
+	0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x10, 0x00, 0x12, 0x09, 0x0a,
+	0x05, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x6b, 0x10,
+	0x03, 0x12, 0x0b, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x10, 0x04, 0x12, 0x0c,
+	0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x10, 0x05, 0x12, 0x08, 0x0a, 0x04,
+	0x70, 0x6f, 0x6c, 0x6c, 0x10, 0x06, 0x12, 0x10, 0x0a, 0x0c, 0x70, 0x6f, 0x6c, 0x6c, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x10, 0x07, 0x12, 0x09, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e,
+	0x6b, 0x10, 0x08, 0x12, 0x0c, 0x0a, 0x08, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x41, 0x63, 0x6b, 0x10,
+	0x09, 0x2a, 0x82, 0x01, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x12, 0x0a, 0x0e, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42,
+	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19,
+	0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x69, 0x65, 0x77, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x75,
+	0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x53,
+	0x74, 0x61, 0x67, 0x65, 0x10, 0x04, 0x2a, 0xf0, 0x02, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65,
+	0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x62, 0x72, 0x6f, 0x6b,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x10, 0x02, 0x12, 0x12, 0x0a,
+	0x0e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10,
+	0x03, 0x12, 0x15, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65,
+	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x61, 0x75, 0x74, 0x68,
+	0x4d, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x05, 0x12, 0x14,
+	0x0a, 0x10, 0x72, 0x65, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f,
+	0x64, 0x65, 0x10, 0x06, 0x12, 0x0d, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x10, 0x07, 0x12, 0x14, 0x0a, 0x10, 0x75, 0x69, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x10, 0x08, 0x12, 0x17, 0x0a, 0x13, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x10, 0x09, 0x12, 0x1c, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x10, 0x0a,
+	0x12, 0x1c, 0x0a, 0x18, 0x69, 0x73, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x0b, 0x12, 0x10,
+	0x0a, 0x0c, 0x73, 0x74, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x10, 0x0c,
+	0x12, 0x16, 0x0a, 0x12, 0x6e, 0x65, 0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x10, 0x0d, 0x12, 0x1d, 0x0a, 0x19, 0x70, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x53, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x45, 0x73, 0x74, 0x69,
+	0x6d, 0x61, 0x74, 0x65, 0x64, 0x10, 0x0e, 0x12, 0x10, 0x0a, 0x0c, 0x61, 0x75, 0x74, 0x68, 0x50,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x10, 0x0f, 0x42, 0x21, 0x5a, 0x1f, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x75, 0x62, 0x75, 0x6e, 0x74, 0x75, 0x2f, 0x61,
+	0x75, 0x74, 0x68, 0x64, 0x2f, 0x70, 0x61, 0x6d, 0x2f, 0x67, 0x64, 0x6d, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+})
 
 var (
 	file_gdm_proto_rawDescOnce sync.Once
-	file_gdm_proto_rawDescData = file_gdm_proto_rawDesc
+	file_gdm_proto_rawDescData []byte
 )
 
 func file_gdm_proto_rawDescGZIP() []byte {
 	file_gdm_proto_rawDescOnce.Do(func() {
-		file_gdm_proto_rawDescData = protoimpl.X.CompressGZIP(file_gdm_proto_rawDescData)
+		file_gdm_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gdm_proto_rawDesc), len(file_gdm_proto_rawDesc)))
 	})
 	return file_gdm_proto_rawDescData
 }
 
 var file_gdm_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_gdm_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_gdm_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_gdm_proto_goTypes = []any{
 	(DataType)(0),                                // 0: gdm.DataType
 	(RequestType)(0),                             // 1: gdm.RequestType
 	(EventType)(0),                               // 2: gdm.EventType
 	(*Data)(nil),                                 // 3: gdm.Data
 	(*HelloData)(nil),                            // 4: gdm.HelloData
-	(*Requests)(nil),                             // 5: gdm.Requests
-	(*RequestData)(nil),                          // 6: gdm.RequestData
-	(*Responses)(nil),                            // 7: gdm.Responses
-	(*ResponseData)(nil),                         // 8: gdm.ResponseData
-	(*Events)(nil),                               // 9: gdm.Events
-	(*EventData)(nil),                            // 10: gdm.EventData
-	(*Requests_UiLayoutCapabilities)(nil),        // 11: gdm.Requests.UiLayoutCapabilities
-	(*Requests_ChangeStage)(nil),                 // 12: gdm.Requests.ChangeStage
-	(*Responses_Ack)(nil),                        // 13: gdm.Responses.Ack
-	(*Responses_UiLayoutCapabilities)(nil),       // 14: gdm.Responses.UiLayoutCapabilities
-	(*Events_BrokersReceived)(nil),               // 15: gdm.Events.BrokersReceived
-	(*Events_BrokerSelected)(nil),                // 16: gdm.Events.BrokerSelected
-	(*Events_UserSelected)(nil),                  // 17: gdm.Events.UserSelected
-	(*Events_StartAuthentication)(nil),           // 18: gdm.Events.StartAuthentication
-	(*Events_AuthModesReceived)(nil),             // 19: gdm.Events.AuthModesReceived
-	(*Events_AuthModeSelected)(nil),              // 20: gdm.Events.AuthModeSelected
-	(*Events_AuthEvent)(nil),                     // 21: gdm.Events.AuthEvent
-	(*Events_ReselectAuthMode)(nil),              // 22: gdm.Events.ReselectAuthMode
-	(*Events_IsAuthenticatedRequested)(nil),      // 23: gdm.Events.IsAuthenticatedRequested
-	(*Events_IsAuthenticatedCancelled)(nil),      // 24: gdm.Events.IsAuthenticatedCancelled
-	(*Events_StageChanged)(nil),                  // 25: gdm.Events.StageChanged
-	(*Events_UiLayoutReceived)(nil),              // 26: gdm.Events.UiLayoutReceived
-	(proto.Stage)(0),                             // 27: pam.Stage
-	(*authd.UILayout)(nil),                       // 28: authd.UILayout
-	(*authd.ABResponse_BrokerInfo)(nil),          // 29: authd.ABResponse.BrokerInfo
-	(*authd.GAMResponse_AuthenticationMode)(nil), // 30: authd.GAMResponse.AuthenticationMode
-	(*authd.IAResponse)(nil),                     // 31: authd.IAResponse
-	(*authd.IARequest_AuthenticationData)(nil),   // 32: authd.IARequest.AuthenticationData
+	(*ChunkData)(nil),                            // 5: gdm.ChunkData
+	(*Requests)(nil),                             // 6: gdm.Requests
+	(*RequestData)(nil),                          // 7: gdm.RequestData
+	(*Responses)(nil),                            // 8: gdm.Responses
+	(*ResponseData)(nil),                         // 9: gdm.ResponseData
+	(*Events)(nil),                               // 10: gdm.Events
+	(*EventData)(nil),                            // 11: gdm.EventData
+	(*Requests_UiLayoutCapabilities)(nil),        // 12: gdm.Requests.UiLayoutCapabilities
+	(*Requests_ChangeStage)(nil),                 // 13: gdm.Requests.ChangeStage
+	(*Responses_Ack)(nil),                        // 14: gdm.Responses.Ack
+	(*Responses_UiLayoutCapabilities)(nil),       // 15: gdm.Responses.UiLayoutCapabilities
+	(*Events_BrokersReceived)(nil),               // 16: gdm.Events.BrokersReceived
+	(*Events_BrokerSelected)(nil),                // 17: gdm.Events.BrokerSelected
+	(*Events_UserSelected)(nil),                  // 18: gdm.Events.UserSelected
+	(*Events_StartAuthentication)(nil),           // 19: gdm.Events.StartAuthentication
+	(*Events_AuthModesReceived)(nil),             // 20: gdm.Events.AuthModesReceived
+	(*Events_AuthModeSelected)(nil),              // 21: gdm.Events.AuthModeSelected
+	(*Events_AuthEvent)(nil),                     // 22: gdm.Events.AuthEvent
+	(*Events_ReselectAuthMode)(nil),              // 23: gdm.Events.ReselectAuthMode
+	(*Events_IsAuthenticatedRequested)(nil),      // 24: gdm.Events.IsAuthenticatedRequested
+	(*Events_IsAuthenticatedCancelled)(nil),      // 25: gdm.Events.IsAuthenticatedCancelled
+	(*Events_StageChanged)(nil),                  // 26: gdm.Events.StageChanged
+	(*Events_UiLayoutReceived)(nil),              // 27: gdm.Events.UiLayoutReceived
+	(*Events_NewPasswordChanged)(nil),            // 28: gdm.Events.NewPasswordChanged
+	(*Events_PasswordStrengthEstimated)(nil),     // 29: gdm.Events.PasswordStrengthEstimated
+	(*Events_AuthProgress)(nil),                  // 30: gdm.Events.AuthProgress
+	(proto.Stage)(0),                             // 31: pam.Stage
+	(*authd.UILayout)(nil),                       // 32: authd.UILayout
+	(*authd.ABResponse_BrokerInfo)(nil),          // 33: authd.ABResponse.BrokerInfo
+	(*authd.GAMResponse_AuthenticationMode)(nil), // 34: authd.GAMResponse.AuthenticationMode
+	(*authd.IAResponse)(nil),                     // 35: authd.IAResponse
+	(*authd.IARequest_AuthenticationData)(nil),   // 36: authd.IARequest.AuthenticationData
 }
 var file_gdm_proto_depIdxs = []int32{
 	0,  // 0: gdm.Data.type:type_name -> gdm.DataType
 	4,  // 1: gdm.Data.hello:type_name -> gdm.HelloData
-	6,  // 2: gdm.Data.request:type_name -> gdm.RequestData
-	8,  // 3: gdm.Data.response:type_name -> gdm.ResponseData
-	10, // 4: gdm.Data.event:type_name -> gdm.EventData
-	10, // 5: gdm.Data.pollResponse:type_name -> gdm.EventData
-	1,  // 6: gdm.RequestData.type:type_name -> gdm.RequestType
-	11, // 7: gdm.RequestData.uiLayoutCapabilities:type_name -> gdm.Requests.UiLayoutCapabilities
-	12, // 8: gdm.RequestData.changeStage:type_name -> gdm.Requests.ChangeStage
-	1,  // 9: gdm.ResponseData.type:type_name -> gdm.RequestType
-	13, // 10: gdm.ResponseData.ack:type_name -> gdm.Responses.Ack
-	14, // 11: gdm.ResponseData.uiLayoutCapabilities:type_name -> gdm.Responses.UiLayoutCapabilities
-	2,  // 12: gdm.EventData.type:type_name -> gdm.EventType
-	15, // 13: gdm.EventData.brokersReceived:type_name -> gdm.Events.BrokersReceived
-	16, // 14: gdm.EventData.brokerSelected:type_name -> gdm.Events.BrokerSelected
-	19, // 15: gdm.EventData.authModesReceived:type_name -> gdm.Events.AuthModesReceived
-	20, // 16: gdm.EventData.authModeSelected:type_name -> gdm.Events.AuthModeSelected
-	23, // 17: gdm.EventData.isAuthenticatedRequested:type_name -> gdm.Events.IsAuthenticatedRequested
-	25, // 18: gdm.EventData.stageChanged:type_name -> gdm.Events.StageChanged
-	26, // 19: gdm.EventData.uiLayoutReceived:type_name -> gdm.Events.UiLayoutReceived
-	21, // 20: gdm.EventData.authEvent:type_name -> gdm.Events.AuthEvent
-	22, // 21: gdm.EventData.reselectAuthMode:type_name -> gdm.Events.ReselectAuthMode
-	18, // 22: gdm.EventData.startAuthentication:type_name -> gdm.Events.StartAuthentication
-	17, // 23: gdm.EventData.userSelected:type_name -> gdm.Events.UserSelected
-	24, // 24: gdm.EventData.isAuthenticatedCancelled:type_name -> gdm.Events.IsAuthenticatedCancelled
-	27, // 25: gdm.Requests.ChangeStage.stage:type_name -> pam.Stage
-	28, // 26: gdm.Responses.UiLayoutCapabilities.supportedUiLayouts:type_name -> authd.UILayout
-	29, // 27: gdm.Events.BrokersReceived.brokersInfos:type_name -> authd.ABResponse.BrokerInfo
-	30, // 28: gdm.Events.AuthModesReceived.authModes:type_name -> authd.GAMResponse.AuthenticationMode
-	31, // 29: gdm.Events.AuthEvent.response:type_name -> authd.IAResponse
-	32, // 30: gdm.Events.IsAuthenticatedRequested.authentication_data:type_name -> authd.IARequest.AuthenticationData
-	27, // 31: gdm.Events.StageChanged.stage:type_name -> pam.Stage
-	28, // 32: gdm.Events.UiLayoutReceived.uiLayout:type_name -> authd.UILayout
-	33, // [33:33] is the sub-list for method output_type
-	33, // [33:33] is the sub-list for method input_type
-	33, // [33:33] is the sub-list for extension type_name
-	33, // [33:33] is the sub-list for extension extendee
-	0,  // [0:33] is the sub-list for field type_name
+	7,  // 2: gdm.Data.request:type_name -> gdm.RequestData
+	9,  // 3: gdm.Data.response:type_name -> gdm.ResponseData
+	11, // 4: gdm.Data.event:type_name -> gdm.EventData
+	11, // 5: gdm.Data.pollResponse:type_name -> gdm.EventData
+	5,  // 6: gdm.Data.chunk:type_name -> gdm.ChunkData
+	1,  // 7: gdm.RequestData.type:type_name -> gdm.RequestType
+	12, // 8: gdm.RequestData.uiLayoutCapabilities:type_name -> gdm.Requests.UiLayoutCapabilities
+	13, // 9: gdm.RequestData.changeStage:type_name -> gdm.Requests.ChangeStage
+	1,  // 10: gdm.ResponseData.type:type_name -> gdm.RequestType
+	14, // 11: gdm.ResponseData.ack:type_name -> gdm.Responses.Ack
+	15, // 12: gdm.ResponseData.uiLayoutCapabilities:type_name -> gdm.Responses.UiLayoutCapabilities
+	2,  // 13: gdm.EventData.type:type_name -> gdm.EventType
+	16, // 14: gdm.EventData.brokersReceived:type_name -> gdm.Events.BrokersReceived
+	17, // 15: gdm.EventData.brokerSelected:type_name -> gdm.Events.BrokerSelected
+	20, // 16: gdm.EventData.authModesReceived:type_name -> gdm.Events.AuthModesReceived
+	21, // 17: gdm.EventData.authModeSelected:type_name -> gdm.Events.AuthModeSelected
+	24, // 18: gdm.EventData.isAuthenticatedRequested:type_name -> gdm.Events.IsAuthenticatedRequested
+	26, // 19: gdm.EventData.stageChanged:type_name -> gdm.Events.StageChanged
+	27, // 20: gdm.EventData.uiLayoutReceived:type_name -> gdm.Events.UiLayoutReceived
+	22, // 21: gdm.EventData.authEvent:type_name -> gdm.Events.AuthEvent
+	23, // 22: gdm.EventData.reselectAuthMode:type_name -> gdm.Events.ReselectAuthMode
+	19, // 23: gdm.EventData.startAuthentication:type_name -> gdm.Events.StartAuthentication
+	18, // 24: gdm.EventData.userSelected:type_name -> gdm.Events.UserSelected
+	25, // 25: gdm.EventData.isAuthenticatedCancelled:type_name -> gdm.Events.IsAuthenticatedCancelled
+	28, // 26: gdm.EventData.newPasswordChanged:type_name -> gdm.Events.NewPasswordChanged
+	29, // 27: gdm.EventData.passwordStrengthEstimated:type_name -> gdm.Events.PasswordStrengthEstimated
+	30, // 28: gdm.EventData.authProgress:type_name -> gdm.Events.AuthProgress
+	31, // 29: gdm.Requests.ChangeStage.stage:type_name -> pam.Stage
+	32, // 30: gdm.Responses.UiLayoutCapabilities.supportedUiLayouts:type_name -> authd.UILayout
+	33, // 31: gdm.Events.BrokersReceived.brokersInfos:type_name -> authd.ABResponse.BrokerInfo
+	34, // 32: gdm.Events.AuthModesReceived.authModes:type_name -> authd.GAMResponse.AuthenticationMode
+	35, // 33: gdm.Events.AuthEvent.response:type_name -> authd.IAResponse
+	36, // 34: gdm.Events.IsAuthenticatedRequested.authentication_data:type_name -> authd.IARequest.AuthenticationData
+	31, // 35: gdm.Events.StageChanged.stage:type_name -> pam.Stage
+	32, // 36: gdm.Events.UiLayoutReceived.uiLayout:type_name -> authd.UILayout
+	37, // [37:37] is the sub-list for method output_type
+	37, // [37:37] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
 }
 
 func init() { file_gdm_proto_init() }
@@ -1865,15 +2308,16 @@ func file_gdm_proto_init() {
 		return
 	}
 	file_gdm_proto_msgTypes[0].OneofWrappers = []any{}
-	file_gdm_proto_msgTypes[3].OneofWrappers = []any{
+	file_gdm_proto_msgTypes[1].OneofWrappers = []any{}
+	file_gdm_proto_msgTypes[4].OneofWrappers = []any{
 		(*RequestData_UiLayoutCapabilities)(nil),
 		(*RequestData_ChangeStage)(nil),
 	}
-	file_gdm_proto_msgTypes[5].OneofWrappers = []any{
+	file_gdm_proto_msgTypes[6].OneofWrappers = []any{
 		(*ResponseData_Ack)(nil),
 		(*ResponseData_UiLayoutCapabilities)(nil),
 	}
-	file_gdm_proto_msgTypes[7].OneofWrappers = []any{
+	file_gdm_proto_msgTypes[8].OneofWrappers = []any{
 		(*EventData_BrokersReceived)(nil),
 		(*EventData_BrokerSelected)(nil),
 		(*EventData_AuthModesReceived)(nil),
@@ -1886,14 +2330,18 @@ func file_gdm_proto_init() {
 		(*EventData_StartAuthentication)(nil),
 		(*EventData_UserSelected)(nil),
 		(*EventData_IsAuthenticatedCancelled)(nil),
+		(*EventData_NewPasswordChanged)(nil),
+		(*EventData_PasswordStrengthEstimated)(nil),
+		(*EventData_AuthProgress)(nil),
 	}
+	file_gdm_proto_msgTypes[27].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_gdm_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gdm_proto_rawDesc), len(file_gdm_proto_rawDesc)),
 			NumEnums:      3,
-			NumMessages:   24,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -1903,7 +2351,6 @@ func file_gdm_proto_init() {
 		MessageInfos:      file_gdm_proto_msgTypes,
 	}.Build()
 	File_gdm_proto = out.File
-	file_gdm_proto_rawDesc = nil
 	file_gdm_proto_goTypes = nil
 	file_gdm_proto_depIdxs = nil
 }
@@ -9,15 +9,32 @@ import (
 	"reflect"
 	"slices"
 
+	"github.com/ubuntu/authd/internal/consts"
 	"github.com/ubuntu/authd/internal/proto/authd"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
 const (
 	// ProtoVersion is the version of the JSON protocol.
-	ProtoVersion = uint32(1)
+	ProtoVersion = uint32(consts.GDMProtocolVersion)
+
+	// maxDataSize is the maximum size of the JSON data we decode, as it's sent by the greeter.
+	maxDataSize = maxAssembledSize
+	// maxDataDepth is the maximum nesting depth of the JSON data we decode.
+	maxDataDepth = 32
 )
 
+// ErrDataTooLarge is returned when the JSON data to decode exceeds the maximum size.
+var ErrDataTooLarge = errors.New("data too large")
+
+// dataUnmarshalOptions are the options used to decode the data received from GDM. Both sides implement the same
+// protocol version (as checked by the hello handshake), so unknown fields are a protocol error and are rejected
+// instead of being silently ignored.
+var dataUnmarshalOptions = protojson.UnmarshalOptions{
+	DiscardUnknown: false,
+	RecursionLimit: maxDataDepth,
+}
+
 // Request is an interface implementing all the gdm requests.
 type Request = isRequestData_Data
 
@@ -29,8 +46,12 @@ type Event = isEventData_Data
 
 // NewDataFromJSON unmarshals data from json bytes.
 func NewDataFromJSON(bytes []byte) (*Data, error) {
+	if len(bytes) > maxDataSize {
+		return nil, fmt.Errorf("%w: %d bytes exceeds %d", ErrDataTooLarge, len(bytes), maxDataSize)
+	}
+
 	var gdmData Data
-	if err := protojson.Unmarshal(bytes, &gdmData); err != nil {
+	if err := dataUnmarshalOptions.Unmarshal(bytes, &gdmData); err != nil {
 		return nil, err
 	}
 
@@ -137,6 +158,22 @@ func (d *Data) Check() error {
 			return err
 		}
 
+	case DataType_chunk:
+		if d.Chunk == nil {
+			return errors.New("missing chunk data")
+		}
+		if d.Chunk.Total == 0 || d.Chunk.Index >= d.Chunk.Total {
+			return fmt.Errorf("invalid chunk index %d of %d", d.Chunk.Index, d.Chunk.Total)
+		}
+		if err := checkMembersFunc(d, []string{"Chunk"}); err != nil {
+			return err
+		}
+
+	case DataType_chunkAck:
+		if err := checkMembersFunc(d, []string{}); err != nil {
+			return err
+		}
+
 	case DataType_pollResponse:
 		if err := checkMembersFunc(d, []string{"PollResponse"}); err != nil {
 			return err
@@ -176,6 +213,15 @@ func stringifyEventDataDebug(ed *EventData) string {
 }
 
 func stringifyEventDataFiltered(ed *EventData) string {
+	if _, ok := ed.GetData().(*EventData_NewPasswordChanged); ok {
+		return (&EventData{
+			Type: ed.Type,
+			Data: &EventData_NewPasswordChanged{
+				NewPasswordChanged: &Events_NewPasswordChanged{Password: "**************"},
+			},
+		}).String()
+	}
+
 	authReq, ok := ed.GetData().(*EventData_IsAuthenticatedRequested)
 	if !ok {
 		return ed.String()
@@ -3,10 +3,12 @@ package gdm_test
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"github.com/ubuntu/authd/pam/internal/gdm"
+	"google.golang.org/protobuf/proto"
 )
 
 func reformatJSON(t *testing.T, input []byte) []byte {
@@ -488,6 +490,11 @@ func TestGdmStructsUnMarshal(t *testing.T) {
 		"Error_empty_packet": {
 			wantErrMsg: "syntax error",
 		},
+		"Error_packet_too_large": {
+			JSON: `{"type":"hello","hello":{"version":1}}` + strings.Repeat(" ", 16*1024*1024),
+
+			wantErrMsg: "data too large",
+		},
 		"Error_empty_packet_object": {
 			JSON: `{}`,
 
@@ -623,3 +630,33 @@ func TestGdmStructsUnMarshal(t *testing.T) {
 		})
 	}
 }
+
+func FuzzNewDataFromJSON(f *testing.F) {
+	for _, seed := range []string{
+		`{"type":"hello","hello":{"version":1,"maxPayloadSize":65536,"supportsCompression":true}}`,
+		`{"type":"event","event":{"type":"brokerSelected","brokerSelected":{"brokerId":"a broker"}}}`,
+		`{"type":"request","request":{"type":"uiLayoutCapabilities","uiLayoutCapabilities":{}}}`,
+		`{"type":"response","response":{"type":"changeStage","ack":{}}}`,
+		`{"type":"pollResponse","pollResponse":[{"type":"authModeSelected","authModeSelected":{"authModeId":"mode"}}]}`,
+		`{"type":"chunk","chunk":{"id":1,"index":0,"total":2,"payload":"cGF5bG9hZA=="}}`,
+		`{"type":"poll","event":{}}`,
+		`{"type":"hello","unknownField":true}`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		gdmData, err := gdm.NewDataFromJSON(data)
+		if err != nil {
+			require.Nil(t, gdmData, "NewDataFromJSON should not return data on error")
+			return
+		}
+
+		// Any accepted data must be valid, so it can be sent back and decoded to the same value.
+		encoded, err := gdmData.JSON()
+		require.NoError(t, err, "Accepted data should be encodable")
+		decoded, err := gdm.NewDataFromJSON(encoded)
+		require.NoError(t, err, "Encoded data should be decodable")
+		require.True(t, proto.Equal(gdmData, decoded), "Decoded data should match the accepted one")
+	})
+}
@@ -3,7 +3,6 @@ package pam_test
 import (
 	"context"
 	"crypto/rsa"
-	"crypto/sha512"
 	"crypto/x509"
 	"encoding/base64"
 	"errors"
@@ -15,6 +14,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/ubuntu/authd/internal/brokers/auth"
+	"github.com/ubuntu/authd/internal/brokers/encryption"
 	"github.com/ubuntu/authd/internal/brokers/layouts"
 	"github.com/ubuntu/authd/internal/brokers/layouts/entries"
 	"github.com/ubuntu/authd/internal/proto/authd"
@@ -45,8 +45,12 @@ type options struct {
 	isAuthenticatedWantSkip   bool
 	isAuthenticatedWantWait   time.Duration
 	isAuthenticatedMessage    string
+	authenticationProgress    *authd.GAPResponse
 	isAuthenticatedMaxRetries int
 
+	rotatedPrivateKey *rsa.PrivateKey
+	rotatedAlgorithm  string
+
 	endSessionErr error
 
 	defaultBrokerForUser       map[string]string
@@ -55,6 +59,9 @@ type options struct {
 	uiLayouts map[string]*authd.UILayout
 	authModes map[string]*authd.GAMResponse_AuthenticationMode
 
+	localPIN            string
+	localPINMaxAttempts int
+
 	ignoreSessionIDChecks     bool
 	ignoreSessionIDGeneration bool
 }
@@ -64,13 +71,16 @@ type DummyClient struct {
 	options
 	mu sync.Mutex
 
-	privateKey    *rsa.PrivateKey
-	encryptionKey string
+	privateKey          *rsa.PrivateKey
+	encryptionKey       string
+	encryptionAlgorithm string
 
 	currentSessionID string
 	selectedBrokerID string
 	selectedUsername string
 	selectedLang     string
+
+	localPINFailedAttempts int
 }
 
 // DummyClientOptions is the function signature used to tweak the daemon creation.
@@ -159,6 +169,15 @@ func WithIsAuthenticatedMaxRetries(maxRetries int) func(o *options) {
 	}
 }
 
+// WithEncryptionKeyRotation is the option to rotate the encryption key on the first retry, using the new private key
+// and algorithm.
+func WithEncryptionKeyRotation(privateKey *rsa.PrivateKey, algorithm string) func(o *options) {
+	return func(o *options) {
+		o.rotatedPrivateKey = privateKey
+		o.rotatedAlgorithm = algorithm
+	}
+}
+
 // WithIsAuthenticatedMessage is the option to define the IsAuthenticated message return values.
 func WithIsAuthenticatedMessage(message string) func(o *options) {
 	return func(o *options) {
@@ -166,6 +185,13 @@ func WithIsAuthenticatedMessage(message string) func(o *options) {
 	}
 }
 
+// WithAuthenticationProgress is the option to define the progress reported while authenticating.
+func WithAuthenticationProgress(progress *authd.GAPResponse) func(o *options) {
+	return func(o *options) {
+		o.authenticationProgress = progress
+	}
+}
+
 // WithEndSessionReturn is the option to define the EndSession return values.
 func WithEndSessionReturn(err error) func(o *options) {
 	return func(o *options) {
@@ -188,6 +214,14 @@ func WithUILayout(authModeID string, label string, uiLayout *authd.UILayout) fun
 	}
 }
 
+// WithLocalPIN is the option to define the local PIN registered by the users, locked after the given failed attempts.
+func WithLocalPIN(pin string, maxAttempts int) func(o *options) {
+	return func(o *options) {
+		o.localPIN = pin
+		o.localPINMaxAttempts = maxAttempts
+	}
+}
+
 // WithIgnoreSessionIDChecks is the option to ignore session ID checks.
 func WithIgnoreSessionIDChecks() func(o *options) {
 	return func(o *options) {
@@ -206,7 +240,8 @@ func WithIgnoreSessionIDGeneration() func(o *options) {
 func NewDummyClient(privateKey *rsa.PrivateKey, args ...DummyClientOptions) *DummyClient {
 	// Set default options.
 	dc := &DummyClient{
-		privateKey: privateKey,
+		privateKey:          privateKey,
+		encryptionAlgorithm: encryption.DefaultAlgorithm,
 	}
 
 	if privateKey != nil {
@@ -456,19 +491,18 @@ func (dc *DummyClient) handleChallenge(secret string, msg string) (*authd.IAResp
 	if secret == "" {
 		return nil, errors.New("no secret provided")
 	}
-	ciphertext, err := base64.StdEncoding.DecodeString(secret)
-	if err != nil {
+	if _, err := base64.StdEncoding.DecodeString(secret); err != nil {
 		return nil, err
 	}
 	if dc.privateKey == nil {
 		return nil, errors.New("no private key defined")
 	}
-	plaintext, err := rsa.DecryptOAEP(sha512.New(), nil, dc.privateKey, ciphertext, nil)
+	plaintext, err := encryption.Decrypt(dc.encryptionAlgorithm, dc.privateKey, secret)
 	if err != nil {
 		return nil, err
 	}
 
-	if string(plaintext) == dc.isAuthenticatedWantSecret {
+	if plaintext == dc.isAuthenticatedWantSecret {
 		return &authd.IAResponse{
 			Access: auth.Granted,
 			Msg:    msg,
@@ -483,10 +517,35 @@ func (dc *DummyClient) handleChallenge(secret string, msg string) (*authd.IAResp
 		}, nil
 	}
 
-	return &authd.IAResponse{
+	resp := &authd.IAResponse{
 		Access: auth.Retry,
 		Msg:    msg,
-	}, nil
+	}
+	if dc.rotatedPrivateKey != nil {
+		pubASN1, err := x509.MarshalPKIXPublicKey(&dc.rotatedPrivateKey.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		resp.EncryptionKey = base64.StdEncoding.EncodeToString(pubASN1)
+		resp.EncryptionAlgorithm = dc.rotatedAlgorithm
+		dc.privateKey, dc.encryptionAlgorithm = dc.rotatedPrivateKey, dc.rotatedAlgorithm
+		dc.rotatedPrivateKey = nil
+	}
+
+	return resp, nil
+}
+
+// GetAuthenticationProgress simulates GetAuthenticationProgress using the provided parameters. It doesn't lock the
+// client, as it's called while IsAuthenticated is in progress.
+func (dc *DummyClient) GetAuthenticationProgress(ctx context.Context, in *authd.GAPRequest, opts ...grpc.CallOption) (*authd.GAPResponse, error) {
+	log.Debugf(ctx, "GetAuthenticationProgress Called: %#v", in)
+	if in == nil {
+		return nil, errors.New("no input values provided")
+	}
+	if dc.authenticationProgress != nil {
+		return dc.authenticationProgress, nil
+	}
+	return &authd.GAPResponse{}, nil
 }
 
 // EndSession simulates EndSession using the provided parameters.
@@ -532,6 +591,256 @@ func (dc *DummyClient) SetDefaultBrokerForUser(ctx context.Context, in *authd.SD
 	return &authd.Empty{}, nil
 }
 
+// IsRecentlyAuthenticated simulates IsRecentlyAuthenticated, never allowing to skip authentication.
+func (dc *DummyClient) IsRecentlyAuthenticated(ctx context.Context, in *authd.IRARequest, opts ...grpc.CallOption) (*authd.IRAResponse, error) {
+	log.Debugf(ctx, "IsRecentlyAuthenticated Called: %#v", in)
+	if in == nil {
+		return nil, errors.New("no input values provided")
+	}
+	if in.Username == "" {
+		return nil, errors.New("no valid username provided")
+	}
+	return &authd.IRAResponse{}, nil
+}
+
+// GetSessionActions simulates GetSessionActions, never requiring any session action.
+func (dc *DummyClient) GetSessionActions(ctx context.Context, in *authd.GSARequest, opts ...grpc.CallOption) (*authd.GSAResponse, error) {
+	log.Debugf(ctx, "GetSessionActions Called: %#v", in)
+	if in == nil {
+		return nil, errors.New("no input values provided")
+	}
+	if in.Username == "" {
+		return nil, errors.New("no valid username provided")
+	}
+	return &authd.GSAResponse{}, nil
+}
+
+// CheckLoginPolicy simulates CheckLoginPolicy, always allowing the user to log in.
+func (dc *DummyClient) CheckLoginPolicy(ctx context.Context, in *authd.CLPRequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "CheckLoginPolicy Called: %#v", in)
+	if in == nil {
+		return nil, errors.New("no input values provided")
+	}
+	if in.Username == "" {
+		return nil, errors.New("no valid username provided")
+	}
+	return &authd.Empty{}, nil
+}
+
+// ListSessions simulates ListSessions, returning the current session, if any.
+func (dc *DummyClient) ListSessions(ctx context.Context, in *authd.Empty, opts ...grpc.CallOption) (*authd.LSResponse, error) {
+	log.Debugf(ctx, "ListSessions Called: %#v", in)
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.currentSessionID == "" {
+		return &authd.LSResponse{}, nil
+	}
+	return &authd.LSResponse{
+		Sessions: []*authd.LSResponse_SessionInfo{{
+			SessionId: dc.currentSessionID,
+			Username:  dc.selectedUsername,
+			BrokerId:  dc.selectedBrokerID,
+		}},
+	}, nil
+}
+
+// AbortSession simulates AbortSession, ending the current session.
+func (dc *DummyClient) AbortSession(ctx context.Context, in *authd.ASRequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "AbortSession Called: %#v", in)
+	return dc.EndSession(ctx, &authd.ESRequest{SessionId: in.GetSessionId()}, opts...)
+}
+
+// AuthenticateHeadless is not supported by the dummy client, as the PAM module never authenticates without a UI.
+func (dc *DummyClient) AuthenticateHeadless(ctx context.Context, in *authd.AHRequest, opts ...grpc.CallOption) (*authd.IAResponse, error) {
+	log.Debugf(ctx, "AuthenticateHeadless Called: %#v", in)
+	return nil, errors.New("headless authentication is not supported by the dummy client")
+}
+
+// DumpDatabase is not supported by the dummy client, as the PAM module never accesses the database.
+func (dc *DummyClient) DumpDatabase(ctx context.Context, in *authd.Empty, opts ...grpc.CallOption) (*authd.DatabaseDump, error) {
+	log.Debugf(ctx, "DumpDatabase Called: %#v", in)
+	return nil, errors.New("dumping the database is not supported by the dummy client")
+}
+
+// ImportDatabase is not supported by the dummy client, as the PAM module never accesses the database.
+func (dc *DummyClient) ImportDatabase(ctx context.Context, in *authd.DatabaseDump, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "ImportDatabase Called: %#v", in)
+	return nil, errors.New("importing the database is not supported by the dummy client")
+}
+
+// GetVersion is not supported by the dummy client, as the PAM module never checks the version of the daemon.
+func (dc *DummyClient) GetVersion(ctx context.Context, in *authd.Empty, opts ...grpc.CallOption) (*authd.GVResponse, error) {
+	log.Debugf(ctx, "GetVersion Called: %#v", in)
+	return nil, errors.New("getting the version is not supported by the dummy client")
+}
+
+// ResetBrokerForUser is not supported by the dummy client, as the PAM module never resets the broker of a user.
+func (dc *DummyClient) ResetBrokerForUser(ctx context.Context, in *authd.RBFURequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "ResetBrokerForUser Called: %#v", in)
+	return nil, errors.New("resetting the broker of a user is not supported by the dummy client")
+}
+
+// SetDebugSocket is not supported by the dummy client, as the PAM module never profiles the daemon.
+func (dc *DummyClient) SetDebugSocket(ctx context.Context, in *authd.SDSRequest, opts ...grpc.CallOption) (*authd.SDSResponse, error) {
+	log.Debugf(ctx, "SetDebugSocket Called: %#v", in)
+	return nil, errors.New("setting the debug socket is not supported by the dummy client")
+}
+
+// GetLocalPINStatus simulates GetLocalPINStatus using the provided parameters.
+func (dc *DummyClient) GetLocalPINStatus(ctx context.Context, in *authd.GLPSRequest, opts ...grpc.CallOption) (*authd.GLPSResponse, error) {
+	log.Debugf(ctx, "GetLocalPINStatus Called: %#v", in)
+	if in == nil {
+		return nil, errors.New("no input values provided")
+	}
+	if in.Username == "" {
+		return nil, errors.New("no valid username provided")
+	}
+	if dc.localPIN == "" {
+		return &authd.GLPSResponse{}, nil
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	return &authd.GLPSResponse{
+		Registered:     true,
+		Usable:         dc.localPINFailedAttempts < dc.localPINMaxAttempts,
+		FailedAttempts: uint32(dc.localPINFailedAttempts),
+	}, nil
+}
+
+// AuthenticateWithLocalPIN simulates AuthenticateWithLocalPIN using the provided parameters.
+func (dc *DummyClient) AuthenticateWithLocalPIN(ctx context.Context, in *authd.ALPRequest, opts ...grpc.CallOption) (*authd.ALPResponse, error) {
+	log.Debugf(ctx, "AuthenticateWithLocalPIN Called: %#v", in)
+	if in == nil {
+		return nil, errors.New("no input values provided")
+	}
+	if in.Username == "" {
+		return nil, errors.New("no valid username provided")
+	}
+	if dc.localPIN == "" {
+		return nil, fmt.Errorf("user %q has no local PIN", in.Username)
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.localPINFailedAttempts >= dc.localPINMaxAttempts {
+		return &authd.ALPResponse{Msg: "Too many failed attempts"}, nil
+	}
+	if in.Pin != dc.localPIN {
+		dc.localPINFailedAttempts++
+		return &authd.ALPResponse{Msg: "Invalid PIN"}, nil
+	}
+	dc.localPINFailedAttempts = 0
+	return &authd.ALPResponse{Granted: true}, nil
+}
+
+// SetLocalPIN is not supported by the dummy client, as the PAM module never registers local PINs.
+func (dc *DummyClient) SetLocalPIN(ctx context.Context, in *authd.SLPRequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "SetLocalPIN Called: %#v", in)
+	return nil, errors.New("local PINs are not supported by the dummy client")
+}
+
+// RemoveLocalPIN is not supported by the dummy client, as the PAM module never removes local PINs.
+func (dc *DummyClient) RemoveLocalPIN(ctx context.Context, in *authd.RLPRequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "RemoveLocalPIN Called: %#v", in)
+	return nil, errors.New("local PINs are not supported by the dummy client")
+}
+
+// SetUserGecos is not supported by the dummy client, as the PAM module never changes the GECOS of the users.
+func (dc *DummyClient) SetUserGecos(ctx context.Context, in *authd.SUGRequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "SetUserGecos Called: %#v", in)
+	return nil, errors.New("changing the GECOS of users is not supported by the dummy client")
+}
+
+// SetUserShell is not supported by the dummy client, as the PAM module never changes the login shell of the users.
+func (dc *DummyClient) SetUserShell(ctx context.Context, in *authd.SUSRequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "SetUserShell Called: %#v", in)
+	return nil, errors.New("changing the login shell of users is not supported by the dummy client")
+}
+
+// SetUserHome is not supported by the dummy client, as the PAM module never changes the home directory of the users.
+func (dc *DummyClient) SetUserHome(ctx context.Context, in *authd.SUHRequest, opts ...grpc.CallOption) (*authd.SUHResponse, error) {
+	log.Debugf(ctx, "SetUserHome Called: %#v", in)
+	return nil, errors.New("changing the home directory of users is not supported by the dummy client")
+}
+
+// SetUserOverride is not supported by the dummy client, as the PAM module never overrides the attributes of the users.
+func (dc *DummyClient) SetUserOverride(ctx context.Context, in *authd.SUORequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "SetUserOverride Called: %#v", in)
+	return nil, errors.New("user overrides are not supported by the dummy client")
+}
+
+// UnsetUserOverride is not supported by the dummy client, as the PAM module never overrides the attributes of the
+// users.
+func (dc *DummyClient) UnsetUserOverride(ctx context.Context, in *authd.UUORequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "UnsetUserOverride Called: %#v", in)
+	return nil, errors.New("user overrides are not supported by the dummy client")
+}
+
+// ListUserOverrides is not supported by the dummy client, as the PAM module never overrides the attributes of the
+// users.
+func (dc *DummyClient) ListUserOverrides(ctx context.Context, in *authd.Empty, opts ...grpc.CallOption) (*authd.LUOResponse, error) {
+	log.Debugf(ctx, "ListUserOverrides Called: %#v", in)
+	return nil, errors.New("user overrides are not supported by the dummy client")
+}
+
+// WhoisUID is not supported by the dummy client, as the PAM module never looks up the owners of UIDs.
+func (dc *DummyClient) WhoisUID(ctx context.Context, in *authd.WUIDRequest, opts ...grpc.CallOption) (*authd.WUIDResponse, error) {
+	log.Debugf(ctx, "WhoisUID Called: %#v", in)
+	return nil, errors.New("UID lookups are not supported by the dummy client")
+}
+
+// WhoisID is not supported by the dummy client, as the PAM module never looks up the owners of IDs.
+func (dc *DummyClient) WhoisID(ctx context.Context, in *authd.WIDRequest, opts ...grpc.CallOption) (*authd.WIDResponse, error) {
+	log.Debugf(ctx, "WhoisID Called: %#v", in)
+	return nil, errors.New("ID lookups are not supported by the dummy client")
+}
+
+// GetFailedAuthentications is not supported by the dummy client, as the PAM module never shows the failed
+// authentications of the users.
+func (dc *DummyClient) GetFailedAuthentications(ctx context.Context, in *authd.GFARequest, opts ...grpc.CallOption) (*authd.GFAResponse, error) {
+	log.Debugf(ctx, "GetFailedAuthentications Called: %#v", in)
+	return nil, errors.New("failed authentications are not supported by the dummy client")
+}
+
+// AdoptUser is not supported by the dummy client, as the PAM module never migrates local users.
+func (dc *DummyClient) AdoptUser(ctx context.Context, in *authd.AURequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "AdoptUser Called: %#v", in)
+	return nil, errors.New("user adoptions are not supported by the dummy client")
+}
+
+// ChangeUID is not supported by the dummy client, as the PAM module never changes the UIDs of the users.
+func (dc *DummyClient) ChangeUID(ctx context.Context, in *authd.CUIDRequest, opts ...grpc.CallOption) (*authd.CUIDResponse, error) {
+	log.Debugf(ctx, "ChangeUID Called: %#v", in)
+	return nil, errors.New("UID changes are not supported by the dummy client")
+}
+
+// ReconcileLocalGroups is not supported by the dummy client, as the PAM module never changes the local groups.
+func (dc *DummyClient) ReconcileLocalGroups(ctx context.Context, in *authd.Empty, opts ...grpc.CallOption) (*authd.RLGResponse, error) {
+	log.Debugf(ctx, "ReconcileLocalGroups Called: %#v", in)
+	return nil, errors.New("reconciling local groups is not supported by the dummy client")
+}
+
+// GetUserAccountInfo is not supported by the dummy client, as the PAM module never shows the account settings of the
+// users.
+func (dc *DummyClient) GetUserAccountInfo(ctx context.Context, in *authd.GUAIRequest, opts ...grpc.CallOption) (*authd.GUAIResponse, error) {
+	log.Debugf(ctx, "GetUserAccountInfo Called: %#v", in)
+	return nil, errors.New("account information is not supported by the dummy client")
+}
+
+// SetUserDisplayName is not supported by the dummy client, as the PAM module never changes the display name of the
+// users.
+func (dc *DummyClient) SetUserDisplayName(ctx context.Context, in *authd.SUDNRequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "SetUserDisplayName Called: %#v", in)
+	return nil, errors.New("setting the display name is not supported by the dummy client")
+}
+
+// SetUserAvatar is not supported by the dummy client, as the PAM module never changes the avatar of the users.
+func (dc *DummyClient) SetUserAvatar(ctx context.Context, in *authd.SUARequest, opts ...grpc.CallOption) (*authd.Empty, error) {
+	log.Debugf(ctx, "SetUserAvatar Called: %#v", in)
+	return nil, errors.New("setting the avatar is not supported by the dummy client")
+}
+
 // Utility functions for testing purposes.
 
 // SelectedUsername returns the selected Username on the client.
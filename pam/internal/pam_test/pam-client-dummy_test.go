@@ -1003,7 +1003,8 @@ func TestIsAuthenticated(t *testing.T) {
 					},
 				},
 			},
-			wantError: rsa.ErrDecryption,
+			// The error is wrapped by the encryption package, that the dummy client uses as the brokers do.
+			wantError: fmt.Errorf("could not decrypt secret: %w", rsa.ErrDecryption),
 		},
 	}
 	for name, tc := range testCases {
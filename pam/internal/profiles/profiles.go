@@ -0,0 +1,121 @@
+// Package profiles loads the behavior profiles of the PAM module, which adapt the authentication to the PAM service
+// loading it, so that for instance sudo can be restricted to some authentication modes while GDM allows all of them.
+package profiles
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ubuntu/decorate"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is the default path of the file defining the profiles, indexed by PAM service.
+const DefaultPath = "/etc/authd/pam-profiles.yaml"
+
+const (
+	// UINative makes the module use the native PAM conversation instead of its interactive UI.
+	UINative = "native"
+	// UIPlain makes the module use undecorated sequential prompts, for screen readers.
+	UIPlain = "plain"
+)
+
+// Profile is the behavior of the PAM module for a PAM service.
+type Profile struct {
+	// AllowedModes are the IDs of the only authentication modes the users can select, all of them if empty.
+	AllowedModes []string `yaml:"allowed_modes"`
+	// UI is the variant of the user interface, the one matching the client if empty.
+	UI string `yaml:"ui"`
+	// ConnectionTimeout is the time to wait for the daemon to accept the connection, the default one if 0.
+	ConnectionTimeout time.Duration `yaml:"connection_timeout"`
+	// GDMDrainTimeout is the time to wait for the GDM conversations to complete on exit, the default one if 0.
+	GDMDrainTimeout time.Duration `yaml:"gdm_drain_timeout"`
+	// SkipBrokerSelection leaves the users who never used any broker to the other PAM modules, instead of asking them
+	// to select one.
+	SkipBrokerSelection bool `yaml:"skip_broker_selection"`
+	// SelectBroker asks the users to select their broker on each login, instead of selecting the one they previously
+	// used.
+	SelectBroker bool `yaml:"select_broker"`
+}
+
+// Load returns the profiles defined in the file at path, indexed by PAM service. There is no profile if the file
+// doesn't exist.
+func Load(path string) (profiles map[string]Profile, err error) {
+	defer decorate.OnError(&err, "could not load PAM profiles from %q", path)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&profiles); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	for service, p := range profiles {
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("invalid profile for service %q: %v", service, err)
+		}
+	}
+	return profiles, nil
+}
+
+// validate returns an error if the profile can't be applied.
+func (p Profile) validate() error {
+	switch p.UI {
+	case "", UINative, UIPlain:
+	default:
+		return fmt.Errorf("unknown UI %q, expected %q or %q", p.UI, UINative, UIPlain)
+	}
+	if p.ConnectionTimeout < 0 {
+		return fmt.Errorf("negative connection timeout %v", p.ConnectionTimeout)
+	}
+	if p.GDMDrainTimeout < 0 {
+		return fmt.Errorf("negative GDM drain timeout %v", p.GDMDrainTimeout)
+	}
+	for _, m := range p.AllowedModes {
+		if m == "" || strings.Contains(m, ",") {
+			return fmt.Errorf("invalid authentication mode ID %q", m)
+		}
+	}
+	return nil
+}
+
+// Args returns the arguments of the module applying the profile.
+func (p Profile) Args() map[string]string {
+	args := make(map[string]string)
+	if len(p.AllowedModes) > 0 {
+		args["allowed_modes"] = strings.Join(p.AllowedModes, ",")
+	}
+	switch p.UI {
+	case UINative:
+		args["force_native_client"] = "true"
+	case UIPlain:
+		args["plain_prompts"] = "true"
+	}
+	if p.ConnectionTimeout > 0 {
+		args["connection_timeout"] = strconv.FormatInt(p.ConnectionTimeout.Milliseconds(), 10)
+	}
+	if p.GDMDrainTimeout > 0 {
+		args["gdm_drain_timeout"] = strconv.FormatInt(p.GDMDrainTimeout.Milliseconds(), 10)
+	}
+	if p.SkipBrokerSelection {
+		args["skip_broker_selection"] = "true"
+	}
+	if p.SelectBroker {
+		args["select_broker"] = "true"
+	}
+	return args
+}
@@ -0,0 +1,120 @@
+package profiles_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/pam/internal/profiles"
+)
+
+const validProfiles = `
+sudo:
+  allowed_modes: [totp]
+  ui: native
+  connection_timeout: 5s
+  skip_broker_selection: true
+gdm-password:
+  gdm_drain_timeout: 1500ms
+login:
+  ui: plain
+`
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content string
+		noFile  bool
+
+		want    map[string]profiles.Profile
+		wantErr bool
+	}{
+		"Load_profiles": {
+			content: validProfiles,
+			want: map[string]profiles.Profile{
+				"sudo": {
+					AllowedModes:        []string{"totp"},
+					UI:                  profiles.UINative,
+					ConnectionTimeout:   5 * time.Second,
+					SkipBrokerSelection: true,
+				},
+				"gdm-password": {GDMDrainTimeout: 1500 * time.Millisecond},
+				"login":        {UI: profiles.UIPlain},
+			},
+		},
+		"No_profile_if_file_does_not_exist":    {noFile: true},
+		"No_profile_if_file_is_empty":          {content: ""},
+		"No_profile_if_file_has_only_comments": {content: "## Profiles\n#sudo:\n#  ui: native\n"},
+
+		"Error_on_invalid_YAML":               {content: "sudo: [", wantErr: true},
+		"Error_on_unknown_field":              {content: "sudo: {unknown: true}", wantErr: true},
+		"Error_on_unknown_UI":                 {content: "sudo: {ui: fancy}", wantErr: true},
+		"Error_on_invalid_timeout":            {content: "sudo: {connection_timeout: soon}", wantErr: true},
+		"Error_on_negative_timeout":           {content: "sudo: {connection_timeout: -1s}", wantErr: true},
+		"Error_on_empty_authentication_mode":  {content: `sudo: {allowed_modes: [""]}`, wantErr: true},
+		"Error_on_authentication_mode_commas": {content: `sudo: {allowed_modes: ["totp,password"]}`, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "pam-profiles.yaml")
+			if !tc.noFile {
+				err := os.WriteFile(path, []byte(tc.content), 0600)
+				require.NoError(t, err, "Setup: could not write profiles file")
+			}
+
+			got, err := profiles.Load(path)
+			if tc.wantErr {
+				require.Error(t, err, "Load should return an error, but did not")
+				return
+			}
+			require.NoError(t, err, "Load should not return an error, but did")
+			require.Equal(t, tc.want, got, "Load returned unexpected profiles")
+		})
+	}
+}
+
+func TestProfileArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		profile profiles.Profile
+
+		want map[string]string
+	}{
+		"No_arguments_for_empty_profile": {want: map[string]string{}},
+		"Arguments_of_all_fields": {
+			profile: profiles.Profile{
+				AllowedModes:        []string{"totp", "password"},
+				UI:                  profiles.UINative,
+				ConnectionTimeout:   5 * time.Second,
+				GDMDrainTimeout:     1500 * time.Millisecond,
+				SkipBrokerSelection: true,
+				SelectBroker:        true,
+			},
+			want: map[string]string{
+				"allowed_modes":         "totp,password",
+				"force_native_client":   "true",
+				"connection_timeout":    "5000",
+				"gdm_drain_timeout":     "1500",
+				"skip_broker_selection": "true",
+				"select_broker":         "true",
+			},
+		},
+		"Plain_prompts_for_plain_UI": {
+			profile: profiles.Profile{UI: profiles.UIPlain},
+			want:    map[string]string{"plain_prompts": "true"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, tc.profile.Args(), "Args returned unexpected arguments")
+		})
+	}
+}
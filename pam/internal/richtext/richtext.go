@@ -0,0 +1,96 @@
+// Package richtext handles the subset of markdown the brokers can use in the labels and messages shown to the users:
+// bold text, lists, and links, which are rendered as footnotes in the terminals.
+package richtext
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var (
+	// escapeSequenceRe matches the terminal escape sequences, like the CSI (colors, cursor moves) and OSC (titles,
+	// hyperlinks) ones.
+	escapeSequenceRe = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)?|.)`)
+	// htmlTagRe matches the HTML tags and comments.
+	htmlTagRe = regexp.MustCompile(`<!--.*?-->|</?[a-zA-Z][^<>\n]*>`)
+	// linkRe matches the links, with their text and their URL, which can contain balanced parentheses.
+	linkRe = regexp.MustCompile(`\[([^\[\]\n]*)\]\(([^()\s]*(?:\([^()\s]*\)[^()\s]*)*)\)`)
+	// boldRe matches the bold text, with either asterisks or underscores.
+	boldRe = regexp.MustCompile(`\*\*([^*\n]+)\*\*|__([^_\n]+)__`)
+	// listItemRe matches the items of the unordered and ordered lists, with their marker and text.
+	listItemRe = regexp.MustCompile(`^\s*([-*+]|\d+\.)\s+(.*)$`)
+)
+
+// linkSchemes are the schemes of the URLs the links can point to.
+var linkSchemes = []string{"http", "https", "mailto"}
+
+// Sanitize returns the text keeping only the supported subset of markdown. The terminal escape sequences, the control
+// characters and the HTML tags are removed, and the links to other URLs than the web and mail ones are replaced by their
+// text.
+func Sanitize(s string) string {
+	s = escapeSequenceRe.ReplaceAllString(s, "")
+	s = strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	s = htmlTagRe.ReplaceAllString(s, "")
+
+	return linkRe.ReplaceAllStringFunc(s, func(link string) string {
+		m := linkRe.FindStringSubmatch(link)
+		u, err := url.Parse(m[2])
+		if err != nil || !slices.Contains(linkSchemes, strings.ToLower(u.Scheme)) {
+			return m[1]
+		}
+		return link
+	})
+}
+
+// Render returns the sanitized text rendered for a terminal. The bold text is styled with bold, if not nil, the list
+// items are prefixed by bullets, and the links are replaced by their text with a reference to the footnote listing
+// their URL at the end of the text.
+func Render(s string, bold func(string) string) string {
+	var footnotes []string
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if m := listItemRe.FindStringSubmatch(line); m != nil {
+			marker := "•"
+			if strings.HasSuffix(m[1], ".") {
+				marker = m[1]
+			}
+			line = fmt.Sprintf("  %s %s", marker, m[2])
+		}
+
+		line = linkRe.ReplaceAllStringFunc(line, func(link string) string {
+			m := linkRe.FindStringSubmatch(link)
+			text, target := m[1], m[2]
+			if text == "" || text == target {
+				return target
+			}
+			footnotes = append(footnotes, target)
+			return fmt.Sprintf("%s[%d]", text, len(footnotes))
+		})
+
+		lines[i] = boldRe.ReplaceAllStringFunc(line, func(b string) string {
+			m := boldRe.FindStringSubmatch(b)
+			text := m[1] + m[2]
+			if bold == nil {
+				return text
+			}
+			return bold(text)
+		})
+	}
+
+	if len(footnotes) > 0 {
+		lines = append(lines, "")
+		for i, f := range footnotes {
+			lines = append(lines, fmt.Sprintf("[%d] %s", i+1, f))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,76 @@
+package richtext_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/pam/internal/richtext"
+)
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		text string
+
+		want string
+	}{
+		"Keep_plain_text":                {text: "Enter your password", want: "Enter your password"},
+		"Keep_supported_markdown":        {text: "**Warning**\n- item\n[docs](https://example.com)", want: "**Warning**\n- item\n[docs](https://example.com)"},
+		"Keep_mail_links":                {text: "[IT](mailto:it@example.com)", want: "[IT](mailto:it@example.com)"},
+		"Keep_comparison_signs":          {text: "a < b and c > d", want: "a < b and c > d"},
+		"Remove_HTML_tags":               {text: "<b>bold</b> <script>alert(1)</script>", want: "bold alert(1)"},
+		"Remove_HTML_comments":           {text: "visible<!-- hidden -->", want: "visible"},
+		"Remove_terminal_colors":         {text: "\x1b[31mred\x1b[0m", want: "red"},
+		"Remove_terminal_hyperlinks":     {text: "\x1b]8;;https://evil.example\x1b\\click\x1b]8;;\x1b\\", want: "click"},
+		"Remove_control_characters":      {text: "bell\a and\rreturn", want: "bell andreturn"},
+		"Keep_newlines_and_tabs":         {text: "line\n\tindented", want: "line\n\tindented"},
+		"Replace_unsafe_links_by_text":   {text: "[click](javascript:alert(1)) [file](file:///etc/passwd)", want: "click file"},
+		"Replace_relative_links_by_text": {text: "[help](/help)", want: "help"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := richtext.Sanitize(tc.text)
+			require.Equal(t, tc.want, got, "Sanitize returned an unexpected text")
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	bold := func(s string) string { return "<" + s + ">" }
+
+	tests := map[string]struct {
+		text    string
+		noStyle bool
+
+		want string
+	}{
+		"Render_plain_text":                {text: "Enter your password", want: "Enter your password"},
+		"Render_bold_text":                 {text: "This is **important** and __urgent__", want: "This is <important> and <urgent>"},
+		"Render_bold_text_without_style":   {text: "This is **important**", noStyle: true, want: "This is important"},
+		"Render_unordered_list":            {text: "Rules:\n- one\n* two\n+ three", want: "Rules:\n  • one\n  • two\n  • three"},
+		"Render_ordered_list":              {text: "Steps:\n1. one\n2. two", want: "Steps:\n  1. one\n  2. two"},
+		"Render_links_as_footnotes":        {text: "Read the [policy](https://example.com/policy) or [mail us](mailto:it@example.com)", want: "Read the policy[1] or mail us[2]\n\n[1] https://example.com/policy\n[2] mailto:it@example.com"},
+		"Render_links_without_text_inline": {text: "Go to [](https://example.com)", want: "Go to https://example.com"},
+		"Render_links_to_their_URL_inline": {text: "Go to [https://example.com](https://example.com)", want: "Go to https://example.com"},
+		"Render_bold_links_in_list_items":  {text: "- **Read** the [policy](https://example.com)", want: "  • <Read> the policy[1]\n\n[1] https://example.com"},
+		"Render_unclosed_bold_as_is":       {text: "2 ** 3", want: "2 ** 3"},
+		"Render_links_with_parentheses":    {text: "[Go](https://en.wikipedia.org/wiki/Go_(game))", want: "Go[1]\n\n[1] https://en.wikipedia.org/wiki/Go_(game)"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			style := bold
+			if tc.noStyle {
+				style = nil
+			}
+			got := richtext.Render(tc.text, style)
+			require.Equal(t, tc.want, got, "Render returned an unexpected text")
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Package usersession implements the actions performed by the PAM module when a session of a user is opened.
+package usersession
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/decorate"
+)
+
+const (
+	// runtimeDirPerms are the permissions of the XDG runtime directory, as required by the XDG base directory
+	// specification.
+	runtimeDirPerms = 0700
+
+	loginDBusName      = "org.freedesktop.login1"
+	loginDBusPath      = "/org/freedesktop/login1"
+	loginDBusInterface = "org.freedesktop.login1.Manager"
+)
+
+type options struct {
+	runtimeDirBase string
+}
+
+// Option is a function that allows changing some of the default behaviors of the package.
+type Option func(*options)
+
+// WithRuntimeDirBase overrides the directory containing the XDG runtime directories of the users.
+func WithRuntimeDirBase(path string) Option {
+	return func(o *options) {
+		o.runtimeDirBase = path
+	}
+}
+
+// EnsureRuntimeDir makes sure that the XDG runtime directory of the user exists, with the expected permissions, and
+// is owned by them. As user IDs can be reused, a directory left by a previous owner of the UID is handed over.
+// It returns the path of the directory.
+func EnsureRuntimeDir(uid, gid uint32, args ...Option) (dir string, err error) {
+	defer decorate.OnError(&err, "could not ensure the runtime directory of user %d", uid)
+
+	opts := options{runtimeDirBase: "/run/user"}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	dir = filepath.Join(opts.runtimeDirBase, strconv.FormatUint(uint64(uid), 10))
+	fi, err := os.Lstat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Debugf(context.TODO(), "Creating runtime directory %q", dir)
+		if err := os.Mkdir(dir, runtimeDirPerms); err != nil {
+			return "", err
+		}
+		fi, err = os.Lstat(dir)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("%q is not a directory", dir)
+	}
+
+	if stat, ok := fi.Sys().(*syscall.Stat_t); !ok || stat.Uid != uid || stat.Gid != gid {
+		log.Infof(context.TODO(), "Changing the owner of runtime directory %q to %d:%d", dir, uid, gid)
+		if err := os.Lchown(dir, int(uid), int(gid)); err != nil {
+			return "", err
+		}
+	}
+	if fi.Mode().Perm() != runtimeDirPerms {
+		if err := os.Chmod(dir, runtimeDirPerms); err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// EnableLinger enables the systemd lingering of the user, so that their user manager, and so their services, keep
+// running once they log out.
+func EnableLinger(uid uint32) (err error) {
+	defer decorate.OnError(&err, "could not enable lingering of user %d", uid)
+
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	obj := conn.Object(loginDBusName, loginDBusPath)
+	return obj.Call(loginDBusInterface+".SetUserLinger", dbus.FlagNoAutoStart, uid, true, false).Err
+}
@@ -0,0 +1,69 @@
+package usersession_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/pam/internal/usersession"
+)
+
+func TestEnsureRuntimeDir(t *testing.T) {
+	t.Parallel()
+
+	// We can only hand over the directories to ourselves, unless we're root.
+	uid, gid := uint32(os.Getuid()), uint32(os.Getgid())
+
+	tests := map[string]struct {
+		existingPerms os.FileMode
+		existingFile  bool
+		noBase        bool
+
+		wantErr bool
+	}{
+		"Creates_missing_runtime_dir":             {},
+		"Keeps_existing_runtime_dir":              {existingPerms: 0700},
+		"Fixes_permissions_of_existing_directory": {existingPerms: 0755},
+
+		"Error_when_runtime_dir_is_not_a_directory": {existingFile: true, wantErr: true},
+		"Error_when_base_directory_does_not_exist":  {noBase: true, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			base := t.TempDir()
+			wantDir := filepath.Join(base, strconv.FormatUint(uint64(uid), 10))
+			if tc.noBase {
+				base = filepath.Join(base, "missing")
+			}
+			if tc.existingPerms != 0 {
+				require.NoError(t, os.Mkdir(wantDir, tc.existingPerms), "Setup: could not create runtime directory")
+				require.NoError(t, os.Chmod(wantDir, tc.existingPerms), "Setup: could not set runtime directory permissions")
+			}
+			if tc.existingFile {
+				require.NoError(t, os.WriteFile(wantDir, nil, 0600), "Setup: could not create file")
+			}
+
+			dir, err := usersession.EnsureRuntimeDir(uid, gid, usersession.WithRuntimeDirBase(base))
+			if tc.wantErr {
+				require.Error(t, err, "EnsureRuntimeDir should have failed")
+				return
+			}
+			require.NoError(t, err, "EnsureRuntimeDir should not fail")
+			require.Equal(t, wantDir, dir, "EnsureRuntimeDir returned an unexpected directory")
+
+			fi, err := os.Stat(dir)
+			require.NoError(t, err, "Runtime directory should exist")
+			require.True(t, fi.IsDir(), "Runtime directory should be a directory")
+			require.Equal(t, os.FileMode(0700), fi.Mode().Perm(), "Runtime directory has unexpected permissions")
+			stat, ok := fi.Sys().(*syscall.Stat_t)
+			require.True(t, ok, "Setup: could not get runtime directory owner")
+			require.Equal(t, uid, stat.Uid, "Runtime directory has unexpected owner")
+			require.Equal(t, gid, stat.Gid, "Runtime directory has unexpected group")
+		})
+	}
+}
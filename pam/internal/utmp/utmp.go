@@ -0,0 +1,159 @@
+// Package utmp records the user sessions in the utmp and wtmp databases, so that they are listed by who and last.
+package utmp
+
+/*
+#define _GNU_SOURCE
+#include <stdlib.h>
+#include <sys/time.h>
+#include <utmpx.h>
+
+static const char *default_utmp_file(void) { return _PATH_UTMPX; }
+static const char *default_wtmp_file(void) { return _PATH_WTMPX; }
+
+static void set_current_time(struct utmpx *ut)
+{
+  struct timeval tv;
+
+  gettimeofday(&tv, NULL);
+  ut->ut_tv.tv_sec = tv.tv_sec;
+  ut->ut_tv.tv_usec = tv.tv_usec;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/ubuntu/decorate"
+)
+
+// Entry is a user session to record.
+type Entry struct {
+	// User is the name of the user logged in.
+	User string
+	// Line is the terminal of the session, without the /dev/ prefix.
+	Line string
+	// Host is the remote host the user logged in from, if any.
+	Host string
+	// PID is the process handling the session, it must be alive for the session to be listed by who.
+	PID int
+}
+
+// utmpMu protects the utmp database file, which is a global state of the C library.
+var utmpMu sync.Mutex
+
+type options struct {
+	utmpFile string
+	wtmpFile string
+}
+
+// Option is a function that allows changing some of the default behaviors of the package.
+type Option func(*options)
+
+// WithUtmpFile overrides the default utmp database file.
+func WithUtmpFile(path string) Option {
+	return func(o *options) {
+		o.utmpFile = path
+	}
+}
+
+// WithWtmpFile overrides the default wtmp database file.
+func WithWtmpFile(path string) Option {
+	return func(o *options) {
+		o.wtmpFile = path
+	}
+}
+
+// Login records that the user logged in.
+func Login(e Entry, args ...Option) (err error) {
+	defer decorate.OnError(&err, "could not record the session of %q on %q", e.User, e.Line)
+
+	if e.User == "" {
+		return errors.New("no user provided")
+	}
+
+	ut := newUtmpx(C.USER_PROCESS, e.Line, e.PID)
+	setField(ut.ut_user[:], e.User)
+	setField(ut.ut_host[:], e.Host)
+
+	return write(&ut, args...)
+}
+
+// Logout records that the session on the line, handled by the process, ended.
+func Logout(line string, pid int, args ...Option) (err error) {
+	defer decorate.OnError(&err, "could not record the end of the session on %q", line)
+
+	ut := newUtmpx(C.DEAD_PROCESS, line, pid)
+	return write(&ut, args...)
+}
+
+// newUtmpx returns an entry of the given type for the line.
+func newUtmpx(utType C.short, line string, pid int) C.struct_utmpx {
+	var ut C.struct_utmpx
+	ut.ut_type = utType
+	ut.ut_pid = C.pid_t(pid)
+	setField(ut.ut_line[:], line)
+	setField(ut.ut_id[:], lineID(line))
+	C.set_current_time(&ut)
+	return ut
+}
+
+// lineID returns the identifier of the entries of the line, which is its suffix as done by login.
+func lineID(line string) string {
+	line = strings.TrimPrefix(line, "tty")
+	if len(line) > len(C.struct_utmpx{}.ut_id) {
+		return line[len(line)-len(C.struct_utmpx{}.ut_id):]
+	}
+	return line
+}
+
+// setField sets the value of a fixed size field of an entry, truncating it if needed.
+func setField(field []C.char, value string) {
+	clear(field)
+	for i := 0; i < len(value) && i < len(field); i++ {
+		field[i] = C.char(value[i])
+	}
+}
+
+// write updates the entry of the line in the utmp database, and appends it to the wtmp one.
+func write(ut *C.struct_utmpx, args ...Option) error {
+	if ut.ut_line[0] == 0 {
+		return errors.New("no line provided")
+	}
+
+	opts := options{
+		utmpFile: C.GoString(C.default_utmp_file()),
+		wtmpFile: C.GoString(C.default_wtmp_file()),
+	}
+	for _, f := range args {
+		f(&opts)
+	}
+
+	utmpMu.Lock()
+	defer utmpMu.Unlock()
+
+	utmpFile := C.CString(opts.utmpFile)
+	defer C.free(unsafe.Pointer(utmpFile))
+	if C.utmpxname(utmpFile) != 0 {
+		return errors.New("could not set the utmp database")
+	}
+
+	C.setutxent()
+	res, err := C.pututxline(ut)
+	C.endutxent()
+	if res == nil {
+		if err == nil {
+			err = errors.New("unknown error")
+		}
+		return err
+	}
+
+	wtmpFile := C.CString(opts.wtmpFile)
+	defer C.free(unsafe.Pointer(wtmpFile))
+	C.updwtmpx(wtmpFile, ut)
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package utmp_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/pam/internal/utmp"
+)
+
+const (
+	userProcess = 7
+	deadProcess = 8
+)
+
+// record is the layout of the utmpx entries in the database files on Linux.
+type record struct {
+	Type    int16
+	_       int16
+	PID     int32
+	Line    [32]byte
+	ID      [4]byte
+	User    [32]byte
+	Host    [256]byte
+	Exit    [2]int16
+	Session int32
+	Sec     int32
+	Usec    int32
+	Addr    [4]int32
+	_       [20]byte
+}
+
+type wantRecord struct {
+	typ  int16
+	pid  int32
+	line string
+	id   string
+	user string
+	host string
+}
+
+func TestLoginAndLogout(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		entry  utmp.Entry
+		logout bool
+
+		wantUtmp []wantRecord
+		wantWtmp []wantRecord
+		wantErr  bool
+	}{
+		"Records_a_login": {
+			entry:    utmp.Entry{User: "user1", Line: "pts/3", Host: "host.example.com", PID: 42},
+			wantUtmp: []wantRecord{{typ: userProcess, pid: 42, line: "pts/3", id: "ts/3", user: "user1", host: "host.example.com"}},
+			wantWtmp: []wantRecord{{typ: userProcess, pid: 42, line: "pts/3", id: "ts/3", user: "user1", host: "host.example.com"}},
+		},
+		"Records_a_login_on_a_tty": {
+			entry:    utmp.Entry{User: "user1", Line: "tty2", PID: 42},
+			wantUtmp: []wantRecord{{typ: userProcess, pid: 42, line: "tty2", id: "2", user: "user1"}},
+			wantWtmp: []wantRecord{{typ: userProcess, pid: 42, line: "tty2", id: "2", user: "user1"}},
+		},
+		"Truncates_long_values": {
+			entry: utmp.Entry{User: "user-with-a-very-long-name-which-does-not-fit", Line: "pts/3", PID: 42},
+			wantUtmp: []wantRecord{{typ: userProcess, pid: 42, line: "pts/3", id: "ts/3",
+				user: "user-with-a-very-long-name-which"}},
+			wantWtmp: []wantRecord{{typ: userProcess, pid: 42, line: "pts/3", id: "ts/3",
+				user: "user-with-a-very-long-name-which"}},
+		},
+		"Records_a_logout": {
+			entry:    utmp.Entry{User: "user1", Line: "pts/3", Host: "host.example.com", PID: 42},
+			logout:   true,
+			wantUtmp: []wantRecord{{typ: deadProcess, pid: 42, line: "pts/3", id: "ts/3"}},
+			wantWtmp: []wantRecord{
+				{typ: userProcess, pid: 42, line: "pts/3", id: "ts/3", user: "user1", host: "host.example.com"},
+				{typ: deadProcess, pid: 42, line: "pts/3", id: "ts/3"},
+			},
+		},
+
+		"Error_when_no_user_is_provided": {entry: utmp.Entry{Line: "pts/3", PID: 42}, wantErr: true},
+		"Error_when_no_line_is_provided": {entry: utmp.Entry{User: "user1", PID: 42}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			utmpFile := filepath.Join(t.TempDir(), "utmp")
+			wtmpFile := filepath.Join(t.TempDir(), "wtmp")
+			opts := []utmp.Option{utmp.WithUtmpFile(utmpFile), utmp.WithWtmpFile(wtmpFile)}
+			require.NoError(t, os.WriteFile(utmpFile, nil, 0600), "Setup: could not create utmp file")
+			// As for the system one, the wtmp database is not created if missing.
+			require.NoError(t, os.WriteFile(wtmpFile, nil, 0600), "Setup: could not create wtmp file")
+
+			err := utmp.Login(tc.entry, opts...)
+			if tc.wantErr {
+				require.Error(t, err, "Login should have failed")
+				return
+			}
+			require.NoError(t, err, "Login should not fail")
+
+			if tc.logout {
+				require.NoError(t, utmp.Logout(tc.entry.Line, tc.entry.PID, opts...), "Logout should not fail")
+			}
+
+			require.Equal(t, tc.wantUtmp, readRecords(t, utmpFile), "utmp records are not the expected ones")
+			require.Equal(t, tc.wantWtmp, readRecords(t, wtmpFile), "wtmp records are not the expected ones")
+		})
+	}
+}
+
+func readRecords(t *testing.T, path string) []wantRecord {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err, "Setup: could not open database")
+	defer f.Close()
+
+	str := func(b []byte) string { return string(bytes.TrimRight(b, "\x00")) }
+
+	var records []wantRecord
+	for {
+		var r record
+		err := binary.Read(f, binary.NativeEndian, &r)
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+		require.NoError(t, err, "Setup: could not read record")
+		require.NotZero(t, r.Sec, "Record time should be set")
+
+		records = append(records, wantRecord{
+			typ:  r.Type,
+			pid:  r.PID,
+			line: str(r.Line[:]),
+			id:   str(r.ID[:]),
+			user: str(r.User[:]),
+			host: str(r.Host[:]),
+		})
+	}
+}
@@ -56,6 +56,9 @@ func mainFunc() error {
 
 	action, args := args[0], args[1:]
 
+	// The sessions are handled by the process which loaded the module, not by us.
+	applicationPID = os.Getppid
+
 	flags := pam.Flags(0)
 	if pamFlags != nil {
 		flags = pam.Flags(*pamFlags)
@@ -16,14 +16,18 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/coreos/go-systemd/v22/journal"
 	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/authderrors"
 	"github.com/ubuntu/authd/internal/brokers"
 	"github.com/ubuntu/authd/internal/consts"
 	"github.com/ubuntu/authd/internal/grpcutils"
+	"github.com/ubuntu/authd/internal/i18n"
 	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/compat"
 	"github.com/ubuntu/authd/internal/services/errmessages"
 	"github.com/ubuntu/authd/log"
 	"github.com/ubuntu/authd/pam/internal/adapter"
 	"github.com/ubuntu/authd/pam/internal/gdm"
+	"github.com/ubuntu/authd/pam/internal/profiles"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -53,13 +57,26 @@ const (
 )
 
 var supportedArgs = []string{
-	"debug",               // When this is set to "true", then debug logging is enabled.
-	"logfile",             // The path of the file that will be used for logging.
-	"disable_journal",     // Disable logging on systemd journal (this is implicit when `logfile` is set).
-	"socket",              // The authd socket to connect to.
-	"connection_timeout",  // The timeout on connecting to authd socket in milliseconds (defaults to 2 seconds).
-	"force_native_client", // Use native PAM client instead of custom UIs.
-	"force_reauth",        // Whether the authentication should be performed again even if it has been already completed.
+	"debug",                 // When this is set to "true", then debug logging is enabled.
+	"logfile",               // The path of the file that will be used for logging.
+	"disable_journal",       // Disable logging on systemd journal (this is implicit when `logfile` is set).
+	"socket",                // The authd socket to connect to.
+	"connection_timeout",    // The timeout on connecting to authd socket in milliseconds (defaults to 2 seconds).
+	"force_native_client",   // Use native PAM client instead of custom UIs.
+	"plain_prompts",         // Use undecorated sequential PAM prompts (implies native client on terminals), for screen readers.
+	"force_reauth",          // Whether the authentication should be performed again even if it has been already completed.
+	"gdm_drain_timeout",     // The time to wait for GDM conversations to complete on exit in milliseconds (defaults to 1 second).
+	"default_broker",        // The broker to select for users which never logged in, overriding the daemon one (empty to disable).
+	"utmp",                  // Whether the sessions of the users authenticated by authd are recorded in utmp and wtmp (defaults to true).
+	"use_first_pass",        // Authenticate with the password of the previous modules of the stack, failing if it's refused.
+	"try_first_pass",        // Authenticate with the password of the previous modules of the stack, prompting if it's refused.
+	"device_code_helpers",   // Copy the device codes to the clipboard and show their URL as hyperlinks, in the terminal emulators of graphical sessions.
+	"open_browser",          // Whether the verification URLs of the link-based modes are opened in the browser of the graphical session (defaults to true).
+	"browser_launcher",      // The absolute path of the command opening the verification URLs (defaults to xdg-open).
+	"allowed_modes",         // The comma-separated IDs of the only authentication modes the users can select.
+	"skip_broker_selection", // Leave the users who never used any broker to the other modules, instead of asking them to select one.
+	"select_broker",         // Ask the users to select their broker on each login, instead of selecting the one they previously used.
+	"profiles",              // The path of the file defining the behavior profiles of the PAM services (defaults to /etc/authd/pam-profiles.yaml).
 }
 
 // parseArgs parses the PAM arguments and returns a map of them and a function that logs the parsing issues.
@@ -85,6 +102,87 @@ func parseArgs(args []string) (map[string]string, func()) {
 	}
 }
 
+// applyProfile completes the arguments with the ones of the behavior profile of the PAM service, if any. The arguments
+// of the module take precedence over the ones of the profile.
+func applyProfile(mTx pam.ModuleTransaction, args map[string]string) {
+	serviceName, err := mTx.GetItem(pam.Service)
+	if err != nil || serviceName == "" {
+		return
+	}
+
+	path := profiles.DefaultPath
+	if p, ok := args["profiles"]; ok {
+		path = p
+	}
+	all, err := profiles.Load(path)
+	if err != nil {
+		log.Warningf(context.TODO(), "Ignoring PAM profiles: %v", err)
+		return
+	}
+	profile, ok := all[serviceName]
+	if !ok {
+		return
+	}
+
+	log.Debugf(context.TODO(), "Applying profile of PAM service %q", serviceName)
+	for arg, value := range profile.Args() {
+		if _, ok := args[arg]; !ok {
+			args[arg] = value
+		}
+	}
+}
+
+// allowedAuthModes returns the IDs of the only authentication modes the users can select, or nil if all are allowed.
+func allowedAuthModes(args map[string]string) []string {
+	if args["allowed_modes"] == "" {
+		return nil
+	}
+	return strings.Split(args["allowed_modes"], ",")
+}
+
+// firstPassPolicy returns how the password collected by the previous modules of the stack is used.
+func firstPassPolicy(args map[string]string) adapter.FirstPassPolicy {
+	// As for the other PAM modules, these arguments are usually passed without any value.
+	isSet := func(arg string) bool {
+		v, ok := args[arg]
+		return ok && (v == "" || v == "true")
+	}
+
+	switch {
+	case isSet("use_first_pass"):
+		return adapter.UseFirstPass
+	case isSet("try_first_pass"):
+		return adapter.TryFirstPass
+	default:
+		return adapter.IgnoreFirstPass
+	}
+}
+
+// browserLauncher returns the command opening the verification URLs, or an empty string if it's disabled.
+func browserLauncher(args map[string]string) string {
+	if args["open_browser"] == "false" {
+		return ""
+	}
+	if launcher, ok := args["browser_launcher"]; ok {
+		return launcher
+	}
+	return adapter.DefaultBrowserLauncher
+}
+
+// gdmDrainTimeout returns the time to wait for the GDM conversations to complete, if set.
+func gdmDrainTimeout(args map[string]string) time.Duration {
+	dt, ok := args["gdm_drain_timeout"]
+	if !ok {
+		return 0
+	}
+	t, err := strconv.Atoi(dt)
+	if err != nil || t <= 0 {
+		log.Warningf(context.Background(), "Impossible to parse GDM drain timeout %q, using default!", dt)
+		return 0
+	}
+	return time.Duration(t) * time.Millisecond
+}
+
 func showPamMessage(mTx pam.ModuleTransaction, style pam.Style, msg string) error {
 	switch style {
 	case pam.TextInfo, pam.ErrorMsg:
@@ -98,7 +196,7 @@ func showPamMessage(mTx pam.ModuleTransaction, style pam.Style, msg string) erro
 	return nil
 }
 
-func sendReturnMessageToPam(mTx pam.ModuleTransaction, retStatus adapter.PamReturnStatus) {
+func sendReturnMessageToPam(mTx pam.ModuleTransaction, retStatus adapter.PamReturnStatus, silent bool) {
 	msg := retStatus.Message()
 	if msg == "" {
 		return
@@ -109,11 +207,19 @@ func sendReturnMessageToPam(mTx pam.ModuleTransaction, retStatus adapter.PamRetu
 	case adapter.PamSuccess:
 		style = pam.TextInfo
 	case adapter.PamReturnError:
+		if rs.Status() == pam.ErrConv {
+			// There's no (working) conversation, so there's no point in trying to use it again.
+			return
+		}
 		if rs.Status() == pam.ErrIgnore {
 			style = pam.TextInfo
 		}
 	}
 
+	if silent && style == pam.TextInfo {
+		return
+	}
+
 	if err := showPamMessage(mTx, style, msg); err != nil {
 		log.Warningf(context.TODO(), "Impossible to send PAM message: %v", err)
 	}
@@ -219,9 +325,6 @@ func (h *pamModule) ChangeAuthTok(mTx pam.ModuleTransaction, flags pam.Flags, ar
 }
 
 func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTransaction, flags pam.Flags, parsedArgs map[string]string, logArgsIssues func()) (err error) {
-	// Initialize localization
-	// TODO
-
 	var pamClientType adapter.PamClientType
 	var teaOpts []tea.ProgramOption
 
@@ -238,6 +341,7 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 		return err
 	}
 	logArgsIssues()
+	applyProfile(mTx, parsedArgs)
 
 	if mode == authd.SessionMode_CHANGE_PASSWORD && flags&pam.PrelimCheck != 0 {
 		log.Debug(context.TODO(), "ChangeAuthTok, preliminary check")
@@ -259,7 +363,7 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 			if msgErr := showPamMessage(mTx, pam.ErrorMsg, err.Error()); msgErr != nil {
 				log.Warningf(context.TODO(), "Impossible to show PAM message: %v", msgErr)
 			}
-			return fmt.Errorf("%w: %w", pam.ErrSystem, err)
+			return fmt.Errorf("%w: %w", adapter.PamStatusFromError(err, pam.ErrSystem), err)
 		}
 
 		if response.GetPreviousBroker() == brokers.LocalBrokerName {
@@ -282,7 +386,10 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 		return pam.ErrIgnore
 	}
 
+	// When silent, we must not take over the terminal with the interactive UI.
+	isSilent := flags&pam.Silent != 0
 	forceNativeClient := parsedArgs["force_native_client"] == "true"
+	plainPrompts := parsedArgs["plain_prompts"] == "true"
 	if !forceNativeClient && gdm.IsPamExtensionSupported(gdm.PamExtensionCustomJSON) {
 		pamClientType = adapter.Gdm
 		modeOpts, err := adapter.TeaHeadlessOptions()
@@ -290,7 +397,7 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 			return fmt.Errorf("%w: can't create tea options: %w", pam.ErrSystem, err)
 		}
 		teaOpts = append(teaOpts, modeOpts...)
-	} else if !forceNativeClient && adapter.IsTerminalTTY(mTx) {
+	} else if !forceNativeClient && !plainPrompts && !isSilent && adapter.IsTerminalTTY(mTx) {
 		pamClientType = adapter.InteractiveTerminal
 		tty, cleanup := adapter.GetPamTTY(mTx)
 		defer cleanup()
@@ -313,12 +420,45 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 	}
 	defer closeConn()
 
+	if mode == authd.SessionMode_LOGIN && isRecentlyAuthenticated(mTx, authd.NewPAMClient(conn), serviceName) {
+		return nil
+	}
+	// GDM asks for the local PIN through its own protocol, when unlocking the screen.
+	if mode == authd.SessionMode_LOGIN && !isSilent && pamClientType != adapter.Gdm {
+		granted, err := authenticateWithLocalPIN(mTx, authd.NewPAMClient(conn), serviceName)
+		if err != nil {
+			return err
+		}
+		if granted {
+			return nil
+		}
+	}
+
+	// Each transaction uses its own translations, as the ones running at the same time may have different locales.
+	catalog := i18n.LoadCatalog(i18n.TextDomain, i18n.WithLocale(adapter.PamLocale(mTx)))
+
 	appState := adapter.UIModel{
-		PamMTx:      mTx,
-		Conn:        conn,
-		ClientType:  pamClientType,
-		SessionMode: mode,
+		PamMTx:       mTx,
+		Conn:         conn,
+		ClientType:   pamClientType,
+		SessionMode:  mode,
+		PlainPrompts: plainPrompts,
+		Silent:       isSilent,
+
+		DeviceCodeHelpers: parsedArgs["device_code_helpers"] == "true",
+		BrowserLauncher:   browserLauncher(parsedArgs),
+		Catalog:           catalog,
+	}
+	if pamClientType == adapter.Gdm {
+		appState.GdmDrainTimeout = gdmDrainTimeout(parsedArgs)
+	}
+	if defaultBroker, ok := parsedArgs["default_broker"]; ok {
+		appState.DefaultBroker = &defaultBroker
 	}
+	appState.FirstPass = firstPassPolicy(parsedArgs)
+	appState.AllowedAuthModes = allowedAuthModes(parsedArgs)
+	appState.SkipBrokerSelection = parsedArgs["skip_broker_selection"] == "true"
+	appState.SelectBroker = parsedArgs["select_broker"] == "true"
 
 	if err := mTx.SetData(authenticationBrokerIDKey, nil); err != nil {
 		return err
@@ -331,7 +471,7 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 		return pam.ErrAbort
 	}
 
-	sendReturnMessageToPam(mTx, appState.ExitStatus())
+	sendReturnMessageToPam(mTx, appState.ExitStatus(), isSilent)
 
 	switch exitStatus := appState.ExitStatus().(type) {
 	case adapter.PamSuccess:
@@ -348,7 +488,90 @@ func (h *pamModule) handleAuthRequest(mode authd.SessionMode, mTx pam.ModuleTran
 	}
 }
 
-// AcctMgmt sets any used brokerID as default for the user.
+// isRecentlyAuthenticated returns whether the daemon policy allows the user to be authenticated to the service
+// without being prompted, because of a recent strong authentication on the same terminal.
+func isRecentlyAuthenticated(mTx pam.ModuleTransaction, client authd.PAMClient, serviceName string) bool {
+	if serviceName == "" {
+		return false
+	}
+
+	username, err := mTx.GetItem(pam.User)
+	if err != nil || username == "" {
+		return false
+	}
+
+	// The daemon only relies on authentications made on the same terminal.
+	tty, _ := mTx.GetItem(pam.Tty)
+
+	response, err := client.IsRecentlyAuthenticated(context.TODO(), &authd.IRARequest{
+		Username: username,
+		Service:  serviceName,
+		Tty:      tty,
+	})
+	if err != nil {
+		log.Warningf(context.TODO(), "Could not check recent authentication of user %q: %v", username, err)
+		return false
+	}
+
+	if response.GetGranted() {
+		log.Infof(context.TODO(), "User %q recently authenticated, not prompting again for service %q", username, serviceName)
+	}
+	return response.GetGranted()
+}
+
+// authenticateWithLocalPIN prompts the user for their local PIN, if the daemon policy allows to use it for the
+// service. An empty PIN falls back to the authentication with the broker, while an invalid one fails.
+func authenticateWithLocalPIN(mTx pam.ModuleTransaction, client authd.PAMClient, serviceName string) (bool, error) {
+	if serviceName == "" {
+		return false, nil
+	}
+
+	username, err := mTx.GetItem(pam.User)
+	if err != nil || username == "" {
+		return false, nil
+	}
+
+	status, err := client.GetLocalPINStatus(context.TODO(), &authd.GLPSRequest{
+		Username: username,
+		Service:  serviceName,
+	})
+	if err != nil {
+		log.Warningf(context.TODO(), "Could not get local PIN status of user %q: %v", username, err)
+		return false, nil
+	}
+	if !status.GetUsable() {
+		return false, nil
+	}
+
+	resp, err := mTx.StartStringConv(pam.PromptEchoOff, "PIN (leave empty to use your identity provider): ")
+	if err != nil {
+		return false, fmt.Errorf("%w: can't prompt for local PIN: %w", pam.ErrConv, err)
+	}
+	if resp.Response() == "" {
+		return false, nil
+	}
+
+	result, err := client.AuthenticateWithLocalPIN(context.TODO(), &authd.ALPRequest{
+		Username: username,
+		Service:  serviceName,
+		Pin:      resp.Response(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", pam.ErrAuthinfoUnavail, err)
+	}
+	if !result.GetGranted() {
+		if err := showPamMessage(mTx, pam.ErrorMsg, result.GetMsg()); err != nil {
+			log.Warningf(context.TODO(), "Impossible to show PAM message: %v", err)
+		}
+		return false, fmt.Errorf("%w: %s", pam.ErrAuth, result.GetMsg())
+	}
+
+	log.Infof(context.TODO(), "User %q authenticated with their local PIN to service %q", username, serviceName)
+	return true, nil
+}
+
+// AcctMgmt refuses the users handled by authd whom the login policy of the daemon doesn't allow to log in, and sets
+// any used brokerID as default for the user.
 func (h *pamModule) AcctMgmt(mTx pam.ModuleTransaction, flags pam.Flags, args []string) (err error) {
 	parsedArgs, logArgsIssues := parseArgs(args)
 	closeLogging, err := initLogging(mTx, parsedArgs, flags)
@@ -360,6 +583,7 @@ func (h *pamModule) AcctMgmt(mTx pam.ModuleTransaction, flags pam.Flags, args []
 		return err
 	}
 	logArgsIssues()
+	applyProfile(mTx, parsedArgs)
 
 	// We ignore AcctMgmt in case we're loading the module through the exec client
 	serviceName, err := mTx.GetItem(pam.Service)
@@ -371,6 +595,10 @@ func (h *pamModule) AcctMgmt(mTx pam.ModuleTransaction, flags pam.Flags, args []
 		return pam.ErrIgnore
 	}
 
+	if err := checkLoginPolicy(mTx, parsedArgs); err != nil {
+		return err
+	}
+
 	brokerData, err := mTx.GetData(authenticationBrokerIDKey)
 	if err != nil && errors.Is(err, pam.ErrNoModuleData) {
 		return pam.ErrIgnore
@@ -433,10 +661,50 @@ func (h *pamModule) AcctMgmt(mTx pam.ModuleTransaction, flags pam.Flags, args []
 	return nil
 }
 
+// checkLoginPolicy returns pam.ErrPermDenied if the user is handled by authd and the login policy of the daemon
+// doesn't allow them to log in on this machine. The users who are not handled by authd are left to the other modules.
+func checkLoginPolicy(mTx pam.ModuleTransaction, parsedArgs map[string]string) error {
+	user, err := mTx.GetItem(pam.User)
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return nil
+	}
+
+	client, closeConn, err := newClient(parsedArgs)
+	if err != nil {
+		// Don't let the users in if the policy can't be checked.
+		log.Warningf(context.TODO(), "Could not connect to authd to check the login policy for %q: %v", user, err)
+		return pam.ErrAuthinfoUnavail
+	}
+	defer closeConn()
+
+	_, err = client.CheckLoginPolicy(context.TODO(), &authd.CLPRequest{Username: user})
+	if authderrors.Is(err, authderrors.NotFound) {
+		return nil
+	}
+	if authderrors.Is(err, authderrors.PermissionDenied) {
+		msg := fmt.Sprintf("User %q is not allowed to log in on this machine", user)
+		if errors.Is(err, authderrors.ErrMissingRole) {
+			msg = fmt.Sprintf("User %q is not allowed to log in on this machine: none of their roles gives access to it", user)
+		}
+		if err := showPamMessage(mTx, pam.ErrorMsg, msg); err != nil {
+			log.Warningf(context.TODO(), "Impossible to show PAM message: %v", err)
+		}
+		return pam.ErrPermDenied
+	}
+	if err != nil {
+		log.Warningf(context.TODO(), "Could not check the login policy for %q: %v", user, err)
+		return pam.ErrAuthinfoUnavail
+	}
+	return nil
+}
+
 func newClientConnection(args map[string]string) (conn *grpc.ClientConn, closeConn func(), err error) {
 	conn, err = grpc.NewClient("unix://"+getSocketPath(args),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(errmessages.FormatErrorMessage))
+		grpc.WithChainUnaryInterceptor(errmessages.FormatErrorMessage, compat.UnaryClientInterceptor))
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not connect to authd: %v", err)
 	}
@@ -484,13 +752,3 @@ func getSocketPath(args map[string]string) string {
 func (h *pamModule) SetCred(pam.ModuleTransaction, pam.Flags, []string) error {
 	return pam.ErrIgnore
 }
-
-// OpenSession is the method that is invoked during pam_open_session request.
-func (h *pamModule) OpenSession(pam.ModuleTransaction, pam.Flags, []string) error {
-	return pam.ErrIgnore
-}
-
-// CloseSession is the method that is invoked during pam_close_session request.
-func (h *pamModule) CloseSession(pam.ModuleTransaction, pam.Flags, []string) error {
-	return pam.ErrIgnore
-}
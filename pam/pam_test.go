@@ -1,10 +1,15 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/msteinert/pam/v2"
 	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/pam/internal/adapter"
+	"github.com/ubuntu/authd/pam/internal/pam_test"
+	"github.com/ubuntu/authd/pam/internal/utmp"
 )
 
 func TestUnimplementedActions(t *testing.T) {
@@ -13,6 +18,215 @@ func TestUnimplementedActions(t *testing.T) {
 	// If these gets changed, go-exec module should be also adapted accordingly
 	// together with TestExecModuleUnimplementedActions
 	require.Error(t, module.SetCred(nil, pam.Flags(0), nil), pam.ErrIgnore)
-	require.Error(t, module.OpenSession(nil, pam.Flags(0), nil), pam.ErrIgnore)
-	require.Error(t, module.CloseSession(nil, pam.Flags(0), nil), pam.ErrIgnore)
 }
+
+func TestSessionActionsWithoutTransaction(t *testing.T) {
+	module := &pamModule{}
+
+	require.ErrorIs(t, module.OpenSession(nil, pam.Flags(0), nil), pam.ErrIgnore)
+	require.ErrorIs(t, module.CloseSession(nil, pam.Flags(0), nil), pam.ErrIgnore)
+}
+
+func TestOpenAndCloseSession(t *testing.T) {
+	// These tests can't be parallel as they change the global utmp options.
+	tests := map[string]struct {
+		brokerID string
+		tty      string
+		args     []string
+
+		wantOpenErr  error
+		wantCloseErr error
+		wantRecorded bool
+	}{
+		"Records_the_session_of_an_user_authenticated_by_authd": {
+			brokerID:     "broker-id",
+			tty:          "/dev/pts/3",
+			wantRecorded: true,
+		},
+		"Ignores_the_session_of_an_user_not_authenticated_by_authd": {
+			tty:          "/dev/pts/3",
+			wantOpenErr:  pam.ErrIgnore,
+			wantCloseErr: pam.ErrIgnore,
+		},
+		"Ignores_the_session_without_a_terminal": {
+			brokerID:     "broker-id",
+			wantOpenErr:  pam.ErrIgnore,
+			wantCloseErr: pam.ErrIgnore,
+		},
+		"Ignores_the_session_if_recording_is_disabled": {
+			brokerID:     "broker-id",
+			tty:          "/dev/pts/3",
+			args:         []string{"utmp=false"},
+			wantOpenErr:  pam.ErrIgnore,
+			wantCloseErr: pam.ErrIgnore,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			utmpFile := filepath.Join(tempDir, "utmp")
+			wtmpFile := filepath.Join(tempDir, "wtmp")
+			for _, f := range []string{utmpFile, wtmpFile} {
+				require.NoError(t, os.WriteFile(f, nil, 0600), "Setup: Creating %q failed", f)
+			}
+			utmpOptions = []utmp.Option{utmp.WithUtmpFile(utmpFile), utmp.WithWtmpFile(wtmpFile)}
+			t.Cleanup(func() { utmpOptions = nil })
+
+			mTx := pam_test.NewModuleTransactionDummy(nil)
+			require.NoError(t, mTx.SetItem(pam.User, "authd-test-user"), "Setup: Setting the user failed")
+			if tc.tty != "" {
+				require.NoError(t, mTx.SetItem(pam.Tty, tc.tty), "Setup: Setting the TTY failed")
+			}
+			if tc.brokerID != "" {
+				require.NoError(t, mTx.SetData(authenticationBrokerIDKey, tc.brokerID),
+					"Setup: Setting the broker ID failed")
+			}
+
+			// The daemon is not running, so its session actions are ignored.
+			args := append([]string{
+				"socket=" + filepath.Join(tempDir, "authd.sock"),
+				"connection_timeout=1",
+				"disable_journal=true",
+			}, tc.args...)
+
+			module := &pamModule{}
+			err := module.OpenSession(mTx, pam.Flags(0), args)
+			if tc.wantOpenErr != nil {
+				require.ErrorIs(t, err, tc.wantOpenErr, "OpenSession should have failed")
+			} else {
+				require.NoError(t, err, "OpenSession should not fail")
+			}
+
+			line, err := mTx.GetData(sessionLineKey)
+			if !tc.wantRecorded {
+				require.ErrorIs(t, err, pam.ErrNoModuleData, "No session line should be saved")
+				requireSessionRecorded(t, utmpFile, false)
+				requireSessionRecorded(t, wtmpFile, false)
+			} else {
+				require.NoError(t, err, "The session line should be saved")
+				require.Equal(t, "pts/3", line, "The session line should be saved without the /dev/ prefix")
+				requireSessionRecorded(t, utmpFile, true)
+				requireSessionRecorded(t, wtmpFile, true)
+			}
+
+			err = module.CloseSession(mTx, pam.Flags(0), args)
+			if tc.wantCloseErr != nil {
+				require.ErrorIs(t, err, tc.wantCloseErr, "CloseSession should have failed")
+				return
+			}
+			require.NoError(t, err, "CloseSession should not fail")
+
+			line, err = mTx.GetData(sessionLineKey)
+			require.NoError(t, err, "The session line should be still available")
+			require.Nil(t, line, "The session line should be reset after closing the session")
+
+			// Closing again the session is a no-op.
+			require.ErrorIs(t, module.CloseSession(mTx, pam.Flags(0), args), pam.ErrIgnore)
+		})
+	}
+}
+
+func requireSessionRecorded(t *testing.T, path string, wantRecords bool) {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err, "File %q should exist", path)
+	if !wantRecords {
+		require.Zero(t, info.Size(), "No session should be recorded in %q", path)
+		return
+	}
+	require.NotZero(t, info.Size(), "The session should be recorded in %q", path)
+}
+
+func TestBrowserLauncher(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args map[string]string
+
+		want string
+	}{
+		"Default_launcher":                       {want: adapter.DefaultBrowserLauncher},
+		"Default_launcher_if_explicitly_enabled": {args: map[string]string{"open_browser": "true"}, want: adapter.DefaultBrowserLauncher},
+		"Custom_launcher":                        {args: map[string]string{"browser_launcher": "/usr/bin/sensible-browser"}, want: "/usr/bin/sensible-browser"},
+		"No_launcher_if_disabled":                {args: map[string]string{"open_browser": "false"}},
+		"No_launcher_if_disabled_even_if_custom": {args: map[string]string{"open_browser": "false", "browser_launcher": "/usr/bin/sensible-browser"}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, browserLauncher(tc.args))
+		})
+	}
+}
+
+func TestFirstPassPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args map[string]string
+
+		want adapter.FirstPassPolicy
+	}{
+		"Ignored_by_default":                     {want: adapter.IgnoreFirstPass},
+		"Used_if_set_without_value":              {args: map[string]string{"use_first_pass": ""}, want: adapter.UseFirstPass},
+		"Used_if_enabled":                        {args: map[string]string{"use_first_pass": "true"}, want: adapter.UseFirstPass},
+		"Tried_if_set_without_value":             {args: map[string]string{"try_first_pass": ""}, want: adapter.TryFirstPass},
+		"Tried_if_enabled":                       {args: map[string]string{"try_first_pass": "true"}, want: adapter.TryFirstPass},
+		"Used_if_both_are_set":                   {args: map[string]string{"try_first_pass": "", "use_first_pass": ""}, want: adapter.UseFirstPass},
+		"Tried_if_use_first_pass_is_disabled":    {args: map[string]string{"try_first_pass": "", "use_first_pass": "false"}, want: adapter.TryFirstPass},
+		"Ignored_if_disabled":                    {args: map[string]string{"try_first_pass": "false", "use_first_pass": "false"}, want: adapter.IgnoreFirstPass},
+		"Ignored_if_set_to_an_unsupported_value": {args: map[string]string{"use_first_pass": "yes"}, want: adapter.IgnoreFirstPass},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, firstPassPolicy(tc.args))
+		})
+	}
+}
+
+func TestSendReturnMessageToPam(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		status pam.Error
+		msg    string
+		silent bool
+
+		wantStyles []pam.Style
+	}{
+		"Error_message_is_sent":                       {status: pam.ErrAuth, msg: "Access denied", wantStyles: []pam.Style{pam.ErrorMsg}},
+		"Error_message_is_sent_if_silent":             {status: pam.ErrAuth, msg: "Access denied", silent: true, wantStyles: []pam.Style{pam.ErrorMsg}},
+		"Ignored_error_message_is_sent_as_info":       {status: pam.ErrIgnore, msg: "Skipped", wantStyles: []pam.Style{pam.TextInfo}},
+		"Ignored_error_message_is_not_sent_if_silent": {status: pam.ErrIgnore, msg: "Skipped", silent: true},
+		"No_message_is_sent_without_message":          {status: pam.ErrAuth},
+		"No_message_is_sent_on_conversation_error":    {status: pam.ErrConv, msg: "Conversation failed"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var styles []pam.Style
+			mTx := pam_test.NewModuleTransactionDummy(pam.ConversationFunc(
+				func(style pam.Style, _ string) (string, error) {
+					styles = append(styles, style)
+					return "", nil
+				}))
+
+			sendReturnMessageToPam(mTx, returnError{status: tc.status, msg: tc.msg}, tc.silent)
+			require.Equal(t, tc.wantStyles, styles, "Unexpected messages sent to PAM")
+		})
+	}
+}
+
+// returnError is an [adapter.PamReturnError] with the given status and message.
+type returnError struct {
+	status pam.Error
+	msg    string
+}
+
+func (r returnError) Status() pam.Error { return r.status }
+func (r returnError) Message() string   { return r.msg }
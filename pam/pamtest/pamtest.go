@@ -0,0 +1,145 @@
+// Package pamtest provides helpers to run end-to-end tests of the authd PAM module, so that they can be shared with
+// the brokers testing the module against their own implementation.
+package pamtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ubuntu/authd/internal/grpcutils"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/internal/services/errmessages"
+	"github.com/ubuntu/authd/internal/testutils"
+	"github.com/ubuntu/authd/internal/testutils/golden"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ArtifactsPathEnv is the environment variable setting the directory where the artifacts of the failed tests are
+// saved. A new temporary directory is used if unset.
+const ArtifactsPathEnv = "AUTHD_TEST_ARTIFACTS_PATH"
+
+var (
+	testSessionTime  = time.Now()
+	artifactsDir     string
+	artifactsDirOnce sync.Once
+)
+
+// PrepareFileLogging returns the path of a log file with the given name, whose content is printed in the test
+// output and saved as an artifact once the test is done.
+func PrepareFileLogging(t *testing.T, fileName string) string {
+	t.Helper()
+
+	logFile := filepath.Join(t.TempDir(), fileName)
+	SaveArtifactsForDebugOnCleanup(t, []string{logFile})
+	t.Cleanup(func() {
+		out, err := os.ReadFile(logFile)
+		if errors.Is(err, fs.ErrNotExist) {
+			return
+		}
+		require.NoError(t, err, "Teardown: Impossible to read PAM client logs")
+		t.Log(string(out))
+	})
+
+	return logFile
+}
+
+// RequirePreviousBrokerForUser checks that the broker with the given name is the one the daemon listening on
+// socketPath has recorded as the previous broker of the user. An empty brokerName means no previous broker.
+func RequirePreviousBrokerForUser(t *testing.T, socketPath string, brokerName string, user string) {
+	t.Helper()
+
+	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(errmessages.FormatErrorMessage))
+	require.NoError(t, err, "Can't connect to authd socket")
+
+	t.Cleanup(func() { conn.Close() })
+	require.NoError(t, grpcutils.WaitForConnection(context.TODO(), conn,
+		SleepDuration(30*time.Second)))
+	pamClient := authd.NewPAMClient(conn)
+	brokers, err := pamClient.AvailableBrokers(context.TODO(), nil)
+	require.NoError(t, err, "Can't get available brokers")
+	prevBroker, err := pamClient.GetPreviousBroker(context.TODO(), &authd.GPBRequest{Username: user})
+	require.NoError(t, err, "Can't get previous broker")
+	var prevBrokerID string
+	for _, b := range brokers.BrokersInfos {
+		if b.Name == brokerName {
+			prevBrokerID = b.Id
+		}
+	}
+	require.Equal(t, prevBroker.PreviousBroker, prevBrokerID)
+}
+
+// ArtifactsPath returns the directory where the artifacts of the failed tests are saved, creating it if needed.
+func ArtifactsPath(t *testing.T) string {
+	t.Helper()
+
+	artifactsDirOnce.Do(func() {
+		defer func() { t.Logf("Saving test artifacts at %s", artifactsDir) }()
+
+		// We need to copy the artifacts to another directory, since the test directory will be cleaned up.
+		artifactsDir = os.Getenv(ArtifactsPathEnv)
+		if artifactsDir != "" {
+			if err := os.MkdirAll(artifactsDir, 0750); err != nil && !os.IsExist(err) {
+				require.NoError(t, err, "TearDown: could not create artifacts directory %q", artifactsDir)
+			}
+			return
+		}
+
+		st := testSessionTime
+		folderName := fmt.Sprintf("authd-test-artifacts-%d-%02d-%02dT%02d:%02d:%02d.%d-",
+			st.Year(), st.Month(), st.Day(), st.Hour(), st.Minute(), st.Second(),
+			st.UnixMilli())
+
+		var err error
+		artifactsDir, err = os.MkdirTemp(os.TempDir(), folderName)
+		require.NoError(t, err, "TearDown: could not create artifacts directory %q", artifactsDir)
+	})
+
+	return artifactsDir
+}
+
+// SaveArtifactsForDebug saves the specified artifacts to the artifacts directory if the test failed.
+func SaveArtifactsForDebug(t *testing.T, artifacts []string) {
+	t.Helper()
+	if !t.Failed() {
+		return
+	}
+
+	tmpDir := filepath.Join(ArtifactsPath(t), golden.Path(t))
+	err := os.MkdirAll(tmpDir, 0750)
+	require.NoError(t, err, "TearDown: could not create temporary directory %q for artifacts", tmpDir)
+
+	// Copy the artifacts to the temporary directory.
+	for _, artifact := range artifacts {
+		content, err := os.ReadFile(artifact)
+		if err != nil {
+			t.Logf("Could not read artifact %q: %v", artifact, err)
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, filepath.Base(artifact)), content, 0600); err != nil {
+			t.Logf("Could not write artifact %q: %v", artifact, err)
+		}
+	}
+}
+
+// SaveArtifactsForDebugOnCleanup saves the specified artifacts to the artifacts directory once the test is done, if
+// it failed.
+func SaveArtifactsForDebugOnCleanup(t *testing.T, artifacts []string) {
+	t.Helper()
+	t.Cleanup(func() { SaveArtifactsForDebug(t, artifacts) })
+}
+
+// SleepDuration returns the duration scaled by the sleep multiplier of the tests, to give more time to slow
+// environments.
+func SleepDuration(in time.Duration) time.Duration {
+	return time.Duration(math.Round(float64(in) * testutils.SleepMultiplier()))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/msteinert/pam/v2"
+	"github.com/ubuntu/authd/internal/authderrors"
+	"github.com/ubuntu/authd/internal/proto/authd"
+	"github.com/ubuntu/authd/log"
+	"github.com/ubuntu/authd/pam/internal/usersession"
+	"github.com/ubuntu/authd/pam/internal/utmp"
+)
+
+// sessionLineKey is the Key used to store in the library the line of the session
+// we recorded in utmp, so that we can record its end.
+const sessionLineKey = "authd.session-line"
+
+// applicationPID returns the PID of the process handling the PAM sessions.
+var applicationPID = os.Getpid
+
+// utmpOptions are the options used when recording the sessions in utmp and wtmp.
+var utmpOptions []utmp.Option
+
+// OpenSession is the method that is invoked during pam_open_session request.
+// It performs the session actions of the daemon policy for the users handled by authd, and records the sessions of
+// the users authenticated by authd in utmp and wtmp, so that they are listed by who and last. The latter can be
+// disabled with utmp=false when the application or another module already does it.
+func (h *pamModule) OpenSession(mTx pam.ModuleTransaction, flags pam.Flags, args []string) (err error) {
+	if mTx == nil {
+		// There's no session to handle without a transaction.
+		return pam.ErrIgnore
+	}
+
+	parsedArgs, logArgsIssues := parseArgs(args)
+	closeLogging, err := initLogging(mTx, parsedArgs, flags)
+	defer closeLogging()
+	defer func() {
+		log.Debugf(context.TODO(), "OpenSession: exiting with error %v", err)
+	}()
+	if err != nil {
+		return err
+	}
+	logArgsIssues()
+
+	setupErr := setupSession(mTx, parsedArgs)
+	recordErr := recordSession(mTx, parsedArgs)
+	if errors.Is(setupErr, pam.ErrIgnore) && errors.Is(recordErr, pam.ErrIgnore) {
+		return pam.ErrIgnore
+	}
+	for _, err := range []error{setupErr, recordErr} {
+		if err != nil && !errors.Is(err, pam.ErrIgnore) {
+			return err
+		}
+	}
+	return nil
+}
+
+// setupSession performs the actions that the daemon requires when opening a session of the user.
+func setupSession(mTx pam.ModuleTransaction, parsedArgs map[string]string) error {
+	user, err := mTx.GetItem(pam.User)
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return pam.ErrIgnore
+	}
+
+	client, closeConn, err := newClient(parsedArgs)
+	if err != nil {
+		log.Warningf(context.TODO(), "Could not connect to authd to set up the session of %q: %v", user, err)
+		return pam.ErrIgnore
+	}
+	defer closeConn()
+
+	actions, err := client.GetSessionActions(context.TODO(), &authd.GSARequest{Username: user})
+	if authderrors.Is(err, authderrors.NotFound) {
+		return pam.ErrIgnore
+	}
+	if err != nil {
+		log.Warningf(context.TODO(), "Could not get the session actions of %q: %v", user, err)
+		return fmt.Errorf("%w: %w", pam.ErrSession, err)
+	}
+	if !actions.GetEnsureRuntimeDir() && !actions.GetEnableLinger() {
+		return pam.ErrIgnore
+	}
+
+	if actions.GetEnsureRuntimeDir() {
+		dir, err := usersession.EnsureRuntimeDir(actions.GetUid(), actions.GetGid())
+		if err != nil {
+			log.Warningf(context.TODO(), "%v", err)
+			return fmt.Errorf("%w: %w", pam.ErrSession, err)
+		}
+		if mTx.GetEnv("XDG_RUNTIME_DIR") == "" {
+			if err := mTx.PutEnv("XDG_RUNTIME_DIR=" + dir); err != nil {
+				return err
+			}
+		}
+	}
+
+	if actions.GetEnableLinger() {
+		log.Debugf(context.TODO(), "Enabling lingering of %q", user)
+		if err := usersession.EnableLinger(actions.GetUid()); err != nil {
+			log.Warningf(context.TODO(), "%v", err)
+			return fmt.Errorf("%w: %w", pam.ErrSession, err)
+		}
+	}
+
+	return nil
+}
+
+// recordSession records the session of the user authenticated by authd in utmp and wtmp.
+func recordSession(mTx pam.ModuleTransaction, parsedArgs map[string]string) error {
+	if parsedArgs["utmp"] == "false" {
+		return pam.ErrIgnore
+	}
+
+	// Only record the sessions of the users we authenticated, the local ones are handled by the other modules.
+	brokerID, err := mTx.GetData(authenticationBrokerIDKey)
+	if err != nil && !errors.Is(err, pam.ErrNoModuleData) {
+		return err
+	}
+	if id, ok := brokerID.(string); !ok || id == "" {
+		return pam.ErrIgnore
+	}
+
+	user, err := mTx.GetItem(pam.User)
+	if err != nil {
+		return err
+	}
+	tty, err := mTx.GetItem(pam.Tty)
+	if err != nil {
+		return err
+	}
+	line := strings.TrimPrefix(tty, "/dev/")
+	if line == "" {
+		log.Debugf(context.TODO(), "No terminal for the session of %q, not recording it", user)
+		return pam.ErrIgnore
+	}
+	host, err := mTx.GetItem(pam.Rhost)
+	if err != nil {
+		return err
+	}
+
+	if err := utmp.Login(utmp.Entry{User: user, Line: line, Host: host, PID: applicationPID()}, utmpOptions...); err != nil {
+		log.Warningf(context.TODO(), "%v", err)
+		return fmt.Errorf("%w: %w", pam.ErrSession, err)
+	}
+
+	return mTx.SetData(sessionLineKey, line)
+}
+
+// CloseSession is the method that is invoked during pam_close_session request.
+// It records the end of the session recorded by OpenSession, if any.
+func (h *pamModule) CloseSession(mTx pam.ModuleTransaction, flags pam.Flags, args []string) (err error) {
+	if mTx == nil {
+		// There's no session to handle without a transaction.
+		return pam.ErrIgnore
+	}
+
+	parsedArgs, logArgsIssues := parseArgs(args)
+	closeLogging, err := initLogging(mTx, parsedArgs, flags)
+	defer closeLogging()
+	defer func() {
+		log.Debugf(context.TODO(), "CloseSession: exiting with error %v", err)
+	}()
+	if err != nil {
+		return err
+	}
+	logArgsIssues()
+
+	data, err := mTx.GetData(sessionLineKey)
+	if err != nil && !errors.Is(err, pam.ErrNoModuleData) {
+		return err
+	}
+	line, ok := data.(string)
+	if !ok || line == "" {
+		return pam.ErrIgnore
+	}
+
+	if err := utmp.Logout(line, applicationPID(), utmpOptions...); err != nil {
+		log.Warningf(context.TODO(), "%v", err)
+		return fmt.Errorf("%w: %w", pam.ErrSession, err)
+	}
+
+	return mTx.SetData(sessionLineKey, nil)
+}
@@ -0,0 +1,30 @@
+#!/usr/bin/env bash
+
+set -euo pipefail
+
+if [ -v DEB_HOST_GNU_TYPE ]; then
+    echo "Translation templates should not be regenerated during package building"
+    exit 0
+fi
+
+root_dir="$(realpath "$(dirname "${BASH_SOURCE[0]}")/..")"
+po_dir="${root_dir}/po"
+
+# Go strings are close enough to the C ones for xgettext to extract them,
+# as long as the translatable strings are not built by concatenation.
+xgettext \
+    --language=C \
+    --from-code=UTF-8 \
+    --keyword= \
+    --keyword=G \
+    --keyword=NG:1,2 \
+    --add-comments=TRANSLATORS: \
+    --package-name=authd \
+    --msgid-bugs-address=https://github.com/ubuntu/authd/issues \
+    --directory="${root_dir}" \
+    --files-from="${po_dir}/POTFILES.in" \
+    --output="${po_dir}/authd.pot"
+
+for lang in $(grep -v '^#' "${po_dir}/LINGUAS"); do
+    msgmerge --update --backup=none --previous "${po_dir}/${lang}.po" "${po_dir}/authd.pot"
+done